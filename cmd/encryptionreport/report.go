@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+)
+
+// ComplianceReport combines everything the apiserver's encryption debug
+// endpoints expose into one auditable document: the configured keys and their
+// provenance, the live provider state and the sampled storage coverage.
+type ComplianceReport struct {
+	// GeneratedAt is when the report was assembled.
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Server is the apiserver the report was collected from.
+	Server string `json:"server"`
+	// State is the configured encryption state, including key provenance and
+	// the last hot reload.
+	State *encryptionconfig.EncryptionState `json:"state,omitempty"`
+	// Providers is the live per-provider state, including key IDs.
+	Providers *encryptionconfig.EncryptionProvidersDebugInfo `json:"providers,omitempty"`
+	// Coverage is the most recent sampled storage coverage, including
+	// plaintext counts.
+	Coverage *encryptionconfig.EncryptionCoverageReport `json:"coverage,omitempty"`
+}
+
+// ReportSignature authenticates a serialized report.
+type ReportSignature struct {
+	// Algorithm identifies how Value was computed. Currently always
+	// HMAC-SHA256.
+	Algorithm string `json:"algorithm"`
+	// Value is the hex encoded signature over the serialized report.
+	Value string `json:"value"`
+}
+
+// SignedComplianceReport is the JSON output format: the report together with
+// a signature over its serialized form.
+type SignedComplianceReport struct {
+	Report *ComplianceReport `json:"report"`
+	// Signature is only present when a signing key was provided.
+	Signature *ReportSignature `json:"signature,omitempty"`
+}
+
+// signReport computes an HMAC-SHA256 signature over the report's canonical
+// JSON serialization.
+func signReport(report *ComplianceReport, key []byte) (*ReportSignature, error) {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize report for signing: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return &ReportSignature{
+		Algorithm: "HMAC-SHA256",
+		Value:     hex.EncodeToString(mac.Sum(nil)),
+	}, nil
+}
+
+// writeJSON writes the report, and its signature when key is non-empty, as
+// indented JSON.
+func writeJSON(w io.Writer, report *ComplianceReport, key []byte) error {
+	signed := &SignedComplianceReport{Report: report}
+	if len(key) > 0 {
+		signature, err := signReport(report, key)
+		if err != nil {
+			return err
+		}
+		signed.Signature = signature
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(signed)
+}
+
+// writeCSV writes one row per fact in the report. The row type is the first
+// column so auditors can filter keys, providers and coverage independently.
+// When key is non-empty a signature over the rows is appended as a comment.
+func writeCSV(w io.Writer, report *ComplianceReport, key []byte) error {
+	var rows [][]string
+	rows = append(rows, []string{"type", "name", "resource", "health", "keyID", "createdAt", "count", "fraction"})
+
+	if report.State != nil {
+		for _, keyState := range report.State.Keys {
+			rows = append(rows, []string{"key", keyState.Prefix, "", "", "", keyState.CreatedAt, "", ""})
+		}
+	}
+	if report.Providers != nil {
+		for _, provider := range report.Providers.Providers {
+			lastProbe := ""
+			if provider.LastProbeTime != nil {
+				lastProbe = provider.LastProbeTime.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{"provider", provider.Name, "", provider.Health, provider.KeyID, lastProbe, "", ""})
+		}
+	}
+	if report.Coverage != nil {
+		for _, resource := range report.Coverage.Resources {
+			for _, provider := range resource.Providers {
+				rows = append(rows, []string{"coverage", provider.Prefix, resource.Resource, "", "", "",
+					strconv.Itoa(provider.Count), strconv.FormatFloat(provider.Fraction, 'f', -1, 64)})
+			}
+			rows = append(rows, []string{"plaintext", "", resource.Resource, "", "", "",
+				strconv.Itoa(resource.Plaintext), ""})
+		}
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.WriteAll(rows); err != nil {
+		return err
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return err
+	}
+
+	if len(key) > 0 {
+		payload := make([]byte, 0, 1024)
+		for _, row := range rows {
+			for _, field := range row {
+				payload = append(payload, field...)
+				payload = append(payload, ',')
+			}
+			payload = append(payload, '\n')
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(payload)
+		if _, err := fmt.Fprintf(w, "# signature: HMAC-SHA256 %s\n", hex.EncodeToString(mac.Sum(nil))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+)
+
+func testReport() *ComplianceReport {
+	return &ComplianceReport{
+		GeneratedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Server:      "https://apiserver:6443",
+		State: &encryptionconfig.EncryptionState{
+			FileContentHash: "sha256:abcd",
+			Keys: []encryptionconfig.KeyState{
+				{Prefix: "k8s:enc:kms:v2:provider-1:", CreatedAt: "2022-12-01"},
+			},
+		},
+		Providers: &encryptionconfig.EncryptionProvidersDebugInfo{
+			Providers: []encryptionconfig.ProviderDebugInfo{
+				{Name: "kms:v2:provider-1", Health: "ok", KeyID: "key-1"},
+			},
+		},
+		Coverage: &encryptionconfig.EncryptionCoverageReport{
+			Resources: []encryptionconfig.ResourceCoverage{
+				{
+					Resource: "secrets",
+					Sampled:  4,
+					Providers: []encryptionconfig.ProviderCoverage{
+						{Prefix: "k8s:enc:kms:v2:provider-1:", Count: 3, Fraction: 0.75},
+					},
+					Plaintext: 1,
+				},
+			},
+		},
+	}
+}
+
+func TestWriteJSONSigned(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, testReport(), []byte("signing-key")); err != nil {
+		t.Fatal(err)
+	}
+
+	var signed SignedComplianceReport
+	if err := json.Unmarshal(buf.Bytes(), &signed); err != nil {
+		t.Fatal(err)
+	}
+	if signed.Signature == nil || signed.Signature.Algorithm != "HMAC-SHA256" {
+		t.Fatalf("expected an HMAC-SHA256 signature, got %+v", signed.Signature)
+	}
+
+	// the signature must verify against the serialized report
+	want, err := signReport(signed.Report, []byte("signing-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.Value != signed.Signature.Value {
+		t.Errorf("signature does not verify: want %s, got %s", want.Value, signed.Signature.Value)
+	}
+
+	// and fail verification under a different key
+	other, err := signReport(signed.Report, []byte("other-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Value == signed.Signature.Value {
+		t.Error("expected the signature to depend on the key")
+	}
+}
+
+func TestWriteJSONUnsigned(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, testReport(), nil); err != nil {
+		t.Fatal(err)
+	}
+	var signed SignedComplianceReport
+	if err := json.Unmarshal(buf.Bytes(), &signed); err != nil {
+		t.Fatal(err)
+	}
+	if signed.Signature != nil {
+		t.Errorf("expected no signature without a key, got %+v", signed.Signature)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, testReport(), []byte("signing-key")); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	want := []string{
+		"type,name,resource,health,keyID,createdAt,count,fraction",
+		"key,k8s:enc:kms:v2:provider-1:,,,,2022-12-01,,",
+		"provider,kms:v2:provider-1,,ok,key-1,,,",
+		"coverage,k8s:enc:kms:v2:provider-1:,secrets,,,,3,0.75",
+		"plaintext,,secrets,,,,1,",
+	}
+	if len(lines) != len(want)+1 {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("line %d: want %q, got %q", i, line, lines[i])
+		}
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "# signature: HMAC-SHA256 ") {
+		t.Errorf("expected a signature trailer, got %q", lines[len(lines)-1])
+	}
+}
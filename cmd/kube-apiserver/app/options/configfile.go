@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	kubeapiserverconfig "k8s.io/kubernetes/pkg/kubeapiserver/apis/config"
+	"k8s.io/kubernetes/pkg/kubeapiserver/apis/config/scheme"
+	kubeapiserverconfigv1alpha1 "k8s.io/kubernetes/pkg/kubeapiserver/apis/config/v1alpha1"
+)
+
+// FromServerRunOptions builds a KubeAPIServerConfiguration reflecting the
+// effective values of the given options. Flags that have no configuration
+// counterpart yet are omitted from the result.
+func FromServerRunOptions(s *ServerRunOptions) *kubeapiserverconfig.KubeAPIServerConfiguration {
+	cfg := &kubeapiserverconfig.KubeAPIServerConfiguration{
+		BindPort:               int32(s.SecureServing.BindPort),
+		ServiceClusterIPRanges: s.ServiceClusterIPRanges,
+		ServiceNodePortRange:   s.ServiceNodePortRange.String(),
+		AllowPrivileged:        s.AllowPrivileged,
+		EventTTL:               metav1.Duration{Duration: s.EventTTL},
+		Etcd: kubeapiserverconfig.EtcdStorageConfiguration{
+			Servers:                                 s.Etcd.StorageConfig.Transport.ServerList,
+			Prefix:                                  s.Etcd.StorageConfig.Prefix,
+			EncryptionProviderConfigFile:            s.Etcd.EncryptionProviderConfigFilepath,
+			EncryptionProviderConfigAutomaticReload: s.Etcd.EncryptionProviderConfigAutomaticReload,
+		},
+		Authorization: kubeapiserverconfig.AuthorizationConfiguration{
+			Modes: s.Authorization.Modes,
+		},
+		Admission: kubeapiserverconfig.AdmissionPluginConfiguration{
+			EnablePlugins:  s.Admission.GenericAdmission.EnablePlugins,
+			DisablePlugins: s.Admission.GenericAdmission.DisablePlugins,
+		},
+	}
+	if s.GenericServerRunOptions.AdvertiseAddress != nil {
+		cfg.AdvertiseAddress = s.GenericServerRunOptions.AdvertiseAddress.String()
+	}
+	return cfg
+}
+
+func encodeConfig(cfg *kubeapiserverconfig.KubeAPIServerConfiguration) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	const mediaType = runtime.ContentTypeYAML
+	info, ok := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), mediaType)
+	if !ok {
+		return buf, fmt.Errorf("unable to locate encoder -- %q is not a supported media type", mediaType)
+	}
+
+	encoder := scheme.Codecs.EncoderForVersion(info.Serializer, kubeapiserverconfigv1alpha1.SchemeGroupVersion)
+	if err := encoder.Encode(cfg, buf); err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// WriteConfigFile writes the versioned component config derived from the given
+// options into the given file name as YAML.
+func WriteConfigFile(fileName string, s *ServerRunOptions) error {
+	buf, err := encodeConfig(FromServerRunOptions(s))
+	if err != nil {
+		return err
+	}
+
+	configFile, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer configFile.Close()
+	if _, err := io.Copy(configFile, buf); err != nil {
+		return err
+	}
+	klog.InfoS("Wrote configuration", "file", fileName)
+	return nil
+}
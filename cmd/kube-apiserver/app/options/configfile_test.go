@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	netutils "k8s.io/utils/net"
+
+	"k8s.io/kubernetes/pkg/kubeapiserver/apis/config/validation"
+)
+
+func TestFromServerRunOptions(t *testing.T) {
+	s := NewServerRunOptions()
+	s.GenericServerRunOptions.AdvertiseAddress = netutils.ParseIPSloppy("192.168.10.4")
+	s.ServiceClusterIPRanges = "192.168.128.0/17"
+	s.AllowPrivileged = true
+	s.EventTTL = 2 * time.Hour
+	s.Etcd.StorageConfig.Transport.ServerList = []string{"http://127.0.0.1:2379"}
+	s.Etcd.EncryptionProviderConfigFilepath = "/path/to/encryption-config.yaml"
+	s.Authorization.Modes = []string{"AlwaysAllow"}
+	s.Admission.GenericAdmission.EnablePlugins = []string{"AlwaysDeny"}
+
+	cfg := FromServerRunOptions(s)
+
+	if cfg.AdvertiseAddress != "192.168.10.4" {
+		t.Errorf("wrong advertise address: %q", cfg.AdvertiseAddress)
+	}
+	if cfg.BindPort != int32(s.SecureServing.BindPort) {
+		t.Errorf("wrong bind port: %d", cfg.BindPort)
+	}
+	if cfg.ServiceClusterIPRanges != "192.168.128.0/17" {
+		t.Errorf("wrong service cluster ip ranges: %q", cfg.ServiceClusterIPRanges)
+	}
+	if !cfg.AllowPrivileged {
+		t.Error("expected allowPrivileged to be carried over")
+	}
+	if cfg.EventTTL.Duration != 2*time.Hour {
+		t.Errorf("wrong event TTL: %v", cfg.EventTTL.Duration)
+	}
+	if len(cfg.Etcd.Servers) != 1 || cfg.Etcd.Servers[0] != "http://127.0.0.1:2379" {
+		t.Errorf("wrong etcd servers: %v", cfg.Etcd.Servers)
+	}
+	if cfg.Etcd.EncryptionProviderConfigFile != "/path/to/encryption-config.yaml" {
+		t.Errorf("wrong encryption provider config file: %q", cfg.Etcd.EncryptionProviderConfigFile)
+	}
+	if len(cfg.Admission.EnablePlugins) != 1 || cfg.Admission.EnablePlugins[0] != "AlwaysDeny" {
+		t.Errorf("wrong admission enable plugins: %v", cfg.Admission.EnablePlugins)
+	}
+
+	if errs := validation.ValidateKubeAPIServerConfiguration(cfg); len(errs) != 0 {
+		t.Errorf("expected config built from populated options to validate, got: %v", errs.ToAggregate())
+	}
+}
+
+func TestEncodeConfig(t *testing.T) {
+	s := NewServerRunOptions()
+	s.Etcd.StorageConfig.Transport.ServerList = []string{"http://127.0.0.1:2379"}
+	s.Authorization.Modes = []string{"RBAC"}
+
+	buf, err := encodeConfig(FromServerRunOptions(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"apiVersion: kubeapiserver.config.k8s.io/v1alpha1",
+		"kind: KubeAPIServerConfiguration",
+		"http://127.0.0.1:2379",
+		"- RBAC",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("encoded config missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -139,6 +139,7 @@ func TestAddFlags(t *testing.T) {
 			MinRequestTimeout:           1800,
 			JSONPatchMaxCopyBytes:       int64(3 * 1024 * 1024),
 			MaxRequestBodyBytes:         int64(3 * 1024 * 1024),
+			ReadOnlyModeExemptions:      []string{"leases.coordination.k8s.io", "events", "events.events.k8s.io"},
 		},
 		Admission: &kubeoptions.AdmissionOptions{
 			GenericAdmission: &apiserveroptions.AdmissionOptions{
@@ -160,13 +161,15 @@ func TestAddFlags(t *testing.T) {
 					CertFile:       "/var/run/kubernetes/etcdce.crt",
 					TracerProvider: oteltrace.NewNoopTracerProvider(),
 				},
-				Paging:                true,
-				Prefix:                "/registry",
-				CompactionInterval:    storagebackend.DefaultCompactInterval,
-				CountMetricPollPeriod: time.Minute,
-				DBMetricPollInterval:  storagebackend.DefaultDBMetricPollInterval,
-				HealthcheckTimeout:    storagebackend.DefaultHealthcheckTimeout,
-				ReadycheckTimeout:     storagebackend.DefaultReadinessTimeout,
+				Paging:                       true,
+				Prefix:                       "/registry",
+				ListDecryptWorkers:           etcd3.DefaultListDecryptWorkers,
+				CompactionInterval:           storagebackend.DefaultCompactInterval,
+				CountMetricPollPeriod:        time.Minute,
+				DBMetricPollInterval:         storagebackend.DefaultDBMetricPollInterval,
+				EndpointRoutingProbeInterval: storagebackend.DefaultEndpointRoutingProbeInterval,
+				HealthcheckTimeout:           storagebackend.DefaultHealthcheckTimeout,
+				ReadycheckTimeout:            storagebackend.DefaultReadinessTimeout,
 				LeaseManagerConfig: etcd3.LeaseManagerConfig{
 					ReuseDurationSeconds: 100,
 					MaxObjectCount:       1000,
@@ -178,6 +181,7 @@ func TestAddFlags(t *testing.T) {
 			EnableWatchCache:        true,
 			DefaultWatchCacheSize:   100,
 		},
+		HealthProbes: apiserveroptions.NewHealthProbesOptions(),
 		SecureServing: (&apiserveroptions.SecureServingOptions{
 			BindAddress: netutils.ParseIPSloppy("192.168.10.20"),
 			BindPort:    6443,
@@ -153,6 +153,7 @@ func (s *ServerRunOptions) Validate() []error {
 	errs = append(errs, validateServiceNodePort(s)...)
 	errs = append(errs, validateAPIPriorityAndFairness(s)...)
 	errs = append(errs, s.SecureServing.Validate()...)
+	errs = append(errs, s.HealthProbes.Validate()...)
 	errs = append(errs, s.Authentication.Validate()...)
 	errs = append(errs, s.Authorization.Validate()...)
 	errs = append(errs, s.Audit.Validate()...)
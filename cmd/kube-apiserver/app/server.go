@@ -34,6 +34,7 @@ import (
 	oteltrace "go.opentelemetry.io/otel/trace"
 
 	extensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -128,6 +129,9 @@ cluster's shared state through which all other components interact.`,
 			if errs := completedOptions.Validate(); len(errs) != 0 {
 				return utilerrors.NewAggregate(errs)
 			}
+			if len(completedOptions.WriteConfigTo) > 0 {
+				return options.WriteConfigFile(completedOptions.WriteConfigTo, completedOptions.ServerRunOptions)
+			}
 			// add feature enablement metrics
 			utilfeature.DefaultMutableFeatureGate.AddMetrics()
 			return Run(completedOptions, genericapiserver.SetupSignalHandler())
@@ -354,6 +358,11 @@ func buildGenericConfig(
 	storageFactory *serverstorage.DefaultStorageFactory,
 	lastErr error,
 ) {
+	if utilfeature.DefaultFeatureGate.Enabled(genericfeatures.StreamingCollectionEncoding) {
+		// rebuild the legacy codecs so every API group installed below picks up
+		// serializers that stream list responses instead of buffering them
+		legacyscheme.Codecs = serializer.NewCodecFactory(legacyscheme.Scheme, serializer.EnableStreamingCollections)
+	}
 	genericConfig = genericapiserver.NewConfig(legacyscheme.Codecs)
 	genericConfig.MergedResourceConfig = controlplane.DefaultAPIResourceConfigSource()
 
@@ -364,6 +373,9 @@ func buildGenericConfig(
 	if lastErr = s.SecureServing.ApplyTo(&genericConfig.SecureServing, &genericConfig.LoopbackClientConfig); lastErr != nil {
 		return
 	}
+	if lastErr = s.HealthProbes.ApplyTo(genericConfig); lastErr != nil {
+		return
+	}
 	if lastErr = s.Features.ApplyTo(genericConfig); lastErr != nil {
 		return
 	}
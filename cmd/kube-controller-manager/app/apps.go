@@ -28,6 +28,7 @@ import (
 	"k8s.io/controller-manager/controller"
 	"k8s.io/kubernetes/pkg/controller/daemon"
 	"k8s.io/kubernetes/pkg/controller/deployment"
+	"k8s.io/kubernetes/pkg/controller/reload"
 	"k8s.io/kubernetes/pkg/controller/replicaset"
 	"k8s.io/kubernetes/pkg/controller/statefulset"
 )
@@ -82,3 +83,14 @@ func startDeploymentController(ctx context.Context, controllerContext Controller
 	go dc.Run(ctx, int(controllerContext.ComponentConfig.DeploymentController.ConcurrentDeploymentSyncs))
 	return nil, true, nil
 }
+
+func startReloadController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	go reload.NewReloadController(
+		controllerContext.InformerFactory.Apps().V1().Deployments(),
+		controllerContext.InformerFactory.Apps().V1().StatefulSets(),
+		controllerContext.InformerFactory.Core().V1().ConfigMaps(),
+		controllerContext.InformerFactory.Core().V1().Secrets(),
+		controllerContext.ClientBuilder.ClientOrDie("reload-controller"),
+	).Run(ctx, 1)
+	return nil, true, nil
+}
@@ -73,6 +73,7 @@ import (
 	"k8s.io/kubernetes/cmd/kube-controller-manager/app/config"
 	"k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
 	kubectrlmgrconfig "k8s.io/kubernetes/pkg/controller/apis/config"
+	"k8s.io/kubernetes/pkg/controller/requeue"
 	serviceaccountcontroller "k8s.io/kubernetes/pkg/controller/serviceaccount"
 	"k8s.io/kubernetes/pkg/serviceaccount"
 )
@@ -137,6 +138,9 @@ controller, and serviceaccounts controller.`,
 			if err != nil {
 				return err
 			}
+			if len(s.WriteConfigTo) > 0 {
+				return options.WriteConfigFile(s.WriteConfigTo, &c.ComponentConfig)
+			}
 			// add feature enablement metrics
 			utilfeature.DefaultMutableFeatureGate.AddMetrics()
 			return Run(c.Complete(), wait.NeverStop)
@@ -370,6 +374,12 @@ type ControllerContext struct {
 
 	// ControllerManagerMetrics provides a proxy to set controller manager specific metrics.
 	ControllerManagerMetrics *controllersmetrics.ControllerManagerMetrics
+
+	// ReconnectRequeuer lets controllers register an informer and work queue
+	// for prioritized, rate-limited requeue of the objects that changed while
+	// the connection to the apiserver was down, instead of relying on a full
+	// resync. It may be nil in tests.
+	ReconnectRequeuer *requeue.Coordinator
 }
 
 // IsControllerEnabled checks if the context's controllers enabled or not
@@ -411,6 +421,9 @@ func KnownControllers() []string {
 // ControllersDisabledByDefault is the set of controllers which is disabled by default
 var ControllersDisabledByDefault = sets.NewString(
 	"bootstrapsigner",
+	"cluster-properties",
+	"janitor",
+	"reload",
 	"tokencleaner",
 )
 
@@ -436,6 +449,7 @@ func NewControllerInitializers(loopMode ControllerLoopMode) map[string]InitFunc
 	register("endpointslicemirroring", startEndpointSliceMirroringController)
 	register("replicationcontroller", startReplicationController)
 	register("podgc", startPodGCController)
+	register("janitor", startJanitorController)
 	register("resourcequota", startResourceQuotaController)
 	register("namespace", startNamespaceController)
 	register("serviceaccount", startServiceAccountController)
@@ -447,6 +461,7 @@ func NewControllerInitializers(loopMode ControllerLoopMode) map[string]InitFunc
 	register("horizontalpodautoscaling", startHPAController)
 	register("disruption", startDisruptionController)
 	register("statefulset", startStatefulSetController)
+	register("reload", startReloadController)
 	register("cronjob", startCronJobController)
 	register("csrsigning", startCSRSigningController)
 	register("csrapproving", startCSRApprovingController)
@@ -456,6 +471,8 @@ func NewControllerInitializers(loopMode ControllerLoopMode) map[string]InitFunc
 	register("tokencleaner", startTokenCleanerController)
 	register("nodeipam", startNodeIpamController)
 	register("nodelifecycle", startNodeLifecycleController)
+	register("nodedecommission", startNodeDecommissionController)
+	register("image-prepull", startImagePrepullController)
 	if loopMode == IncludeCloudLoops {
 		register("service", startServiceController)
 		register("route", startRouteController)
@@ -466,6 +483,7 @@ func NewControllerInitializers(loopMode ControllerLoopMode) map[string]InitFunc
 	register("attachdetach", startAttachDetachController)
 	register("persistentvolume-expander", startVolumeExpandController)
 	register("clusterrole-aggregation", startClusterRoleAggregrationController)
+	register("cluster-properties", startClusterPropertyController)
 	register("pvc-protection", startPVCProtectionController)
 	register("pv-protection", startPVProtectionController)
 	register("ttl-after-finished", startTTLAfterFinishedController)
@@ -546,6 +564,15 @@ func CreateControllerContext(s *config.CompletedConfig, rootClientBuilder, clien
 		return ControllerContext{}, err
 	}
 
+	reconnectRequeuer := requeue.NewCoordinator(func(ctx context.Context) error {
+		return versionedClient.Discovery().RESTClient().Get().AbsPath("/healthz").Do(ctx).Error()
+	}, 10*time.Second, 20)
+	requeueCtx, cancelRequeuer := wait.ContextForChannel(stop)
+	go func() {
+		defer cancelRequeuer()
+		reconnectRequeuer.Run(requeueCtx)
+	}()
+
 	ctx := ControllerContext{
 		ClientBuilder:                   clientBuilder,
 		InformerFactory:                 sharedInformers,
@@ -558,6 +585,7 @@ func CreateControllerContext(s *config.CompletedConfig, rootClientBuilder, clien
 		InformersStarted:                make(chan struct{}),
 		ResyncPeriod:                    ResyncPeriod(s),
 		ControllerManagerMetrics:        controllersmetrics.NewControllerManagerMetrics("kube-controller-manager"),
+		ReconnectRequeuer:               reconnectRequeuer,
 	}
 	controllersmetrics.Register()
 	return ctx, nil
@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/quota/v1/generic"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/metadata"
 	restclient "k8s.io/client-go/rest"
@@ -47,7 +48,10 @@ import (
 	pkgcontroller "k8s.io/kubernetes/pkg/controller"
 	endpointcontroller "k8s.io/kubernetes/pkg/controller/endpoint"
 	"k8s.io/kubernetes/pkg/controller/garbagecollector"
+	imageprepullcontroller "k8s.io/kubernetes/pkg/controller/imageprepull"
+	janitorcontroller "k8s.io/kubernetes/pkg/controller/janitor"
 	namespacecontroller "k8s.io/kubernetes/pkg/controller/namespace"
+	nodedecommissioncontroller "k8s.io/kubernetes/pkg/controller/nodedecommission"
 	nodeipamcontroller "k8s.io/kubernetes/pkg/controller/nodeipam"
 	nodeipamconfig "k8s.io/kubernetes/pkg/controller/nodeipam/config"
 	"k8s.io/kubernetes/pkg/controller/nodeipam/ipam"
@@ -405,6 +409,20 @@ func startPodGCController(ctx context.Context, controllerContext ControllerConte
 	return nil, true, nil
 }
 
+func startJanitorController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	go janitorcontroller.NewJanitor(
+		controllerContext.ClientBuilder.ClientOrDie("janitor-controller"),
+		controllerContext.InformerFactory.Apps().V1().ControllerRevisions(),
+		controllerContext.InformerFactory.Apps().V1().DaemonSets(),
+		controllerContext.InformerFactory.Apps().V1().StatefulSets(),
+		controllerContext.InformerFactory.Discovery().V1().EndpointSlices(),
+		controllerContext.InformerFactory.Core().V1().Services(),
+		controllerContext.InformerFactory.Coordination().V1().Leases(),
+		janitorcontroller.DefaultPolicies(),
+	).Run(ctx)
+	return nil, true, nil
+}
+
 func startResourceQuotaController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
 	resourceQuotaControllerClient := controllerContext.ClientBuilder.ClientOrDie("resourcequota-controller")
 	resourceQuotaControllerDiscoveryClient := controllerContext.ClientBuilder.DiscoveryClientOrDie("resourcequota-controller")
@@ -483,6 +501,33 @@ func startServiceAccountController(ctx context.Context, controllerContext Contro
 	return nil, true, nil
 }
 
+func startNodeDecommissionController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	go nodedecommissioncontroller.NewNodeDecommissionController(
+		controllerContext.InformerFactory.Core().V1().Nodes(),
+		controllerContext.InformerFactory.Storage().V1().VolumeAttachments(),
+		controllerContext.ClientBuilder.ClientOrDie("node-decommission-controller"),
+	).Run(ctx, 1)
+	return nil, true, nil
+}
+
+func startImagePrepullController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	// the ImagePrepullSet API is alpha, only start when it is served
+	if !controllerContext.AvailableResources[imageprepullcontroller.ImagePrepullSetResource] {
+		return nil, false, nil
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(controllerContext.ClientBuilder.ConfigOrDie("image-prepull-controller"))
+	if err != nil {
+		return nil, true, err
+	}
+	go imageprepullcontroller.NewImagePrepullController(
+		controllerContext.ClientBuilder.ClientOrDie("image-prepull-controller"),
+		dynamicClient,
+		time.Minute,
+	).Run(ctx)
+	return nil, true, nil
+}
+
 func startTTLController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
 	go ttlcontroller.NewTTLController(
 		controllerContext.InformerFactory.Core().V1().Nodes(),
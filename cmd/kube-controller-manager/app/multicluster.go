@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	clusterpropertyhelpers "k8s.io/component-helpers/multicluster/clusterproperty"
+	"k8s.io/controller-manager/controller"
+	"k8s.io/kubernetes/pkg/controller/clusterproperty"
+)
+
+func startClusterPropertyController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	// the About API is served by a CRD, only start when it is installed
+	if !controllerContext.AvailableResources[clusterpropertyhelpers.ClusterPropertyResource] {
+		return nil, false, nil
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(controllerContext.ClientBuilder.ConfigOrDie("cluster-property-controller"))
+	if err != nil {
+		return nil, true, err
+	}
+	go clusterproperty.NewClusterPropertyController(
+		dynamicClient,
+		5*time.Minute,
+	).Run(ctx)
+	return nil, true, nil
+}
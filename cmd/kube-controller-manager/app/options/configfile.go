@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	kubectrlmgrconfigv1alpha1 "k8s.io/kube-controller-manager/config/v1alpha1"
+	kubectrlmgrconfig "k8s.io/kubernetes/pkg/controller/apis/config"
+	kubectrlmgrconfigscheme "k8s.io/kubernetes/pkg/controller/apis/config/scheme"
+)
+
+func encodeConfig(cfg *kubectrlmgrconfig.KubeControllerManagerConfiguration) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	const mediaType = runtime.ContentTypeYAML
+	info, ok := runtime.SerializerInfoForMediaType(kubectrlmgrconfigscheme.Codecs.SupportedMediaTypes(), mediaType)
+	if !ok {
+		return buf, fmt.Errorf("unable to locate encoder -- %q is not a supported media type", mediaType)
+	}
+
+	encoder := kubectrlmgrconfigscheme.Codecs.EncoderForVersion(info.Serializer, kubectrlmgrconfigv1alpha1.SchemeGroupVersion)
+	if err := encoder.Encode(cfg, buf); err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// WriteConfigFile writes the given component config into the given file name as YAML.
+func WriteConfigFile(fileName string, cfg *kubectrlmgrconfig.KubeControllerManagerConfiguration) error {
+	buf, err := encodeConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	configFile, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer configFile.Close()
+	if _, err := io.Copy(configFile, buf); err != nil {
+		return err
+	}
+	klog.InfoS("Wrote configuration", "file", fileName)
+	return nil
+}
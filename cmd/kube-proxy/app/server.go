@@ -75,6 +75,7 @@ import (
 	"k8s.io/kubernetes/pkg/proxy/apis/config/validation"
 	"k8s.io/kubernetes/pkg/proxy/config"
 	"k8s.io/kubernetes/pkg/proxy/healthcheck"
+	"k8s.io/kubernetes/pkg/proxy/programming"
 	proxyutil "k8s.io/kubernetes/pkg/proxy/util"
 	"k8s.io/kubernetes/pkg/util/filesystem"
 	utilflag "k8s.io/kubernetes/pkg/util/flag"
@@ -163,6 +164,7 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.Var(&utilflag.IPVar{Val: &o.config.BindAddress}, "bind-address", "The IP address for the proxy server to serve on (set to '0.0.0.0' for all IPv4 interfaces and '::' for all IPv6 interfaces). This parameter is ignored if a config file is specified by --config.")
 	fs.Var(&utilflag.IPPortVar{Val: &o.config.HealthzBindAddress}, "healthz-bind-address", "The IP address with port for the health check server to serve on (set to '0.0.0.0:10256' for all IPv4 interfaces and '[::]:10256' for all IPv6 interfaces). Set empty to disable. This parameter is ignored if a config file is specified by --config.")
 	fs.Var(&utilflag.IPPortVar{Val: &o.config.MetricsBindAddress}, "metrics-bind-address", "The IP address with port for the metrics server to serve on (set to '0.0.0.0:10249' for all IPv4 interfaces and '[::]:10249' for all IPv6 interfaces). Set empty to disable. This parameter is ignored if a config file is specified by --config.")
+	fs.Var(&utilflag.IPPortVar{Val: &o.config.ProgrammingBindAddress}, "programming-bind-address", "The IP address with port for the service programming API to serve on (e.g. '127.0.0.1:10258'). The API exposes the joined service, endpoint slice and node topology view to external dataplanes. Set empty to disable (default). This parameter is ignored if a config file is specified by --config.")
 	fs.BoolVar(&o.config.BindAddressHardFail, "bind-address-hard-fail", o.config.BindAddressHardFail, "If true kube-proxy will treat failure to bind to a port as fatal and exit")
 	fs.Var(utilflag.PortRangeVar{Val: &o.config.PortRange}, "proxy-port-range", "Range of host ports (beginPort-endPort, single port or beginPort+offset, inclusive) that may be consumed in order to proxy service traffic. If (unspecified, 0, or 0-0) then ports will be randomly chosen.")
 	fs.Var(&o.config.Mode, "proxy-mode", "Which proxy mode to use: on Linux this can be 'iptables' (default) or 'ipvs'. On Windows the only supported value is 'kernelspace'."+
@@ -539,6 +541,7 @@ type ProxyServer struct {
 	ProxyMode              kubeproxyconfig.ProxyMode
 	NodeRef                *v1.ObjectReference
 	MetricsBindAddress     string
+	ProgrammingBindAddress string
 	BindAddressHardFail    bool
 	EnableProfiling        bool
 	OOMScoreAdj            *int32
@@ -648,6 +651,27 @@ func serveMetrics(bindAddress string, proxyMode kubeproxyconfig.ProxyMode, enabl
 	go wait.Until(fn, 5*time.Second, wait.NeverStop)
 }
 
+func serveProgramming(bindAddress string, server *programming.Server, errCh chan error) {
+	if len(bindAddress) == 0 {
+		return
+	}
+
+	fn := func() {
+		err := http.ListenAndServe(bindAddress, server.Handler())
+		if err != nil {
+			err = fmt.Errorf("starting service programming API server failed: %v", err)
+			utilruntime.HandleError(err)
+			if errCh != nil {
+				errCh <- err
+				// if in hardfail mode, never retry again
+				blockCh := make(chan error)
+				<-blockCh
+			}
+		}
+	}
+	go wait.Until(fn, 5*time.Second, wait.NeverStop)
+}
+
 // Run runs the specified ProxyServer.  This should never exit (unless CleanupAndExit is set).
 // TODO: At the moment, Run() cannot return a nil error, otherwise it's caller will never exit. Update callers of Run to handle nil errors.
 func (s *ProxyServer) Run() error {
@@ -747,12 +771,26 @@ func (s *ProxyServer) Run() error {
 	// Note: RegisterHandler() calls need to happen before creation of Sources because sources
 	// only notify on changes, and the initial update (on process start) may be lost if no handlers
 	// are registered yet.
+	// The service programming API consumes the same watches as the proxier,
+	// so external dataplanes see exactly the state kube-proxy would program.
+	var programmingServer *programming.Server
+	if len(s.ProgrammingBindAddress) > 0 {
+		programmingServer = programming.NewServer(s.NodeRef.Name)
+		serveProgramming(s.ProgrammingBindAddress, programmingServer, errCh)
+	}
+
 	serviceConfig := config.NewServiceConfig(informerFactory.Core().V1().Services(), s.ConfigSyncPeriod)
 	serviceConfig.RegisterEventHandler(s.Proxier)
+	if programmingServer != nil {
+		serviceConfig.RegisterEventHandler(programmingServer)
+	}
 	go serviceConfig.Run(wait.NeverStop)
 
 	endpointSliceConfig := config.NewEndpointSliceConfig(informerFactory.Discovery().V1().EndpointSlices(), s.ConfigSyncPeriod)
 	endpointSliceConfig.RegisterEventHandler(s.Proxier)
+	if programmingServer != nil {
+		endpointSliceConfig.RegisterEventHandler(programmingServer)
+	}
 	go endpointSliceConfig.Run(wait.NeverStop)
 
 	// This has to start after the calls to NewServiceConfig because that
@@ -770,6 +808,9 @@ func (s *ProxyServer) Run() error {
 		nodeConfig.RegisterEventHandler(&proxy.NodePodCIDRHandler{})
 	}
 	nodeConfig.RegisterEventHandler(s.Proxier)
+	if programmingServer != nil {
+		nodeConfig.RegisterEventHandler(programmingServer)
+	}
 
 	go nodeConfig.Run(wait.NeverStop)
 
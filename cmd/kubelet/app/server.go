@@ -97,7 +97,9 @@ import (
 	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
 	"k8s.io/kubernetes/pkg/kubelet/kubeletconfig/configfiles"
 	kubeletmetrics "k8s.io/kubernetes/pkg/kubelet/metrics"
+	"k8s.io/kubernetes/pkg/kubelet/nodehealth"
 	"k8s.io/kubernetes/pkg/kubelet/server"
+	"k8s.io/kubernetes/pkg/kubelet/standalone"
 	"k8s.io/kubernetes/pkg/kubelet/stats/pidlimit"
 	kubeletutil "k8s.io/kubernetes/pkg/kubelet/util"
 	utilfs "k8s.io/kubernetes/pkg/util/filesystem"
@@ -612,6 +614,13 @@ func run(ctx context.Context, s *options.KubeletServer, kubeDeps *kubelet.Depend
 		if err != nil {
 			return fmt.Errorf("failed to initialize kubelet heartbeat client: %w", err)
 		}
+
+		// measuring clock skew is best effort; without a detector the
+		// ClockSkew node condition is simply not reported
+		kubeDeps.ClockSkewDetector, err = nodehealth.NewClockSkewDetector(clientConfig)
+		if err != nil {
+			klog.InfoS("Failed to initialize clock skew detector, the ClockSkew node condition will not be reported", "err", err)
+		}
 	}
 
 	if kubeDeps.Auth == nil {
@@ -1175,6 +1184,28 @@ func RunKubelet(kubeServer *options.KubeletServer, kubeDeps *kubelet.Dependencie
 		klog.ErrorS(err, "Failed to set rlimit on max file handles")
 	}
 
+	if len(kubeServer.LocalManifestAPISocket) > 0 {
+		if len(kubeServer.KubeConfig) > 0 {
+			return fmt.Errorf("--local-manifest-api-socket is only supported in standalone mode")
+		}
+		if len(kubeServer.StaticPodPath) == 0 {
+			return fmt.Errorf("--local-manifest-api-socket requires the static pod path to be configured")
+		}
+		statusStore := standalone.NewStatusStore(filepath.Join(kubeServer.RootDirectory, "standalone-pod-status.json"))
+		if err := statusStore.Load(); err != nil {
+			klog.ErrorS(err, "Failed to load persisted pod statuses, continuing with an empty store")
+		}
+		// The concrete kubelet implements GetPods; the interfaces used for
+		// bootstrapping do not expose it.
+		podsProvider, _ := k.(standalone.PodsProvider)
+		manifestServer := standalone.NewServer(kubeServer.LocalManifestAPISocket, kubeServer.StaticPodPath, podsProvider, statusStore)
+		go func() {
+			if err := manifestServer.ListenAndServe(wait.NeverStop); err != nil {
+				klog.ErrorS(err, "Failed to serve local manifest API")
+			}
+		}()
+	}
+
 	// process pods and exit.
 	if runOnce {
 		if _, err := k.RunOnce(podCfg.Updates()); err != nil {
@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -76,6 +77,10 @@ type hollowNodeConfig struct {
 	MaxPods                 int
 	ExtendedResources       map[string]string
 	UseHostImageService     bool
+	NodeCores               int
+	NodeMemory              string
+	PressureConditions      []string
+	PodStartupLatency       string
 }
 
 const (
@@ -107,6 +112,10 @@ func (c *hollowNodeConfig) addFlags(fs *pflag.FlagSet) {
 	bindableExtendedResources := cliflag.ConfigurationMap(c.ExtendedResources)
 	fs.Var(&bindableExtendedResources, "extended-resources", "Register the node with extended resources (comma separated \"<name>=<quantity>\")")
 	fs.BoolVar(&c.UseHostImageService, "use-host-image-service", true, "Set to true if the hollow-kubelet should use the host image service. If set to false the fake image service will be used")
+	fs.IntVar(&c.NodeCores, "simulated-node-cores", 0, "Number of cores the hollow node reports. 0 keeps the default single core.")
+	fs.StringVar(&c.NodeMemory, "simulated-node-memory", "", "Memory capacity the hollow node reports as a resource quantity (e.g. \"16Gi\"). Empty keeps the default 3.75GB.")
+	fs.StringSliceVar(&c.PressureConditions, "simulated-pressure-conditions", nil, "Comma separated node conditions to keep asserted on the hollow node (e.g. \"MemoryPressure,DiskPressure\").")
+	fs.StringVar(&c.PodStartupLatency, "simulated-pod-startup-latency", "", "Pod startup latency distribution by quantile (e.g. \"p50=1s,p90=5s,p99=30s\"). Empty starts pods instantly.")
 }
 
 func (c *hollowNodeConfig) createClientConfigFromFile() (*restclient.Config, error) {
@@ -224,6 +233,14 @@ func run(cmd *cobra.Command, config *hollowNodeConfig) error {
 
 		cadvisorInterface := &cadvisortest.Fake{
 			NodeName: config.NodeName,
+			NumCores: config.NodeCores,
+		}
+		if config.NodeMemory != "" {
+			memory, err := resource.ParseQuantity(config.NodeMemory)
+			if err != nil {
+				return fmt.Errorf("Failed to parse --simulated-node-memory %q, error: %w", config.NodeMemory, err)
+			}
+			cadvisorInterface.MemoryCapacity = uint64(memory.Value())
 		}
 
 		var containerManager cm.ContainerManager
@@ -251,6 +268,11 @@ func run(cmd *cobra.Command, config *hollowNodeConfig) error {
 		if err != nil {
 			return fmt.Errorf("Failed to init runtime service, error: %w", err)
 		}
+		startupLatency, err := kubemark.ParseLatencyDistribution(config.PodStartupLatency)
+		if err != nil {
+			return fmt.Errorf("Failed to parse --simulated-pod-startup-latency, error: %w", err)
+		}
+		runtimeService = kubemark.NewRuntimeServiceWithLatency(runtimeService, startupLatency)
 
 		var imageService internalapi.ImageManagerService = fakeRemoteRuntime.ImageService
 		if config.UseHostImageService {
@@ -269,6 +291,14 @@ func run(cmd *cobra.Command, config *hollowNodeConfig) error {
 			runtimeService,
 			containerManager,
 		)
+		if len(config.PressureConditions) > 0 {
+			conditions := make([]v1.NodeConditionType, 0, len(config.PressureConditions))
+			for _, condition := range config.PressureConditions {
+				conditions = append(conditions, v1.NodeConditionType(condition))
+			}
+			injector := kubemark.NewPressureInjector(client, config.NodeName, conditions, 5*time.Second)
+			go injector.Run(wait.NeverStop)
+		}
 		hollowKubelet.Run()
 	}
 
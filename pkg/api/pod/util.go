@@ -545,6 +545,15 @@ func dropDisabledFields(
 		podSpec.SchedulingGates = nil
 	}
 
+	// If the feature is disabled and not in use, drop the readiness gate default and expiry fields.
+	if !utilfeature.DefaultFeatureGate.Enabled(features.PodReadinessGateDefaults) && !readinessGateDefaultsInUse(oldPodSpec) {
+		for i := range podSpec.ReadinessGates {
+			podSpec.ReadinessGates[i].DefaultStatus = ""
+			podSpec.ReadinessGates[i].ExpirySeconds = 0
+			podSpec.ReadinessGates[i].ExpiryStatus = ""
+		}
+	}
+
 	dropDisabledProcMountField(podSpec, oldPodSpec)
 
 	dropDisabledTopologySpreadConstraintsFields(podSpec, oldPodSpec)
@@ -763,6 +772,20 @@ func probeGracePeriodInUse(podSpec *api.PodSpec) bool {
 	return inUse
 }
 
+// readinessGateDefaultsInUse returns true if the pod spec is non-nil and one of
+// its readiness gates sets a default status or an expiry.
+func readinessGateDefaultsInUse(podSpec *api.PodSpec) bool {
+	if podSpec == nil {
+		return false
+	}
+	for _, rg := range podSpec.ReadinessGates {
+		if rg.DefaultStatus != "" || rg.ExpirySeconds != 0 || rg.ExpiryStatus != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // schedulingGatesInUse returns true if the pod spec is non-nil and it has SchedulingGates field set.
 func schedulingGatesInUse(podSpec *api.PodSpec) bool {
 	if podSpec == nil {
@@ -2095,6 +2095,95 @@ func TestDropHostUsers(t *testing.T) {
 
 }
 
+func TestDropReadinessGateDefaults(t *testing.T) {
+	podWithReadinessGateDefaults := func() *api.Pod {
+		return &api.Pod{
+			Spec: api.PodSpec{
+				ReadinessGates: []api.PodReadinessGate{
+					{ConditionType: api.PodConditionType("gate1"), DefaultStatus: api.ConditionTrue, ExpirySeconds: 60, ExpiryStatus: api.ConditionFalse},
+				},
+			},
+		}
+	}
+	podWithPlainReadinessGate := func() *api.Pod {
+		return &api.Pod{
+			Spec: api.PodSpec{
+				ReadinessGates: []api.PodReadinessGate{
+					{ConditionType: api.PodConditionType("gate1")},
+				},
+			},
+		}
+	}
+
+	podInfo := []struct {
+		description string
+		hasDefaults bool
+		pod         func() *api.Pod
+	}{
+		{
+			description: "has readiness gate defaults",
+			hasDefaults: true,
+			pod:         podWithReadinessGateDefaults,
+		},
+		{
+			description: "has plain readiness gate",
+			hasDefaults: false,
+			pod:         podWithPlainReadinessGate,
+		},
+		{
+			description: "is nil",
+			hasDefaults: false,
+			pod:         func() *api.Pod { return nil },
+		},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		for _, oldPodInfo := range podInfo {
+			for _, newPodInfo := range podInfo {
+				oldPodHasDefaults, oldPod := oldPodInfo.hasDefaults, oldPodInfo.pod()
+				newPodHasDefaults, newPod := newPodInfo.hasDefaults, newPodInfo.pod()
+				if newPod == nil {
+					continue
+				}
+
+				t.Run(fmt.Sprintf("feature enabled=%v, old pod %v, new pod %v", enabled, oldPodInfo.description, newPodInfo.description), func(t *testing.T) {
+					defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PodReadinessGateDefaults, enabled)()
+					var oldPodSpec *api.PodSpec
+					if oldPod != nil {
+						oldPodSpec = &oldPod.Spec
+					}
+					dropDisabledFields(&newPod.Spec, nil, oldPodSpec, nil)
+					// Old Pod should never be changed.
+					if diff := cmp.Diff(oldPod, oldPodInfo.pod()); diff != "" {
+						t.Errorf("old pod changed: %v", diff)
+					}
+					switch {
+					case enabled || oldPodHasDefaults:
+						// New Pod should not be changed if the feature is enabled, or if the old Pod used the fields.
+						if diff := cmp.Diff(newPod, newPodInfo.pod()); diff != "" {
+							t.Errorf("new pod changed: %v", diff)
+						}
+					case newPodHasDefaults:
+						// New Pod should be changed.
+						if reflect.DeepEqual(newPod, newPodInfo.pod()) {
+							t.Errorf("new pod was not changed")
+						}
+						// New Pod should only keep the condition type.
+						if diff := cmp.Diff(newPod, podWithPlainReadinessGate()); diff != "" {
+							t.Errorf("new pod has readiness gate defaults: %v", diff)
+						}
+					default:
+						// New pod should not need to be changed.
+						if diff := cmp.Diff(newPod, newPodInfo.pod()); diff != "" {
+							t.Errorf("new pod changed: %v", diff)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
 func TestDropSchedulingGates(t *testing.T) {
 	podWithSchedulingGates := func() *api.Pod {
 		return &api.Pod{
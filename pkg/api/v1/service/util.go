@@ -84,6 +84,23 @@ func InternalPolicyLocal(service *v1.Service) bool {
 	return *service.Spec.InternalTrafficPolicy == v1.ServiceInternalTrafficPolicyLocal
 }
 
+// ExternalPolicyPreferLocal checks if service has ETP = PreferLocal.
+func ExternalPolicyPreferLocal(service *v1.Service) bool {
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer &&
+		service.Spec.Type != v1.ServiceTypeNodePort {
+		return false
+	}
+	return service.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypePreferLocal
+}
+
+// InternalPolicyPreferLocal checks if service has ITP = PreferLocal.
+func InternalPolicyPreferLocal(service *v1.Service) bool {
+	if service.Spec.InternalTrafficPolicy == nil {
+		return false
+	}
+	return *service.Spec.InternalTrafficPolicy == v1.ServiceInternalTrafficPolicyPreferLocal
+}
+
 // NeedsHealthCheck checks if service needs health check.
 func NeedsHealthCheck(service *v1.Service) bool {
 	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
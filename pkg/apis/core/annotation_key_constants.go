@@ -34,6 +34,12 @@ const (
 	// in the Annotations of a Node.
 	TaintsAnnotationKey string = "scheduler.alpha.kubernetes.io/taints"
 
+	// PodCreationBatchAnnotationKey represents the key of a creation batch
+	// identifier stamped by workload controllers on the Pods of one creation
+	// burst. The scheduler keeps pods sharing a value adjacent in its
+	// scheduling queue.
+	PodCreationBatchAnnotationKey string = "scheduler.alpha.kubernetes.io/creation-batch"
+
 	// SeccompPodAnnotationKey represents the key of a seccomp profile applied
 	// to all containers of a pod.
 	// Deprecated: set a pod security context `seccompProfile` field.
@@ -2866,6 +2866,20 @@ const (
 type PodReadinessGate struct {
 	// ConditionType refers to a condition in the pod's condition list with matching type.
 	ConditionType PodConditionType
+	// DefaultStatus is the status assumed for the condition while the controller
+	// owning the gate has not reported it.
+	// +optional
+	DefaultStatus ConditionStatus
+	// ExpirySeconds is the number of seconds after the pod's start time after
+	// which a condition that has still not been reported auto-resolves to
+	// ExpiryStatus. When zero, the gate never expires.
+	// +optional
+	ExpirySeconds int64
+	// ExpiryStatus is the status ("True" or "False") the condition resolves to
+	// once ExpirySeconds have elapsed. Defaults to "True". May only be set
+	// together with ExpirySeconds.
+	// +optional
+	ExpiryStatus ConditionStatus
 }
 
 // PodSpec is a description of a pod
@@ -3793,6 +3807,11 @@ const (
 	// node as the traffic was received on (dropping the traffic if there are no
 	// local endpoints).
 	ServiceInternalTrafficPolicyLocal ServiceInternalTrafficPolicyType = "Local"
+	// ServiceInternalTrafficPolicyPreferLocal routes traffic to endpoints on the same
+	// node as the client pod when enough of them are ready, and spills over to
+	// remote endpoints otherwise. This is an alpha value and requires enabling the
+	// ServiceTrafficPolicyPreferLocal feature gate.
+	ServiceInternalTrafficPolicyPreferLocal ServiceInternalTrafficPolicyType = "PreferLocal"
 )
 
 // ServiceExternalTrafficPolicyType describes the endpoint-selection policy for
@@ -3808,6 +3827,11 @@ const (
 	// routing only to endpoints on the same node as the traffic was received on
 	// (dropping the traffic if there are no local endpoints).
 	ServiceExternalTrafficPolicyTypeLocal ServiceExternalTrafficPolicyType = "Local"
+	// ServiceExternalTrafficPolicyTypePreferLocal routes traffic to endpoints on the
+	// same node as the traffic was received on when enough of them are ready, and
+	// spills over to remote endpoints otherwise. This is an alpha value and requires
+	// enabling the ServiceTrafficPolicyPreferLocal feature gate.
+	ServiceExternalTrafficPolicyTypePreferLocal ServiceExternalTrafficPolicyType = "PreferLocal"
 )
 
 // These are the valid conditions of a service.
@@ -6123,6 +6123,9 @@ func Convert_url_Values_To_v1_PodProxyOptions(in *url.Values, out *v1.PodProxyOp
 
 func autoConvert_v1_PodReadinessGate_To_core_PodReadinessGate(in *v1.PodReadinessGate, out *core.PodReadinessGate, s conversion.Scope) error {
 	out.ConditionType = core.PodConditionType(in.ConditionType)
+	out.DefaultStatus = core.ConditionStatus(in.DefaultStatus)
+	out.ExpirySeconds = in.ExpirySeconds
+	out.ExpiryStatus = core.ConditionStatus(in.ExpiryStatus)
 	return nil
 }
 
@@ -6133,6 +6136,9 @@ func Convert_v1_PodReadinessGate_To_core_PodReadinessGate(in *v1.PodReadinessGat
 
 func autoConvert_core_PodReadinessGate_To_v1_PodReadinessGate(in *core.PodReadinessGate, out *v1.PodReadinessGate, s conversion.Scope) error {
 	out.ConditionType = v1.PodConditionType(in.ConditionType)
+	out.DefaultStatus = v1.ConditionStatus(in.DefaultStatus)
+	out.ExpirySeconds = in.ExpirySeconds
+	out.ExpiryStatus = v1.ConditionStatus(in.ExpiryStatus)
 	return nil
 }
 
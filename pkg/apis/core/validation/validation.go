@@ -3373,6 +3373,23 @@ func validateReadinessGates(readinessGates []core.PodReadinessGate, fldPath *fie
 	allErrs := field.ErrorList{}
 	for i, value := range readinessGates {
 		allErrs = append(allErrs, ValidateQualifiedName(string(value.ConditionType), fldPath.Index(i).Child("conditionType"))...)
+		switch value.DefaultStatus {
+		case "", core.ConditionTrue, core.ConditionFalse, core.ConditionUnknown:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("defaultStatus"), value.DefaultStatus, []string{string(core.ConditionTrue), string(core.ConditionFalse), string(core.ConditionUnknown)}))
+		}
+		if value.ExpirySeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("expirySeconds"), value.ExpirySeconds, isNegativeErrorMsg))
+		}
+		switch value.ExpiryStatus {
+		case "":
+		case core.ConditionTrue, core.ConditionFalse:
+			if value.ExpirySeconds == 0 {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("expiryStatus"), "may only be specified when expirySeconds is set"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("expiryStatus"), value.ExpiryStatus, []string{string(core.ConditionTrue), string(core.ConditionFalse)}))
+		}
 	}
 	return allErrs
 }
@@ -4744,7 +4761,7 @@ var supportedSessionAffinityType = sets.NewString(string(core.ServiceAffinityCli
 var supportedServiceType = sets.NewString(string(core.ServiceTypeClusterIP), string(core.ServiceTypeNodePort),
 	string(core.ServiceTypeLoadBalancer), string(core.ServiceTypeExternalName))
 
-var supportedServiceInternalTrafficPolicy = sets.NewString(string(core.ServiceInternalTrafficPolicyCluster), string(core.ServiceExternalTrafficPolicyTypeLocal))
+var supportedServiceInternalTrafficPolicy = sets.NewString(string(core.ServiceInternalTrafficPolicyCluster), string(core.ServiceExternalTrafficPolicyTypeLocal), string(core.ServiceInternalTrafficPolicyPreferLocal))
 
 var supportedServiceIPFamily = sets.NewString(string(core.IPv4Protocol), string(core.IPv6Protocol))
 var supportedServiceIPFamilyPolicy = sets.NewString(string(core.IPFamilyPolicySingleStack), string(core.IPFamilyPolicyPreferDualStack), string(core.IPFamilyPolicyRequireDualStack))
@@ -4977,7 +4994,8 @@ func needsExternalTrafficPolicy(svc *core.Service) bool {
 
 var validExternalTrafficPolicies = sets.NewString(
 	string(core.ServiceExternalTrafficPolicyTypeCluster),
-	string(core.ServiceExternalTrafficPolicyTypeLocal))
+	string(core.ServiceExternalTrafficPolicyTypeLocal),
+	string(core.ServiceExternalTrafficPolicyTypePreferLocal))
 
 func validateServiceExternalTrafficPolicy(service *core.Service) field.ErrorList {
 	allErrs := field.ErrorList{}
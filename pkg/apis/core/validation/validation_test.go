@@ -8637,6 +8637,25 @@ func TestValidatePodReadinessGates(t *testing.T) {
 				},
 			},
 		},
+		{
+			"readiness gate with default status",
+			[]core.PodReadinessGate{
+				{
+					ConditionType: core.PodConditionType("example.com/condition"),
+					DefaultStatus: core.ConditionTrue,
+				},
+			},
+		},
+		{
+			"readiness gate with expiry",
+			[]core.PodReadinessGate{
+				{
+					ConditionType: core.PodConditionType("example.com/condition"),
+					ExpirySeconds: 300,
+					ExpiryStatus:  core.ConditionFalse,
+				},
+			},
+		},
 	}
 	for _, tc := range successCases {
 		if errs := validateReadinessGates(tc.readinessGates, field.NewPath("field")); len(errs) != 0 {
@@ -8656,6 +8675,43 @@ func TestValidatePodReadinessGates(t *testing.T) {
 				},
 			},
 		},
+		{
+			"invalid default status",
+			[]core.PodReadinessGate{
+				{
+					ConditionType: core.PodConditionType("example.com/condition"),
+					DefaultStatus: core.ConditionStatus("Maybe"),
+				},
+			},
+		},
+		{
+			"negative expiry seconds",
+			[]core.PodReadinessGate{
+				{
+					ConditionType: core.PodConditionType("example.com/condition"),
+					ExpirySeconds: -1,
+				},
+			},
+		},
+		{
+			"expiry status without expiry seconds",
+			[]core.PodReadinessGate{
+				{
+					ConditionType: core.PodConditionType("example.com/condition"),
+					ExpiryStatus:  core.ConditionTrue,
+				},
+			},
+		},
+		{
+			"unknown expiry status",
+			[]core.PodReadinessGate{
+				{
+					ConditionType: core.PodConditionType("example.com/condition"),
+					ExpirySeconds: 300,
+					ExpiryStatus:  core.ConditionUnknown,
+				},
+			},
+		},
 	}
 	for _, tc := range errorCases {
 		if errs := validateReadinessGates(tc.readinessGates, field.NewPath("field")); len(errs) == 0 {
@@ -66,6 +66,13 @@ type ResourceClaimSpec struct {
 	// resource. "WaitForFirstConsumer" is the default.
 	// +optional
 	AllocationMode AllocationMode
+
+	// AdminAccess marks a claim that is meant for administrative tasks
+	// like monitoring. Such a claim can always be reserved for additional
+	// pods, regardless of whether the allocated resource is shareable, and
+	// is not counted against the object count quota for claims.
+	// +optional
+	AdminAccess bool
 }
 
 // AllocationMode describes whether a ResourceClaim gets allocated immediately
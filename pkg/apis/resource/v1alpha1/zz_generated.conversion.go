@@ -473,6 +473,7 @@ func autoConvert_v1alpha1_ResourceClaimSpec_To_resource_ResourceClaimSpec(in *v1
 	out.ResourceClassName = in.ResourceClassName
 	out.ParametersRef = (*resource.ResourceClaimParametersReference)(unsafe.Pointer(in.ParametersRef))
 	out.AllocationMode = resource.AllocationMode(in.AllocationMode)
+	out.AdminAccess = in.AdminAccess
 	return nil
 }
 
@@ -485,6 +486,7 @@ func autoConvert_resource_ResourceClaimSpec_To_v1alpha1_ResourceClaimSpec(in *re
 	out.ResourceClassName = in.ResourceClassName
 	out.ParametersRef = (*v1alpha1.ResourceClaimParametersReference)(unsafe.Pointer(in.ParametersRef))
 	out.AllocationMode = v1alpha1.AllocationMode(in.AllocationMode)
+	out.AdminAccess = in.AdminAccess
 	return nil
 }
 
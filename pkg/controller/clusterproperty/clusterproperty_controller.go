@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterproperty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/component-helpers/multicluster/clusterproperty"
+	"k8s.io/klog/v2"
+)
+
+// Controller manages the well-known ClusterProperty objects of the About API
+// (cluster.clusterset.k8s.io). It ensures the "cluster.id" property exists,
+// generating a stable identifier on first sync, and verifies that operator
+// managed properties such as "clusterset" carry valid values. It never
+// rewrites an existing "cluster.id" value because cluster identity must stay
+// stable for the lifetime of the cluster's clusterset membership.
+type Controller struct {
+	client dynamic.Interface
+
+	// resyncPeriod is the interval between reconciliations of the well-known
+	// properties.
+	resyncPeriod time.Duration
+}
+
+// NewClusterPropertyController creates an instance of Controller.
+func NewClusterPropertyController(client dynamic.Interface, resyncPeriod time.Duration) *Controller {
+	return &Controller{
+		client:       client,
+		resyncPeriod: resyncPeriod,
+	}
+}
+
+// Run starts the controller and blocks until the context is cancelled.
+func (c *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	klog.Infof("Starting cluster property controller")
+	defer klog.Infof("Shutting down cluster property controller")
+
+	wait.UntilWithContext(ctx, c.sync, c.resyncPeriod)
+}
+
+func (c *Controller) sync(ctx context.Context) {
+	if err := c.ensureClusterID(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to ensure the %q cluster property: %v", clusterproperty.ClusterIDPropertyName, err))
+	}
+	if err := c.verifyClusterSet(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to verify the %q cluster property: %v", clusterproperty.ClusterSetPropertyName, err))
+	}
+}
+
+// ensureClusterID creates the "cluster.id" property with a generated UUID if
+// it does not exist, and warns if an existing value is invalid.
+func (c *Controller) ensureClusterID(ctx context.Context) error {
+	obj, err := c.client.Resource(clusterproperty.ClusterPropertyResource).Get(ctx, clusterproperty.ClusterIDPropertyName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return c.createProperty(ctx, clusterproperty.ClusterIDPropertyName, string(uuid.NewUUID()))
+	}
+	if err != nil {
+		return err
+	}
+
+	value := propertyValue(obj)
+	if errs := clusterproperty.ValidateClusterPropertyValue(clusterproperty.ClusterIDPropertyName, value); len(errs) > 0 {
+		// The value is left untouched: rewriting it would change the
+		// cluster's identity underneath multi-cluster consumers.
+		klog.Warningf("ClusterProperty %q has invalid value %q: %s", clusterproperty.ClusterIDPropertyName, value, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// verifyClusterSet checks the operator managed "clusterset" property, if any.
+// Membership is a deliberate administrative decision, so the controller only
+// validates the value and never creates or updates it.
+func (c *Controller) verifyClusterSet(ctx context.Context) error {
+	obj, err := c.client.Resource(clusterproperty.ClusterPropertyResource).Get(ctx, clusterproperty.ClusterSetPropertyName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	value := propertyValue(obj)
+	if errs := clusterproperty.ValidateClusterPropertyValue(clusterproperty.ClusterSetPropertyName, value); len(errs) > 0 {
+		klog.Warningf("ClusterProperty %q has invalid value %q: %s", clusterproperty.ClusterSetPropertyName, value, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *Controller) createProperty(ctx context.Context, name, value string) error {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": clusterproperty.GroupName + "/" + clusterproperty.Version,
+			"kind":       "ClusterProperty",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"value": value,
+			},
+		},
+	}
+	_, err := c.client.Resource(clusterproperty.ClusterPropertyResource).Create(ctx, obj, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// another controller manager instance won the race
+		return nil
+	}
+	if err == nil {
+		klog.Infof("Created ClusterProperty %q with value %q", name, value)
+	}
+	return err
+}
+
+func propertyValue(obj *unstructured.Unstructured) string {
+	value, _, _ := unstructured.NestedString(obj.Object, "spec", "value")
+	return value
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterproperty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/component-helpers/multicluster/clusterproperty"
+)
+
+func newFakeClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	listKinds := map[schema.GroupVersionResource]string{
+		clusterproperty.ClusterPropertyResource: "ClusterPropertyList",
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, objects...)
+}
+
+func newProperty(name, value string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": clusterproperty.GroupName + "/" + clusterproperty.Version,
+			"kind":       "ClusterProperty",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"value": value,
+			},
+		},
+	}
+}
+
+func TestSyncCreatesClusterID(t *testing.T) {
+	client := newFakeClient()
+	c := NewClusterPropertyController(client, time.Minute)
+
+	c.sync(context.TODO())
+
+	obj, err := client.Resource(clusterproperty.ClusterPropertyResource).Get(context.TODO(), clusterproperty.ClusterIDPropertyName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the %q property to be created: %v", clusterproperty.ClusterIDPropertyName, err)
+	}
+	value := propertyValue(obj)
+	if errs := clusterproperty.ValidateClusterPropertyValue(clusterproperty.ClusterIDPropertyName, value); len(errs) > 0 {
+		t.Errorf("generated cluster id %q is invalid: %v", value, errs)
+	}
+}
+
+func TestSyncPreservesExistingClusterID(t *testing.T) {
+	client := newFakeClient(newProperty(clusterproperty.ClusterIDPropertyName, "existing-cluster-id"))
+	c := NewClusterPropertyController(client, time.Minute)
+
+	c.sync(context.TODO())
+
+	obj, err := client.Resource(clusterproperty.ClusterPropertyResource).Get(context.TODO(), clusterproperty.ClusterIDPropertyName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value := propertyValue(obj); value != "existing-cluster-id" {
+		t.Errorf("expected the existing cluster id to be preserved, got %q", value)
+	}
+}
+
+func TestSyncLeavesClusterSetToOperators(t *testing.T) {
+	client := newFakeClient()
+	c := NewClusterPropertyController(client, time.Minute)
+
+	c.sync(context.TODO())
+
+	_, err := client.Resource(clusterproperty.ClusterPropertyResource).Get(context.TODO(), clusterproperty.ClusterSetPropertyName, metav1.GetOptions{})
+	if err == nil {
+		t.Errorf("expected the %q property to be left unmanaged", clusterproperty.ClusterSetPropertyName)
+	}
+}
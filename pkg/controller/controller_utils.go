@@ -536,6 +536,14 @@ func (r RealPodControl) PatchPod(ctx context.Context, namespace, name string, da
 	return err
 }
 
+// PodCreationBatchID returns the value a controller stamps into the
+// v1.PodCreationBatchAnnotationKey annotation for one burst of pod creations.
+// The identifier is prefixed with a zero-padded timestamp so that comparing
+// identifiers lexically orders batches by their creation time.
+func PodCreationBatchID(controllerUID types.UID) string {
+	return fmt.Sprintf("%020d-%s", time.Now().UnixNano(), controllerUID)
+}
+
 func GetPodFromTemplate(template *v1.PodTemplateSpec, parentObject runtime.Object, controllerRef *metav1.OwnerReference) (*v1.Pod, error) {
 	desiredLabels := getPodsLabelSet(template)
 	desiredFinalizers := getPodsFinalizers(template)
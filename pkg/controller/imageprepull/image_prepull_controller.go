@@ -0,0 +1,308 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imageprepull implements a controller that propagates
+// ImagePrepullSet objects (node.k8s.io/v1alpha2) to kubelets and aggregates
+// their progress. For every node it resolves which sets select the node,
+// orders the requested images by the sets' priority classes, and writes the
+// resulting list into the node's prepull annotation; the kubelet pulls and
+// pins the images and reports per-image state back through its own
+// annotation, which the controller folds into each set's per-node status.
+package imageprepull
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	nodev1alpha2 "k8s.io/api/node/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ImagePrepullSetResource is the resource the controller reconciles.
+var ImagePrepullSetResource = schema.GroupVersionResource{Group: nodev1alpha2.GroupName, Version: "v1alpha2", Resource: "imageprepullsets"}
+
+// Controller reconciles ImagePrepullSets against nodes.
+type Controller struct {
+	kubeClient    clientset.Interface
+	dynamicClient dynamic.Interface
+
+	// resyncPeriod is the interval between full reconciliations.
+	resyncPeriod time.Duration
+}
+
+// NewImagePrepullController creates an instance of Controller.
+func NewImagePrepullController(kubeClient clientset.Interface, dynamicClient dynamic.Interface, resyncPeriod time.Duration) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		resyncPeriod:  resyncPeriod,
+	}
+}
+
+// Run starts the controller and blocks until the context is cancelled.
+func (c *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	klog.Infof("Starting image prepull controller")
+	defer klog.Infof("Shutting down image prepull controller")
+
+	wait.UntilWithContext(ctx, c.sync, c.resyncPeriod)
+}
+
+func (c *Controller) sync(ctx context.Context) {
+	if err := c.syncOnce(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to sync image prepull sets: %v", err))
+	}
+}
+
+func (c *Controller) syncOnce(ctx context.Context) error {
+	sets, err := c.listPrepullSets(ctx)
+	if err != nil {
+		return err
+	}
+	c.orderByPriorityClass(ctx, sets)
+
+	nodeList, err := c.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing nodes: %v", err)
+	}
+
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if err := c.syncNodeAnnotation(ctx, node, sets); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to sync prepull annotation of node %q: %v", node.Name, err))
+		}
+	}
+	for _, set := range sets {
+		if err := c.syncSetStatus(ctx, set, nodeList.Items); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to sync status of image prepull set %q: %v", set.Name, err))
+		}
+	}
+	return nil
+}
+
+// listPrepullSets fetches all ImagePrepullSets through the dynamic client.
+// The type is alpha and has no generated clientset yet.
+func (c *Controller) listPrepullSets(ctx context.Context) ([]*nodev1alpha2.ImagePrepullSet, error) {
+	list, err := c.dynamicClient.Resource(ImagePrepullSetResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing image prepull sets: %v", err)
+	}
+
+	sets := make([]*nodev1alpha2.ImagePrepullSet, 0, len(list.Items))
+	for i := range list.Items {
+		set := &nodev1alpha2.ImagePrepullSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, set); err != nil {
+			utilruntime.HandleError(fmt.Errorf("malformed image prepull set %q: %v", list.Items[i].GetName(), err))
+			continue
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// orderByPriorityClass sorts the sets so that images of a set referencing a
+// higher PriorityClass come first on every node's prepull list. Sets without
+// a priority class sort last; ties break by name for a stable order.
+func (c *Controller) orderByPriorityClass(ctx context.Context, sets []*nodev1alpha2.ImagePrepullSet) {
+	priorities := map[string]int32{}
+	for _, set := range sets {
+		name := set.Spec.PriorityClassName
+		if name == "" {
+			continue
+		}
+		if _, resolved := priorities[name]; resolved {
+			continue
+		}
+		pc, err := c.kubeClient.SchedulingV1().PriorityClasses().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			// an unresolvable priority class orders the set as if it had none
+			if !apierrors.IsNotFound(err) {
+				utilruntime.HandleError(fmt.Errorf("resolving priority class %q: %v", name, err))
+			}
+			priorities[name] = 0
+			continue
+		}
+		priorities[name] = pc.Value
+	}
+	sort.SliceStable(sets, func(i, j int) bool {
+		pi, pj := priorities[sets[i].Spec.PriorityClassName], priorities[sets[j].Spec.PriorityClassName]
+		if pi != pj {
+			return pi > pj
+		}
+		return sets[i].Name < sets[j].Name
+	})
+}
+
+// imagesForNode returns the images the node's kubelet should prepull, in pull
+// order, deduplicated keeping the highest priority occurrence.
+func imagesForNode(node *v1.Node, sets []*nodev1alpha2.ImagePrepullSet) ([]string, error) {
+	var images []string
+	seen := map[string]bool{}
+	for _, set := range sets {
+		matches, err := selectsNode(set, node)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+		for _, image := range set.Spec.Images {
+			if seen[image] {
+				continue
+			}
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+func selectsNode(set *nodev1alpha2.ImagePrepullSet, node *v1.Node) (bool, error) {
+	if set.Spec.NodeSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.NodeSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid node selector of image prepull set %q: %v", set.Name, err)
+	}
+	return selector.Matches(labels.Set(node.Labels)), nil
+}
+
+// syncNodeAnnotation brings the node's prepull annotation in line with the
+// sets that select it, removing the annotation when no set does.
+func (c *Controller) syncNodeAnnotation(ctx context.Context, node *v1.Node, sets []*nodev1alpha2.ImagePrepullSet) error {
+	images, err := imagesForNode(node, sets)
+	if err != nil {
+		return err
+	}
+
+	var desired string
+	if len(images) > 0 {
+		encoded, err := json.Marshal(images)
+		if err != nil {
+			return err
+		}
+		desired = string(encoded)
+	}
+	if node.Annotations[nodev1alpha2.ImagePrepullImagesAnnotationKey] == desired {
+		return nil
+	}
+
+	oldData, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	node = node.DeepCopy()
+	if desired == "" {
+		delete(node.Annotations, nodev1alpha2.ImagePrepullImagesAnnotationKey)
+	} else {
+		metav1.SetMetaDataAnnotation(&node.ObjectMeta, nodev1alpha2.ImagePrepullImagesAnnotationKey, desired)
+	}
+	newData, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, &v1.Node{})
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+// syncSetStatus folds the kubelets' per-node status annotations into the
+// set's status, one entry per selected node.
+func (c *Controller) syncSetStatus(ctx context.Context, set *nodev1alpha2.ImagePrepullSet, nodes []v1.Node) error {
+	var statuses []nodev1alpha2.ImagePrepullNodeStatus
+	for i := range nodes {
+		node := &nodes[i]
+		matches, err := selectsNode(set, node)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			continue
+		}
+		statuses = append(statuses, nodeStatusForSet(set, node))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].NodeName < statuses[j].NodeName })
+
+	if equality.Semantic.DeepEqual(set.Status.Nodes, statuses) {
+		return nil
+	}
+	updated := set.DeepCopy()
+	updated.Status.Nodes = statuses
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updated)
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamicClient.Resource(ImagePrepullSetResource).Update(ctx, &unstructured.Unstructured{Object: content}, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		// the set changed or went away; the next resync reconciles it
+		return nil
+	}
+	return err
+}
+
+// nodeStatusForSet derives one node's entry of the set's status from the
+// kubelet's status annotation. A node that has not reported yet counts all
+// images as pending.
+func nodeStatusForSet(set *nodev1alpha2.ImagePrepullSet, node *v1.Node) nodev1alpha2.ImagePrepullNodeStatus {
+	status := nodev1alpha2.ImagePrepullNodeStatus{
+		NodeName:      node.Name,
+		PendingImages: int32(len(set.Spec.Images)),
+	}
+	reported := map[string]string{}
+	if encoded := node.Annotations[nodev1alpha2.ImagePrepullStatusAnnotationKey]; encoded != "" {
+		if err := json.Unmarshal([]byte(encoded), &reported); err != nil {
+			status.LastError = fmt.Sprintf("malformed status annotation: %v", err)
+			return status
+		}
+	}
+	for _, image := range set.Spec.Images {
+		state := reported[image]
+		switch {
+		case state == nodev1alpha2.ImagePrepullStatePulled:
+			status.PulledImages++
+			status.PendingImages--
+		case strings.HasPrefix(state, nodev1alpha2.ImagePrepullStateFailedPrefix):
+			status.LastError = strings.TrimPrefix(state, nodev1alpha2.ImagePrepullStateFailedPrefix)
+		}
+	}
+	return status
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageprepull
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	nodev1alpha2 "k8s.io/api/node/v1alpha2"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newController(t *testing.T, kubeObjects []runtime.Object, sets ...*nodev1alpha2.ImagePrepullSet) (*Controller, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	dynamicObjects := make([]runtime.Object, 0, len(sets))
+	for _, set := range sets {
+		set = set.DeepCopy()
+		set.TypeMeta = metav1.TypeMeta{APIVersion: nodev1alpha2.SchemeGroupVersion.String(), Kind: "ImagePrepullSet"}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(set)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		dynamicObjects = append(dynamicObjects, &unstructured.Unstructured{Object: content})
+	}
+	kubeClient := fake.NewSimpleClientset(kubeObjects...)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		ImagePrepullSetResource: "ImagePrepullSetList",
+	}, dynamicObjects...)
+	return NewImagePrepullController(kubeClient, dynamicClient, time.Minute), kubeClient, dynamicClient
+}
+
+func nodeWithLabels(name string, labels map[string]string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func nodeImages(t *testing.T, kubeClient *fake.Clientset, nodeName string) []string {
+	t.Helper()
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, ok := node.Annotations[nodev1alpha2.ImagePrepullImagesAnnotationKey]
+	if !ok {
+		return nil
+	}
+	var images []string
+	if err := json.Unmarshal([]byte(encoded), &images); err != nil {
+		t.Fatalf("malformed prepull annotation %q: %v", encoded, err)
+	}
+	return images
+}
+
+func TestSyncWritesPrepullAnnotationForSelectedNodes(t *testing.T) {
+	c, kubeClient, _ := newController(t,
+		[]runtime.Object{
+			nodeWithLabels("gpu-node", map[string]string{"accelerator": "gpu"}),
+			nodeWithLabels("plain-node", nil),
+		},
+		&nodev1alpha2.ImagePrepullSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-images"},
+			Spec: nodev1alpha2.ImagePrepullSetSpec{
+				Images:       []string{"registry.example.com/cuda:12"},
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"accelerator": "gpu"}},
+			},
+		},
+	)
+
+	c.sync(context.TODO())
+
+	if images := nodeImages(t, kubeClient, "gpu-node"); len(images) != 1 || images[0] != "registry.example.com/cuda:12" {
+		t.Errorf("expected the selected node to be annotated with the set's images, got %v", images)
+	}
+	if images := nodeImages(t, kubeClient, "plain-node"); images != nil {
+		t.Errorf("expected the unselected node to carry no prepull annotation, got %v", images)
+	}
+}
+
+func TestSyncOrdersImagesByPriorityClass(t *testing.T) {
+	c, kubeClient, _ := newController(t,
+		[]runtime.Object{
+			nodeWithLabels("node", nil),
+			&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "critical"}, Value: 1000},
+		},
+		&nodev1alpha2.ImagePrepullSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-low"},
+			Spec:       nodev1alpha2.ImagePrepullSetSpec{Images: []string{"batch:v1"}},
+		},
+		&nodev1alpha2.ImagePrepullSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "z-high"},
+			Spec:       nodev1alpha2.ImagePrepullSetSpec{Images: []string{"frontend:v2"}, PriorityClassName: "critical"},
+		},
+	)
+
+	c.sync(context.TODO())
+
+	images := nodeImages(t, kubeClient, "node")
+	if len(images) != 2 || images[0] != "frontend:v2" || images[1] != "batch:v1" {
+		t.Errorf("expected the higher priority set's images first, got %v", images)
+	}
+}
+
+func TestSyncRemovesStaleAnnotation(t *testing.T) {
+	node := nodeWithLabels("node", nil)
+	node.Annotations = map[string]string{nodev1alpha2.ImagePrepullImagesAnnotationKey: `["old:v1"]`}
+	c, kubeClient, _ := newController(t, []runtime.Object{node})
+
+	c.sync(context.TODO())
+
+	got, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "node", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, ok := got.Annotations[nodev1alpha2.ImagePrepullImagesAnnotationKey]; ok {
+		t.Errorf("expected the prepull annotation to be removed when no set selects the node, got %q", value)
+	}
+}
+
+func TestSyncAggregatesNodeStatus(t *testing.T) {
+	reported := nodeWithLabels("reported", nil)
+	reported.Annotations = map[string]string{
+		nodev1alpha2.ImagePrepullStatusAnnotationKey: `{"app:v1":"pulled","app:v2":"failed: pull access denied"}`,
+	}
+	c, _, dynamicClient := newController(t,
+		[]runtime.Object{reported, nodeWithLabels("silent", nil)},
+		&nodev1alpha2.ImagePrepullSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps"},
+			Spec:       nodev1alpha2.ImagePrepullSetSpec{Images: []string{"app:v1", "app:v2"}},
+		},
+	)
+
+	c.sync(context.TODO())
+
+	obj, err := dynamicClient.Resource(ImagePrepullSetResource).Get(context.TODO(), "apps", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	set := &nodev1alpha2.ImagePrepullSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, set); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []nodev1alpha2.ImagePrepullNodeStatus{
+		{NodeName: "reported", PulledImages: 1, PendingImages: 1, LastError: "pull access denied"},
+		{NodeName: "silent", PendingImages: 2},
+	}
+	if len(set.Status.Nodes) != len(expected) {
+		t.Fatalf("expected %d node statuses, got %+v", len(expected), set.Status.Nodes)
+	}
+	for i := range expected {
+		if set.Status.Nodes[i] != expected[i] {
+			t.Errorf("node status %d: expected %+v, got %+v", i, expected[i], set.Status.Nodes[i])
+		}
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package janitor cleans up objects that outlive their purpose but escape the
+// ordinary garbage collector: ControllerRevisions whose workload is gone,
+// EndpointSlices of deleted Services, and Leases that no component renews
+// anymore. Each type has its own policy, and a policy can run dry so that
+// operators see what would be deleted before enabling deletion.
+package janitor
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	coordinationinformers "k8s.io/client-go/informers/coordination/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	coordinationlisters "k8s.io/client-go/listers/coordination/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// sweepPeriod defines the frequency of the main janitor loop.
+const sweepPeriod = 1 * time.Minute
+
+// Policy controls when the janitor may delete objects of one type.
+type Policy struct {
+	// MinAge is how long an object must have been eligible before it is
+	// deleted: since creation for ControllerRevisions and EndpointSlices,
+	// and past the renew deadline for Leases. It absorbs informer lag and
+	// in-flight owner creation.
+	MinAge time.Duration
+	// DryRun reports objects that would be deleted without deleting them,
+	// so a policy can be observed before it is enforced.
+	DryRun bool
+}
+
+// Policies groups the per-type policies of the janitor.
+type Policies struct {
+	ControllerRevisions Policy
+	EndpointSlices      Policy
+	Leases              Policy
+}
+
+// DefaultPolicies keeps every type on a one hour grace period with deletion
+// enabled.
+func DefaultPolicies() Policies {
+	return Policies{
+		ControllerRevisions: Policy{MinAge: 1 * time.Hour},
+		EndpointSlices:      Policy{MinAge: 1 * time.Hour},
+		Leases:              Policy{MinAge: 1 * time.Hour},
+	}
+}
+
+// Janitor deletes orphaned ControllerRevisions, stale EndpointSlices and
+// expired Leases according to per-type policies.
+type Janitor struct {
+	kubeClient clientset.Interface
+	policies   Policies
+
+	revisionLister    appslisters.ControllerRevisionLister
+	daemonSetLister   appslisters.DaemonSetLister
+	statefulSetLister appslisters.StatefulSetLister
+	sliceLister       discoverylisters.EndpointSliceLister
+	serviceLister     corelisters.ServiceLister
+	leaseLister       coordinationlisters.LeaseLister
+
+	listersSynced []cache.InformerSynced
+
+	clock clock.Clock
+}
+
+// NewJanitor returns a janitor that sweeps the cluster once per sweepPeriod.
+func NewJanitor(
+	kubeClient clientset.Interface,
+	revisionInformer appsinformers.ControllerRevisionInformer,
+	daemonSetInformer appsinformers.DaemonSetInformer,
+	statefulSetInformer appsinformers.StatefulSetInformer,
+	sliceInformer discoveryinformers.EndpointSliceInformer,
+	serviceInformer coreinformers.ServiceInformer,
+	leaseInformer coordinationinformers.LeaseInformer,
+	policies Policies,
+) *Janitor {
+	return newJanitor(kubeClient, revisionInformer, daemonSetInformer, statefulSetInformer, sliceInformer, serviceInformer, leaseInformer, policies, clock.RealClock{})
+}
+
+func newJanitor(
+	kubeClient clientset.Interface,
+	revisionInformer appsinformers.ControllerRevisionInformer,
+	daemonSetInformer appsinformers.DaemonSetInformer,
+	statefulSetInformer appsinformers.StatefulSetInformer,
+	sliceInformer discoveryinformers.EndpointSliceInformer,
+	serviceInformer coreinformers.ServiceInformer,
+	leaseInformer coordinationinformers.LeaseInformer,
+	policies Policies,
+	clock clock.Clock,
+) *Janitor {
+	return &Janitor{
+		kubeClient:        kubeClient,
+		policies:          policies,
+		revisionLister:    revisionInformer.Lister(),
+		daemonSetLister:   daemonSetInformer.Lister(),
+		statefulSetLister: statefulSetInformer.Lister(),
+		sliceLister:       sliceInformer.Lister(),
+		serviceLister:     serviceInformer.Lister(),
+		leaseLister:       leaseInformer.Lister(),
+		listersSynced: []cache.InformerSynced{
+			revisionInformer.Informer().HasSynced,
+			daemonSetInformer.Informer().HasSynced,
+			statefulSetInformer.Informer().HasSynced,
+			sliceInformer.Informer().HasSynced,
+			serviceInformer.Informer().HasSynced,
+			leaseInformer.Informer().HasSynced,
+		},
+		clock: clock,
+	}
+}
+
+// Run starts the janitor loop and blocks until ctx is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	klog.InfoS("Starting janitor controller")
+	defer klog.InfoS("Shutting down janitor controller")
+
+	if !cache.WaitForNamedCacheSync("janitor", ctx.Done(), j.listersSynced...) {
+		return
+	}
+
+	wait.UntilWithContext(ctx, j.sweep, sweepPeriod)
+}
+
+func (j *Janitor) sweep(ctx context.Context) {
+	j.sweepControllerRevisions(ctx)
+	j.sweepEndpointSlices(ctx)
+	j.sweepLeases(ctx)
+}
+
+func (j *Janitor) sweepControllerRevisions(ctx context.Context) {
+	revisions, err := j.revisionLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Error while listing ControllerRevisions")
+		return
+	}
+	for _, revision := range revisions {
+		if j.clock.Since(revision.CreationTimestamp.Time) < j.policies.ControllerRevisions.MinAge {
+			continue
+		}
+		reason, orphaned := j.revisionOrphaned(revision)
+		if !orphaned {
+			continue
+		}
+		if j.policies.ControllerRevisions.DryRun {
+			klog.InfoS("Janitor would delete ControllerRevision (dry run)", "controllerRevision", klog.KObj(revision), "reason", reason)
+			continue
+		}
+		klog.V(2).InfoS("Janitor deleting ControllerRevision", "controllerRevision", klog.KObj(revision), "reason", reason)
+		if err := j.kubeClient.AppsV1().ControllerRevisions(revision.Namespace).Delete(ctx, revision.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(revision.UID))}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Error while deleting ControllerRevision", "controllerRevision", klog.KObj(revision))
+		}
+	}
+}
+
+// revisionOrphaned reports whether a ControllerRevision's named workload
+// owner is confirmed gone. Revisions without a controller owner reference are
+// left alone: the DaemonSet and StatefulSet history controllers deliberately
+// adopt ownerless revisions, e.g. after a cascade-orphaning delete and
+// recreate of the workload. Owner kinds the janitor does not know about are
+// also left alone.
+func (j *Janitor) revisionOrphaned(revision *appsv1.ControllerRevision) (string, bool) {
+	controllerRef := metav1.GetControllerOf(revision)
+	if controllerRef == nil {
+		return "", false
+	}
+	var err error
+	switch controllerRef.Kind {
+	case "DaemonSet":
+		_, err = j.daemonSetLister.DaemonSets(revision.Namespace).Get(controllerRef.Name)
+	case "StatefulSet":
+		_, err = j.statefulSetLister.StatefulSets(revision.Namespace).Get(controllerRef.Name)
+	default:
+		return "", false
+	}
+	if apierrors.IsNotFound(err) {
+		return "owning " + controllerRef.Kind + " no longer exists", true
+	}
+	return "", false
+}
+
+func (j *Janitor) sweepEndpointSlices(ctx context.Context) {
+	slices, err := j.sliceLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Error while listing EndpointSlices")
+		return
+	}
+	for _, slice := range slices {
+		if j.clock.Since(slice.CreationTimestamp.Time) < j.policies.EndpointSlices.MinAge {
+			continue
+		}
+		if !j.sliceStale(slice) {
+			continue
+		}
+		if j.policies.EndpointSlices.DryRun {
+			klog.InfoS("Janitor would delete EndpointSlice (dry run)", "endpointSlice", klog.KObj(slice), "service", slice.Labels[discoveryv1.LabelServiceName])
+			continue
+		}
+		klog.V(2).InfoS("Janitor deleting EndpointSlice", "endpointSlice", klog.KObj(slice), "service", slice.Labels[discoveryv1.LabelServiceName])
+		if err := j.kubeClient.DiscoveryV1().EndpointSlices(slice.Namespace).Delete(ctx, slice.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(slice.UID))}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Error while deleting EndpointSlice", "endpointSlice", klog.KObj(slice))
+		}
+	}
+}
+
+// sliceStale reports whether an EndpointSlice claims to belong to a Service
+// that no longer exists. Slices without a service-name label are not managed
+// per Service and are left alone.
+func (j *Janitor) sliceStale(slice *discoveryv1.EndpointSlice) bool {
+	serviceName, ok := slice.Labels[discoveryv1.LabelServiceName]
+	if !ok || serviceName == "" {
+		return false
+	}
+	_, err := j.serviceLister.Services(slice.Namespace).Get(serviceName)
+	return apierrors.IsNotFound(err)
+}
+
+func (j *Janitor) sweepLeases(ctx context.Context) {
+	leases, err := j.leaseLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Error while listing Leases")
+		return
+	}
+	for _, lease := range leases {
+		if !j.leaseExpired(lease) {
+			continue
+		}
+		if j.policies.Leases.DryRun {
+			klog.InfoS("Janitor would delete Lease (dry run)", "lease", klog.KObj(lease), "holder", holderIdentity(lease))
+			continue
+		}
+		klog.V(2).InfoS("Janitor deleting Lease", "lease", klog.KObj(lease), "holder", holderIdentity(lease))
+		if err := j.kubeClient.CoordinationV1().Leases(lease.Namespace).Delete(ctx, lease.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(lease.UID))}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Error while deleting Lease", "lease", klog.KObj(lease))
+		}
+	}
+}
+
+// leaseExpired reports whether a Lease has gone unrenewed for at least MinAge
+// beyond its own renew deadline, meaning whichever component held it is long
+// gone. An actively contended lease is renewed or re-acquired well within
+// that window.
+func (j *Janitor) leaseExpired(lease *coordinationv1.Lease) bool {
+	lastActive := lease.CreationTimestamp.Time
+	if lease.Spec.AcquireTime != nil {
+		lastActive = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil {
+		lastActive = lease.Spec.RenewTime.Time
+	}
+	deadline := lastActive
+	if lease.Spec.LeaseDurationSeconds != nil {
+		deadline = deadline.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	}
+	return j.clock.Since(deadline) >= j.policies.Leases.MinAge
+}
+
+func holderIdentity(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
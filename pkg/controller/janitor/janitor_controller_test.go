@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package janitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+)
+
+var testNow = time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestJanitor(t *testing.T, policies Policies, objects ...runtime.Object) (*Janitor, *fake.Clientset) {
+	t.Helper()
+	client := fake.NewSimpleClientset(objects...)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	j := newJanitor(
+		client,
+		factory.Apps().V1().ControllerRevisions(),
+		factory.Apps().V1().DaemonSets(),
+		factory.Apps().V1().StatefulSets(),
+		factory.Discovery().V1().EndpointSlices(),
+		factory.Core().V1().Services(),
+		factory.Coordination().V1().Leases(),
+		policies,
+		testingclock.NewFakeClock(testNow),
+	)
+	for _, obj := range objects {
+		var err error
+		switch typed := obj.(type) {
+		case *appsv1.ControllerRevision:
+			err = factory.Apps().V1().ControllerRevisions().Informer().GetIndexer().Add(typed)
+		case *appsv1.DaemonSet:
+			err = factory.Apps().V1().DaemonSets().Informer().GetIndexer().Add(typed)
+		case *appsv1.StatefulSet:
+			err = factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(typed)
+		case *discoveryv1.EndpointSlice:
+			err = factory.Discovery().V1().EndpointSlices().Informer().GetIndexer().Add(typed)
+		case *v1.Service:
+			err = factory.Core().V1().Services().Informer().GetIndexer().Add(typed)
+		case *coordinationv1.Lease:
+			err = factory.Coordination().V1().Leases().Informer().GetIndexer().Add(typed)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return j, client
+}
+
+func deletedNames(t *testing.T, client *fake.Clientset, resource string) sets.String {
+	t.Helper()
+	names := sets.NewString()
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "delete" || action.GetResource().Resource != resource {
+			continue
+		}
+		names.Insert(action.(interface{ GetName() string }).GetName())
+	}
+	return names
+}
+
+func testRevision(name string, age time.Duration, owner *metav1.OwnerReference) *appsv1.ControllerRevision {
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              name,
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(testNow.Add(-age)),
+		},
+	}
+	if owner != nil {
+		revision.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return revision
+}
+
+func controllerOf(kind, name string) *metav1.OwnerReference {
+	return &metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, UID: types.UID("uid-" + name), Controller: pointer.Bool(true)}
+}
+
+func TestSweepControllerRevisions(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ds"}}
+	j, client := newTestJanitor(t, DefaultPolicies(),
+		daemonSet,
+		// ownerless revisions stay: the history controllers adopt them
+		testRevision("ownerless", 2*time.Hour, nil),
+		testRevision("owned", 2*time.Hour, controllerOf("DaemonSet", "ds")),
+		testRevision("owner-gone", 2*time.Hour, controllerOf("StatefulSet", "deleted")),
+		testRevision("young-owner-gone", 30*time.Minute, controllerOf("StatefulSet", "deleted")),
+		testRevision("unknown-owner", 2*time.Hour, controllerOf("CloneSet", "cs")),
+	)
+
+	j.sweep(context.TODO())
+
+	want := sets.NewString("owner-gone")
+	if got := deletedNames(t, client, "controllerrevisions"); !got.Equal(want) {
+		t.Errorf("expected deletion of %v, got %v", want.List(), got.List())
+	}
+}
+
+func testSlice(name, serviceName string, age time.Duration) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              name,
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(testNow.Add(-age)),
+		},
+	}
+	if serviceName != "" {
+		slice.Labels = map[string]string{discoveryv1.LabelServiceName: serviceName}
+	}
+	return slice
+}
+
+func TestSweepEndpointSlices(t *testing.T) {
+	j, client := newTestJanitor(t, DefaultPolicies(),
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "live"}},
+		testSlice("live-abc", "live", 2*time.Hour),
+		testSlice("stale-abc", "deleted", 2*time.Hour),
+		testSlice("young-abc", "deleted", 30*time.Minute),
+		testSlice("unmanaged", "", 2*time.Hour),
+	)
+
+	j.sweep(context.TODO())
+
+	want := sets.NewString("stale-abc")
+	if got := deletedNames(t, client, "endpointslices"); !got.Equal(want) {
+		t.Errorf("expected deletion of %v, got %v", want.List(), got.List())
+	}
+}
+
+func testLease(name string, renewedAgo time.Duration) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID("uid-" + name)},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       pointer.String("holder-of-" + name),
+			LeaseDurationSeconds: pointer.Int32(15),
+			RenewTime:            &metav1.MicroTime{Time: testNow.Add(-renewedAgo)},
+		},
+	}
+}
+
+func TestSweepLeases(t *testing.T) {
+	j, client := newTestJanitor(t, DefaultPolicies(),
+		testLease("active", 5*time.Second),
+		testLease("recently-stale", 30*time.Minute),
+		testLease("expired", 2*time.Hour),
+	)
+
+	j.sweep(context.TODO())
+
+	want := sets.NewString("expired")
+	if got := deletedNames(t, client, "leases"); !got.Equal(want) {
+		t.Errorf("expected deletion of %v, got %v", want.List(), got.List())
+	}
+}
+
+func TestSweepDryRun(t *testing.T) {
+	policies := DefaultPolicies()
+	policies.ControllerRevisions.DryRun = true
+	policies.EndpointSlices.DryRun = true
+	policies.Leases.DryRun = true
+
+	j, client := newTestJanitor(t, policies,
+		testRevision("owner-gone", 2*time.Hour, controllerOf("StatefulSet", "deleted")),
+		testSlice("stale-abc", "deleted", 2*time.Hour),
+		testLease("expired", 2*time.Hour),
+	)
+
+	j.sweep(context.TODO())
+
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete" {
+			t.Errorf("unexpected delete of %s under a dry-run policy", action.GetResource().Resource)
+		}
+	}
+}
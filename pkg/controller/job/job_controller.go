@@ -1475,6 +1475,9 @@ func (jm *Controller) manageJob(ctx context.Context, job *batch.Job, activePods
 		if hasJobTrackingAnnotation(job) {
 			podTemplate.Finalizers = appendJobCompletionFinalizerIfNotFound(podTemplate.Finalizers)
 		}
+		// Stamp every pod of this burst with the same creation batch
+		// identifier so the scheduler can keep them adjacent in its queue.
+		metav1.SetMetaDataAnnotation(&podTemplate.ObjectMeta, v1.PodCreationBatchAnnotationKey, controller.PodCreationBatchID(job.UID))
 
 		// Batch the pod creates. Batch sizes start at SlowStartInitialBatchSize
 		// and double with each successful iteration in a kind of "slow start".
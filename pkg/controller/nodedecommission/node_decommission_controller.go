@@ -0,0 +1,358 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodedecommission implements a controller that safely removes Node
+// objects on request, replacing manual `kubectl delete node`. An operator
+// requests decommissioning by annotating the Node; the controller refuses to
+// proceed while volumes are still attached or daemon-managed critical pods
+// are running, cordons the node, evicts the remaining pods, and only deletes
+// the Node object once it is empty. The cloud controller manager observes the
+// deletion and releases the cloud resources backing the node.
+package nodedecommission
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+	"k8s.io/kubernetes/pkg/controller"
+)
+
+const (
+	// DecommissionRequestedAnnotationKey marks a Node for decommissioning.
+	// Any non-empty value requests the flow; the controller never sets it.
+	DecommissionRequestedAnnotationKey = "node.alpha.kubernetes.io/decommission-requested"
+
+	// DecommissionStatusAnnotationKey records the controller's progress on a
+	// requested decommission so operators can see why it has not finished.
+	DecommissionStatusAnnotationKey = "node.alpha.kubernetes.io/decommission-status"
+
+	statusDraining = "draining"
+
+	// retryInterval is how long the controller waits before re-examining a
+	// node whose decommission is blocked or still draining.
+	retryInterval = 10 * time.Second
+)
+
+// Controller decommissions annotated nodes: it validates that nothing pins
+// the node, drains it, and deletes the Node object.
+type Controller struct {
+	kubeClient clientset.Interface
+
+	nodeLister             corelisters.NodeLister
+	volumeAttachmentLister storagelisters.VolumeAttachmentLister
+
+	// Nodes that need to be examined.
+	queue workqueue.RateLimitingInterface
+
+	// Returns true if all underlying informers are synced.
+	hasSynced func() bool
+}
+
+// NewNodeDecommissionController creates a new node decommission controller.
+func NewNodeDecommissionController(nodeInformer coreinformers.NodeInformer, volumeAttachmentInformer storageinformers.VolumeAttachmentInformer, kubeClient clientset.Interface) *Controller {
+	c := &Controller{
+		kubeClient: kubeClient,
+		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "node_decommission"),
+	}
+
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueNode(obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueNode(newObj)
+		},
+	})
+	// A detached volume can unblock a pending decommission, so map
+	// VolumeAttachment deletions back to their node.
+	volumeAttachmentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueVolumeAttachmentNode(obj)
+		},
+	})
+
+	c.nodeLister = nodeInformer.Lister()
+	c.volumeAttachmentLister = volumeAttachmentInformer.Lister()
+	c.hasSynced = func() bool {
+		return nodeInformer.Informer().HasSynced() && volumeAttachmentInformer.Informer().HasSynced()
+	}
+
+	return c
+}
+
+// Run begins watching and syncing.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting node decommission controller")
+	defer klog.Infof("Shutting down node decommission controller")
+
+	if !cache.WaitForNamedCacheSync("node decommission", ctx.Done(), c.hasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.worker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) enqueueNode(obj interface{}) {
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type: %v", obj))
+		return
+	}
+	if _, requested := node.Annotations[DecommissionRequestedAnnotationKey]; !requested {
+		return
+	}
+	key, err := controller.KeyFunc(node)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %+v", node))
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) enqueueVolumeAttachmentNode(obj interface{}) {
+	va, ok := obj.(*storagev1.VolumeAttachment)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("unexpected object type: %v", obj))
+			return
+		}
+		va, ok = tombstone.Obj.(*storagev1.VolumeAttachment)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("unexpected object type: %v", obj))
+			return
+		}
+	}
+	node, err := c.nodeLister.Get(va.Spec.NodeName)
+	if err != nil {
+		return
+	}
+	c.enqueueNode(node)
+}
+
+func (c *Controller) worker(ctx context.Context) {
+	for c.processItem(ctx) {
+	}
+}
+
+func (c *Controller) processItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done, err := c.syncNode(ctx, key.(string))
+	switch {
+	case err != nil:
+		utilruntime.HandleError(err)
+		c.queue.AddRateLimited(key)
+	case !done:
+		c.queue.Forget(key)
+		c.queue.AddAfter(key, retryInterval)
+	default:
+		c.queue.Forget(key)
+	}
+	return true
+}
+
+// syncNode advances the decommission of one node. It returns true when no
+// further work is pending, i.e. decommissioning was not requested, is
+// blocked, or has completed.
+func (c *Controller) syncNode(ctx context.Context, key string) (bool, error) {
+	node, err := c.nodeLister.Get(key)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if _, requested := node.Annotations[DecommissionRequestedAnnotationKey]; !requested {
+		return true, nil
+	}
+
+	pods, err := c.podsOnNode(ctx, node.Name)
+	if err != nil {
+		return false, err
+	}
+	if reason := c.decommissionBlockedReason(node, pods); reason != "" {
+		klog.V(2).InfoS("Node decommission is blocked", "node", klog.KObj(node), "reason", reason)
+		return true, c.patchStatusAnnotation(ctx, node, "blocked: "+reason)
+	}
+
+	if !node.Spec.Unschedulable {
+		if err := c.cordon(ctx, node); err != nil {
+			return false, err
+		}
+	}
+	if node.Annotations[DecommissionStatusAnnotationKey] != statusDraining {
+		if err := c.patchStatusAnnotation(ctx, node, statusDraining); err != nil {
+			return false, err
+		}
+	}
+
+	remaining := 0
+	for i := range pods {
+		pod := &pods[i]
+		if !needsEviction(pod) {
+			continue
+		}
+		remaining++
+		err := c.kubeClient.CoreV1().Pods(pod.Namespace).EvictV1(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			if apierrors.IsTooManyRequests(err) {
+				// A PodDisruptionBudget is temporarily refusing the
+				// eviction; try again on the next pass.
+				klog.V(2).InfoS("Eviction refused during node decommission", "node", klog.KObj(node), "pod", klog.KObj(pod), "err", err)
+				continue
+			}
+			return false, fmt.Errorf("evicting pod %s/%s from node %q: %v", pod.Namespace, pod.Name, node.Name, err)
+		}
+	}
+	if remaining > 0 {
+		klog.V(2).InfoS("Node decommission is waiting for pods to terminate", "node", klog.KObj(node), "pods", remaining)
+		return false, nil
+	}
+
+	if err := c.kubeClient.CoreV1().Nodes().Delete(ctx, node.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	klog.InfoS("Decommissioned node deleted", "node", klog.KObj(node))
+	return true, nil
+}
+
+func (c *Controller) podsOnNode(ctx context.Context, nodeName string) ([]v1.Pod, error) {
+	podList, err := c.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods on node %q: %v", nodeName, err)
+	}
+	return podList.Items, nil
+}
+
+// decommissionBlockedReason returns a human readable reason when the node
+// must not be decommissioned yet, or an empty string if it is safe to drain.
+func (c *Controller) decommissionBlockedReason(node *v1.Node, pods []v1.Pod) string {
+	attachments, err := c.volumeAttachmentLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Sprintf("cannot list volume attachments: %v", err)
+	}
+	for _, va := range attachments {
+		if va.Spec.NodeName == node.Name {
+			return fmt.Sprintf("volume attachment %q still references the node", va.Name)
+		}
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if isTerminal(pod) {
+			continue
+		}
+		if isDaemonManaged(pod) && isCritical(pod) {
+			return fmt.Sprintf("daemon-managed critical pod %s/%s is still running", pod.Namespace, pod.Name)
+		}
+	}
+	return ""
+}
+
+func (c *Controller) cordon(ctx context.Context, node *v1.Node) error {
+	patch := []byte(`{"spec":{"unschedulable":true}}`)
+	_, err := c.kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("cordoning node %q: %v", node.Name, err)
+	}
+	klog.V(2).InfoS("Cordoned node for decommission", "node", klog.KObj(node))
+	return nil
+}
+
+func (c *Controller) patchStatusAnnotation(ctx context.Context, node *v1.Node, status string) error {
+	if node.Annotations[DecommissionStatusAnnotationKey] == status {
+		return nil
+	}
+	oldData, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	node = node.DeepCopy()
+	metav1.SetMetaDataAnnotation(&node.ObjectMeta, DecommissionStatusAnnotationKey, status)
+	newData, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, &v1.Node{})
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+// needsEviction reports whether the pod still pins the node during a drain.
+// Daemon-managed and mirror pods are left to the daemon set controller and
+// the kubelet respectively, and terminal pods no longer run anything.
+func needsEviction(pod *v1.Pod) bool {
+	if isTerminal(pod) || isDaemonManaged(pod) {
+		return false
+	}
+	if _, isMirror := pod.Annotations[v1.MirrorPodAnnotationKey]; isMirror {
+		return false
+	}
+	return true
+}
+
+func isTerminal(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+func isDaemonManaged(pod *v1.Pod) bool {
+	controllerRef := metav1.GetControllerOf(pod)
+	return controllerRef != nil && controllerRef.Kind == "DaemonSet"
+}
+
+func isCritical(pod *v1.Pod) bool {
+	return pod.Spec.PriorityClassName == scheduling.SystemNodeCritical || pod.Spec.PriorityClassName == scheduling.SystemClusterCritical
+}
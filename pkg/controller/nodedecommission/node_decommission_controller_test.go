@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedecommission
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+	"k8s.io/utils/pointer"
+)
+
+func newTestController(t *testing.T, objects []runtime.Object, attachments ...*storagev1.VolumeAttachment) (*Controller, *fake.Clientset) {
+	client := fake.NewSimpleClientset(objects...)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	c := NewNodeDecommissionController(factory.Core().V1().Nodes(), factory.Storage().V1().VolumeAttachments(), client)
+	for _, obj := range objects {
+		if node, ok := obj.(*v1.Node); ok {
+			if err := factory.Core().V1().Nodes().Informer().GetIndexer().Add(node); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, va := range attachments {
+		if err := factory.Storage().V1().VolumeAttachments().Informer().GetIndexer().Add(va); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The fake clientset does not implement the eviction subresource, so
+	// translate evictions into pod deletions.
+	client.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		createAction := action.(core.CreateAction)
+		if createAction.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := createAction.GetObject().(metav1.Object)
+		err := client.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), eviction.GetNamespace(), eviction.GetName())
+		return true, nil, err
+	})
+	return c, client
+}
+
+func decommissionedNode(name string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{DecommissionRequestedAnnotationKey: "true"},
+		},
+	}
+}
+
+func podOnNode(namespace, name, nodeName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestSyncNodeIgnoresUnannotatedNode(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	c, client := newTestController(t, []runtime.Object{node})
+
+	done, err := c.syncNode(context.TODO(), "node-a")
+	if err != nil || !done {
+		t.Fatalf("syncNode() = %v, %v, want true, nil", done, err)
+	}
+	if _, err := client.CoreV1().Nodes().Get(context.TODO(), "node-a", metav1.GetOptions{}); err != nil {
+		t.Errorf("node without decommission annotation was touched: %v", err)
+	}
+}
+
+func TestSyncNodeBlockedByVolumeAttachment(t *testing.T) {
+	node := decommissionedNode("node-a")
+	va := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "va-1"},
+		Spec:       storagev1.VolumeAttachmentSpec{NodeName: "node-a", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: pointer.String("pv-1")}},
+	}
+	c, client := newTestController(t, []runtime.Object{node}, va)
+
+	done, err := c.syncNode(context.TODO(), "node-a")
+	if err != nil || !done {
+		t.Fatalf("syncNode() = %v, %v, want true, nil", done, err)
+	}
+	got, err := client.CoreV1().Nodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("node was deleted while a volume was still attached: %v", err)
+	}
+	if status := got.Annotations[DecommissionStatusAnnotationKey]; !strings.HasPrefix(status, "blocked:") {
+		t.Errorf("expected blocked status annotation, got %q", status)
+	}
+}
+
+func TestSyncNodeBlockedByDaemonManagedCriticalPod(t *testing.T) {
+	node := decommissionedNode("node-a")
+	pod := podOnNode("kube-system", "critical-daemon", "node-a")
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "critical", Controller: pointer.Bool(true)},
+	}
+	pod.Spec.PriorityClassName = scheduling.SystemNodeCritical
+	c, client := newTestController(t, []runtime.Object{node, pod})
+
+	done, err := c.syncNode(context.TODO(), "node-a")
+	if err != nil || !done {
+		t.Fatalf("syncNode() = %v, %v, want true, nil", done, err)
+	}
+	got, err := client.CoreV1().Nodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("node was deleted while a daemon-managed critical pod was running: %v", err)
+	}
+	if status := got.Annotations[DecommissionStatusAnnotationKey]; !strings.HasPrefix(status, "blocked:") {
+		t.Errorf("expected blocked status annotation, got %q", status)
+	}
+}
+
+func TestSyncNodeDrains(t *testing.T) {
+	node := decommissionedNode("node-a")
+	pod := podOnNode("default", "app", "node-a")
+	c, client := newTestController(t, []runtime.Object{node, pod})
+
+	done, err := c.syncNode(context.TODO(), "node-a")
+	if err != nil {
+		t.Fatalf("syncNode() failed: %v", err)
+	}
+	if done {
+		t.Errorf("syncNode() = done while pods were still draining")
+	}
+	got, err := client.CoreV1().Nodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("node was deleted before the drain finished: %v", err)
+	}
+	if !got.Spec.Unschedulable {
+		t.Errorf("expected node to be cordoned")
+	}
+	if status := got.Annotations[DecommissionStatusAnnotationKey]; status != statusDraining {
+		t.Errorf("expected %q status annotation, got %q", statusDraining, status)
+	}
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "app", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected pod to be evicted, got err %v", err)
+	}
+}
+
+func TestSyncNodeDeletesEmptyNode(t *testing.T) {
+	node := decommissionedNode("node-a")
+	daemonPod := podOnNode("kube-system", "daemon", "node-a")
+	daemonPod.OwnerReferences = []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "daemon", Controller: pointer.Bool(true)},
+	}
+	c, client := newTestController(t, []runtime.Object{node, daemonPod})
+
+	done, err := c.syncNode(context.TODO(), "node-a")
+	if err != nil || !done {
+		t.Fatalf("syncNode() = %v, %v, want true, nil", done, err)
+	}
+	if _, err := client.CoreV1().Nodes().Get(context.TODO(), "node-a", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected node to be deleted, got err %v", err)
+	}
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reload implements an opt-in controller that restarts workloads when
+// the contents of the ConfigMaps and Secrets they reference change. It hashes
+// the referenced contents and stamps the hash on the pod template, so a
+// content change rolls the workload through its ordinary update machinery.
+package reload
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ReloadEnabledAnnotationKey opts a Deployment or StatefulSet in to
+	// automatic restarts when referenced ConfigMap or Secret contents change.
+	// The only recognized value is "true".
+	ReloadEnabledAnnotationKey = "reload.alpha.kubernetes.io/enabled"
+
+	// ReloadConfigMapsAnnotationKey optionally restricts reloads to a
+	// comma-separated list of referenced ConfigMap names. When unset, every
+	// referenced ConfigMap triggers a reload.
+	ReloadConfigMapsAnnotationKey = "reload.alpha.kubernetes.io/configmaps"
+
+	// ReloadSecretsAnnotationKey is the Secret counterpart of
+	// ReloadConfigMapsAnnotationKey.
+	ReloadSecretsAnnotationKey = "reload.alpha.kubernetes.io/secrets"
+
+	// ContentHashAnnotationKey is stamped on the pod template with the hash of
+	// the tracked referenced contents; changing it rolls the workload.
+	ContentHashAnnotationKey = "reload.alpha.kubernetes.io/content-hash"
+)
+
+const (
+	deploymentKind  = "Deployment"
+	statefulSetKind = "StatefulSet"
+)
+
+// Controller watches ConfigMaps and Secrets and rolls opted-in Deployments
+// and StatefulSets when the contents they reference change.
+type Controller struct {
+	kubeClient clientset.Interface
+
+	deploymentLister  appslisters.DeploymentLister
+	statefulSetLister appslisters.StatefulSetLister
+	configMapLister   corelisters.ConfigMapLister
+	secretLister      corelisters.SecretLister
+
+	queue workqueue.RateLimitingInterface
+
+	hasSynced []cache.InformerSynced
+}
+
+// NewReloadController creates a new Controller.
+func NewReloadController(
+	deploymentInformer appsinformers.DeploymentInformer,
+	statefulSetInformer appsinformers.StatefulSetInformer,
+	configMapInformer coreinformers.ConfigMapInformer,
+	secretInformer coreinformers.SecretInformer,
+	kubeClient clientset.Interface,
+) *Controller {
+	c := &Controller{
+		kubeClient:        kubeClient,
+		deploymentLister:  deploymentInformer.Lister(),
+		statefulSetLister: statefulSetInformer.Lister(),
+		configMapLister:   configMapInformer.Lister(),
+		secretLister:      secretInformer.Lister(),
+		queue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "reload"),
+		hasSynced: []cache.InformerSynced{
+			deploymentInformer.Informer().HasSynced,
+			statefulSetInformer.Informer().HasSynced,
+			configMapInformer.Informer().HasSynced,
+			secretInformer.Informer().HasSynced,
+		},
+	}
+
+	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkload(deploymentKind, obj) },
+		UpdateFunc: func(_, newObj interface{}) { c.enqueueWorkload(deploymentKind, newObj) },
+	})
+	statefulSetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkload(statefulSetKind, obj) },
+		UpdateFunc: func(_, newObj interface{}) { c.enqueueWorkload(statefulSetKind, newObj) },
+	})
+	configMapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueConsumers(obj) },
+		UpdateFunc: func(_, newObj interface{}) { c.enqueueConsumers(newObj) },
+	})
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueConsumers(obj) },
+		UpdateFunc: func(_, newObj interface{}) { c.enqueueConsumers(newObj) },
+	})
+
+	return c
+}
+
+// Run starts the controller workers and blocks until ctx is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.InfoS("Starting reload controller")
+	defer klog.InfoS("Shutting down reload controller")
+
+	if !cache.WaitForNamedCacheSync("reload", ctx.Done(), c.hasSynced...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.worker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) worker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncWorkload(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("error syncing %q: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// enqueueWorkload adds an opted-in workload to the queue.
+func (c *Controller) enqueueWorkload(kind string, obj interface{}) {
+	workload, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	if !reloadEnabled(workload) {
+		return
+	}
+	c.queue.Add(workloadKey(kind, workload.GetNamespace(), workload.GetName()))
+}
+
+// enqueueConsumers adds every opted-in workload in the object's namespace that
+// references the changed ConfigMap or Secret.
+func (c *Controller) enqueueConsumers(obj interface{}) {
+	switch source := obj.(type) {
+	case *v1.ConfigMap:
+		c.enqueueReferencingWorkloads(source.Namespace, source.Name, false)
+	case *v1.Secret:
+		c.enqueueReferencingWorkloads(source.Namespace, source.Name, true)
+	}
+}
+
+func (c *Controller) enqueueReferencingWorkloads(namespace, name string, secret bool) {
+	deployments, err := c.deploymentLister.Deployments(namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, deployment := range deployments {
+		if c.tracksReference(&deployment.ObjectMeta, &deployment.Spec.Template.Spec, name, secret) {
+			c.queue.Add(workloadKey(deploymentKind, deployment.Namespace, deployment.Name))
+		}
+	}
+
+	statefulSets, err := c.statefulSetLister.StatefulSets(namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, statefulSet := range statefulSets {
+		if c.tracksReference(&statefulSet.ObjectMeta, &statefulSet.Spec.Template.Spec, name, secret) {
+			c.queue.Add(workloadKey(statefulSetKind, statefulSet.Namespace, statefulSet.Name))
+		}
+	}
+}
+
+func (c *Controller) tracksReference(workload *metav1.ObjectMeta, podSpec *v1.PodSpec, name string, secret bool) bool {
+	if !reloadEnabled(workload) {
+		return false
+	}
+	if secret {
+		return trackedReferences(workload, ReloadSecretsAnnotationKey, referencedSecrets(podSpec)).Has(name)
+	}
+	return trackedReferences(workload, ReloadConfigMapsAnnotationKey, referencedConfigMaps(podSpec)).Has(name)
+}
+
+func (c *Controller) syncWorkload(ctx context.Context, key string) error {
+	kind, namespace, name, err := splitWorkloadKey(key)
+	if err != nil {
+		// a malformed key cannot be retried.
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	switch kind {
+	case deploymentKind:
+		deployment, err := c.deploymentLister.Deployments(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !reloadEnabled(&deployment.ObjectMeta) {
+			return nil
+		}
+		hash := c.contentHash(&deployment.ObjectMeta, &deployment.Spec.Template.Spec, namespace)
+		if deployment.Spec.Template.Annotations[ContentHashAnnotationKey] == hash {
+			return nil
+		}
+		klog.V(2).InfoS("Rolling workload after referenced content change", "kind", kind, "workload", klog.KRef(namespace, name), "contentHash", hash)
+		_, err = c.kubeClient.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, contentHashPatch(hash), metav1.PatchOptions{})
+		return err
+	case statefulSetKind:
+		statefulSet, err := c.statefulSetLister.StatefulSets(namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !reloadEnabled(&statefulSet.ObjectMeta) {
+			return nil
+		}
+		hash := c.contentHash(&statefulSet.ObjectMeta, &statefulSet.Spec.Template.Spec, namespace)
+		if statefulSet.Spec.Template.Annotations[ContentHashAnnotationKey] == hash {
+			return nil
+		}
+		klog.V(2).InfoS("Rolling workload after referenced content change", "kind", kind, "workload", klog.KRef(namespace, name), "contentHash", hash)
+		_, err = c.kubeClient.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, contentHashPatch(hash), metav1.PatchOptions{})
+		return err
+	default:
+		utilruntime.HandleError(fmt.Errorf("unknown workload kind %q", kind))
+		return nil
+	}
+}
+
+// contentHash hashes the contents of every tracked ConfigMap and Secret the
+// pod template references. Missing references are hashed by name only, so a
+// reference appearing later still changes the hash.
+func (c *Controller) contentHash(workload *metav1.ObjectMeta, podSpec *v1.PodSpec, namespace string) string {
+	hasher := sha256.New()
+
+	for _, name := range trackedReferences(workload, ReloadConfigMapsAnnotationKey, referencedConfigMaps(podSpec)).List() {
+		fmt.Fprintf(hasher, "configmap/%s\n", name)
+		configMap, err := c.configMapLister.ConfigMaps(namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		for _, key := range sets.StringKeySet(configMap.Data).List() {
+			fmt.Fprintf(hasher, "%s=%s\n", key, configMap.Data[key])
+		}
+		for _, key := range sets.StringKeySet(configMap.BinaryData).List() {
+			fmt.Fprintf(hasher, "%s=", key)
+			hasher.Write(configMap.BinaryData[key])
+			fmt.Fprintln(hasher)
+		}
+	}
+
+	for _, name := range trackedReferences(workload, ReloadSecretsAnnotationKey, referencedSecrets(podSpec)).List() {
+		fmt.Fprintf(hasher, "secret/%s\n", name)
+		secret, err := c.secretLister.Secrets(namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		for _, key := range sets.StringKeySet(secret.Data).List() {
+			fmt.Fprintf(hasher, "%s=", key)
+			hasher.Write(secret.Data[key])
+			fmt.Fprintln(hasher)
+		}
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
+
+func contentHashPatch(hash string) []byte {
+	return []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, ContentHashAnnotationKey, hash))
+}
+
+func reloadEnabled(workload metav1.Object) bool {
+	return workload.GetAnnotations()[ReloadEnabledAnnotationKey] == "true"
+}
+
+// trackedReferences intersects the references found in the pod template with
+// the optional allow-list annotation, implementing the per-reference policy.
+func trackedReferences(workload *metav1.ObjectMeta, annotationKey string, referenced sets.String) sets.String {
+	allowList, ok := workload.Annotations[annotationKey]
+	if !ok {
+		return referenced
+	}
+	allowed := sets.NewString()
+	for _, name := range strings.Split(allowList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed.Insert(name)
+		}
+	}
+	return referenced.Intersection(allowed)
+}
+
+// referencedConfigMaps returns the names of all ConfigMaps the pod spec
+// references through volumes, envFrom or env value sources.
+func referencedConfigMaps(podSpec *v1.PodSpec) sets.String {
+	names := sets.NewString()
+	for _, volume := range podSpec.Volumes {
+		if volume.ConfigMap != nil {
+			names.Insert(volume.ConfigMap.Name)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					names.Insert(source.ConfigMap.Name)
+				}
+			}
+		}
+	}
+	visitContainers(podSpec, func(container *v1.Container) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				names.Insert(envFrom.ConfigMapRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				names.Insert(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+	})
+	return names
+}
+
+// referencedSecrets is the Secret counterpart of referencedConfigMaps.
+func referencedSecrets(podSpec *v1.PodSpec) sets.String {
+	names := sets.NewString()
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			names.Insert(volume.Secret.SecretName)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					names.Insert(source.Secret.Name)
+				}
+			}
+		}
+	}
+	visitContainers(podSpec, func(container *v1.Container) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names.Insert(envFrom.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names.Insert(env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	})
+	return names
+}
+
+func visitContainers(podSpec *v1.PodSpec, visit func(container *v1.Container)) {
+	for i := range podSpec.InitContainers {
+		visit(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		visit(&podSpec.Containers[i])
+	}
+}
+
+func workloadKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func splitWorkloadKey(key string) (kind, namespace, name string, err error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("unexpected workload key format: %q", key)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
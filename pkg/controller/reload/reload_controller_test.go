@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reload
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestController(t *testing.T, objects ...runtime.Object) (*Controller, *fake.Clientset) {
+	t.Helper()
+	client := fake.NewSimpleClientset(objects...)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	c := NewReloadController(
+		factory.Apps().V1().Deployments(),
+		factory.Apps().V1().StatefulSets(),
+		factory.Core().V1().ConfigMaps(),
+		factory.Core().V1().Secrets(),
+		client,
+	)
+	for _, obj := range objects {
+		var err error
+		switch typed := obj.(type) {
+		case *appsv1.Deployment:
+			err = factory.Apps().V1().Deployments().Informer().GetIndexer().Add(typed)
+		case *appsv1.StatefulSet:
+			err = factory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(typed)
+		case *v1.ConfigMap:
+			err = factory.Core().V1().ConfigMaps().Informer().GetIndexer().Add(typed)
+		case *v1.Secret:
+			err = factory.Core().V1().Secrets().Informer().GetIndexer().Add(typed)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c, client
+}
+
+func testDeployment(annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app", Annotations: annotations},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name: "app",
+						EnvFrom: []v1.EnvFromSource{
+							{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app-config"}}},
+						},
+					}},
+					Volumes: []v1.Volume{{
+						Name:         "creds",
+						VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "app-creds"}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func testConfigMap(name string, data map[string]string) *v1.ConfigMap {
+	return &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}, Data: data}
+}
+
+func getDeployment(t *testing.T, client *fake.Clientset) *appsv1.Deployment {
+	t.Helper()
+	deployment, err := client.AppsV1().Deployments("ns").Get(context.TODO(), "app", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deployment
+}
+
+func TestSyncIgnoresWorkloadWithoutOptIn(t *testing.T) {
+	c, client := newTestController(t,
+		testDeployment(nil),
+		testConfigMap("app-config", map[string]string{"key": "value"}),
+	)
+
+	if err := c.syncWorkload(context.TODO(), "Deployment/ns/app"); err != nil {
+		t.Fatal(err)
+	}
+	if got := getDeployment(t, client); got.Spec.Template.Annotations[ContentHashAnnotationKey] != "" {
+		t.Errorf("expected no content hash on workload without opt-in, got %q", got.Spec.Template.Annotations[ContentHashAnnotationKey])
+	}
+}
+
+func TestSyncStampsContentHash(t *testing.T) {
+	c, client := newTestController(t,
+		testDeployment(map[string]string{ReloadEnabledAnnotationKey: "true"}),
+		testConfigMap("app-config", map[string]string{"key": "value"}),
+	)
+
+	if err := c.syncWorkload(context.TODO(), "Deployment/ns/app"); err != nil {
+		t.Fatal(err)
+	}
+	hash := getDeployment(t, client).Spec.Template.Annotations[ContentHashAnnotationKey]
+	if hash == "" {
+		t.Fatal("expected a content hash to be stamped on the pod template")
+	}
+}
+
+func TestContentHashChangesWithContents(t *testing.T) {
+	deployment := testDeployment(map[string]string{ReloadEnabledAnnotationKey: "true"})
+
+	c, _ := newTestController(t, deployment, testConfigMap("app-config", map[string]string{"key": "value"}))
+	before := c.contentHash(&deployment.ObjectMeta, &deployment.Spec.Template.Spec, "ns")
+
+	c, _ = newTestController(t, deployment, testConfigMap("app-config", map[string]string{"key": "changed"}))
+	after := c.contentHash(&deployment.ObjectMeta, &deployment.Spec.Template.Spec, "ns")
+
+	if before == after {
+		t.Error("expected the content hash to change when ConfigMap contents change")
+	}
+}
+
+func TestPerReferencePolicyExcludesUntrackedReferences(t *testing.T) {
+	// Only app-creds is tracked, so app-config changes must not affect the hash.
+	deployment := testDeployment(map[string]string{
+		ReloadEnabledAnnotationKey:    "true",
+		ReloadConfigMapsAnnotationKey: "",
+	})
+
+	c, _ := newTestController(t, deployment, testConfigMap("app-config", map[string]string{"key": "value"}))
+	before := c.contentHash(&deployment.ObjectMeta, &deployment.Spec.Template.Spec, "ns")
+
+	c, _ = newTestController(t, deployment, testConfigMap("app-config", map[string]string{"key": "changed"}))
+	after := c.contentHash(&deployment.ObjectMeta, &deployment.Spec.Template.Spec, "ns")
+
+	if before != after {
+		t.Error("expected untracked ConfigMap changes to leave the content hash unchanged")
+	}
+}
+
+func TestEnqueueConsumers(t *testing.T) {
+	c, _ := newTestController(t,
+		testDeployment(map[string]string{ReloadEnabledAnnotationKey: "true"}),
+		testConfigMap("app-config", map[string]string{"key": "value"}),
+	)
+
+	c.enqueueConsumers(testConfigMap("app-config", map[string]string{"key": "changed"}))
+	if c.queue.Len() != 1 {
+		t.Fatalf("expected the consuming deployment to be enqueued, queue length %d", c.queue.Len())
+	}
+	key, _ := c.queue.Get()
+	if key.(string) != "Deployment/ns/app" {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	c.enqueueConsumers(testConfigMap("unrelated", nil))
+	if c.queue.Len() != 0 {
+		t.Errorf("expected no enqueues for an unreferenced ConfigMap, queue length %d", c.queue.Len())
+	}
+}
+
+func TestReferencedNames(t *testing.T) {
+	podSpec := &testDeployment(nil).Spec.Template.Spec
+	podSpec.InitContainers = []v1.Container{{
+		Name: "init",
+		Env: []v1.EnvVar{{
+			Name: "TOKEN",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "init-token"}, Key: "token"},
+			},
+		}},
+	}}
+
+	if got := referencedConfigMaps(podSpec).List(); len(got) != 1 || got[0] != "app-config" {
+		t.Errorf("referencedConfigMaps() = %v, want [app-config]", got)
+	}
+	wantSecrets := []string{"app-creds", "init-token"}
+	gotSecrets := referencedSecrets(podSpec).List()
+	if len(gotSecrets) != 2 || gotSecrets[0] != wantSecrets[0] || gotSecrets[1] != wantSecrets[1] {
+		t.Errorf("referencedSecrets() = %v, want %v", gotSecrets, wantSecrets)
+	}
+}
@@ -577,8 +577,12 @@ func (rsc *ReplicaSetController) manageReplicas(ctx context.Context, filteredPod
 		// prevented from spamming the API service with the pod create requests
 		// after one of its pods fails.  Conveniently, this also prevents the
 		// event spam that those failures would generate.
+		// Stamp every pod of this burst with the same creation batch
+		// identifier so the scheduler can keep them adjacent in its queue.
+		template := rs.Spec.Template.DeepCopy()
+		metav1.SetMetaDataAnnotation(&template.ObjectMeta, v1.PodCreationBatchAnnotationKey, controller.PodCreationBatchID(rs.UID))
 		successfulCreations, err := slowStartBatch(diff, controller.SlowStartInitialBatchSize, func() error {
-			err := rsc.podControl.CreatePods(ctx, rs.Namespace, &rs.Spec.Template, rs, metav1.NewControllerRef(rs, rsc.GroupVersionKind))
+			err := rsc.podControl.CreatePods(ctx, rs.Namespace, template, rs, metav1.NewControllerRef(rs, rsc.GroupVersionKind))
 			if err != nil {
 				if apierrors.HasStatusCause(err, v1.NamespaceTerminatingCause) {
 					// if the namespace is being terminated, we don't have to do
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requeue provides shared machinery for controllers to recover from
+// apiserver connection gaps. Controllers register their informer and work
+// queue; the coordinator tracks a resourceVersion watermark per registration
+// while the connection is healthy, and after a reconnect requeues only the
+// objects that changed past the watermark, in priority order and rate
+// limited, instead of relying on a full resync.
+package requeue
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// requeueBurst is the burst size of the shared requeue rate limiter, bounding
+// how many keys can be added back-to-back right after a reconnect.
+const requeueBurst = 100
+
+// Informer is the subset of cache.SharedIndexInformer the coordinator needs.
+type Informer interface {
+	AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error)
+	GetStore() cache.Store
+	HasSynced() bool
+}
+
+// registration tracks the resourceVersion watermark for a single controller.
+//
+// Resource versions are opaque to clients in general, but within a single
+// resource they are produced by etcd in increasing order, which is the same
+// assumption watch bookmarks and the reflector rely on. The watermark is only
+// ever compared against resource versions of the same resource.
+type registration struct {
+	name     string
+	priority int
+	informer Informer
+	queue    workqueue.Interface
+
+	mu                    sync.Mutex
+	watermark             uint64
+	watermarkAtDisconnect uint64
+}
+
+func (r *registration) observe(obj interface{}) {
+	rv, ok := parseResourceVersion(obj)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rv > r.watermark {
+		r.watermark = rv
+	}
+}
+
+func (r *registration) markDisconnected() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watermarkAtDisconnect = r.watermark
+}
+
+func (r *registration) disconnectWatermark() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.watermarkAtDisconnect
+}
+
+// Coordinator detects apiserver reconnects and performs prioritized, rate
+// limited bulk requeues for all registered controllers.
+type Coordinator struct {
+	ping         func(ctx context.Context) error
+	pingInterval time.Duration
+	limiter      flowcontrol.RateLimiter
+
+	mu            sync.Mutex
+	registrations []*registration
+	disconnected  bool
+}
+
+// NewCoordinator returns a coordinator that probes the apiserver with ping
+// every pingInterval and requeues at most requeueQPS keys per second after a
+// reconnect.
+func NewCoordinator(ping func(ctx context.Context) error, pingInterval time.Duration, requeueQPS float32) *Coordinator {
+	return &Coordinator{
+		ping:         ping,
+		pingInterval: pingInterval,
+		limiter:      flowcontrol.NewTokenBucketRateLimiter(requeueQPS, requeueBurst),
+	}
+}
+
+// Register subscribes a controller's work queue to bulk requeues. Objects that
+// changed during a connection gap are added to queue by their namespace/name
+// key. Registrations with a higher priority are requeued first.
+func (c *Coordinator) Register(name string, priority int, informer Informer, queue workqueue.Interface) error {
+	r := &registration{
+		name:     name,
+		priority: priority,
+		informer: informer,
+		queue:    queue,
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    r.observe,
+		UpdateFunc: func(_, newObj interface{}) { r.observe(newObj) },
+	}); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registrations = append(c.registrations, r)
+	return nil
+}
+
+// Run probes the apiserver until ctx is done, recording watermarks when the
+// connection is lost and requeuing changed objects once it comes back.
+func (c *Coordinator) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	wait.UntilWithContext(ctx, c.checkConnectivity, c.pingInterval)
+}
+
+func (c *Coordinator) checkConnectivity(ctx context.Context) {
+	err := c.ping(ctx)
+
+	c.mu.Lock()
+	wasDisconnected := c.disconnected
+	c.disconnected = err != nil
+	registrations := make([]*registration, len(c.registrations))
+	copy(registrations, c.registrations)
+	c.mu.Unlock()
+
+	switch {
+	case err != nil && !wasDisconnected:
+		klog.V(2).InfoS("Lost connection to apiserver, recording requeue watermarks", "err", err)
+		for _, r := range registrations {
+			r.markDisconnected()
+		}
+	case err == nil && wasDisconnected:
+		klog.V(2).InfoS("Reconnected to apiserver, requeuing objects changed during the gap")
+		c.requeueChanged(ctx, registrations)
+	}
+}
+
+func (c *Coordinator) requeueChanged(ctx context.Context, registrations []*registration) {
+	sort.SliceStable(registrations, func(i, j int) bool {
+		return registrations[i].priority > registrations[j].priority
+	})
+
+	for _, r := range registrations {
+		// wait for the reflector to relist so the store reflects the state the
+		// apiserver converged to during the gap.
+		if !cache.WaitForNamedCacheSync(r.name, ctx.Done(), r.informer.HasSynced) {
+			return
+		}
+
+		watermark := r.disconnectWatermark()
+		requeued := 0
+		for _, obj := range r.informer.GetStore().List() {
+			rv, ok := parseResourceVersion(obj)
+			if !ok || rv <= watermark {
+				continue
+			}
+			key, err := cache.MetaNamespaceKeyFunc(obj)
+			if err != nil {
+				utilruntime.HandleError(err)
+				continue
+			}
+			c.limiter.Accept()
+			r.queue.Add(key)
+			requeued++
+		}
+		klog.V(2).InfoS("Requeued objects changed during apiserver connection gap", "name", r.name, "count", requeued)
+	}
+}
+
+func parseResourceVersion(obj interface{}) (uint64, bool) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return 0, false
+	}
+	rv, err := strconv.ParseUint(accessor.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return rv, true
+}
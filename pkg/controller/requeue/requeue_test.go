@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requeue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// fakeInformer is an Informer backed by a plain store; tests drive the
+// registered event handler directly.
+type fakeInformer struct {
+	store   cache.Store
+	handler cache.ResourceEventHandler
+}
+
+func newFakeInformer() *fakeInformer {
+	return &fakeInformer{store: cache.NewStore(cache.MetaNamespaceKeyFunc)}
+}
+
+func (f *fakeInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	f.handler = handler
+	return nil, nil
+}
+
+func (f *fakeInformer) GetStore() cache.Store { return f.store }
+
+func (f *fakeInformer) HasSynced() bool { return true }
+
+// add puts the pod in the store and delivers it to the event handler, the way
+// a real informer would.
+func (f *fakeInformer) add(t *testing.T, pod *v1.Pod) {
+	t.Helper()
+	if err := f.store.Add(pod); err != nil {
+		t.Fatal(err)
+	}
+	f.handler.OnAdd(pod)
+}
+
+func pod(name, resourceVersion string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: resourceVersion}}
+}
+
+func drainQueue(queue workqueue.Interface) []string {
+	var keys []string
+	for queue.Len() > 0 {
+		key, _ := queue.Get()
+		queue.Done(key)
+		keys = append(keys, key.(string))
+	}
+	return keys
+}
+
+func TestRequeueAfterReconnect(t *testing.T) {
+	var pingErr error
+	c := NewCoordinator(func(ctx context.Context) error { return pingErr }, time.Minute, 1000)
+
+	informer := newFakeInformer()
+	queue := workqueue.New()
+	defer queue.ShutDown()
+	if err := c.Register("test", 0, informer, queue); err != nil {
+		t.Fatal(err)
+	}
+
+	// Objects observed while connected move the watermark.
+	informer.add(t, pod("before", "5"))
+	c.checkConnectivity(context.Background())
+
+	// Connection drops; the watermark is snapshotted at 5.
+	pingErr = errors.New("connection refused")
+	c.checkConnectivity(context.Background())
+
+	// While disconnected the apiserver keeps moving; after the reconnect the
+	// relisted store contains the new state.
+	informer.add(t, pod("changed", "7"))
+	pingErr = nil
+	c.checkConnectivity(context.Background())
+
+	keys := drainQueue(queue)
+	if len(keys) != 1 || keys[0] != "ns/changed" {
+		t.Errorf("expected only the changed object to be requeued, got %v", keys)
+	}
+}
+
+// recordingQueue appends every added key to a shared, coordinator-wide log so
+// tests can observe the order registrations were requeued in.
+type recordingQueue struct {
+	workqueue.Interface
+	name  string
+	order *[]string
+}
+
+func (q *recordingQueue) Add(item interface{}) {
+	*q.order = append(*q.order, q.name)
+	q.Interface.Add(item)
+}
+
+func TestRequeueHonorsPriority(t *testing.T) {
+	var pingErr error
+	c := NewCoordinator(func(ctx context.Context) error { return pingErr }, time.Minute, 1000)
+
+	var order []string
+	lowInformer, highInformer := newFakeInformer(), newFakeInformer()
+	lowQueue := &recordingQueue{Interface: workqueue.New(), name: "low", order: &order}
+	highQueue := &recordingQueue{Interface: workqueue.New(), name: "high", order: &order}
+	defer lowQueue.ShutDown()
+	defer highQueue.ShutDown()
+	if err := c.Register("low", 0, lowInformer, lowQueue); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register("high", 10, highInformer, highQueue); err != nil {
+		t.Fatal(err)
+	}
+
+	pingErr = errors.New("connection refused")
+	c.checkConnectivity(context.Background())
+
+	lowInformer.add(t, pod("low-pod", "2"))
+	highInformer.add(t, pod("high-pod", "2"))
+	pingErr = nil
+	c.checkConnectivity(context.Background())
+
+	want := []string{"high", "low"}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Errorf("expected requeue order %v, got %v", want, order)
+	}
+}
+
+func TestNoRequeueWithoutDisconnect(t *testing.T) {
+	c := NewCoordinator(func(ctx context.Context) error { return nil }, time.Minute, 1000)
+
+	informer := newFakeInformer()
+	queue := workqueue.New()
+	defer queue.ShutDown()
+	if err := c.Register("test", 0, informer, queue); err != nil {
+		t.Fatal(err)
+	}
+
+	informer.add(t, pod("steady", "3"))
+	c.checkConnectivity(context.Background())
+	c.checkConnectivity(context.Background())
+
+	if queue.Len() != 0 {
+		t.Errorf("expected no requeues while connected, got %d", queue.Len())
+	}
+}
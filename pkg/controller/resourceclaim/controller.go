@@ -452,13 +452,61 @@ func (ec *Controller) syncClaim(ctx context.Context, namespace, name string) err
 		// TODO (#113700): patch
 		claim := claim.DeepCopy()
 		claim.Status.ReservedFor = valid
-		_, err := ec.kubeClient.ResourceV1alpha1().ResourceClaims(claim.Namespace).UpdateStatus(ctx, claim, metav1.UpdateOptions{})
+		claim, err = ec.kubeClient.ResourceV1alpha1().ResourceClaims(claim.Namespace).UpdateStatus(ctx, claim, metav1.UpdateOptions{})
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return ec.handOverOwnership(ctx, claim, valid)
+}
+
+// handOverOwnership keeps a shared claim alive while it is in use. A claim
+// generated from a template is owned by the pod it was generated for, which
+// also makes garbage collection remove the claim together with that pod. When
+// other pods still have the claim reserved at that point, ownership is handed
+// over to one of the remaining consumers so that the claim is only removed
+// once the last consumer is gone.
+func (ec *Controller) handOverOwnership(ctx context.Context, claim *resourcev1alpha1.ResourceClaim, reservedFor []resourcev1alpha1.ResourceClaimConsumerReference) error {
+	logger := klog.FromContext(ctx)
+
+	controller := metav1.GetControllerOf(claim)
+	if controller == nil || controller.APIVersion != "v1" || controller.Kind != "Pod" || len(reservedFor) == 0 {
+		return nil
+	}
+	for _, reserved := range reservedFor {
+		if reserved.UID == controller.UID {
+			// The owning pod is still a consumer.
+			return nil
+		}
+	}
+
+	// The owner is not among the consumers. Only reassign ownership if the
+	// owning pod really is gone; it might simply not have reserved the claim
+	// yet.
+	pod, err := ec.podLister.Pods(claim.Namespace).Get(controller.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if pod != nil && pod.UID == controller.UID && pod.DeletionTimestamp == nil {
+		return nil
+	}
+
+	claim = claim.DeepCopy()
+	isTrue := true
+	claim.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion:         "v1",
+			Kind:               "Pod",
+			Name:               reservedFor[0].Name,
+			UID:                reservedFor[0].UID,
+			Controller:         &isTrue,
+			BlockOwnerDeletion: &isTrue,
+		},
+	}
+	logger.V(5).Info("handing over ownership of shared claim", "newOwner", reservedFor[0].Name)
+	_, err = ec.kubeClient.ResourceV1alpha1().ResourceClaims(claim.Namespace).Update(ctx, claim, metav1.UpdateOptions{})
+	return err
 }
 
 // podResourceClaimIndexFunc is an index function that returns ResourceClaim keys (=
@@ -69,6 +69,7 @@ import (
 	"k8s.io/apiserver/pkg/registry/generic"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
 	serverstorage "k8s.io/apiserver/pkg/server/storage"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
@@ -128,6 +129,10 @@ const (
 	KubeAPIServer = "kube-apiserver"
 	// KubeAPIServerIdentityLeaseLabelSelector selects kube-apiserver identity leases
 	KubeAPIServerIdentityLeaseLabelSelector = IdentityLeaseComponentLabelKey + "=" + KubeAPIServer
+	// EncryptionConfigHashAnnotationKey carries the hash of the encryption configuration the
+	// apiserver is actively serving with. Comparing the annotation across identity leases
+	// detects an apiserver left behind by a partial encryption config rollout.
+	EncryptionConfigHashAnnotationKey = "apiserver.kubernetes.io/encryption-config-hash"
 	// repairLoopInterval defines the interval used to run the Services ClusterIP and NodePort repair loops
 	repairLoopInterval = 3 * time.Minute
 )
@@ -551,6 +556,15 @@ func labelAPIServerHeartbeat(lease *coordinationapiv1.Lease) error {
 
 	// convenience label to easily map a lease object to a specific apiserver
 	lease.Labels[apiv1.LabelHostname] = hostname
+
+	// expose the active encryption config hash so a partial rollout is detectable
+	// by comparing this annotation across the identity leases
+	if hash := encryptionconfig.ActiveConfigHash(); hash != "" {
+		if lease.Annotations == nil {
+			lease.Annotations = map[string]string{}
+		}
+		lease.Annotations[EncryptionConfigHashAnnotationKey] = hash
+	}
 	return nil
 }
 
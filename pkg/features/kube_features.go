@@ -685,6 +685,13 @@ const (
 	// sandbox creation and network configuration completes successfully
 	PodHasNetworkCondition featuregate.Feature = "PodHasNetworkCondition"
 
+	// owner: sig-node
+	// alpha: v1.27
+	//
+	// Allow pod readiness gates to declare a default status and an expiry
+	// after which a condition that was never reported auto-resolves.
+	PodReadinessGateDefaults featuregate.Feature = "PodReadinessGateDefaults"
+
 	// owner: @Huang-Wei
 	// kep: https://kep.k8s.io/3521
 	// alpha: v1.26
@@ -783,6 +790,14 @@ const (
 	// Subdivide the ClusterIP range for dynamic and static IP allocation.
 	ServiceIPStaticSubrange featuregate.Feature = "ServiceIPStaticSubrange"
 
+	// owner: sig-network
+	// alpha: v1.27
+	//
+	// Allows the PreferLocal value for Service internal and external traffic
+	// policies, which routes traffic to node-local endpoints while enough of
+	// them are ready and spills over to remote endpoints otherwise.
+	ServiceTrafficPolicyPreferLocal featuregate.Feature = "ServiceTrafficPolicyPreferLocal"
+
 	// owner: @derekwaynecarr
 	// alpha: v1.20
 	// beta: v1.22
@@ -1097,6 +1112,8 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 
 	PodHasNetworkCondition: {Default: false, PreRelease: featuregate.Alpha},
 
+	PodReadinessGateDefaults: {Default: false, PreRelease: featuregate.Alpha},
+
 	PodSchedulingReadiness: {Default: false, PreRelease: featuregate.Alpha},
 
 	PodSecurity: {Default: true, PreRelease: featuregate.GA, LockToDefault: true},
@@ -1123,6 +1140,8 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 
 	ServiceInternalTrafficPolicy: {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // remove in 1.28
 
+	ServiceTrafficPolicyPreferLocal: {Default: false, PreRelease: featuregate.Alpha},
+
 	SizeMemoryBackedVolumes: {Default: true, PreRelease: featuregate.Beta},
 
 	StatefulSetAutoDeletePVC: {Default: false, PreRelease: featuregate.Alpha},
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KubeAPIServerConfiguration contains the configuration of the kube-apiserver
+// in ComponentConfig form. It covers the settings most commonly reviewed in
+// configuration audits; flags without a field here remain flag-only. The
+// configuration is produced from the effective flag values by
+// --write-config-to, so a running server's configuration can be captured,
+// reviewed and diffed as a file.
+type KubeAPIServerConfiguration struct {
+	metav1.TypeMeta
+
+	// advertiseAddress is the IP address on which to advertise the apiserver
+	// to members of the cluster.
+	AdvertiseAddress string
+	// bindPort is the port on which to serve HTTPS with authentication and
+	// authorization.
+	BindPort int32
+	// serviceClusterIPRanges is the CIDR notation IP ranges from which to
+	// assign service cluster IPs, comma separated.
+	ServiceClusterIPRanges string
+	// serviceNodePortRange is the port range reserved for services with
+	// NodePort visibility.
+	ServiceNodePortRange string
+	// allowPrivileged allows privileged containers when true.
+	AllowPrivileged bool
+	// eventTTL is the amount of time to retain events.
+	EventTTL metav1.Duration
+
+	// etcd holds the configuration of the storage backend.
+	Etcd EtcdStorageConfiguration
+	// authorization holds the configuration of request authorization.
+	Authorization AuthorizationConfiguration
+	// admission holds the configuration of admission plugins.
+	Admission AdmissionPluginConfiguration
+	// featureGates is a map of feature names to bits that enable or disable
+	// alpha or experimental features.
+	FeatureGates map[string]bool
+}
+
+// EtcdStorageConfiguration contains the configuration of the etcd storage
+// backend.
+type EtcdStorageConfiguration struct {
+	// servers is the list of etcd servers to connect with.
+	Servers []string
+	// prefix is the prefix all resource paths are prepended with in etcd.
+	Prefix string
+	// encryptionProviderConfigFile is the path to the file containing the
+	// configuration for encryption providers, when encryption at rest is
+	// configured.
+	EncryptionProviderConfigFile string
+	// encryptionProviderConfigAutomaticReload reloads the file set in
+	// encryptionProviderConfigFile when its disk contents change.
+	EncryptionProviderConfigAutomaticReload bool
+}
+
+// AuthorizationConfiguration contains the configuration of request
+// authorization.
+type AuthorizationConfiguration struct {
+	// modes is the ordered list of plugins to do authorization.
+	Modes []string
+}
+
+// AdmissionPluginConfiguration contains the configuration of admission
+// plugins.
+type AdmissionPluginConfiguration struct {
+	// enablePlugins are admission plugins enabled in addition to the default
+	// enabled ones.
+	EnablePlugins []string
+	// disablePlugins are admission plugins disabled even if they are in the
+	// default enabled list.
+	DisablePlugins []string
+}
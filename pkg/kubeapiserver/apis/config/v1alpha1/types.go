@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KubeAPIServerConfiguration contains the configuration of the kube-apiserver
+// in ComponentConfig form. It covers the settings most commonly reviewed in
+// configuration audits; flags without a field here remain flag-only. The
+// configuration is produced from the effective flag values by
+// --write-config-to, so a running server's configuration can be captured,
+// reviewed and diffed as a file.
+type KubeAPIServerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// advertiseAddress is the IP address on which to advertise the apiserver
+	// to members of the cluster.
+	AdvertiseAddress string `json:"advertiseAddress,omitempty"`
+	// bindPort is the port on which to serve HTTPS with authentication and
+	// authorization.
+	BindPort int32 `json:"bindPort,omitempty"`
+	// serviceClusterIPRanges is the CIDR notation IP ranges from which to
+	// assign service cluster IPs, comma separated.
+	ServiceClusterIPRanges string `json:"serviceClusterIPRanges,omitempty"`
+	// serviceNodePortRange is the port range reserved for services with
+	// NodePort visibility.
+	ServiceNodePortRange string `json:"serviceNodePortRange,omitempty"`
+	// allowPrivileged allows privileged containers when true.
+	AllowPrivileged bool `json:"allowPrivileged,omitempty"`
+	// eventTTL is the amount of time to retain events.
+	EventTTL metav1.Duration `json:"eventTTL,omitempty"`
+
+	// etcd holds the configuration of the storage backend.
+	Etcd EtcdStorageConfiguration `json:"etcd"`
+	// authorization holds the configuration of request authorization.
+	Authorization AuthorizationConfiguration `json:"authorization"`
+	// admission holds the configuration of admission plugins.
+	Admission AdmissionPluginConfiguration `json:"admission"`
+	// featureGates is a map of feature names to bits that enable or disable
+	// alpha or experimental features.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// EtcdStorageConfiguration contains the configuration of the etcd storage
+// backend.
+type EtcdStorageConfiguration struct {
+	// servers is the list of etcd servers to connect with.
+	Servers []string `json:"servers"`
+	// prefix is the prefix all resource paths are prepended with in etcd.
+	Prefix string `json:"prefix,omitempty"`
+	// encryptionProviderConfigFile is the path to the file containing the
+	// configuration for encryption providers, when encryption at rest is
+	// configured.
+	EncryptionProviderConfigFile string `json:"encryptionProviderConfigFile,omitempty"`
+	// encryptionProviderConfigAutomaticReload reloads the file set in
+	// encryptionProviderConfigFile when its disk contents change.
+	EncryptionProviderConfigAutomaticReload bool `json:"encryptionProviderConfigAutomaticReload,omitempty"`
+}
+
+// AuthorizationConfiguration contains the configuration of request
+// authorization.
+type AuthorizationConfiguration struct {
+	// modes is the ordered list of plugins to do authorization.
+	Modes []string `json:"modes"`
+}
+
+// AdmissionPluginConfiguration contains the configuration of admission
+// plugins.
+type AdmissionPluginConfiguration struct {
+	// enablePlugins are admission plugins enabled in addition to the default
+	// enabled ones.
+	EnablePlugins []string `json:"enablePlugins,omitempty"`
+	// disablePlugins are admission plugins disabled even if they are in the
+	// default enabled list.
+	DisablePlugins []string `json:"disablePlugins,omitempty"`
+}
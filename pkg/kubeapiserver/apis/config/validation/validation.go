@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation validates KubeAPIServerConfiguration.
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/kubeapiserver/apis/config"
+)
+
+// ValidateKubeAPIServerConfiguration validates a KubeAPIServerConfiguration.
+func ValidateKubeAPIServerConfiguration(c *config.KubeAPIServerConfiguration) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.BindPort < 1 || c.BindPort > 65535 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("bindPort"), c.BindPort, "must be between 1 and 65535, inclusive"))
+	}
+	if len(c.Etcd.Servers) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("etcd", "servers"), "at least one etcd server is required"))
+	}
+	if len(c.Authorization.Modes) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("authorization", "modes"), "at least one authorization mode is required"))
+	}
+	if c.Etcd.EncryptionProviderConfigAutomaticReload && len(c.Etcd.EncryptionProviderConfigFile) == 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("etcd", "encryptionProviderConfigAutomaticReload"), "requires encryptionProviderConfigFile to be set"))
+	}
+
+	return allErrs
+}
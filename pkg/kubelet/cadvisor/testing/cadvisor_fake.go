@@ -26,6 +26,12 @@ import (
 // Fake cadvisor.Interface implementation.
 type Fake struct {
 	NodeName string
+	// NumCores overrides the number of cores reported by MachineInfo when
+	// positive; zero keeps the historical one-core default.
+	NumCores int
+	// MemoryCapacity overrides the memory capacity reported by MachineInfo
+	// when positive; zero keeps the historical 3.75GB default.
+	MemoryCapacity uint64
 }
 
 const (
@@ -73,12 +79,20 @@ func (c *Fake) DockerContainer(name string, req *cadvisorapi.ContainerInfoReques
 
 // MachineInfo is a fake implementation of Interface.MachineInfo.
 func (c *Fake) MachineInfo() (*cadvisorapi.MachineInfo, error) {
-	// Simulate a machine with 1 core and 3.75GB of memory.
+	// Simulate a machine with 1 core and 3.75GB of memory unless overridden.
 	// We set it to non-zero values to make non-zero-capacity machines in Kubemark.
+	numCores := fakeNumCores
+	if c.NumCores > 0 {
+		numCores = c.NumCores
+	}
+	memoryCapacity := uint64(fakeMemoryCapacity)
+	if c.MemoryCapacity > 0 {
+		memoryCapacity = c.MemoryCapacity
+	}
 	return &cadvisorapi.MachineInfo{
-		NumCores:       fakeNumCores,
+		NumCores:       numCores,
 		InstanceID:     cadvisorapi.InstanceID(c.NodeName),
-		MemoryCapacity: fakeMemoryCapacity,
+		MemoryCapacity: memoryCapacity,
 	}, nil
 }
 
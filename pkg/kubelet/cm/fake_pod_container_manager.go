@@ -29,6 +29,7 @@ type FakePodContainerManager struct {
 	sync.Mutex
 	CalledFunctions []string
 	Cgroups         map[types.UID]CgroupName
+	Frozen          map[types.UID]bool
 }
 
 var _ PodContainerManager = &FakePodContainerManager{}
@@ -36,6 +37,7 @@ var _ PodContainerManager = &FakePodContainerManager{}
 func NewFakePodContainerManager() *FakePodContainerManager {
 	return &FakePodContainerManager{
 		Cgroups: make(map[types.UID]CgroupName),
+		Frozen:  make(map[types.UID]bool),
 	}
 }
 
@@ -104,3 +106,18 @@ func (m *FakePodContainerManager) IsPodCgroup(cgroupfs string) (bool, types.UID)
 	m.CalledFunctions = append(m.CalledFunctions, "IsPodCgroup")
 	return false, types.UID("")
 }
+
+func (m *FakePodContainerManager) SetPodCgroupFrozen(pod *v1.Pod, frozen bool) error {
+	m.Lock()
+	defer m.Unlock()
+	m.CalledFunctions = append(m.CalledFunctions, "SetPodCgroupFrozen")
+	m.Frozen[pod.UID] = frozen
+	return nil
+}
+
+func (m *FakePodContainerManager) IsPodCgroupFrozen(pod *v1.Pod) bool {
+	m.Lock()
+	defer m.Unlock()
+	m.CalledFunctions = append(m.CalledFunctions, "IsPodCgroupFrozen")
+	return m.Frozen[pod.UID]
+}
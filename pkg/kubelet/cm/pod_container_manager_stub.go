@@ -53,3 +53,11 @@ func (m *podContainerManagerStub) GetAllPodsFromCgroups() (map[types.UID]CgroupN
 func (m *podContainerManagerStub) IsPodCgroup(cgroupfs string) (bool, types.UID) {
 	return false, types.UID("")
 }
+
+func (m *podContainerManagerStub) SetPodCgroupFrozen(_ *v1.Pod, _ bool) error {
+	return nil
+}
+
+func (m *podContainerManagerStub) IsPodCgroupFrozen(_ *v1.Pod) bool {
+	return false
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package topologymanager
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"k8s.io/api/core/v1"
@@ -133,6 +136,48 @@ func (s *scope) RemoveContainer(containerID string) error {
 	return nil
 }
 
+// affinityFailureReasons explains why a policy merge over the given providers'
+// hints failed to produce an admittable affinity. Resources that cannot be
+// aligned on their own are called out individually; when every resource is
+// individually alignable, the failure is the combination of resources.
+func affinityFailureReasons(providersHints []map[string][]TopologyHint) []string {
+	var reasons []string
+	var alignable []string
+	for _, hints := range providersHints {
+		for resource, resourceHints := range hints {
+			if resourceHints == nil {
+				// The provider has no preference for this resource.
+				continue
+			}
+			if len(resourceHints) == 0 {
+				reasons = append(reasons, fmt.Sprintf("resource %s has no possible NUMA affinity", resource))
+				continue
+			}
+			preferred := false
+			for _, hint := range resourceHints {
+				if hint.Preferred {
+					preferred = true
+					break
+				}
+			}
+			if !preferred {
+				reasons = append(reasons, fmt.Sprintf("resource %s cannot be allocated from a preferred set of NUMA nodes", resource))
+				continue
+			}
+			alignable = append(alignable, resource)
+		}
+	}
+	if len(reasons) == 0 {
+		if len(alignable) == 0 {
+			return []string{"providers returned no alignable topology hints"}
+		}
+		sort.Strings(alignable)
+		return []string{fmt.Sprintf("no common NUMA affinity across resources %s", strings.Join(alignable, ", "))}
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
 func (s *scope) admitPolicyNone(pod *v1.Pod) lifecycle.PodAdmitResult {
 	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
 		err := s.allocateAlignedResources(pod, &container)
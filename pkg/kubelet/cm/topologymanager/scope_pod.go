@@ -49,10 +49,10 @@ func (s *podScope) Admit(pod *v1.Pod) lifecycle.PodAdmitResult {
 		return s.admitPolicyNone(pod)
 	}
 
-	bestHint, admit := s.calculateAffinity(pod)
+	bestHint, admit, providersHints := s.calculateAffinity(pod)
 	klog.InfoS("Best TopologyHint", "bestHint", bestHint, "pod", klog.KObj(pod))
 	if !admit {
-		return admission.GetPodAdmitResult(&TopologyAffinityError{})
+		return admission.GetPodAdmitResult(&TopologyAffinityError{Reasons: affinityFailureReasons(providersHints)})
 	}
 
 	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
@@ -79,9 +79,9 @@ func (s *podScope) accumulateProvidersHints(pod *v1.Pod) []map[string][]Topology
 	return providersHints
 }
 
-func (s *podScope) calculateAffinity(pod *v1.Pod) (TopologyHint, bool) {
+func (s *podScope) calculateAffinity(pod *v1.Pod) (TopologyHint, bool, []map[string][]TopologyHint) {
 	providersHints := s.accumulateProvidersHints(pod)
 	bestHint, admit := s.policy.Merge(providersHints)
 	klog.InfoS("PodTopologyHint", "bestHint", bestHint)
-	return bestHint, admit
+	return bestHint, admit, providersHints
 }
@@ -125,3 +125,63 @@ func TestRemoveContainer(t *testing.T) {
 	}
 
 }
+
+func TestAffinityFailureReasons(t *testing.T) {
+	tcases := []struct {
+		name           string
+		providersHints []map[string][]TopologyHint
+		expected       []string
+	}{
+		{
+			name:           "no providers",
+			providersHints: nil,
+			expected:       []string{"providers returned no alignable topology hints"},
+		},
+		{
+			name: "resource with no possible affinity",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource-1": {},
+				},
+			},
+			expected: []string{"resource resource-1 has no possible NUMA affinity"},
+		},
+		{
+			name: "resource without a preferred hint",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource-1": {{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false}},
+				},
+			},
+			expected: []string{"resource resource-1 cannot be allocated from a preferred set of NUMA nodes"},
+		},
+		{
+			name: "individually alignable resources without a common affinity",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource-1": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+				},
+				{
+					"resource-2": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+				},
+			},
+			expected: []string{"no common NUMA affinity across resources resource-1, resource-2"},
+		},
+		{
+			name: "provider with no preference is ignored",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource-1": nil,
+					"resource-2": {},
+				},
+			},
+			expected: []string{"resource resource-2 has no possible NUMA affinity"},
+		},
+	}
+	for _, tc := range tcases {
+		actual := affinityFailureReasons(tc.providersHints)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%v: expected reasons %v, got %v", tc.name, tc.expected, actual)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package topologymanager
 
 import (
 	"fmt"
+	"strings"
 
 	cadvisorapi "github.com/google/cadvisor/info/v1"
 	"k8s.io/api/core/v1"
@@ -41,10 +42,17 @@ const (
 )
 
 // TopologyAffinityError represents an resource alignment error
-type TopologyAffinityError struct{}
+type TopologyAffinityError struct {
+	// Reasons details which NUMA alignment constraints could not be
+	// satisfied, one entry per failed resource or resource combination.
+	Reasons []string
+}
 
 func (e TopologyAffinityError) Error() string {
-	return "Resources cannot be allocated with Topology locality"
+	if len(e.Reasons) == 0 {
+		return "Resources cannot be allocated with Topology locality"
+	}
+	return fmt.Sprintf("Resources cannot be allocated with Topology locality: %s", strings.Join(e.Reasons, "; "))
 }
 
 func (e TopologyAffinityError) Type() string {
@@ -119,4 +119,11 @@ type PodContainerManager interface {
 
 	// IsPodCgroup returns true if the literal cgroupfs name corresponds to a pod
 	IsPodCgroup(cgroupfs string) (bool, types.UID)
+
+	// SetPodCgroupFrozen freezes or thaws every process in the pod-level
+	// cgroup. It is only supported on hosts running cgroup v2.
+	SetPodCgroupFrozen(pod *v1.Pod, frozen bool) error
+
+	// IsPodCgroupFrozen returns true if the pod-level cgroup is currently frozen.
+	IsPodCgroupFrozen(pod *v1.Pod) bool
 }
@@ -104,6 +104,9 @@ type realImageGCManager struct {
 
 	// sandbox image exempted from GC
 	sandboxImage string
+
+	// prepuller pins proactively pulled images; it may be nil.
+	prepuller *ImagePrepuller
 }
 
 // imageCache caches latest result of ListImages.
@@ -153,7 +156,7 @@ type imageRecord struct {
 }
 
 // NewImageGCManager instantiates a new ImageGCManager object.
-func NewImageGCManager(runtime container.Runtime, statsProvider StatsProvider, recorder record.EventRecorder, nodeRef *v1.ObjectReference, policy ImageGCPolicy, sandboxImage string) (ImageGCManager, error) {
+func NewImageGCManager(runtime container.Runtime, statsProvider StatsProvider, recorder record.EventRecorder, nodeRef *v1.ObjectReference, policy ImageGCPolicy, sandboxImage string, prepuller *ImagePrepuller) (ImageGCManager, error) {
 	// Validate policy.
 	if policy.HighThresholdPercent < 0 || policy.HighThresholdPercent > 100 {
 		return nil, fmt.Errorf("invalid HighThresholdPercent %d, must be in range [0-100]", policy.HighThresholdPercent)
@@ -173,6 +176,7 @@ func NewImageGCManager(runtime container.Runtime, statsProvider StatsProvider, r
 		nodeRef:       nodeRef,
 		initialized:   false,
 		sandboxImage:  sandboxImage,
+		prepuller:     prepuller,
 	}
 
 	return im, nil
@@ -359,6 +363,11 @@ func (im *realImageGCManager) freeSpace(ctx context.Context, bytesToFree int64,
 			continue
 
 		}
+		// Check if image was prepulled for this node, prevent garbage collection
+		if im.prepuller != nil && im.prepuller.Pinned(image) {
+			klog.V(5).InfoS("Image is pinned by the image prepuller, skipping garbage collection", "imageID", image)
+			continue
+		}
 		images = append(images, evictionInfo{
 			id:          image,
 			imageRecord: *record,
@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	nodev1alpha2 "k8s.io/api/node/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
+)
+
+// imagePrepullPeriod is how often the prepuller re-reads the node's prepull
+// annotation. Failed pulls are retried on the next pass.
+const imagePrepullPeriod = time.Minute
+
+// ImagePrepuller pulls the images the image prepull controller requested for
+// this node through the Node's prepull annotation, in the annotated order,
+// and reports per-image state back through the status annotation. Images it
+// pulled are held pinned against image garbage collection for as long as they
+// stay requested.
+type ImagePrepuller struct {
+	imageService kubecontainer.ImageService
+	getNode      func() (*v1.Node, error)
+	// kubeClient is used to report status; it may be nil in standalone mode,
+	// in which case images are still pulled but no status is reported.
+	kubeClient clientset.Interface
+	nodeName   string
+
+	lock sync.Mutex
+	// status maps each currently requested image to its pull state.
+	status map[string]string
+	// pinnedRefs are the image refs of successfully pulled requested images.
+	pinnedRefs sets.String
+	// reportedStatus is the last status annotation value written to the node.
+	reportedStatus string
+}
+
+// NewImagePrepuller constructs an ImagePrepuller for this node.
+func NewImagePrepuller(imageService kubecontainer.ImageService, getNode func() (*v1.Node, error), kubeClient clientset.Interface, nodeName string) *ImagePrepuller {
+	return &ImagePrepuller{
+		imageService: imageService,
+		getNode:      getNode,
+		kubeClient:   kubeClient,
+		nodeName:     nodeName,
+		status:       map[string]string{},
+		pinnedRefs:   sets.NewString(),
+	}
+}
+
+// Start begins the periodic prepull passes.
+func (p *ImagePrepuller) Start() {
+	go wait.Forever(func() {
+		p.sync(context.Background())
+	}, imagePrepullPeriod)
+}
+
+func (p *ImagePrepuller) sync(ctx context.Context) {
+	node, err := p.getNode()
+	if err != nil {
+		klog.V(4).InfoS("Image prepull pass skipped, node not available yet", "err", err)
+		return
+	}
+	images, err := parsePrepullAnnotation(node.Annotations[nodev1alpha2.ImagePrepullImagesAnnotationKey])
+	if err != nil {
+		klog.ErrorS(err, "Malformed image prepull annotation", "node", klog.KObj(node))
+		return
+	}
+	p.Prepull(ctx, images)
+	if err := p.reportStatus(ctx); err != nil {
+		klog.ErrorS(err, "Failed to report image prepull status", "node", klog.KObj(node))
+	}
+}
+
+// parsePrepullAnnotation decodes the controller-written JSON image list. An
+// absent annotation means nothing should be prepulled.
+func parsePrepullAnnotation(encoded string) ([]string, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	var images []string
+	if err := json.Unmarshal([]byte(encoded), &images); err != nil {
+		return nil, fmt.Errorf("decoding %s: %v", nodev1alpha2.ImagePrepullImagesAnnotationKey, err)
+	}
+	return images, nil
+}
+
+// Prepull pulls any of the given images not yet present, serially and in the
+// given order so higher priority images land first, and updates the per-image
+// states and the pinned set. Images no longer requested are unpinned and
+// become ordinary garbage collection candidates again.
+func (p *ImagePrepuller) Prepull(ctx context.Context, images []string) {
+	status := make(map[string]string, len(images))
+	pinnedRefs := sets.NewString()
+	for _, image := range images {
+		spec := kubecontainer.ImageSpec{Image: image}
+		ref, err := p.imageService.GetImageRef(ctx, spec)
+		if err == nil && ref == "" {
+			klog.V(3).InfoS("Prepulling image", "image", image)
+			ref, err = p.imageService.PullImage(ctx, spec, nil, nil)
+		}
+		if err != nil {
+			status[image] = nodev1alpha2.ImagePrepullStateFailedPrefix + err.Error()
+			continue
+		}
+		status[image] = nodev1alpha2.ImagePrepullStatePulled
+		pinnedRefs.Insert(ref)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.status = status
+	p.pinnedRefs = pinnedRefs
+}
+
+// Pinned reports whether the image ref belongs to a currently requested,
+// successfully prepulled image. The image garbage collector skips such
+// images.
+func (p *ImagePrepuller) Pinned(imageRef string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.pinnedRefs.Has(imageRef)
+}
+
+// statusAnnotationValue encodes the per-image states for the node's status
+// annotation. It returns the empty string when nothing is requested.
+func (p *ImagePrepuller) statusAnnotationValue() (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.status) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(p.status)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// reportStatus patches the node's prepull status annotation when it changed
+// since the last successful report.
+func (p *ImagePrepuller) reportStatus(ctx context.Context) error {
+	if p.kubeClient == nil {
+		return nil
+	}
+	value, err := p.statusAnnotationValue()
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	unchanged := p.reportedStatus == value
+	p.lock.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	annotations := map[string]interface{}{nodev1alpha2.ImagePrepullStatusAnnotationKey: value}
+	if value == "" {
+		annotations[nodev1alpha2.ImagePrepullStatusAnnotationKey] = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	if _, err := p.kubeClient.CoreV1().Nodes().Patch(ctx, p.nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	p.reportedStatus = value
+	p.lock.Unlock()
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	nodev1alpha2 "k8s.io/api/node/v1alpha2"
+	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
+	containertest "k8s.io/kubernetes/pkg/kubelet/container/testing"
+)
+
+func TestPrepullPullsAndPinsRequestedImages(t *testing.T) {
+	ctx := context.Background()
+	fakeRuntime := &containertest.FakeRuntime{}
+	prepuller := NewImagePrepuller(fakeRuntime, nil, nil, "node")
+
+	prepuller.Prepull(ctx, []string{"app:v1", "app:v2"})
+
+	for _, image := range []string{"app:v1", "app:v2"} {
+		ref, err := fakeRuntime.GetImageRef(ctx, kubecontainer.ImageSpec{Image: image})
+		if err != nil || ref == "" {
+			t.Errorf("expected image %q to be pulled, got ref %q, err: %v", image, ref, err)
+		}
+		if !prepuller.Pinned(ref) {
+			t.Errorf("expected image %q (ref %q) to be pinned", image, ref)
+		}
+	}
+
+	// images dropped from the request are unpinned again
+	prepuller.Prepull(ctx, []string{"app:v2"})
+	ref, _ := fakeRuntime.GetImageRef(ctx, kubecontainer.ImageSpec{Image: "app:v1"})
+	if prepuller.Pinned(ref) {
+		t.Errorf("expected image %q to be unpinned after it was dropped from the request", "app:v1")
+	}
+}
+
+func TestPrepullRecordsFailedPulls(t *testing.T) {
+	ctx := context.Background()
+	fakeRuntime := &containertest.FakeRuntime{Err: fmt.Errorf("pull access denied")}
+	prepuller := NewImagePrepuller(fakeRuntime, nil, nil, "node")
+
+	prepuller.Prepull(ctx, []string{"app:v1"})
+
+	value, err := prepuller.statusAnnotationValue()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		t.Fatalf("malformed status annotation %q: %v", value, err)
+	}
+	if status["app:v1"] != nodev1alpha2.ImagePrepullStateFailedPrefix+"pull access denied" {
+		t.Errorf("expected the pull failure to be recorded, got %q", status["app:v1"])
+	}
+}
+
+func TestParsePrepullAnnotation(t *testing.T) {
+	if images, err := parsePrepullAnnotation(""); err != nil || images != nil {
+		t.Errorf("expected an absent annotation to request nothing, got %v, err: %v", images, err)
+	}
+	if images, err := parsePrepullAnnotation(`["a:v1","b:v2"]`); err != nil || len(images) != 2 {
+		t.Errorf("expected two images, got %v, err: %v", images, err)
+	}
+	if _, err := parsePrepullAnnotation("not json"); err == nil {
+		t.Error("expected an error for a malformed annotation")
+	}
+}
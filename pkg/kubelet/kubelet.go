@@ -73,6 +73,7 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/cloudresource"
 	"k8s.io/kubernetes/pkg/kubelet/cm"
 	draplugin "k8s.io/kubernetes/pkg/kubelet/cm/dra/plugin"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager"
 	"k8s.io/kubernetes/pkg/kubelet/config"
 	"k8s.io/kubernetes/pkg/kubelet/configmap"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
@@ -86,12 +87,14 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/metrics"
 	"k8s.io/kubernetes/pkg/kubelet/metrics/collectors"
 	"k8s.io/kubernetes/pkg/kubelet/network/dns"
+	"k8s.io/kubernetes/pkg/kubelet/nodehealth"
 	"k8s.io/kubernetes/pkg/kubelet/nodeshutdown"
 	oomwatcher "k8s.io/kubernetes/pkg/kubelet/oom"
 	"k8s.io/kubernetes/pkg/kubelet/pleg"
 	"k8s.io/kubernetes/pkg/kubelet/pluginmanager"
 	plugincache "k8s.io/kubernetes/pkg/kubelet/pluginmanager/cache"
 	kubepod "k8s.io/kubernetes/pkg/kubelet/pod"
+	"k8s.io/kubernetes/pkg/kubelet/policycache"
 	"k8s.io/kubernetes/pkg/kubelet/preemption"
 	"k8s.io/kubernetes/pkg/kubelet/prober"
 	proberesults "k8s.io/kubernetes/pkg/kubelet/prober/results"
@@ -243,6 +246,7 @@ type Dependencies struct {
 	EventClient              v1core.EventsGetter
 	HeartbeatClient          clientset.Interface
 	OnHeartbeatFailure       func()
+	ClockSkewDetector        *nodehealth.ClockSkewDetector
 	KubeClient               clientset.Interface
 	Mounter                  mount.Interface
 	HostUtil                 hostutil.HostUtils
@@ -514,6 +518,7 @@ func NewMainKubelet(kubeCfg *kubeletconfiginternal.KubeletConfiguration,
 		kubeClient:                              kubeDeps.KubeClient,
 		heartbeatClient:                         kubeDeps.HeartbeatClient,
 		onRepeatedHeartbeatFailure:              kubeDeps.OnHeartbeatFailure,
+		clockSkewDetector:                       kubeDeps.ClockSkewDetector,
 		rootDirectory:                           rootDirectory,
 		resyncInterval:                          kubeCfg.SyncFrequency.Duration,
 		sourcesReady:                            config.NewSourcesReady(kubeDeps.PodConfig.SeenAllSources),
@@ -616,6 +621,7 @@ func NewMainKubelet(kubeCfg *kubeletconfiginternal.KubeletConfiguration,
 	klet.resourceAnalyzer = serverstats.NewResourceAnalyzer(klet, kubeCfg.VolumeStatsAggPeriod.Duration, kubeDeps.Recorder)
 
 	klet.runtimeService = kubeDeps.RemoteRuntimeService
+	klet.imageService = kubeDeps.RemoteImageService
 
 	if kubeDeps.KubeClient != nil {
 		klet.runtimeClassManager = runtimeclass.NewManager(kubeDeps.KubeClient)
@@ -759,7 +765,8 @@ func NewMainKubelet(kubeCfg *kubeletconfiginternal.KubeletConfiguration,
 	klet.containerDeletor = newPodContainerDeletor(klet.containerRuntime, integer.IntMax(containerGCPolicy.MaxPerPodContainer, minDeadContainerInPod))
 
 	// setup imageManager
-	imageManager, err := images.NewImageGCManager(klet.containerRuntime, klet.StatsProvider, kubeDeps.Recorder, nodeRef, imageGCPolicy, crOptions.PodSandboxImage)
+	klet.imagePrepuller = images.NewImagePrepuller(klet.containerRuntime, klet.GetNode, klet.kubeClient, string(klet.nodeName))
+	imageManager, err := images.NewImageGCManager(klet.containerRuntime, klet.StatsProvider, kubeDeps.Recorder, nodeRef, imageGCPolicy, crOptions.PodSandboxImage, klet.imagePrepuller)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize image manager: %v", err)
 	}
@@ -848,6 +855,18 @@ func NewMainKubelet(kubeCfg *kubeletconfiginternal.KubeletConfiguration,
 	}
 	klet.admitHandlers.AddPodAdmitHandler(sysctlsAllowlist)
 
+	// Cache admission-relevant policies locally so pod admission keeps
+	// behaving consistently across restarts while the API server is
+	// unreachable.
+	policyCache := policycache.NewCache(filepath.Join(rootDirectory, "policy-cache"))
+	if err := policyCache.Load(); err != nil {
+		klog.InfoS("Failed to load local policy cache, continuing without persisted state", "err", err)
+	}
+	if kubeDeps.KubeClient != nil {
+		go policyCache.Run(kubeDeps.KubeClient, policycache.DefaultSyncPeriod, wait.NeverStop)
+	}
+	klet.admitHandlers.AddPodAdmitHandler(policycache.NewAdmissionHandler(policyCache))
+
 	// enable active deadline handler
 	activeDeadlineHandler, err := newActiveDeadlineHandler(klet.statusManager, kubeDeps.Recorder, klet.clock)
 	if err != nil {
@@ -941,6 +960,10 @@ type Kubelet struct {
 	// onRepeatedHeartbeatFailure is called when a heartbeat operation fails more than once. optional.
 	onRepeatedHeartbeatFailure func()
 
+	// clockSkewDetector measures the offset between the local clock and the
+	// apiserver's clock for the ClockSkew node condition. optional.
+	clockSkewDetector *nodehealth.ClockSkewDetector
+
 	// podWorkers handle syncing Pods in response to events.
 	podWorkers PodWorkers
 
@@ -1020,6 +1043,9 @@ type Kubelet struct {
 	// Manager for image garbage collection.
 	imageManager images.ImageGCManager
 
+	// Pulls and pins images requested through the node's prepull annotation.
+	imagePrepuller *images.ImagePrepuller
+
 	// Manager for container logs.
 	containerLogManager logs.ContainerLogManager
 
@@ -1063,6 +1089,9 @@ type Kubelet struct {
 	// Container runtime service (needed by container runtime Start()).
 	runtimeService internalapi.RuntimeService
 
+	// Image service (needed by the CRI introspection endpoints).
+	imageService internalapi.ImageManagerService
+
 	// reasonCache caches the failure reason of the last creation of all containers, which is
 	// used for generating ContainerStatus.
 	reasonCache *ReasonCache
@@ -1424,6 +1453,9 @@ func (kl *Kubelet) initializeModules() error {
 	// Start the image manager.
 	kl.imageManager.Start()
 
+	// Start the image prepuller.
+	kl.imagePrepuller.Start()
+
 	// Start the certificate manager if it was enabled.
 	if kl.serverCertificateManager != nil {
 		kl.serverCertificateManager.Start()
@@ -1772,6 +1804,19 @@ func (kl *Kubelet) syncPod(_ context.Context, updateType kubetypes.SyncPodType,
 				}
 			}
 		}
+
+		// Reconcile the pod-level cgroup freeze state with the pause
+		// annotation, so a paused pod stops running without losing state.
+		if pcm.Exists(pod) {
+			paused := kubetypes.IsPodPauseRequested(pod)
+			if paused != pcm.IsPodCgroupFrozen(pod) {
+				if err := pcm.SetPodCgroupFrozen(pod, paused); err != nil {
+					klog.ErrorS(err, "Failed to update pod cgroup freeze state", "pod", klog.KObj(pod), "paused", paused)
+				} else {
+					klog.V(2).InfoS("Updated pod cgroup freeze state", "pod", klog.KObj(pod), "paused", paused)
+				}
+			}
+		}
 	}
 
 	// Create Mirror Pod for Static Pod if it doesn't already exist
@@ -2064,10 +2109,24 @@ func (kl *Kubelet) deletePod(pod *v1.Pod) error {
 // and updates the pod to the failed phase in the status manage.
 func (kl *Kubelet) rejectPod(pod *v1.Pod, reason, message string) {
 	kl.recorder.Eventf(pod, v1.EventTypeWarning, reason, message)
-	kl.statusManager.SetPodStatus(pod, v1.PodStatus{
+	status := v1.PodStatus{
 		Phase:   v1.PodFailed,
 		Reason:  reason,
-		Message: "Pod was rejected: " + message})
+		Message: "Pod was rejected: " + message,
+	}
+	// Surface the detailed outcome of the topology manager's pre-flight
+	// evaluation as a condition, so consumers can see which NUMA constraint
+	// failed instead of just the opaque rejection reason.
+	if reason == topologymanager.ErrorTopologyAffinity {
+		status.Conditions = []v1.PodCondition{{
+			Type:               kubetypes.PodTopologyAffinity,
+			Status:             v1.ConditionFalse,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: metav1.Now(),
+		}}
+	}
+	kl.statusManager.SetPodStatus(pod, status)
 }
 
 // canAdmitPod determines if a pod can be admitted, and gives a reason if it
@@ -2607,6 +2666,23 @@ func (kl *Kubelet) ListPodSandboxMetrics(ctx context.Context) ([]*runtimeapi.Pod
 	return kl.containerRuntime.ListPodSandboxMetrics(ctx)
 }
 
+// ListRuntimeContainers lists all containers known to the container runtime,
+// including containers not managed by this kubelet.
+func (kl *Kubelet) ListRuntimeContainers(ctx context.Context) ([]*runtimeapi.Container, error) {
+	return kl.runtimeService.ListContainers(ctx, nil)
+}
+
+// ListRuntimePodSandboxes lists all pod sandboxes known to the container runtime.
+func (kl *Kubelet) ListRuntimePodSandboxes(ctx context.Context) ([]*runtimeapi.PodSandbox, error) {
+	return kl.runtimeService.ListPodSandbox(ctx, nil)
+}
+
+// RuntimeImageFsInfo returns information about the filesystems the container
+// runtime stores images on.
+func (kl *Kubelet) RuntimeImageFsInfo(ctx context.Context) ([]*runtimeapi.FilesystemUsage, error) {
+	return kl.imageService.ImageFsInfo(ctx)
+}
+
 func (kl *Kubelet) supportLocalStorageCapacityIsolation() bool {
 	return kl.GetConfiguration().LocalStorageCapacityIsolation
 }
@@ -39,6 +39,7 @@ import (
 	kubeletapis "k8s.io/kubelet/pkg/apis"
 	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	"k8s.io/kubernetes/pkg/kubelet/events"
+	"k8s.io/kubernetes/pkg/kubelet/nodehealth"
 	"k8s.io/kubernetes/pkg/kubelet/nodestatus"
 	"k8s.io/kubernetes/pkg/kubelet/util"
 	taintutil "k8s.io/kubernetes/pkg/util/taints"
@@ -746,6 +747,16 @@ func (kl *Kubelet) defaultNodeStatusFuncs() []func(context.Context, *v1.Node) er
 		nodestatus.ReadyCondition(kl.clock.Now, kl.runtimeState.runtimeErrors, kl.runtimeState.networkErrors, kl.runtimeState.storageErrors,
 			validateHostFunc, kl.containerManager.Status, kl.shutdownManager.ShutdownStatus, kl.recordNodeStatusEvent, kl.supportLocalStorageCapacityIsolation()),
 		nodestatus.VolumesInUse(kl.volumeManager.ReconcilerStatesHasBeenSynced, kl.volumeManager.GetVolumesInUse),
+	)
+	if kl.clockSkewDetector != nil {
+		setters = append(setters,
+			nodestatus.ClockSkewCondition(kl.clock.Now, kl.clockSkewDetector.Skew, nodehealth.DefaultMaxClockSkew, kl.recordNodeStatusEvent))
+	}
+	if nodehealth.EntropySupported() {
+		setters = append(setters,
+			nodestatus.LowEntropyCondition(kl.clock.Now, nodehealth.EntropyAvailable, nodehealth.DefaultMinEntropy, kl.recordNodeStatusEvent))
+	}
+	setters = append(setters,
 		// TODO(mtaufen): I decided not to move this setter for now, since all it does is send an event
 		// and record state back to the Kubelet runtime object. In the future, I'd like to isolate
 		// these side-effects by decoupling the decisions to send events and partial status recording
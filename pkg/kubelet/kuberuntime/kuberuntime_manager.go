@@ -494,7 +494,16 @@ func containerSucceeded(c *v1.Container, podStatus *kubecontainer.PodStatus) boo
 func (m *kubeGenericRuntimeManager) computePodActions(pod *v1.Pod, podStatus *kubecontainer.PodStatus) podActions {
 	klog.V(5).InfoS("Syncing Pod", "pod", klog.KObj(pod))
 
-	createPodSandbox, attempt, sandboxID := runtimeutil.PodSandboxChanged(pod, podStatus)
+	reusePolicy := runtimeclass.SandboxReuseNever
+	if m.runtimeClassManager != nil {
+		policy, err := m.runtimeClassManager.LookupSandboxReusePolicy(pod.Spec.RuntimeClassName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to look up the sandbox reuse policy for pod, assuming Never", "pod", klog.KObj(pod))
+		} else {
+			reusePolicy = policy
+		}
+	}
+	createPodSandbox, attempt, sandboxID := runtimeutil.PodSandboxChangedWithReusePolicy(pod, podStatus, reusePolicy)
 	changes := podActions{
 		KillPod:           createPodSandbox,
 		CreateSandbox:     createPodSandbox,
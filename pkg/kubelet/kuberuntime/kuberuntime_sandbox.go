@@ -31,6 +31,7 @@ import (
 	"k8s.io/kubernetes/pkg/features"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 	runtimeutil "k8s.io/kubernetes/pkg/kubelet/kuberuntime/util"
+	"k8s.io/kubernetes/pkg/kubelet/runtimeclass"
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	"k8s.io/kubernetes/pkg/kubelet/util"
 	"k8s.io/kubernetes/pkg/kubelet/util/format"
@@ -64,6 +65,20 @@ func (m *kubeGenericRuntimeManager) createPodSandbox(ctx context.Context, pod *v
 		if runtimeHandler != "" {
 			klog.V(2).InfoS("Running pod with runtime handler", "pod", klog.KObj(pod), "runtimeHandler", runtimeHandler)
 		}
+
+		reusePolicy, err := m.runtimeClassManager.LookupSandboxReusePolicy(pod.Spec.RuntimeClassName)
+		if err != nil {
+			message := fmt.Sprintf("Failed to create sandbox for pod %q: %v", format.Pod(pod), err)
+			return "", message, err
+		}
+		if reusePolicy != runtimeclass.SandboxReuseNever {
+			// Ask the runtime to retain the network namespace and IP of this
+			// sandbox so they survive a sandbox restart.
+			if podSandboxConfig.Annotations == nil {
+				podSandboxConfig.Annotations = map[string]string{}
+			}
+			podSandboxConfig.Annotations[runtimeclass.SandboxReusePolicyAnnotation] = string(reusePolicy)
+		}
 	}
 
 	podSandBoxID, err := m.runtimeService.RunPodSandbox(ctx, podSandboxConfig, runtimeHandler)
@@ -21,11 +21,21 @@ import (
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/klog/v2"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
+	"k8s.io/kubernetes/pkg/kubelet/runtimeclass"
 )
 
 // PodSandboxChanged checks whether the spec of the pod is changed and returns
 // (changed, new attempt, original sandboxID if exist).
 func PodSandboxChanged(pod *v1.Pod, podStatus *kubecontainer.PodStatus) (bool, uint32, string) {
+	return PodSandboxChangedWithReusePolicy(pod, podStatus, runtimeclass.SandboxReuseNever)
+}
+
+// PodSandboxChangedWithReusePolicy is PodSandboxChanged with an explicit
+// sandbox reuse policy. Under SandboxReuseOnContainerRestart the latest ready
+// sandbox is kept even when older ready duplicates exist, so the pod retains
+// its network namespace and IP; the duplicates are left to sandbox garbage
+// collection.
+func PodSandboxChangedWithReusePolicy(pod *v1.Pod, podStatus *kubecontainer.PodStatus, reusePolicy runtimeclass.SandboxReusePolicy) (bool, uint32, string) {
 	if len(podStatus.SandboxStatuses) == 0 {
 		klog.V(2).InfoS("No sandbox for pod can be found. Need to start a new one", "pod", klog.KObj(pod))
 		return true, 0, ""
@@ -41,8 +51,12 @@ func PodSandboxChanged(pod *v1.Pod, podStatus *kubecontainer.PodStatus) (bool, u
 	// Needs to create a new sandbox when readySandboxCount > 1 or the ready sandbox is not the latest one.
 	sandboxStatus := podStatus.SandboxStatuses[0]
 	if readySandboxCount > 1 {
-		klog.V(2).InfoS("Multiple sandboxes are ready for Pod. Need to reconcile them", "pod", klog.KObj(pod))
-		return true, sandboxStatus.Metadata.Attempt + 1, sandboxStatus.Id
+		if reusePolicy == runtimeclass.SandboxReuseOnContainerRestart && sandboxStatus.State == runtimeapi.PodSandboxState_SANDBOX_READY {
+			klog.V(2).InfoS("Multiple sandboxes are ready for Pod. Reusing the latest one per sandbox reuse policy", "pod", klog.KObj(pod))
+		} else {
+			klog.V(2).InfoS("Multiple sandboxes are ready for Pod. Need to reconcile them", "pod", klog.KObj(pod))
+			return true, sandboxStatus.Metadata.Attempt + 1, sandboxStatus.Id
+		}
 	}
 	if sandboxStatus.State != runtimeapi.PodSandboxState_SANDBOX_READY {
 		klog.V(2).InfoS("No ready sandbox for pod can be found. Need to start a new one", "pod", klog.KObj(pod))
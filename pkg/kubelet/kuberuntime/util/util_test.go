@@ -25,6 +25,7 @@ import (
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 	kubecontainertest "k8s.io/kubernetes/pkg/kubelet/container/testing"
+	"k8s.io/kubernetes/pkg/kubelet/runtimeclass"
 )
 
 func TestPodSandboxChanged(t *testing.T) {
@@ -229,3 +230,41 @@ func TestNamespacesForPod(t *testing.T) {
 		})
 	}
 }
+
+func TestPodSandboxChangedWithReusePolicy(t *testing.T) {
+	status := &kubecontainer.PodStatus{
+		SandboxStatuses: []*runtimeapi.PodSandboxStatus{
+			{
+				Id:       "sandboxID2",
+				Metadata: &runtimeapi.PodSandboxMetadata{Attempt: uint32(1)},
+				State:    runtimeapi.PodSandboxState_SANDBOX_READY,
+				Network:  &runtimeapi.PodSandboxNetworkStatus{Ip: "10.0.0.10"},
+			},
+			{
+				Id:       "sandboxID1",
+				Metadata: &runtimeapi.PodSandboxMetadata{Attempt: uint32(0)},
+				State:    runtimeapi.PodSandboxState_SANDBOX_READY,
+			},
+		},
+	}
+
+	// The default policy reconciles multiple ready sandboxes with a new one.
+	changed, attempt, id := PodSandboxChangedWithReusePolicy(&v1.Pod{}, status, runtimeclass.SandboxReuseNever)
+	require.True(t, changed)
+	require.Equal(t, uint32(2), attempt)
+	require.Equal(t, "sandboxID2", id)
+
+	// The reuse policy keeps the latest ready sandbox and its IP.
+	changed, attempt, id = PodSandboxChangedWithReusePolicy(&v1.Pod{}, status, runtimeclass.SandboxReuseOnContainerRestart)
+	require.False(t, changed)
+	require.Equal(t, uint32(1), attempt)
+	require.Equal(t, "sandboxID2", id)
+
+	// A dead sandbox still has to be replaced regardless of the policy.
+	status.SandboxStatuses[0].State = runtimeapi.PodSandboxState_SANDBOX_NOTREADY
+	status.SandboxStatuses[1].State = runtimeapi.PodSandboxState_SANDBOX_NOTREADY
+	changed, attempt, id = PodSandboxChangedWithReusePolicy(&v1.Pod{}, status, runtimeclass.SandboxReuseOnContainerRestart)
+	require.True(t, changed)
+	require.Equal(t, uint32(2), attempt)
+	require.Equal(t, "sandboxID2", id)
+}
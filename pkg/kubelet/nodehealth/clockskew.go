@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodehealth contains node-level health probes the kubelet surfaces
+// as node conditions: clock skew relative to the apiserver and entropy
+// starvation. Both silently break certificate validation and token issuance,
+// so they are reported rather than left for operators to discover.
+package nodehealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+)
+
+// DefaultMaxClockSkew is the skew relative to the apiserver above which the
+// node is reported as skewed. Five minutes matches the leeway commonly
+// granted when validating certificates and service account tokens; a node
+// beyond it is already at risk of authentication failures.
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// ClockSkewDetector estimates the offset between the local clock and the
+// apiserver's clock.
+type ClockSkewDetector struct {
+	base   *url.URL
+	client *http.Client
+	clock  clock.Clock
+}
+
+// NewClockSkewDetector returns a detector measuring against the apiserver of
+// the given client configuration.
+func NewClockSkewDetector(config *restclient.Config) (*ClockSkewDetector, error) {
+	base, err := url.Parse(config.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse apiserver URL %q: %w", config.Host, err)
+	}
+	client, err := restclient.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return &ClockSkewDetector{base: base, client: client, clock: clock.RealClock{}}, nil
+}
+
+// Skew estimates the local clock's offset from the apiserver's clock: a
+// positive skew means the local clock runs ahead. The estimate comes from the
+// Date header of an apiserver response, corrected by half the round trip
+// time; the header has second granularity, which is plenty for the
+// multi-minute skews that break authentication.
+func (d *ClockSkewDetector) Skew(ctx context.Context) (time.Duration, error) {
+	requestURL := *d.base
+	requestURL.Path = "/version"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := d.clock.Now()
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	roundTrip := d.clock.Since(sent)
+
+	// any response carries a Date header, so an unauthorized or forbidden
+	// status still yields a valid measurement
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("apiserver response carries no parseable Date header: %w", err)
+	}
+	localEstimate := sent.Add(roundTrip / 2)
+	return localEstimate.Sub(serverTime), nil
+}
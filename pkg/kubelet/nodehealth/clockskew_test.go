@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+func newTestDetector(t *testing.T, handler http.Handler) (*ClockSkewDetector, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	base, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &ClockSkewDetector{base: base, client: server.Client(), clock: clock.RealClock{}}, server
+}
+
+func TestSkew(t *testing.T) {
+	// the Date header has second granularity and the estimate includes the
+	// round trip correction, so allow generous slack around each offset
+	const tolerance = 10 * time.Second
+
+	cases := []struct {
+		desc   string
+		offset time.Duration
+	}{
+		{desc: "in sync", offset: 0},
+		{desc: "local clock ahead", offset: 10 * time.Minute},
+		{desc: "local clock behind", offset: -10 * time.Minute},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			detector, _ := newTestDetector(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Date", time.Now().Add(-tc.offset).UTC().Format(http.TimeFormat))
+				w.WriteHeader(http.StatusOK)
+			}))
+			skew, err := detector.Skew(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := skew - tc.offset; diff < -tolerance || diff > tolerance {
+				t.Errorf("expected skew near %v, got %v", tc.offset, skew)
+			}
+		})
+	}
+}
+
+func TestSkewUsesDateFromErrorResponses(t *testing.T) {
+	detector, _ := newTestDetector(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	skew, err := detector.Skew(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skew < -10*time.Second || skew > 10*time.Second {
+		t.Errorf("expected near-zero skew, got %v", skew)
+	}
+}
+
+func TestSkewMissingDateHeader(t *testing.T) {
+	detector, _ := newTestDetector(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Date"] = nil
+		w.WriteHeader(http.StatusOK)
+	}))
+	if _, err := detector.Skew(context.Background()); err == nil {
+		t.Error("expected error for response without a Date header")
+	}
+}
+
+func TestSkewUnreachableServer(t *testing.T) {
+	detector, server := newTestDetector(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	if _, err := detector.Skew(context.Background()); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+}
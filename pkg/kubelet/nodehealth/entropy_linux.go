@@ -0,0 +1,56 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultMinEntropy is the available entropy, in bits, below which the node
+// is reported as starved. Kernels since 5.6 keep the pool at 256 bits once
+// initialized, so a sustained reading below this threshold indicates a pool
+// that never initialized or a legacy kernel draining faster than its noise
+// sources refill.
+const DefaultMinEntropy = 200
+
+const entropyAvailPath = "/proc/sys/kernel/random/entropy_avail"
+
+// EntropySupported reports whether available entropy can be read on this
+// platform.
+func EntropySupported() bool {
+	return true
+}
+
+// EntropyAvailable returns the entropy, in bits, currently available to the
+// kernel random pool.
+func EntropyAvailable() (int, error) {
+	data, err := os.ReadFile(entropyAvailPath)
+	if err != nil {
+		return 0, err
+	}
+	entropy, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", entropyAvailPath, err)
+	}
+	return entropy, nil
+}
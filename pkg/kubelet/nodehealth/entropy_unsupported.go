@@ -0,0 +1,37 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import "errors"
+
+// DefaultMinEntropy is unused on platforms without entropy accounting.
+const DefaultMinEntropy = 0
+
+// EntropySupported reports whether available entropy can be read on this
+// platform.
+func EntropySupported() bool {
+	return false
+}
+
+// EntropyAvailable returns the entropy, in bits, currently available to the
+// kernel random pool.
+func EntropyAvailable() (int, error) {
+	return 0, errors.New("entropy accounting is not supported on this platform")
+}
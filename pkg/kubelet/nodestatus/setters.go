@@ -735,6 +735,155 @@ func DiskPressureCondition(nowFunc func() time.Time, // typically Kubelet.clock.
 	}
 }
 
+const (
+	// NodeClockSkew is the condition reporting that the node's clock is
+	// significantly skewed relative to the apiserver.
+	NodeClockSkew v1.NodeConditionType = "ClockSkew"
+	// NodeLowEntropy is the condition reporting that the kernel random pool
+	// of the node is starved of entropy.
+	NodeLowEntropy v1.NodeConditionType = "LowEntropy"
+)
+
+// ClockSkewCondition returns a Setter that updates the v1.NodeConditionType
+// ClockSkew condition on the node. Skew beyond maxSkew silently breaks
+// certificate validation and token issuance, so it is surfaced as a
+// condition and an event rather than left for operators to discover.
+func ClockSkewCondition(nowFunc func() time.Time, // typically Kubelet.clock.Now
+	skewFunc func(ctx context.Context) (time.Duration, error), // typically Kubelet.clockSkewDetector.Skew
+	maxSkew time.Duration,
+	recordEventFunc func(eventType, event string), // typically Kubelet.recordNodeStatusEvent
+) Setter {
+	return func(ctx context.Context, node *v1.Node) error {
+		currentTime := metav1.NewTime(nowFunc())
+		var condition *v1.NodeCondition
+
+		// Check if ClockSkew condition already exists and if it does, just pick it up for update.
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == NodeClockSkew {
+				condition = &node.Status.Conditions[i]
+			}
+		}
+
+		newCondition := false
+		// If the ClockSkew condition doesn't exist, create one
+		if condition == nil {
+			condition = &v1.NodeCondition{
+				Type:   NodeClockSkew,
+				Status: v1.ConditionUnknown,
+			}
+			// cannot be appended to node.Status.Conditions here because it gets
+			// copied to the slice. So if we append to the slice here none of the
+			// updates we make below are reflected in the slice.
+			newCondition = true
+		}
+
+		// Update the heartbeat time
+		condition.LastHeartbeatTime = currentTime
+
+		skew, err := skewFunc(ctx)
+		switch {
+		case err != nil:
+			if newCondition || condition.Status != v1.ConditionUnknown {
+				condition.Status = v1.ConditionUnknown
+				condition.Reason = "ClockSkewUnknown"
+				condition.LastTransitionTime = currentTime
+			}
+			condition.Message = fmt.Sprintf("kubelet failed to measure clock skew: %v", err)
+		case skew > maxSkew || skew < -maxSkew:
+			if condition.Status != v1.ConditionTrue {
+				condition.Status = v1.ConditionTrue
+				condition.Reason = "KubeletDetectedClockSkew"
+				condition.LastTransitionTime = currentTime
+				recordEventFunc(v1.EventTypeWarning, "NodeHasClockSkew")
+			}
+			condition.Message = fmt.Sprintf("node clock is skewed by %v relative to the apiserver, exceeding the %v tolerance", skew, maxSkew)
+		default:
+			if condition.Status != v1.ConditionFalse {
+				condition.Status = v1.ConditionFalse
+				condition.Reason = "KubeletHasSynchronizedClock"
+				condition.LastTransitionTime = currentTime
+				recordEventFunc(v1.EventTypeNormal, "NodeHasSynchronizedClock")
+			}
+			condition.Message = fmt.Sprintf("node clock is within %v of the apiserver", maxSkew)
+		}
+
+		if newCondition {
+			node.Status.Conditions = append(node.Status.Conditions, *condition)
+		}
+		return nil
+	}
+}
+
+// LowEntropyCondition returns a Setter that updates the v1.NodeConditionType
+// LowEntropy condition on the node. An entropy-starved node silently blocks
+// or weakens key generation, so starvation is surfaced as a condition and an
+// event.
+func LowEntropyCondition(nowFunc func() time.Time, // typically Kubelet.clock.Now
+	entropyFunc func() (int, error), // typically nodehealth.EntropyAvailable
+	minEntropy int,
+	recordEventFunc func(eventType, event string), // typically Kubelet.recordNodeStatusEvent
+) Setter {
+	return func(ctx context.Context, node *v1.Node) error {
+		currentTime := metav1.NewTime(nowFunc())
+		var condition *v1.NodeCondition
+
+		// Check if LowEntropy condition already exists and if it does, just pick it up for update.
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == NodeLowEntropy {
+				condition = &node.Status.Conditions[i]
+			}
+		}
+
+		newCondition := false
+		// If the LowEntropy condition doesn't exist, create one
+		if condition == nil {
+			condition = &v1.NodeCondition{
+				Type:   NodeLowEntropy,
+				Status: v1.ConditionUnknown,
+			}
+			// cannot be appended to node.Status.Conditions here because it gets
+			// copied to the slice. So if we append to the slice here none of the
+			// updates we make below are reflected in the slice.
+			newCondition = true
+		}
+
+		// Update the heartbeat time
+		condition.LastHeartbeatTime = currentTime
+
+		entropy, err := entropyFunc()
+		switch {
+		case err != nil:
+			if newCondition || condition.Status != v1.ConditionUnknown {
+				condition.Status = v1.ConditionUnknown
+				condition.Reason = "EntropyUnknown"
+				condition.LastTransitionTime = currentTime
+			}
+			condition.Message = fmt.Sprintf("kubelet failed to read available entropy: %v", err)
+		case entropy < minEntropy:
+			if condition.Status != v1.ConditionTrue {
+				condition.Status = v1.ConditionTrue
+				condition.Reason = "KubeletDetectedLowEntropy"
+				condition.LastTransitionTime = currentTime
+				recordEventFunc(v1.EventTypeWarning, "NodeHasLowEntropy")
+			}
+			condition.Message = fmt.Sprintf("node has %d bits of entropy available, below the %d bit minimum", entropy, minEntropy)
+		default:
+			if condition.Status != v1.ConditionFalse {
+				condition.Status = v1.ConditionFalse
+				condition.Reason = "KubeletHasSufficientEntropy"
+				condition.LastTransitionTime = currentTime
+				recordEventFunc(v1.EventTypeNormal, "NodeHasSufficientEntropy")
+			}
+			condition.Message = fmt.Sprintf("node has %d bits of entropy available", entropy)
+		}
+
+		if newCondition {
+			node.Status.Conditions = append(node.Status.Conditions, *condition)
+		}
+		return nil
+	}
+}
+
 // VolumesInUse returns a Setter that updates the volumes in use on the node.
 func VolumesInUse(syncedFunc func() bool, // typically Kubelet.volumeManager.ReconcilerStatesHasBeenSynced
 	volumesInUseFunc func() []v1.UniqueVolumeName, // typically Kubelet.volumeManager.GetVolumesInUse
@@ -2075,3 +2075,279 @@ func makeDiskPressureCondition(pressure bool, transition, heartbeat time.Time) *
 		LastHeartbeatTime:  metav1.NewTime(heartbeat),
 	}
 }
+
+func TestClockSkewCondition(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Second)
+	nowFunc := func() time.Time { return now }
+	maxSkew := 5 * time.Minute
+
+	cases := []struct {
+		desc             string
+		node             *v1.Node
+		skew             time.Duration
+		err              error
+		expectConditions []v1.NodeCondition
+		expectEvents     []testEvent
+	}{
+		{
+			desc:             "new, in sync",
+			node:             &v1.Node{},
+			skew:             2 * time.Second,
+			expectConditions: []v1.NodeCondition{*makeClockSkewCondition(false, now, now, maxSkew, 0)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeNormal,
+					event:     "NodeHasSynchronizedClock",
+				},
+			},
+		},
+		{
+			desc:             "new, skewed ahead",
+			node:             &v1.Node{},
+			skew:             10 * time.Minute,
+			expectConditions: []v1.NodeCondition{*makeClockSkewCondition(true, now, now, maxSkew, 10*time.Minute)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeWarning,
+					event:     "NodeHasClockSkew",
+				},
+			},
+		},
+		{
+			desc:             "new, skewed behind",
+			node:             &v1.Node{},
+			skew:             -10 * time.Minute,
+			expectConditions: []v1.NodeCondition{*makeClockSkewCondition(true, now, now, maxSkew, -10*time.Minute)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeWarning,
+					event:     "NodeHasClockSkew",
+				},
+			},
+		},
+		{
+			desc: "transition back to sync",
+			node: &v1.Node{
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{*makeClockSkewCondition(true, before, before, maxSkew, 10*time.Minute)},
+				},
+			},
+			skew:             time.Second,
+			expectConditions: []v1.NodeCondition{*makeClockSkewCondition(false, now, now, maxSkew, 0)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeNormal,
+					event:     "NodeHasSynchronizedClock",
+				},
+			},
+		},
+		{
+			desc: "in sync, no transition",
+			node: &v1.Node{
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{*makeClockSkewCondition(false, before, before, maxSkew, 0)},
+				},
+			},
+			skew:             time.Second,
+			expectConditions: []v1.NodeCondition{*makeClockSkewCondition(false, before, now, maxSkew, 0)},
+			expectEvents:     []testEvent{},
+		},
+		{
+			desc: "measurement failure reports unknown",
+			node: &v1.Node{},
+			err:  fmt.Errorf("connection refused"),
+			expectConditions: []v1.NodeCondition{{
+				Type:               NodeClockSkew,
+				Status:             v1.ConditionUnknown,
+				Reason:             "ClockSkewUnknown",
+				Message:            "kubelet failed to measure clock skew: connection refused",
+				LastHeartbeatTime:  metav1.NewTime(now),
+				LastTransitionTime: metav1.NewTime(now),
+			}},
+			expectEvents: []testEvent{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			events := []testEvent{}
+			recordEventFunc := func(eventType, event string) {
+				events = append(events, testEvent{
+					eventType: eventType,
+					event:     event,
+				})
+			}
+			skewFunc := func(ctx context.Context) (time.Duration, error) {
+				return tc.skew, tc.err
+			}
+			// construct setter
+			setter := ClockSkewCondition(nowFunc, skewFunc, maxSkew, recordEventFunc)
+			// call setter on node
+			if err := setter(ctx, tc.node); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// check expected condition
+			assert.True(t, apiequality.Semantic.DeepEqual(tc.expectConditions, tc.node.Status.Conditions),
+				"Diff: %s", diff.ObjectDiff(tc.expectConditions, tc.node.Status.Conditions))
+			// check expected events
+			require.Equal(t, len(tc.expectEvents), len(events))
+			for i := range tc.expectEvents {
+				assert.Equal(t, tc.expectEvents[i], events[i])
+			}
+		})
+	}
+}
+
+func TestLowEntropyCondition(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Second)
+	nowFunc := func() time.Time { return now }
+	minEntropy := 200
+
+	cases := []struct {
+		desc             string
+		node             *v1.Node
+		entropy          int
+		err              error
+		expectConditions []v1.NodeCondition
+		expectEvents     []testEvent
+	}{
+		{
+			desc:             "new, sufficient entropy",
+			node:             &v1.Node{},
+			entropy:          256,
+			expectConditions: []v1.NodeCondition{*makeLowEntropyCondition(false, now, now, minEntropy, 256)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeNormal,
+					event:     "NodeHasSufficientEntropy",
+				},
+			},
+		},
+		{
+			desc:             "new, starved",
+			node:             &v1.Node{},
+			entropy:          12,
+			expectConditions: []v1.NodeCondition{*makeLowEntropyCondition(true, now, now, minEntropy, 12)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeWarning,
+					event:     "NodeHasLowEntropy",
+				},
+			},
+		},
+		{
+			desc: "transition back to sufficient",
+			node: &v1.Node{
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{*makeLowEntropyCondition(true, before, before, minEntropy, 12)},
+				},
+			},
+			entropy:          256,
+			expectConditions: []v1.NodeCondition{*makeLowEntropyCondition(false, now, now, minEntropy, 256)},
+			expectEvents: []testEvent{
+				{
+					eventType: v1.EventTypeNormal,
+					event:     "NodeHasSufficientEntropy",
+				},
+			},
+		},
+		{
+			desc: "sufficient, no transition",
+			node: &v1.Node{
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{*makeLowEntropyCondition(false, before, before, minEntropy, 256)},
+				},
+			},
+			entropy:          256,
+			expectConditions: []v1.NodeCondition{*makeLowEntropyCondition(false, before, now, minEntropy, 256)},
+			expectEvents:     []testEvent{},
+		},
+		{
+			desc: "read failure reports unknown",
+			node: &v1.Node{},
+			err:  fmt.Errorf("open /proc/sys/kernel/random/entropy_avail: no such file or directory"),
+			expectConditions: []v1.NodeCondition{{
+				Type:               NodeLowEntropy,
+				Status:             v1.ConditionUnknown,
+				Reason:             "EntropyUnknown",
+				Message:            "kubelet failed to read available entropy: open /proc/sys/kernel/random/entropy_avail: no such file or directory",
+				LastHeartbeatTime:  metav1.NewTime(now),
+				LastTransitionTime: metav1.NewTime(now),
+			}},
+			expectEvents: []testEvent{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			events := []testEvent{}
+			recordEventFunc := func(eventType, event string) {
+				events = append(events, testEvent{
+					eventType: eventType,
+					event:     event,
+				})
+			}
+			entropyFunc := func() (int, error) {
+				return tc.entropy, tc.err
+			}
+			// construct setter
+			setter := LowEntropyCondition(nowFunc, entropyFunc, minEntropy, recordEventFunc)
+			// call setter on node
+			if err := setter(ctx, tc.node); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// check expected condition
+			assert.True(t, apiequality.Semantic.DeepEqual(tc.expectConditions, tc.node.Status.Conditions),
+				"Diff: %s", diff.ObjectDiff(tc.expectConditions, tc.node.Status.Conditions))
+			// check expected events
+			require.Equal(t, len(tc.expectEvents), len(events))
+			for i := range tc.expectEvents {
+				assert.Equal(t, tc.expectEvents[i], events[i])
+			}
+		})
+	}
+}
+
+func makeClockSkewCondition(skewed bool, transition, heartbeat time.Time, maxSkew, skew time.Duration) *v1.NodeCondition {
+	if skewed {
+		return &v1.NodeCondition{
+			Type:               NodeClockSkew,
+			Status:             v1.ConditionTrue,
+			Reason:             "KubeletDetectedClockSkew",
+			Message:            fmt.Sprintf("node clock is skewed by %v relative to the apiserver, exceeding the %v tolerance", skew, maxSkew),
+			LastTransitionTime: metav1.NewTime(transition),
+			LastHeartbeatTime:  metav1.NewTime(heartbeat),
+		}
+	}
+	return &v1.NodeCondition{
+		Type:               NodeClockSkew,
+		Status:             v1.ConditionFalse,
+		Reason:             "KubeletHasSynchronizedClock",
+		Message:            fmt.Sprintf("node clock is within %v of the apiserver", maxSkew),
+		LastTransitionTime: metav1.NewTime(transition),
+		LastHeartbeatTime:  metav1.NewTime(heartbeat),
+	}
+}
+
+func makeLowEntropyCondition(starved bool, transition, heartbeat time.Time, minEntropy, entropy int) *v1.NodeCondition {
+	if starved {
+		return &v1.NodeCondition{
+			Type:               NodeLowEntropy,
+			Status:             v1.ConditionTrue,
+			Reason:             "KubeletDetectedLowEntropy",
+			Message:            fmt.Sprintf("node has %d bits of entropy available, below the %d bit minimum", entropy, minEntropy),
+			LastTransitionTime: metav1.NewTime(transition),
+			LastHeartbeatTime:  metav1.NewTime(heartbeat),
+		}
+	}
+	return &v1.NodeCondition{
+		Type:               NodeLowEntropy,
+		Status:             v1.ConditionFalse,
+		Reason:             "KubeletHasSufficientEntropy",
+		Message:            fmt.Sprintf("node has %d bits of entropy available", entropy),
+		LastTransitionTime: metav1.NewTime(transition),
+		LastHeartbeatTime:  metav1.NewTime(heartbeat),
+	}
+}
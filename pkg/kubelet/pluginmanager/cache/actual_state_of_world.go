@@ -81,6 +81,21 @@ type PluginInfo struct {
 	Timestamp  time.Time
 	Handler    PluginHandler
 	Name       string
+	// Capabilities is the list of registration protocol capabilities the
+	// plugin advertised at registration, filtered to the ones the kubelet
+	// supports.
+	Capabilities []string
+}
+
+// HasCapability returns true if the plugin advertised the named capability at
+// registration.
+func (info PluginInfo) HasCapability(capability string) bool {
+	for _, c := range info.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
 }
 
 func (asw *actualStateOfWorld) AddPlugin(pluginInfo PluginInfo) error {
@@ -113,10 +113,11 @@ func (og *operationGenerator) GenerateRegisterPluginFunc(
 		// We add the plugin to the actual state of world cache before calling a plugin consumer's Register handle
 		// so that if we receive a delete event during Register Plugin, we can process it as a DeRegister call.
 		err = actualStateOfWorldUpdater.AddPlugin(cache.PluginInfo{
-			SocketPath: socketPath,
-			Timestamp:  timestamp,
-			Handler:    handler,
-			Name:       infoResp.Name,
+			SocketPath:   socketPath,
+			Timestamp:    timestamp,
+			Handler:      handler,
+			Name:         infoResp.Name,
+			Capabilities: filterSupportedCapabilities(infoResp.Name, infoResp.Capabilities),
 		})
 		if err != nil {
 			klog.ErrorS(err, "RegisterPlugin error -- failed to add plugin", "path", socketPath)
@@ -142,6 +143,18 @@ func (og *operationGenerator) GenerateUnregisterPluginFunc(
 		if pluginInfo.Handler == nil {
 			return fmt.Errorf("UnregisterPlugin error -- failed to get plugin handler for %s", pluginInfo.SocketPath)
 		}
+		// Give plugins that asked for it a best-effort notification before the
+		// handler tears down its side of the registration.
+		if pluginInfo.HasCapability(registerapi.CapabilityGracefulDeregistration) {
+			if client, conn, err := dial(pluginInfo.SocketPath, dialTimeoutDuration); err != nil {
+				klog.V(4).InfoS("UnregisterPlugin -- failed to dial plugin for deregistration notification", "path", pluginInfo.SocketPath, "err", err)
+			} else {
+				if err := og.notifyPlugin(client, false, ""); err != nil {
+					klog.V(4).InfoS("UnregisterPlugin -- failed to notify plugin of deregistration", "path", pluginInfo.SocketPath, "err", err)
+				}
+				conn.Close()
+			}
+		}
 		// We remove the plugin to the actual state of world cache before calling a plugin consumer's Unregister handle
 		// so that if we receive a register event during Register Plugin, we can process it as a Register call.
 		actualStateOfWorldUpdater.RemovePlugin(pluginInfo.SocketPath)
@@ -154,6 +167,39 @@ func (og *operationGenerator) GenerateUnregisterPluginFunc(
 	return unregisterPluginFunc
 }
 
+// filterSupportedCapabilities drops capabilities this kubelet does not
+// understand, so only protocol extensions both sides support are exercised.
+func filterSupportedCapabilities(pluginName string, capabilities []string) []string {
+	var supported []string
+	for _, capability := range capabilities {
+		switch capability {
+		case registerapi.CapabilityHealthPing, registerapi.CapabilityGracefulDeregistration:
+			supported = append(supported, capability)
+		default:
+			klog.V(2).InfoS("Ignoring unsupported capability advertised by plugin", "pluginName", pluginName, "capability", capability)
+		}
+	}
+	return supported
+}
+
+// PingPlugin verifies that a plugin still serves the registration API on the
+// given socket by issuing a GetInfo call.
+func PingPlugin(socketPath string, timeout time.Duration) error {
+	client, conn, err := dial(socketPath, timeout)
+	if err != nil {
+		return fmt.Errorf("health ping of plugin at socket %s failed to dial: %v", socketPath, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if _, err := client.GetInfo(ctx, &registerapi.InfoRequest{}); err != nil {
+		return fmt.Errorf("health ping of plugin at socket %s failed: %v", socketPath, err)
+	}
+	return nil
+}
+
 func (og *operationGenerator) notifyPlugin(client registerapi.RegistrationClient, registered bool, errStr string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeoutDuration)
 	defer cancel()
@@ -20,17 +20,30 @@ limitations under the License.
 package reconciler
 
 import (
+	"os"
 	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
 	"k8s.io/kubernetes/pkg/kubelet/pluginmanager/cache"
 	"k8s.io/kubernetes/pkg/kubelet/pluginmanager/operationexecutor"
 	"k8s.io/kubernetes/pkg/util/goroutinemap"
 	"k8s.io/kubernetes/pkg/util/goroutinemap/exponentialbackoff"
 )
 
+const (
+	// healthPingInterval is the minimum amount of time between health pings of
+	// the same plugin.
+	healthPingInterval = 30 * time.Second
+	// healthPingTimeout bounds the dial and the GetInfo call of a single ping.
+	healthPingTimeout = 5 * time.Second
+	// healthPingMaxFailures is the number of consecutive failed pings after
+	// which a plugin is considered gone and gets deregistered.
+	healthPingMaxFailures = 3
+)
+
 // Reconciler runs a periodic loop to reconcile the desired state of the world
 // with the actual state of the world by triggering register and unregister
 // operations. Also provides a means to add a handler for a plugin type.
@@ -71,15 +84,27 @@ func NewReconciler(
 		desiredStateOfWorld: desiredStateOfWorld,
 		actualStateOfWorld:  actualStateOfWorld,
 		handlers:            make(map[string]cache.PluginHandler),
+		pingPlugin:          operationexecutor.PingPlugin,
+		pluginHealth:        make(map[string]*pluginHealthState),
 	}
 }
 
+// pluginHealthState tracks the health pings issued for one registered plugin.
+type pluginHealthState struct {
+	lastPing time.Time
+	failures int
+}
+
 type reconciler struct {
 	operationExecutor   operationexecutor.OperationExecutor
 	loopSleepDuration   time.Duration
 	desiredStateOfWorld cache.DesiredStateOfWorld
 	actualStateOfWorld  cache.ActualStateOfWorld
 	handlers            map[string]cache.PluginHandler
+	// pingPlugin probes a plugin's registration socket; it is a field so tests
+	// can substitute a fake.
+	pingPlugin   func(socketPath string, timeout time.Duration) error
+	pluginHealth map[string]*pluginHealthState
 	sync.RWMutex
 }
 
@@ -111,7 +136,66 @@ func (rc *reconciler) getHandlers() map[string]cache.PluginHandler {
 	return copyHandlers
 }
 
+// checkPluginHealth pings registered plugins that advertised the HealthPing
+// capability and removes plugins whose registration socket has gone stale, so
+// a plugin that died without cleaning up does not keep its registration and
+// socket around indefinitely.
+func (rc *reconciler) checkPluginHealth() {
+	rc.Lock()
+	defer rc.Unlock()
+
+	registeredPlugins := rc.actualStateOfWorld.GetRegisteredPlugins()
+
+	// Forget health state of plugins that are no longer registered.
+	registered := make(map[string]bool, len(registeredPlugins))
+	for _, plugin := range registeredPlugins {
+		registered[plugin.SocketPath] = true
+	}
+	for socketPath := range rc.pluginHealth {
+		if !registered[socketPath] {
+			delete(rc.pluginHealth, socketPath)
+		}
+	}
+
+	for _, plugin := range registeredPlugins {
+		if !plugin.HasCapability(registerapi.CapabilityHealthPing) {
+			continue
+		}
+
+		state, ok := rc.pluginHealth[plugin.SocketPath]
+		if !ok {
+			state = &pluginHealthState{}
+			rc.pluginHealth[plugin.SocketPath] = state
+		}
+		if time.Since(state.lastPing) < healthPingInterval {
+			continue
+		}
+		state.lastPing = time.Now()
+
+		if err := rc.pingPlugin(plugin.SocketPath, healthPingTimeout); err != nil {
+			state.failures++
+			klog.V(2).InfoS("Plugin failed a health ping", "plugin", plugin, "failures", state.failures, "err", err)
+			if state.failures < healthPingMaxFailures {
+				continue
+			}
+
+			klog.InfoS("Plugin failed consecutive health pings, deregistering it and removing its stale socket", "plugin", plugin, "failures", state.failures)
+			rc.desiredStateOfWorld.RemovePlugin(plugin.SocketPath)
+			if err := os.Remove(plugin.SocketPath); err != nil && !os.IsNotExist(err) {
+				klog.ErrorS(err, "Failed to remove stale plugin socket", "path", plugin.SocketPath)
+			}
+			delete(rc.pluginHealth, plugin.SocketPath)
+			continue
+		}
+		state.failures = 0
+	}
+}
+
 func (rc *reconciler) reconcile() {
+	// Health pings may remove stale plugins from the desired state of the
+	// world, so they run before unregistrations are computed.
+	rc.checkPluginHealth()
+
 	// Unregisterations are triggered before registrations
 
 	// Ensure plugins that should be unregistered are unregistered.
@@ -316,3 +316,80 @@ func Test_Run_Positive_ReRegister(t *testing.T) {
 		t.Fatalf("Test_Run_Positive_RegisterThenUnregister: expected\n%s\nin actual state of world, but got\n%v\n", socketPath, aswPlugins[0])
 	}
 }
+
+func Test_CheckPluginHealth_DeregistersStalePlugin(t *testing.T) {
+	defer cleanup(t)
+
+	dsw := cache.NewDesiredStateOfWorld()
+	asw := cache.NewActualStateOfWorld()
+	fakeRecorder := &record.FakeRecorder{}
+	oex := operationexecutor.NewOperationExecutor(operationexecutor.NewOperationGenerator(
+		fakeRecorder,
+	))
+	rc := NewReconciler(
+		oex,
+		reconcilerLoopSleepDuration,
+		dsw,
+		asw).(*reconciler)
+
+	pingErrs := make(map[string]int)
+	rc.pingPlugin = func(socketPath string, timeout time.Duration) error {
+		pingErrs[socketPath]++
+		return fmt.Errorf("connection refused")
+	}
+
+	socketPath := filepath.Join(socketDir, "stale-plugin.sock")
+	require.NoError(t, dsw.AddOrUpdatePlugin(socketPath))
+	require.NoError(t, asw.AddPlugin(cache.PluginInfo{
+		SocketPath:   socketPath,
+		Timestamp:    time.Now(),
+		Name:         "stale-plugin",
+		Capabilities: []string{registerapi.CapabilityHealthPing},
+	}))
+
+	for i := 0; i < healthPingMaxFailures; i++ {
+		rc.checkPluginHealth()
+		// bypass the ping interval for the next iteration
+		if state, ok := rc.pluginHealth[socketPath]; ok {
+			state.lastPing = time.Time{}
+		}
+	}
+
+	require.Equal(t, healthPingMaxFailures, pingErrs[socketPath])
+	require.False(t, dsw.PluginExists(socketPath), "plugin that failed consecutive health pings should be removed from the desired state of the world")
+}
+
+func Test_CheckPluginHealth_SkipsPluginsWithoutCapability(t *testing.T) {
+	defer cleanup(t)
+
+	dsw := cache.NewDesiredStateOfWorld()
+	asw := cache.NewActualStateOfWorld()
+	fakeRecorder := &record.FakeRecorder{}
+	oex := operationexecutor.NewOperationExecutor(operationexecutor.NewOperationGenerator(
+		fakeRecorder,
+	))
+	rc := NewReconciler(
+		oex,
+		reconcilerLoopSleepDuration,
+		dsw,
+		asw).(*reconciler)
+
+	pinged := false
+	rc.pingPlugin = func(socketPath string, timeout time.Duration) error {
+		pinged = true
+		return fmt.Errorf("connection refused")
+	}
+
+	socketPath := filepath.Join(socketDir, "legacy-plugin.sock")
+	require.NoError(t, dsw.AddOrUpdatePlugin(socketPath))
+	require.NoError(t, asw.AddPlugin(cache.PluginInfo{
+		SocketPath: socketPath,
+		Timestamp:  time.Now(),
+		Name:       "legacy-plugin",
+	}))
+
+	rc.checkPluginHealth()
+
+	require.False(t, pinged, "plugins that did not advertise the HealthPing capability should not be pinged")
+	require.True(t, dsw.PluginExists(socketPath))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policycache
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
+)
+
+const (
+	// UnknownPriorityClass is the reason used when a pod references a priority
+	// class that is neither built in nor in the local cache.
+	UnknownPriorityClass = "UnknownPriorityClass"
+	// UnknownRuntimeClass is the reason used when a pod references a runtime
+	// class that is not in the local cache.
+	UnknownRuntimeClass = "UnknownRuntimeClass"
+)
+
+// admissionHandler validates pod references to cached policies. It only
+// enforces once the cache has data for the corresponding policy type, so a
+// kubelet that has never reached the API server keeps its previous admission
+// behavior.
+type admissionHandler struct {
+	cache *Cache
+}
+
+var _ lifecycle.PodAdmitHandler = &admissionHandler{}
+
+// NewAdmissionHandler returns a PodAdmitHandler backed by the local policy
+// cache. Pods admitted through the API server already carry a resolved
+// priority, so in practice this only affects static pods and admissions that
+// happen while the API server is unreachable.
+func NewAdmissionHandler(cache *Cache) lifecycle.PodAdmitHandler {
+	return &admissionHandler{cache: cache}
+}
+
+func (a *admissionHandler) Admit(attrs *lifecycle.PodAdmitAttributes) lifecycle.PodAdmitResult {
+	pod := attrs.Pod
+
+	// Priority is resolved from the class name at API admission time; only
+	// pods that bypassed the API server still need the reference checked.
+	if pod.Spec.Priority == nil && len(pod.Spec.PriorityClassName) > 0 && !isSystemPriorityClass(pod.Spec.PriorityClassName) && a.cache.HasPriorityClasses() {
+		if _, ok := a.cache.GetPriorityClass(pod.Spec.PriorityClassName); !ok {
+			return lifecycle.PodAdmitResult{
+				Admit:   false,
+				Reason:  UnknownPriorityClass,
+				Message: fmt.Sprintf("pod references priority class %q which is not in the local policy cache", pod.Spec.PriorityClassName),
+			}
+		}
+	}
+
+	if pod.Spec.RuntimeClassName != nil && len(*pod.Spec.RuntimeClassName) > 0 && a.cache.HasRuntimeClasses() {
+		if _, ok := a.cache.GetRuntimeClass(*pod.Spec.RuntimeClassName); !ok {
+			return lifecycle.PodAdmitResult{
+				Admit:   false,
+				Reason:  UnknownRuntimeClass,
+				Message: fmt.Sprintf("pod references runtime class %q which is not in the local policy cache", *pod.Spec.RuntimeClassName),
+			}
+		}
+	}
+
+	return lifecycle.PodAdmitResult{Admit: true}
+}
+
+// isSystemPriorityClass reports whether name refers to one of the priority
+// classes that exist in every cluster without an API object.
+func isSystemPriorityClass(name string) bool {
+	return name == scheduling.SystemNodeCritical || name == scheduling.SystemClusterCritical
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policycache caches admission-relevant cluster policies (priority
+// classes and runtime classes) on local disk so that pod admission keeps
+// behaving consistently across kubelet restarts while the API server is
+// unreachable. The cache is refreshed from the API server whenever a client
+// is available and is otherwise served from the last persisted state.
+package policycache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	nodev1 "k8s.io/api/node/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultSyncPeriod is how often the cache is refreshed from the API server.
+	DefaultSyncPeriod = 5 * time.Minute
+
+	priorityClassesFile = "priority-classes.json"
+	runtimeClassesFile  = "runtime-classes.json"
+)
+
+// state is the serialized form of the cache.
+type state struct {
+	PriorityClasses []schedulingv1.PriorityClass `json:"priorityClasses,omitempty"`
+	RuntimeClasses  []nodev1.RuntimeClass        `json:"runtimeClasses,omitempty"`
+}
+
+// Cache holds the last known priority classes and runtime classes, persisted
+// below stateDir. The zero state before the first successful sync or load is
+// explicitly tracked so callers can avoid enforcing against an empty cache.
+type Cache struct {
+	stateDir string
+
+	lock                  sync.RWMutex
+	priorityClasses       map[string]*schedulingv1.PriorityClass
+	runtimeClasses        map[string]*nodev1.RuntimeClass
+	priorityClassesSynced bool
+	runtimeClassesSynced  bool
+}
+
+// NewCache returns a cache persisting its state below stateDir. Call Load to
+// pick up state persisted by a previous kubelet process.
+func NewCache(stateDir string) *Cache {
+	return &Cache{
+		stateDir:        stateDir,
+		priorityClasses: map[string]*schedulingv1.PriorityClass{},
+		runtimeClasses:  map[string]*nodev1.RuntimeClass{},
+	}
+}
+
+// Load reads the persisted state from disk. Missing state files are not an
+// error; the corresponding policies simply stay unsynced until the first
+// successful refresh from the API server.
+func (c *Cache) Load() error {
+	persisted := state{}
+	foundPriorityClasses, err := c.loadFile(priorityClassesFile, &persisted.PriorityClasses)
+	if err != nil {
+		return err
+	}
+	foundRuntimeClasses, err := c.loadFile(runtimeClassesFile, &persisted.RuntimeClasses)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if foundPriorityClasses {
+		c.setPriorityClassesLocked(persisted.PriorityClasses)
+	}
+	if foundRuntimeClasses {
+		c.setRuntimeClassesLocked(persisted.RuntimeClasses)
+	}
+	return nil
+}
+
+// Run refreshes the cache from the API server every period until stopCh is
+// closed.
+func (c *Cache) Run(client clientset.Interface, period time.Duration, stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := c.Sync(context.TODO(), client); err != nil {
+			klog.ErrorS(err, "Failed to refresh local policy cache")
+		}
+	}, period, stopCh)
+}
+
+// Sync lists priority classes and runtime classes from the API server and
+// persists them. On error the previously cached state stays in effect.
+func (c *Cache) Sync(ctx context.Context, client clientset.Interface) error {
+	priorityClasses, err := client.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list priority classes: %w", err)
+	}
+	runtimeClasses, err := client.NodeV1().RuntimeClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list runtime classes: %w", err)
+	}
+
+	c.lock.Lock()
+	c.setPriorityClassesLocked(priorityClasses.Items)
+	c.setRuntimeClassesLocked(runtimeClasses.Items)
+	c.lock.Unlock()
+
+	if err := c.saveFile(priorityClassesFile, priorityClasses.Items); err != nil {
+		return err
+	}
+	return c.saveFile(runtimeClassesFile, runtimeClasses.Items)
+}
+
+// GetPriorityClass returns the cached priority class with the given name.
+func (c *Cache) GetPriorityClass(name string) (*schedulingv1.PriorityClass, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	pc, ok := c.priorityClasses[name]
+	return pc, ok
+}
+
+// GetRuntimeClass returns the cached runtime class with the given name.
+func (c *Cache) GetRuntimeClass(name string) (*nodev1.RuntimeClass, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	rc, ok := c.runtimeClasses[name]
+	return rc, ok
+}
+
+// HasPriorityClasses reports whether the cache holds priority class data from
+// a sync or a previous process. Admission must not enforce priority class
+// existence before this is true.
+func (c *Cache) HasPriorityClasses() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.priorityClassesSynced
+}
+
+// HasRuntimeClasses reports whether the cache holds runtime class data from a
+// sync or a previous process.
+func (c *Cache) HasRuntimeClasses() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.runtimeClassesSynced
+}
+
+func (c *Cache) setPriorityClassesLocked(priorityClasses []schedulingv1.PriorityClass) {
+	c.priorityClasses = make(map[string]*schedulingv1.PriorityClass, len(priorityClasses))
+	for i := range priorityClasses {
+		c.priorityClasses[priorityClasses[i].Name] = &priorityClasses[i]
+	}
+	c.priorityClassesSynced = true
+}
+
+func (c *Cache) setRuntimeClassesLocked(runtimeClasses []nodev1.RuntimeClass) {
+	c.runtimeClasses = make(map[string]*nodev1.RuntimeClass, len(runtimeClasses))
+	for i := range runtimeClasses {
+		c.runtimeClasses[runtimeClasses[i].Name] = &runtimeClasses[i]
+	}
+	c.runtimeClassesSynced = true
+}
+
+// loadFile reads one state file, reporting whether it exists.
+func (c *Cache) loadFile(name string, into interface{}) (bool, error) {
+	path := filepath.Join(c.stateDir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read policy cache file %q: %w", path, err)
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return false, fmt.Errorf("failed to parse policy cache file %q: %w", path, err)
+	}
+	return true, nil
+}
+
+// saveFile writes one state file atomically.
+func (c *Cache) saveFile(name string, obj interface{}) error {
+	if err := os.MkdirAll(c.stateDir, 0700); err != nil {
+		return fmt.Errorf("failed to create policy cache directory %q: %w", c.stateDir, err)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to encode policy cache file %q: %w", name, err)
+	}
+	path := filepath.Join(c.stateDir, name)
+	tmp, err := os.CreateTemp(c.stateDir, "."+name)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policycache
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
+)
+
+func testPolicies() (*schedulingv1.PriorityClass, *nodev1.RuntimeClass) {
+	pc := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "high-priority"},
+		Value:      1000,
+	}
+	rc := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gvisor"},
+		Handler:    "runsc",
+	}
+	return pc, rc
+}
+
+func TestSyncAndPersistence(t *testing.T) {
+	stateDir := t.TempDir()
+	pc, rc := testPolicies()
+	client := fake.NewSimpleClientset(pc, rc)
+
+	cache := NewCache(stateDir)
+	if cache.HasPriorityClasses() || cache.HasRuntimeClasses() {
+		t.Fatal("fresh cache should not report synced data")
+	}
+	if err := cache.Sync(context.TODO(), client); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if _, ok := cache.GetPriorityClass("high-priority"); !ok {
+		t.Error("priority class missing after sync")
+	}
+	if _, ok := cache.GetRuntimeClass("gvisor"); !ok {
+		t.Error("runtime class missing after sync")
+	}
+
+	// A fresh cache over the same state dir simulates a kubelet restart with
+	// the API server unreachable.
+	restarted := NewCache(stateDir)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !restarted.HasPriorityClasses() || !restarted.HasRuntimeClasses() {
+		t.Fatal("restarted cache should report synced data")
+	}
+	got, ok := restarted.GetPriorityClass("high-priority")
+	if !ok {
+		t.Fatal("priority class missing after restart")
+	}
+	if got.Value != 1000 {
+		t.Errorf("priority class value = %d, want 1000", got.Value)
+	}
+	if _, ok := restarted.GetRuntimeClass("gvisor"); !ok {
+		t.Error("runtime class missing after restart")
+	}
+}
+
+func TestLoadMissingState(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	if err := cache.Load(); err != nil {
+		t.Fatalf("Load of empty state dir failed: %v", err)
+	}
+	if cache.HasPriorityClasses() || cache.HasRuntimeClasses() {
+		t.Fatal("cache without state files should not report synced data")
+	}
+}
+
+func TestAdmissionHandler(t *testing.T) {
+	priority := int32(1000)
+	gvisor := "gvisor"
+	unknown := "unknown"
+
+	testCases := []struct {
+		desc       string
+		synced     bool
+		pod        *v1.Pod
+		wantAdmit  bool
+		wantReason string
+	}{
+		{
+			desc:      "no policy references",
+			synced:    true,
+			pod:       &v1.Pod{},
+			wantAdmit: true,
+		},
+		{
+			desc:      "known priority class",
+			synced:    true,
+			pod:       &v1.Pod{Spec: v1.PodSpec{PriorityClassName: "high-priority"}},
+			wantAdmit: true,
+		},
+		{
+			desc:       "unknown priority class",
+			synced:     true,
+			pod:        &v1.Pod{Spec: v1.PodSpec{PriorityClassName: "unknown"}},
+			wantAdmit:  false,
+			wantReason: UnknownPriorityClass,
+		},
+		{
+			desc:      "unknown priority class with resolved priority",
+			synced:    true,
+			pod:       &v1.Pod{Spec: v1.PodSpec{PriorityClassName: "unknown", Priority: &priority}},
+			wantAdmit: true,
+		},
+		{
+			desc:      "system priority class is always known",
+			synced:    true,
+			pod:       &v1.Pod{Spec: v1.PodSpec{PriorityClassName: "system-node-critical"}},
+			wantAdmit: true,
+		},
+		{
+			desc:      "known runtime class",
+			synced:    true,
+			pod:       &v1.Pod{Spec: v1.PodSpec{RuntimeClassName: &gvisor}},
+			wantAdmit: true,
+		},
+		{
+			desc:       "unknown runtime class",
+			synced:     true,
+			pod:        &v1.Pod{Spec: v1.PodSpec{RuntimeClassName: &unknown}},
+			wantAdmit:  false,
+			wantReason: UnknownRuntimeClass,
+		},
+		{
+			desc:      "never synced cache does not enforce",
+			synced:    false,
+			pod:       &v1.Pod{Spec: v1.PodSpec{PriorityClassName: "unknown", RuntimeClassName: &unknown}},
+			wantAdmit: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			cache := NewCache(t.TempDir())
+			if tt.synced {
+				pc, rc := testPolicies()
+				if err := cache.Sync(context.TODO(), fake.NewSimpleClientset(pc, rc)); err != nil {
+					t.Fatalf("Sync failed: %v", err)
+				}
+			}
+			result := NewAdmissionHandler(cache).Admit(&lifecycle.PodAdmitAttributes{Pod: tt.pod})
+			if result.Admit != tt.wantAdmit {
+				t.Fatalf("Admit = %v (reason %q), want %v", result.Admit, result.Reason, tt.wantAdmit)
+			}
+			if !tt.wantAdmit && result.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -25,6 +25,28 @@ import (
 	nodev1 "k8s.io/client-go/listers/node/v1"
 )
 
+// SandboxReusePolicy controls whether the kubelet and the runtime keep the pod
+// sandbox (and with it the network namespace and IP) when every container in
+// the pod is restarted.
+type SandboxReusePolicy string
+
+const (
+	// SandboxReusePolicyAnnotation is the annotation on a RuntimeClass object
+	// that selects the sandbox reuse policy for pods running with that class.
+	// The value is also passed to the runtime on the sandbox config so that
+	// runtimes which tear down the sandbox when its containers exit can retain
+	// the network namespace and IP for the replacement sandbox.
+	SandboxReusePolicyAnnotation = "runtimeclass.kubernetes.io/sandbox-reuse-policy"
+
+	// SandboxReuseNever recreates the sandbox whenever it needs to be
+	// reconciled. This is the default and matches the historical behavior.
+	SandboxReuseNever SandboxReusePolicy = "Never"
+	// SandboxReuseOnContainerRestart keeps an existing ready sandbox across
+	// full container restarts within the pod lifetime, avoiding IP churn for
+	// crashlooping pods.
+	SandboxReuseOnContainerRestart SandboxReusePolicy = "OnContainerRestart"
+)
+
 // Manager caches RuntimeClass API objects, and provides accessors to the Kubelet.
 type Manager struct {
 	informerFactory informers.SharedInformerFactory
@@ -76,3 +98,32 @@ func (m *Manager) LookupRuntimeHandler(runtimeClassName *string) (string, error)
 
 	return rc.Handler, nil
 }
+
+// LookupSandboxReusePolicy returns the sandbox reuse policy selected by the
+// given RuntimeClass name (or SandboxReuseNever for nil, the empty name, or a
+// class without the annotation). If the RuntimeClass is not found or carries an
+// invalid policy, it returns an error.
+func (m *Manager) LookupSandboxReusePolicy(runtimeClassName *string) (SandboxReusePolicy, error) {
+	if runtimeClassName == nil || *runtimeClassName == "" {
+		return SandboxReuseNever, nil
+	}
+
+	name := *runtimeClassName
+
+	rc, err := m.lister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return SandboxReuseNever, err
+		}
+		return SandboxReuseNever, fmt.Errorf("failed to lookup RuntimeClass %s: %v", name, err)
+	}
+
+	switch policy := SandboxReusePolicy(rc.Annotations[SandboxReusePolicyAnnotation]); policy {
+	case "", SandboxReuseNever:
+		return SandboxReuseNever, nil
+	case SandboxReuseOnContainerRestart:
+		return policy, nil
+	default:
+		return SandboxReuseNever, fmt.Errorf("RuntimeClass %s has invalid %s annotation %q", name, SandboxReusePolicyAnnotation, policy)
+	}
+}
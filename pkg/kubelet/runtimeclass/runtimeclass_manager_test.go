@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kubernetes/pkg/kubelet/runtimeclass"
 	rctest "k8s.io/kubernetes/pkg/kubelet/runtimeclass/testing"
 	"k8s.io/utils/pointer"
@@ -58,3 +59,49 @@ func TestLookupRuntimeHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupSandboxReusePolicy(t *testing.T) {
+	reuse := rctest.NewRuntimeClass("reuse", rctest.SandboxRuntimeHandler)
+	reuse.Annotations = map[string]string{
+		runtimeclass.SandboxReusePolicyAnnotation: string(runtimeclass.SandboxReuseOnContainerRestart),
+	}
+	invalid := rctest.NewRuntimeClass("invalid-reuse", rctest.SandboxRuntimeHandler)
+	invalid.Annotations = map[string]string{
+		runtimeclass.SandboxReusePolicyAnnotation: "Sometimes",
+	}
+
+	tests := []struct {
+		rcn         *string
+		expected    runtimeclass.SandboxReusePolicy
+		expectError bool
+	}{
+		{rcn: nil, expected: runtimeclass.SandboxReuseNever},
+		{rcn: pointer.StringPtr(""), expected: runtimeclass.SandboxReuseNever},
+		{rcn: pointer.StringPtr(rctest.SandboxRuntimeClass), expected: runtimeclass.SandboxReuseNever},
+		{rcn: pointer.StringPtr("reuse"), expected: runtimeclass.SandboxReuseOnContainerRestart},
+		{rcn: pointer.StringPtr("invalid-reuse"), expectError: true},
+		{rcn: pointer.StringPtr("phantom"), expectError: true},
+	}
+
+	client := fake.NewSimpleClientset(
+		rctest.NewRuntimeClass(rctest.SandboxRuntimeClass, rctest.SandboxRuntimeHandler),
+		reuse, invalid)
+	manager := runtimeclass.NewManager(client)
+	defer rctest.StartManagerSync(manager)()
+
+	for _, test := range tests {
+		tname := "nil"
+		if test.rcn != nil {
+			tname = *test.rcn
+		}
+		t.Run(fmt.Sprintf("%q->%q(err:%v)", tname, test.expected, test.expectError), func(t *testing.T) {
+			policy, err := manager.LookupSandboxReusePolicy(test.rcn)
+			if test.expectError {
+				assert.Error(t, err, "policy=%q", policy)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, policy)
+			}
+		})
+	}
+}
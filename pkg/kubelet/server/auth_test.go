@@ -113,8 +113,11 @@ func AuthzTestCases() []AuthzTestCase {
 		"/checkpoint/{podNamespace}/{podID}/{containerName}":   "proxy",
 		"/configz": "proxy",
 		"/containerLogs/{podNamespace}/{podID}/{containerName}": "proxy",
-		"/debug/flags/v":                                     "proxy",
-		"/debug/pprof/{subpath:*}":                           "proxy",
+		"/cri/containers":          "proxy",
+		"/cri/imagefsinfo":         "proxy",
+		"/cri/sandboxes":           "proxy",
+		"/debug/flags/v":           "proxy",
+		"/debug/pprof/{subpath:*}": "proxy",
 		"/exec/{podNamespace}/{podID}/{containerName}":       "proxy",
 		"/exec/{podNamespace}/{podID}/{uid}/{containerName}": "proxy",
 		"/healthz":                            "proxy",
@@ -19,6 +19,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -252,6 +253,9 @@ type HostInterface interface {
 	GetPortForward(ctx context.Context, podName, podNamespace string, podUID types.UID, portForwardOpts portforward.V4Options) (*url.URL, error)
 	ListMetricDescriptors(ctx context.Context) ([]*runtimeapi.MetricDescriptor, error)
 	ListPodSandboxMetrics(ctx context.Context) ([]*runtimeapi.PodSandboxMetrics, error)
+	ListRuntimeContainers(ctx context.Context) ([]*runtimeapi.Container, error)
+	ListRuntimePodSandboxes(ctx context.Context) ([]*runtimeapi.PodSandbox, error)
+	RuntimeImageFsInfo(ctx context.Context) ([]*runtimeapi.FilesystemUsage, error)
 }
 
 // NewServer initializes and configures a kubelet.Server object to handle HTTP requests.
@@ -538,6 +542,24 @@ func (s *Server) InstallDebuggingHandlers() {
 		To(s.getRunningPods).
 		Operation("getRunningPods"))
 	s.restfulCont.Add(ws)
+
+	// The /cri endpoints mirror the read-only crictl operations so node
+	// debugging does not require access to the runtime socket.
+	s.addMetricsBucketMatcher("cri")
+	ws = new(restful.WebService)
+	ws.
+		Path("/cri/").
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("containers").
+		To(s.getCRIContainers).
+		Operation("getCRIContainers"))
+	ws.Route(ws.GET("sandboxes").
+		To(s.getCRISandboxes).
+		Operation("getCRISandboxes"))
+	ws.Route(ws.GET("imagefsinfo").
+		To(s.getCRIImageFsInfo).
+		Operation("getCRIImageFsInfo"))
+	s.restfulCont.Add(ws)
 }
 
 // InstallDebuggingDisabledHandlers registers the HTTP request patterns that provide better error message
@@ -552,11 +574,12 @@ func (s *Server) InstallDebuggingDisabledHandlers() {
 	s.addMetricsBucketMatcher("portForward")
 	s.addMetricsBucketMatcher("containerLogs")
 	s.addMetricsBucketMatcher("runningpods")
+	s.addMetricsBucketMatcher("cri")
 	s.addMetricsBucketMatcher("pprof")
 	s.addMetricsBucketMatcher("logs")
 	paths := []string{
 		"/run/", "/exec/", "/attach/", "/portForward/", "/containerLogs/",
-		"/runningpods/", pprofBasePath, logsPath}
+		"/runningpods/", "/cri/", pprofBasePath, logsPath}
 	for _, p := range paths {
 		s.restfulCont.Handle(p, h)
 	}
@@ -758,6 +781,49 @@ func (s *Server) getRunningPods(request *restful.Request, response *restful.Resp
 	writeJSONResponse(response, data)
 }
 
+// getCRIContainers returns the containers known to the container runtime, as
+// reported by the runtime itself.
+func (s *Server) getCRIContainers(request *restful.Request, response *restful.Response) {
+	containers, err := s.host.ListRuntimeContainers(request.Request.Context())
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	writeCRIResponse(response, "containers", containers)
+}
+
+// getCRISandboxes returns the pod sandboxes known to the container runtime.
+func (s *Server) getCRISandboxes(request *restful.Request, response *restful.Response) {
+	sandboxes, err := s.host.ListRuntimePodSandboxes(request.Request.Context())
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	writeCRIResponse(response, "sandboxes", sandboxes)
+}
+
+// getCRIImageFsInfo returns usage information for the filesystems the
+// container runtime stores images on.
+func (s *Server) getCRIImageFsInfo(request *restful.Request, response *restful.Response) {
+	filesystems, err := s.host.RuntimeImageFsInfo(request.Request.Context())
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	writeCRIResponse(response, "imageFilesystems", filesystems)
+}
+
+// writeCRIResponse writes items as a JSON object with a single named list, so
+// the payload shape matches the corresponding CRI response message.
+func writeCRIResponse(response *restful.Response, field string, items interface{}) {
+	data, err := json.Marshal(map[string]interface{}{field: items})
+	if err != nil {
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	writeJSONResponse(response, data)
+}
+
 // getLogs handles logs requests against the Kubelet.
 func (s *Server) getLogs(request *restful.Request, response *restful.Response) {
 	s.host.ServeLogs(response, request.Request)
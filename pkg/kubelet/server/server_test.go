@@ -164,6 +164,18 @@ func (fk *fakeKubelet) ListPodSandboxMetrics(ctx context.Context) ([]*runtimeapi
 	return nil, nil
 }
 
+func (fk *fakeKubelet) ListRuntimeContainers(ctx context.Context) ([]*runtimeapi.Container, error) {
+	return nil, nil
+}
+
+func (fk *fakeKubelet) ListRuntimePodSandboxes(ctx context.Context) ([]*runtimeapi.PodSandbox, error) {
+	return nil, nil
+}
+
+func (fk *fakeKubelet) RuntimeImageFsInfo(ctx context.Context) ([]*runtimeapi.FilesystemUsage, error) {
+	return nil, nil
+}
+
 type fakeRuntime struct {
 	execFunc        func(string, []string, io.Reader, io.WriteCloser, io.WriteCloser, bool, <-chan remotecommand.TerminalSize) error
 	attachFunc      func(string, io.Reader, io.WriteCloser, io.WriteCloser, bool, <-chan remotecommand.TerminalSize) error
@@ -0,0 +1,397 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package standalone implements the local manifest API served by the kubelet
+// when it runs in standalone mode, i.e. without an API server. The API is
+// served over a unix socket and offers CRUD on the static pod manifests in the
+// kubelet's static pod path; authorization is delegated to file permissions on
+// the socket.
+package standalone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	// Ensure that core apis are installed
+	_ "k8s.io/kubernetes/pkg/apis/core/install"
+	k8s_api_v1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	"k8s.io/kubernetes/pkg/apis/core/validation"
+	utilio "k8s.io/utils/io"
+)
+
+const (
+	// maxManifestLength is the longest manifest the server will accept, matching
+	// the limit the file source applies when reading the static pod path.
+	maxManifestLength = 10 * 1 << 20 // 10MB
+
+	// statusSyncPeriod is how often observed pod statuses are persisted to disk.
+	statusSyncPeriod = 10 * time.Second
+)
+
+// PodsProvider knows how to list the pods the kubelet is running together
+// with their most recent status.
+type PodsProvider interface {
+	GetPods() []*v1.Pod
+}
+
+// Server serves the local manifest API over a unix socket. Writes are applied
+// to the kubelet's static pod path, where the file source picks them up like
+// any other static pod manifest.
+type Server struct {
+	socketPath  string
+	manifestDir string
+	provider    PodsProvider
+	statusStore *StatusStore
+}
+
+// NewServer returns a local manifest API server that manages manifests below
+// manifestDir. provider and statusStore are optional; when set they back the
+// pod status endpoint, with statusStore holding the last status observed
+// before a restart.
+func NewServer(socketPath, manifestDir string, provider PodsProvider, statusStore *StatusStore) *Server {
+	return &Server{
+		socketPath:  socketPath,
+		manifestDir: manifestDir,
+		provider:    provider,
+		statusStore: statusStore,
+	}
+}
+
+// ListenAndServe serves the local manifest API until stopCh is closed. The
+// socket is created with mode 0600 so that only the owner of the kubelet
+// process can use the API.
+func (s *Server) ListenAndServe(stopCh <-chan struct{}) error {
+	if err := os.MkdirAll(s.manifestDir, 0750); err != nil {
+		return fmt.Errorf("failed to create manifest directory %q: %w", s.manifestDir, err)
+	}
+	// Clean up a socket left behind by a previous kubelet process.
+	if err := os.Remove(s.socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket %q: %w", s.socketPath, err)
+	}
+
+	l, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", s.socketPath, err)
+	}
+	if err := os.Chmod(s.socketPath, 0600); err != nil {
+		l.Close()
+		return fmt.Errorf("failed to set permissions on %q: %w", s.socketPath, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pods", s.handlePods)
+	mux.HandleFunc("/pods/", s.handlePod)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-stopCh
+		server.Close()
+	}()
+	if s.provider != nil && s.statusStore != nil {
+		go s.syncStatuses(stopCh)
+	}
+
+	klog.InfoS("Serving local manifest API", "socket", s.socketPath, "manifestDir", s.manifestDir)
+	if err := server.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// syncStatuses periodically persists the statuses of all known pods so they
+// survive a kubelet restart.
+func (s *Server) syncStatuses(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(statusSyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			for _, pod := range s.provider.GetPods() {
+				s.statusStore.SetPodStatus(pod.Namespace, pod.Name, pod.Status)
+			}
+			if err := s.statusStore.Save(); err != nil {
+				klog.ErrorS(err, "Failed to persist pod statuses")
+			}
+		}
+	}
+}
+
+// handlePods serves the manifest collection: GET lists all manifests, POST and
+// PUT create or update the manifest for the pod in the request body.
+func (s *Server) handlePods(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		pods, err := s.listPods()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.writeObject(w, http.StatusOK, pods)
+	case http.MethodPost, http.MethodPut:
+		s.createOrUpdatePod(w, req)
+	default:
+		http.Error(w, fmt.Sprintf("method %q not allowed", req.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePod serves a single manifest addressed as /pods/<namespace>/<name>,
+// plus the pod's last observed status at /pods/<namespace>/<name>/status.
+func (s *Server) handlePod(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/pods/"), "/"), "/")
+	wantStatus := false
+	if len(parts) == 3 && parts[2] == "status" {
+		wantStatus = true
+		parts = parts[:2]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, "expected /pods/<namespace>/<name>", http.StatusNotFound)
+		return
+	}
+	namespace, name := parts[0], parts[1]
+
+	if wantStatus {
+		if req.Method != http.MethodGet {
+			http.Error(w, fmt.Sprintf("method %q not allowed", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		s.getPodStatus(w, namespace, name)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		pod, err := s.getPod(namespace, name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if pod == nil {
+			http.Error(w, fmt.Sprintf("pod %s/%s not found", namespace, name), http.StatusNotFound)
+			return
+		}
+		s.writeObject(w, http.StatusOK, pod)
+	case http.MethodDelete:
+		s.deletePod(w, namespace, name)
+	default:
+		http.Error(w, fmt.Sprintf("method %q not allowed", req.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) createOrUpdatePod(w http.ResponseWriter, req *http.Request) {
+	data, err := utilio.ReadAtMost(req.Body, maxManifestLength)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read manifest: %v", err), http.StatusBadRequest)
+		return
+	}
+	pod, err := decodePod(data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid manifest: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	path := s.manifestPath(pod.Namespace, pod.Name)
+	existed := true
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		existed = false
+	}
+	if err := writeFileAtomically(path, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to write manifest: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	klog.V(2).InfoS("Local manifest API wrote manifest", "pod", klog.KObj(pod), "path", path)
+	if existed {
+		s.writeObject(w, http.StatusOK, pod)
+	} else {
+		s.writeObject(w, http.StatusCreated, pod)
+	}
+}
+
+func (s *Server) deletePod(w http.ResponseWriter, namespace, name string) {
+	path := s.manifestPath(namespace, name)
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, fmt.Sprintf("pod %s/%s not found", namespace, name), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to remove manifest: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if s.statusStore != nil {
+		s.statusStore.DeletePodStatus(namespace, name)
+		if err := s.statusStore.Save(); err != nil {
+			klog.ErrorS(err, "Failed to persist pod statuses")
+		}
+	}
+	klog.V(2).InfoS("Local manifest API removed manifest", "pod", klog.KRef(namespace, name), "path", path)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getPodStatus(w http.ResponseWriter, namespace, name string) {
+	if s.provider != nil {
+		for _, pod := range s.provider.GetPods() {
+			// Static pod names are suffixed with the node name, so match on prefix.
+			if pod.Namespace == namespace && (pod.Name == name || strings.HasPrefix(pod.Name, name+"-")) {
+				s.writeJSON(w, http.StatusOK, &pod.Status)
+				return
+			}
+		}
+	}
+	if s.statusStore != nil {
+		if status, ok := s.statusStore.GetPodStatus(namespace, name); ok {
+			s.writeJSON(w, http.StatusOK, &status)
+			return
+		}
+	}
+	http.Error(w, fmt.Sprintf("no status known for pod %s/%s", namespace, name), http.StatusNotFound)
+}
+
+// listPods decodes every manifest in the manifest directory. Unparseable files
+// are skipped with a log message rather than failing the whole list, matching
+// the tolerance of the file source.
+func (s *Server) listPods() (*v1.PodList, error) {
+	entries, err := os.ReadDir(s.manifestDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest directory: %w", err)
+	}
+	pods := &v1.PodList{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.manifestDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pod, err := decodePod(data)
+		if err != nil {
+			klog.InfoS("Skipping unparseable manifest", "path", filepath.Join(s.manifestDir, entry.Name()), "err", err)
+			continue
+		}
+		pods.Items = append(pods.Items, *pod)
+	}
+	return pods, nil
+}
+
+// getPod returns the decoded manifest for the given pod, or nil if no
+// manifest names it.
+func (s *Server) getPod(namespace, name string) (*v1.Pod, error) {
+	pods, err := s.listPods()
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Namespace == namespace && pods.Items[i].Name == name {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// manifestPath returns the file that manifests written through the API are
+// stored in. Manifests placed in the directory by other means keep their own
+// file names; they are still visible through the read endpoints.
+func (s *Server) manifestPath(namespace, name string) string {
+	return filepath.Join(s.manifestDir, fmt.Sprintf("%s_%s.json", namespace, name))
+}
+
+func (s *Server) writeObject(w http.ResponseWriter, code int, obj runtime.Object) {
+	info, ok := runtime.SerializerInfoForMediaType(legacyscheme.Codecs.SupportedMediaTypes(), runtime.ContentTypeJSON)
+	if !ok {
+		http.Error(w, "no JSON serializer registered", http.StatusInternalServerError)
+		return
+	}
+	codec := legacyscheme.Codecs.EncoderForVersion(info.Serializer, v1.SchemeGroupVersion)
+	w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+	w.WriteHeader(code)
+	if err := codec.Encode(obj, w); err != nil {
+		klog.ErrorS(err, "Failed to encode response")
+	}
+}
+
+// writeJSON serializes sub-resources that are not runtime.Objects, such as a
+// bare pod status.
+func (s *Server) writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.ErrorS(err, "Failed to encode response")
+	}
+}
+
+// decodePod parses and validates a single pod manifest. Unlike the file
+// source it does not apply the static pod name and UID rewrites; those happen
+// when the file source reads the written manifest back.
+func decodePod(data []byte) (*v1.Pod, error) {
+	// JSON is valid YAML, so this handles both encodings.
+	json, err := utilyaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := runtime.Decode(legacyscheme.Codecs.UniversalDecoder(), json)
+	if err != nil {
+		return nil, err
+	}
+	pod, ok := obj.(*api.Pod)
+	if !ok {
+		return nil, fmt.Errorf("manifest is not a pod: %#v", obj)
+	}
+	if pod.Namespace == "" {
+		pod.Namespace = metav1.NamespaceDefault
+	}
+	if errs := validation.ValidatePodCreate(pod, validation.PodValidationOptions{}); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid pod: %v", errs)
+	}
+	v1Pod := &v1.Pod{}
+	if err := k8s_api_v1.Convert_core_Pod_To_v1_Pod(pod, v1Pod, nil); err != nil {
+		return nil, err
+	}
+	return v1Pod, nil
+}
+
+// writeFileAtomically writes data to path via a rename so the file source
+// never observes a partially written manifest.
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakePodsProvider struct {
+	pods []*v1.Pod
+}
+
+func (f *fakePodsProvider) GetPods() []*v1.Pod {
+	return f.pods
+}
+
+func podManifest(namespace, name string) string {
+	return fmt.Sprintf(`{
+  "apiVersion": "v1",
+  "kind": "Pod",
+  "metadata": {"name": %q, "namespace": %q},
+  "spec": {"containers": [{"name": "app", "image": "registry.k8s.io/pause:3.8", "terminationMessagePolicy": "File", "imagePullPolicy": "IfNotPresent"}], "restartPolicy": "Always", "dnsPolicy": "ClusterFirst"}
+}`, name, namespace)
+}
+
+// startServer runs a Server over a unix socket in a temp dir and returns a
+// client wired to it together with the manifest directory.
+func startServer(t *testing.T, provider PodsProvider, statusStore *StatusStore) (*http.Client, string) {
+	t.Helper()
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "manifest.sock")
+	manifestDir := filepath.Join(dir, "manifests")
+
+	server := NewServer(socketPath, manifestDir, provider, statusStore)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	go func() {
+		if err := server.ListenAndServe(stopCh); err != nil {
+			t.Errorf("ListenAndServe failed: %v", err)
+		}
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	// Wait for the socket to come up.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(socketPath); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for socket %q", socketPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return client, manifestDir
+}
+
+func TestManifestCRUD(t *testing.T) {
+	client, manifestDir := startServer(t, nil, nil)
+
+	// Create.
+	resp, err := client.Post("http://localhost/pods", "application/json", bytes.NewBufferString(podManifest("default", "nginx")))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create returned status %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	resp.Body.Close()
+	if _, err := os.Stat(filepath.Join(manifestDir, "default_nginx.json")); err != nil {
+		t.Fatalf("manifest file was not written: %v", err)
+	}
+
+	// Update.
+	req, err := http.NewRequest(http.MethodPut, "http://localhost/pods", bytes.NewBufferString(podManifest("default", "nginx")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update returned status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	resp.Body.Close()
+
+	// List.
+	resp, err = client.Get("http://localhost/pods")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	podList := v1.PodList{}
+	if err := json.Unmarshal(body, &podList); err != nil {
+		t.Fatalf("failed to parse pod list %q: %v", string(body), err)
+	}
+	if len(podList.Items) != 1 || podList.Items[0].Name != "nginx" {
+		t.Fatalf("unexpected pod list: %#v", podList.Items)
+	}
+
+	// Get.
+	resp, err = client.Get("http://localhost/pods/default/nginx")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get returned status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// Delete.
+	req, err = http.NewRequest(http.MethodDelete, "http://localhost/pods/default/nginx", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete returned status %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if _, err := os.Stat(filepath.Join(manifestDir, "default_nginx.json")); !os.IsNotExist(err) {
+		t.Fatalf("manifest file still exists after delete: %v", err)
+	}
+
+	// Get after delete.
+	resp, err = client.Get("http://localhost/pods/default/nginx")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get after delete returned status %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestCreateRejectsInvalidManifest(t *testing.T) {
+	client, _ := startServer(t, nil, nil)
+
+	resp, err := client.Post("http://localhost/pods", "application/json", bytes.NewBufferString(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "Not_A_Valid_Name"}}`))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("create returned status %d, want %d", resp.StatusCode, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetPodStatus(t *testing.T) {
+	provider := &fakePodsProvider{pods: []*v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx-node1"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}}}
+	statusStore := NewStatusStore(filepath.Join(t.TempDir(), "status.json"))
+	statusStore.SetPodStatus("kube-system", "etcd", v1.PodStatus{Phase: v1.PodPending})
+	client, _ := startServer(t, provider, statusStore)
+
+	testCases := []struct {
+		desc      string
+		path      string
+		wantCode  int
+		wantPhase v1.PodPhase
+	}{
+		{
+			desc:      "status from provider, static pod name suffix",
+			path:      "/pods/default/nginx/status",
+			wantCode:  http.StatusOK,
+			wantPhase: v1.PodRunning,
+		},
+		{
+			desc:      "status from persisted store",
+			path:      "/pods/kube-system/etcd/status",
+			wantCode:  http.StatusOK,
+			wantPhase: v1.PodPending,
+		},
+		{
+			desc:     "unknown pod",
+			path:     "/pods/default/unknown/status",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			resp, err := client.Get("http://localhost" + tt.path)
+			if err != nil {
+				t.Fatalf("get failed: %v", err)
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("GET %s returned status %d, want %d", tt.path, resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			status := v1.PodStatus{}
+			if err := json.Unmarshal(body, &status); err != nil {
+				t.Fatalf("failed to parse status %q: %v", string(body), err)
+			}
+			if status.Phase != tt.wantPhase {
+				t.Errorf("GET %s returned phase %q, want %q", tt.path, status.Phase, tt.wantPhase)
+			}
+		})
+	}
+}
+
+func TestStatusStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+
+	store := NewStatusStore(path)
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load of missing file failed: %v", err)
+	}
+	store.SetPodStatus("default", "nginx", v1.PodStatus{Phase: v1.PodRunning})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A fresh store simulates a kubelet restart.
+	restarted := NewStatusStore(path)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	status, ok := restarted.GetPodStatus("default", "nginx")
+	if !ok {
+		t.Fatal("status was not persisted across stores")
+	}
+	if status.Phase != v1.PodRunning {
+		t.Errorf("persisted phase = %q, want %q", status.Phase, v1.PodRunning)
+	}
+
+	restarted.DeletePodStatus("default", "nginx")
+	if _, ok := restarted.GetPodStatus("default", "nginx"); ok {
+		t.Error("status still present after delete")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package standalone
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// StatusStore persists the last observed status of each pod to a file so that
+// the local manifest API can answer status queries across kubelet restarts,
+// when the status manager cache starts out empty.
+type StatusStore struct {
+	path string
+
+	lock     sync.Mutex
+	statuses map[string]v1.PodStatus
+}
+
+// NewStatusStore returns a store backed by the file at path. Call Load before
+// first use to pick up statuses persisted by a previous process.
+func NewStatusStore(path string) *StatusStore {
+	return &StatusStore{
+		path:     path,
+		statuses: map[string]v1.PodStatus{},
+	}
+}
+
+// Load reads previously persisted statuses from disk. A missing file is not
+// an error; it simply leaves the store empty.
+func (s *StatusStore) Load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read status store %q: %w", s.path, err)
+	}
+	statuses := map[string]v1.PodStatus{}
+	if err := json.Unmarshal(data, &statuses); err != nil {
+		return fmt.Errorf("failed to parse status store %q: %w", s.path, err)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.statuses = statuses
+	return nil
+}
+
+// Save writes the current statuses to disk atomically.
+func (s *StatusStore) Save() error {
+	s.lock.Lock()
+	data, err := json.Marshal(s.statuses)
+	s.lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode status store: %w", err)
+	}
+	return writeFileAtomically(s.path, data)
+}
+
+// SetPodStatus records the status for the given pod. The change is only
+// persisted on the next Save.
+func (s *StatusStore) SetPodStatus(namespace, name string, status v1.PodStatus) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.statuses[statusKey(namespace, name)] = status
+}
+
+// GetPodStatus returns the recorded status for the given pod, and whether one
+// is known.
+func (s *StatusStore) GetPodStatus(namespace, name string) (v1.PodStatus, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status, ok := s.statuses[statusKey(namespace, name)]
+	return status, ok
+}
+
+// DeletePodStatus forgets the recorded status for the given pod. The change
+// is only persisted on the next Save.
+func (s *StatusStore) DeletePodStatus(namespace, name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.statuses, statusKey(namespace, name))
+}
+
+func statusKey(namespace, name string) string {
+	return namespace + "/" + name
+}
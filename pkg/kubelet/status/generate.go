@@ -19,14 +19,23 @@ package status
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/kubernetes/pkg/features"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 	runtimeutil "k8s.io/kubernetes/pkg/kubelet/kuberuntime/util"
 	kubetypes "k8s.io/kubernetes/pkg/kubelet/types"
+	"k8s.io/utils/clock"
 )
 
+// readinessGateClock is used to decide whether a readiness gate with
+// ExpirySeconds has expired. Overridden in tests.
+var readinessGateClock clock.Clock = clock.RealClock{}
+
 const (
 	// UnknownContainerStatuses says that all container statuses are unknown.
 	UnknownContainerStatuses = "UnknownContainerStatuses"
@@ -102,7 +111,9 @@ func GenerateContainersReadyCondition(spec *v1.PodSpec, containerStatuses []v1.C
 // GeneratePodReadyCondition returns "Ready" condition of a pod.
 // The status of "Ready" condition is "True", if all containers in a pod are ready
 // AND all matching conditions specified in the ReadinessGates have status equal to "True".
-func GeneratePodReadyCondition(spec *v1.PodSpec, conditions []v1.PodCondition, containerStatuses []v1.ContainerStatus, podPhase v1.PodPhase) v1.PodCondition {
+// A gate whose condition has not been reported counts as "True" once its
+// DefaultStatus or ExpiryStatus, measured against podStartTime, says so.
+func GeneratePodReadyCondition(spec *v1.PodSpec, podStartTime *metav1.Time, conditions []v1.PodCondition, containerStatuses []v1.ContainerStatus, podPhase v1.PodPhase) v1.PodCondition {
 	containersReady := GenerateContainersReadyCondition(spec, containerStatuses, podPhase)
 	// If the status of ContainersReady is not True, return the same status, reason and message as ContainersReady.
 	if containersReady.Status != v1.ConditionTrue {
@@ -120,6 +131,12 @@ func GeneratePodReadyCondition(spec *v1.PodSpec, conditions []v1.PodCondition, c
 	for _, rg := range spec.ReadinessGates {
 		_, c := podutil.GetPodConditionFromList(conditions, rg.ConditionType)
 		if c == nil {
+			if status, resolved := resolveUnreportedReadinessGate(rg, podStartTime); resolved {
+				if status != v1.ConditionTrue {
+					unreadyMessages = append(unreadyMessages, fmt.Sprintf("the unreported pod readiness gate %q resolved to %v", string(rg.ConditionType), status))
+				}
+				continue
+			}
 			unreadyMessages = append(unreadyMessages, fmt.Sprintf("corresponding condition of pod readiness gate %q does not exist.", string(rg.ConditionType)))
 		} else if c.Status != v1.ConditionTrue {
 			unreadyMessages = append(unreadyMessages, fmt.Sprintf("the status of pod readiness gate %q is not \"True\", but %v", string(rg.ConditionType), c.Status))
@@ -143,6 +160,30 @@ func GeneratePodReadyCondition(spec *v1.PodSpec, conditions []v1.PodCondition, c
 	}
 }
 
+// resolveUnreportedReadinessGate returns the status a readiness gate whose
+// condition has not been reported yet resolves to. A gate with ExpirySeconds
+// resolves to its ExpiryStatus (default "True") once that many seconds have
+// passed since the pod started; before that, or without an expiry, a declared
+// DefaultStatus applies. Gates declaring neither are left unresolved, which
+// keeps the pod not ready. Gates never resolve while the
+// PodReadinessGateDefaults feature is disabled.
+func resolveUnreportedReadinessGate(rg v1.PodReadinessGate, podStartTime *metav1.Time) (v1.ConditionStatus, bool) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.PodReadinessGateDefaults) {
+		return "", false
+	}
+	if rg.ExpirySeconds > 0 && podStartTime != nil &&
+		readinessGateClock.Since(podStartTime.Time) >= time.Duration(rg.ExpirySeconds)*time.Second {
+		if rg.ExpiryStatus != "" {
+			return rg.ExpiryStatus, true
+		}
+		return v1.ConditionTrue, true
+	}
+	if rg.DefaultStatus != "" {
+		return rg.DefaultStatus, true
+	}
+	return "", false
+}
+
 // GeneratePodInitializedCondition returns initialized condition if all init containers in a pod are ready, else it
 // returns an uninitialized condition.
 func GeneratePodInitializedCondition(spec *v1.PodSpec, containerStatuses []v1.ContainerStatus, podPhase v1.PodPhase) v1.PodCondition {
@@ -216,6 +257,22 @@ func GeneratePodHasNetworkCondition(pod *v1.Pod, podStatus *kubecontainer.PodSta
 	}
 }
 
+// GeneratePodPausedCondition returns a PodPaused condition reflecting whether
+// the pod-level cgroup is currently frozen. It is only produced for pods that
+// carry the pause annotation.
+func GeneratePodPausedCondition(frozen bool) v1.PodCondition {
+	if frozen {
+		return v1.PodCondition{
+			Type:   kubetypes.PodPaused,
+			Status: v1.ConditionTrue,
+		}
+	}
+	return v1.PodCondition{
+		Type:   kubetypes.PodPaused,
+		Status: v1.ConditionFalse,
+	}
+}
+
 func generateContainersReadyConditionForTerminalPhase(podPhase v1.PodPhase) v1.PodCondition {
 	condition := v1.PodCondition{
 		Type:   v1.ContainersReady,
@@ -19,12 +19,17 @@ package status
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/kubernetes/pkg/features"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 	kubetypes "k8s.io/kubernetes/pkg/kubelet/types"
 )
@@ -122,8 +127,11 @@ func TestGenerateContainersReadyCondition(t *testing.T) {
 }
 
 func TestGeneratePodReadyCondition(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PodReadinessGateDefaults, true)()
+	startedLongAgo := metav1.NewTime(time.Now().Add(-time.Hour))
 	tests := []struct {
 		spec              *v1.PodSpec
+		podStartTime      *metav1.Time
 		conditions        []v1.PodCondition
 		containerStatuses []v1.ContainerStatus
 		podPhase          v1.PodPhase
@@ -308,16 +316,94 @@ func TestGeneratePodReadyCondition(t *testing.T) {
 			podPhase:          v1.PodRunning,
 			expectReady:       getPodCondition(v1.PodReady, v1.ConditionFalse, ContainersNotReady, "containers with unready status: [1234]"),
 		},
+		{
+			spec: &v1.PodSpec{
+				ReadinessGates: []v1.PodReadinessGate{
+					{ConditionType: v1.PodConditionType("gate1"), DefaultStatus: v1.ConditionTrue},
+				},
+			},
+			conditions:        nil,
+			containerStatuses: []v1.ContainerStatus{},
+			podPhase:          v1.PodRunning,
+			expectReady:       getPodCondition(v1.PodReady, v1.ConditionTrue, "", ""),
+		},
+		{
+			spec: &v1.PodSpec{
+				ReadinessGates: []v1.PodReadinessGate{
+					{ConditionType: v1.PodConditionType("gate1"), DefaultStatus: v1.ConditionTrue},
+				},
+			},
+			conditions: []v1.PodCondition{
+				getPodCondition("gate1", v1.ConditionFalse, "", ""),
+			},
+			containerStatuses: []v1.ContainerStatus{},
+			podPhase:          v1.PodRunning,
+			expectReady:       getPodCondition(v1.PodReady, v1.ConditionFalse, ReadinessGatesNotReady, `the status of pod readiness gate "gate1" is not "True", but False`),
+		},
+		{
+			spec: &v1.PodSpec{
+				ReadinessGates: []v1.PodReadinessGate{
+					{ConditionType: v1.PodConditionType("gate1"), ExpirySeconds: 60},
+				},
+			},
+			podStartTime:      &startedLongAgo,
+			conditions:        nil,
+			containerStatuses: []v1.ContainerStatus{},
+			podPhase:          v1.PodRunning,
+			expectReady:       getPodCondition(v1.PodReady, v1.ConditionTrue, "", ""),
+		},
+		{
+			spec: &v1.PodSpec{
+				ReadinessGates: []v1.PodReadinessGate{
+					{ConditionType: v1.PodConditionType("gate1"), ExpirySeconds: 60, ExpiryStatus: v1.ConditionFalse},
+				},
+			},
+			podStartTime:      &startedLongAgo,
+			conditions:        nil,
+			containerStatuses: []v1.ContainerStatus{},
+			podPhase:          v1.PodRunning,
+			expectReady:       getPodCondition(v1.PodReady, v1.ConditionFalse, ReadinessGatesNotReady, `the unreported pod readiness gate "gate1" resolved to False`),
+		},
+		{
+			spec: &v1.PodSpec{
+				ReadinessGates: []v1.PodReadinessGate{
+					{ConditionType: v1.PodConditionType("gate1"), ExpirySeconds: 3600 * 24},
+				},
+			},
+			podStartTime:      &startedLongAgo,
+			conditions:        nil,
+			containerStatuses: []v1.ContainerStatus{},
+			podPhase:          v1.PodRunning,
+			expectReady:       getPodCondition(v1.PodReady, v1.ConditionFalse, ReadinessGatesNotReady, `corresponding condition of pod readiness gate "gate1" does not exist.`),
+		},
 	}
 
 	for i, test := range tests {
-		ready := GeneratePodReadyCondition(test.spec, test.conditions, test.containerStatuses, test.podPhase)
+		ready := GeneratePodReadyCondition(test.spec, test.podStartTime, test.conditions, test.containerStatuses, test.podPhase)
 		if !reflect.DeepEqual(ready, test.expectReady) {
 			t.Errorf("On test case %v, expectReady:\n%+v\ngot\n%+v\n", i, test.expectReady, ready)
 		}
 	}
 }
 
+func TestGeneratePodReadyConditionGateDisabled(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PodReadinessGateDefaults, false)()
+	startedLongAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	// with the feature disabled, neither the default status nor the expiry
+	// resolves an unreported gate condition
+	spec := &v1.PodSpec{
+		ReadinessGates: []v1.PodReadinessGate{
+			{ConditionType: v1.PodConditionType("gate1"), DefaultStatus: v1.ConditionTrue, ExpirySeconds: 60},
+		},
+	}
+	ready := GeneratePodReadyCondition(spec, &startedLongAgo, nil, []v1.ContainerStatus{}, v1.PodRunning)
+	expected := getPodCondition(v1.PodReady, v1.ConditionFalse, ReadinessGatesNotReady, `corresponding condition of pod readiness gate "gate1" does not exist.`)
+	if !reflect.DeepEqual(ready, expected) {
+		t.Errorf("expectReady:\n%+v\ngot\n%+v\n", expected, ready)
+	}
+}
+
 func TestGeneratePodInitializedCondition(t *testing.T) {
 	noInitContainer := &v1.PodSpec{}
 	oneInitContainer := &v1.PodSpec{
@@ -422,6 +508,37 @@ func TestGeneratePodInitializedCondition(t *testing.T) {
 	}
 }
 
+func TestGeneratePodPausedCondition(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		frozen   bool
+		expected v1.PodCondition
+	}{
+		{
+			desc:   "frozen pod cgroup",
+			frozen: true,
+			expected: v1.PodCondition{
+				Type:   kubetypes.PodPaused,
+				Status: v1.ConditionTrue,
+			},
+		},
+		{
+			desc:   "thawed pod cgroup",
+			frozen: false,
+			expected: v1.PodCondition{
+				Type:   kubetypes.PodPaused,
+				Status: v1.ConditionFalse,
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := GeneratePodPausedCondition(tc.frozen); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("unexpected condition: %v, expected: %v", got, tc.expected)
+			}
+		})
+	}
+}
+
 func TestGeneratePodHasNetworkCondition(t *testing.T) {
 	for desc, test := range map[string]struct {
 		pod      *v1.Pod
@@ -52,4 +52,18 @@ const (
 	// pod and IP address(es) assigned. Images for containers specified in the pod
 	// spec can be pulled and containers launched after this condition is true.
 	PodHasNetwork = "PodHasNetwork"
+
+	// PodPaused indicates that the pod-level cgroup is currently frozen. It is
+	// only reported for pods that carry the pause annotation.
+	PodPaused = "PodPaused"
+
+	// PodTopologyAffinity carries the detailed outcome of the topology
+	// manager's pre-flight evaluation when a pod is rejected because its
+	// resources cannot be aligned to the node's NUMA topology.
+	PodTopologyAffinity = "TopologyAffinity"
 )
+
+// PodPausedAnnotationKey requests that the kubelet freeze ("true") or thaw
+// (any other value) the pod-level cgroup, stopping every process in the pod
+// without killing it. It requires cgroup v2.
+const PodPausedAnnotationKey = "pod.alpha.kubernetes.io/paused"
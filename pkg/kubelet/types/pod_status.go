@@ -42,6 +42,12 @@ func PodConditionByKubelet(conditionType v1.PodConditionType) bool {
 			return true
 		}
 	}
+	if conditionType == PodPaused {
+		return true
+	}
+	if conditionType == PodTopologyAffinity {
+		return true
+	}
 	return false
 }
 
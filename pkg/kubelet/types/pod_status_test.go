@@ -35,6 +35,7 @@ func TestPodConditionByKubelet(t *testing.T) {
 		v1.PodInitialized,
 		v1.ContainersReady,
 		PodHasNetwork,
+		PodPaused,
 	}
 
 	for _, tc := range trueCases {
@@ -156,6 +156,12 @@ func IsStaticPod(pod *v1.Pod) bool {
 }
 
 // IsCriticalPod returns true if pod's priority is greater than or equal to SystemCriticalPriority.
+// IsPodPauseRequested returns true if the pod asks the kubelet to freeze its
+// pod-level cgroup.
+func IsPodPauseRequested(pod *v1.Pod) bool {
+	return pod.Annotations[PodPausedAnnotationKey] == "true"
+}
+
 func IsCriticalPod(pod *v1.Pod) bool {
 	if IsStaticPod(pod) {
 		return true
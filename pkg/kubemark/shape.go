@@ -0,0 +1,219 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemark
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/klog/v2"
+)
+
+// LatencyDistribution describes pod startup latency by its quantiles. Real
+// fleets do not start pods at a single fixed speed, so modeling the tail is
+// what makes control-plane scalability numbers transferable.
+type LatencyDistribution struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// ParseLatencyDistribution parses a distribution in the form
+// "p50=100ms,p90=1s,p99=5s". Omitted quantiles default to the next lower one.
+func ParseLatencyDistribution(s string) (LatencyDistribution, error) {
+	var d LatencyDistribution
+	if s == "" {
+		return d, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		quantile, val, found := strings.Cut(part, "=")
+		if !found {
+			return d, fmt.Errorf("malformed latency distribution element %q, expected <quantile>=<duration>", part)
+		}
+		duration, err := time.ParseDuration(val)
+		if err != nil {
+			return d, fmt.Errorf("malformed duration in latency distribution element %q: %v", part, err)
+		}
+		switch quantile {
+		case "p50":
+			d.P50 = duration
+		case "p90":
+			d.P90 = duration
+		case "p99":
+			d.P99 = duration
+		default:
+			return d, fmt.Errorf("unknown quantile %q in latency distribution, expected p50, p90 or p99", quantile)
+		}
+	}
+	if d.P90 < d.P50 {
+		d.P90 = d.P50
+	}
+	if d.P99 < d.P90 {
+		d.P99 = d.P90
+	}
+	return d, nil
+}
+
+// IsZero reports whether the distribution adds no latency.
+func (d LatencyDistribution) IsZero() bool {
+	return d.P50 == 0 && d.P90 == 0 && d.P99 == 0
+}
+
+// Sample draws one latency from the distribution by linear interpolation
+// between the configured quantiles.
+func (d LatencyDistribution) Sample(r *rand.Rand) time.Duration {
+	u := r.Float64()
+	switch {
+	case u < 0.5:
+		return interpolate(0, 0.5, 0, d.P50, u)
+	case u < 0.9:
+		return interpolate(0.5, 0.9, d.P50, d.P90, u)
+	default:
+		return interpolate(0.9, 0.99, d.P90, d.P99, u)
+	}
+}
+
+func interpolate(lowQ, highQ float64, low, high time.Duration, u float64) time.Duration {
+	if u > highQ {
+		return high
+	}
+	fraction := (u - lowQ) / (highQ - lowQ)
+	return low + time.Duration(fraction*float64(high-low))
+}
+
+// latencyRuntimeService delays sandbox and container startup according to a
+// latency distribution, so hollow pods become ready at fleet-realistic speed
+// instead of instantly.
+type latencyRuntimeService struct {
+	internalapi.RuntimeService
+
+	distribution LatencyDistribution
+
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+// NewRuntimeServiceWithLatency wraps a runtime service so that every pod
+// sandbox start observes a latency sampled from the distribution.
+func NewRuntimeServiceWithLatency(runtimeService internalapi.RuntimeService, distribution LatencyDistribution) internalapi.RuntimeService {
+	if distribution.IsZero() {
+		return runtimeService
+	}
+	return &latencyRuntimeService{
+		RuntimeService: runtimeService,
+		distribution:   distribution,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (l *latencyRuntimeService) RunPodSandbox(ctx context.Context, config *runtimeapi.PodSandboxConfig, runtimeHandler string) (string, error) {
+	l.lock.Lock()
+	delay := l.distribution.Sample(l.rand)
+	l.lock.Unlock()
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(delay):
+	}
+	return l.RuntimeService.RunPodSandbox(ctx, config, runtimeHandler)
+}
+
+// PressureInjector keeps a set of node pressure conditions asserted on the
+// hollow node, so control-plane tests can measure how the scheduler, the
+// node lifecycle controller and the taint manager behave against a fleet
+// where some nodes are permanently under pressure. It re-asserts the
+// conditions on an interval because the hollow kubelet's own status updates
+// reset them.
+type PressureInjector struct {
+	client     clientset.Interface
+	nodeName   string
+	conditions []v1.NodeConditionType
+	interval   time.Duration
+}
+
+// NewPressureInjector returns an injector asserting the given conditions
+// (e.g. MemoryPressure, DiskPressure, PIDPressure) on the named node.
+func NewPressureInjector(client clientset.Interface, nodeName string, conditions []v1.NodeConditionType, interval time.Duration) *PressureInjector {
+	return &PressureInjector{
+		client:     client,
+		nodeName:   nodeName,
+		conditions: conditions,
+		interval:   interval,
+	}
+}
+
+// Run asserts the conditions until stopCh is closed.
+func (p *PressureInjector) Run(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := p.assert(context.Background()); err != nil {
+			klog.V(2).Infof("Could not inject pressure conditions on node %s: %v", p.nodeName, err)
+		}
+	}, p.interval, stopCh)
+}
+
+func (p *PressureInjector) assert(ctx context.Context) error {
+	node, err := p.client.CoreV1().Nodes().Get(ctx, p.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	changed := false
+	for _, conditionType := range p.conditions {
+		condition := v1.NodeCondition{
+			Type:               conditionType,
+			Status:             v1.ConditionTrue,
+			Reason:             "KubemarkSimulatedPressure",
+			Message:            "kubemark is simulating pressure on this node",
+			LastHeartbeatTime:  now,
+			LastTransitionTime: now,
+		}
+		replaced := false
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type != conditionType {
+				continue
+			}
+			replaced = true
+			if node.Status.Conditions[i].Status != v1.ConditionTrue {
+				condition.LastTransitionTime = now
+				node.Status.Conditions[i] = condition
+				changed = true
+			}
+			break
+		}
+		if !replaced {
+			node.Status.Conditions = append(node.Status.Conditions, condition)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	_, err = p.client.CoreV1().Nodes().UpdateStatus(ctx, node, metav1.UpdateOptions{})
+	return err
+}
@@ -141,6 +141,11 @@ type KubeProxyConfiguration struct {
 	// metricsBindAddress is the IP address and port for the metrics server to serve on,
 	// defaulting to 127.0.0.1:10249 (set to 0.0.0.0 for all interfaces)
 	MetricsBindAddress string
+	// programmingBindAddress is the IP address and port for the service
+	// programming API to serve on, e.g. 127.0.0.1:10258. The API exposes the
+	// joined service, endpoint slice and node topology view to external
+	// dataplanes. Empty (the default) disables the API.
+	ProgrammingBindAddress string
 	// BindAddressHardFail, if true, kube-proxy will treat failure to bind to a port as fatal and exit
 	BindAddressHardFail bool
 	// enableProfiling enables profiling via web interface on /debug/pprof handler.
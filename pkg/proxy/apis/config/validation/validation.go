@@ -70,6 +70,10 @@ func Validate(config *kubeproxyconfig.KubeProxyConfiguration) field.ErrorList {
 	}
 	allErrs = append(allErrs, validateHostPort(config.MetricsBindAddress, newPath.Child("MetricsBindAddress"))...)
 
+	if config.ProgrammingBindAddress != "" {
+		allErrs = append(allErrs, validateHostPort(config.ProgrammingBindAddress, newPath.Child("ProgrammingBindAddress"))...)
+	}
+
 	if config.ClusterCIDR != "" {
 		cidrs := strings.Split(config.ClusterCIDR, ",")
 		switch {
@@ -94,11 +94,12 @@ const sysctlBridgeCallIPTables = "net/bridge/bridge-nf-call-iptables"
 type servicePortInfo struct {
 	*proxy.BaseServicePortInfo
 	// The following fields are computed and stored for performance reasons.
-	nameString             string
-	clusterPolicyChainName utiliptables.Chain
-	localPolicyChainName   utiliptables.Chain
-	firewallChainName      utiliptables.Chain
-	externalChainName      utiliptables.Chain
+	nameString                 string
+	clusterPolicyChainName     utiliptables.Chain
+	preferLocalPolicyChainName utiliptables.Chain
+	localPolicyChainName       utiliptables.Chain
+	firewallChainName          utiliptables.Chain
+	externalChainName          utiliptables.Chain
 }
 
 // returns a new proxy.ServicePort which abstracts a serviceInfo
@@ -111,6 +112,7 @@ func newServiceInfo(port *v1.ServicePort, service *v1.Service, bsvcPortInfo *pro
 	protocol := strings.ToLower(string(svcPort.Protocol()))
 	svcPort.nameString = svcPortName.String()
 	svcPort.clusterPolicyChainName = servicePortPolicyClusterChain(svcPort.nameString, protocol)
+	svcPort.preferLocalPolicyChainName = servicePortPolicyPreferLocalChainName(svcPort.nameString, protocol)
 	svcPort.localPolicyChainName = servicePortPolicyLocalChainName(svcPort.nameString, protocol)
 	svcPort.firewallChainName = serviceFirewallChainName(svcPort.nameString, protocol)
 	svcPort.externalChainName = serviceExternalChainName(svcPort.nameString, protocol)
@@ -670,11 +672,12 @@ func portProtoHash(servicePortName string, protocol string) string {
 }
 
 const (
-	servicePortPolicyClusterChainNamePrefix = "KUBE-SVC-"
-	servicePortPolicyLocalChainNamePrefix   = "KUBE-SVL-"
-	serviceFirewallChainNamePrefix          = "KUBE-FW-"
-	serviceExternalChainNamePrefix          = "KUBE-EXT-"
-	servicePortEndpointChainNamePrefix      = "KUBE-SEP-"
+	servicePortPolicyClusterChainNamePrefix     = "KUBE-SVC-"
+	servicePortPolicyPreferLocalChainNamePrefix = "KUBE-SVP-"
+	servicePortPolicyLocalChainNamePrefix       = "KUBE-SVL-"
+	serviceFirewallChainNamePrefix              = "KUBE-FW-"
+	serviceExternalChainNamePrefix              = "KUBE-EXT-"
+	servicePortEndpointChainNamePrefix          = "KUBE-SEP-"
 
 	// For cleanup.  This can be removed after 1.26 is released.
 	deprecatedServiceLBChainNamePrefix = "KUBE-XLB-"
@@ -687,6 +690,14 @@ func servicePortPolicyClusterChain(servicePortName string, protocol string) util
 	return utiliptables.Chain(servicePortPolicyClusterChainNamePrefix + portProtoHash(servicePortName, protocol))
 }
 
+// servicePortPolicyPreferLocalChainName returns the name of the KUBE-SVP-XXXX chain for a
+// service, which dispatches to the narrowed set of local endpoints when using `PreferLocal`
+// traffic policy. This chain only exists while the service has enough ready local endpoints
+// to stay node-local; otherwise PreferLocal traffic uses the KUBE-SVC-XXXX chain.
+func servicePortPolicyPreferLocalChainName(servicePortName string, protocol string) utiliptables.Chain {
+	return utiliptables.Chain(servicePortPolicyPreferLocalChainNamePrefix + portProtoHash(servicePortName, protocol))
+}
+
 // servicePortPolicyLocalChainName returns the name of the KUBE-SVL-XXXX chain for a service, which
 // handles dispatching to local endpoints when using `Local` traffic policy. This chain only
 // exists if the service has `Local` internal or external traffic policy.
@@ -719,6 +730,7 @@ func servicePortEndpointChainName(servicePortName string, protocol string, endpo
 func isServiceChainName(chainString string) bool {
 	prefixes := []string{
 		servicePortPolicyClusterChainNamePrefix,
+		servicePortPolicyPreferLocalChainNamePrefix,
 		servicePortPolicyLocalChainNamePrefix,
 		servicePortEndpointChainNamePrefix,
 		serviceFirewallChainNamePrefix,
@@ -982,7 +994,7 @@ func (proxier *Proxier) syncProxyRules() {
 		// from this node, given the service's traffic policies. hasEndpoints is true
 		// if the service has any usable endpoints on any node, not just this one.
 		allEndpoints := proxier.endpointsMap[svcName]
-		clusterEndpoints, localEndpoints, allLocallyReachableEndpoints, hasEndpoints := proxy.CategorizeEndpoints(allEndpoints, svcInfo, proxier.nodeLabels)
+		clusterEndpoints, preferLocalEndpoints, localEndpoints, allLocallyReachableEndpoints, hasEndpoints := proxy.CategorizeEndpoints(allEndpoints, svcInfo, proxier.nodeLabels)
 
 		// Note the endpoint chains that will be used
 		for _, ep := range allLocallyReachableEndpoints {
@@ -998,6 +1010,16 @@ func (proxier *Proxier) syncProxyRules() {
 			activeNATChains[clusterPolicyChain] = true
 		}
 
+		// preferLocalPolicyChain contains the narrowed set of local endpoints
+		// used with "PreferLocal" traffic policy while enough of them are
+		// ready; when it is unused, PreferLocal traffic falls back to
+		// clusterPolicyChain.
+		preferLocalPolicyChain := svcInfo.preferLocalPolicyChainName
+		usesPreferLocalPolicyChain := len(preferLocalEndpoints) > 0
+		if usesPreferLocalPolicyChain {
+			activeNATChains[preferLocalPolicyChain] = true
+		}
+
 		// localPolicyChain contains the endpoints used with "Local" traffic policy
 		localPolicyChain := svcInfo.localPolicyChainName
 		usesLocalPolicyChain := len(localEndpoints) > 0 && svcInfo.UsesLocalEndpoints()
@@ -1018,6 +1040,8 @@ func (proxier *Proxier) syncProxyRules() {
 			if len(localEndpoints) == 0 {
 				hasInternalEndpoints = false
 			}
+		} else if svcInfo.InternalPolicyPreferLocal() && usesPreferLocalPolicyChain {
+			internalPolicyChain = preferLocalPolicyChain
 		}
 		internalTrafficChain := internalPolicyChain
 
@@ -1036,6 +1060,8 @@ func (proxier *Proxier) syncProxyRules() {
 			if len(localEndpoints) == 0 {
 				hasExternalEndpoints = false
 			}
+		} else if svcInfo.ExternalPolicyPreferLocal() && usesPreferLocalPolicyChain {
+			externalPolicyChain = preferLocalPolicyChain
 		}
 		externalTrafficChain := svcInfo.externalChainName // eventually jumps to externalPolicyChain
 
@@ -1359,6 +1385,14 @@ func (proxier *Proxier) syncProxyRules() {
 			proxier.writeServiceToEndpointRules(svcPortNameString, svcInfo, clusterPolicyChain, clusterEndpoints, args)
 		}
 
+		// If PreferLocal policy is narrowing to local endpoints, create the chain
+		// and create rules jumping from preferLocalPolicyChain to the
+		// preferLocalEndpoints
+		if usesPreferLocalPolicyChain {
+			proxier.natChains.Write(utiliptables.MakeChainLine(preferLocalPolicyChain))
+			proxier.writeServiceToEndpointRules(svcPortNameString, svcInfo, preferLocalPolicyChain, preferLocalEndpoints, args)
+		}
+
 		// If Local policy is in use, create the chain and create rules jumping
 		// from localPolicyChain to the localEndpoints
 		if usesLocalPolicyChain {
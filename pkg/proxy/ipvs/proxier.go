@@ -1240,7 +1240,7 @@ func (proxier *Proxier) syncProxyRules() {
 			if svcInfo.InternalPolicyLocal() {
 				internalNodeLocal = true
 			}
-			if err := proxier.syncEndpoint(svcPortName, internalNodeLocal, serv); err != nil {
+			if err := proxier.syncEndpoint(svcPortName, internalNodeLocal, svcInfo.InternalPolicyPreferLocal(), serv); err != nil {
 				klog.ErrorS(err, "Failed to sync endpoint for service", "servicePortName", svcPortName, "virtualServer", serv)
 			}
 		} else {
@@ -1287,7 +1287,7 @@ func (proxier *Proxier) syncProxyRules() {
 				activeIPVSServices[serv.String()] = true
 				activeBindAddrs[serv.Address.String()] = true
 
-				if err := proxier.syncEndpoint(svcPortName, svcInfo.ExternalPolicyLocal(), serv); err != nil {
+				if err := proxier.syncEndpoint(svcPortName, svcInfo.ExternalPolicyLocal(), svcInfo.ExternalPolicyPreferLocal(), serv); err != nil {
 					klog.ErrorS(err, "Failed to sync endpoint for service", "servicePortName", svcPortName, "virtualServer", serv)
 				}
 			} else {
@@ -1386,7 +1386,7 @@ func (proxier *Proxier) syncProxyRules() {
 			if err := proxier.syncService(svcPortNameString, serv, true, bindedAddresses); err == nil {
 				activeIPVSServices[serv.String()] = true
 				activeBindAddrs[serv.Address.String()] = true
-				if err := proxier.syncEndpoint(svcPortName, svcInfo.ExternalPolicyLocal(), serv); err != nil {
+				if err := proxier.syncEndpoint(svcPortName, svcInfo.ExternalPolicyLocal(), svcInfo.ExternalPolicyPreferLocal(), serv); err != nil {
 					klog.ErrorS(err, "Failed to sync endpoint for service", "servicePortName", svcPortName, "virtualServer", serv)
 				}
 			} else {
@@ -1529,7 +1529,7 @@ func (proxier *Proxier) syncProxyRules() {
 				// There is no need to bind Node IP to dummy interface, so set parameter `bindAddr` to `false`.
 				if err := proxier.syncService(svcPortNameString, serv, false, bindedAddresses); err == nil {
 					activeIPVSServices[serv.String()] = true
-					if err := proxier.syncEndpoint(svcPortName, svcInfo.ExternalPolicyLocal(), serv); err != nil {
+					if err := proxier.syncEndpoint(svcPortName, svcInfo.ExternalPolicyLocal(), svcInfo.ExternalPolicyPreferLocal(), serv); err != nil {
 						klog.ErrorS(err, "Failed to sync endpoint for service", "servicePortName", svcPortName, "virtualServer", serv)
 					}
 				} else {
@@ -1974,7 +1974,7 @@ func (proxier *Proxier) syncService(svcName string, vs *utilipvs.VirtualServer,
 	return nil
 }
 
-func (proxier *Proxier) syncEndpoint(svcPortName proxy.ServicePortName, onlyNodeLocalEndpoints bool, vs *utilipvs.VirtualServer) error {
+func (proxier *Proxier) syncEndpoint(svcPortName proxy.ServicePortName, onlyNodeLocalEndpoints, preferNodeLocalEndpoints bool, vs *utilipvs.VirtualServer) error {
 	appliedVirtualServer, err := proxier.ipvs.GetVirtualServer(vs)
 	if err != nil {
 		klog.ErrorS(err, "Failed to get IPVS service")
@@ -2005,7 +2005,7 @@ func (proxier *Proxier) syncEndpoint(svcPortName proxy.ServicePortName, onlyNode
 	if !ok {
 		klog.InfoS("Unable to filter endpoints due to missing service info", "servicePortName", svcPortName)
 	} else {
-		clusterEndpoints, localEndpoints, _, hasAnyEndpoints := proxy.CategorizeEndpoints(endpoints, svcInfo, proxier.nodeLabels)
+		clusterEndpoints, preferLocalEndpoints, localEndpoints, _, hasAnyEndpoints := proxy.CategorizeEndpoints(endpoints, svcInfo, proxier.nodeLabels)
 		if onlyNodeLocalEndpoints {
 			if len(localEndpoints) > 0 {
 				endpoints = localEndpoints
@@ -2024,6 +2024,11 @@ func (proxier *Proxier) syncEndpoint(svcPortName proxy.ServicePortName, onlyNode
 					proxier.serviceNoLocalEndpointsExternal.Insert(svcPortName.NamespacedName.String())
 				}
 			}
+		} else if preferNodeLocalEndpoints && len(preferLocalEndpoints) > 0 {
+			// PreferLocal policy narrows to the ready local endpoints; when
+			// there are too few of them, preferLocalEndpoints is empty and
+			// traffic spills over to clusterEndpoints.
+			endpoints = preferLocalEndpoints
 		} else {
 			endpoints = clusterEndpoints
 		}
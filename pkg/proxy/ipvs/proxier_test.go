@@ -1458,7 +1458,7 @@ func Test_syncEndpoint_updateWeightsOnRestart(t *testing.T) {
 		},
 		Port:     "80",
 		Protocol: tcpProtocol,
-	}, true, vs)
+	}, true, false, vs)
 	if err != nil {
 		t.Errorf("failed to sync endpoint, err: %v", err)
 	}
@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Conformance tests of the service programming API. They exercise the API
+// over HTTP exactly as an external dataplane would, so they double as the
+// contract a consumer may rely on.
+
+package programming
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func makeService(namespace, name string) *v1.Service {
+	internalPolicy := v1.ServiceInternalTrafficPolicyCluster
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1.ServiceSpec{
+			ClusterIP:             "172.30.0.1",
+			ClusterIPs:            []string{"172.30.0.1"},
+			ExternalIPs:           []string{"192.0.2.10"},
+			SessionAffinity:       v1.ServiceAffinityNone,
+			InternalTrafficPolicy: &internalPolicy,
+			ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+			Ports: []v1.ServicePort{
+				{Name: "http", Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080},
+			},
+		},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "198.51.100.1"}}},
+		},
+	}
+}
+
+func makeSlice(namespace, service, name string) *discovery.EndpointSlice {
+	return &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{discovery.LabelServiceName: service},
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Ports: []discovery.EndpointPort{
+			{Name: pointer.String("http"), Protocol: &[]v1.Protocol{v1.ProtocolTCP}[0], Port: pointer.Int32(8080)},
+		},
+	}
+}
+
+func TestSnapshotJoinsServiceEndpointsAndTopology(t *testing.T) {
+	server := NewServer("node-a")
+
+	server.OnNodeAdd(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node-a",
+		Labels: map[string]string{v1.LabelTopologyZone: "zone-a"},
+	}})
+	server.OnServiceAdd(makeService("ns", "svc"))
+
+	slice := makeSlice("ns", "svc", "svc-1")
+	slice.Endpoints = []discovery.Endpoint{
+		{
+			// all conditions absent: defaults to ready and serving
+			Addresses: []string{"10.0.0.1"},
+			NodeName:  pointer.String("node-a"),
+			Zone:      pointer.String("zone-a"),
+			Hints:     &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: "zone-a"}}},
+		},
+		{
+			Addresses: []string{"10.0.0.2"},
+			NodeName:  pointer.String("node-b"),
+			Zone:      pointer.String("zone-b"),
+			Conditions: discovery.EndpointConditions{
+				Ready:       pointer.Bool(false),
+				Serving:     pointer.Bool(true),
+				Terminating: pointer.Bool(true),
+			},
+		},
+	}
+	server.OnEndpointSliceAdd(slice)
+
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/v1alpha1/snapshot")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	snapshot := Snapshot{}
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		t.Fatalf("malformed snapshot: %v", err)
+	}
+
+	if snapshot.APIVersion != APIVersion {
+		t.Errorf("expected apiVersion %q, got %q", APIVersion, snapshot.APIVersion)
+	}
+	if snapshot.Node == nil || snapshot.Node.Name != "node-a" || snapshot.Node.Zone != "zone-a" {
+		t.Errorf("unexpected node topology: %+v", snapshot.Node)
+	}
+	if len(snapshot.Services) != 1 {
+		t.Fatalf("expected one service, got %d", len(snapshot.Services))
+	}
+
+	program := snapshot.Services[0]
+	if program.Namespace != "ns" || program.Name != "svc" {
+		t.Errorf("unexpected service identity: %+v", program)
+	}
+	if !reflect.DeepEqual(program.ClusterIPs, []string{"172.30.0.1"}) ||
+		!reflect.DeepEqual(program.ExternalIPs, []string{"192.0.2.10"}) ||
+		!reflect.DeepEqual(program.LoadBalancerIPs, []string{"198.51.100.1"}) {
+		t.Errorf("unexpected service IPs: %+v", program)
+	}
+	if program.InternalTrafficPolicy != "Cluster" || program.ExternalTrafficPolicy != "Local" {
+		t.Errorf("unexpected traffic policies: %+v", program)
+	}
+	expectedPorts := []ServicePort{{Name: "http", Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080}}
+	if !reflect.DeepEqual(program.Ports, expectedPorts) {
+		t.Errorf("unexpected ports: %+v", program.Ports)
+	}
+
+	if len(program.Endpoints) != 2 {
+		t.Fatalf("expected two endpoints, got %d", len(program.Endpoints))
+	}
+	local := program.Endpoints[0]
+	if !local.Ready || !local.Serving || local.Terminating {
+		t.Errorf("expected absent conditions to default to a ready endpoint, got %+v", local)
+	}
+	if !local.Local || local.NodeName != "node-a" || local.Zone != "zone-a" {
+		t.Errorf("expected the endpoint on node-a to be marked local, got %+v", local)
+	}
+	if !reflect.DeepEqual(local.ZoneHints, []string{"zone-a"}) {
+		t.Errorf("unexpected zone hints: %+v", local)
+	}
+	if !reflect.DeepEqual(local.Ports, []EndpointPort{{Name: "http", Protocol: v1.ProtocolTCP, Port: 8080}}) {
+		t.Errorf("unexpected endpoint ports: %+v", local)
+	}
+	terminating := program.Endpoints[1]
+	if terminating.Ready || !terminating.Serving || !terminating.Terminating || terminating.Local {
+		t.Errorf("unexpected terminating endpoint: %+v", terminating)
+	}
+}
+
+// readEvents decodes count events from the watch stream.
+func readEvents(t *testing.T, reader *bufio.Reader, count int) []Event {
+	t.Helper()
+	events := make([]Event, 0, count)
+	for i := 0; i < count; i++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("watch stream ended early: %v", err)
+		}
+		event := Event{}
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatalf("malformed event %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestWatchReplaysStateAndStreamsChanges(t *testing.T) {
+	server := NewServer("node-a")
+	server.OnServiceAdd(makeService("ns", "svc"))
+	server.OnServiceSynced()
+	server.OnEndpointSlicesSynced()
+
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1alpha1/watch", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	// the stream must replay the current state and fence it with SYNC
+	replay := readEvents(t, reader, 2)
+	if replay[0].Type != EventSet || replay[0].Service == nil || replay[0].Service.Name != "svc" {
+		t.Fatalf("expected the existing service to be replayed, got %+v", replay[0])
+	}
+	if replay[1].Type != EventSync {
+		t.Fatalf("expected a SYNC event after the replay, got %+v", replay[1])
+	}
+
+	// live changes stream in order, with monotonically increasing revisions
+	server.OnEndpointSliceAdd(makeSlice("ns", "svc", "svc-1"))
+	server.OnServiceDelete(makeService("ns", "svc"))
+
+	live := readEvents(t, reader, 2)
+	if live[0].Type != EventSet || live[0].Service == nil {
+		t.Fatalf("expected a SET event for the slice change, got %+v", live[0])
+	}
+	if live[1].Type != EventDelete || live[1].Namespace != "ns" || live[1].Name != "svc" {
+		t.Fatalf("expected a DELETE event, got %+v", live[1])
+	}
+	if live[1].Revision <= live[0].Revision || live[0].Revision <= replay[1].Revision {
+		t.Errorf("expected monotonically increasing revisions, got %d, %d, %d", replay[1].Revision, live[0].Revision, live[1].Revision)
+	}
+}
+
+func TestSlicesWithoutServiceAreHeldBack(t *testing.T) {
+	server := NewServer("node-a")
+
+	// slices arriving before their service must not surface partial programs
+	slice := makeSlice("ns", "svc", "svc-1")
+	slice.Endpoints = []discovery.Endpoint{{Addresses: []string{"10.0.0.1"}}}
+	server.OnEndpointSliceAdd(slice)
+	server.lock.Lock()
+	snapshot := server.snapshot()
+	server.lock.Unlock()
+	if len(snapshot.Services) != 0 {
+		t.Fatalf("expected no services, got %+v", snapshot.Services)
+	}
+
+	// once the service arrives, the program includes the earlier slice
+	server.OnServiceAdd(makeService("ns", "svc"))
+	server.lock.Lock()
+	snapshot = server.snapshot()
+	server.lock.Unlock()
+	if len(snapshot.Services) != 1 || len(snapshot.Services[0].Endpoints) != 1 {
+		t.Fatalf("expected the service joined with the earlier slice, got %+v", snapshot.Services)
+	}
+}
+
+func TestLaggingWatcherIsDropped(t *testing.T) {
+	server := NewServer("node-a")
+	_, ch := server.subscribe()
+
+	for i := 0; i < watcherBufferSize+2; i++ {
+		server.OnServiceAdd(makeService("ns", "svc"))
+	}
+
+	deadline := time.After(30 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				// closed channel signals the consumer to re-list
+				return
+			}
+		case <-deadline:
+			t.Fatal("expected the lagging watcher to be dropped")
+		}
+	}
+}
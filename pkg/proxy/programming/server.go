@@ -0,0 +1,399 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package programming serves the joined service, endpoint slice and node
+// topology view of the cluster on a stable, versioned watch API, so external
+// dataplanes (e.g. eBPF agents) can program services without reimplementing
+// the merge/translate logic kube-proxy contains.
+//
+// The API is served over HTTP under /v1alpha1: GET /v1alpha1/snapshot returns
+// the full programmed state, GET /v1alpha1/watch streams one JSON event per
+// line, starting with a replay of the current state terminated by a SYNC
+// event.
+package programming
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// watcherBufferSize bounds the number of events buffered per watcher. A
+// watcher that falls this far behind is disconnected and must re-list.
+const watcherBufferSize = 128
+
+// Server maintains the joined view and serves it. It implements the
+// config.ServiceHandler, config.EndpointSliceHandler and config.NodeHandler
+// interfaces, so it is fed from the same watches as the proxier.
+type Server struct {
+	nodeName string
+
+	lock     sync.Mutex
+	services map[types.NamespacedName]*v1.Service
+	// slices indexes the endpoint slices of each service by slice name.
+	slices        map[types.NamespacedName]map[string]*discovery.EndpointSlice
+	node          *Node
+	revision      uint64
+	servicesSync  bool
+	slicesSync    bool
+	announcedSync bool
+	watchers      map[int]chan Event
+	nextWatcher   int
+}
+
+// NewServer returns a server joining state for the node with the given name.
+func NewServer(nodeName string) *Server {
+	return &Server{
+		nodeName: nodeName,
+		services: map[types.NamespacedName]*v1.Service{},
+		slices:   map[types.NamespacedName]map[string]*discovery.EndpointSlice{},
+		watchers: map[int]chan Event{},
+	}
+}
+
+// OnServiceAdd implements config.ServiceHandler.
+func (s *Server) OnServiceAdd(service *v1.Service) {
+	s.OnServiceUpdate(nil, service)
+}
+
+// OnServiceUpdate implements config.ServiceHandler.
+func (s *Server) OnServiceUpdate(oldService, service *v1.Service) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	name := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+	s.services[name] = service
+	s.broadcastService(name)
+}
+
+// OnServiceDelete implements config.ServiceHandler.
+func (s *Server) OnServiceDelete(service *v1.Service) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	name := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+	delete(s.services, name)
+	s.revision++
+	s.broadcast(Event{Type: EventDelete, Revision: s.revision, Namespace: name.Namespace, Name: name.Name})
+}
+
+// OnServiceSynced implements config.ServiceHandler.
+func (s *Server) OnServiceSynced() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.servicesSync = true
+	s.maybeAnnounceSync()
+}
+
+// OnEndpointSliceAdd implements config.EndpointSliceHandler.
+func (s *Server) OnEndpointSliceAdd(slice *discovery.EndpointSlice) {
+	s.OnEndpointSliceUpdate(nil, slice)
+}
+
+// OnEndpointSliceUpdate implements config.EndpointSliceHandler.
+func (s *Server) OnEndpointSliceUpdate(oldSlice, slice *discovery.EndpointSlice) {
+	name, ok := sliceServiceName(slice)
+	if !ok {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.slices[name] == nil {
+		s.slices[name] = map[string]*discovery.EndpointSlice{}
+	}
+	s.slices[name][slice.Name] = slice
+	s.broadcastService(name)
+}
+
+// OnEndpointSliceDelete implements config.EndpointSliceHandler.
+func (s *Server) OnEndpointSliceDelete(slice *discovery.EndpointSlice) {
+	name, ok := sliceServiceName(slice)
+	if !ok {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.slices[name], slice.Name)
+	if len(s.slices[name]) == 0 {
+		delete(s.slices, name)
+	}
+	s.broadcastService(name)
+}
+
+// OnEndpointSlicesSynced implements config.EndpointSliceHandler.
+func (s *Server) OnEndpointSlicesSynced() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.slicesSync = true
+	s.maybeAnnounceSync()
+}
+
+// OnNodeAdd implements config.NodeHandler.
+func (s *Server) OnNodeAdd(node *v1.Node) {
+	s.OnNodeUpdate(nil, node)
+}
+
+// OnNodeUpdate implements config.NodeHandler.
+func (s *Server) OnNodeUpdate(oldNode, node *v1.Node) {
+	if node.Name != s.nodeName {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.node = &Node{Name: node.Name, Zone: node.Labels[v1.LabelTopologyZone], Labels: node.Labels}
+	s.revision++
+	s.broadcast(Event{Type: EventNode, Revision: s.revision, Node: s.node})
+}
+
+// OnNodeDelete implements config.NodeHandler.
+func (s *Server) OnNodeDelete(node *v1.Node) {}
+
+// OnNodeSynced implements config.NodeHandler.
+func (s *Server) OnNodeSynced() {}
+
+func sliceServiceName(slice *discovery.EndpointSlice) (types.NamespacedName, bool) {
+	serviceName, ok := slice.Labels[discovery.LabelServiceName]
+	if !ok || serviceName == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: slice.Namespace, Name: serviceName}, true
+}
+
+// broadcastService bumps the revision and sends the re-joined program of name
+// to all watchers. Callers must hold the lock.
+func (s *Server) broadcastService(name types.NamespacedName) {
+	service, ok := s.services[name]
+	if !ok {
+		// slice churn for a service we have not seen yet; the program is
+		// broadcast once the service arrives
+		return
+	}
+	s.revision++
+	program := s.program(service)
+	s.broadcast(Event{Type: EventSet, Revision: s.revision, Service: &program})
+}
+
+// maybeAnnounceSync sends the one-time SYNC event once both initial listings
+// finished. Callers must hold the lock.
+func (s *Server) maybeAnnounceSync() {
+	if s.announcedSync || !s.servicesSync || !s.slicesSync {
+		return
+	}
+	s.announcedSync = true
+	s.broadcast(Event{Type: EventSync, Revision: s.revision})
+}
+
+// broadcast delivers event to all watchers, dropping watchers that are too
+// far behind. Callers must hold the lock.
+func (s *Server) broadcast(event Event) {
+	for id, ch := range s.watchers {
+		select {
+		case ch <- event:
+		default:
+			klog.V(2).InfoS("Dropping service programming watcher that fell behind", "watcher", id)
+			close(ch)
+			delete(s.watchers, id)
+		}
+	}
+}
+
+// program joins a service with its endpoint slices and the node topology.
+// Callers must hold the lock.
+func (s *Server) program(service *v1.Service) ServiceProgram {
+	name := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+	program := ServiceProgram{
+		Namespace:       service.Namespace,
+		Name:            service.Name,
+		SessionAffinity: service.Spec.SessionAffinity,
+		ExternalIPs:     service.Spec.ExternalIPs,
+	}
+	for _, ip := range service.Spec.ClusterIPs {
+		if ip != v1.ClusterIPNone {
+			program.ClusterIPs = append(program.ClusterIPs, ip)
+		}
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			program.LoadBalancerIPs = append(program.LoadBalancerIPs, ingress.IP)
+		}
+	}
+	if service.Spec.InternalTrafficPolicy != nil {
+		program.InternalTrafficPolicy = string(*service.Spec.InternalTrafficPolicy)
+	}
+	program.ExternalTrafficPolicy = string(service.Spec.ExternalTrafficPolicy)
+	for _, port := range service.Spec.Ports {
+		program.Ports = append(program.Ports, ServicePort{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Port:     port.Port,
+			NodePort: port.NodePort,
+		})
+	}
+
+	sliceNames := make([]string, 0, len(s.slices[name]))
+	for sliceName := range s.slices[name] {
+		sliceNames = append(sliceNames, sliceName)
+	}
+	sort.Strings(sliceNames)
+	for _, sliceName := range sliceNames {
+		slice := s.slices[name][sliceName]
+		ports := make([]EndpointPort, 0, len(slice.Ports))
+		for _, port := range slice.Ports {
+			endpointPort := EndpointPort{}
+			if port.Name != nil {
+				endpointPort.Name = *port.Name
+			}
+			if port.Protocol != nil {
+				endpointPort.Protocol = *port.Protocol
+			}
+			if port.Port != nil {
+				endpointPort.Port = *port.Port
+			}
+			ports = append(ports, endpointPort)
+		}
+		for _, endpoint := range slice.Endpoints {
+			joined := Endpoint{
+				Addresses: endpoint.Addresses,
+				Ports:     ports,
+				// conditions default exactly like kube-proxy defaults them:
+				// absent Ready means ready, absent Serving follows Ready,
+				// absent Terminating means not terminating
+				Ready:       endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready,
+				Terminating: endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating,
+			}
+			if endpoint.Conditions.Serving != nil {
+				joined.Serving = *endpoint.Conditions.Serving
+			} else {
+				joined.Serving = joined.Ready
+			}
+			if endpoint.NodeName != nil {
+				joined.NodeName = *endpoint.NodeName
+				joined.Local = *endpoint.NodeName == s.nodeName
+			}
+			if endpoint.Zone != nil {
+				joined.Zone = *endpoint.Zone
+			}
+			if endpoint.Hints != nil {
+				for _, hint := range endpoint.Hints.ForZones {
+					joined.ZoneHints = append(joined.ZoneHints, hint.Name)
+				}
+			}
+			program.Endpoints = append(program.Endpoints, joined)
+		}
+	}
+	return program
+}
+
+// snapshot returns the full current state. Callers must hold the lock.
+func (s *Server) snapshot() Snapshot {
+	snapshot := Snapshot{APIVersion: APIVersion, Revision: s.revision, Node: s.node, Services: []ServiceProgram{}}
+	names := make([]types.NamespacedName, 0, len(s.services))
+	for name := range s.services {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+	for _, name := range names {
+		snapshot.Services = append(snapshot.Services, s.program(s.services[name]))
+	}
+	return snapshot
+}
+
+// subscribe registers a watcher and returns its channel, primed with a replay
+// of the current state terminated by a SYNC event.
+func (s *Server) subscribe() (int, chan Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ch := make(chan Event, watcherBufferSize)
+	if s.node != nil {
+		ch <- Event{Type: EventNode, Revision: s.revision, Node: s.node}
+	}
+	for _, program := range s.snapshot().Services {
+		program := program
+		ch <- Event{Type: EventSet, Revision: s.revision, Service: &program}
+	}
+	ch <- Event{Type: EventSync, Revision: s.revision}
+	id := s.nextWatcher
+	s.nextWatcher++
+	s.watchers[id] = ch
+	return id, ch
+}
+
+func (s *Server) unsubscribe(id int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if ch, ok := s.watchers[id]; ok {
+		close(ch)
+		delete(s.watchers, id)
+	}
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1alpha1/snapshot", s.serveSnapshot)
+	mux.HandleFunc("/v1alpha1/watch", s.serveWatch)
+	return mux
+}
+
+func (s *Server) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	snapshot := s.snapshot()
+	s.lock.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		http.Error(w, "failed to encode snapshot", http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) serveWatch(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "watch requires a streaming capable client", http.StatusInternalServerError)
+		return
+	}
+	id, ch := s.subscribe()
+	defer s.unsubscribe(id)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				// the watcher fell behind and was dropped; the client must
+				// re-list from /v1alpha1/snapshot
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// String implements fmt.Stringer for log messages.
+func (s *Server) String() string {
+	return fmt.Sprintf("service programming API %s for node %s", APIVersion, s.nodeName)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package programming
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// APIVersion identifies the wire format of the service programming API. The
+// format is versioned independently of kube-proxy so external dataplanes can
+// pin against it; incompatible changes require a new version under a new
+// path prefix.
+const APIVersion = "programming.proxy.k8s.io/v1alpha1"
+
+// ServicePort is one port of a programmed service.
+type ServicePort struct {
+	// Name is the name of the port within the service; empty for single-port
+	// services. Endpoint ports with the same name carry its traffic.
+	Name     string      `json:"name,omitempty"`
+	Protocol v1.Protocol `json:"protocol"`
+	// Port is the service-facing port on the cluster and external IPs.
+	Port int32 `json:"port"`
+	// NodePort is the node-facing port, zero when not allocated.
+	NodePort int32 `json:"nodePort,omitempty"`
+}
+
+// EndpointPort is one target port of an endpoint.
+type EndpointPort struct {
+	// Name associates the port with the service port of the same name.
+	Name     string      `json:"name,omitempty"`
+	Protocol v1.Protocol `json:"protocol"`
+	Port     int32       `json:"port"`
+}
+
+// Endpoint is one backend of a programmed service, joined with its topology.
+type Endpoint struct {
+	Addresses []string       `json:"addresses"`
+	Ports     []EndpointPort `json:"ports"`
+	// NodeName and Zone locate the endpoint for locality decisions.
+	NodeName string `json:"nodeName,omitempty"`
+	Zone     string `json:"zone,omitempty"`
+	// ZoneHints lists the zones the endpoint should serve, when the
+	// EndpointSlice controller assigned topology hints.
+	ZoneHints []string `json:"zoneHints,omitempty"`
+	// Local is true when the endpoint runs on the node serving this API.
+	Local bool `json:"local,omitempty"`
+	// Ready, Serving and Terminating mirror the endpoint conditions, with
+	// absent conditions defaulted the same way kube-proxy defaults them.
+	Ready       bool `json:"ready"`
+	Serving     bool `json:"serving"`
+	Terminating bool `json:"terminating"`
+}
+
+// ServiceProgram is the joined service, endpoint and topology view of one
+// service: everything a dataplane needs to program it, with the
+// merge/translate logic kube-proxy contains already applied.
+type ServiceProgram struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	ClusterIPs      []string `json:"clusterIPs,omitempty"`
+	ExternalIPs     []string `json:"externalIPs,omitempty"`
+	LoadBalancerIPs []string `json:"loadBalancerIPs,omitempty"`
+
+	SessionAffinity       v1.ServiceAffinity `json:"sessionAffinity"`
+	InternalTrafficPolicy string             `json:"internalTrafficPolicy,omitempty"`
+	ExternalTrafficPolicy string             `json:"externalTrafficPolicy,omitempty"`
+
+	Ports     []ServicePort `json:"ports"`
+	Endpoints []Endpoint    `json:"endpoints"`
+}
+
+// Node is the topology of the node serving the API.
+type Node struct {
+	Name   string            `json:"name"`
+	Zone   string            `json:"zone,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Snapshot is the full programmed state at one revision.
+type Snapshot struct {
+	APIVersion string `json:"apiVersion"`
+	// Revision increases on every state change; watch events carry the
+	// revision they correspond to so a snapshot and a watch can be stitched
+	// together.
+	Revision uint64           `json:"revision"`
+	Node     *Node            `json:"node,omitempty"`
+	Services []ServiceProgram `json:"services"`
+}
+
+// EventType classifies a watch event.
+type EventType string
+
+const (
+	// EventSet carries the complete new program of one service; it covers
+	// creation and every kind of update.
+	EventSet EventType = "SET"
+	// EventDelete signals that the named service is gone.
+	EventDelete EventType = "DELETE"
+	// EventNode carries updated topology of the local node.
+	EventNode EventType = "NODE"
+	// EventSync signals that the state up to this revision is complete; it is
+	// sent once the initial listing finished and after the replayed snapshot
+	// on every new watch.
+	EventSync EventType = "SYNC"
+)
+
+// Event is one entry of the watch stream, encoded as one JSON object per
+// line.
+type Event struct {
+	Type     EventType `json:"type"`
+	Revision uint64    `json:"revision"`
+	// Service is set on SET events.
+	Service *ServiceProgram `json:"service,omitempty"`
+	// Namespace and Name identify the service on DELETE events.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// Node is set on NODE events.
+	Node *Node `json:"node,omitempty"`
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -40,20 +41,23 @@ import (
 // or can be used for constructing a more specific ServiceInfo struct
 // defined by the proxier if needed.
 type BaseServicePortInfo struct {
-	clusterIP                net.IP
-	port                     int
-	protocol                 v1.Protocol
-	nodePort                 int
-	loadBalancerStatus       v1.LoadBalancerStatus
-	sessionAffinityType      v1.ServiceAffinity
-	stickyMaxAgeSeconds      int
-	externalIPs              []string
-	loadBalancerSourceRanges []string
-	healthCheckNodePort      int
-	externalPolicyLocal      bool
-	internalPolicyLocal      bool
-	internalTrafficPolicy    *v1.ServiceInternalTrafficPolicyType
-	hintsAnnotation          string
+	clusterIP                     net.IP
+	port                          int
+	protocol                      v1.Protocol
+	nodePort                      int
+	loadBalancerStatus            v1.LoadBalancerStatus
+	sessionAffinityType           v1.ServiceAffinity
+	stickyMaxAgeSeconds           int
+	externalIPs                   []string
+	loadBalancerSourceRanges      []string
+	healthCheckNodePort           int
+	externalPolicyLocal           bool
+	internalPolicyLocal           bool
+	externalPolicyPreferLocal     bool
+	internalPolicyPreferLocal     bool
+	preferLocalSpilloverThreshold int
+	internalTrafficPolicy         *v1.ServiceInternalTrafficPolicyType
+	hintsAnnotation               string
 }
 
 var _ ServicePort = &BaseServicePortInfo{}
@@ -127,6 +131,21 @@ func (bsvcPortInfo *BaseServicePortInfo) InternalPolicyLocal() bool {
 	return bsvcPortInfo.internalPolicyLocal
 }
 
+// ExternalPolicyPreferLocal is part of ServicePort interface.
+func (bsvcPortInfo *BaseServicePortInfo) ExternalPolicyPreferLocal() bool {
+	return bsvcPortInfo.externalPolicyPreferLocal
+}
+
+// InternalPolicyPreferLocal is part of ServicePort interface
+func (bsvcPortInfo *BaseServicePortInfo) InternalPolicyPreferLocal() bool {
+	return bsvcPortInfo.internalPolicyPreferLocal
+}
+
+// PreferLocalSpilloverThreshold is part of ServicePort interface.
+func (bsvcPortInfo *BaseServicePortInfo) PreferLocalSpilloverThreshold() int {
+	return bsvcPortInfo.preferLocalSpilloverThreshold
+}
+
 // InternalTrafficPolicy is part of ServicePort interface
 func (bsvcPortInfo *BaseServicePortInfo) InternalTrafficPolicy() *v1.ServiceInternalTrafficPolicyType {
 	return bsvcPortInfo.internalTrafficPolicy
@@ -158,6 +177,18 @@ func (bsvcPortInfo *BaseServicePortInfo) UsesLocalEndpoints() bool {
 func (sct *ServiceChangeTracker) newBaseServiceInfo(port *v1.ServicePort, service *v1.Service) *BaseServicePortInfo {
 	externalPolicyLocal := apiservice.ExternalPolicyLocal(service)
 	internalPolicyLocal := apiservice.InternalPolicyLocal(service)
+	externalPolicyPreferLocal := apiservice.ExternalPolicyPreferLocal(service)
+	internalPolicyPreferLocal := apiservice.InternalPolicyPreferLocal(service)
+
+	preferLocalSpilloverThreshold := 1
+	if value, ok := service.Annotations[v1.AnnotationPreferLocalSpillover]; ok {
+		if threshold, err := strconv.Atoi(value); err == nil && threshold > 0 {
+			preferLocalSpilloverThreshold = threshold
+		} else {
+			klog.ErrorS(err, "Ignoring invalid prefer-local spillover threshold annotation",
+				"annotation", v1.AnnotationPreferLocalSpillover, "value", value, "service", klog.KObj(service))
+		}
+	}
 
 	var stickyMaxAgeSeconds int
 	if service.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
@@ -167,16 +198,19 @@ func (sct *ServiceChangeTracker) newBaseServiceInfo(port *v1.ServicePort, servic
 
 	clusterIP := utilproxy.GetClusterIPByFamily(sct.ipFamily, service)
 	info := &BaseServicePortInfo{
-		clusterIP:             netutils.ParseIPSloppy(clusterIP),
-		port:                  int(port.Port),
-		protocol:              port.Protocol,
-		nodePort:              int(port.NodePort),
-		sessionAffinityType:   service.Spec.SessionAffinity,
-		stickyMaxAgeSeconds:   stickyMaxAgeSeconds,
-		externalPolicyLocal:   externalPolicyLocal,
-		internalPolicyLocal:   internalPolicyLocal,
-		internalTrafficPolicy: service.Spec.InternalTrafficPolicy,
-		hintsAnnotation:       service.Annotations[v1.AnnotationTopologyAwareHints],
+		clusterIP:                     netutils.ParseIPSloppy(clusterIP),
+		port:                          int(port.Port),
+		protocol:                      port.Protocol,
+		nodePort:                      int(port.NodePort),
+		sessionAffinityType:           service.Spec.SessionAffinity,
+		stickyMaxAgeSeconds:           stickyMaxAgeSeconds,
+		externalPolicyLocal:           externalPolicyLocal,
+		internalPolicyLocal:           internalPolicyLocal,
+		externalPolicyPreferLocal:     externalPolicyPreferLocal,
+		internalPolicyPreferLocal:     internalPolicyPreferLocal,
+		preferLocalSpilloverThreshold: preferLocalSpilloverThreshold,
+		internalTrafficPolicy:         service.Spec.InternalTrafficPolicy,
+		hintsAnnotation:               service.Annotations[v1.AnnotationTopologyAwareHints],
 	}
 
 	loadBalancerSourceRanges := make([]string, len(service.Spec.LoadBalancerSourceRanges))
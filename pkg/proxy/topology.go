@@ -28,16 +28,23 @@ import (
 //   - The service's usable Cluster-traffic-policy endpoints (taking topology into account, if
 //     relevant). This will be nil if the service does not ever use Cluster traffic policy.
 //
+//   - The service's usable PreferLocal-traffic-policy endpoints. This will be nil unless the
+//     service uses PreferLocal traffic policy and has enough ready local endpoints to stay
+//     node-local; traffic under a PreferLocal policy then uses this set, and falls back to the
+//     Cluster-traffic-policy endpoints otherwise. It is computed separately from the Cluster
+//     set so that a PreferLocal policy for one kind of traffic never changes the routing of
+//     traffic governed by the other policy.
+//
 //   - The service's usable Local-traffic-policy endpoints (including terminating endpoints, if
 //     relevant). This will be nil if the service does not ever use Local traffic policy.
 //
 //   - The combined list of all endpoints reachable from this node (which is the union of the
-//     previous two lists, but in the case where it is identical to one or the other, we avoid
+//     previous lists, but in the case where it is identical to one or the other, we avoid
 //     allocating a separate list).
 //
 //   - An indication of whether the service has any endpoints reachable from anywhere in the
 //     cluster. (This may be true even if allReachableEndpoints is empty.)
-func CategorizeEndpoints(endpoints []Endpoint, svcInfo ServicePort, nodeLabels map[string]string) (clusterEndpoints, localEndpoints, allReachableEndpoints []Endpoint, hasAnyEndpoints bool) {
+func CategorizeEndpoints(endpoints []Endpoint, svcInfo ServicePort, nodeLabels map[string]string) (clusterEndpoints, preferLocalEndpoints, localEndpoints, allReachableEndpoints []Endpoint, hasAnyEndpoints bool) {
 	var useTopology, useServingTerminatingEndpoints bool
 
 	if svcInfo.UsesClusterEndpoints() {
@@ -70,6 +77,22 @@ func CategorizeEndpoints(endpoints []Endpoint, svcInfo ServicePort, nodeLabels m
 		if len(clusterEndpoints) > 0 {
 			hasAnyEndpoints = true
 		}
+
+		// For PreferLocal traffic policies, compute the local endpoints that
+		// policy narrows to, as long as enough of them are ready. Endpoints
+		// whose probes fail drop out of the ready set, so traffic spills over
+		// to the remote endpoints once the local ones fall below the configured
+		// threshold. The narrowed set is kept separate from clusterEndpoints
+		// because internal and external traffic decide independently whether
+		// their policy is PreferLocal.
+		if svcInfo.InternalPolicyPreferLocal() || svcInfo.ExternalPolicyPreferLocal() {
+			localReadyEndpoints := filterEndpoints(clusterEndpoints, func(ep Endpoint) bool {
+				return ep.GetIsLocal() && ep.IsReady()
+			})
+			if len(localReadyEndpoints) >= svcInfo.PreferLocalSpilloverThreshold() {
+				preferLocalEndpoints = localReadyEndpoints
+			}
+		}
 	}
 
 	if !svcInfo.UsesLocalEndpoints() {
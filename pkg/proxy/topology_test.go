@@ -60,10 +60,11 @@ func TestCategorizeEndpoints(t *testing.T) {
 		// localEndpoints is set, and allEndpoints is identical to it.
 		// onlyRemoteEndpoints should be true if CategorizeEndpoints returns true for
 		// hasAnyEndpoints despite allEndpoints being empty.
-		clusterEndpoints    sets.String
-		localEndpoints      sets.String
-		allEndpoints        sets.String
-		onlyRemoteEndpoints bool
+		clusterEndpoints     sets.String
+		preferLocalEndpoints sets.String
+		localEndpoints       sets.String
+		allEndpoints         sets.String
+		onlyRemoteEndpoints  bool
 	}{{
 		name:         "hints enabled, hints annotation == auto",
 		hintsEnabled: true,
@@ -485,6 +486,68 @@ func TestCategorizeEndpoints(t *testing.T) {
 		clusterEndpoints: nil,
 		localEndpoints:   sets.NewString("10.0.0.1:80"),
 		allEndpoints:     sets.NewString("10.0.0.1:80"),
+	}, {
+		name:        "iTP:PreferLocal prefers ready local endpoints",
+		serviceInfo: &BaseServicePortInfo{internalPolicyPreferLocal: true, preferLocalSpilloverThreshold: 1},
+		endpoints: []Endpoint{
+			&BaseEndpointInfo{Endpoint: "10.0.0.0:80", Ready: true, IsLocal: false},
+			&BaseEndpointInfo{Endpoint: "10.0.0.1:80", Ready: true, IsLocal: true},
+		},
+		clusterEndpoints:     sets.NewString("10.0.0.0:80", "10.0.0.1:80"),
+		preferLocalEndpoints: sets.NewString("10.0.0.1:80"),
+		localEndpoints:       nil,
+	}, {
+		name:        "iTP:PreferLocal spills over when local endpoints are below the threshold",
+		serviceInfo: &BaseServicePortInfo{internalPolicyPreferLocal: true, preferLocalSpilloverThreshold: 2},
+		endpoints: []Endpoint{
+			&BaseEndpointInfo{Endpoint: "10.0.0.0:80", Ready: true, IsLocal: false},
+			&BaseEndpointInfo{Endpoint: "10.0.0.1:80", Ready: true, IsLocal: true},
+		},
+		clusterEndpoints:     sets.NewString("10.0.0.0:80", "10.0.0.1:80"),
+		preferLocalEndpoints: nil,
+		localEndpoints:       nil,
+	}, {
+		name:        "iTP:PreferLocal spills over when local endpoints are not ready",
+		serviceInfo: &BaseServicePortInfo{internalPolicyPreferLocal: true, preferLocalSpilloverThreshold: 1},
+		endpoints: []Endpoint{
+			&BaseEndpointInfo{Endpoint: "10.0.0.0:80", Ready: true, IsLocal: false},
+			&BaseEndpointInfo{Endpoint: "10.0.0.1:80", Ready: false, IsLocal: true},
+		},
+		clusterEndpoints:     sets.NewString("10.0.0.0:80"),
+		preferLocalEndpoints: nil,
+		localEndpoints:       nil,
+	}, {
+		name:        "eTP:PreferLocal prefers ready local endpoints for externally accessible service",
+		serviceInfo: &BaseServicePortInfo{externalPolicyPreferLocal: true, preferLocalSpilloverThreshold: 1, nodePort: 8080},
+		endpoints: []Endpoint{
+			&BaseEndpointInfo{Endpoint: "10.0.0.0:80", Ready: true, IsLocal: false},
+			&BaseEndpointInfo{Endpoint: "10.0.0.1:80", Ready: true, IsLocal: true},
+		},
+		clusterEndpoints:     sets.NewString("10.0.0.0:80", "10.0.0.1:80"),
+		preferLocalEndpoints: sets.NewString("10.0.0.1:80"),
+		localEndpoints:       nil,
+	}, {
+		name:        "eTP:PreferLocal does not narrow the cluster endpoints used by internal traffic",
+		serviceInfo: &BaseServicePortInfo{externalPolicyPreferLocal: true, preferLocalSpilloverThreshold: 1, nodePort: 8080},
+		endpoints: []Endpoint{
+			&BaseEndpointInfo{Endpoint: "10.0.0.0:80", Ready: true, IsLocal: false},
+			&BaseEndpointInfo{Endpoint: "10.0.0.1:80", Ready: true, IsLocal: true},
+			&BaseEndpointInfo{Endpoint: "10.0.0.2:80", Ready: true, IsLocal: false},
+		},
+		clusterEndpoints:     sets.NewString("10.0.0.0:80", "10.0.0.1:80", "10.0.0.2:80"),
+		preferLocalEndpoints: sets.NewString("10.0.0.1:80"),
+		localEndpoints:       nil,
+	}, {
+		name:        "iTP:Local eTP:PreferLocal computes separate endpoint sets",
+		serviceInfo: &BaseServicePortInfo{internalPolicyLocal: true, externalPolicyPreferLocal: true, preferLocalSpilloverThreshold: 1, nodePort: 8080},
+		endpoints: []Endpoint{
+			&BaseEndpointInfo{Endpoint: "10.0.0.0:80", Ready: true, IsLocal: false},
+			&BaseEndpointInfo{Endpoint: "10.0.0.1:80", Ready: true, IsLocal: true},
+		},
+		clusterEndpoints:     sets.NewString("10.0.0.0:80", "10.0.0.1:80"),
+		preferLocalEndpoints: sets.NewString("10.0.0.1:80"),
+		localEndpoints:       sets.NewString("10.0.0.1:80"),
+		allEndpoints:         sets.NewString("10.0.0.0:80", "10.0.0.1:80"),
 	}}
 
 	for _, tc := range testCases {
@@ -492,7 +555,7 @@ func TestCategorizeEndpoints(t *testing.T) {
 			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.TopologyAwareHints, tc.hintsEnabled)()
 			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ProxyTerminatingEndpoints, tc.pteEnabled)()
 
-			clusterEndpoints, localEndpoints, allEndpoints, hasAnyEndpoints := CategorizeEndpoints(tc.endpoints, tc.serviceInfo, tc.nodeLabels)
+			clusterEndpoints, preferLocalEndpoints, localEndpoints, allEndpoints, hasAnyEndpoints := CategorizeEndpoints(tc.endpoints, tc.serviceInfo, tc.nodeLabels)
 
 			if tc.clusterEndpoints == nil && clusterEndpoints != nil {
 				t.Errorf("expected no cluster endpoints but got %v", clusterEndpoints)
@@ -503,6 +566,15 @@ func TestCategorizeEndpoints(t *testing.T) {
 				}
 			}
 
+			if tc.preferLocalEndpoints == nil && preferLocalEndpoints != nil {
+				t.Errorf("expected no prefer-local endpoints but got %v", preferLocalEndpoints)
+			} else {
+				err := checkExpectedEndpoints(tc.preferLocalEndpoints, preferLocalEndpoints)
+				if err != nil {
+					t.Errorf("error with prefer-local endpoints: %v", err)
+				}
+			}
+
 			if tc.localEndpoints == nil && localEndpoints != nil {
 				t.Errorf("expected no local endpoints but got %v", localEndpoints)
 			} else {
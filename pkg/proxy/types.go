@@ -87,6 +87,15 @@ type ServicePort interface {
 	ExternalPolicyLocal() bool
 	// InternalPolicyLocal returns if a service has only node local endpoints for internal traffic.
 	InternalPolicyLocal() bool
+	// ExternalPolicyPreferLocal returns if a service prefers node local endpoints for
+	// external traffic but spills over to remote endpoints when too few are ready.
+	ExternalPolicyPreferLocal() bool
+	// InternalPolicyPreferLocal returns if a service prefers node local endpoints for
+	// internal traffic but spills over to remote endpoints when too few are ready.
+	InternalPolicyPreferLocal() bool
+	// PreferLocalSpilloverThreshold returns the minimum number of ready local
+	// endpoints a PreferLocal service needs to keep traffic node-local.
+	PreferLocalSpilloverThreshold() int
 	// InternalTrafficPolicy returns service InternalTrafficPolicy
 	InternalTrafficPolicy() *v1.ServiceInternalTrafficPolicyType
 	// HintsAnnotation returns the value of the v1.AnnotationTopologyAwareHints annotation.
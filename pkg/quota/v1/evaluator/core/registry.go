@@ -39,6 +39,7 @@ func NewEvaluators(f quota.ListerForResourceFunc) []quota.Evaluator {
 		NewPodEvaluator(f, clock.RealClock{}),
 		NewServiceEvaluator(f),
 		NewPersistentVolumeClaimEvaluator(f),
+		NewResourceClaimEvaluator(f),
 	}
 	// these evaluators require an alias for backwards compatibility
 	for gvr, alias := range legacyObjectCountAliases {
@@ -24,9 +24,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/storage/names"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
 	api "k8s.io/kubernetes/pkg/apis/core"
 	"k8s.io/kubernetes/pkg/apis/core/validation"
+	"k8s.io/kubernetes/pkg/features"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 )
 
@@ -114,7 +116,27 @@ func (svcStrategy) AllowUnconditionalUpdate() bool {
 //	    newSvc.Spec.MyFeature = nil
 //	}
 func dropServiceDisabledFields(newSvc *api.Service, oldSvc *api.Service) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ServiceTrafficPolicyPreferLocal) && !preferLocalTrafficPolicyInUse(oldSvc) {
+		if newSvc.Spec.ExternalTrafficPolicy == api.ServiceExternalTrafficPolicyTypePreferLocal {
+			newSvc.Spec.ExternalTrafficPolicy = api.ServiceExternalTrafficPolicyTypeCluster
+		}
+		if newSvc.Spec.InternalTrafficPolicy != nil && *newSvc.Spec.InternalTrafficPolicy == api.ServiceInternalTrafficPolicyPreferLocal {
+			clusterPolicy := api.ServiceInternalTrafficPolicyCluster
+			newSvc.Spec.InternalTrafficPolicy = &clusterPolicy
+		}
+	}
+}
 
+// preferLocalTrafficPolicyInUse returns true if the svc uses the PreferLocal
+// value for either traffic policy.
+func preferLocalTrafficPolicyInUse(svc *api.Service) bool {
+	if svc == nil {
+		return false
+	}
+	if svc.Spec.ExternalTrafficPolicy == api.ServiceExternalTrafficPolicyTypePreferLocal {
+		return true
+	}
+	return svc.Spec.InternalTrafficPolicy != nil && *svc.Spec.InternalTrafficPolicy == api.ServiceInternalTrafficPolicyPreferLocal
 }
 
 type serviceStatusStrategy struct {
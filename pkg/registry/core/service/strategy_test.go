@@ -26,8 +26,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	api "k8s.io/kubernetes/pkg/apis/core"
 	_ "k8s.io/kubernetes/pkg/apis/core/install"
+	"k8s.io/kubernetes/pkg/features"
 	utilpointer "k8s.io/utils/pointer"
 )
 
@@ -229,6 +232,65 @@ func TestDropDisabledField(t *testing.T) {
 
 }
 
+func TestDropServiceTrafficPolicyPreferLocal(t *testing.T) {
+	makeService := func(itp api.ServiceInternalTrafficPolicyType, etp api.ServiceExternalTrafficPolicyType) *api.Service {
+		return &api.Service{
+			Spec: api.ServiceSpec{
+				InternalTrafficPolicy: &itp,
+				ExternalTrafficPolicy: etp,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		gateEnabled bool
+		svc         *api.Service
+		oldSvc      *api.Service
+		compareSvc  *api.Service
+	}{
+		{
+			name:        "gate enabled, PreferLocal kept",
+			gateEnabled: true,
+			svc:         makeService(api.ServiceInternalTrafficPolicyPreferLocal, api.ServiceExternalTrafficPolicyTypePreferLocal),
+			oldSvc:      nil,
+			compareSvc:  makeService(api.ServiceInternalTrafficPolicyPreferLocal, api.ServiceExternalTrafficPolicyTypePreferLocal),
+		},
+		{
+			name:        "gate disabled, PreferLocal dropped to Cluster",
+			gateEnabled: false,
+			svc:         makeService(api.ServiceInternalTrafficPolicyPreferLocal, api.ServiceExternalTrafficPolicyTypePreferLocal),
+			oldSvc:      nil,
+			compareSvc:  makeService(api.ServiceInternalTrafficPolicyCluster, api.ServiceExternalTrafficPolicyTypeCluster),
+		},
+		{
+			name:        "gate disabled, PreferLocal kept when already in use in old",
+			gateEnabled: false,
+			svc:         makeService(api.ServiceInternalTrafficPolicyPreferLocal, api.ServiceExternalTrafficPolicyTypePreferLocal),
+			oldSvc:      makeService(api.ServiceInternalTrafficPolicyPreferLocal, api.ServiceExternalTrafficPolicyTypeCluster),
+			compareSvc:  makeService(api.ServiceInternalTrafficPolicyPreferLocal, api.ServiceExternalTrafficPolicyTypePreferLocal),
+		},
+		{
+			name:        "gate disabled, other policies untouched",
+			gateEnabled: false,
+			svc:         makeService(api.ServiceInternalTrafficPolicyLocal, api.ServiceExternalTrafficPolicyTypeLocal),
+			oldSvc:      nil,
+			compareSvc:  makeService(api.ServiceInternalTrafficPolicyLocal, api.ServiceExternalTrafficPolicyTypeLocal),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ServiceTrafficPolicyPreferLocal, tc.gateEnabled)()
+
+			dropServiceDisabledFields(tc.svc, tc.oldSvc)
+
+			if !reflect.DeepEqual(tc.svc, tc.compareSvc) {
+				t.Errorf("unexpected svc spec: %v", diff.ObjectReflectDiff(tc.svc, tc.compareSvc))
+			}
+		})
+	}
+}
+
 func TestDropTypeDependentFields(t *testing.T) {
 	// Tweaks used below.
 	setTypeExternalName := func(svc *api.Service) {
@@ -130,6 +130,27 @@ type KubeSchedulerProfile struct {
 	// Omitting config args for a plugin is equivalent to using the default config
 	// for that plugin.
 	PluginConfig []PluginConfig
+
+	// PluginExecutionBudgets is an optional list of per-plugin execution time
+	// budgets. A plugin whose Filter or Score calls repeatedly exceed its
+	// budget can have its Score calls temporarily skipped, protecting
+	// scheduling throughput from a single slow plugin. Filter calls are never
+	// skipped, because they enforce hard constraints.
+	PluginExecutionBudgets []PluginExecutionBudget
+}
+
+// PluginExecutionBudget is an execution time budget for a single plugin.
+type PluginExecutionBudget struct {
+	// Name is the name of the plugin being budgeted.
+	Name string
+	// Budget is the maximum duration a single Filter or Score call of the
+	// plugin is expected to take.
+	Budget metav1.Duration
+	// MaxConsecutiveOverruns is the number of consecutive calls exceeding
+	// Budget after which the plugin is temporarily skipped. Zero means the
+	// budget is record-only: overruns are surfaced in metrics but the plugin
+	// always runs.
+	MaxConsecutiveOverruns int32
 }
 
 // Plugins include multiple extension points. When specified, the list of plugins for
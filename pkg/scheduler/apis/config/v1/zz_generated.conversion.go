@@ -150,6 +150,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.PluginExecutionBudget)(nil), (*config.PluginExecutionBudget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PluginExecutionBudget_To_config_PluginExecutionBudget(a.(*v1.PluginExecutionBudget), b.(*config.PluginExecutionBudget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PluginExecutionBudget)(nil), (*v1.PluginExecutionBudget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PluginExecutionBudget_To_v1_PluginExecutionBudget(a.(*config.PluginExecutionBudget), b.(*v1.PluginExecutionBudget), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.PluginSet)(nil), (*config.PluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_PluginSet_To_config_PluginSet(a.(*v1.PluginSet), b.(*config.PluginSet), scope)
 	}); err != nil {
@@ -492,6 +502,7 @@ func autoConvert_v1_KubeSchedulerProfile_To_config_KubeSchedulerProfile(in *v1.K
 	} else {
 		out.PluginConfig = nil
 	}
+	out.PluginExecutionBudgets = *(*[]config.PluginExecutionBudget)(unsafe.Pointer(&in.PluginExecutionBudgets))
 	return nil
 }
 
@@ -525,6 +536,7 @@ func autoConvert_config_KubeSchedulerProfile_To_v1_KubeSchedulerProfile(in *conf
 	} else {
 		out.PluginConfig = nil
 	}
+	out.PluginExecutionBudgets = *(*[]v1.PluginExecutionBudget)(unsafe.Pointer(&in.PluginExecutionBudgets))
 	return nil
 }
 
@@ -649,6 +661,30 @@ func Convert_config_PluginConfig_To_v1_PluginConfig(in *config.PluginConfig, out
 	return autoConvert_config_PluginConfig_To_v1_PluginConfig(in, out, s)
 }
 
+func autoConvert_v1_PluginExecutionBudget_To_config_PluginExecutionBudget(in *v1.PluginExecutionBudget, out *config.PluginExecutionBudget, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Budget = in.Budget
+	out.MaxConsecutiveOverruns = in.MaxConsecutiveOverruns
+	return nil
+}
+
+// Convert_v1_PluginExecutionBudget_To_config_PluginExecutionBudget is an autogenerated conversion function.
+func Convert_v1_PluginExecutionBudget_To_config_PluginExecutionBudget(in *v1.PluginExecutionBudget, out *config.PluginExecutionBudget, s conversion.Scope) error {
+	return autoConvert_v1_PluginExecutionBudget_To_config_PluginExecutionBudget(in, out, s)
+}
+
+func autoConvert_config_PluginExecutionBudget_To_v1_PluginExecutionBudget(in *config.PluginExecutionBudget, out *v1.PluginExecutionBudget, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Budget = in.Budget
+	out.MaxConsecutiveOverruns = in.MaxConsecutiveOverruns
+	return nil
+}
+
+// Convert_config_PluginExecutionBudget_To_v1_PluginExecutionBudget is an autogenerated conversion function.
+func Convert_config_PluginExecutionBudget_To_v1_PluginExecutionBudget(in *config.PluginExecutionBudget, out *v1.PluginExecutionBudget, s conversion.Scope) error {
+	return autoConvert_config_PluginExecutionBudget_To_v1_PluginExecutionBudget(in, out, s)
+}
+
 func autoConvert_v1_PluginSet_To_config_PluginSet(in *v1.PluginSet, out *config.PluginSet, s conversion.Scope) error {
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
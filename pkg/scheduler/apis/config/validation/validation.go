@@ -181,6 +181,29 @@ func validateKubeSchedulerProfile(path *field.Path, apiVersion string, profile *
 	}
 	errs = append(errs, validatePercentageOfNodesToScore(path.Child("percentageOfNodesToScore"), profile.PercentageOfNodesToScore))
 	errs = append(errs, validatePluginConfig(path, apiVersion, profile)...)
+	errs = append(errs, validatePluginExecutionBudgets(path.Child("pluginExecutionBudgets"), profile.PluginExecutionBudgets)...)
+	return errs
+}
+
+func validatePluginExecutionBudgets(path *field.Path, budgets []config.PluginExecutionBudget) []error {
+	var errs []error
+	seen := make(map[string]int, len(budgets))
+	for i, budget := range budgets {
+		budgetPath := path.Index(i)
+		if len(budget.Name) == 0 {
+			errs = append(errs, field.Required(budgetPath.Child("name"), ""))
+		}
+		if budget.Budget.Duration <= 0 {
+			errs = append(errs, field.Invalid(budgetPath.Child("budget"), budget.Budget, "must be greater than zero"))
+		}
+		if budget.MaxConsecutiveOverruns < 0 {
+			errs = append(errs, field.Invalid(budgetPath.Child("maxConsecutiveOverruns"), budget.MaxConsecutiveOverruns, "must not be negative"))
+		}
+		if idx, ok := seen[budget.Name]; ok {
+			errs = append(errs, field.Duplicate(budgetPath.Child("name"), path.Index(idx).Child("name")))
+		}
+		seen[budget.Name] = i
+	}
 	return errs
 }
 
@@ -214,6 +214,12 @@ func TestValidateKubeSchedulerConfigurationV1beta2(t *testing.T) {
 	validPlugins := validConfig.DeepCopy()
 	validPlugins.Profiles[0].Plugins.Score.Enabled = append(validPlugins.Profiles[0].Plugins.Score.Enabled, config.Plugin{Name: "PodTopologySpread", Weight: 2})
 
+	invalidPluginExecutionBudgets := validConfig.DeepCopy()
+	invalidPluginExecutionBudgets.Profiles[0].PluginExecutionBudgets = []config.PluginExecutionBudget{
+		{Name: "NodeResourcesFit", Budget: metav1.Duration{}},
+		{Name: "NodeResourcesFit", Budget: metav1.Duration{Duration: 5 * time.Millisecond}},
+	}
+
 	scenarios := map[string]struct {
 		config   *config.KubeSchedulerConfiguration
 		wantErrs field.ErrorList
@@ -404,6 +410,19 @@ func TestValidateKubeSchedulerConfigurationV1beta2(t *testing.T) {
 		"valid-plugins": {
 			config: validPlugins,
 		},
+		"invalid-plugin-execution-budgets": {
+			config: invalidPluginExecutionBudgets,
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "profiles[0].pluginExecutionBudgets[0].budget",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "profiles[0].pluginExecutionBudgets[1].name",
+				},
+			},
+		},
 	}
 
 	for name, scenario := range scenarios {
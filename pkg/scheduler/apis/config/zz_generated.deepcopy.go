@@ -217,6 +217,11 @@ func (in *KubeSchedulerProfile) DeepCopyInto(out *KubeSchedulerProfile) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PluginExecutionBudgets != nil {
+		in, out := &in.PluginExecutionBudgets, &out.PluginExecutionBudgets
+		*out = make([]PluginExecutionBudget, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -365,6 +370,23 @@ func (in *PluginConfig) DeepCopy() *PluginConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginExecutionBudget) DeepCopyInto(out *PluginExecutionBudget) {
+	*out = *in
+	out.Budget = in.Budget
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginExecutionBudget.
+func (in *PluginExecutionBudget) DeepCopy() *PluginExecutionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginExecutionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginSet) DeepCopyInto(out *PluginSet) {
 	*out = *in
@@ -17,6 +17,7 @@ limitations under the License.
 package queuesort
 
 import (
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -37,12 +38,38 @@ func (pl *PrioritySort) Name() string {
 }
 
 // Less is the function used by the activeQ heap algorithm to sort pods.
-// It sorts pods based on their priority. When priorities are equal, it uses
-// PodQueueInfo.timestamp.
+// It sorts pods based on their priority. When priorities are equal, ties are
+// broken by PodQueueInfo.timestamp, and pods queued at the very same instant
+// are ordered by the creation batch annotation stamped by their workload
+// controller.
+//
+// The annotation is deliberately never consulted before the timestamp, even
+// though that limits it to exact-timestamp ties and so does not keep a batch
+// adjacent once its pods were queued at different instants. Any ordering that
+// ranked batch ahead of queue time would let a pod overtake pods queued
+// earlier, and because the annotation is settable by any pod author, that
+// would be an unprivileged way to jump the queue; bounding the effect with an
+// adjacency window would additionally make the comparison non-transitive,
+// which the heap cannot tolerate. Batch-adjacent ordering therefore cannot be
+// provided safely by this plugin.
 func (pl *PrioritySort) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
 	p1 := corev1helpers.PodPriority(pInfo1.Pod)
 	p2 := corev1helpers.PodPriority(pInfo2.Pod)
-	return (p1 > p2) || (p1 == p2 && pInfo1.Timestamp.Before(pInfo2.Timestamp))
+	if p1 != p2 {
+		return p1 > p2
+	}
+	if !pInfo1.Timestamp.Equal(pInfo2.Timestamp) {
+		return pInfo1.Timestamp.Before(pInfo2.Timestamp)
+	}
+	// Controllers stamp identifiers whose lexical order matches their
+	// creation order, so this keeps batches roughly FIFO as a whole.
+	return creationBatch(pInfo1.Pod) < creationBatch(pInfo2.Pod)
+}
+
+// creationBatch returns the creation batch identifier stamped on the pod by
+// its workload controller, or an empty string if the pod has none.
+func creationBatch(pod *v1.Pod) string {
+	return pod.Annotations[v1.PodCreationBatchAnnotationKey]
 }
 
 // New initializes a new plugin and returns it.
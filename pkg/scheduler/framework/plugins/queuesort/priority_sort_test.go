@@ -80,6 +80,54 @@ func TestLess(t *testing.T) {
 			},
 			expected: false, // p2 should be ahead of p1 in the queue
 		},
+		{
+			name: "equal priority and timestamp. p1 belongs to an earlier creation batch than p2",
+			p1: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000001-a").Obj()),
+				Timestamp: t1,
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000002-b").Obj()),
+				Timestamp: t1,
+			},
+			expected: true, // p1's batch should be ahead of p2's batch in the queue
+		},
+		{
+			name: "equal priority. p1 belongs to an earlier creation batch but was queued later",
+			p1: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000001-a").Obj()),
+				Timestamp: t2,
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000002-b").Obj()),
+				Timestamp: t1,
+			},
+			expected: false, // the batch annotation does not let p1 overtake the earlier queued p2
+		},
+		{
+			name: "equal priority and creation batch. p1 is added to schedulingQ earlier than p2",
+			p1: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000001-a").Obj()),
+				Timestamp: t1,
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000001-a").Obj()),
+				Timestamp: t2,
+			},
+			expected: true, // p1 should be ahead of p2 in the queue
+		},
+		{
+			name: "equal priority. only p2 has a creation batch",
+			p1: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Obj()),
+				Timestamp: t2,
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo:   mustNewPodInfo(t, st.MakePod().Priority(highPriority).Annotation(v1.PodCreationBatchAnnotationKey, "00000000000000000001-a").Obj()),
+				Timestamp: t1,
+			},
+			expected: false, // the batch annotation alone does not beat the queue timestamp
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			if got := prioritySort.Less(tt.p1, tt.p2); got != tt.expected {
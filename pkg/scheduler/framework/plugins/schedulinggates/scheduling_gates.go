@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	autoscalingv1alpha1 "k8s.io/api/autoscaling/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -46,6 +47,13 @@ func (pl *SchedulingGates) PreEnqueue(ctx context.Context, p *v1.Pod) *framework
 	if !pl.enablePodSchedulingReadiness || len(p.Spec.SchedulingGates) == 0 {
 		return nil
 	}
+	// pods waiting on a ProvisioningRequest are called out explicitly so the
+	// pod's events name the request it is waiting for.
+	if len(p.Spec.SchedulingGates) == 1 && p.Spec.SchedulingGates[0].Name == autoscalingv1alpha1.ProvisioningRequestPodGate {
+		if request, ok := p.Annotations[autoscalingv1alpha1.ProvisioningRequestPodAnnotationKey]; ok {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("waiting for ProvisioningRequest %q to be provisioned", request))
+		}
+	}
 	var gates []string
 	for _, gate := range p.Spec.SchedulingGates {
 		gates = append(gates, gate.Name)
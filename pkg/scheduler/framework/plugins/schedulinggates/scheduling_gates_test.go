@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	autoscalingv1alpha1 "k8s.io/api/autoscaling/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
@@ -59,6 +60,23 @@ func TestPreEnqueue(t *testing.T) {
 			enablePodSchedulingReadiness: true,
 			want:                         framework.NewStatus(framework.UnschedulableAndUnresolvable, "waiting for scheduling gates: [foo bar]"),
 		},
+		{
+			name: "pod carries the provisioning request gate, feature enabled",
+			pod: st.MakePod().Name("p").
+				SchedulingGates([]string{autoscalingv1alpha1.ProvisioningRequestPodGate}).
+				Annotation(autoscalingv1alpha1.ProvisioningRequestPodAnnotationKey, "pr-1").
+				Obj(),
+			enablePodSchedulingReadiness: true,
+			want:                         framework.NewStatus(framework.UnschedulableAndUnresolvable, `waiting for ProvisioningRequest "pr-1" to be provisioned`),
+		},
+		{
+			name: "pod carries the provisioning request gate without naming a request, feature enabled",
+			pod: st.MakePod().Name("p").
+				SchedulingGates([]string{autoscalingv1alpha1.ProvisioningRequestPodGate}).
+				Obj(),
+			enablePodSchedulingReadiness: true,
+			want:                         framework.NewStatus(framework.UnschedulableAndUnresolvable, "waiting for scheduling gates: [provisioning.autoscaling.k8s.io/pending]"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+)
+
+// budgetDegradedPeriod is how long a plugin is skipped once it has exhausted
+// its execution budget, before it is given another chance to run.
+const budgetDegradedPeriod = 30 * time.Second
+
+// pluginBudget tracks a single plugin's execution time budget. A call that
+// takes longer than the budget is an overrun; maxConsecutiveOverruns overruns
+// in a row degrade the plugin, and its Score calls are skipped for
+// budgetDegradedPeriod. Filter calls are observed but never skipped: a Filter
+// plugin enforces hard constraints and must always run. A zero
+// maxConsecutiveOverruns makes the budget record-only: overruns are counted in
+// metrics but the plugin's scores are never dropped.
+type pluginBudget struct {
+	pluginName             string
+	budget                 time.Duration
+	maxConsecutiveOverruns int32
+
+	mu                  sync.Mutex
+	consecutiveOverruns int32
+	degradedUntil       time.Time
+}
+
+// newPluginBudgets builds the budget state for a profile. It returns nil if
+// the profile configures no budgets, so callers can use a nil map check as
+// the fast path.
+func newPluginBudgets(budgets []config.PluginExecutionBudget) map[string]*pluginBudget {
+	if len(budgets) == 0 {
+		return nil
+	}
+	out := make(map[string]*pluginBudget, len(budgets))
+	for _, b := range budgets {
+		out[b.Name] = &pluginBudget{
+			pluginName:             b.Name,
+			budget:                 b.Budget.Duration,
+			maxConsecutiveOverruns: b.MaxConsecutiveOverruns,
+		}
+	}
+	return out
+}
+
+// shouldSkip reports whether the plugin is currently degraded and its call
+// should be skipped. It is only consulted for extension points that are safe
+// to drop, i.e. scoring. Once the degraded period has passed, the plugin runs
+// again with a fresh overrun count.
+func (b *pluginBudget) shouldSkip(extensionPoint string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.degradedUntil.IsZero() {
+		return false
+	}
+	if time.Now().Before(b.degradedUntil) {
+		metrics.PluginExecutionBudgetSkips.WithLabelValues(b.pluginName, extensionPoint).Inc()
+		return true
+	}
+	b.degradedUntil = time.Time{}
+	metrics.PluginDegraded.WithLabelValues(b.pluginName).Set(0)
+	klog.InfoS("Plugin finished its degraded period and will run again", "plugin", b.pluginName)
+	return false
+}
+
+// observe records the duration of a completed plugin call and degrades the
+// plugin if it has exceeded its budget too many times in a row.
+func (b *pluginBudget) observe(extensionPoint string, elapsed time.Duration) {
+	if elapsed <= b.budget {
+		b.mu.Lock()
+		b.consecutiveOverruns = 0
+		b.mu.Unlock()
+		return
+	}
+
+	metrics.PluginExecutionBudgetOverruns.WithLabelValues(b.pluginName, extensionPoint).Inc()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveOverruns++
+	if b.maxConsecutiveOverruns == 0 || b.consecutiveOverruns < b.maxConsecutiveOverruns {
+		return
+	}
+	b.consecutiveOverruns = 0
+	b.degradedUntil = time.Now().Add(budgetDegradedPeriod)
+	metrics.PluginDegraded.WithLabelValues(b.pluginName).Set(1)
+	klog.InfoS("Plugin repeatedly exceeded its execution budget and will be skipped",
+		"plugin", b.pluginName, "extensionPoint", extensionPoint, "budget", b.budget, "degradedPeriod", budgetDegradedPeriod)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+func TestNewPluginBudgets(t *testing.T) {
+	if got := newPluginBudgets(nil); got != nil {
+		t.Errorf("expected nil map for empty budgets, got %v", got)
+	}
+	budgets := newPluginBudgets([]config.PluginExecutionBudget{
+		{Name: "slow-plugin", Budget: metav1.Duration{Duration: time.Millisecond}, MaxConsecutiveOverruns: 3},
+	})
+	b, ok := budgets["slow-plugin"]
+	if !ok {
+		t.Fatalf("expected budget for slow-plugin, got %v", budgets)
+	}
+	if b.budget != time.Millisecond || b.maxConsecutiveOverruns != 3 {
+		t.Errorf("unexpected budget state: %+v", b)
+	}
+}
+
+func TestPluginBudgetDegradation(t *testing.T) {
+	b := &pluginBudget{
+		pluginName:             "slow-plugin",
+		budget:                 time.Millisecond,
+		maxConsecutiveOverruns: 2,
+	}
+
+	// A single overrun must not degrade the plugin.
+	b.observe(score, 2*time.Millisecond)
+	if b.shouldSkip(score) {
+		t.Errorf("plugin degraded after a single overrun")
+	}
+
+	// A call within budget resets the consecutive overrun count.
+	b.observe(score, time.Microsecond)
+	b.observe(score, 2*time.Millisecond)
+	if b.shouldSkip(score) {
+		t.Errorf("plugin degraded even though the overrun streak was broken")
+	}
+
+	// Overruns at any extension point count toward degradation, including
+	// Filter, whose calls themselves are never skipped.
+	b.observe(Filter, 2*time.Millisecond)
+	if !b.shouldSkip(score) {
+		t.Errorf("plugin not degraded after reaching maxConsecutiveOverruns")
+	}
+
+	// Once the degraded period has passed, the plugin runs again.
+	b.mu.Lock()
+	b.degradedUntil = time.Now().Add(-time.Second)
+	b.mu.Unlock()
+	if b.shouldSkip(score) {
+		t.Errorf("plugin still degraded after the degraded period elapsed")
+	}
+}
+
+func TestPluginBudgetRecordOnly(t *testing.T) {
+	b := &pluginBudget{
+		pluginName: "slow-plugin",
+		budget:     time.Millisecond,
+	}
+	for i := 0; i < 10; i++ {
+		b.observe(score, 2*time.Millisecond)
+	}
+	if b.shouldSkip(score) {
+		t.Errorf("record-only budget degraded the plugin")
+	}
+}
@@ -96,6 +96,7 @@ type frameworkImpl struct {
 	metricsRecorder          *metricsRecorder
 	profileName              string
 	percentageOfNodesToScore *int32
+	pluginBudgets            map[string]*pluginBudget
 
 	extenders []framework.Extender
 	framework.PodNominator
@@ -275,6 +276,7 @@ func NewFramework(r Registry, profile *config.KubeSchedulerProfile, stopCh <-cha
 
 	f.profileName = profile.SchedulerName
 	f.percentageOfNodesToScore = profile.PercentageOfNodesToScore
+	f.pluginBudgets = newPluginBudgets(profile.PluginExecutionBudgets)
 	if profile.Plugins == nil {
 		return f, nil
 	}
@@ -725,6 +727,10 @@ func (f *frameworkImpl) RunFilterPlugins(
 ) framework.PluginToStatus {
 	statuses := make(framework.PluginToStatus)
 	for _, pl := range f.filterPlugins {
+		// Degraded plugins are never skipped here: a Filter plugin enforces
+		// hard constraints, and skipping it would let pods schedule onto
+		// nodes that violate them. Filter overruns still count toward
+		// degradation, which only suppresses Score calls.
 		pluginStatus := f.runFilterPlugin(ctx, pl, state, pod, nodeInfo)
 		if !pluginStatus.IsSuccess() {
 			if !pluginStatus.IsUnschedulable() {
@@ -742,12 +748,19 @@ func (f *frameworkImpl) RunFilterPlugins(
 }
 
 func (f *frameworkImpl) runFilterPlugin(ctx context.Context, pl framework.FilterPlugin, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
-	if !state.ShouldRecordPluginMetrics() {
+	budget := f.pluginBudgets[pl.Name()]
+	if budget == nil && !state.ShouldRecordPluginMetrics() {
 		return pl.Filter(ctx, state, pod, nodeInfo)
 	}
 	startTime := time.Now()
 	status := pl.Filter(ctx, state, pod, nodeInfo)
-	f.metricsRecorder.observePluginDurationAsync(Filter, pl.Name(), status, metrics.SinceInSeconds(startTime))
+	elapsed := time.Since(startTime)
+	if state.ShouldRecordPluginMetrics() {
+		f.metricsRecorder.observePluginDurationAsync(Filter, pl.Name(), status, elapsed.Seconds())
+	}
+	if budget != nil {
+		budget.observe(Filter, elapsed)
+	}
 	return status
 }
 
@@ -913,8 +926,20 @@ func (f *frameworkImpl) RunScorePlugins(ctx context.Context, state *framework.Cy
 		metrics.FrameworkExtensionPointDuration.WithLabelValues(score, status.Code().String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
 	}()
 	allNodePluginScores := make([]framework.NodePluginScores, len(nodes))
-	pluginToNodeScores := make(map[string]framework.NodeScoreList, len(f.scorePlugins))
-	for _, pl := range f.scorePlugins {
+	scorePlugins := f.scorePlugins
+	if len(f.pluginBudgets) > 0 {
+		// Degraded plugins are dropped for the whole cycle, so every node
+		// misses the same scores.
+		scorePlugins = make([]framework.ScorePlugin, 0, len(f.scorePlugins))
+		for _, pl := range f.scorePlugins {
+			if b := f.pluginBudgets[pl.Name()]; b != nil && b.shouldSkip(score) {
+				continue
+			}
+			scorePlugins = append(scorePlugins, pl)
+		}
+	}
+	pluginToNodeScores := make(map[string]framework.NodeScoreList, len(scorePlugins))
+	for _, pl := range scorePlugins {
 		pluginToNodeScores[pl.Name()] = make(framework.NodeScoreList, len(nodes))
 	}
 	ctx, cancel := context.WithCancel(ctx)
@@ -923,7 +948,7 @@ func (f *frameworkImpl) RunScorePlugins(ctx context.Context, state *framework.Cy
 
 	// Run Score method for each node in parallel.
 	f.Parallelizer().Until(ctx, len(nodes), func(index int) {
-		for _, pl := range f.scorePlugins {
+		for _, pl := range scorePlugins {
 			nodeName := nodes[index].Name
 			s, status := f.runScorePlugin(ctx, pl, state, pod, nodeName)
 			if !status.IsSuccess() {
@@ -942,8 +967,8 @@ func (f *frameworkImpl) RunScorePlugins(ctx context.Context, state *framework.Cy
 	}
 
 	// Run NormalizeScore method for each ScorePlugin in parallel.
-	f.Parallelizer().Until(ctx, len(f.scorePlugins), func(index int) {
-		pl := f.scorePlugins[index]
+	f.Parallelizer().Until(ctx, len(scorePlugins), func(index int) {
+		pl := scorePlugins[index]
 		if pl.ScoreExtensions() == nil {
 			return
 		}
@@ -964,10 +989,10 @@ func (f *frameworkImpl) RunScorePlugins(ctx context.Context, state *framework.Cy
 	f.Parallelizer().Until(ctx, len(nodes), func(index int) {
 		nodePluginScores := framework.NodePluginScores{
 			Name:   nodes[index].Name,
-			Scores: make([]framework.PluginScore, len(f.scorePlugins)),
+			Scores: make([]framework.PluginScore, len(scorePlugins)),
 		}
 
-		for i, pl := range f.scorePlugins {
+		for i, pl := range scorePlugins {
 			weight := f.scorePluginWeight[pl.Name()]
 			nodeScoreList := pluginToNodeScores[pl.Name()]
 			score := nodeScoreList[index].Score
@@ -994,12 +1019,19 @@ func (f *frameworkImpl) RunScorePlugins(ctx context.Context, state *framework.Cy
 }
 
 func (f *frameworkImpl) runScorePlugin(ctx context.Context, pl framework.ScorePlugin, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
-	if !state.ShouldRecordPluginMetrics() {
+	budget := f.pluginBudgets[pl.Name()]
+	if budget == nil && !state.ShouldRecordPluginMetrics() {
 		return pl.Score(ctx, state, pod, nodeName)
 	}
 	startTime := time.Now()
 	s, status := pl.Score(ctx, state, pod, nodeName)
-	f.metricsRecorder.observePluginDurationAsync(score, pl.Name(), status, metrics.SinceInSeconds(startTime))
+	elapsed := time.Since(startTime)
+	if state.ShouldRecordPluginMetrics() {
+		f.metricsRecorder.observePluginDurationAsync(score, pl.Name(), status, elapsed.Seconds())
+	}
+	if budget != nil {
+		budget.observe(score, elapsed)
+	}
 	return s, status
 }
 
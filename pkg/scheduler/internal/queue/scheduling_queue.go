@@ -820,6 +820,11 @@ func (p *PriorityQueue) podsCompareBackoffCompleted(podInfo1, podInfo2 interface
 	pInfo2 := podInfo2.(*framework.QueuedPodInfo)
 	bo1 := p.getBackoffTime(pInfo1)
 	bo2 := p.getBackoffTime(pInfo2)
+	if bo1.Equal(bo2) {
+		// Keep pods of one controller creation burst adjacent so they flush
+		// back to the active queue together.
+		return pInfo1.Pod.Annotations[v1.PodCreationBatchAnnotationKey] < pInfo2.Pod.Annotations[v1.PodCreationBatchAnnotationKey]
+	}
 	return bo1.Before(bo2)
 }
 
@@ -155,6 +155,33 @@ var (
 		},
 		[]string{"plugin", "extension_point", "status"})
 
+	PluginExecutionBudgetOverruns = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "plugin_execution_budget_overruns_total",
+			Help:           "Number of plugin calls that exceeded the plugin's configured execution budget.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "extension_point"})
+
+	PluginExecutionBudgetSkips = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "plugin_execution_budget_skips_total",
+			Help:           "Number of plugin calls skipped because the plugin exhausted its execution budget.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "extension_point"})
+
+	PluginDegraded = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "plugin_degraded",
+			Help:           "Whether a plugin is currently being skipped for exceeding its execution budget (1 while degraded).",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin"})
+
 	SchedulerQueueIncomingPods = metrics.NewCounterVec(
 		&metrics.CounterOpts{
 			Subsystem:      SchedulerSubsystem,
@@ -201,6 +228,9 @@ var (
 		PodSchedulingAttempts,
 		FrameworkExtensionPointDuration,
 		PluginExecutionDuration,
+		PluginExecutionBudgetOverruns,
+		PluginExecutionBudgetSkips,
+		PluginDegraded,
 		SchedulerQueueIncomingPods,
 		SchedulerGoroutines,
 		Goroutines,
@@ -0,0 +1,290 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fieldauthorization restricts which field paths a principal may
+// modify within an object, beyond the spec/status subresource split. Rules
+// select principals and resources and carry a CEL expression over the set of
+// field paths an update changes, so a policy can express e.g. "node agents
+// may only update their own labels under a given prefix".
+package fieldauthorization
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// PluginName is a string with the name of the plugin
+const PluginName = "FieldAuthorization"
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
+		pluginConfig, err := readConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return newPlugin(pluginConfig)
+	})
+}
+
+// pluginConfig is the configuration read from the file provided with
+// --admission-control-config-file.
+type pluginConfig struct {
+	FieldAuthorizationPluginConfig struct {
+		Rules []ruleConfig `json:"rules"`
+	} `json:"fieldAuthorizationPluginConfig"`
+}
+
+// ruleConfig restricts the updates of one set of principals on one set of
+// resources.
+type ruleConfig struct {
+	// Users and Groups select the principals the rule applies to. A rule
+	// with neither applies to every principal.
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// Resources lists the resources the rule applies to, as
+	// "<resource>.<group>" with an optional "/<subresource>" suffix. A rule
+	// without resources applies to every resource.
+	Resources []string `json:"resources,omitempty"`
+	// Expression is a CEL expression over the variables changedPaths (the
+	// sorted list of field paths the update modifies), userName and
+	// userGroups. The update is forbidden when it evaluates to false.
+	Expression string `json:"expression"`
+}
+
+func readConfig(config io.Reader) (*pluginConfig, error) {
+	pluginConfig := &pluginConfig{}
+	if config == nil || reflect.ValueOf(config).IsNil() {
+		return pluginConfig, nil
+	}
+	d := yaml.NewYAMLOrJSONDecoder(config, 4096)
+	for {
+		if err := d.Decode(pluginConfig); err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("failed to read %s plugin configuration: %w", PluginName, err)
+			}
+			break
+		}
+	}
+	return pluginConfig, nil
+}
+
+// compiledRule is a ruleConfig with its expression compiled.
+type compiledRule struct {
+	ruleConfig
+	program cel.Program
+}
+
+func (r *compiledRule) matchesUser(userInfo user.Info) bool {
+	if len(r.Users) == 0 && len(r.Groups) == 0 {
+		return true
+	}
+	for _, name := range r.Users {
+		if userInfo.GetName() == name {
+			return true
+		}
+	}
+	groups := userInfo.GetGroups()
+	for _, group := range r.Groups {
+		for _, userGroup := range groups {
+			if group == userGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *compiledRule) matchesResource(resource string) bool {
+	if len(r.Resources) == 0 {
+		return true
+	}
+	for _, ruleResource := range r.Resources {
+		if ruleResource == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Plugin is an implementation of admission.Interface.
+type Plugin struct {
+	*admission.Handler
+	rules []compiledRule
+}
+
+var _ admission.ValidationInterface = &Plugin{}
+
+// newPlugin compiles the configured rules and returns the plugin.
+func newPlugin(config *pluginConfig) (*Plugin, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("changedPaths", cel.ListType(cel.StringType)),
+		cel.Variable("userName", cel.StringType),
+		cel.Variable("userGroups", cel.ListType(cel.StringType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment for %s: %w", PluginName, err)
+	}
+
+	rules := make([]compiledRule, 0, len(config.FieldAuthorizationPluginConfig.Rules))
+	for i, rule := range config.FieldAuthorizationPluginConfig.Rules {
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile expression of %s rule %d: %w", PluginName, i, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("expression of %s rule %d must evaluate to bool, got %v", PluginName, i, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("failed to program expression of %s rule %d: %w", PluginName, i, err)
+		}
+		rules = append(rules, compiledRule{ruleConfig: rule, program: program})
+	}
+
+	return &Plugin{
+		Handler: admission.NewHandler(admission.Update),
+		rules:   rules,
+	}, nil
+}
+
+// Validate rejects updates that modify field paths a matching rule's
+// expression does not permit.
+func (p *Plugin) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if len(p.rules) == 0 || a.GetObject() == nil || a.GetOldObject() == nil {
+		return nil
+	}
+	userInfo := a.GetUserInfo()
+	if userInfo == nil {
+		return nil
+	}
+
+	resource := a.GetResource().GroupResource().String()
+	if subresource := a.GetSubresource(); subresource != "" {
+		resource = resource + "/" + subresource
+	}
+
+	var matching []*compiledRule
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if rule.matchesUser(userInfo) && rule.matchesResource(resource) {
+			matching = append(matching, rule)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+
+	paths, err := changedFieldPaths(a, o)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("failed to compute changed field paths: %w", err))
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	activation := map[string]interface{}{
+		"changedPaths": paths,
+		"userName":     userInfo.GetName(),
+		"userGroups":   userInfo.GetGroups(),
+	}
+	for _, rule := range matching {
+		out, _, err := rule.program.Eval(activation)
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("failed to evaluate field authorization expression %q: %w", rule.Expression, err))
+		}
+		if out != celtypes.True {
+			return admission.NewForbidden(a, fmt.Errorf("user %q may not modify field paths %v of %s: denied by field authorization expression %q",
+				userInfo.GetName(), paths, resource, rule.Expression))
+		}
+	}
+	return nil
+}
+
+// changedFieldPaths returns the sorted dotted field paths that differ between
+// the old and the new object. Both objects are converted to the request's
+// external version first so paths use the wire field names.
+func changedFieldPaths(a admission.Attributes, o admission.ObjectInterfaces) ([]string, error) {
+	oldObject, err := toExternalMap(a.GetOldObject(), a, o)
+	if err != nil {
+		return nil, err
+	}
+	newObject, err := toExternalMap(a.GetObject(), a, o)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := diffPaths("", oldObject, newObject, nil)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func toExternalMap(obj runtime.Object, a admission.Attributes, o admission.ObjectInterfaces) (map[string]interface{}, error) {
+	// internal types carry no field tags, so convert to the request's
+	// external version before flattening
+	if o != nil {
+		if converted, err := o.GetObjectConvertor().ConvertToVersion(obj, a.GetKind().GroupVersion()); err == nil {
+			obj = converted
+		}
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// diffPaths walks both maps and collects the dotted paths whose values
+// differ. Lists are treated as atomic values.
+func diffPaths(prefix string, oldObject, newObject map[string]interface{}, paths []string) []string {
+	keys := map[string]bool{}
+	for key := range oldObject {
+		keys[key] = true
+	}
+	for key := range newObject {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		oldValue, oldOK := oldObject[key]
+		newValue, newOK := newObject[key]
+		if oldOK != newOK {
+			paths = append(paths, path)
+			continue
+		}
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			paths = diffPaths(path, oldMap, newMap, paths)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldauthorization
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const testConfig = `
+fieldAuthorizationPluginConfig:
+  rules:
+  - groups: ["system:nodes"]
+    resources: ["nodes"]
+    expression: "changedPaths.all(p, p.startsWith('metadata.labels.node.kubernetes.io/'))"
+`
+
+func newTestPlugin(t *testing.T) *Plugin {
+	t.Helper()
+	config, err := readConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := newPlugin(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plugin
+}
+
+func nodeWithLabels(labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: labels}}
+}
+
+func nodeAttributes(oldNode, newNode *corev1.Node, userInfo user.Info) admission.Attributes {
+	return admission.NewAttributesRecord(
+		newNode, oldNode,
+		corev1.SchemeGroupVersion.WithKind("Node"),
+		"", "node-1",
+		corev1.SchemeGroupVersion.WithResource("nodes"),
+		"", admission.Update, &metav1.UpdateOptions{}, false, userInfo)
+}
+
+func objectInterfaces(t *testing.T) admission.ObjectInterfaces {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return admission.NewObjectInterfacesFromScheme(scheme)
+}
+
+func TestValidate(t *testing.T) {
+	nodeUser := &user.DefaultInfo{Name: "system:node:node-1", Groups: []string{"system:nodes"}}
+	adminUser := &user.DefaultInfo{Name: "admin", Groups: []string{"system:masters"}}
+
+	tests := []struct {
+		name     string
+		oldNode  *corev1.Node
+		newNode  *corev1.Node
+		userInfo user.Info
+		wantDeny bool
+	}{
+		{
+			name:     "node may update labels under the allowed prefix",
+			oldNode:  nodeWithLabels(map[string]string{"node.kubernetes.io/zone": "a"}),
+			newNode:  nodeWithLabels(map[string]string{"node.kubernetes.io/zone": "b"}),
+			userInfo: nodeUser,
+		},
+		{
+			name:     "node may not update labels outside the allowed prefix",
+			oldNode:  nodeWithLabels(map[string]string{"role": "worker"}),
+			newNode:  nodeWithLabels(map[string]string{"role": "master"}),
+			userInfo: nodeUser,
+			wantDeny: true,
+		},
+		{
+			name:     "node may not update the spec",
+			oldNode:  nodeWithLabels(nil),
+			newNode:  &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+			userInfo: nodeUser,
+			wantDeny: true,
+		},
+		{
+			name:     "rules do not apply to other principals",
+			oldNode:  nodeWithLabels(map[string]string{"role": "worker"}),
+			newNode:  nodeWithLabels(map[string]string{"role": "master"}),
+			userInfo: adminUser,
+		},
+		{
+			name:     "no-op updates are allowed",
+			oldNode:  nodeWithLabels(map[string]string{"role": "worker"}),
+			newNode:  nodeWithLabels(map[string]string{"role": "worker"}),
+			userInfo: nodeUser,
+		},
+	}
+
+	plugin := newTestPlugin(t)
+	o := objectInterfaces(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := plugin.Validate(context.Background(), nodeAttributes(tt.oldNode, tt.newNode, tt.userInfo), o)
+			if tt.wantDeny && err == nil {
+				t.Error("expected the update to be denied")
+			}
+			if !tt.wantDeny && err != nil {
+				t.Errorf("expected the update to be allowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRuleCompilationErrors(t *testing.T) {
+	for _, expression := range []string{"not valid cel (", "'not a bool'"} {
+		config := &pluginConfig{}
+		config.FieldAuthorizationPluginConfig.Rules = []ruleConfig{{Expression: expression}}
+		if _, err := newPlugin(config); err == nil {
+			t.Errorf("expected an error compiling %q", expression)
+		}
+	}
+}
+
+func TestChangedFieldPaths(t *testing.T) {
+	oldNode := nodeWithLabels(map[string]string{"role": "worker", "zone": "a"})
+	newNode := nodeWithLabels(map[string]string{"role": "master", "region": "r"})
+	newNode.Spec.Unschedulable = true
+
+	paths, err := changedFieldPaths(nodeAttributes(oldNode, newNode, &user.DefaultInfo{}), objectInterfaces(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"metadata.labels.region", "metadata.labels.role", "metadata.labels.zone", "spec.unschedulable"}
+	if len(paths) != len(want) {
+		t.Fatalf("want paths %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("want paths %v, got %v", want, paths)
+		}
+	}
+}
+
+func TestResourceMatching(t *testing.T) {
+	rule := &compiledRule{ruleConfig: ruleConfig{Resources: []string{"nodes", "pods/status"}}}
+	for resource, want := range map[string]bool{
+		"nodes":       true,
+		"pods/status": true,
+		"pods":        false,
+		"nodes.apps":  false,
+	} {
+		if got := rule.matchesResource(resource); got != want {
+			t.Errorf("matchesResource(%q) = %v, want %v", resource, got, want)
+		}
+	}
+}
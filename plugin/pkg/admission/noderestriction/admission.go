@@ -29,11 +29,13 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/diff"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/apiserver/pkg/admission"
 	apiserveradmission "k8s.io/apiserver/pkg/admission/initializer"
 	"k8s.io/client-go/informers"
 	corev1lister "k8s.io/client-go/listers/core/v1"
 	"k8s.io/component-base/featuregate"
+	"k8s.io/klog/v2"
 	kubeletapis "k8s.io/kubelet/pkg/apis"
 	podutil "k8s.io/kubernetes/pkg/api/pod"
 	authenticationapi "k8s.io/kubernetes/pkg/apis/authentication"
@@ -48,13 +50,73 @@ import (
 // PluginName is a string with the name of the plugin
 const PluginName = "NodeRestriction"
 
+// auditAnnotationPrefix is the prefix for all audit annotations set by this plugin.
+const auditAnnotationPrefix = "noderestriction.admission.k8s.io/"
+
 // Register registers a plugin
 func Register(plugins *admission.Plugins) {
 	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
-		return NewPlugin(nodeidentifier.NewDefaultNodeIdentifier()), nil
+		pluginConfig, err := readConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		p := NewPlugin(nodeidentifier.NewDefaultNodeIdentifier())
+		p.policies = pluginConfig.NodeRestrictionPluginConfig
+		return p, nil
 	})
 }
 
+// pluginConfig is the configuration read from the file provided with
+// --admission-control-config-file.
+type pluginConfig struct {
+	NodeRestrictionPluginConfig prefixPolicies `json:"nodeRestrictionPluginConfig"`
+}
+
+// prefixPolicies adjusts which label and taint keys a node may self-set, on
+// top of the built-in rules. Denied prefixes always win over allowed ones.
+type prefixPolicies struct {
+	// AllowedLabelPrefixes lists label key prefixes nodes may set or modify
+	// on themselves in addition to the built-in kubelet label set. It cannot
+	// allow keys in the node-restriction.kubernetes.io namespace.
+	AllowedLabelPrefixes []string `json:"allowedLabelPrefixes,omitempty"`
+	// DeniedLabelPrefixes lists label key prefixes nodes may never set or
+	// modify on themselves, even when the built-in rules would allow them.
+	DeniedLabelPrefixes []string `json:"deniedLabelPrefixes,omitempty"`
+	// AllowedTaintPrefixes lists taint key prefixes nodes may modify on
+	// themselves. Without it, nodes may not modify their taints at all.
+	AllowedTaintPrefixes []string `json:"allowedTaintPrefixes,omitempty"`
+	// DeniedTaintPrefixes lists taint key prefixes nodes may never set on
+	// themselves, including at registration.
+	DeniedTaintPrefixes []string `json:"deniedTaintPrefixes,omitempty"`
+}
+
+func readConfig(config io.Reader) (*pluginConfig, error) {
+	pluginConfig := &pluginConfig{}
+	if config == nil {
+		return pluginConfig, nil
+	}
+	d := yaml.NewYAMLOrJSONDecoder(config, 4096)
+	for {
+		if err := d.Decode(pluginConfig); err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("failed to read %s plugin configuration: %w", PluginName, err)
+			}
+			break
+		}
+	}
+	return pluginConfig, nil
+}
+
+// matchesAnyPrefix returns true when the key starts with one of the prefixes.
+func matchesAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // NewPlugin creates a new NodeRestriction admission plugin.
 // This plugin identifies requests from nodes
 func NewPlugin(nodeIdentifier nodeidentifier.NodeIdentifier) *Plugin {
@@ -70,6 +132,7 @@ type Plugin struct {
 	nodeIdentifier nodeidentifier.NodeIdentifier
 	podsGetter     corev1lister.PodLister
 	nodesGetter    corev1lister.NodeLister
+	policies       prefixPolicies
 
 	expansionRecoveryEnabled bool
 }
@@ -408,8 +471,15 @@ func (p *Plugin) admitNode(nodeName string, a admission.Attributes) error {
 		// This would allow a node to add or modify its labels in a way that would let it steer privileged workloads to itself.
 		modifiedLabels := getModifiedLabels(node.Labels, nil)
 		if forbiddenLabels := p.getForbiddenLabels(modifiedLabels); len(forbiddenLabels) > 0 {
+			recordForbiddenKeys(a, "labels", forbiddenLabels)
 			return admission.NewForbidden(a, fmt.Errorf("node %q is not allowed to set the following labels: %s", nodeName, strings.Join(forbiddenLabels.List(), ", ")))
 		}
+
+		// Don't allow a node to register with taints under a denied prefix.
+		if forbiddenTaints := p.getDeniedTaintKeys(node.Spec.Taints); len(forbiddenTaints) > 0 {
+			recordForbiddenKeys(a, "taints", forbiddenTaints)
+			return admission.NewForbidden(a, fmt.Errorf("node %q is not allowed to set the following taints: %s", nodeName, strings.Join(forbiddenTaints.List(), ", ")))
+		}
 	}
 
 	if a.GetOperation() == admission.Update {
@@ -429,16 +499,21 @@ func (p *Plugin) admitNode(nodeName string, a admission.Attributes) error {
 			return admission.NewForbidden(a, fmt.Errorf("node %q is not allowed to update configSource to a new non-nil configSource", nodeName))
 		}
 
-		// Don't allow a node to update its own taints. This would allow a node to remove or modify its
-		// taints in a way that would let it steer disallowed workloads to itself.
+		// Don't allow a node to update its own taints outside the allowed set.
+		// This would allow a node to remove or modify its taints in a way that would let it steer disallowed workloads to itself.
 		if !apiequality.Semantic.DeepEqual(node.Spec.Taints, oldNode.Spec.Taints) {
-			return admission.NewForbidden(a, fmt.Errorf("node %q is not allowed to modify taints", nodeName))
+			modifiedTaints := getModifiedTaintKeys(node.Spec.Taints, oldNode.Spec.Taints)
+			if forbiddenTaints := p.getForbiddenTaintKeys(modifiedTaints); len(forbiddenTaints) > 0 {
+				recordForbiddenKeys(a, "taints", forbiddenTaints)
+				return admission.NewForbidden(a, fmt.Errorf("node %q is not allowed to modify taints: %s", nodeName, strings.Join(forbiddenTaints.List(), ", ")))
+			}
 		}
 
 		// Don't allow a node to update labels outside the allowed set.
 		// This would allow a node to add or modify its labels in a way that would let it steer privileged workloads to itself.
 		modifiedLabels := getModifiedLabels(node.Labels, oldNode.Labels)
 		if forbiddenUpdateLabels := p.getForbiddenLabels(modifiedLabels); len(forbiddenUpdateLabels) > 0 {
+			recordForbiddenKeys(a, "labels", forbiddenUpdateLabels)
 			return admission.NewForbidden(a, fmt.Errorf("is not allowed to modify labels: %s", strings.Join(forbiddenUpdateLabels.List(), ", ")))
 		}
 	}
@@ -488,20 +563,92 @@ func (p *Plugin) getForbiddenLabels(modifiedLabels sets.String) sets.String {
 
 	forbiddenLabels := sets.NewString()
 	for label := range modifiedLabels {
+		// configured denied prefixes win over everything else
+		if matchesAnyPrefix(label, p.policies.DeniedLabelPrefixes) {
+			forbiddenLabels.Insert(label)
+			continue
+		}
 		namespace := getLabelNamespace(label)
-		// forbid kubelets from setting node-restriction labels
+		// forbid kubelets from setting node-restriction labels; this namespace
+		// is guaranteed node-unsettable, so no allowed prefix can unlock it
 		if namespace == v1.LabelNamespaceNodeRestriction || strings.HasSuffix(namespace, "."+v1.LabelNamespaceNodeRestriction) {
 			forbiddenLabels.Insert(label)
+			continue
+		}
+		// configured allowed prefixes extend the built-in kubelet label set
+		if matchesAnyPrefix(label, p.policies.AllowedLabelPrefixes) {
+			continue
 		}
 		// forbid kubelets from setting unknown kubernetes.io and k8s.io labels on update
 		if isKubernetesLabel(label) && !kubeletapis.IsKubeletLabel(label) {
-			// TODO: defer to label policy once available
 			forbiddenLabels.Insert(label)
 		}
 	}
 	return forbiddenLabels
 }
 
+// getModifiedTaintKeys returns the keys of the taints that are different
+// between the two lists.
+func getModifiedTaintKeys(a, b []api.Taint) sets.String {
+	modified := sets.NewString()
+	for _, t1 := range a {
+		if !taintInList(t1, b) {
+			modified.Insert(t1.Key)
+		}
+	}
+	for _, t1 := range b {
+		if !taintInList(t1, a) {
+			modified.Insert(t1.Key)
+		}
+	}
+	return modified
+}
+
+func taintInList(taint api.Taint, taints []api.Taint) bool {
+	for _, t := range taints {
+		if apiequality.Semantic.DeepEqual(taint, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// getForbiddenTaintKeys returns the modified taint keys the node may not
+// modify on itself. Only keys under a configured allowed prefix, and not
+// under a denied one, may be modified.
+func (p *Plugin) getForbiddenTaintKeys(modifiedTaints sets.String) sets.String {
+	forbiddenTaints := sets.NewString()
+	for key := range modifiedTaints {
+		if matchesAnyPrefix(key, p.policies.DeniedTaintPrefixes) ||
+			!matchesAnyPrefix(key, p.policies.AllowedTaintPrefixes) {
+			forbiddenTaints.Insert(key)
+		}
+	}
+	return forbiddenTaints
+}
+
+// getDeniedTaintKeys returns the taint keys under a denied prefix. Unlike
+// updates, registering with taints is allowed by default, so only the denied
+// prefixes apply on create.
+func (p *Plugin) getDeniedTaintKeys(taints []api.Taint) sets.String {
+	deniedTaints := sets.NewString()
+	for _, taint := range taints {
+		if matchesAnyPrefix(taint.Key, p.policies.DeniedTaintPrefixes) {
+			deniedTaints.Insert(taint.Key)
+		}
+	}
+	return deniedTaints
+}
+
+// recordForbiddenKeys reports the label or taint keys of a rejected request
+// in the audit log.
+func recordForbiddenKeys(a admission.Attributes, kind string, keys sets.String) {
+	key := auditAnnotationPrefix + "forbidden-" + kind
+	if err := a.AddAnnotation(key, strings.Join(keys.List(), ",")); err != nil {
+		klog.Warningf("failed to set admission audit annotation %s: %v", key, err)
+	}
+}
+
 func (p *Plugin) admitServiceAccount(nodeName string, a admission.Attributes) error {
 	if a.GetOperation() != admission.Create {
 		return nil
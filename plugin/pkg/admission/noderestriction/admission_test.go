@@ -212,6 +212,7 @@ type admitTestCase struct {
 	nodesGetter corev1lister.NodeLister
 	attributes  admission.Attributes
 	features    featuregate.FeatureGate
+	policies    prefixPolicies
 	err         string
 }
 
@@ -223,6 +224,7 @@ func (a *admitTestCase) run(t *testing.T) {
 		}
 		c.podsGetter = a.podsGetter
 		c.nodesGetter = a.nodesGetter
+		c.policies = a.policies
 		err := c.Admit(context.TODO(), a.attributes, nil)
 		if (err == nil) != (len(a.err) == 0) {
 			t.Errorf("nodePlugin.Admit() error = %v, expected %v", err, a.err)
@@ -1584,3 +1586,115 @@ func createPodAttributes(pod *api.Pod, user user.Info) admission.Attributes {
 	podKind := api.Kind("Pod").WithVersion("v1")
 	return admission.NewAttributesRecord(pod, nil, podKind, pod.Namespace, pod.Name, podResource, "", admission.Create, &metav1.CreateOptions{}, false, user)
 }
+
+func Test_nodePlugin_AdmitPrefixPolicies(t *testing.T) {
+	mynode := &user.DefaultInfo{Name: "system:node:mynode", Groups: []string{"system:nodes"}}
+	nodeResource := api.Resource("nodes").WithVersion("v1")
+	nodeKind := api.Kind("Node").WithVersion("v1")
+
+	nodeWithLabels := func(labels map[string]string) *api.Node {
+		return &api.Node{ObjectMeta: metav1.ObjectMeta{Name: "mynode", Labels: labels}}
+	}
+	nodeWithTaints := func(taints ...api.Taint) *api.Node {
+		return &api.Node{ObjectMeta: metav1.ObjectMeta{Name: "mynode"}, Spec: api.NodeSpec{Taints: taints}}
+	}
+	update := func(newNode, oldNode *api.Node) admission.Attributes {
+		return admission.NewAttributesRecord(newNode, oldNode, nodeKind, "", "mynode", nodeResource, "", admission.Update, &metav1.UpdateOptions{}, false, mynode)
+	}
+	create := func(node *api.Node) admission.Attributes {
+		return admission.NewAttributesRecord(node, nil, nodeKind, "", "mynode", nodeResource, "", admission.Create, &metav1.CreateOptions{}, false, mynode)
+	}
+
+	policies := prefixPolicies{
+		AllowedLabelPrefixes: []string{"labels.example.kubernetes.io/"},
+		DeniedLabelPrefixes:  []string{"example.com/denied-"},
+		AllowedTaintPrefixes: []string{"taints.example.com/"},
+		DeniedTaintPrefixes:  []string{"taints.example.com/denied-"},
+	}
+
+	tests := []admitTestCase{
+		{
+			name:       "allowed label prefix extends the built-in kubelet label set",
+			attributes: update(nodeWithLabels(map[string]string{"labels.example.kubernetes.io/foo": "bar"}), nodeWithLabels(nil)),
+			policies:   policies,
+		},
+		{
+			name:       "allowed label prefix cannot unlock the node-restriction namespace",
+			attributes: update(nodeWithLabels(map[string]string{"node-restriction.kubernetes.io/foo": "bar"}), nodeWithLabels(nil)),
+			policies: prefixPolicies{
+				AllowedLabelPrefixes: []string{"node-restriction.kubernetes.io/"},
+			},
+			err: "is not allowed to modify labels: node-restriction.kubernetes.io/foo",
+		},
+		{
+			name:       "kubernetes labels outside the allowed prefixes remain forbidden",
+			attributes: update(nodeWithLabels(map[string]string{"other.kubernetes.io/foo": "bar"}), nodeWithLabels(nil)),
+			policies:   policies,
+			err:        "is not allowed to modify labels: other.kubernetes.io/foo",
+		},
+		{
+			name:       "denied label prefix forbids otherwise allowed labels",
+			attributes: update(nodeWithLabels(map[string]string{"example.com/denied-foo": "bar"}), nodeWithLabels(nil)),
+			policies:   policies,
+			err:        "is not allowed to modify labels: example.com/denied-foo",
+		},
+		{
+			name:       "denied label prefix forbids registration labels",
+			attributes: create(nodeWithLabels(map[string]string{"example.com/denied-foo": "bar"})),
+			policies:   policies,
+			err:        "is not allowed to set the following labels: example.com/denied-foo",
+		},
+		{
+			name:       "allowed taint prefix permits taint updates",
+			attributes: update(nodeWithTaints(api.Taint{Key: "taints.example.com/maintenance", Effect: api.TaintEffectNoSchedule}), nodeWithTaints()),
+			policies:   policies,
+		},
+		{
+			name:       "taints outside the allowed prefixes remain forbidden",
+			attributes: update(nodeWithTaints(api.Taint{Key: "other.example.com/foo", Effect: api.TaintEffectNoSchedule}), nodeWithTaints()),
+			policies:   policies,
+			err:        "is not allowed to modify taints: other.example.com/foo",
+		},
+		{
+			name:       "denied taint prefix wins over an allowed one",
+			attributes: update(nodeWithTaints(api.Taint{Key: "taints.example.com/denied-foo", Effect: api.TaintEffectNoSchedule}), nodeWithTaints()),
+			policies:   policies,
+			err:        "is not allowed to modify taints: taints.example.com/denied-foo",
+		},
+		{
+			name:       "denied taint prefix forbids registration taints",
+			attributes: create(nodeWithTaints(api.Taint{Key: "taints.example.com/denied-foo", Effect: api.TaintEffectNoSchedule})),
+			policies:   policies,
+			err:        "is not allowed to set the following taints: taints.example.com/denied-foo",
+		},
+		{
+			name:       "registration taints are allowed without a policy",
+			attributes: create(nodeWithTaints(api.Taint{Key: "other.example.com/foo", Effect: api.TaintEffectNoSchedule})),
+		},
+	}
+	for i := range tests {
+		tests[i].run(t)
+	}
+}
+
+func Test_readConfig(t *testing.T) {
+	config, err := readConfig(strings.NewReader(`
+nodeRestrictionPluginConfig:
+  allowedLabelPrefixes: ["labels.example.kubernetes.io/"]
+  deniedTaintPrefixes: ["taints.example.com/denied-"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies := config.NodeRestrictionPluginConfig
+	if len(policies.AllowedLabelPrefixes) != 1 || policies.AllowedLabelPrefixes[0] != "labels.example.kubernetes.io/" {
+		t.Errorf("unexpected allowed label prefixes %v", policies.AllowedLabelPrefixes)
+	}
+	if len(policies.DeniedTaintPrefixes) != 1 || policies.DeniedTaintPrefixes[0] != "taints.example.com/denied-" {
+		t.Errorf("unexpected denied taint prefixes %v", policies.DeniedTaintPrefixes)
+	}
+
+	if config, err = readConfig(nil); err != nil || len(config.NodeRestrictionPluginConfig.AllowedLabelPrefixes) != 0 {
+		t.Errorf("unexpected config for a nil reader: %+v, %v", config, err)
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objectnamingpolicy enforces cluster-wide naming and label
+// conventions at admission. Rules carry a CEL expression over object metadata
+// only — name, generateName, namespace, labels and annotations — so they stay
+// on an extremely cheap path that runs before any webhook is called, giving
+// platform teams convention guardrails without another webhook in the hot
+// path.
+package objectnamingpolicy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// PluginName is a string with the name of the plugin
+const PluginName = "ObjectNamingPolicy"
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
+		pluginConfig, err := readConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return newPlugin(pluginConfig)
+	})
+}
+
+// pluginConfig is the configuration read from the file provided with
+// --admission-control-config-file.
+type pluginConfig struct {
+	ObjectNamingPolicyPluginConfig struct {
+		Rules []ruleConfig `json:"rules"`
+	} `json:"objectNamingPolicyPluginConfig"`
+}
+
+// ruleConfig is one naming convention.
+type ruleConfig struct {
+	// Resources lists the resources the rule applies to, as
+	// "<resource>.<group>". A rule without resources applies to every
+	// resource.
+	Resources []string `json:"resources,omitempty"`
+	// Namespaces lists the namespaces the rule applies to. A rule without
+	// namespaces applies everywhere, including to cluster-scoped objects.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Expression is a CEL expression over the metadata variables name,
+	// generateName, namespace, labels and annotations. The request is
+	// forbidden when it evaluates to false.
+	Expression string `json:"expression"`
+	// Message is returned to the client when the expression rejects an
+	// object. When empty the expression itself is reported.
+	Message string `json:"message,omitempty"`
+}
+
+func readConfig(config io.Reader) (*pluginConfig, error) {
+	pluginConfig := &pluginConfig{}
+	if config == nil || reflect.ValueOf(config).IsNil() {
+		return pluginConfig, nil
+	}
+	d := yaml.NewYAMLOrJSONDecoder(config, 4096)
+	for {
+		if err := d.Decode(pluginConfig); err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("failed to read %s plugin configuration: %w", PluginName, err)
+			}
+			break
+		}
+	}
+	return pluginConfig, nil
+}
+
+// compiledRule is a ruleConfig with its expression compiled.
+type compiledRule struct {
+	ruleConfig
+	program cel.Program
+}
+
+func (r *compiledRule) matchesResource(resource string) bool {
+	if len(r.Resources) == 0 {
+		return true
+	}
+	for _, ruleResource := range r.Resources {
+		if ruleResource == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *compiledRule) matchesNamespace(namespace string) bool {
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, ruleNamespace := range r.Namespaces {
+		if ruleNamespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Plugin is an implementation of admission.Interface.
+type Plugin struct {
+	*admission.Handler
+	rules []compiledRule
+}
+
+var _ admission.ValidationInterface = &Plugin{}
+
+// newPlugin compiles the configured rules and returns the plugin.
+func newPlugin(config *pluginConfig) (*Plugin, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("name", cel.StringType),
+		cel.Variable("generateName", cel.StringType),
+		cel.Variable("namespace", cel.StringType),
+		cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("annotations", cel.MapType(cel.StringType, cel.StringType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment for %s: %w", PluginName, err)
+	}
+
+	rules := make([]compiledRule, 0, len(config.ObjectNamingPolicyPluginConfig.Rules))
+	for i, rule := range config.ObjectNamingPolicyPluginConfig.Rules {
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile expression of %s rule %d: %w", PluginName, i, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("expression of %s rule %d must evaluate to bool, got %v", PluginName, i, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("failed to program expression of %s rule %d: %w", PluginName, i, err)
+		}
+		rules = append(rules, compiledRule{ruleConfig: rule, program: program})
+	}
+
+	return &Plugin{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+		rules:   rules,
+	}, nil
+}
+
+// Validate rejects objects whose metadata violates a matching naming rule.
+func (p *Plugin) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if len(p.rules) == 0 || a.GetObject() == nil || a.GetSubresource() != "" {
+		return nil
+	}
+
+	resource := a.GetResource().GroupResource().String()
+	namespace := a.GetNamespace()
+
+	var matching []*compiledRule
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if rule.matchesResource(resource) && rule.matchesNamespace(namespace) {
+			matching = append(matching, rule)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+
+	accessor, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		// objects without object metadata have no naming to police
+		return nil
+	}
+	activation := map[string]interface{}{
+		"name":         accessor.GetName(),
+		"generateName": accessor.GetGenerateName(),
+		"namespace":    namespace,
+		"labels":       emptyIfNil(accessor.GetLabels()),
+		"annotations":  emptyIfNil(accessor.GetAnnotations()),
+	}
+
+	for _, rule := range matching {
+		out, _, err := rule.program.Eval(activation)
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("failed to evaluate naming policy expression %q: %w", rule.Expression, err))
+		}
+		if out != celtypes.True {
+			reason := rule.Message
+			if reason == "" {
+				reason = fmt.Sprintf("denied by naming policy expression %q", rule.Expression)
+			}
+			return admission.NewForbidden(a, fmt.Errorf("object metadata of %s %q violates the cluster naming policy: %s", resource, name(accessor), reason))
+		}
+	}
+	return nil
+}
+
+// name returns the object's name, falling back to its generate name for
+// objects created with generateName.
+func name(accessor metav1.Object) string {
+	if accessor.GetName() != "" {
+		return accessor.GetName()
+	}
+	return accessor.GetGenerateName() + "*"
+}
+
+func emptyIfNil(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
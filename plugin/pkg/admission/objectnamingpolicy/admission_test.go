@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectnamingpolicy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const testConfig = `
+objectNamingPolicyPluginConfig:
+  rules:
+  - resources: ["configmaps"]
+    namespaces: ["prod"]
+    expression: "name.startsWith('prod-')"
+    message: "configmap names in prod must start with prod-"
+  - expression: "'app.kubernetes.io/managed-by' in labels"
+`
+
+func newTestPlugin(t *testing.T, config string) *Plugin {
+	t.Helper()
+	pluginConfig, err := readConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := newPlugin(pluginConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plugin
+}
+
+func configMapAttributes(namespace, name string, labels map[string]string, subresource string) admission.Attributes {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	return admission.NewAttributesRecord(
+		cm, nil,
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		namespace, name,
+		corev1.SchemeGroupVersion.WithResource("configmaps"),
+		subresource, admission.Create, &metav1.CreateOptions{}, false,
+		&user.DefaultInfo{Name: "creator"})
+}
+
+func TestValidate(t *testing.T) {
+	managed := map[string]string{"app.kubernetes.io/managed-by": "helm"}
+
+	tests := []struct {
+		name        string
+		attributes  admission.Attributes
+		expectError string
+	}{
+		{
+			name:       "conforming object is admitted",
+			attributes: configMapAttributes("prod", "prod-settings", managed, ""),
+		},
+		{
+			name:        "name rule rejects in its namespace",
+			attributes:  configMapAttributes("prod", "settings", managed, ""),
+			expectError: "configmap names in prod must start with prod-",
+		},
+		{
+			name:       "name rule does not apply outside its namespace",
+			attributes: configMapAttributes("dev", "settings", managed, ""),
+		},
+		{
+			name:        "label rule applies everywhere",
+			attributes:  configMapAttributes("dev", "settings", nil, ""),
+			expectError: "denied by naming policy expression",
+		},
+		{
+			name:       "subresources are ignored",
+			attributes: configMapAttributes("prod", "settings", nil, "status"),
+		},
+	}
+
+	plugin := newTestPlugin(t, testConfig)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := plugin.Validate(context.Background(), tc.attributes, nil)
+			if tc.expectError == "" {
+				if err != nil {
+					t.Fatalf("expected the object to be admitted, got: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the object to be rejected")
+			}
+			if !apierrors.IsForbidden(err) {
+				t.Errorf("expected a forbidden error, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectError) {
+				t.Errorf("expected error to contain %q, got: %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestInvalidExpressions(t *testing.T) {
+	invalidConfigs := map[string]string{
+		"unparsable expression": `
+objectNamingPolicyPluginConfig:
+  rules:
+  - expression: "name.startsWith("
+`,
+		"non-bool expression": `
+objectNamingPolicyPluginConfig:
+  rules:
+  - expression: "name"
+`,
+	}
+	for name, config := range invalidConfigs {
+		t.Run(name, func(t *testing.T) {
+			pluginConfig, err := readConfig(strings.NewReader(config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := newPlugin(pluginConfig); err == nil {
+				t.Error("expected plugin construction to fail")
+			}
+		})
+	}
+}
+
+func TestEmptyConfigAdmitsEverything(t *testing.T) {
+	plugin := newTestPlugin(t, "")
+	if err := plugin.Validate(context.Background(), configMapAttributes("prod", "anything", nil, ""), nil); err != nil {
+		t.Fatalf("expected an unconfigured plugin to admit everything, got: %v", err)
+	}
+}
@@ -132,6 +132,7 @@ type RollingUpdateStatefulSetStrategy struct {
 // PersistentVolumeClaimRetentionPolicyType is a string enumeration of the policies that will determine
 // when volumes from the VolumeClaimTemplates will be deleted when the controlling StatefulSet is
 // deleted or scaled down.
+// +enum
 type PersistentVolumeClaimRetentionPolicyType string
 
 const (
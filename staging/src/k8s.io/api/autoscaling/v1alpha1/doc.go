@@ -0,0 +1,25 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=package
+
+// +groupName=autoscaling.k8s.io
+
+// Package v1alpha1 contains the ProvisioningRequest API, the contract between
+// workload controllers, the scheduler and cluster autoscalers for requesting
+// capacity ahead of pod scheduling.
+package v1alpha1 // import "k8s.io/api/autoscaling/v1alpha1"
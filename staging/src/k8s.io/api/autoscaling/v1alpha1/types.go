@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ProvisioningClassCheckCapacity asks the autoscaler to verify that the
+	// requested capacity is available in the cluster without provisioning new
+	// nodes. The request is marked Provisioned when the capacity exists.
+	ProvisioningClassCheckCapacity = "check-capacity.autoscaling.k8s.io"
+
+	// ProvisioningClassAtomicScaleUp asks the autoscaler to provision the
+	// requested capacity all at once, or not at all. Partially provisioned
+	// capacity is scaled back down.
+	ProvisioningClassAtomicScaleUp = "atomic-scale-up.autoscaling.k8s.io"
+)
+
+const (
+	// ProvisioningRequestPodAnnotationKey names the ProvisioningRequest, in
+	// the pod's namespace, that a gated pod is waiting on.
+	ProvisioningRequestPodAnnotationKey = "autoscaling.k8s.io/provisioning-request"
+
+	// ProvisioningRequestPodGate is the scheduling gate that keeps pods tied
+	// to a ProvisioningRequest out of the scheduling queue. The component
+	// that marks the request Provisioned is responsible for removing it.
+	ProvisioningRequestPodGate = "provisioning.autoscaling.k8s.io/pending"
+)
+
+// These are the valid condition types of a ProvisioningRequest.
+const (
+	// ProvisioningRequestAccepted means the autoscaler has seen the request
+	// and is working on it.
+	ProvisioningRequestAccepted = "Accepted"
+	// ProvisioningRequestProvisioned means the requested capacity is
+	// available and gated pods can be released for scheduling.
+	ProvisioningRequestProvisioned = "Provisioned"
+	// ProvisioningRequestFailed means the requested capacity cannot be
+	// provided and the request will not be retried.
+	ProvisioningRequestFailed = "Failed"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProvisioningRequest is a request for capacity ahead of pod scheduling. It
+// is the contract between workload controllers and queueing systems on one
+// side and cluster autoscalers on the other: the workload describes the pods
+// it is about to create, the autoscaler answers whether (or when) the
+// capacity for them is available.
+type ProvisioningRequest struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec describes the requested capacity.
+	Spec ProvisioningRequestSpec `json:"spec"`
+
+	// Status describes what the autoscaler made of the request.
+	// +optional
+	Status ProvisioningRequestStatus `json:"status,omitempty"`
+}
+
+// ProvisioningRequestSpec describes the requested capacity and how it should
+// be provisioned.
+type ProvisioningRequestSpec struct {
+	// ProvisioningClassName selects the semantics of the request, e.g.
+	// check-capacity or atomic scale-up. Classes outside the
+	// autoscaling.k8s.io suffix are reserved for custom autoscalers.
+	ProvisioningClassName string `json:"provisioningClassName"`
+
+	// PodSets describes the pods the capacity is requested for. The pods do
+	// not have to exist yet.
+	// +listType=atomic
+	PodSets []PodSet `json:"podSets"`
+
+	// Parameters carries provisioning-class specific parameters.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// PodSet is a number of pods sharing one pod template.
+type PodSet struct {
+	// PodTemplate describes what the pods that need capacity will look like.
+	PodTemplate v1.PodTemplateSpec `json:"podTemplate"`
+
+	// Count is the number of pods that will be created from this template.
+	Count int32 `json:"count"`
+}
+
+// ProvisioningRequestStatus is the observed state of a ProvisioningRequest.
+type ProvisioningRequestStatus struct {
+	// Conditions describes the request's progression through the autoscaler.
+	// Known condition types are Accepted, Provisioned and Failed.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ProvisioningClassDetails carries provisioning-class specific status,
+	// e.g. an expiration time for reserved capacity.
+	// +optional
+	ProvisioningClassDetails map[string]string `json:"provisioningClassDetails,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProvisioningRequestList is a collection of ProvisioningRequests.
+type ProvisioningRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ProvisioningRequests.
+	Items []ProvisioningRequest `json:"items"`
+}
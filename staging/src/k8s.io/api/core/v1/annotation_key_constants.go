@@ -34,6 +34,12 @@ const (
 	// in the Annotations of a Node.
 	TaintsAnnotationKey string = "scheduler.alpha.kubernetes.io/taints"
 
+	// PodCreationBatchAnnotationKey represents the key of a creation batch
+	// identifier stamped by workload controllers on the Pods of one creation
+	// burst. The scheduler keeps pods sharing a value adjacent in its
+	// scheduling queue.
+	PodCreationBatchAnnotationKey string = "scheduler.alpha.kubernetes.io/creation-batch"
+
 	// SeccompPodAnnotationKey represents the key of a seccomp profile applied
 	// to all containers of a pod.
 	// Deprecated: set a pod security context `seccompProfile` field.
@@ -148,4 +154,11 @@ const (
 	// Aware Hints for a Service. This may be set to "Auto" or "Disabled". Any
 	// other value is treated as "Disabled".
 	AnnotationTopologyAwareHints = "service.kubernetes.io/topology-aware-hints"
+
+	// AnnotationPreferLocalSpillover can be set on a Service using the
+	// PreferLocal traffic policy to the minimum number of ready local
+	// endpoints required to keep traffic node-local. When fewer local
+	// endpoints are ready (for example because their probes are failing),
+	// kube-proxy spills traffic over to the remote endpoints. Defaults to 1.
+	AnnotationPreferLocalSpillover = "service.kubernetes.io/prefer-local-spillover-threshold"
 )
@@ -15077,6 +15077,19 @@ func (m *PodReadinessGate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	i -= len(m.ExpiryStatus)
+	copy(dAtA[i:], m.ExpiryStatus)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ExpiryStatus)))
+	i--
+	dAtA[i] = 0x22
+	i = encodeVarintGenerated(dAtA, i, uint64(m.ExpirySeconds))
+	i--
+	dAtA[i] = 0x18
+	i -= len(m.DefaultStatus)
+	copy(dAtA[i:], m.DefaultStatus)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.DefaultStatus)))
+	i--
+	dAtA[i] = 0x12
 	i -= len(m.ConditionType)
 	copy(dAtA[i:], m.ConditionType)
 	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ConditionType)))
@@ -23060,6 +23073,11 @@ func (m *PodReadinessGate) Size() (n int) {
 	_ = l
 	l = len(m.ConditionType)
 	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.DefaultStatus)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.ExpirySeconds))
+	l = len(m.ExpiryStatus)
+	n += 1 + l + sovGenerated(uint64(l))
 	return n
 }
 
@@ -27098,6 +27116,9 @@ func (this *PodReadinessGate) String() string {
 	}
 	s := strings.Join([]string{`&PodReadinessGate{`,
 		`ConditionType:` + fmt.Sprintf("%v", this.ConditionType) + `,`,
+		`DefaultStatus:` + fmt.Sprintf("%v", this.DefaultStatus) + `,`,
+		`ExpirySeconds:` + fmt.Sprintf("%v", this.ExpirySeconds) + `,`,
+		`ExpiryStatus:` + fmt.Sprintf("%v", this.ExpiryStatus) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -53331,6 +53352,89 @@ func (m *PodReadinessGate) Unmarshal(dAtA []byte) error {
 			}
 			m.ConditionType = PodConditionType(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DefaultStatus", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DefaultStatus = ConditionStatus(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpirySeconds", wireType)
+			}
+			m.ExpirySeconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpirySeconds |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiryStatus", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExpiryStatus = ConditionStatus(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
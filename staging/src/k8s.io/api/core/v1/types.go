@@ -3114,6 +3114,28 @@ const (
 type PodReadinessGate struct {
 	// ConditionType refers to a condition in the pod's condition list with matching type.
 	ConditionType PodConditionType `json:"conditionType" protobuf:"bytes,1,opt,name=conditionType,casttype=PodConditionType"`
+	// DefaultStatus is the status assumed for the condition while the controller
+	// owning the gate has not reported it. When unset, a missing condition keeps
+	// the pod not ready.
+	// This is an alpha field and requires enabling the PodReadinessGateDefaults
+	// feature gate.
+	// +optional
+	DefaultStatus ConditionStatus `json:"defaultStatus,omitempty" protobuf:"bytes,2,opt,name=defaultStatus,casttype=ConditionStatus"`
+	// ExpirySeconds is the number of seconds after the pod's start time after
+	// which a condition that has still not been reported auto-resolves to
+	// ExpiryStatus, so pods do not stay not ready forever when the controller
+	// owning the gate is down. When zero, the gate never expires.
+	// This is an alpha field and requires enabling the PodReadinessGateDefaults
+	// feature gate.
+	// +optional
+	ExpirySeconds int64 `json:"expirySeconds,omitempty" protobuf:"varint,3,opt,name=expirySeconds"`
+	// ExpiryStatus is the status ("True" or "False") the condition resolves to
+	// once ExpirySeconds have elapsed. Defaults to "True". May only be set
+	// together with ExpirySeconds.
+	// This is an alpha field and requires enabling the PodReadinessGateDefaults
+	// feature gate.
+	// +optional
+	ExpiryStatus ConditionStatus `json:"expiryStatus,omitempty" protobuf:"bytes,4,opt,name=expiryStatus,casttype=ConditionStatus"`
 }
 
 // PodSpec is a description of a pod.
@@ -3453,6 +3475,7 @@ type ClaimSource struct {
 }
 
 // OSName is the set of OS'es that can be used in OS.
+// +enum
 type OSName string
 
 // These are valid values for OSName
@@ -4380,6 +4403,12 @@ const (
 	// ServiceInternalTrafficPolicyLocal routes traffic only to endpoints on the same
 	// node as the client pod (dropping the traffic if there are no local endpoints).
 	ServiceInternalTrafficPolicyLocal ServiceInternalTrafficPolicyType = "Local"
+
+	// ServiceInternalTrafficPolicyPreferLocal routes traffic to endpoints on the same
+	// node as the client pod when enough of them are ready, and spills over to
+	// remote endpoints otherwise. This is an alpha value and requires enabling the
+	// ServiceTrafficPolicyPreferLocal feature gate.
+	ServiceInternalTrafficPolicyPreferLocal ServiceInternalTrafficPolicyType = "PreferLocal"
 )
 
 // ServiceExternalTrafficPolicyType describes how nodes distribute service traffic they
@@ -4396,6 +4425,13 @@ const (
 	// routing only to endpoints on the same node as the traffic was received on
 	// (dropping the traffic if there are no local endpoints).
 	ServiceExternalTrafficPolicyTypeLocal ServiceExternalTrafficPolicyType = "Local"
+
+	// ServiceExternalTrafficPolicyTypePreferLocal routes traffic to endpoints on the
+	// same node as the traffic was received on when enough of them are ready, and
+	// spills over to remote endpoints otherwise. The source IP is not preserved
+	// once traffic spills over. This is an alpha value and requires enabling the
+	// ServiceTrafficPolicyPreferLocal feature gate.
+	ServiceExternalTrafficPolicyTypePreferLocal ServiceExternalTrafficPolicyType = "PreferLocal"
 )
 
 // These are the valid conditions of a service.
@@ -5336,6 +5372,7 @@ type NodeCondition struct {
 	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
 }
 
+// +enum
 type NodeAddressType string
 
 // These are built-in addresses type of node. A cloud provider may set a type not listed here.
@@ -6001,6 +6038,7 @@ type List metav1.List
 
 // LimitType is a type of object that is limited. It can be Pod, Container, PersistentVolumeClaim or
 // a fully qualified resource name.
+// +enum
 type LimitType string
 
 const (
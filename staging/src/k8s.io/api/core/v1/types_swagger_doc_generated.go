@@ -1610,6 +1610,9 @@ func (PodProxyOptions) SwaggerDoc() map[string]string {
 var map_PodReadinessGate = map[string]string{
 	"":              "PodReadinessGate contains the reference to a pod condition",
 	"conditionType": "ConditionType refers to a condition in the pod's condition list with matching type.",
+	"defaultStatus": "DefaultStatus is the status assumed for the condition while the controller owning the gate has not reported it. When unset, a missing condition keeps the pod not ready. This is an alpha field and requires enabling the PodReadinessGateDefaults feature gate.",
+	"expirySeconds": "ExpirySeconds is the number of seconds after the pod's start time after which a condition that has still not been reported auto-resolves to ExpiryStatus, so pods do not stay not ready forever when the controller owning the gate is down. When zero, the gate never expires. This is an alpha field and requires enabling the PodReadinessGateDefaults feature gate.",
+	"expiryStatus":  "ExpiryStatus is the status (\"True\" or \"False\") the condition resolves to once ExpirySeconds have elapsed. Defaults to \"True\". May only be set together with ExpirySeconds. This is an alpha field and requires enabling the PodReadinessGateDefaults feature gate.",
 }
 
 func (PodReadinessGate) SwaggerDoc() map[string]string {
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ImagePrepullImagesAnnotationKey is the contract between the image
+	// prepull controller and the kubelet: the controller writes a JSON array
+	// of image references onto the Node, ordered by the priority class of the
+	// ImagePrepullSets that requested them, and the kubelet pulls and pins
+	// the images in that order.
+	ImagePrepullImagesAnnotationKey = "prepull.node.k8s.io/images"
+
+	// ImagePrepullStatusAnnotationKey is written by the kubelet: a JSON
+	// object mapping each requested image reference to its pull state. The
+	// controller aggregates these per-node reports into ImagePrepullSet
+	// status.
+	ImagePrepullStatusAnnotationKey = "prepull.node.k8s.io/status"
+)
+
+// These are the image pull states the kubelet reports in the status
+// annotation. Failed states carry the pull error after the prefix.
+const (
+	// ImagePrepullStatePulled means the image is present on the node and
+	// pinned against image garbage collection.
+	ImagePrepullStatePulled = "pulled"
+	// ImagePrepullStatePending means the kubelet has seen the request but has
+	// not finished pulling the image yet.
+	ImagePrepullStatePending = "pending"
+	// ImagePrepullStateFailedPrefix prefixes the pull error of an image the
+	// kubelet could not pull. The kubelet retries on its next prepull pass.
+	ImagePrepullStateFailedPrefix = "failed: "
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePrepullSet declares images that the kubelets on selected nodes should
+// pull and pin before any pod references them, so pods of critical workloads
+// do not pay image pull latency on freshly provisioned nodes.
+type ImagePrepullSet struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec describes which images should be prepulled onto which nodes.
+	Spec ImagePrepullSetSpec `json:"spec"`
+
+	// Status reports per-node prepull progress.
+	// +optional
+	Status ImagePrepullSetStatus `json:"status,omitempty"`
+}
+
+// ImagePrepullSetSpec describes which images should be prepulled onto which
+// nodes.
+type ImagePrepullSetSpec struct {
+	// Images is the list of image references to pull and pin on every
+	// selected node.
+	// +listType=atomic
+	Images []string `json:"images"`
+
+	// NodeSelector selects the nodes the images should be prepulled onto. A
+	// nil selector selects all nodes.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// PriorityClassName references a PriorityClass that orders this set
+	// against other ImagePrepullSets selecting the same node: images of
+	// higher priority sets are pulled first. Sets without a priority class
+	// are pulled last.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// ImagePrepullSetStatus reports per-node prepull progress.
+type ImagePrepullSetStatus struct {
+	// Nodes holds one entry per selected node, aggregated from the kubelets'
+	// status reports.
+	// +optional
+	// +listType=map
+	// +listMapKey=nodeName
+	Nodes []ImagePrepullNodeStatus `json:"nodes,omitempty"`
+}
+
+// ImagePrepullNodeStatus is the prepull progress of one node.
+type ImagePrepullNodeStatus struct {
+	// NodeName is the name of the node this entry describes.
+	NodeName string `json:"nodeName"`
+
+	// PulledImages is the number of this set's images that are present and
+	// pinned on the node.
+	// +optional
+	PulledImages int32 `json:"pulledImages,omitempty"`
+
+	// PendingImages is the number of this set's images the node has not
+	// reported as pulled yet, including images whose pulls failed.
+	// +optional
+	PendingImages int32 `json:"pendingImages,omitempty"`
+
+	// LastError is the most recent pull failure reported by the node for one
+	// of this set's images, if any.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePrepullSetList is a collection of ImagePrepullSets.
+type ImagePrepullSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ImagePrepullSets.
+	Items []ImagePrepullSet `json:"items"`
+}
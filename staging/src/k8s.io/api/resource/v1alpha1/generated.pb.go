@@ -1113,6 +1113,14 @@ func (m *ResourceClaimSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	i--
+	if m.AdminAccess {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x20
 	i -= len(m.AllocationMode)
 	copy(dAtA[i:], m.AllocationMode)
 	i = encodeVarintGenerated(dAtA, i, uint64(len(m.AllocationMode)))
@@ -1671,6 +1679,7 @@ func (m *ResourceClaimSpec) Size() (n int) {
 	}
 	l = len(m.AllocationMode)
 	n += 1 + l + sovGenerated(uint64(l))
+	n += 2
 	return n
 }
 
@@ -1938,6 +1947,7 @@ func (this *ResourceClaimSpec) String() string {
 		`ResourceClassName:` + fmt.Sprintf("%v", this.ResourceClassName) + `,`,
 		`ParametersRef:` + strings.Replace(this.ParametersRef.String(), "ResourceClaimParametersReference", "ResourceClaimParametersReference", 1) + `,`,
 		`AllocationMode:` + fmt.Sprintf("%v", this.AllocationMode) + `,`,
+		`AdminAccess:` + fmt.Sprintf("%v", this.AdminAccess) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3483,6 +3493,26 @@ func (m *ResourceClaimSpec) Unmarshal(dAtA []byte) error {
 			}
 			m.AllocationMode = AllocationMode(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdminAccess", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AdminAccess = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
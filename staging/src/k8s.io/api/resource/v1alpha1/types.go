@@ -68,6 +68,13 @@ type ResourceClaimSpec struct {
 	// resource. "WaitForFirstConsumer" is the default.
 	// +optional
 	AllocationMode AllocationMode `json:"allocationMode,omitempty" protobuf:"bytes,3,opt,name=allocationMode"`
+
+	// AdminAccess marks a claim that is meant for administrative tasks
+	// like monitoring. Such a claim can always be reserved for additional
+	// pods, regardless of whether the allocated resource is shareable, and
+	// is not counted against the object count quota for claims.
+	// +optional
+	AdminAccess bool `json:"adminAccess,omitempty" protobuf:"varint,4,opt,name=adminAccess"`
 }
 
 // AllocationMode describes whether a ResourceClaim gets allocated immediately
@@ -137,6 +137,7 @@ var map_ResourceClaimSpec = map[string]string{
 	"resourceClassName": "ResourceClassName references the driver and additional parameters via the name of a ResourceClass that was created as part of the driver deployment.",
 	"parametersRef":     "ParametersRef references a separate object with arbitrary parameters that will be used by the driver when allocating a resource for the claim.\n\nThe object must be in the same namespace as the ResourceClaim.",
 	"allocationMode":    "Allocation can start immediately or when a Pod wants to use the resource. \"WaitForFirstConsumer\" is the default.",
+	"adminAccess":       "AdminAccess marks a claim that is meant for administrative tasks like monitoring. Such a claim can always be reserved for additional pods, regardless of whether the allocated resource is shareable, and is not counted against the object count quota for claims.",
 }
 
 func (ResourceClaimSpec) SwaggerDoc() map[string]string {
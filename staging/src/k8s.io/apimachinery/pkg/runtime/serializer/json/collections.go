@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package json
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// streamEncodeCollections encodes obj to w one item at a time when it is a
+// list, avoiding the second, fully serialized copy of the list that a
+// buffered json.Marshal produces. The bytes written are identical to those of
+// the buffered path. ok is false when obj is not a list this encoder knows
+// how to stream, in which case the caller must fall back to buffered
+// encoding.
+func streamEncodeCollections(obj runtime.Object, w io.Writer) (ok bool, err error) {
+	if list, isList := obj.(*unstructured.UnstructuredList); isList {
+		return true, streamEncodeUnstructuredList(list, w)
+	}
+	if _, isMarshaler := obj.(json.Marshaler); isMarshaler {
+		// the type controls its own serialization and cannot be streamed piecemeal
+		return false, nil
+	}
+	typeMeta, listMeta, items, isList := typedListFields(obj)
+	if !isList {
+		return false, nil
+	}
+	return true, streamEncodeTypedList(typeMeta, listMeta, items, w)
+}
+
+// typedListFields extracts the canonical TypeMeta/ListMeta/Items fields of a
+// generated list type. Any other shape, including unexpected json tags on
+// those fields, disqualifies the object from streaming.
+func typedListFields(obj runtime.Object) (typeMeta metav1.TypeMeta, listMeta metav1.ListMeta, items reflect.Value, ok bool) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return typeMeta, listMeta, items, false
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct || v.NumField() != 3 {
+		return typeMeta, listMeta, items, false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		switch field.Name {
+		case "TypeMeta":
+			m, isMeta := v.Field(i).Interface().(metav1.TypeMeta)
+			if !isMeta || field.Tag.Get("json") != ",inline" {
+				return typeMeta, listMeta, items, false
+			}
+			typeMeta = m
+		case "ListMeta":
+			m, isMeta := v.Field(i).Interface().(metav1.ListMeta)
+			if !isMeta || !strings.HasPrefix(field.Tag.Get("json"), "metadata") {
+				return typeMeta, listMeta, items, false
+			}
+			listMeta = m
+		case "Items":
+			if v.Field(i).Kind() != reflect.Slice || !strings.HasPrefix(field.Tag.Get("json"), "items") {
+				return typeMeta, listMeta, items, false
+			}
+			items = v.Field(i)
+		default:
+			return typeMeta, listMeta, items, false
+		}
+	}
+	if !items.IsValid() {
+		return typeMeta, listMeta, items, false
+	}
+	return typeMeta, listMeta, items, true
+}
+
+// streamWriter accumulates the first write error so the encoding code can
+// stay free of error handling at every write.
+type streamWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (sw *streamWriter) write(p []byte) {
+	if sw.err == nil {
+		_, sw.err = sw.w.Write(p)
+	}
+}
+
+func (sw *streamWriter) writeJSON(v interface{}) {
+	if sw.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		sw.err = err
+		return
+	}
+	sw.write(data)
+}
+
+// streamEncodeTypedList writes the list envelope and then each item
+// individually, mirroring the field order and omitempty behavior of the
+// json tags on generated list types.
+func streamEncodeTypedList(typeMeta metav1.TypeMeta, listMeta metav1.ListMeta, items reflect.Value, w io.Writer) error {
+	sw := &streamWriter{w: w}
+	sw.write([]byte{'{'})
+	if len(typeMeta.Kind) > 0 {
+		sw.write([]byte(`"kind":`))
+		sw.writeJSON(typeMeta.Kind)
+		sw.write([]byte{','})
+	}
+	if len(typeMeta.APIVersion) > 0 {
+		sw.write([]byte(`"apiVersion":`))
+		sw.writeJSON(typeMeta.APIVersion)
+		sw.write([]byte{','})
+	}
+	sw.write([]byte(`"metadata":`))
+	sw.writeJSON(listMeta)
+	sw.write([]byte(`,"items":`))
+	if items.IsNil() {
+		sw.write([]byte("null"))
+	} else {
+		sw.write([]byte{'['})
+		for i := 0; i < items.Len(); i++ {
+			if i > 0 {
+				sw.write([]byte{','})
+			}
+			sw.writeJSON(items.Index(i).Addr().Interface())
+		}
+		sw.write([]byte{']'})
+	}
+	// json.Encoder terminates the object with a newline
+	sw.write([]byte("}\n"))
+	return sw.err
+}
+
+// streamEncodeUnstructuredList writes the fields of the list in the sorted
+// key order encoding/json uses for maps, streaming the synthesized items
+// field in its sorted position.
+func streamEncodeUnstructuredList(list *unstructured.UnstructuredList, w io.Writer) error {
+	keys := make([]string, 0, len(list.Object)+1)
+	for k := range list.Object {
+		if k != "items" {
+			keys = append(keys, k)
+		}
+	}
+	keys = append(keys, "items")
+	sort.Strings(keys)
+
+	sw := &streamWriter{w: w}
+	sw.write([]byte{'{'})
+	for i, k := range keys {
+		if i > 0 {
+			sw.write([]byte{','})
+		}
+		sw.writeJSON(k)
+		sw.write([]byte{':'})
+		if k == "items" {
+			sw.write([]byte{'['})
+			for j := range list.Items {
+				if j > 0 {
+					sw.write([]byte{','})
+				}
+				sw.writeJSON(list.Items[j].Object)
+			}
+			sw.write([]byte{']'})
+			continue
+		}
+		sw.writeJSON(list.Object[k])
+	}
+	// json.Encoder terminates the object with a newline
+	sw.write([]byte("}\n"))
+	return sw.err
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package json_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+)
+
+type testItem struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Value string `json:"value,omitempty"`
+}
+
+func (in *testItem) DeepCopyObject() runtime.Object {
+	copied := *in
+	return &copied
+}
+
+type testItemList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []testItem `json:"items"`
+}
+
+func (in *testItemList) DeepCopyObject() runtime.Object {
+	copied := *in
+	copied.Items = append([]testItem(nil), in.Items...)
+	return &copied
+}
+
+func TestStreamingCollectionsEncoding(t *testing.T) {
+	testCases := []struct {
+		name string
+		obj  runtime.Object
+	}{
+		{
+			name: "populated typed list",
+			obj: &testItemList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "group/version", Kind: "TestList"},
+				ListMeta: metav1.ListMeta{ResourceVersion: "2345", Continue: "abc"},
+				Items: []testItem{
+					{ObjectMeta: metav1.ObjectMeta{Name: "first"}, Value: "1"},
+					{ObjectMeta: metav1.ObjectMeta{Name: "second"}},
+				},
+			},
+		},
+		{
+			name: "empty typed list",
+			obj: &testItemList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "group/version", Kind: "TestList"},
+				Items:    []testItem{},
+			},
+		},
+		{
+			name: "nil items typed list",
+			obj:  &testItemList{},
+		},
+		{
+			name: "unstructured list",
+			obj: &unstructured.UnstructuredList{
+				Object: map[string]interface{}{
+					"apiVersion": "group/version",
+					"kind":       "TestList",
+					"metadata":   map[string]interface{}{"resourceVersion": "2345"},
+				},
+				Items: []unstructured.Unstructured{
+					{Object: map[string]interface{}{"value": "first", "spec": map[string]interface{}{}}},
+					{Object: map[string]interface{}{"value": "second"}},
+				},
+			},
+		},
+		{
+			name: "empty unstructured list",
+			obj: &unstructured.UnstructuredList{
+				Object: map[string]interface{}{"kind": "TestList"},
+			},
+		},
+		{
+			name: "non-list object falls back",
+			obj:  &testItem{ObjectMeta: metav1.ObjectMeta{Name: "first"}, Value: "1"},
+		},
+	}
+
+	buffered := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{})
+	streaming := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{StreamingCollectionsEncoding: true})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var want, got bytes.Buffer
+			if err := buffered.Encode(tc.obj, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := streaming.Encode(tc.obj, &got); err != nil {
+				t.Fatal(err)
+			}
+			if want.String() != got.String() {
+				t.Errorf("streaming encoding differs from buffered encoding:\nwant: %s\ngot:  %s", want.String(), got.String())
+			}
+		})
+	}
+}
+
+func benchmarkList(size int) *testItemList {
+	list := &testItemList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "group/version", Kind: "TestList"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "2345"},
+		Items:    make([]testItem, 0, size),
+	}
+	for i := 0; i < size; i++ {
+		list.Items = append(list.Items, testItem{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("item-%d", i), Namespace: "default"},
+			Value:      "0123456789abcdef0123456789abcdef",
+		})
+	}
+	return list
+}
+
+func BenchmarkCollectionsEncoding(b *testing.B) {
+	for _, streaming := range []bool{false, true} {
+		serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{StreamingCollectionsEncoding: streaming})
+		for _, size := range []int{1000, 100000} {
+			list := benchmarkList(size)
+			b.Run(fmt.Sprintf("streaming=%t/items=%d", streaming, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := serializer.Encode(list, io.Discard); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
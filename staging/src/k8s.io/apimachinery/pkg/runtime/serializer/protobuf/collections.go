@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protobuf
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// listMetaFieldTag and listItemsFieldTag are the tags of the two fields every
+	// generated list message shares: ListMeta at field 1 and the repeated items
+	// at field 2, both length-delimited.
+	listMetaFieldTag  = 0x0a
+	listItemsFieldTag = 0x12
+	// the remaining tags mirror the generated marshalling of runtime.Unknown:
+	// typeMeta at field 1, raw at field 2, contentEncoding at field 3 and
+	// contentType at field 4, all length-delimited.
+	unknownTypeMetaFieldTag        = 0x0a
+	unknownRawFieldTag             = 0x12
+	unknownContentEncodingFieldTag = 0x1a
+	unknownContentTypeFieldTag     = 0x22
+)
+
+// streamingListData holds everything needed to write a typed list as a protobuf
+// message without first marshalling it into one contiguous buffer.
+type streamingListData struct {
+	// totalSize is the wire size of the whole list message.
+	totalSize int
+
+	listMeta     *metav1.ListMeta
+	listMetaSize int
+
+	// items point into the list's Items slice, each knowing its own wire size.
+	items     []bufferedMarshaller
+	itemSizes []int
+}
+
+// getStreamingListData inspects obj and, when it has the canonical
+// TypeMeta/ListMeta/Items shape of a generated list type, extracts the pieces
+// needed to stream it. ok is false when the object cannot be streamed and the
+// caller must fall back to the buffered path.
+func getStreamingListData(obj runtime.Object) (*streamingListData, bool) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, false
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct || v.NumField() != 3 {
+		return nil, false
+	}
+	data := &streamingListData{}
+	var items reflect.Value
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		switch field.Name {
+		case "TypeMeta":
+			// carried by the runtime.Unknown envelope, not the list message
+		case "ListMeta":
+			listMeta, ok := v.Field(i).Addr().Interface().(*metav1.ListMeta)
+			if !ok || !strings.Contains(field.Tag.Get("protobuf"), ",1,") {
+				return nil, false
+			}
+			data.listMeta = listMeta
+		case "Items":
+			if v.Field(i).Kind() != reflect.Slice || !strings.Contains(field.Tag.Get("protobuf"), ",2,rep,") {
+				return nil, false
+			}
+			items = v.Field(i)
+		default:
+			return nil, false
+		}
+	}
+	if data.listMeta == nil || !items.IsValid() {
+		return nil, false
+	}
+	data.listMetaSize = data.listMeta.Size()
+	data.totalSize = 1 + sovStreaming(uint64(data.listMetaSize)) + data.listMetaSize
+	data.items = make([]bufferedMarshaller, 0, items.Len())
+	data.itemSizes = make([]int, 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		item, ok := items.Index(i).Addr().Interface().(bufferedMarshaller)
+		if !ok {
+			return nil, false
+		}
+		size := item.Size()
+		data.items = append(data.items, item)
+		data.itemSizes = append(data.itemSizes, size)
+		data.totalSize += 1 + sovStreaming(uint64(size)) + size
+	}
+	return data, true
+}
+
+// streamingEncodeUnknownList writes the runtime.Unknown envelope followed by
+// the list message one item at a time. The bytes produced are identical to the
+// buffered path through Unknown.NestedMarshalTo.
+func (s *Serializer) streamingEncodeUnknownList(w io.Writer, unk runtime.Unknown, data *streamingListData, memAlloc runtime.MemoryAllocator) error {
+	if _, err := w.Write(s.prefix); err != nil {
+		return err
+	}
+	var scratch [1 + binary.MaxVarintLen64]byte
+
+	// Unknown.typeMeta, field 1
+	typeMetaSize := unk.TypeMeta.Size()
+	if err := writeFieldHeader(w, scratch[:], unknownTypeMetaFieldTag, typeMetaSize); err != nil {
+		return err
+	}
+	buf := memAlloc.Allocate(uint64(typeMetaSize))
+	n, err := unk.TypeMeta.MarshalTo(buf)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buf[:n]); err != nil {
+		return err
+	}
+
+	// Unknown.raw, field 2 - only the length prefix, the contents are streamed below
+	if err := writeFieldHeader(w, scratch[:], unknownRawFieldTag, data.totalSize); err != nil {
+		return err
+	}
+
+	// list ListMeta, field 1
+	if err := writeFieldHeader(w, scratch[:], listMetaFieldTag, data.listMetaSize); err != nil {
+		return err
+	}
+	buf = memAlloc.Allocate(uint64(data.listMetaSize))
+	n, err = data.listMeta.MarshalTo(buf)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buf[:n]); err != nil {
+		return err
+	}
+
+	// list items, field 2, sharing a single buffer sized for the largest item
+	maxItemSize := 0
+	for _, size := range data.itemSizes {
+		if size > maxItemSize {
+			maxItemSize = size
+		}
+	}
+	buf = memAlloc.Allocate(uint64(maxItemSize))
+	for i, item := range data.items {
+		if err := writeFieldHeader(w, scratch[:], listItemsFieldTag, data.itemSizes[i]); err != nil {
+			return err
+		}
+		n, err := item.MarshalTo(buf[:data.itemSizes[i]])
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+
+	// Unknown.contentEncoding and contentType, fields 3 and 4 - NestedMarshalTo
+	// writes both even when empty, so the streamed bytes must too
+	if err := writeFieldHeader(w, scratch[:], unknownContentEncodingFieldTag, len(unk.ContentEncoding)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, unk.ContentEncoding); err != nil {
+		return err
+	}
+	if err := writeFieldHeader(w, scratch[:], unknownContentTypeFieldTag, len(unk.ContentType)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, unk.ContentType); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeFieldHeader writes the tag and length prefix of a length-delimited
+// protobuf field.
+func writeFieldHeader(w io.Writer, scratch []byte, tag byte, size int) error {
+	scratch[0] = tag
+	n := binary.PutUvarint(scratch[1:], uint64(size))
+	_, err := w.Write(scratch[:1+n])
+	return err
+}
+
+// sovStreaming mirrors the sovGenerated helper emitted by gogo-protobuf and
+// returns the number of bytes the varint encoding of x occupies.
+func sovStreaming(x uint64) (n int) {
+	return (bits.Len64(x|1) + 6) / 7
+}
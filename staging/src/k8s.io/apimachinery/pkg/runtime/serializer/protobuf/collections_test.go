@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testapigroupv1 "k8s.io/apimachinery/pkg/apis/testapigroup/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestStreamingCollectionsEncoding(t *testing.T) {
+	testCases := []struct {
+		name string
+		obj  runtime.Object
+	}{
+		{
+			name: "populated list",
+			obj: &testapigroupv1.CarpList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "group/version", Kind: "CarpList"},
+				ListMeta: metav1.ListMeta{ResourceVersion: "2345", Continue: "abc"},
+				Items: []testapigroupv1.Carp{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "namespace"},
+						Spec:       testapigroupv1.CarpSpec{Subdomain: "carp.k8s.io"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "namespace"},
+					},
+				},
+			},
+		},
+		{
+			name: "empty list",
+			obj: &testapigroupv1.CarpList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "group/version", Kind: "CarpList"},
+				Items:    []testapigroupv1.Carp{},
+			},
+		},
+		{
+			name: "nil items list",
+			obj:  &testapigroupv1.CarpList{},
+		},
+		{
+			name: "non-list object falls back",
+			obj: &testapigroupv1.Carp{
+				ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "namespace"},
+			},
+		},
+	}
+
+	buffered := NewSerializer(nil, nil)
+	streaming := NewSerializerWithOptions(nil, nil, SerializerOptions{StreamingCollectionsEncoding: true})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var want, got bytes.Buffer
+			if err := buffered.Encode(tc.obj, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := streaming.Encode(tc.obj, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(want.Bytes(), got.Bytes()) {
+				t.Errorf("streaming encoding differs from buffered encoding:\nwant: %v\ngot:  %v", want.Bytes(), got.Bytes())
+			}
+		})
+	}
+}
+
+func benchmarkCarpList(size int) *testapigroupv1.CarpList {
+	list := &testapigroupv1.CarpList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "group/version", Kind: "CarpList"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "2345"},
+		Items:    make([]testapigroupv1.Carp, 0, size),
+	}
+	for i := 0; i < size; i++ {
+		list.Items = append(list.Items, testapigroupv1.Carp{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("carp-%d", i), Namespace: "default"},
+			Spec:       testapigroupv1.CarpSpec{Subdomain: "carp.k8s.io"},
+		})
+	}
+	return list
+}
+
+func BenchmarkCollectionsEncoding(b *testing.B) {
+	for _, streaming := range []bool{false, true} {
+		serializer := NewSerializerWithOptions(nil, nil, SerializerOptions{StreamingCollectionsEncoding: streaming})
+		for _, size := range []int{1000, 100000} {
+			list := benchmarkCarpList(size)
+			b.Run(fmt.Sprintf("streaming=%t/items=%d", streaming, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := serializer.Encode(list, io.Discard); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -72,18 +72,37 @@ func IsNotMarshalable(err error) bool {
 // is passed, the encoded object will have group, version, and kind fields set. If typer is nil, the objects will be written
 // as-is (any type info passed with the object will be used).
 func NewSerializer(creater runtime.ObjectCreater, typer runtime.ObjectTyper) *Serializer {
+	return NewSerializerWithOptions(creater, typer, SerializerOptions{})
+}
+
+// NewSerializerWithOptions creates a Protobuf serializer that handles encoding versioned objects into the proper wire form.
+// If a typer is passed, the encoded object will have group, version, and kind fields set. If typer is nil, the objects will
+// be written as-is (any type info passed with the object will be used). Options are copied into the Serializer and are
+// immutable.
+func NewSerializerWithOptions(creater runtime.ObjectCreater, typer runtime.ObjectTyper, options SerializerOptions) *Serializer {
 	return &Serializer{
 		prefix:  protoEncodingPrefix,
 		creater: creater,
 		typer:   typer,
+		options: options,
 	}
 }
 
+// SerializerOptions holds the options which are used to configure a Protobuf serializer.
+type SerializerOptions struct {
+	// StreamingCollectionsEncoding: configures the Serializer to encode list objects one
+	// item at a time instead of buffering the entire list, bounding the memory needed
+	// to serialize large collections. The bytes produced are identical to the buffered
+	// encoding.
+	StreamingCollectionsEncoding bool
+}
+
 // Serializer handles encoding versioned objects into the proper wire form
 type Serializer struct {
 	prefix  []byte
 	creater runtime.ObjectCreater
 	typer   runtime.ObjectTyper
+	options SerializerOptions
 }
 
 var _ runtime.Serializer = &Serializer{}
@@ -210,6 +229,13 @@ func (s *Serializer) doEncode(obj runtime.Object, w io.Writer, memAlloc runtime.
 		}
 	}
 
+	if s.options.StreamingCollectionsEncoding {
+		listData, ok := getStreamingListData(obj)
+		if ok {
+			return s.streamingEncodeUnknownList(w, unk, listData, memAlloc)
+		}
+	}
+
 	switch t := obj.(type) {
 	case bufferedMarshaller:
 		// this path performs a single allocation during write only when the Allocator wasn't provided
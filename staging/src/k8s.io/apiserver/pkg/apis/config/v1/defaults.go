@@ -27,6 +27,12 @@ var (
 	defaultTimeout          = &metav1.Duration{Duration: 3 * time.Second}
 	defaultCacheSize  int32 = 1000
 	defaultAPIVersion       = "v1"
+
+	defaultTPMDevicePath = "/dev/tpmrm0"
+	defaultTPMMode       = "aesgcm"
+	defaultTPMPCRs       = []int32{7}
+
+	defaultKeyringMode = "aesgcm"
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -46,4 +52,51 @@ func SetDefaults_KMSConfiguration(obj *KMSConfiguration) {
 	if obj.APIVersion == "" {
 		obj.APIVersion = defaultAPIVersion
 	}
+
+	if obj.DEKCache != nil && obj.DEKCache.SealedKeyFile != "" {
+		if obj.DEKCache.DevicePath == "" {
+			obj.DEKCache.DevicePath = defaultTPMDevicePath
+		}
+
+		if len(obj.DEKCache.PCRs) == 0 {
+			obj.DEKCache.PCRs = append([]int32(nil), defaultTPMPCRs...)
+		}
+	}
+}
+
+// SetDefaults_KMSSplitConfiguration applies defaults to KMSSplitConfiguration.
+func SetDefaults_KMSSplitConfiguration(obj *KMSSplitConfiguration) {
+	if obj.CacheSize == nil {
+		obj.CacheSize = &defaultCacheSize
+	}
+
+	if obj.Primary.Timeout == nil {
+		obj.Primary.Timeout = defaultTimeout
+	}
+
+	if obj.Secondary.Timeout == nil {
+		obj.Secondary.Timeout = defaultTimeout
+	}
+}
+
+// SetDefaults_TPMConfiguration applies defaults to TPMConfiguration.
+func SetDefaults_TPMConfiguration(obj *TPMConfiguration) {
+	if obj.DevicePath == "" {
+		obj.DevicePath = defaultTPMDevicePath
+	}
+
+	if len(obj.PCRs) == 0 {
+		obj.PCRs = append([]int32(nil), defaultTPMPCRs...)
+	}
+
+	if obj.Mode == "" {
+		obj.Mode = defaultTPMMode
+	}
+}
+
+// SetDefaults_KeyringConfiguration applies defaults to KeyringConfiguration.
+func SetDefaults_KeyringConfiguration(obj *KeyringConfiguration) {
+	if obj.Mode == "" {
+		obj.Mode = defaultKeyringMode
+	}
 }
@@ -91,6 +91,44 @@ func TestKMSProviderCacheDefaults(t *testing.T) {
 	}
 }
 
+func TestKMSProviderDEKCacheDefaults(t *testing.T) {
+	testCases := []struct {
+		desc string
+		in   *KMSConfiguration
+		want *KMSConfiguration
+	}{
+		{
+			desc: "dekCache not supplied",
+			in:   &KMSConfiguration{},
+			want: &KMSConfiguration{Timeout: defaultTimeout, CacheSize: &defaultCacheSize, APIVersion: defaultAPIVersion},
+		},
+		{
+			desc: "key file dekCache gets no TPM defaults",
+			in:   &KMSConfiguration{DEKCache: &DEKCacheConfiguration{File: "/run/kms/dek-cache", KeyFile: "/run/kms/dek-cache-key"}},
+			want: &KMSConfiguration{Timeout: defaultTimeout, CacheSize: &defaultCacheSize, APIVersion: defaultAPIVersion, DEKCache: &DEKCacheConfiguration{File: "/run/kms/dek-cache", KeyFile: "/run/kms/dek-cache-key"}},
+		},
+		{
+			desc: "sealed key dekCache gets TPM defaults",
+			in:   &KMSConfiguration{DEKCache: &DEKCacheConfiguration{File: "/run/kms/dek-cache", SealedKeyFile: "/etc/kms/sealed-key"}},
+			want: &KMSConfiguration{Timeout: defaultTimeout, CacheSize: &defaultCacheSize, APIVersion: defaultAPIVersion, DEKCache: &DEKCacheConfiguration{File: "/run/kms/dek-cache", SealedKeyFile: "/etc/kms/sealed-key", DevicePath: defaultTPMDevicePath, PCRs: []int32{7}}},
+		},
+		{
+			desc: "supplied TPM fields are kept",
+			in:   &KMSConfiguration{DEKCache: &DEKCacheConfiguration{File: "/run/kms/dek-cache", SealedKeyFile: "/etc/kms/sealed-key", DevicePath: "/dev/tpm0", PCRs: []int32{0, 7}}},
+			want: &KMSConfiguration{Timeout: defaultTimeout, CacheSize: &defaultCacheSize, APIVersion: defaultAPIVersion, DEKCache: &DEKCacheConfiguration{File: "/run/kms/dek-cache", SealedKeyFile: "/etc/kms/sealed-key", DevicePath: "/dev/tpm0", PCRs: []int32{0, 7}}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			SetDefaults_KMSConfiguration(tt.in)
+			if d := cmp.Diff(tt.want, tt.in); d != "" {
+				t.Fatalf("KMS Provider mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
 func TestKMSProviderAPIVersionDefaults(t *testing.T) {
 	testCases := []struct {
 		desc string
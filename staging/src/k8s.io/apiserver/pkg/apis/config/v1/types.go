@@ -29,6 +29,27 @@ type EncryptionConfiguration struct {
 	metav1.TypeMeta
 	// resources is a list containing resources, and their corresponding encryption providers.
 	Resources []ResourceConfiguration `json:"resources"`
+	// revocations is a list of key encryption keys that must no longer protect
+	// data, e.g. because they are known to be compromised. Reads of records
+	// protected by a revoked key fail with a distinct error and an audit
+	// annotation instead of serving the data.
+	// +optional
+	Revocations []RevocationConfiguration `json:"revocations,omitempty"`
+}
+
+// RevocationConfiguration declares a revoked key encryption key. Exactly one
+// of keyID or provider must be set.
+type RevocationConfiguration struct {
+	// keyID is a key_id reported by a KMS v2 plugin that must no longer
+	// decrypt data.
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+	// provider is the name of a KMS or KMSSplit provider none of whose keys
+	// may decrypt data anymore. The provider may remain configured (in a
+	// non-write position) so affected records keep failing distinctly instead
+	// of falling through to the unknown-prefix error.
+	// +optional
+	Provider string `json:"provider,omitempty"`
 }
 
 // ResourceConfiguration stores per resource configuration.
@@ -38,6 +59,12 @@ type ResourceConfiguration struct {
 	// providers is a list of transformers to be used for reading and writing the resources to disk.
 	// eg: aesgcm, aescbc, secretbox, identity.
 	Providers []ProviderConfiguration `json:"providers"`
+	// tier constrains which providers this rule may use. The empty value places
+	// no constraint. "local" requires every provider in the rule to be a local
+	// cipher, keeping high-churn resources such as events from generating KMS
+	// traffic. Rules that cover secrets may not be limited to local ciphers.
+	// +optional
+	Tier string `json:"tier,omitempty"`
 }
 
 // ProviderConfiguration stores the provided configuration for an encryption provider.
@@ -52,6 +79,14 @@ type ProviderConfiguration struct {
 	Identity *IdentityConfiguration `json:"identity,omitempty"`
 	// kms contains the name, cache size and path to configuration file for a KMS based envelope transformer.
 	KMS *KMSConfiguration `json:"kms,omitempty"`
+	// tpm is the configuration for a transformer whose key is sealed to the node's TPM.
+	TPM *TPMConfiguration `json:"tpm,omitempty"`
+	// x25519 is the configuration for a write-only transformer encrypting to offline X25519 recipients.
+	X25519 *X25519Configuration `json:"x25519,omitempty"`
+	// keyring is the configuration for an AES transformer whose keys live in a separate keyring file.
+	Keyring *KeyringConfiguration `json:"keyring,omitempty"`
+	// kmssplit is the configuration for a dual-control envelope transformer wrapping DEK shares with two independent KMS providers.
+	KMSSplit *KMSSplitConfiguration `json:"kmssplit,omitempty"`
 }
 
 // AESConfiguration contains the API configuration for an AES transformer.
@@ -73,7 +108,16 @@ type Key struct {
 	// name is the name of the key to be used while storing data to disk.
 	Name string `json:"name"`
 	// secret is the actual key, encoded in base64.
-	Secret string `json:"secret"`
+	// +optional
+	Secret string `json:"secret,omitempty"`
+	// secretFile is the path to a file containing the base64 encoded key.
+	// Mutually exclusive with secret. The file is re-read when the encryption
+	// configuration is reloaded.
+	// +optional
+	SecretFile string `json:"secretFile,omitempty"`
+	// provenance is optional key management metadata for this key.
+	// +optional
+	Provenance *ProvenanceConfiguration `json:"provenance,omitempty"`
 }
 
 // String implements Stringer interface in a log safe way.
@@ -81,9 +125,132 @@ func (k Key) String() string {
 	return fmt.Sprintf("Name: %s, Secret: [REDACTED]", k.Name)
 }
 
+// DEKCacheConfiguration configures persistence of the wrapped-to-plain DEK
+// cache of a KMS provider. The cache file is encrypted with a key that never
+// comes from the KMS: either a key file (typically kept on tmpfs, so the
+// cache survives apiserver restarts but not reboots) or a TPM-sealed key.
+type DEKCacheConfiguration struct {
+	// file is the path the encrypted DEK cache is persisted to.
+	File string `json:"file"`
+	// keyFile is the path of the cache encryption key. A fresh key is
+	// generated at that path on first use if the file does not exist.
+	// Mutually exclusive with sealedKeyFile.
+	// +optional
+	KeyFile string `json:"keyFile,omitempty"`
+	// sealedKeyFile is the path of a TPM-sealed cache encryption key.
+	// Mutually exclusive with keyFile.
+	// +optional
+	SealedKeyFile string `json:"sealedKeyFile,omitempty"`
+	// devicePath is the path of the TPM device used to unseal sealedKeyFile.
+	// The default is /dev/tpmrm0.
+	// +optional
+	DevicePath string `json:"devicePath,omitempty"`
+	// pcrs lists the PCR indexes bound into the unseal policy. The default is [7].
+	// +optional
+	PCRs []int32 `json:"pcrs,omitempty"`
+}
+
+// ProvenanceConfiguration carries optional key management metadata for a key
+// or provider. It is not used for encryption; it is validated, folded into the
+// configuration identity hash, and surfaced through the encryption state
+// endpoint so auditors can tie etcd prefixes back to key management records.
+type ProvenanceConfiguration struct {
+	// createdAt is the RFC 3339 timestamp the key material was created.
+	// +optional
+	CreatedAt string `json:"createdAt,omitempty"`
+	// owner identifies the party responsible for the key.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// ticket references the key management record the key was issued under.
+	// +optional
+	Ticket string `json:"ticket,omitempty"`
+}
+
 // IdentityConfiguration is an empty struct to allow identity transformer in provider configuration.
 type IdentityConfiguration struct{}
 
+// TPMConfiguration contains the API configuration for a transformer whose key
+// encryption key is sealed to the node's TPM 2.0 device, so the raw key is
+// never stored on the filesystem.
+type TPMConfiguration struct {
+	// name is the name of the sealed key, used as part of the stored data prefix.
+	Name string `json:"name"`
+	// sealedKeyFile is the path to the key blob sealed by the TPM.
+	SealedKeyFile string `json:"sealedKeyFile"`
+	// devicePath is the path of the TPM device. The default is /dev/tpmrm0.
+	// +optional
+	DevicePath string `json:"devicePath,omitempty"`
+	// pcrs lists the PCR indexes bound into the unseal policy. The default is [7].
+	// +optional
+	PCRs []int32 `json:"pcrs,omitempty"`
+	// mode is the block cipher mode used with the unsealed key, either aesgcm or aescbc.
+	// The default is aesgcm.
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// X25519Configuration contains the API configuration for a transformer that
+// encrypts to one or more X25519 recipient public keys. The matching private
+// keys are held offline, so the apiserver can write but never read the data:
+// decryption is reserved for break-glass recovery tooling.
+type X25519Configuration struct {
+	// name is the name of the provider, used as part of the stored data prefix.
+	Name string `json:"name"`
+	// recipients is a list of X25519 recipient public keys, each 32 bytes
+	// encoded in base64. The data encryption key is wrapped to every recipient.
+	Recipients []string `json:"recipients"`
+}
+
+// KeyringConfiguration contains the API configuration for a transformer whose
+// AES keys are kept in a keyring file instead of inline in this config. The
+// last key in the keyring is the write key, so rotating is appending a key.
+// Keys removed from the keyring can no longer decrypt the records that still
+// reference them; such reads fail naming the missing key.
+type KeyringConfiguration struct {
+	// path is the path to the keyring file, a YAML list of name/secret pairs.
+	Path string `json:"path"`
+	// mode is the block cipher mode used with the keys, either aesgcm or aescbc.
+	// The default is aesgcm.
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// KMSSplitConfiguration contains the configuration for a dual-control envelope
+// transformer that splits each DEK into two shares with a one-time pad and
+// wraps the shares with two independent KMS providers. Both providers must be
+// available to decrypt, so a single compromised KMS account cannot read the
+// stored data.
+type KMSSplitConfiguration struct {
+	// name is the name of this provider, used as part of the stored data prefix.
+	Name string `json:"name"`
+	// cachesize is the maximum number of secrets which are cached in memory. The default value is 1000.
+	// Set to a negative value to disable caching.
+	// +optional
+	CacheSize *int32 `json:"cachesize,omitempty"`
+	// primary is the KMS plugin wrapping the first DEK share.
+	Primary KMSSplitEndpoint `json:"primary"`
+	// secondary is the KMS plugin wrapping the second DEK share. The pair is
+	// order-sensitive; swapping the endpoints makes previously written data
+	// unreadable.
+	Secondary KMSSplitEndpoint `json:"secondary"`
+	// failClosedWrites rejects writes through this provider immediately while
+	// its health probe reports either plugin unhealthy, instead of letting
+	// every write discover the outage on its own timeout. Reads keep being
+	// served from cached data encryption keys.
+	// +optional
+	FailClosedWrites bool `json:"failClosedWrites,omitempty"`
+}
+
+// KMSSplitEndpoint identifies one of the two KMS plugins wrapping DEK shares
+// for a kmssplit provider. The plugins speak the v1 KeyManagementService API.
+type KMSSplitEndpoint struct {
+	// endpoint is the gRPC server listening address, for example "unix:///var/run/kms-provider.sock".
+	Endpoint string `json:"endpoint"`
+	// timeout for gRPC calls to kms-plugin (ex. 5s). The default is 3 seconds.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // KMSConfiguration contains the name, cache size and path to configuration file for a KMS based envelope transformer.
 type KMSConfiguration struct {
 	// apiVersion of KeyManagementService
@@ -91,6 +258,12 @@ type KMSConfiguration struct {
 	APIVersion string `json:"apiVersion"`
 	// name is the name of the KMS plugin to be used.
 	Name string `json:"name"`
+	// aliases are previous names of this provider. Records written under an
+	// aliased name decrypt through this provider and are rewritten under the
+	// current name on their next update, so a provider can be renamed without
+	// a full storage migration.
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
 	// cachesize is the maximum number of secrets which are cached in memory. The default value is 1000.
 	// Set to a negative value to disable caching.
 	// +optional
@@ -100,4 +273,37 @@ type KMSConfiguration struct {
 	// timeout for gRPC calls to kms-plugin (ex. 5s). The default is 3 seconds.
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// maxInFlight bounds how many Encrypt and Decrypt calls may be outstanding
+	// against this plugin at once; further calls wait for a slot. This protects
+	// low-throughput plugins, such as HSM-backed ones, from being overwhelmed
+	// when a migration or restart unwraps many keys at once. Unset means no
+	// bound.
+	// +optional
+	MaxInFlight *int32 `json:"maxInFlight,omitempty"`
+	// dekReuseMaxWrites is the number of objects a single data encryption key
+	// may encrypt before a fresh one is requested from the plugin, trading KMS
+	// call volume against the blast radius of a leaked key. Only honored with
+	// apiVersion v2 or later. Unset or 1 generates a new key for every write.
+	// +optional
+	DEKReuseMaxWrites *int32 `json:"dekReuseMaxWrites,omitempty"`
+	// dekReuseDuration bounds how long a data encryption key may be reused
+	// regardless of how few writes it has served. Only honored with apiVersion
+	// v2 or later. Unset means writes alone bound reuse.
+	// +optional
+	DEKReuseDuration *metav1.Duration `json:"dekReuseDuration,omitempty"`
+	// dekCache optionally persists unwrapped data encryption keys, encrypted
+	// at rest, so that an apiserver restarted during a KMS outage can keep
+	// serving reads of previously accessed objects. Only honored with
+	// apiVersion v2 or later.
+	// +optional
+	DEKCache *DEKCacheConfiguration `json:"dekCache,omitempty"`
+	// failClosedWrites rejects writes through this provider immediately while
+	// its health probe reports the plugin unhealthy, instead of letting every
+	// write discover the outage on its own timeout. Reads keep being served
+	// from cached data encryption keys.
+	// +optional
+	FailClosedWrites bool `json:"failClosedWrites,omitempty"`
+	// provenance is optional key management metadata for this provider.
+	// +optional
+	Provenance *ProvenanceConfiguration `json:"provenance,omitempty"`
 }
@@ -47,6 +47,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DEKCacheConfiguration)(nil), (*config.DEKCacheConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DEKCacheConfiguration_To_config_DEKCacheConfiguration(a.(*DEKCacheConfiguration), b.(*config.DEKCacheConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DEKCacheConfiguration)(nil), (*DEKCacheConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DEKCacheConfiguration_To_v1_DEKCacheConfiguration(a.(*config.DEKCacheConfiguration), b.(*DEKCacheConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EncryptionConfiguration)(nil), (*config.EncryptionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_EncryptionConfiguration_To_config_EncryptionConfiguration(a.(*EncryptionConfiguration), b.(*config.EncryptionConfiguration), scope)
 	}); err != nil {
@@ -77,6 +87,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KMSSplitConfiguration)(nil), (*config.KMSSplitConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_KMSSplitConfiguration_To_config_KMSSplitConfiguration(a.(*KMSSplitConfiguration), b.(*config.KMSSplitConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.KMSSplitConfiguration)(nil), (*KMSSplitConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_KMSSplitConfiguration_To_v1_KMSSplitConfiguration(a.(*config.KMSSplitConfiguration), b.(*KMSSplitConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KMSSplitEndpoint)(nil), (*config.KMSSplitEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint(a.(*KMSSplitEndpoint), b.(*config.KMSSplitEndpoint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.KMSSplitEndpoint)(nil), (*KMSSplitEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint(a.(*config.KMSSplitEndpoint), b.(*KMSSplitEndpoint), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Key)(nil), (*config.Key)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_Key_To_config_Key(a.(*Key), b.(*config.Key), scope)
 	}); err != nil {
@@ -87,6 +117,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KeyringConfiguration)(nil), (*config.KeyringConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_KeyringConfiguration_To_config_KeyringConfiguration(a.(*KeyringConfiguration), b.(*config.KeyringConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.KeyringConfiguration)(nil), (*KeyringConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_KeyringConfiguration_To_v1_KeyringConfiguration(a.(*config.KeyringConfiguration), b.(*KeyringConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ProvenanceConfiguration)(nil), (*config.ProvenanceConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ProvenanceConfiguration_To_config_ProvenanceConfiguration(a.(*ProvenanceConfiguration), b.(*config.ProvenanceConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ProvenanceConfiguration)(nil), (*ProvenanceConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ProvenanceConfiguration_To_v1_ProvenanceConfiguration(a.(*config.ProvenanceConfiguration), b.(*ProvenanceConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ProviderConfiguration)(nil), (*config.ProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ProviderConfiguration_To_config_ProviderConfiguration(a.(*ProviderConfiguration), b.(*config.ProviderConfiguration), scope)
 	}); err != nil {
@@ -107,6 +157,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RevocationConfiguration)(nil), (*config.RevocationConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_RevocationConfiguration_To_config_RevocationConfiguration(a.(*RevocationConfiguration), b.(*config.RevocationConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RevocationConfiguration)(nil), (*RevocationConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RevocationConfiguration_To_v1_RevocationConfiguration(a.(*config.RevocationConfiguration), b.(*RevocationConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SecretboxConfiguration)(nil), (*config.SecretboxConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_SecretboxConfiguration_To_config_SecretboxConfiguration(a.(*SecretboxConfiguration), b.(*config.SecretboxConfiguration), scope)
 	}); err != nil {
@@ -117,6 +177,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TPMConfiguration)(nil), (*config.TPMConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_TPMConfiguration_To_config_TPMConfiguration(a.(*TPMConfiguration), b.(*config.TPMConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TPMConfiguration)(nil), (*TPMConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TPMConfiguration_To_v1_TPMConfiguration(a.(*config.TPMConfiguration), b.(*TPMConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*X25519Configuration)(nil), (*config.X25519Configuration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_X25519Configuration_To_config_X25519Configuration(a.(*X25519Configuration), b.(*config.X25519Configuration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.X25519Configuration)(nil), (*X25519Configuration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_X25519Configuration_To_v1_X25519Configuration(a.(*config.X25519Configuration), b.(*X25519Configuration), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -140,8 +220,37 @@ func Convert_config_AESConfiguration_To_v1_AESConfiguration(in *config.AESConfig
 	return autoConvert_config_AESConfiguration_To_v1_AESConfiguration(in, out, s)
 }
 
+func autoConvert_v1_DEKCacheConfiguration_To_config_DEKCacheConfiguration(in *DEKCacheConfiguration, out *config.DEKCacheConfiguration, s conversion.Scope) error {
+	out.File = in.File
+	out.KeyFile = in.KeyFile
+	out.SealedKeyFile = in.SealedKeyFile
+	out.DevicePath = in.DevicePath
+	out.PCRs = *(*[]int32)(unsafe.Pointer(&in.PCRs))
+	return nil
+}
+
+// Convert_v1_DEKCacheConfiguration_To_config_DEKCacheConfiguration is an autogenerated conversion function.
+func Convert_v1_DEKCacheConfiguration_To_config_DEKCacheConfiguration(in *DEKCacheConfiguration, out *config.DEKCacheConfiguration, s conversion.Scope) error {
+	return autoConvert_v1_DEKCacheConfiguration_To_config_DEKCacheConfiguration(in, out, s)
+}
+
+func autoConvert_config_DEKCacheConfiguration_To_v1_DEKCacheConfiguration(in *config.DEKCacheConfiguration, out *DEKCacheConfiguration, s conversion.Scope) error {
+	out.File = in.File
+	out.KeyFile = in.KeyFile
+	out.SealedKeyFile = in.SealedKeyFile
+	out.DevicePath = in.DevicePath
+	out.PCRs = *(*[]int32)(unsafe.Pointer(&in.PCRs))
+	return nil
+}
+
+// Convert_config_DEKCacheConfiguration_To_v1_DEKCacheConfiguration is an autogenerated conversion function.
+func Convert_config_DEKCacheConfiguration_To_v1_DEKCacheConfiguration(in *config.DEKCacheConfiguration, out *DEKCacheConfiguration, s conversion.Scope) error {
+	return autoConvert_config_DEKCacheConfiguration_To_v1_DEKCacheConfiguration(in, out, s)
+}
+
 func autoConvert_v1_EncryptionConfiguration_To_config_EncryptionConfiguration(in *EncryptionConfiguration, out *config.EncryptionConfiguration, s conversion.Scope) error {
 	out.Resources = *(*[]config.ResourceConfiguration)(unsafe.Pointer(&in.Resources))
+	out.Revocations = *(*[]config.RevocationConfiguration)(unsafe.Pointer(&in.Revocations))
 	return nil
 }
 
@@ -152,6 +261,7 @@ func Convert_v1_EncryptionConfiguration_To_config_EncryptionConfiguration(in *En
 
 func autoConvert_config_EncryptionConfiguration_To_v1_EncryptionConfiguration(in *config.EncryptionConfiguration, out *EncryptionConfiguration, s conversion.Scope) error {
 	out.Resources = *(*[]ResourceConfiguration)(unsafe.Pointer(&in.Resources))
+	out.Revocations = *(*[]RevocationConfiguration)(unsafe.Pointer(&in.Revocations))
 	return nil
 }
 
@@ -181,9 +291,16 @@ func Convert_config_IdentityConfiguration_To_v1_IdentityConfiguration(in *config
 func autoConvert_v1_KMSConfiguration_To_config_KMSConfiguration(in *KMSConfiguration, out *config.KMSConfiguration, s conversion.Scope) error {
 	out.APIVersion = in.APIVersion
 	out.Name = in.Name
+	out.Aliases = *(*[]string)(unsafe.Pointer(&in.Aliases))
 	out.CacheSize = (*int32)(unsafe.Pointer(in.CacheSize))
 	out.Endpoint = in.Endpoint
 	out.Timeout = (*metav1.Duration)(unsafe.Pointer(in.Timeout))
+	out.MaxInFlight = (*int32)(unsafe.Pointer(in.MaxInFlight))
+	out.DEKReuseMaxWrites = (*int32)(unsafe.Pointer(in.DEKReuseMaxWrites))
+	out.DEKReuseDuration = (*metav1.Duration)(unsafe.Pointer(in.DEKReuseDuration))
+	out.DEKCache = (*config.DEKCacheConfiguration)(unsafe.Pointer(in.DEKCache))
+	out.FailClosedWrites = in.FailClosedWrites
+	out.Provenance = (*config.ProvenanceConfiguration)(unsafe.Pointer(in.Provenance))
 	return nil
 }
 
@@ -195,9 +312,16 @@ func Convert_v1_KMSConfiguration_To_config_KMSConfiguration(in *KMSConfiguration
 func autoConvert_config_KMSConfiguration_To_v1_KMSConfiguration(in *config.KMSConfiguration, out *KMSConfiguration, s conversion.Scope) error {
 	out.APIVersion = in.APIVersion
 	out.Name = in.Name
+	out.Aliases = *(*[]string)(unsafe.Pointer(&in.Aliases))
 	out.CacheSize = (*int32)(unsafe.Pointer(in.CacheSize))
 	out.Endpoint = in.Endpoint
 	out.Timeout = (*metav1.Duration)(unsafe.Pointer(in.Timeout))
+	out.MaxInFlight = (*int32)(unsafe.Pointer(in.MaxInFlight))
+	out.DEKReuseMaxWrites = (*int32)(unsafe.Pointer(in.DEKReuseMaxWrites))
+	out.DEKReuseDuration = (*metav1.Duration)(unsafe.Pointer(in.DEKReuseDuration))
+	out.DEKCache = (*DEKCacheConfiguration)(unsafe.Pointer(in.DEKCache))
+	out.FailClosedWrites = in.FailClosedWrites
+	out.Provenance = (*ProvenanceConfiguration)(unsafe.Pointer(in.Provenance))
 	return nil
 }
 
@@ -206,9 +330,69 @@ func Convert_config_KMSConfiguration_To_v1_KMSConfiguration(in *config.KMSConfig
 	return autoConvert_config_KMSConfiguration_To_v1_KMSConfiguration(in, out, s)
 }
 
+func autoConvert_v1_KMSSplitConfiguration_To_config_KMSSplitConfiguration(in *KMSSplitConfiguration, out *config.KMSSplitConfiguration, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CacheSize = (*int32)(unsafe.Pointer(in.CacheSize))
+	if err := Convert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint(&in.Primary, &out.Primary, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint(&in.Secondary, &out.Secondary, s); err != nil {
+		return err
+	}
+	out.FailClosedWrites = in.FailClosedWrites
+	return nil
+}
+
+// Convert_v1_KMSSplitConfiguration_To_config_KMSSplitConfiguration is an autogenerated conversion function.
+func Convert_v1_KMSSplitConfiguration_To_config_KMSSplitConfiguration(in *KMSSplitConfiguration, out *config.KMSSplitConfiguration, s conversion.Scope) error {
+	return autoConvert_v1_KMSSplitConfiguration_To_config_KMSSplitConfiguration(in, out, s)
+}
+
+func autoConvert_config_KMSSplitConfiguration_To_v1_KMSSplitConfiguration(in *config.KMSSplitConfiguration, out *KMSSplitConfiguration, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CacheSize = (*int32)(unsafe.Pointer(in.CacheSize))
+	if err := Convert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint(&in.Primary, &out.Primary, s); err != nil {
+		return err
+	}
+	if err := Convert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint(&in.Secondary, &out.Secondary, s); err != nil {
+		return err
+	}
+	out.FailClosedWrites = in.FailClosedWrites
+	return nil
+}
+
+// Convert_config_KMSSplitConfiguration_To_v1_KMSSplitConfiguration is an autogenerated conversion function.
+func Convert_config_KMSSplitConfiguration_To_v1_KMSSplitConfiguration(in *config.KMSSplitConfiguration, out *KMSSplitConfiguration, s conversion.Scope) error {
+	return autoConvert_config_KMSSplitConfiguration_To_v1_KMSSplitConfiguration(in, out, s)
+}
+
+func autoConvert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint(in *KMSSplitEndpoint, out *config.KMSSplitEndpoint, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Timeout = (*metav1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint is an autogenerated conversion function.
+func Convert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint(in *KMSSplitEndpoint, out *config.KMSSplitEndpoint, s conversion.Scope) error {
+	return autoConvert_v1_KMSSplitEndpoint_To_config_KMSSplitEndpoint(in, out, s)
+}
+
+func autoConvert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint(in *config.KMSSplitEndpoint, out *KMSSplitEndpoint, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Timeout = (*metav1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint is an autogenerated conversion function.
+func Convert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint(in *config.KMSSplitEndpoint, out *KMSSplitEndpoint, s conversion.Scope) error {
+	return autoConvert_config_KMSSplitEndpoint_To_v1_KMSSplitEndpoint(in, out, s)
+}
+
 func autoConvert_v1_Key_To_config_Key(in *Key, out *config.Key, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Secret = in.Secret
+	out.SecretFile = in.SecretFile
+	out.Provenance = (*config.ProvenanceConfiguration)(unsafe.Pointer(in.Provenance))
 	return nil
 }
 
@@ -220,6 +404,8 @@ func Convert_v1_Key_To_config_Key(in *Key, out *config.Key, s conversion.Scope)
 func autoConvert_config_Key_To_v1_Key(in *config.Key, out *Key, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Secret = in.Secret
+	out.SecretFile = in.SecretFile
+	out.Provenance = (*ProvenanceConfiguration)(unsafe.Pointer(in.Provenance))
 	return nil
 }
 
@@ -228,12 +414,62 @@ func Convert_config_Key_To_v1_Key(in *config.Key, out *Key, s conversion.Scope)
 	return autoConvert_config_Key_To_v1_Key(in, out, s)
 }
 
+func autoConvert_v1_KeyringConfiguration_To_config_KeyringConfiguration(in *KeyringConfiguration, out *config.KeyringConfiguration, s conversion.Scope) error {
+	out.Path = in.Path
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_v1_KeyringConfiguration_To_config_KeyringConfiguration is an autogenerated conversion function.
+func Convert_v1_KeyringConfiguration_To_config_KeyringConfiguration(in *KeyringConfiguration, out *config.KeyringConfiguration, s conversion.Scope) error {
+	return autoConvert_v1_KeyringConfiguration_To_config_KeyringConfiguration(in, out, s)
+}
+
+func autoConvert_config_KeyringConfiguration_To_v1_KeyringConfiguration(in *config.KeyringConfiguration, out *KeyringConfiguration, s conversion.Scope) error {
+	out.Path = in.Path
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_config_KeyringConfiguration_To_v1_KeyringConfiguration is an autogenerated conversion function.
+func Convert_config_KeyringConfiguration_To_v1_KeyringConfiguration(in *config.KeyringConfiguration, out *KeyringConfiguration, s conversion.Scope) error {
+	return autoConvert_config_KeyringConfiguration_To_v1_KeyringConfiguration(in, out, s)
+}
+
+func autoConvert_v1_ProvenanceConfiguration_To_config_ProvenanceConfiguration(in *ProvenanceConfiguration, out *config.ProvenanceConfiguration, s conversion.Scope) error {
+	out.CreatedAt = in.CreatedAt
+	out.Owner = in.Owner
+	out.Ticket = in.Ticket
+	return nil
+}
+
+// Convert_v1_ProvenanceConfiguration_To_config_ProvenanceConfiguration is an autogenerated conversion function.
+func Convert_v1_ProvenanceConfiguration_To_config_ProvenanceConfiguration(in *ProvenanceConfiguration, out *config.ProvenanceConfiguration, s conversion.Scope) error {
+	return autoConvert_v1_ProvenanceConfiguration_To_config_ProvenanceConfiguration(in, out, s)
+}
+
+func autoConvert_config_ProvenanceConfiguration_To_v1_ProvenanceConfiguration(in *config.ProvenanceConfiguration, out *ProvenanceConfiguration, s conversion.Scope) error {
+	out.CreatedAt = in.CreatedAt
+	out.Owner = in.Owner
+	out.Ticket = in.Ticket
+	return nil
+}
+
+// Convert_config_ProvenanceConfiguration_To_v1_ProvenanceConfiguration is an autogenerated conversion function.
+func Convert_config_ProvenanceConfiguration_To_v1_ProvenanceConfiguration(in *config.ProvenanceConfiguration, out *ProvenanceConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ProvenanceConfiguration_To_v1_ProvenanceConfiguration(in, out, s)
+}
+
 func autoConvert_v1_ProviderConfiguration_To_config_ProviderConfiguration(in *ProviderConfiguration, out *config.ProviderConfiguration, s conversion.Scope) error {
 	out.AESGCM = (*config.AESConfiguration)(unsafe.Pointer(in.AESGCM))
 	out.AESCBC = (*config.AESConfiguration)(unsafe.Pointer(in.AESCBC))
 	out.Secretbox = (*config.SecretboxConfiguration)(unsafe.Pointer(in.Secretbox))
 	out.Identity = (*config.IdentityConfiguration)(unsafe.Pointer(in.Identity))
 	out.KMS = (*config.KMSConfiguration)(unsafe.Pointer(in.KMS))
+	out.TPM = (*config.TPMConfiguration)(unsafe.Pointer(in.TPM))
+	out.X25519 = (*config.X25519Configuration)(unsafe.Pointer(in.X25519))
+	out.Keyring = (*config.KeyringConfiguration)(unsafe.Pointer(in.Keyring))
+	out.KMSSplit = (*config.KMSSplitConfiguration)(unsafe.Pointer(in.KMSSplit))
 	return nil
 }
 
@@ -248,6 +484,10 @@ func autoConvert_config_ProviderConfiguration_To_v1_ProviderConfiguration(in *co
 	out.Secretbox = (*SecretboxConfiguration)(unsafe.Pointer(in.Secretbox))
 	out.Identity = (*IdentityConfiguration)(unsafe.Pointer(in.Identity))
 	out.KMS = (*KMSConfiguration)(unsafe.Pointer(in.KMS))
+	out.TPM = (*TPMConfiguration)(unsafe.Pointer(in.TPM))
+	out.X25519 = (*X25519Configuration)(unsafe.Pointer(in.X25519))
+	out.Keyring = (*KeyringConfiguration)(unsafe.Pointer(in.Keyring))
+	out.KMSSplit = (*KMSSplitConfiguration)(unsafe.Pointer(in.KMSSplit))
 	return nil
 }
 
@@ -259,6 +499,7 @@ func Convert_config_ProviderConfiguration_To_v1_ProviderConfiguration(in *config
 func autoConvert_v1_ResourceConfiguration_To_config_ResourceConfiguration(in *ResourceConfiguration, out *config.ResourceConfiguration, s conversion.Scope) error {
 	out.Resources = *(*[]string)(unsafe.Pointer(&in.Resources))
 	out.Providers = *(*[]config.ProviderConfiguration)(unsafe.Pointer(&in.Providers))
+	out.Tier = in.Tier
 	return nil
 }
 
@@ -270,6 +511,7 @@ func Convert_v1_ResourceConfiguration_To_config_ResourceConfiguration(in *Resour
 func autoConvert_config_ResourceConfiguration_To_v1_ResourceConfiguration(in *config.ResourceConfiguration, out *ResourceConfiguration, s conversion.Scope) error {
 	out.Resources = *(*[]string)(unsafe.Pointer(&in.Resources))
 	out.Providers = *(*[]ProviderConfiguration)(unsafe.Pointer(&in.Providers))
+	out.Tier = in.Tier
 	return nil
 }
 
@@ -278,6 +520,28 @@ func Convert_config_ResourceConfiguration_To_v1_ResourceConfiguration(in *config
 	return autoConvert_config_ResourceConfiguration_To_v1_ResourceConfiguration(in, out, s)
 }
 
+func autoConvert_v1_RevocationConfiguration_To_config_RevocationConfiguration(in *RevocationConfiguration, out *config.RevocationConfiguration, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Provider = in.Provider
+	return nil
+}
+
+// Convert_v1_RevocationConfiguration_To_config_RevocationConfiguration is an autogenerated conversion function.
+func Convert_v1_RevocationConfiguration_To_config_RevocationConfiguration(in *RevocationConfiguration, out *config.RevocationConfiguration, s conversion.Scope) error {
+	return autoConvert_v1_RevocationConfiguration_To_config_RevocationConfiguration(in, out, s)
+}
+
+func autoConvert_config_RevocationConfiguration_To_v1_RevocationConfiguration(in *config.RevocationConfiguration, out *RevocationConfiguration, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Provider = in.Provider
+	return nil
+}
+
+// Convert_config_RevocationConfiguration_To_v1_RevocationConfiguration is an autogenerated conversion function.
+func Convert_config_RevocationConfiguration_To_v1_RevocationConfiguration(in *config.RevocationConfiguration, out *RevocationConfiguration, s conversion.Scope) error {
+	return autoConvert_config_RevocationConfiguration_To_v1_RevocationConfiguration(in, out, s)
+}
+
 func autoConvert_v1_SecretboxConfiguration_To_config_SecretboxConfiguration(in *SecretboxConfiguration, out *config.SecretboxConfiguration, s conversion.Scope) error {
 	out.Keys = *(*[]config.Key)(unsafe.Pointer(&in.Keys))
 	return nil
@@ -297,3 +561,53 @@ func autoConvert_config_SecretboxConfiguration_To_v1_SecretboxConfiguration(in *
 func Convert_config_SecretboxConfiguration_To_v1_SecretboxConfiguration(in *config.SecretboxConfiguration, out *SecretboxConfiguration, s conversion.Scope) error {
 	return autoConvert_config_SecretboxConfiguration_To_v1_SecretboxConfiguration(in, out, s)
 }
+
+func autoConvert_v1_TPMConfiguration_To_config_TPMConfiguration(in *TPMConfiguration, out *config.TPMConfiguration, s conversion.Scope) error {
+	out.Name = in.Name
+	out.SealedKeyFile = in.SealedKeyFile
+	out.DevicePath = in.DevicePath
+	out.PCRs = *(*[]int32)(unsafe.Pointer(&in.PCRs))
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_v1_TPMConfiguration_To_config_TPMConfiguration is an autogenerated conversion function.
+func Convert_v1_TPMConfiguration_To_config_TPMConfiguration(in *TPMConfiguration, out *config.TPMConfiguration, s conversion.Scope) error {
+	return autoConvert_v1_TPMConfiguration_To_config_TPMConfiguration(in, out, s)
+}
+
+func autoConvert_config_TPMConfiguration_To_v1_TPMConfiguration(in *config.TPMConfiguration, out *TPMConfiguration, s conversion.Scope) error {
+	out.Name = in.Name
+	out.SealedKeyFile = in.SealedKeyFile
+	out.DevicePath = in.DevicePath
+	out.PCRs = *(*[]int32)(unsafe.Pointer(&in.PCRs))
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_config_TPMConfiguration_To_v1_TPMConfiguration is an autogenerated conversion function.
+func Convert_config_TPMConfiguration_To_v1_TPMConfiguration(in *config.TPMConfiguration, out *TPMConfiguration, s conversion.Scope) error {
+	return autoConvert_config_TPMConfiguration_To_v1_TPMConfiguration(in, out, s)
+}
+
+func autoConvert_v1_X25519Configuration_To_config_X25519Configuration(in *X25519Configuration, out *config.X25519Configuration, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Recipients = *(*[]string)(unsafe.Pointer(&in.Recipients))
+	return nil
+}
+
+// Convert_v1_X25519Configuration_To_config_X25519Configuration is an autogenerated conversion function.
+func Convert_v1_X25519Configuration_To_config_X25519Configuration(in *X25519Configuration, out *config.X25519Configuration, s conversion.Scope) error {
+	return autoConvert_v1_X25519Configuration_To_config_X25519Configuration(in, out, s)
+}
+
+func autoConvert_config_X25519Configuration_To_v1_X25519Configuration(in *config.X25519Configuration, out *X25519Configuration, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Recipients = *(*[]string)(unsafe.Pointer(&in.Recipients))
+	return nil
+}
+
+// Convert_config_X25519Configuration_To_v1_X25519Configuration is an autogenerated conversion function.
+func Convert_config_X25519Configuration_To_v1_X25519Configuration(in *config.X25519Configuration, out *X25519Configuration, s conversion.Scope) error {
+	return autoConvert_config_X25519Configuration_To_v1_X25519Configuration(in, out, s)
+}
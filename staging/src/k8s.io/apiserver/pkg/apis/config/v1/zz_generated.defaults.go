@@ -41,6 +41,15 @@ func SetObjectDefaults_EncryptionConfiguration(in *EncryptionConfiguration) {
 			if b.KMS != nil {
 				SetDefaults_KMSConfiguration(b.KMS)
 			}
+			if b.TPM != nil {
+				SetDefaults_TPMConfiguration(b.TPM)
+			}
+			if b.Keyring != nil {
+				SetDefaults_KeyringConfiguration(b.Keyring)
+			}
+			if b.KMSSplit != nil {
+				SetDefaults_KMSSplitConfiguration(b.KMSSplit)
+			}
 		}
 	}
 }
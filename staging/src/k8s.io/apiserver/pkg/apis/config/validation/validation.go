@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -29,21 +30,41 @@ import (
 )
 
 const (
-	moreThanOneElementErr          = "more than one provider specified in a single element, should split into different list elements"
-	keyLenErrFmt                   = "secret is not of the expected length, got %d, expected one of %v"
-	unsupportedSchemeErrFmt        = "unsupported scheme %q for KMS provider, only unix is supported"
-	unsupportedKMSAPIVersionErrFmt = "unsupported apiVersion %s for KMS provider, only v1 and v2 are supported"
-	atLeastOneRequiredErrFmt       = "at least one %s is required"
-	invalidURLErrFmt               = "invalid endpoint for kms provider, error: parse %s: net/url: invalid control character in URL"
-	mandatoryFieldErrFmt           = "%s is a mandatory field for a %s"
-	base64EncodingErr              = "secrets must be base64 encoded"
-	zeroOrNegativeErrFmt           = "%s should be a positive value"
-	nonZeroErrFmt                  = "%s should be a positive value, or negative to disable"
-	encryptionConfigNilErr         = "EncryptionConfiguration can't be nil"
-	invalidKMSConfigNameErrFmt     = "invalid KMS provider name %s, must not contain ':'"
-	duplicateKMSConfigNameErrFmt   = "duplicate KMS provider name %s, names must be unique"
+	moreThanOneElementErr           = "more than one provider specified in a single element, should split into different list elements"
+	keyLenErrFmt                    = "secret is not of the expected length, got %d, expected one of %v"
+	unsupportedSchemeErrFmt         = "unsupported scheme %q for KMS provider, only unix is supported"
+	unsupportedKMSAPIVersionErrFmt  = "unsupported apiVersion %s for KMS provider, only v1, v2 and v3 are supported"
+	atLeastOneRequiredErrFmt        = "at least one %s is required"
+	invalidURLErrFmt                = "invalid endpoint for kms provider, error: parse %s: net/url: invalid control character in URL"
+	mandatoryFieldErrFmt            = "%s is a mandatory field for a %s"
+	base64EncodingErr               = "secrets must be base64 encoded"
+	zeroOrNegativeErrFmt            = "%s should be a positive value"
+	nonZeroErrFmt                   = "%s should be a positive value, or negative to disable"
+	encryptionConfigNilErr          = "EncryptionConfiguration can't be nil"
+	invalidKMSConfigNameErrFmt      = "invalid KMS provider name %s, must not contain ':'"
+	duplicateKMSConfigNameErrFmt    = "duplicate KMS provider name %s, names must be unique"
+	unsupportedTPMModeErrFmt        = "unsupported mode %q for TPM provider, only aesgcm and aescbc are supported"
+	invalidTPMPCRErrFmt             = "invalid PCR index %d for TPM provider, must be between 0 and 23"
+	invalidTPMConfigNameErrFmt      = "invalid TPM provider name %s, must not contain ':'"
+	invalidX25519ConfigNameErrFmt   = "invalid x25519 provider name %s, must not contain ':'"
+	invalidX25519RecipientErr       = "recipients must be base64 encoded 32 byte X25519 public keys"
+	unsupportedKeyringModeErrFmt    = "unsupported mode %q for keyring provider, only aesgcm and aescbc are supported"
+	invalidKMSSplitConfigNameErrFmt = "invalid kmssplit provider name %s, must not contain ':'"
+	sharedKMSSplitEndpointErr       = "primary and secondary endpoints of a kmssplit provider must differ"
+	secretAndSecretFileErr          = "secret and secretFile are mutually exclusive"
+	invalidCreatedAtErrFmt          = "createdAt must be a valid RFC 3339 timestamp: %v"
+	dekReuseRequiresV2ErrFmt        = "%s is only supported with apiVersion v2 or later"
+	dekCacheKeySourceErr            = "exactly one of keyFile and sealedKeyFile must be set"
+	dekCacheTPMOnlyErrFmt           = "%s may only be set together with sealedKeyFile"
+	unsupportedTierErrFmt           = "unsupported tier %q, only local is supported"
+	kmsInLocalTierErr               = "kms providers are not allowed in a rule with tier: local"
+	secretsInLocalTierErr           = "secrets may not be limited to local ciphers, remove tier: local from the rule that covers them"
 )
 
+// localTier restricts a rule to local cipher providers, keeping high-churn
+// resources from generating KMS traffic.
+const localTier = "local"
+
 var (
 	// See https://golang.org/pkg/crypto/aes/#NewCipher for details on supported key sizes for AES.
 	aesKeySizes = []int{16, 24, 32}
@@ -82,6 +103,8 @@ func ValidateEncryptionConfiguration(c *config.EncryptionConfiguration, reload b
 			allErrs = append(allErrs, field.Required(p, fmt.Sprintf(atLeastOneRequiredErrFmt, p)))
 		}
 
+		allErrs = append(allErrs, validateResourceTier(conf, root.Index(i).Child("tier"))...)
+
 		for j, provider := range conf.Providers {
 			path := p.Index(j)
 			allErrs = append(allErrs, validateSingleProvider(provider, path)...)
@@ -90,13 +113,104 @@ func ValidateEncryptionConfiguration(c *config.EncryptionConfiguration, reload b
 			case provider.KMS != nil:
 				allErrs = append(allErrs, validateKMSConfiguration(provider.KMS, path.Child("kms"), kmsProviderNames, reload)...)
 				kmsProviderNames.Insert(provider.KMS.Name)
+				kmsProviderNames.Insert(provider.KMS.Aliases...)
 			case provider.AESGCM != nil:
 				allErrs = append(allErrs, validateKeys(provider.AESGCM.Keys, path.Child("aesgcm").Child("keys"), aesKeySizes)...)
 			case provider.AESCBC != nil:
 				allErrs = append(allErrs, validateKeys(provider.AESCBC.Keys, path.Child("aescbc").Child("keys"), aesKeySizes)...)
 			case provider.Secretbox != nil:
 				allErrs = append(allErrs, validateKeys(provider.Secretbox.Keys, path.Child("secretbox").Child("keys"), secretBoxKeySizes)...)
+			case provider.TPM != nil:
+				allErrs = append(allErrs, validateTPMConfiguration(provider.TPM, path.Child("tpm"))...)
+			case provider.X25519 != nil:
+				allErrs = append(allErrs, validateX25519Configuration(provider.X25519, path.Child("x25519"))...)
+			case provider.Keyring != nil:
+				allErrs = append(allErrs, validateKeyringConfiguration(provider.Keyring, path.Child("keyring"))...)
+			case provider.KMSSplit != nil:
+				allErrs = append(allErrs, validateKMSSplitConfiguration(provider.KMSSplit, path.Child("kmssplit"), kmsProviderNames, reload)...)
+				kmsProviderNames.Insert(provider.KMSSplit.Name)
+			}
+		}
+	}
+
+	allErrs = append(allErrs, validateRevocations(c, field.NewPath("revocations"))...)
+
+	return allErrs
+}
+
+// validateRevocations checks that each revocation names exactly one key, that
+// revocations are not duplicated, and that no resource still writes through a
+// revoked provider.
+func validateRevocations(c *config.EncryptionConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	revokedProviders := sets.NewString()
+	seenKeyIDs := sets.NewString()
+	for i, revocation := range c.Revocations {
+		path := fieldPath.Index(i)
+		switch {
+		case len(revocation.KeyID) == 0 && len(revocation.Provider) == 0:
+			allErrs = append(allErrs, field.Required(path, "either keyID or provider is required"))
+		case len(revocation.KeyID) != 0 && len(revocation.Provider) != 0:
+			allErrs = append(allErrs, field.Invalid(path, revocation, "exactly one of keyID or provider may be set"))
+		case len(revocation.Provider) != 0:
+			if revokedProviders.Has(revocation.Provider) {
+				allErrs = append(allErrs, field.Duplicate(path.Child("provider"), revocation.Provider))
+			}
+			revokedProviders.Insert(revocation.Provider)
+		default:
+			if seenKeyIDs.Has(revocation.KeyID) {
+				allErrs = append(allErrs, field.Duplicate(path.Child("keyID"), revocation.KeyID))
 			}
+			seenKeyIDs.Insert(revocation.KeyID)
+		}
+	}
+
+	// a revoked provider may stay configured so its records fail distinctly,
+	// but nothing may still be written through it.
+	for i, conf := range c.Resources {
+		if len(conf.Providers) == 0 {
+			continue
+		}
+		writeProvider := conf.Providers[0]
+		var writeName string
+		switch {
+		case writeProvider.KMS != nil:
+			writeName = writeProvider.KMS.Name
+		case writeProvider.KMSSplit != nil:
+			writeName = writeProvider.KMSSplit.Name
+		}
+		if len(writeName) != 0 && revokedProviders.Has(writeName) {
+			allErrs = append(allErrs, field.Invalid(root.Index(i).Child("providers").Index(0), writeName, "provider is revoked and may not be used to write new data"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateResourceTier(conf config.ResourceConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch conf.Tier {
+	case "":
+		return allErrs
+	case localTier:
+	default:
+		return append(allErrs, field.Invalid(fieldPath, conf.Tier, fmt.Sprintf(unsupportedTierErrFmt, conf.Tier)))
+	}
+
+	// a local tier rule covering secrets would silently downgrade them from
+	// KMS to local ciphers, so it is rejected outright.
+	for _, resource := range conf.Resources {
+		if resource == "secrets" {
+			allErrs = append(allErrs, field.Invalid(fieldPath, conf.Tier, secretsInLocalTierErr))
+			break
+		}
+	}
+
+	for i, provider := range conf.Providers {
+		if provider.KMS != nil || provider.KMSSplit != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath, conf.Tier, fmt.Sprintf("%s (provider %d)", kmsInLocalTierErr, i)))
 		}
 	}
 
@@ -122,9 +236,21 @@ func validateSingleProvider(provider config.ProviderConfiguration, fieldPath *fi
 	if provider.Identity != nil {
 		found++
 	}
+	if provider.TPM != nil {
+		found++
+	}
+	if provider.X25519 != nil {
+		found++
+	}
+	if provider.Keyring != nil {
+		found++
+	}
+	if provider.KMSSplit != nil {
+		found++
+	}
 
 	if found == 0 {
-		return append(allErrs, field.Invalid(fieldPath, provider, "provider does not contain any of the expected providers: KMS, AESGCM, AESCBC, Secretbox, Identity"))
+		return append(allErrs, field.Invalid(fieldPath, provider, "provider does not contain any of the expected providers: KMS, AESGCM, AESCBC, Secretbox, Identity, TPM, X25519, Keyring, KMSSplit"))
 	}
 
 	if found > 1 {
@@ -152,10 +278,22 @@ func validateKeys(keys []config.Key, fieldPath *field.Path, expectedLen []int) f
 func validateKey(key config.Key, fieldPath *field.Path, expectedLen []int) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	allErrs = append(allErrs, validateProvenance(key.Provenance, fieldPath.Child("provenance"))...)
+
 	if key.Name == "" {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), fmt.Sprintf(mandatoryFieldErrFmt, "name", "key")))
 	}
 
+	if key.Secret != "" && key.SecretFile != "" {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("secretFile"), key.SecretFile, secretAndSecretFileErr))
+		return allErrs
+	}
+
+	if key.SecretFile != "" {
+		// the file contents are read and checked when the transformer is built.
+		return allErrs
+	}
+
 	if key.Secret == "" {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("secret"), fmt.Sprintf(mandatoryFieldErrFmt, "secret", "key")))
 		return allErrs
@@ -186,10 +324,30 @@ func validateKMSConfiguration(c *config.KMSConfiguration, fieldPath *field.Path,
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateKMSConfigName(c, fieldPath.Child("name"), kmsProviderNames, reload)...)
+	allErrs = append(allErrs, validateKMSAliases(c, fieldPath.Child("aliases"), kmsProviderNames, reload)...)
 	allErrs = append(allErrs, validateKMSTimeout(c, fieldPath.Child("timeout"))...)
 	allErrs = append(allErrs, validateKMSEndpoint(c, fieldPath.Child("endpoint"))...)
 	allErrs = append(allErrs, validateKMSCacheSize(c, fieldPath.Child("cachesize"))...)
+	allErrs = append(allErrs, validateKMSMaxInFlight(c, fieldPath.Child("maxInFlight"))...)
+	allErrs = append(allErrs, validateKMSDEKReuse(c, fieldPath)...)
+	allErrs = append(allErrs, validateKMSDEKCache(c, fieldPath.Child("dekCache"))...)
 	allErrs = append(allErrs, validateKMSAPIVersion(c, fieldPath.Child("apiVersion"))...)
+	allErrs = append(allErrs, validateProvenance(c.Provenance, fieldPath.Child("provenance"))...)
+	return allErrs
+}
+
+func validateProvenance(p *config.ProvenanceConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p == nil {
+		return allErrs
+	}
+
+	if p.CreatedAt != "" {
+		if _, err := time.Parse(time.RFC3339, p.CreatedAt); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("createdAt"), p.CreatedAt, fmt.Sprintf(invalidCreatedAtErrFmt, err)))
+		}
+	}
+
 	return allErrs
 }
 
@@ -202,6 +360,70 @@ func validateKMSCacheSize(c *config.KMSConfiguration, fieldPath *field.Path) fie
 	return allErrs
 }
 
+func validateKMSMaxInFlight(c *config.KMSConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.MaxInFlight != nil && *c.MaxInFlight <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, *c.MaxInFlight, fmt.Sprintf(zeroOrNegativeErrFmt, "maxInFlight")))
+	}
+
+	return allErrs
+}
+
+func validateKMSDEKReuse(c *config.KMSConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.DEKReuseMaxWrites != nil {
+		if *c.DEKReuseMaxWrites <= 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("dekReuseMaxWrites"), *c.DEKReuseMaxWrites, fmt.Sprintf(zeroOrNegativeErrFmt, "dekReuseMaxWrites")))
+		}
+		if c.APIVersion == "v1" {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("dekReuseMaxWrites"), *c.DEKReuseMaxWrites, fmt.Sprintf(dekReuseRequiresV2ErrFmt, "dekReuseMaxWrites")))
+		}
+	}
+	if c.DEKReuseDuration != nil {
+		if c.DEKReuseDuration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("dekReuseDuration"), c.DEKReuseDuration, fmt.Sprintf(zeroOrNegativeErrFmt, "dekReuseDuration")))
+		}
+		if c.APIVersion == "v1" {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("dekReuseDuration"), c.DEKReuseDuration, fmt.Sprintf(dekReuseRequiresV2ErrFmt, "dekReuseDuration")))
+		}
+	}
+
+	return allErrs
+}
+
+func validateKMSDEKCache(c *config.KMSConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	cache := c.DEKCache
+	if cache == nil {
+		return allErrs
+	}
+
+	if c.APIVersion == "v1" {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", fmt.Sprintf(dekReuseRequiresV2ErrFmt, "dekCache")))
+	}
+	if len(cache.File) == 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("file"), "", fmt.Sprintf(mandatoryFieldErrFmt, "file", "dekCache")))
+	}
+	if (len(cache.KeyFile) == 0) == (len(cache.SealedKeyFile) == 0) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", dekCacheKeySourceErr))
+	}
+	if len(cache.SealedKeyFile) == 0 {
+		if len(cache.DevicePath) != 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("devicePath"), cache.DevicePath, fmt.Sprintf(dekCacheTPMOnlyErrFmt, "devicePath")))
+		}
+		if len(cache.PCRs) != 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("pcrs"), cache.PCRs, fmt.Sprintf(dekCacheTPMOnlyErrFmt, "pcrs")))
+		}
+	}
+	for _, pcr := range cache.PCRs {
+		if pcr < 0 || pcr > 23 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("pcrs"), pcr, fmt.Sprintf(invalidTPMPCRErrFmt, pcr)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateKMSTimeout(c *config.KMSConfiguration, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if c.Timeout.Duration <= 0 {
@@ -231,13 +453,170 @@ func validateKMSEndpoint(c *config.KMSConfiguration, fieldPath *field.Path) fiel
 
 func validateKMSAPIVersion(c *config.KMSConfiguration, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
-	if c.APIVersion != "v1" && c.APIVersion != "v2" {
+	if c.APIVersion != "v1" && c.APIVersion != "v2" && c.APIVersion != "v3" {
 		allErrs = append(allErrs, field.Invalid(fieldPath, c.APIVersion, fmt.Sprintf(unsupportedKMSAPIVersionErrFmt, "apiVersion")))
 	}
 
 	return allErrs
 }
 
+func validateKMSSplitConfiguration(c *config.KMSSplitConfiguration, fieldPath *field.Path, kmsProviderNames sets.String, reload bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), fmt.Sprintf(mandatoryFieldErrFmt, "name", "provider")))
+	}
+	if strings.Contains(c.Name, ":") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), c.Name, fmt.Sprintf(invalidKMSSplitConfigNameErrFmt, c.Name)))
+	}
+	// kmssplit providers share the prefix namespace with kms providers, so
+	// their names must always be unique across both.
+	if kmsProviderNames.Has(c.Name) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), c.Name, fmt.Sprintf(duplicateKMSConfigNameErrFmt, c.Name)))
+	}
+
+	if *c.CacheSize == 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cachesize"), *c.CacheSize, fmt.Sprintf(nonZeroErrFmt, "cachesize")))
+	}
+
+	allErrs = append(allErrs, validateKMSSplitEndpoint(&c.Primary, fieldPath.Child("primary"))...)
+	allErrs = append(allErrs, validateKMSSplitEndpoint(&c.Secondary, fieldPath.Child("secondary"))...)
+
+	// dual control needs two independent providers; the same endpoint twice
+	// would collapse the scheme back to a single KMS account.
+	if len(c.Primary.Endpoint) != 0 && c.Primary.Endpoint == c.Secondary.Endpoint {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("secondary").Child("endpoint"), c.Secondary.Endpoint, sharedKMSSplitEndpointErr))
+	}
+
+	return allErrs
+}
+
+func validateKMSSplitEndpoint(e *config.KMSSplitEndpoint, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if e.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("timeout"), e.Timeout, fmt.Sprintf(zeroOrNegativeErrFmt, "timeout")))
+	}
+
+	if len(e.Endpoint) == 0 {
+		return append(allErrs, field.Invalid(fieldPath.Child("endpoint"), "", fmt.Sprintf(mandatoryFieldErrFmt, "endpoint", "kmssplit")))
+	}
+	u, err := url.Parse(e.Endpoint)
+	if err != nil {
+		return append(allErrs, field.Invalid(fieldPath.Child("endpoint"), e.Endpoint, fmt.Sprintf("invalid endpoint for kmssplit provider, error: %v", err)))
+	}
+	if u.Scheme != "unix" {
+		return append(allErrs, field.Invalid(fieldPath.Child("endpoint"), e.Endpoint, fmt.Sprintf(unsupportedSchemeErrFmt, u.Scheme)))
+	}
+
+	return allErrs
+}
+
+func validateTPMConfiguration(c *config.TPMConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), fmt.Sprintf(mandatoryFieldErrFmt, "name", "provider")))
+	}
+	if strings.Contains(c.Name, ":") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), c.Name, fmt.Sprintf(invalidTPMConfigNameErrFmt, c.Name)))
+	}
+
+	if c.SealedKeyFile == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("sealedKeyFile"), fmt.Sprintf(mandatoryFieldErrFmt, "sealedKeyFile", "tpm")))
+	}
+
+	seenPCRs := sets.NewInt()
+	for i, pcr := range c.PCRs {
+		// TPM 2.0 mandates at least 24 PCRs per bank
+		if pcr < 0 || pcr > 23 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("pcrs").Index(i), pcr, fmt.Sprintf(invalidTPMPCRErrFmt, pcr)))
+			continue
+		}
+		if seenPCRs.Has(int(pcr)) {
+			allErrs = append(allErrs, field.Duplicate(fieldPath.Child("pcrs").Index(i), pcr))
+		}
+		seenPCRs.Insert(int(pcr))
+	}
+
+	if c.Mode != "" && c.Mode != "aesgcm" && c.Mode != "aescbc" {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("mode"), c.Mode, fmt.Sprintf(unsupportedTPMModeErrFmt, c.Mode)))
+	}
+
+	return allErrs
+}
+
+func validateX25519Configuration(c *config.X25519Configuration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), fmt.Sprintf(mandatoryFieldErrFmt, "name", "provider")))
+	}
+	if strings.Contains(c.Name, ":") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), c.Name, fmt.Sprintf(invalidX25519ConfigNameErrFmt, c.Name)))
+	}
+
+	if len(c.Recipients) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("recipients"), fmt.Sprintf(atLeastOneRequiredErrFmt, "recipients")))
+	}
+
+	seenRecipients := sets.NewString()
+	for i, recipient := range c.Recipients {
+		key, err := base64.StdEncoding.DecodeString(recipient)
+		if err != nil || len(key) != 32 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("recipients").Index(i), "REDACTED", invalidX25519RecipientErr))
+			continue
+		}
+		if seenRecipients.Has(recipient) {
+			allErrs = append(allErrs, field.Duplicate(fieldPath.Child("recipients").Index(i), "REDACTED"))
+		}
+		seenRecipients.Insert(recipient)
+	}
+
+	return allErrs
+}
+
+func validateKeyringConfiguration(c *config.KeyringConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.Path == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("path"), fmt.Sprintf(mandatoryFieldErrFmt, "path", "provider")))
+	}
+
+	if c.Mode != "" && c.Mode != "aesgcm" && c.Mode != "aescbc" {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("mode"), c.Mode, fmt.Sprintf(unsupportedKeyringModeErrFmt, c.Mode)))
+	}
+
+	return allErrs
+}
+
+func validateKMSAliases(c *config.KMSConfiguration, fieldPath *field.Path, kmsProviderNames sets.String, reload bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.NewString(c.Name)
+	for i, alias := range c.Aliases {
+		path := fieldPath.Index(i)
+		if alias == "" {
+			allErrs = append(allErrs, field.Required(path, fmt.Sprintf(mandatoryFieldErrFmt, "aliases", "provider")))
+			continue
+		}
+
+		// aliases become part of the same stored data prefix as names, so the
+		// kms v2 ":" restriction applies to them as well
+		if c.APIVersion != "v1" && strings.Contains(alias, ":") {
+			allErrs = append(allErrs, field.Invalid(path, alias, fmt.Sprintf(invalidKMSConfigNameErrFmt, alias)))
+		}
+
+		// an alias must not collide with this provider's name or other aliases,
+		// nor (under the same conditions as names) with any other kms provider
+		if seen.Has(alias) || ((reload || c.APIVersion != "v1") && kmsProviderNames.Has(alias)) {
+			allErrs = append(allErrs, field.Invalid(path, alias, fmt.Sprintf(duplicateKMSConfigNameErrFmt, alias)))
+		}
+		seen.Insert(alias)
+	}
+
+	return allErrs
+}
+
 func validateKMSConfigName(c *config.KMSConfiguration, fieldPath *field.Path, kmsProviderNames sets.String, reload bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if c.Name == "" {
@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/base64"
 	"fmt"
 	"testing"
 	"time"
@@ -414,6 +415,38 @@ func TestKey(t *testing.T) {
 				field.Required(path.Child("secret"), fmt.Sprintf(mandatoryFieldErrFmt, "secret", "key")),
 			},
 		},
+		{
+			desc: "key with secret file",
+			in:   config.Key{Name: "foo", SecretFile: "/etc/kubernetes/keys/foo"},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "key with both secret and secret file",
+			in:   config.Key{Name: "foo", Secret: "c2VjcmV0IGlzIHNlY3VyZQ==", SecretFile: "/etc/kubernetes/keys/foo"},
+			want: field.ErrorList{
+				field.Invalid(path.Child("secretFile"), "/etc/kubernetes/keys/foo", secretAndSecretFileErr),
+			},
+		},
+		{
+			desc: "key with valid provenance",
+			in: config.Key{
+				Name:       "foo",
+				Secret:     "c2VjcmV0IGlzIHNlY3VyZQ==",
+				Provenance: &config.ProvenanceConfiguration{CreatedAt: "2023-01-02T15:04:05Z", Owner: "storage-team", Ticket: "KEY-123"},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "key with invalid provenance createdAt",
+			in: config.Key{
+				Name:       "foo",
+				Secret:     "c2VjcmV0IGlzIHNlY3VyZQ==",
+				Provenance: &config.ProvenanceConfiguration{CreatedAt: "yesterday"},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("provenance").Child("createdAt"), "yesterday", fmt.Sprintf(invalidCreatedAtErrFmt, mustParseErr("yesterday"))),
+			},
+		},
 		{
 			desc: "key is not base64 encoded",
 			in:   config.Key{Name: "foo", Secret: "P@ssword"},
@@ -440,6 +473,13 @@ func TestKey(t *testing.T) {
 	}
 }
 
+// mustParseErr returns the parse error time.Parse reports for an invalid
+// RFC 3339 timestamp, so expected validation messages match exactly.
+func mustParseErr(value string) error {
+	_, err := time.Parse(time.RFC3339, value)
+	return err
+}
+
 func TestKMSProviderTimeout(t *testing.T) {
 	timeoutField := field.NewPath("Resource").Index(0).Child("Provider").Index(0).Child("KMS").Child("Timeout")
 	negativeTimeout := &metav1.Duration{Duration: -1 * time.Minute}
@@ -566,6 +606,187 @@ func TestKMSProviderCacheSize(t *testing.T) {
 	}
 }
 
+func TestKMSProviderMaxInFlight(t *testing.T) {
+	maxInFlightField := root.Index(0).Child("kms").Child("maxInFlight")
+	negativeMaxInFlight := int32(-1)
+	positiveMaxInFlight := int32(4)
+	zeroMaxInFlight := int32(0)
+
+	testCases := []struct {
+		desc string
+		in   *config.KMSConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "valid unset max in flight",
+			in:   &config.KMSConfiguration{},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "valid positive max in flight",
+			in:   &config.KMSConfiguration{MaxInFlight: &positiveMaxInFlight},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "invalid zero max in flight",
+			in:   &config.KMSConfiguration{MaxInFlight: &zeroMaxInFlight},
+			want: field.ErrorList{
+				field.Invalid(maxInFlightField, int32(0), fmt.Sprintf(zeroOrNegativeErrFmt, "maxInFlight")),
+			},
+		},
+		{
+			desc: "invalid negative max in flight",
+			in:   &config.KMSConfiguration{MaxInFlight: &negativeMaxInFlight},
+			want: field.ErrorList{
+				field.Invalid(maxInFlightField, int32(-1), fmt.Sprintf(zeroOrNegativeErrFmt, "maxInFlight")),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateKMSMaxInFlight(tt.in, maxInFlightField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("KMS Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestKMSProviderDEKReuse(t *testing.T) {
+	kmsField := root.Index(0).Child("kms")
+	negativeMaxWrites := int32(-1)
+	positiveMaxWrites := int32(1000)
+	negativeDuration := metav1.Duration{Duration: -time.Minute}
+	positiveDuration := metav1.Duration{Duration: time.Minute}
+
+	testCases := []struct {
+		desc string
+		in   *config.KMSConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "valid unset reuse fields",
+			in:   &config.KMSConfiguration{APIVersion: "v2"},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "valid positive reuse fields with v2",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKReuseMaxWrites: &positiveMaxWrites, DEKReuseDuration: &positiveDuration},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "invalid negative max writes",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKReuseMaxWrites: &negativeMaxWrites},
+			want: field.ErrorList{
+				field.Invalid(kmsField.Child("dekReuseMaxWrites"), int32(-1), fmt.Sprintf(zeroOrNegativeErrFmt, "dekReuseMaxWrites")),
+			},
+		},
+		{
+			desc: "invalid negative duration",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKReuseDuration: &negativeDuration},
+			want: field.ErrorList{
+				field.Invalid(kmsField.Child("dekReuseDuration"), &negativeDuration, fmt.Sprintf(zeroOrNegativeErrFmt, "dekReuseDuration")),
+			},
+		},
+		{
+			desc: "invalid reuse fields with v1",
+			in:   &config.KMSConfiguration{APIVersion: "v1", DEKReuseMaxWrites: &positiveMaxWrites, DEKReuseDuration: &positiveDuration},
+			want: field.ErrorList{
+				field.Invalid(kmsField.Child("dekReuseMaxWrites"), int32(1000), fmt.Sprintf(dekReuseRequiresV2ErrFmt, "dekReuseMaxWrites")),
+				field.Invalid(kmsField.Child("dekReuseDuration"), &positiveDuration, fmt.Sprintf(dekReuseRequiresV2ErrFmt, "dekReuseDuration")),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateKMSDEKReuse(tt.in, kmsField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("KMS Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestKMSProviderDEKCache(t *testing.T) {
+	cacheField := root.Index(0).Child("kms").Child("dekCache")
+
+	testCases := []struct {
+		desc string
+		in   *config.KMSConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "valid unset dekCache",
+			in:   &config.KMSConfiguration{APIVersion: "v2"},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "valid dekCache with key file",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache", KeyFile: "/run/kms/dek-cache-key"}},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "valid dekCache with sealed key",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache", SealedKeyFile: "/etc/kms/sealed-key", DevicePath: "/dev/tpmrm0", PCRs: []int32{7}}},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "invalid dekCache with v1",
+			in:   &config.KMSConfiguration{APIVersion: "v1", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache", KeyFile: "/run/kms/dek-cache-key"}},
+			want: field.ErrorList{
+				field.Invalid(cacheField, "", fmt.Sprintf(dekReuseRequiresV2ErrFmt, "dekCache")),
+			},
+		},
+		{
+			desc: "invalid dekCache without file",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{KeyFile: "/run/kms/dek-cache-key"}},
+			want: field.ErrorList{
+				field.Invalid(cacheField.Child("file"), "", fmt.Sprintf(mandatoryFieldErrFmt, "file", "dekCache")),
+			},
+		},
+		{
+			desc: "invalid dekCache without key source",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache"}},
+			want: field.ErrorList{
+				field.Invalid(cacheField, "", dekCacheKeySourceErr),
+			},
+		},
+		{
+			desc: "invalid dekCache with both key sources",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache", KeyFile: "/run/kms/dek-cache-key", SealedKeyFile: "/etc/kms/sealed-key"}},
+			want: field.ErrorList{
+				field.Invalid(cacheField, "", dekCacheKeySourceErr),
+			},
+		},
+		{
+			desc: "invalid TPM fields without sealed key",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache", KeyFile: "/run/kms/dek-cache-key", DevicePath: "/dev/tpmrm0", PCRs: []int32{7}}},
+			want: field.ErrorList{
+				field.Invalid(cacheField.Child("devicePath"), "/dev/tpmrm0", fmt.Sprintf(dekCacheTPMOnlyErrFmt, "devicePath")),
+				field.Invalid(cacheField.Child("pcrs"), []int32{7}, fmt.Sprintf(dekCacheTPMOnlyErrFmt, "pcrs")),
+			},
+		},
+		{
+			desc: "invalid PCR index",
+			in:   &config.KMSConfiguration{APIVersion: "v2", DEKCache: &config.DEKCacheConfiguration{File: "/run/kms/dek-cache", SealedKeyFile: "/etc/kms/sealed-key", PCRs: []int32{24}}},
+			want: field.ErrorList{
+				field.Invalid(cacheField.Child("pcrs"), int32(24), fmt.Sprintf(invalidTPMPCRErrFmt, 24)),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateKMSDEKCache(tt.in, cacheField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("KMS Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
 func TestKMSProviderAPIVersion(t *testing.T) {
 	apiVersionField := field.NewPath("Resource").Index(0).Child("Provider").Index(0).Child("KMS").Child("APIVersion")
 
@@ -585,10 +806,15 @@ func TestKMSProviderAPIVersion(t *testing.T) {
 			want: field.ErrorList{},
 		},
 		{
-			desc: "invalid api version",
+			desc: "valid v3 api version",
 			in:   &config.KMSConfiguration{APIVersion: "v3"},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "invalid api version",
+			in:   &config.KMSConfiguration{APIVersion: "v4"},
 			want: field.ErrorList{
-				field.Invalid(apiVersionField, "v3", fmt.Sprintf(unsupportedKMSAPIVersionErrFmt, "apiVersion")),
+				field.Invalid(apiVersionField, "v4", fmt.Sprintf(unsupportedKMSAPIVersionErrFmt, "apiVersion")),
 			},
 		},
 	}
@@ -680,3 +906,436 @@ func TestKMSProviderName(t *testing.T) {
 		})
 	}
 }
+
+func TestKMSProviderAliases(t *testing.T) {
+	aliasesField := field.NewPath("Resource").Index(0).Child("Provider").Index(0).Child("KMS").Child("aliases")
+
+	testCases := []struct {
+		desc             string
+		in               *config.KMSConfiguration
+		reload           bool
+		kmsProviderNames sets.String
+		want             field.ErrorList
+	}{
+		{
+			desc: "valid aliases",
+			in:   &config.KMSConfiguration{Name: "new-name", Aliases: []string{"old-name", "older-name"}},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "empty alias",
+			in:   &config.KMSConfiguration{Name: "foo", Aliases: []string{""}},
+			want: field.ErrorList{
+				field.Required(aliasesField.Index(0), fmt.Sprintf(mandatoryFieldErrFmt, "aliases", "provider")),
+			},
+		},
+		{
+			desc: "alias with :, kms v2",
+			in:   &config.KMSConfiguration{APIVersion: "v2", Name: "foo", Aliases: []string{"foo:bar"}},
+			want: field.ErrorList{
+				field.Invalid(aliasesField.Index(0), "foo:bar", fmt.Sprintf(invalidKMSConfigNameErrFmt, "foo:bar")),
+			},
+		},
+		{
+			desc: "alias with :, kms v1",
+			in:   &config.KMSConfiguration{APIVersion: "v1", Name: "foo", Aliases: []string{"foo:bar"}},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "alias equal to provider name",
+			in:   &config.KMSConfiguration{Name: "foo", Aliases: []string{"foo"}},
+			want: field.ErrorList{
+				field.Invalid(aliasesField.Index(0), "foo", fmt.Sprintf(duplicateKMSConfigNameErrFmt, "foo")),
+			},
+		},
+		{
+			desc: "alias repeated in list",
+			in:   &config.KMSConfiguration{Name: "foo", Aliases: []string{"bar", "bar"}},
+			want: field.ErrorList{
+				field.Invalid(aliasesField.Index(1), "bar", fmt.Sprintf(duplicateKMSConfigNameErrFmt, "bar")),
+			},
+		},
+		{
+			desc:             "alias collides with other provider, kms v2",
+			in:               &config.KMSConfiguration{APIVersion: "v2", Name: "foo", Aliases: []string{"bar"}},
+			kmsProviderNames: sets.NewString("bar"),
+			want: field.ErrorList{
+				field.Invalid(aliasesField.Index(0), "bar", fmt.Sprintf(duplicateKMSConfigNameErrFmt, "bar")),
+			},
+		},
+		{
+			desc:             "alias collides with other provider, kms v1, reload=false",
+			in:               &config.KMSConfiguration{APIVersion: "v1", Name: "foo", Aliases: []string{"bar"}},
+			kmsProviderNames: sets.NewString("bar"),
+			want:             field.ErrorList{},
+		},
+		{
+			desc:             "alias collides with other provider, kms v1, reload=true",
+			in:               &config.KMSConfiguration{APIVersion: "v1", Name: "foo", Aliases: []string{"bar"}},
+			reload:           true,
+			kmsProviderNames: sets.NewString("bar"),
+			want: field.ErrorList{
+				field.Invalid(aliasesField.Index(0), "bar", fmt.Sprintf(duplicateKMSConfigNameErrFmt, "bar")),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateKMSAliases(tt.in, aliasesField, tt.kmsProviderNames, tt.reload)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("KMS Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestResourceTier(t *testing.T) {
+	tierField := field.NewPath("Resource").Index(0).Child("tier")
+	aescbcProvider := config.ProviderConfiguration{
+		AESCBC: &config.AESConfiguration{
+			Keys: []config.Key{{Name: "foo", Secret: "A"}},
+		},
+	}
+	kmsProvider := config.ProviderConfiguration{
+		KMS: &config.KMSConfiguration{Name: "foo", Endpoint: "unix:///tmp/kms.sock"},
+	}
+
+	testCases := []struct {
+		desc string
+		in   config.ResourceConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "no tier",
+			in: config.ResourceConfiguration{
+				Resources: []string{"secrets"},
+				Providers: []config.ProviderConfiguration{kmsProvider},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "local tier with local providers",
+			in: config.ResourceConfiguration{
+				Resources: []string{"events"},
+				Providers: []config.ProviderConfiguration{aescbcProvider},
+				Tier:      "local",
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "unsupported tier",
+			in: config.ResourceConfiguration{
+				Resources: []string{"events"},
+				Providers: []config.ProviderConfiguration{aescbcProvider},
+				Tier:      "remote",
+			},
+			want: field.ErrorList{
+				field.Invalid(tierField, "remote", fmt.Sprintf(unsupportedTierErrFmt, "remote")),
+			},
+		},
+		{
+			desc: "local tier with kms provider",
+			in: config.ResourceConfiguration{
+				Resources: []string{"events"},
+				Providers: []config.ProviderConfiguration{aescbcProvider, kmsProvider},
+				Tier:      "local",
+			},
+			want: field.ErrorList{
+				field.Invalid(tierField, "local", fmt.Sprintf("%s (provider %d)", kmsInLocalTierErr, 1)),
+			},
+		},
+		{
+			desc: "local tier covering secrets",
+			in: config.ResourceConfiguration{
+				Resources: []string{"events", "secrets"},
+				Providers: []config.ProviderConfiguration{aescbcProvider},
+				Tier:      "local",
+			},
+			want: field.ErrorList{
+				field.Invalid(tierField, "local", secretsInLocalTierErr),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateResourceTier(tt.in, tierField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("Resource tier validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestTPMProvider(t *testing.T) {
+	tpmField := field.NewPath("Resource").Index(0).Child("Provider").Index(0).Child("TPM")
+
+	testCases := []struct {
+		desc string
+		in   *config.TPMConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "valid configuration",
+			in: &config.TPMConfiguration{
+				Name:          "foo",
+				SealedKeyFile: "/etc/kubernetes/sealed.key",
+				DevicePath:    "/dev/tpmrm0",
+				PCRs:          []int32{0, 7},
+				Mode:          "aesgcm",
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "missing name and sealedKeyFile",
+			in:   &config.TPMConfiguration{},
+			want: field.ErrorList{
+				field.Required(tpmField.Child("name"), fmt.Sprintf(mandatoryFieldErrFmt, "name", "provider")),
+				field.Required(tpmField.Child("sealedKeyFile"), fmt.Sprintf(mandatoryFieldErrFmt, "sealedKeyFile", "tpm")),
+			},
+		},
+		{
+			desc: "name with colon",
+			in: &config.TPMConfiguration{
+				Name:          "foo:bar",
+				SealedKeyFile: "/etc/kubernetes/sealed.key",
+			},
+			want: field.ErrorList{
+				field.Invalid(tpmField.Child("name"), "foo:bar", fmt.Sprintf(invalidTPMConfigNameErrFmt, "foo:bar")),
+			},
+		},
+		{
+			desc: "out of range and duplicate pcrs",
+			in: &config.TPMConfiguration{
+				Name:          "foo",
+				SealedKeyFile: "/etc/kubernetes/sealed.key",
+				PCRs:          []int32{24, 7, 7},
+			},
+			want: field.ErrorList{
+				field.Invalid(tpmField.Child("pcrs").Index(0), int32(24), fmt.Sprintf(invalidTPMPCRErrFmt, 24)),
+				field.Duplicate(tpmField.Child("pcrs").Index(2), int32(7)),
+			},
+		},
+		{
+			desc: "unsupported mode",
+			in: &config.TPMConfiguration{
+				Name:          "foo",
+				SealedKeyFile: "/etc/kubernetes/sealed.key",
+				Mode:          "chacha20",
+			},
+			want: field.ErrorList{
+				field.Invalid(tpmField.Child("mode"), "chacha20", fmt.Sprintf(unsupportedTPMModeErrFmt, "chacha20")),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateTPMConfiguration(tt.in, tpmField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("TPM Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestX25519Provider(t *testing.T) {
+	x25519Field := field.NewPath("Resource").Index(0).Child("Provider").Index(0).Child("X25519")
+	validRecipient := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	testCases := []struct {
+		desc string
+		in   *config.X25519Configuration
+		want field.ErrorList
+	}{
+		{
+			desc: "valid configuration",
+			in: &config.X25519Configuration{
+				Name:       "foo",
+				Recipients: []string{validRecipient},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "missing name and recipients",
+			in:   &config.X25519Configuration{},
+			want: field.ErrorList{
+				field.Required(x25519Field.Child("name"), fmt.Sprintf(mandatoryFieldErrFmt, "name", "provider")),
+				field.Required(x25519Field.Child("recipients"), fmt.Sprintf(atLeastOneRequiredErrFmt, "recipients")),
+			},
+		},
+		{
+			desc: "name with colon",
+			in: &config.X25519Configuration{
+				Name:       "foo:bar",
+				Recipients: []string{validRecipient},
+			},
+			want: field.ErrorList{
+				field.Invalid(x25519Field.Child("name"), "foo:bar", fmt.Sprintf(invalidX25519ConfigNameErrFmt, "foo:bar")),
+			},
+		},
+		{
+			desc: "invalid and duplicate recipients",
+			in: &config.X25519Configuration{
+				Name:       "foo",
+				Recipients: []string{"not base64", base64.StdEncoding.EncodeToString([]byte("short")), validRecipient, validRecipient},
+			},
+			want: field.ErrorList{
+				field.Invalid(x25519Field.Child("recipients").Index(0), "REDACTED", invalidX25519RecipientErr),
+				field.Invalid(x25519Field.Child("recipients").Index(1), "REDACTED", invalidX25519RecipientErr),
+				field.Duplicate(x25519Field.Child("recipients").Index(3), "REDACTED"),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateX25519Configuration(tt.in, x25519Field)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("x25519 Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestKeyringProvider(t *testing.T) {
+	keyringField := field.NewPath("Resource").Index(0).Child("Provider").Index(0).Child("Keyring")
+
+	testCases := []struct {
+		desc string
+		in   *config.KeyringConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "valid configuration",
+			in: &config.KeyringConfiguration{
+				Path: "/etc/kubernetes/keyring.yaml",
+				Mode: "aesgcm",
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "missing path",
+			in:   &config.KeyringConfiguration{},
+			want: field.ErrorList{
+				field.Required(keyringField.Child("path"), fmt.Sprintf(mandatoryFieldErrFmt, "path", "provider")),
+			},
+		},
+		{
+			desc: "unsupported mode",
+			in: &config.KeyringConfiguration{
+				Path: "/etc/kubernetes/keyring.yaml",
+				Mode: "aesctr",
+			},
+			want: field.ErrorList{
+				field.Invalid(keyringField.Child("mode"), "aesctr", fmt.Sprintf(unsupportedKeyringModeErrFmt, "aesctr")),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateKeyringConfiguration(tt.in, keyringField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("keyring Provider validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRevocations(t *testing.T) {
+	revocationsField := field.NewPath("revocations")
+	kmsProvider := config.ProviderConfiguration{
+		KMS: &config.KMSConfiguration{Name: "foo", Endpoint: "unix:///tmp/kms.sock"},
+	}
+	aescbcProvider := config.ProviderConfiguration{
+		AESCBC: &config.AESConfiguration{
+			Keys: []config.Key{{Name: "key-1", Secret: "A"}},
+		},
+	}
+
+	testCases := []struct {
+		desc string
+		in   *config.EncryptionConfiguration
+		want field.ErrorList
+	}{
+		{
+			desc: "revoked key id",
+			in: &config.EncryptionConfiguration{
+				Resources: []config.ResourceConfiguration{
+					{Resources: []string{"secrets"}, Providers: []config.ProviderConfiguration{kmsProvider}},
+				},
+				Revocations: []config.RevocationConfiguration{{KeyID: "key-2022"}},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "revoked provider in read position",
+			in: &config.EncryptionConfiguration{
+				Resources: []config.ResourceConfiguration{
+					{Resources: []string{"secrets"}, Providers: []config.ProviderConfiguration{aescbcProvider, kmsProvider}},
+				},
+				Revocations: []config.RevocationConfiguration{{Provider: "foo"}},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			desc: "neither key id nor provider",
+			in: &config.EncryptionConfiguration{
+				Resources: []config.ResourceConfiguration{
+					{Resources: []string{"secrets"}, Providers: []config.ProviderConfiguration{kmsProvider}},
+				},
+				Revocations: []config.RevocationConfiguration{{}},
+			},
+			want: field.ErrorList{
+				field.Required(revocationsField.Index(0), "either keyID or provider is required"),
+			},
+		},
+		{
+			desc: "both key id and provider",
+			in: &config.EncryptionConfiguration{
+				Resources: []config.ResourceConfiguration{
+					{Resources: []string{"secrets"}, Providers: []config.ProviderConfiguration{kmsProvider}},
+				},
+				Revocations: []config.RevocationConfiguration{{KeyID: "key-2022", Provider: "foo"}},
+			},
+			want: field.ErrorList{
+				field.Invalid(revocationsField.Index(0), config.RevocationConfiguration{KeyID: "key-2022", Provider: "foo"}, "exactly one of keyID or provider may be set"),
+			},
+		},
+		{
+			desc: "duplicate revoked provider",
+			in: &config.EncryptionConfiguration{
+				Resources: []config.ResourceConfiguration{
+					{Resources: []string{"secrets"}, Providers: []config.ProviderConfiguration{aescbcProvider, kmsProvider}},
+				},
+				Revocations: []config.RevocationConfiguration{{Provider: "foo"}, {Provider: "foo"}},
+			},
+			want: field.ErrorList{
+				field.Duplicate(revocationsField.Index(1).Child("provider"), "foo"),
+			},
+		},
+		{
+			desc: "revoked provider in write position",
+			in: &config.EncryptionConfiguration{
+				Resources: []config.ResourceConfiguration{
+					{Resources: []string{"secrets"}, Providers: []config.ProviderConfiguration{kmsProvider, aescbcProvider}},
+				},
+				Revocations: []config.RevocationConfiguration{{Provider: "foo"}},
+			},
+			want: field.ErrorList{
+				field.Invalid(root.Index(0).Child("providers").Index(0), "foo", "provider is revoked and may not be used to write new data"),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := validateRevocations(tt.in, revocationsField)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("revocation validation mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -32,7 +32,9 @@ func (in *AESConfiguration) DeepCopyInto(out *AESConfiguration) {
 	if in.Keys != nil {
 		in, out := &in.Keys, &out.Keys
 		*out = make([]Key, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -47,6 +49,27 @@ func (in *AESConfiguration) DeepCopy() *AESConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DEKCacheConfiguration) DeepCopyInto(out *DEKCacheConfiguration) {
+	*out = *in
+	if in.PCRs != nil {
+		in, out := &in.PCRs, &out.PCRs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DEKCacheConfiguration.
+func (in *DEKCacheConfiguration) DeepCopy() *DEKCacheConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DEKCacheConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EncryptionConfiguration) DeepCopyInto(out *EncryptionConfiguration) {
 	*out = *in
@@ -58,6 +81,11 @@ func (in *EncryptionConfiguration) DeepCopyInto(out *EncryptionConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Revocations != nil {
+		in, out := &in.Revocations, &out.Revocations
+		*out = make([]RevocationConfiguration, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -98,6 +126,11 @@ func (in *IdentityConfiguration) DeepCopy() *IdentityConfiguration {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KMSConfiguration) DeepCopyInto(out *KMSConfiguration) {
 	*out = *in
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.CacheSize != nil {
 		in, out := &in.CacheSize, &out.CacheSize
 		*out = new(int32)
@@ -108,6 +141,31 @@ func (in *KMSConfiguration) DeepCopyInto(out *KMSConfiguration) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.MaxInFlight != nil {
+		in, out := &in.MaxInFlight, &out.MaxInFlight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DEKReuseMaxWrites != nil {
+		in, out := &in.DEKReuseMaxWrites, &out.DEKReuseMaxWrites
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DEKReuseDuration != nil {
+		in, out := &in.DEKReuseDuration, &out.DEKReuseDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DEKCache != nil {
+		in, out := &in.DEKCache, &out.DEKCache
+		*out = new(DEKCacheConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = new(ProvenanceConfiguration)
+		**out = **in
+	}
 	return
 }
 
@@ -121,9 +179,58 @@ func (in *KMSConfiguration) DeepCopy() *KMSConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KMSSplitConfiguration) DeepCopyInto(out *KMSSplitConfiguration) {
+	*out = *in
+	if in.CacheSize != nil {
+		in, out := &in.CacheSize, &out.CacheSize
+		*out = new(int32)
+		**out = **in
+	}
+	in.Primary.DeepCopyInto(&out.Primary)
+	in.Secondary.DeepCopyInto(&out.Secondary)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KMSSplitConfiguration.
+func (in *KMSSplitConfiguration) DeepCopy() *KMSSplitConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KMSSplitConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KMSSplitEndpoint) DeepCopyInto(out *KMSSplitEndpoint) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KMSSplitEndpoint.
+func (in *KMSSplitEndpoint) DeepCopy() *KMSSplitEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(KMSSplitEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Key) DeepCopyInto(out *Key) {
 	*out = *in
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = new(ProvenanceConfiguration)
+		**out = **in
+	}
 	return
 }
 
@@ -137,6 +244,38 @@ func (in *Key) DeepCopy() *Key {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyringConfiguration) DeepCopyInto(out *KeyringConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyringConfiguration.
+func (in *KeyringConfiguration) DeepCopy() *KeyringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvenanceConfiguration) DeepCopyInto(out *ProvenanceConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvenanceConfiguration.
+func (in *ProvenanceConfiguration) DeepCopy() *ProvenanceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvenanceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfiguration) DeepCopyInto(out *ProviderConfiguration) {
 	*out = *in
@@ -165,6 +304,26 @@ func (in *ProviderConfiguration) DeepCopyInto(out *ProviderConfiguration) {
 		*out = new(KMSConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(TPMConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.X25519 != nil {
+		in, out := &in.X25519, &out.X25519
+		*out = new(X25519Configuration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Keyring != nil {
+		in, out := &in.Keyring, &out.Keyring
+		*out = new(KeyringConfiguration)
+		**out = **in
+	}
+	if in.KMSSplit != nil {
+		in, out := &in.KMSSplit, &out.KMSSplit
+		*out = new(KMSSplitConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -206,13 +365,31 @@ func (in *ResourceConfiguration) DeepCopy() *ResourceConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevocationConfiguration) DeepCopyInto(out *RevocationConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevocationConfiguration.
+func (in *RevocationConfiguration) DeepCopy() *RevocationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RevocationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretboxConfiguration) DeepCopyInto(out *SecretboxConfiguration) {
 	*out = *in
 	if in.Keys != nil {
 		in, out := &in.Keys, &out.Keys
 		*out = make([]Key, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -226,3 +403,45 @@ func (in *SecretboxConfiguration) DeepCopy() *SecretboxConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPMConfiguration) DeepCopyInto(out *TPMConfiguration) {
+	*out = *in
+	if in.PCRs != nil {
+		in, out := &in.PCRs, &out.PCRs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMConfiguration.
+func (in *TPMConfiguration) DeepCopy() *TPMConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *X25519Configuration) DeepCopyInto(out *X25519Configuration) {
+	*out = *in
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new X25519Configuration.
+func (in *X25519Configuration) DeepCopy() *X25519Configuration {
+	if in == nil {
+		return nil
+	}
+	out := new(X25519Configuration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package responsewriters
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -195,16 +196,24 @@ type deferredResponseWriter struct {
 	hw         http.ResponseWriter
 	w          io.Writer
 
+	// buf holds the initial writes of a compressible response until it is clear
+	// whether it crosses defaultGzipThresholdBytes and is worth compressing.
+	// Streaming encoders produce the response in many small writes, so the
+	// decision cannot be made on the size of the first write alone.
+	buf *bytes.Buffer
+
 	ctx context.Context
 }
 
 func (w *deferredResponseWriter) Write(p []byte) (n int, err error) {
 	ctx := w.ctx
 	span := tracing.SpanFromContext(ctx)
-	// This Step usually wraps in-memory object serialization.
-	span.AddEvent("About to start writing response", attribute.Int("size", len(p)))
+	firstWrite := !w.hasWritten && w.buf == nil
+	if firstWrite {
+		// This Step usually wraps in-memory object serialization.
+		span.AddEvent("About to start writing response", attribute.Int("size", len(p)))
+	}
 
-	firstWrite := !w.hasWritten
 	defer func() {
 		if err != nil {
 			span.AddEvent("Write call failed",
@@ -212,7 +221,7 @@ func (w *deferredResponseWriter) Write(p []byte) (n int, err error) {
 				attribute.Int("size", len(p)),
 				attribute.Bool("firstWrite", firstWrite),
 				attribute.String("err", err.Error()))
-		} else {
+		} else if firstWrite {
 			span.AddEvent("Write call succeeded",
 				attribute.String("writer", fmt.Sprintf("%T", w.w)),
 				attribute.Int("size", len(p)),
@@ -222,12 +231,39 @@ func (w *deferredResponseWriter) Write(p []byte) (n int, err error) {
 	if w.hasWritten {
 		return w.w.Write(p)
 	}
+
+	if w.contentEncoding == "gzip" && w.bufferedLen()+len(p) <= defaultGzipThresholdBytes {
+		// the response may still stay below the compression threshold, hold the
+		// bytes back until the decision can be made
+		if w.buf == nil {
+			w.buf = &bytes.Buffer{}
+		}
+		return w.buf.Write(p)
+	}
+
+	if err := w.writeHeaders(w.bufferedLen() + len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+func (w *deferredResponseWriter) bufferedLen() int {
+	if w.buf == nil {
+		return 0
+	}
+	return w.buf.Len()
+}
+
+// writeHeaders sends the response headers, choosing compression based on the
+// response size known at this point, and flushes any bytes held back while the
+// decision was pending.
+func (w *deferredResponseWriter) writeHeaders(size int) error {
 	w.hasWritten = true
 
 	hw := w.hw
 	header := hw.Header()
 	switch {
-	case w.contentEncoding == "gzip" && len(p) > defaultGzipThresholdBytes:
+	case w.contentEncoding == "gzip" && size > defaultGzipThresholdBytes:
 		header.Set("Content-Encoding", "gzip")
 		header.Add("Vary", "Accept-Encoding")
 
@@ -241,12 +277,23 @@ func (w *deferredResponseWriter) Write(p []byte) (n int, err error) {
 
 	header.Set("Content-Type", w.mediaType)
 	hw.WriteHeader(w.statusCode)
-	return w.w.Write(p)
+	if w.buf != nil {
+		_, err := w.w.Write(w.buf.Bytes())
+		w.buf = nil
+		return err
+	}
+	return nil
 }
 
 func (w *deferredResponseWriter) Close() error {
 	if !w.hasWritten {
-		return nil
+		if w.buf == nil {
+			return nil
+		}
+		// the entire response stayed below the compression threshold
+		if err := w.writeHeaders(w.bufferedLen()); err != nil {
+			return err
+		}
 	}
 	var err error
 	switch t := w.w.(type) {
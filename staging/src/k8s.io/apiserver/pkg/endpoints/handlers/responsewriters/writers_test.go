@@ -19,6 +19,7 @@ package responsewriters
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -374,6 +375,85 @@ func TestSerializeObject(t *testing.T) {
 	}
 }
 
+func TestDeferredResponseWriterStreamingWrites(t *testing.T) {
+	largePayload := bytes.Repeat([]byte("0123456789abcdef"), defaultGzipThresholdBytes/16+1)
+	smallPayload := []byte("{test-object,test-object}")
+	tests := []struct {
+		name string
+
+		payload     []byte
+		wantHeaders http.Header
+		wantGzip    bool
+	}{
+		{
+			name:    "many small writes crossing the gzip threshold are compressed",
+			payload: largePayload,
+			wantHeaders: http.Header{
+				"Content-Type":     []string{"application/json"},
+				"Content-Encoding": []string{"gzip"},
+				"Vary":             []string{"Accept-Encoding"},
+			},
+			wantGzip: true,
+		},
+		{
+			name:    "many small writes below the gzip threshold stay uncompressed",
+			payload: smallPayload,
+			wantHeaders: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			drw := &deferredResponseWriter{
+				mediaType:       "application/json",
+				statusCode:      http.StatusOK,
+				contentEncoding: "gzip",
+				hw:              recorder,
+				ctx:             context.Background(),
+			}
+			// stream the payload the way a streaming encoder would, a few bytes at a time
+			for chunk := tt.payload; len(chunk) > 0; {
+				n := 16
+				if n > len(chunk) {
+					n = len(chunk)
+				}
+				if _, err := drw.Write(chunk[:n]); err != nil {
+					t.Fatal(err)
+				}
+				chunk = chunk[n:]
+			}
+			if err := drw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			result := recorder.Result()
+			if result.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected code: %v", result.StatusCode)
+			}
+			if !reflect.DeepEqual(result.Header, tt.wantHeaders) {
+				t.Fatal(diff.ObjectReflectDiff(tt.wantHeaders, result.Header))
+			}
+			body := result.Body
+			if tt.wantGzip {
+				gr, err := gzip.NewReader(body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = io.NopCloser(gr)
+			}
+			got, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(tt.payload, got) {
+				t.Fatalf("wanted:\n%s\ngot:\n%s", hex.Dump(tt.payload), hex.Dump(got))
+			}
+		})
+	}
+}
+
 func randTime(t *time.Time, r *rand.Rand) {
 	*t = time.Unix(r.Int63n(1000*365*24*60*60), r.Int63())
 }
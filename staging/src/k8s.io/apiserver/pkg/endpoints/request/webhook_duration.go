@@ -125,6 +125,16 @@ type LatencyTrackers struct {
 	// It does not include the time incurred in admission, or validation.
 	StorageTracker DurationTracker
 
+	// StorageTransformTracker tracks the latency incurred in the storage
+	// value transformer chain: decryption of data read from the storage
+	// layer and encryption of data written to it, including any external
+	// KMS round trips. Transformations run sequentially, so latency is
+	// aggregated using sum function.
+	// It is a subset of the time accounted by StorageTracker and allows
+	// attributing slow reads and writes to encryption rather than to the
+	// storage layer itself.
+	StorageTransformTracker DurationTracker
+
 	// TransformTracker tracks the latency incurred in transforming the
 	// response object(s) returned from the underlying storage layer.
 	// This includes transforming the object to user's desired form
@@ -169,6 +179,7 @@ func WithLatencyTrackersAndCustomClock(parent context.Context, c clock.Clock) co
 		MutatingWebhookTracker:   newSumLatencyTracker(c),
 		ValidatingWebhookTracker: newMaxLatencyTracker(c),
 		StorageTracker:           newSumLatencyTracker(c),
+		StorageTransformTracker:  newSumLatencyTracker(c),
 		TransformTracker:         newSumLatencyTracker(c),
 		SerializationTracker:     newSumLatencyTracker(c),
 		ResponseWriteTracker:     newSumLatencyTracker(c),
@@ -208,6 +219,15 @@ func TrackStorageLatency(ctx context.Context, d time.Duration) {
 	}
 }
 
+// TrackStorageTransformLatency is used to track latency incurred
+// inside the storage value transformer chain (decryption and encryption).
+// When called multiple times, the latency provided will be summed up.
+func TrackStorageTransformLatency(ctx context.Context, d time.Duration) {
+	if tracker, ok := LatencyTrackersFrom(ctx); ok {
+		tracker.StorageTransformTracker.TrackDuration(d)
+	}
+}
+
 // TrackSerializeResponseObjectLatency is used to track latency incurred in
 // serialization (json or protobuf) of the response object.
 // When called multiple times, the latency provided will be summed up.
@@ -237,6 +257,7 @@ func AuditAnnotationsFromLatencyTrackers(ctx context.Context) map[string]string
 	const (
 		transformLatencyKey         = "apiserver.latency.k8s.io/transform-response-object"
 		storageLatencyKey           = "apiserver.latency.k8s.io/etcd"
+		storageTransformLatencyKey  = "apiserver.latency.k8s.io/storage-transform"
 		serializationLatencyKey     = "apiserver.latency.k8s.io/serialize-response-object"
 		responseWriteLatencyKey     = "apiserver.latency.k8s.io/response-write"
 		mutatingWebhookLatencyKey   = "apiserver.latency.k8s.io/mutating-webhook"
@@ -255,6 +276,9 @@ func AuditAnnotationsFromLatencyTrackers(ctx context.Context) map[string]string
 	if latency := tracker.StorageTracker.GetLatency(); latency != 0 {
 		annotations[storageLatencyKey] = latency.String()
 	}
+	if latency := tracker.StorageTransformTracker.GetLatency(); latency != 0 {
+		annotations[storageTransformLatencyKey] = latency.String()
+	}
 	if latency := tracker.SerializationTracker.GetLatency(); latency != 0 {
 		annotations[serializationLatencyKey] = latency.String()
 	}
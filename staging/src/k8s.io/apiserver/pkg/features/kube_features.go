@@ -191,6 +191,13 @@ const (
 	// document.
 	StorageVersionHash featuregate.Feature = "StorageVersionHash"
 
+	// owner: @serathius
+	// alpha: v1.27
+	//
+	// Serializes list responses item by item instead of buffering the entire
+	// list in memory, bounding the memory needed to serve large LIST requests.
+	StreamingCollectionEncoding featuregate.Feature = "StreamingCollectionEncoding"
+
 	// owner: @wojtek-t
 	// alpha: v1.15
 	// beta: v1.16
@@ -248,5 +255,7 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 
 	StorageVersionHash: {Default: true, PreRelease: featuregate.Beta},
 
+	StreamingCollectionEncoding: {Default: false, PreRelease: featuregate.Alpha},
+
 	WatchBookmark: {Default: true, PreRelease: featuregate.GA, LockToDefault: true},
 }
@@ -62,6 +62,7 @@ import (
 	"k8s.io/apiserver/pkg/server/egressselector"
 	genericfilters "k8s.io/apiserver/pkg/server/filters"
 	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/mux"
 	"k8s.io/apiserver/pkg/server/routes"
 	serverstore "k8s.io/apiserver/pkg/server/storage"
 	"k8s.io/apiserver/pkg/storageversion"
@@ -124,6 +125,37 @@ type Config struct {
 	// FlowControl, if not nil, gives priority and fairness to request handling
 	FlowControl utilflowcontrol.Interface
 
+	// ReadOnlyMode, if not nil, allows all mutating requests to be rejected at
+	// runtime, e.g. during etcd maintenance. Its state is exposed via the
+	// privileged /debug/flags/read-only endpoint and reflected in /readyz.
+	ReadOnlyMode *genericfilters.ReadOnlyMode
+
+	// RemovedAPICompatibilityShim, if not nil, rewrites requests for selected
+	// removed group-versions in selected namespaces to a replacement
+	// group-version until a configured deadline, easing migrations blocked on
+	// manifests pinned to removed beta APIs.
+	RemovedAPICompatibilityShim *genericfilters.CompatibilityShim
+
+	// EncryptionStateHandler, if not nil, serves the state of the encryption
+	// at rest configuration, including key provenance metadata, on the
+	// privileged /debug/encryption-state endpoint.
+	EncryptionStateHandler http.Handler
+
+	// EncryptionProvidersDebugHandler, if not nil, serves live per-provider
+	// statistics of the encryption at rest stack on the privileged
+	// /debug/encryption/providers endpoint.
+	EncryptionProvidersDebugHandler http.Handler
+
+	// EncryptionCoverageHandler, if not nil, serves the most recent sampled
+	// encryption coverage report on the privileged
+	// /debug/encryption/coverage endpoint.
+	EncryptionCoverageHandler http.Handler
+
+	// EncryptionQuarantineHandler, if not nil, serves the index of records
+	// that repeatedly failed decryption, with forced deletion and raw export,
+	// on the privileged /debug/encryption/quarantine endpoint.
+	EncryptionQuarantineHandler http.Handler
+
 	EnableIndex     bool
 	EnableProfiling bool
 	EnableDiscovery bool
@@ -162,6 +194,14 @@ type Config struct {
 	DiscoveryAddresses discovery.Addresses
 	// The default set of healthz checks. There might be more added via AddHealthChecks dynamically.
 	HealthzChecks []healthz.HealthChecker
+	// HealthProbesListener, when set, additionally serves /healthz, /livez and /readyz on a
+	// dedicated listener outside the secure serving chain. Requests on this listener bypass
+	// authentication and API priority and fairness, so load balancer probes need no tokens
+	// and health stays answerable while the secure chain is saturated.
+	HealthProbesListener net.Listener
+	// HealthProbesMaxInFlight caps the number of concurrent requests served by the health
+	// probes listener; excess requests are rejected with 429. Zero means no limit.
+	HealthProbesMaxInFlight int
 	// The default set of livez checks. There might be more added via AddHealthChecks dynamically.
 	LivezChecks []healthz.HealthChecker
 	// The default set of readyz-only checks. There might be more added via AddReadyzChecks dynamically.
@@ -662,10 +702,12 @@ func (c completedConfig) New(name string, delegationTarget DelegationTarget) (*G
 		preShutdownHooks:       map[string]preShutdownHookEntry{},
 		disabledPostStartHooks: c.DisabledPostStartHooks,
 
-		healthzChecks:    c.HealthzChecks,
-		livezChecks:      c.LivezChecks,
-		readyzChecks:     c.ReadyzChecks,
-		livezGracePeriod: c.LivezGracePeriod,
+		healthzChecks:           c.HealthzChecks,
+		livezChecks:             c.LivezChecks,
+		readyzChecks:            c.ReadyzChecks,
+		livezGracePeriod:        c.LivezGracePeriod,
+		healthProbesListener:    c.HealthProbesListener,
+		healthProbesMaxInFlight: c.HealthProbesMaxInFlight,
 
 		DiscoveryGroupManager: discovery.NewRootAPIsHandler(c.DiscoveryAddresses, c.Serializer),
 
@@ -683,6 +725,10 @@ func (c completedConfig) New(name string, delegationTarget DelegationTarget) (*G
 		muxAndDiscoveryCompleteSignals: map[string]<-chan struct{}{},
 	}
 
+	if c.HealthProbesListener != nil {
+		s.healthProbesMux = mux.NewPathRecorderMux("health-probes")
+	}
+
 	if utilfeature.DefaultFeatureGate.Enabled(genericfeatures.AggregatedDiscoveryEndpoint) {
 		manager := c.AggregatedDiscoveryGroupManager
 		if manager == nil {
@@ -745,6 +791,22 @@ func (c completedConfig) New(name string, delegationTarget DelegationTarget) (*G
 		}
 	}
 
+	if c.ReadOnlyMode != nil {
+		// While read-only mode is enabled, the server deliberately rejects
+		// mutating requests, so it reports not ready to steer clients that
+		// need a writable apiserver elsewhere.
+		readOnlyMode := c.ReadOnlyMode
+		err := s.AddReadyzChecks(healthz.NamedCheck("read-only-mode", func(r *http.Request) error {
+			if readOnlyMode.Enabled() {
+				return fmt.Errorf("the apiserver is in read-only mode")
+			}
+			return nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	const priorityAndFairnessConfigConsumerHookName = "priority-and-fairness-config-consumer"
 	if s.isPostStartHookRegistered(priorityAndFairnessConfigConsumerHookName) {
 	} else if c.FlowControl != nil {
@@ -840,7 +902,14 @@ func BuildHandlerChainWithStorageVersionPrecondition(apiHandler http.Handler, c
 }
 
 func DefaultBuildHandlerChain(apiHandler http.Handler, c *Config) http.Handler {
-	handler := filterlatency.TrackCompleted(apiHandler)
+	handler := apiHandler
+	if c.RemovedAPICompatibilityShim != nil {
+		handler = genericfilters.WithRemovedAPICompatibility(handler, c.RemovedAPICompatibilityShim)
+	}
+	handler = filterlatency.TrackCompleted(handler)
+	if c.ReadOnlyMode != nil {
+		handler = genericfilters.WithReadOnlyMode(handler, c.ReadOnlyMode)
+	}
 	handler = genericapifilters.WithAuthorization(handler, c.Authorization.Authorizer, c.Serializer)
 	handler = filterlatency.TrackStarted(handler, c.TracerProvider, "authorization")
 
@@ -914,6 +983,21 @@ func installAPI(s *GenericAPIServer, c *Config) {
 		// so far, only logging related endpoints are considered valid to add for these debug flags.
 		routes.DebugFlags{}.Install(s.Handler.NonGoRestfulMux, "v", routes.StringFlagPutHandler(logs.GlogSetter))
 	}
+	if c.ReadOnlyMode != nil {
+		routes.DebugFlags{}.Install(s.Handler.NonGoRestfulMux, "read-only", routes.StringFlagPutHandler(c.ReadOnlyMode.Set))
+	}
+	if c.EncryptionStateHandler != nil {
+		s.Handler.NonGoRestfulMux.UnlistedHandle("/debug/encryption-state", c.EncryptionStateHandler)
+	}
+	if c.EncryptionProvidersDebugHandler != nil {
+		s.Handler.NonGoRestfulMux.UnlistedHandle("/debug/encryption/providers", c.EncryptionProvidersDebugHandler)
+	}
+	if c.EncryptionCoverageHandler != nil {
+		s.Handler.NonGoRestfulMux.UnlistedHandle("/debug/encryption/coverage", c.EncryptionCoverageHandler)
+	}
+	if c.EncryptionQuarantineHandler != nil {
+		s.Handler.NonGoRestfulMux.UnlistedHandle("/debug/encryption/quarantine", c.EncryptionQuarantineHandler)
+	}
 
 	if c.EnableMetrics {
 		if c.EnableProfiling {
@@ -111,6 +111,7 @@ func (c *DynamicCertKeyPairContent) loadCertKeyPair() error {
 
 	c.certKeyPair.Store(newCertKey)
 	klog.V(2).InfoS("Loaded a new cert/key pair", "name", c.Name())
+	recordCertificateExpiry(c.name, cert)
 
 	for _, listener := range c.listeners {
 		listener.Enqueue()
@@ -142,6 +143,13 @@ func (c *DynamicCertKeyPairContent) Run(ctx context.Context, workers int) {
 		}
 	}, time.Minute, ctx.Done())
 
+	// start a jittered timer that rechecks periodically, in case a file event is missed.  the
+	// jitter staggers reloads across control-plane replicas sharing rotated files, so they do
+	// not all pick up the new content at the same instant.
+	go wait.JitterUntil(func() {
+		c.queue.Add(workItemKey)
+	}, FileRefreshDuration, 1.0, true, ctx.Done())
+
 	<-ctx.Done()
 }
 
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamiccertificates
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var registerMetricsOnce sync.Once
+
+var certificateExpirySeconds = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Namespace: "apiserver",
+		Name:      "dynamic_certificate_expiration_timestamp_seconds",
+		Help: "Expiration timestamp of the leaf certificate of each dynamically loaded " +
+			"cert/key pair, labeled by the certificate's purpose. Rotation has succeeded " +
+			"when the timestamp moves into the future again.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"name"},
+)
+
+// recordCertificateExpiry publishes the expiration timestamp of the leaf
+// certificate of a freshly loaded cert/key pair. Certificates that cannot be
+// parsed are ignored; loading has already validated the pair.
+func recordCertificateExpiry(name string, certPEM []byte) {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(certificateExpirySeconds)
+	})
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return
+	}
+	certificateExpirySeconds.WithLabelValues(name).Set(float64(cert.NotAfter.Unix()))
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamiccertificates
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// SecretCertKeyController provides a CertKeyContentProvider that can dynamically react to changes of
+// a TLS secret, so serving and client certificates rotated by writing a new secret are picked up
+// without a restart.
+type SecretCertKeyController struct {
+	name string
+
+	secretLister    corev1listers.SecretLister
+	secretNamespace string
+	secretName      string
+	// secretInformer is tracked so that we can start it on Run
+	secretInformer cache.SharedIndexInformer
+
+	// certKeyPair is a certKeyContent that contains the last read, non-zero length content of the key and cert
+	certKeyPair atomic.Value
+
+	listeners []Listener
+
+	queue workqueue.RateLimitingInterface
+	// preRunCaches are the caches to sync before starting the work of this control loop
+	preRunCaches []cache.InformerSynced
+}
+
+var _ CertKeyContentProvider = &SecretCertKeyController{}
+var _ ControllerRunner = &SecretCertKeyController{}
+
+// NewDynamicCertKeyPairFromSecretController returns a CertKeyContentProvider based on a secret that
+// automatically reloads content. The secret must carry the certificate and key under the standard
+// tls.crt and tls.key keys. It is near-realtime via an informer.
+func NewDynamicCertKeyPairFromSecretController(purpose, namespace, name string, kubeClient kubernetes.Interface) (*SecretCertKeyController, error) {
+	if len(purpose) == 0 {
+		return nil, fmt.Errorf("missing purpose for cert/key pair")
+	}
+	if len(namespace) == 0 {
+		return nil, fmt.Errorf("missing namespace for cert/key pair")
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("missing name for cert/key pair")
+	}
+	certKeyName := fmt.Sprintf("%s::%s::%s", purpose, namespace, name)
+
+	// we construct our own informer because we need such a small subset of the information available.  Just one namespace.
+	uncastSecretInformer := corev1informers.NewFilteredSecretInformer(kubeClient, namespace, 12*time.Hour, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, func(listOptions *v1.ListOptions) {
+		listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	})
+
+	secretLister := corev1listers.NewSecretLister(uncastSecretInformer.GetIndexer())
+
+	c := &SecretCertKeyController{
+		name:            certKeyName,
+		secretNamespace: namespace,
+		secretName:      name,
+		secretLister:    secretLister,
+		secretInformer:  uncastSecretInformer,
+
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("DynamicSecretCertKeyPair-%s", purpose)),
+		preRunCaches: []cache.InformerSynced{uncastSecretInformer.HasSynced},
+	}
+
+	uncastSecretInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if cast, ok := obj.(*corev1.Secret); ok {
+				return cast.Name == c.secretName && cast.Namespace == c.secretNamespace
+			}
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				if cast, ok := tombstone.Obj.(*corev1.Secret); ok {
+					return cast.Name == c.secretName && cast.Namespace == c.secretNamespace
+				}
+			}
+			return true // always return true just in case.  The checks are fairly cheap
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			// we have a filter, so any time we're called, we may as well queue. We only ever check one secret
+			// so we don't have to be choosy about our key.
+			AddFunc: func(obj interface{}) {
+				c.queue.Add(c.keyFn())
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.queue.Add(c.keyFn())
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.queue.Add(c.keyFn())
+			},
+		},
+	})
+
+	return c, nil
+}
+
+func (c *SecretCertKeyController) keyFn() string {
+	// this format matches DeletionHandlingMetaNamespaceKeyFunc for our single key
+	return c.secretNamespace + "/" + c.secretName
+}
+
+// AddListener adds a listener to be notified when the cert/key pair content changes.
+func (c *SecretCertKeyController) AddListener(listener Listener) {
+	c.listeners = append(c.listeners, listener)
+}
+
+// loadCertKeyPair determines the next set of content for the cert/key pair.
+func (c *SecretCertKeyController) loadCertKeyPair() error {
+	secret, err := c.secretLister.Secrets(c.secretNamespace).Get(c.secretName)
+	if err != nil {
+		return err
+	}
+	cert := secret.Data[corev1.TLSCertKey]
+	key := secret.Data[corev1.TLSPrivateKeyKey]
+	if len(cert) == 0 || len(key) == 0 {
+		return fmt.Errorf("missing content for cert/key pair %q", c.Name())
+	}
+
+	// Ensure that the key matches the cert and both are valid
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return err
+	}
+
+	newCertKey := &certKeyContent{
+		cert: cert,
+		key:  key,
+	}
+
+	// check to see if we have a change. If the values are the same, do nothing.
+	existing, ok := c.certKeyPair.Load().(*certKeyContent)
+	if ok && existing != nil && existing.Equal(newCertKey) {
+		return nil
+	}
+
+	c.certKeyPair.Store(newCertKey)
+	klog.V(2).InfoS("Loaded a new cert/key pair", "name", c.Name())
+	recordCertificateExpiry(c.name, cert)
+
+	for _, listener := range c.listeners {
+		listener.Enqueue()
+	}
+
+	return nil
+}
+
+// RunOnce runs a single sync loop
+func (c *SecretCertKeyController) RunOnce(ctx context.Context) error {
+	// Ignore the error when running once, because we think it's better to serve with the previous
+	// content for a brief time than to completely crash.  If crashing is necessary, higher order
+	// logic like a healthcheck should cause failures.
+	_ = c.loadCertKeyPair()
+	return nil
+}
+
+// Run starts the controller and blocks until context is killed.
+func (c *SecretCertKeyController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.InfoS("Starting controller", "name", c.name)
+	defer klog.InfoS("Shutting down controller", "name", c.name)
+
+	// we have a personal informer that is narrowly scoped, start it.
+	go c.secretInformer.Run(ctx.Done())
+
+	// wait for your secondary caches to fill before starting your work
+	if !cache.WaitForNamedCacheSync(c.name, ctx.Done(), c.preRunCaches...) {
+		return
+	}
+
+	// doesn't matter what workers say, only start one.
+	go wait.Until(c.runWorker, time.Second, ctx.Done())
+
+	// start a jittered timer that rechecks periodically, just in case.  the jitter staggers reloads
+	// across control-plane replicas sharing a rotated secret, so they do not all pick up the new
+	// content at the same instant.
+	go wait.JitterUntil(func() {
+		c.queue.Add(workItemKey)
+	}, FileRefreshDuration, 1.0, true, ctx.Done())
+
+	<-ctx.Done()
+}
+
+func (c *SecretCertKeyController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *SecretCertKeyController) processNextWorkItem() bool {
+	dsKey, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(dsKey)
+
+	err := c.loadCertKeyPair()
+	if err == nil {
+		c.queue.Forget(dsKey)
+		return true
+	}
+
+	utilruntime.HandleError(fmt.Errorf("%v failed with : %v", dsKey, err))
+	c.queue.AddRateLimited(dsKey)
+
+	return true
+}
+
+// Name is just an identifier
+func (c *SecretCertKeyController) Name() string {
+	return c.name
+}
+
+// CurrentCertKeyContent provides cert and key byte content
+func (c *SecretCertKeyController) CurrentCertKeyContent() ([]byte, []byte) {
+	uncastObj := c.certKeyPair.Load()
+	if uncastObj == nil {
+		return nil, nil // this can happen if we've been unable to load data from the apiserver for some reason
+	}
+
+	certKeyContent := uncastObj.(*certKeyContent)
+	return certKeyContent.cert, certKeyContent.key
+}
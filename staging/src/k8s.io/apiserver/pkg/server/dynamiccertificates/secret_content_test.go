@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamiccertificates
+
+import (
+	"bytes"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSecretCertKeyController(t *testing.T) {
+	c, err := NewDynamicCertKeyPairFromSecretController("serving-cert", "kube-system", "serving-cert", fake.NewSimpleClientset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// before any content is available the provider must not claim a cert/key pair
+	if cert, key := c.CurrentCertKeyContent(); cert != nil || key != nil {
+		t.Errorf("expected no content before the first load, got %q, %q", cert, key)
+	}
+	if err := c.loadCertKeyPair(); err == nil {
+		t.Error("expected an error loading a missing secret")
+	}
+
+	if err := c.secretInformer.GetIndexer().Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "serving-cert"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       serverCert,
+			corev1.TLSPrivateKeyKey: serverKey,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	listener := &enqueueTrackingListener{}
+	c.AddListener(listener)
+
+	if err := c.loadCertKeyPair(); err != nil {
+		t.Fatal(err)
+	}
+	cert, key := c.CurrentCertKeyContent()
+	if !bytes.Equal(cert, serverCert) || !bytes.Equal(key, serverKey) {
+		t.Error("unexpected cert/key content after loading")
+	}
+	if !listener.enqueued {
+		t.Error("expected listeners to be notified of the new content")
+	}
+
+	// reloading unchanged content must not notify listeners again
+	listener.enqueued = false
+	if err := c.loadCertKeyPair(); err != nil {
+		t.Fatal(err)
+	}
+	if listener.enqueued {
+		t.Error("expected no notification for unchanged content")
+	}
+}
+
+type enqueueTrackingListener struct {
+	enqueued bool
+}
+
+func (l *enqueueTrackingListener) Enqueue() {
+	l.enqueued = true
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/audit"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
+)
+
+// removedAPICompatibilityAuditAnnotationKey is the audit annotation recording
+// that a request for a removed group-version was rewritten to its replacement
+// by the compatibility shim.
+const removedAPICompatibilityAuditAnnotationKey = "compatibility.k8s.io/removed-api"
+
+// CompatibilityShim re-serves selected removed group-versions by rewriting
+// matching requests to a replacement group-version that is still served. It is
+// a migration aid for namespaces whose manifests are pinned to a removed beta
+// API: the rewrite only applies to the configured namespaces and only until
+// the configured deadline, after which the shim is inert.
+//
+// The rewrite is purely positional - the request and response bodies are the
+// replacement version's representation - so it is only useful for removed
+// versions that are wire-compatible with their replacement.
+type CompatibilityShim struct {
+	// rules maps a removed group-version to the group-version its requests
+	// are rewritten to.
+	rules map[schema.GroupVersion]schema.GroupVersion
+
+	// namespaces holds the namespaces the rewrite applies to; requests for
+	// other namespaces, and requests without a namespace, pass through and
+	// get the regular 404.
+	namespaces sets.String
+
+	// until is the instant the shim expires. The deadline is evaluated per
+	// request so a long-running server goes dark on schedule.
+	until time.Time
+}
+
+// ParseCompatibilityShimRules parses rules in the form
+// "group/version=group/replacementVersion", e.g.
+// "networking.example.com/v1beta1=networking.example.com/v1". Both
+// group-versions must belong to the same API group.
+func ParseCompatibilityShimRules(specs []string) (map[schema.GroupVersion]schema.GroupVersion, error) {
+	rules := make(map[schema.GroupVersion]schema.GroupVersion, len(specs))
+	for _, spec := range specs {
+		removed, replacement, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid removed API compatibility rule %q: must be in group/version=group/version form", spec)
+		}
+		removedGV, err := schema.ParseGroupVersion(removed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid removed API compatibility rule %q: %v", spec, err)
+		}
+		replacementGV, err := schema.ParseGroupVersion(replacement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid removed API compatibility rule %q: %v", spec, err)
+		}
+		if removedGV == replacementGV {
+			return nil, fmt.Errorf("invalid removed API compatibility rule %q: removed and replacement group-versions are identical", spec)
+		}
+		// the shim runs after authorization, which authorizes the removed
+		// group; a cross-group rewrite would serve a request the user was
+		// never authorized for against the replacement group's storage
+		if removedGV.Group != replacementGV.Group {
+			return nil, fmt.Errorf("invalid removed API compatibility rule %q: removed and replacement group-versions must be in the same API group", spec)
+		}
+		if _, ok := rules[removedGV]; ok {
+			return nil, fmt.Errorf("duplicate removed API compatibility rule for %s", removedGV)
+		}
+		rules[removedGV] = replacementGV
+	}
+	return rules, nil
+}
+
+// NewCompatibilityShim returns a CompatibilityShim rewriting the given
+// group-versions for the given namespaces until the given deadline.
+func NewCompatibilityShim(rules map[schema.GroupVersion]schema.GroupVersion, namespaces []string, until time.Time) *CompatibilityShim {
+	return &CompatibilityShim{
+		rules:      rules,
+		namespaces: sets.NewString(namespaces...),
+		until:      until,
+	}
+}
+
+// apiPrefix returns the URL path prefix the given group-version is served
+// under.
+func apiPrefix(gv schema.GroupVersion) string {
+	if len(gv.Group) == 0 {
+		return "/api/" + gv.Version
+	}
+	return "/apis/" + gv.Group + "/" + gv.Version
+}
+
+// WithRemovedAPICompatibility rewrites resource requests for removed
+// group-versions covered by the shim to their replacement group-version,
+// attaching a warning to the response and an annotation to the audit event.
+// It must run after the request info filter and before routing.
+func WithRemovedAPICompatibility(handler http.Handler, shim *CompatibilityShim) http.Handler {
+	if shim == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		requestInfo, ok := apirequest.RequestInfoFrom(ctx)
+		if !ok {
+			handleError(w, req, fmt.Errorf("no RequestInfo found in context, handler chain must be wrong"))
+			return
+		}
+		if !requestInfo.IsResourceRequest || len(requestInfo.Namespace) == 0 || !shim.namespaces.Has(requestInfo.Namespace) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		removedGV := schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
+		replacementGV, ok := shim.rules[removedGV]
+		if !ok || time.Now().After(shim.until) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		rewrite := fmt.Sprintf("%s => %s", removedGV, replacementGV)
+		warning.AddWarning(ctx, "", fmt.Sprintf(
+			"%s is no longer served; this request was rewritten to %s by the removed API compatibility shim for namespace %q, which expires at %s",
+			removedGV, replacementGV, requestInfo.Namespace, shim.until.Format(time.RFC3339)))
+		audit.AddAuditAnnotation(ctx, removedAPICompatibilityAuditAnnotationKey, rewrite)
+
+		newPath := apiPrefix(replacementGV) + strings.TrimPrefix(req.URL.Path, apiPrefix(removedGV))
+		// refresh the RequestInfo so downstream consumers such as priority
+		// and fairness and audit see the version actually served; rules never
+		// cross group boundaries, so the authorization decision made against
+		// the removed group-version still stands
+		newRequestInfo := *requestInfo
+		newRequestInfo.APIVersion = replacementGV.Version
+		newRequestInfo.Path = newPath
+		newReq := req.Clone(apirequest.WithRequestInfo(ctx, &newRequestInfo))
+		newReq.URL.Path = newPath
+		handler.ServeHTTP(w, newReq)
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
+)
+
+func TestParseCompatibilityShimRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		wantErr bool
+	}{
+		{
+			name:  "valid rule",
+			specs: []string{"networking.example.com/v1beta1=networking.example.com/v1"},
+		},
+		{
+			name:    "cross-group rule",
+			specs:   []string{"events.k8s.io/v1beta1=v1"},
+			wantErr: true,
+		},
+		{
+			name:    "missing replacement",
+			specs:   []string{"networking.example.com/v1beta1"},
+			wantErr: true,
+		},
+		{
+			name:    "identical group-versions",
+			specs:   []string{"apps/v1=apps/v1"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate removed group-version",
+			specs:   []string{"apps/v1beta1=apps/v1", "apps/v1beta1=apps/v1beta2"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCompatibilityShimRules(test.specs)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, wanted error: %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithRemovedAPICompatibility(t *testing.T) {
+	rules := map[schema.GroupVersion]schema.GroupVersion{
+		{Group: "networking.example.com", Version: "v1beta1"}: {Group: "networking.example.com", Version: "v1"},
+	}
+
+	tests := []struct {
+		name            string
+		until           time.Time
+		requestInfo     *apirequest.RequestInfo
+		path            string
+		expectedPath    string
+		expectedVersion string
+		wantWarning     bool
+	}{
+		{
+			name:            "covered namespace is rewritten",
+			until:           time.Now().Add(time.Hour),
+			requestInfo:     &apirequest.RequestInfo{IsResourceRequest: true, APIGroup: "networking.example.com", APIVersion: "v1beta1", Namespace: "legacy", Resource: "gadgets", Verb: "get"},
+			path:            "/apis/networking.example.com/v1beta1/namespaces/legacy/gadgets/foo",
+			expectedPath:    "/apis/networking.example.com/v1/namespaces/legacy/gadgets/foo",
+			expectedVersion: "v1",
+			wantWarning:     true,
+		},
+		{
+			name:         "other namespace passes through",
+			until:        time.Now().Add(time.Hour),
+			requestInfo:  &apirequest.RequestInfo{IsResourceRequest: true, APIGroup: "networking.example.com", APIVersion: "v1beta1", Namespace: "other", Resource: "gadgets", Verb: "get"},
+			path:         "/apis/networking.example.com/v1beta1/namespaces/other/gadgets/foo",
+			expectedPath: "/apis/networking.example.com/v1beta1/namespaces/other/gadgets/foo",
+		},
+		{
+			name:         "cluster-scoped request passes through",
+			until:        time.Now().Add(time.Hour),
+			requestInfo:  &apirequest.RequestInfo{IsResourceRequest: true, APIGroup: "networking.example.com", APIVersion: "v1beta1", Resource: "gadgets", Verb: "list"},
+			path:         "/apis/networking.example.com/v1beta1/gadgets",
+			expectedPath: "/apis/networking.example.com/v1beta1/gadgets",
+		},
+		{
+			name:         "uncovered group-version passes through",
+			until:        time.Now().Add(time.Hour),
+			requestInfo:  &apirequest.RequestInfo{IsResourceRequest: true, APIGroup: "apps", APIVersion: "v1", Namespace: "legacy", Resource: "deployments", Verb: "get"},
+			path:         "/apis/apps/v1/namespaces/legacy/deployments/foo",
+			expectedPath: "/apis/apps/v1/namespaces/legacy/deployments/foo",
+		},
+		{
+			name:         "expired shim is inert",
+			until:        time.Now().Add(-time.Hour),
+			requestInfo:  &apirequest.RequestInfo{IsResourceRequest: true, APIGroup: "networking.example.com", APIVersion: "v1beta1", Namespace: "legacy", Resource: "gadgets", Verb: "get"},
+			path:         "/apis/networking.example.com/v1beta1/namespaces/legacy/gadgets/foo",
+			expectedPath: "/apis/networking.example.com/v1beta1/namespaces/legacy/gadgets/foo",
+		},
+		{
+			name:         "non-resource request passes through",
+			until:        time.Now().Add(time.Hour),
+			requestInfo:  &apirequest.RequestInfo{IsResourceRequest: false, Verb: "get", Path: "/version"},
+			path:         "/version",
+			expectedPath: "/version",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shim := NewCompatibilityShim(rules, []string{"legacy"}, test.until)
+
+			var gotPath, gotVersion string
+			handler := WithRemovedAPICompatibility(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotPath = req.URL.Path
+				if info, ok := apirequest.RequestInfoFrom(req.Context()); ok {
+					gotVersion = info.APIVersion
+				}
+				w.WriteHeader(http.StatusOK)
+			}), shim)
+
+			recorder := &recordingWarningRecorder{}
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			ctx := apirequest.WithRequestInfo(req.Context(), test.requestInfo)
+			ctx = warning.WithWarningRecorder(ctx, recorder)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if gotPath != test.expectedPath {
+				t.Errorf("expected delegated path %q, got %q", test.expectedPath, gotPath)
+			}
+			expectedVersion := test.expectedVersion
+			if len(expectedVersion) == 0 {
+				expectedVersion = test.requestInfo.APIVersion
+			}
+			if gotVersion != expectedVersion {
+				t.Errorf("expected delegated RequestInfo version %q, got %q", expectedVersion, gotVersion)
+			}
+			if gotWarning := len(recorder.warnings) > 0; gotWarning != test.wantWarning {
+				t.Errorf("got warnings %v, wanted a warning: %t", recorder.warnings, test.wantWarning)
+			}
+		})
+	}
+}
+
+type recordingWarningRecorder struct {
+	warnings []string
+}
+
+func (r *recordingWarningRecorder) AddWarning(agent, text string) {
+	r.warnings = append(r.warnings, text)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// mutatingVerbs is the set of request verbs that modify cluster state and
+// are therefore rejected while read-only mode is enabled.
+var mutatingVerbs = sets.NewString("create", "update", "patch", "delete", "deletecollection")
+
+// ReadOnlyMode tracks whether the apiserver should reject mutating requests.
+// It is safe for concurrent use; the enabled state may be flipped at runtime
+// while the exemption list is fixed at construction time.
+//
+// Read-only mode is intended for disaster recovery scenarios, e.g. freezing
+// cluster state during etcd maintenance or a forensic investigation.
+type ReadOnlyMode struct {
+	enabled atomic.Bool
+
+	// exemptResources holds resources (in "resource.group" form, e.g.
+	// "leases.coordination.k8s.io") whose mutating requests are still
+	// admitted while read-only mode is enabled.
+	exemptResources sets.String
+}
+
+// DefaultReadOnlyModeExemptions lists the resources that remain writable in
+// read-only mode unless the operator configures a different set. Leases are
+// exempt so that leader election and node heartbeats keep working; events are
+// exempt so that components can still record what happened during the freeze.
+func DefaultReadOnlyModeExemptions() []string {
+	return []string{
+		"leases.coordination.k8s.io",
+		"events",
+		"events.events.k8s.io",
+	}
+}
+
+// NewReadOnlyMode returns a ReadOnlyMode that starts disabled. The given
+// resources (in "resource.group" form) are exempt from rejection while the
+// mode is enabled.
+func NewReadOnlyMode(exemptResources []string) *ReadOnlyMode {
+	exempt := sets.NewString()
+	for _, gr := range exemptResources {
+		exempt.Insert(schema.ParseGroupResource(gr).String())
+	}
+	return &ReadOnlyMode{exemptResources: exempt}
+}
+
+// Enabled reports whether read-only mode is currently in effect.
+func (m *ReadOnlyMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set toggles read-only mode from a string value ("true" or "false") and is
+// suitable for use with routes.StringFlagPutHandler.
+func (m *ReadOnlyMode) Set(value string) (string, error) {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid read-only mode value %q: %v", value, err)
+	}
+	m.enabled.Store(enabled)
+	return fmt.Sprintf("successfully set read-only mode to %t", enabled), nil
+}
+
+// isExempt returns true if mutating requests for the given resource should be
+// admitted even while read-only mode is enabled.
+func (m *ReadOnlyMode) isExempt(requestInfo *apirequest.RequestInfo) bool {
+	gr := schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource}
+	return m.exemptResources.Has(gr.String())
+}
+
+// WithReadOnlyMode rejects mutating resource requests with a 503 and a
+// 'Retry-After' response header while the given mode is enabled. Non-resource
+// requests and requests for exempt resources pass through unmodified.
+func WithReadOnlyMode(handler http.Handler, mode *ReadOnlyMode) http.Handler {
+	if mode == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !mode.Enabled() {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		requestInfo, ok := apirequest.RequestInfoFrom(req.Context())
+		if !ok {
+			handleError(w, req, fmt.Errorf("no RequestInfo found in context, handler chain must be wrong"))
+			return
+		}
+		if !requestInfo.IsResourceRequest || !mutatingVerbs.Has(requestInfo.Verb) || mode.isExempt(requestInfo) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		gr := schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource}
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, fmt.Sprintf("the apiserver is in read-only mode, %s requests for %s are rejected", requestInfo.Verb, gr.String()), http.StatusServiceUnavailable)
+	})
+}
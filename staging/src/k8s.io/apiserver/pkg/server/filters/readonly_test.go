@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWithReadOnlyMode(t *testing.T) {
+	tests := []struct {
+		name               string
+		enabled            bool
+		requestInfo        *apirequest.RequestInfo
+		expectedStatusCode int
+	}{
+		{
+			name:               "disabled, mutating request admitted",
+			enabled:            false,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "create", Resource: "pods"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "enabled, mutating request rejected",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "create", Resource: "pods"},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "enabled, delete request rejected",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "delete", Resource: "secrets"},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "enabled, read request admitted",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "get", Resource: "pods"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "enabled, watch request admitted",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "watch", Resource: "pods"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "enabled, non-resource request admitted",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: false, Verb: "get", Path: "/version"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "enabled, exempt lease update admitted",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "update", APIGroup: "coordination.k8s.io", Resource: "leases"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "enabled, exempt event creation admitted",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "create", Resource: "events"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "enabled, non-exempt group resource rejected",
+			enabled:            true,
+			requestInfo:        &apirequest.RequestInfo{IsResourceRequest: true, Verb: "create", APIGroup: "apps", Resource: "deployments"},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode := NewReadOnlyMode(DefaultReadOnlyModeExemptions())
+			if test.enabled {
+				if _, err := mode.Set("true"); err != nil {
+					t.Fatalf("unexpected error enabling read-only mode: %v", err)
+				}
+			}
+
+			handler := WithReadOnlyMode(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}), mode)
+
+			req := httptest.NewRequest(http.MethodGet, "/ignored", nil)
+			req = req.WithContext(apirequest.WithRequestInfo(req.Context(), test.requestInfo))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != test.expectedStatusCode {
+				t.Errorf("expected status code %d, got %d", test.expectedStatusCode, w.Code)
+			}
+			if test.expectedStatusCode == http.StatusServiceUnavailable {
+				if retryAfter := w.Header().Get("Retry-After"); retryAfter == "" {
+					t.Errorf("expected a 'Retry-After' response header")
+				}
+			}
+		})
+	}
+}
+
+func TestReadOnlyModeSet(t *testing.T) {
+	mode := NewReadOnlyMode(nil)
+	if mode.Enabled() {
+		t.Errorf("expected read-only mode to start disabled")
+	}
+	if _, err := mode.Set("true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mode.Enabled() {
+		t.Errorf("expected read-only mode to be enabled")
+	}
+	if _, err := mode.Set("false"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mode.Enabled() {
+		t.Errorf("expected read-only mode to be disabled")
+	}
+	if _, err := mode.Set("not-a-bool"); err == nil {
+		t.Errorf("expected an error for an invalid value")
+	}
+}
@@ -18,6 +18,7 @@ package server
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	gpath "path"
 	"strings"
@@ -45,6 +46,7 @@ import (
 	"k8s.io/apiserver/pkg/features"
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/mux"
 	"k8s.io/apiserver/pkg/server/routes"
 	"k8s.io/apiserver/pkg/storageversion"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -199,6 +201,12 @@ type GenericAPIServer struct {
 	livezGracePeriod      time.Duration
 	livezClock            clock.Clock
 
+	// health probes listener, serving the health endpoints outside the secure
+	// serving chain. nil unless Config.HealthProbesListener was set.
+	healthProbesListener    net.Listener
+	healthProbesMaxInFlight int
+	healthProbesMux         *mux.PathRecorderMux
+
 	// auditing. The backend is started before the server starts listening.
 	AuditBackend audit.Backend
 
@@ -635,6 +643,18 @@ func (s preparedGenericAPIServer) NonBlockingRun(stopCh <-chan struct{}, shutdow
 		}
 	}
 
+	if s.healthProbesListener != nil {
+		probeServer := &http.Server{
+			Handler:        probeInFlightLimit(s.healthProbesMux, s.healthProbesMaxInFlight),
+			MaxHeaderBytes: 1 << 20,
+		}
+		klog.Infof("Serving health probes insecurely on %s", s.healthProbesListener.Addr())
+		if _, _, err := RunServer(probeServer, s.healthProbesListener, shutdownTimeout, internalStopCh); err != nil {
+			close(internalStopCh)
+			return nil, nil, err
+		}
+	}
+
 	// Now that listener have bound successfully, it is the
 	// responsibility of the caller to close the provided channel to
 	// ensure cleanup.
@@ -96,6 +96,9 @@ func (s *GenericAPIServer) installHealthz() {
 	defer s.healthzLock.Unlock()
 	s.healthzChecksInstalled = true
 	healthz.InstallHandler(s.Handler.NonGoRestfulMux, s.healthzChecks...)
+	if s.healthProbesMux != nil {
+		healthz.InstallHandler(s.healthProbesMux, s.healthzChecks...)
+	}
 }
 
 // installReadyz creates the readyz endpoint for this server.
@@ -107,6 +110,10 @@ func (s *GenericAPIServer) installReadyz() {
 		// note: InstallReadyzHandlerWithHealthyFunc guarantees that this is called only once
 		s.lifecycleSignals.HasBeenReady.Signal()
 	}, s.readyzChecks...)
+	if s.healthProbesMux != nil {
+		// the HasBeenReady lifecycle signal is owned by the secure handler above
+		healthz.InstallReadyzHandler(s.healthProbesMux, s.readyzChecks...)
+	}
 }
 
 // installLivez creates the livez endpoint for this server.
@@ -115,6 +122,29 @@ func (s *GenericAPIServer) installLivez() {
 	defer s.livezLock.Unlock()
 	s.livezChecksInstalled = true
 	healthz.InstallLivezHandler(s.Handler.NonGoRestfulMux, s.livezChecks...)
+	if s.healthProbesMux != nil {
+		healthz.InstallLivezHandler(s.healthProbesMux, s.livezChecks...)
+	}
+}
+
+// probeInFlightLimit bounds the number of concurrent requests handled by the health
+// probes listener, which runs outside the API priority and fairness machinery. Requests
+// beyond the limit are rejected with 429 rather than queued. A non-positive limit
+// disables the bound.
+func probeInFlightLimit(handler http.Handler, limit int) http.Handler {
+	if limit <= 0 {
+		return handler
+	}
+	inFlight := make(chan struct{}, limit)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case inFlight <- struct{}{}:
+			defer func() { <-inFlight }()
+			handler.ServeHTTP(w, req)
+		default:
+			http.Error(w, "too many probe requests in flight", http.StatusTooManyRequests)
+		}
+	})
 }
 
 // shutdownCheck fails if the embedded channel is closed. This is intended to allow for graceful shutdown sequences
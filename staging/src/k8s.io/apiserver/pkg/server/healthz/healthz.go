@@ -19,6 +19,7 @@ package healthz
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -41,6 +42,15 @@ type HealthChecker interface {
 	Check(req *http.Request) error
 }
 
+// DetailedHealthChecker is an optional interface a HealthChecker can implement
+// to append a short informational string to its line in verbose output, e.g.
+// the version of the backend the check talked to. The detail is only rendered
+// when the request asked for verbose output.
+type DetailedHealthChecker interface {
+	HealthChecker
+	Detail() string
+}
+
 // PingHealthz returns true automatically when checked
 var PingHealthz HealthChecker = ping{}
 
@@ -126,6 +136,12 @@ func NamedCheck(name string, check func(r *http.Request) error) HealthChecker {
 	return &healthzCheck{name, check}
 }
 
+// NamedDetailedCheck is like NamedCheck, but the returned checker also
+// surfaces the string produced by detail in verbose output.
+func NamedDetailedCheck(name string, check func(r *http.Request) error, detail func() string) HealthChecker {
+	return &detailedHealthzCheck{healthzCheck{name, check}, detail}
+}
+
 // InstallHandler registers handlers for health checking on the path
 // "/healthz" to mux. *All handlers* for mux must be specified in
 // exactly one call to InstallHandler. Calling InstallHandler more
@@ -188,7 +204,7 @@ func InstallPathHandlerWithHealthyFunc(mux mux, path string, firstTimeHealthy fu
 			/* removedRelease */ "",
 			handleRootHealth(name, firstTimeHealthy, checks...)))
 	for _, check := range checks {
-		mux.Handle(fmt.Sprintf("%s/%v", path, check.Name()), adaptCheckToHandler(check.Check))
+		mux.Handle(fmt.Sprintf("%s/%v", path, check.Name()), adaptCheckToHandler(check))
 	}
 }
 
@@ -213,6 +229,19 @@ func (c *healthzCheck) Check(r *http.Request) error {
 	return c.check(r)
 }
 
+// detailedHealthzCheck is a healthzCheck with an extra detail function for
+// verbose output.
+type detailedHealthzCheck struct {
+	healthzCheck
+	detail func() string
+}
+
+var _ DetailedHealthChecker = &detailedHealthzCheck{}
+
+func (c *detailedHealthzCheck) Detail() string {
+	return c.detail()
+}
+
 // getExcludedChecks extracts the health check names to be excluded from the query param
 func getExcludedChecks(r *http.Request) sets.String {
 	checks, found := r.URL.Query()["exclude"]
@@ -222,34 +251,103 @@ func getExcludedChecks(r *http.Request) sets.String {
 	return sets.NewString()
 }
 
+// Values of the status fields in the JSON rendering of a health endpoint.
+const (
+	CheckStatusOK       = "ok"
+	CheckStatusFailed   = "failed"
+	CheckStatusExcluded = "excluded"
+)
+
+// CheckStatus describes the outcome of a single health check in the JSON
+// rendering of a health endpoint.
+type CheckStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// LatencyMilliseconds is how long the check took on this request. It is
+	// zero for excluded checks, which are not run.
+	LatencyMilliseconds int64 `json:"latencyMilliseconds"`
+	// Error is the failure reason, when the check failed. It is withheld on
+	// the root endpoints, which are reachable without authorization, and only
+	// populated on the per-check subpaths.
+	Error string `json:"error,omitempty"`
+	// ConsecutiveFailures counts how many times in a row this check has
+	// failed, including this request. It resets when the check passes and is
+	// left unchanged while the check is excluded.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// Detail is the informational string of a DetailedHealthChecker, when the
+	// check provides one.
+	Detail string `json:"detail,omitempty"`
+}
+
+// HealthStatus is the machine-readable response served when a health endpoint
+// is queried with ?format=json.
+type HealthStatus struct {
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Checks []CheckStatus `json:"checks"`
+}
+
 // handleRootHealth returns an http.HandlerFunc that serves the provided checks.
 func handleRootHealth(name string, firstTimeHealthy func(), checks ...HealthChecker) http.HandlerFunc {
 	var notifyOnce sync.Once
+	var failureCountsLock sync.Mutex
+	consecutiveFailures := map[string]int{}
 	return func(w http.ResponseWriter, r *http.Request) {
 		excluded := getExcludedChecks(r)
+		_, verbose := r.URL.Query()["verbose"]
+		wantJSON := r.URL.Query().Get("format") == "json"
 		// failedVerboseLogOutput is for output to the log.  It indicates detailed failed output information for the log.
 		var failedVerboseLogOutput bytes.Buffer
 		var failedChecks []string
 		var individualCheckOutput bytes.Buffer
+		results := make([]CheckStatus, 0, len(checks))
 		for _, check := range checks {
 			// no-op the check if we've specified we want to exclude the check
 			if excluded.Has(check.Name()) {
 				excluded.Delete(check.Name())
 				fmt.Fprintf(&individualCheckOutput, "[+]%s excluded: ok\n", check.Name())
+				failureCountsLock.Lock()
+				failures := consecutiveFailures[check.Name()]
+				failureCountsLock.Unlock()
+				results = append(results, CheckStatus{Name: check.Name(), Status: CheckStatusExcluded, ConsecutiveFailures: failures})
 				continue
 			}
-			if err := check.Check(r); err != nil {
+			start := time.Now()
+			err := check.Check(r)
+			result := CheckStatus{Name: check.Name(), LatencyMilliseconds: time.Since(start).Milliseconds()}
+			if err != nil {
 				slis.ObserveHealthcheck(context.Background(), check.Name(), name, slis.Error)
-				// don't include the error since this endpoint is public.  If someone wants more detail
-				// they should have explicit permission to the detailed checks.
+				// don't include the error since this endpoint is public, in the
+				// JSON rendering as much as in the text one.  If someone wants
+				// more detail they should have explicit permission to the
+				// detailed checks.
 				fmt.Fprintf(&individualCheckOutput, "[-]%s failed: reason withheld\n", check.Name())
 				// but we do want detailed information for our log
 				fmt.Fprintf(&failedVerboseLogOutput, "[-]%s failed: %v\n", check.Name(), err)
 				failedChecks = append(failedChecks, check.Name())
+				result.Status = CheckStatusFailed
+				failureCountsLock.Lock()
+				consecutiveFailures[check.Name()]++
+				result.ConsecutiveFailures = consecutiveFailures[check.Name()]
+				failureCountsLock.Unlock()
 			} else {
 				slis.ObserveHealthcheck(context.Background(), check.Name(), name, slis.Success)
-				fmt.Fprintf(&individualCheckOutput, "[+]%s ok\n", check.Name())
+				detail := ""
+				if detailed, ok := check.(DetailedHealthChecker); ok && (verbose || wantJSON) {
+					detail = detailed.Detail()
+				}
+				if detail != "" && verbose {
+					fmt.Fprintf(&individualCheckOutput, "[+]%s ok: %s\n", check.Name(), detail)
+				} else {
+					fmt.Fprintf(&individualCheckOutput, "[+]%s ok\n", check.Name())
+				}
+				result.Status = CheckStatusOK
+				result.Detail = detail
+				failureCountsLock.Lock()
+				delete(consecutiveFailures, check.Name())
+				failureCountsLock.Unlock()
 			}
+			results = append(results, result)
 		}
 		if excluded.Len() > 0 {
 			fmt.Fprintf(&individualCheckOutput, "warn: some health checks cannot be excluded: no matches for %s\n", formatQuoted(excluded.List()...))
@@ -260,6 +358,10 @@ func handleRootHealth(name string, firstTimeHealthy func(), checks ...HealthChec
 		if len(failedChecks) > 0 {
 			klog.V(2).Infof("%s check failed: %s\n%v", strings.Join(failedChecks, ","), name, failedVerboseLogOutput.String())
 			httplog.SetStacktracePredicate(r.Context(), func(int) bool { return false })
+			if wantJSON {
+				writeHealthStatus(w, http.StatusInternalServerError, &HealthStatus{Name: name, Status: CheckStatusFailed, Checks: results})
+				return
+			}
 			http.Error(w, fmt.Sprintf("%s%s check failed", individualCheckOutput.String(), name), http.StatusInternalServerError)
 			return
 		}
@@ -269,9 +371,14 @@ func handleRootHealth(name string, firstTimeHealthy func(), checks ...HealthChec
 			notifyOnce.Do(firstTimeHealthy)
 		}
 
+		if wantJSON {
+			writeHealthStatus(w, http.StatusOK, &HealthStatus{Name: name, Status: CheckStatusOK, Checks: results})
+			return
+		}
+
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.Header().Set("X-Content-Type-Options", "nosniff")
-		if _, found := r.URL.Query()["verbose"]; !found {
+		if !verbose {
 			fmt.Fprint(w, "ok")
 			return
 		}
@@ -281,10 +388,44 @@ func handleRootHealth(name string, firstTimeHealthy func(), checks ...HealthChec
 	}
 }
 
-// adaptCheckToHandler returns an http.HandlerFunc that serves the provided checks.
-func adaptCheckToHandler(c func(r *http.Request) error) http.HandlerFunc {
+// writeHealthStatus serves a HealthStatus as JSON with the given status code.
+func writeHealthStatus(w http.ResponseWriter, statusCode int, status *HealthStatus) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		klog.Errorf("error encoding health status: %v", err)
+	}
+}
+
+// adaptCheckToHandler returns an http.HandlerFunc that serves the provided check.
+// Unlike the root endpoints, the per-check subpaths require authorization, so
+// the failure reason is included here, in the text as well as the JSON output.
+func adaptCheckToHandler(check HealthChecker) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		err := c(r)
+		start := time.Now()
+		err := check.Check(r)
+		if r.URL.Query().Get("format") == "json" {
+			result := CheckStatus{Name: check.Name(), LatencyMilliseconds: time.Since(start).Milliseconds()}
+			statusCode := http.StatusOK
+			if err != nil {
+				result.Status = CheckStatusFailed
+				result.Error = err.Error()
+				statusCode = http.StatusInternalServerError
+			} else {
+				result.Status = CheckStatusOK
+				if detailed, ok := check.(DetailedHealthChecker); ok {
+					result.Detail = detailed.Detail()
+				}
+			}
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(statusCode)
+			if err := json.NewEncoder(w).Encode(&result); err != nil {
+				klog.Errorf("error encoding check status: %v", err)
+			}
+			return
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("internal server error: %v", err), http.StatusInternalServerError)
 		} else {
@@ -18,6 +18,7 @@ package healthz
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -375,3 +376,142 @@ func (c readyOnChanClose) Check(_ *http.Request) error {
 	}
 	return fmt.Errorf("the provided channel hasn't been closed")
 }
+
+func TestNamedDetailedCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	InstallHandler(mux,
+		PingHealthz,
+		NamedDetailedCheck("detailed", func(r *http.Request) error { return nil }, func() string { return "version=v2, key_id=key-1" }),
+	)
+
+	// without verbose the detail must not appear
+	req, err := http.NewRequest("GET", "http://example.com/healthz", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected %v, got %v", http.StatusOK, w.Code)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("expected %v, got %v", "ok", w.Body.String())
+	}
+
+	req, err = http.NewRequest("GET", "http://example.com/healthz?verbose", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected %v, got %v", http.StatusOK, w.Code)
+	}
+	expected := "[+]ping ok\n[+]detailed ok: version=v2, key_id=key-1\nhealthz check passed\n"
+	if w.Body.String() != expected {
+		t.Errorf("expected %v, got %v", expected, w.Body.String())
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	failing := true
+	mux := http.NewServeMux()
+	InstallHandler(mux,
+		PingHealthz,
+		NamedDetailedCheck("etcd", func(r *http.Request) error { return nil }, func() string { return "version 3.5.6" }),
+		NamedCheck("kms-provider-0", func(r *http.Request) error {
+			if failing {
+				return errors.New("rpc error: code = Unavailable")
+			}
+			return nil
+		}),
+	)
+
+	get := func(url string) (*httptest.ResponseRecorder, HealthStatus) {
+		t.Helper()
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var status HealthStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return w, status
+	}
+
+	byName := func(status HealthStatus, name string) CheckStatus {
+		t.Helper()
+		for _, check := range status.Checks {
+			if check.Name == name {
+				return check
+			}
+		}
+		t.Fatalf("no check named %q in %+v", name, status)
+		return CheckStatus{}
+	}
+
+	w, status := get("http://example.com/healthz?format=json")
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %v, got %v", http.StatusInternalServerError, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+	if status.Name != "healthz" || status.Status != CheckStatusFailed {
+		t.Errorf("unexpected health status %+v", status)
+	}
+	// the root endpoint is reachable without authorization, so the failure
+	// reason is withheld from its JSON output just like from the text output
+	if check := byName(status, "kms-provider-0"); check.Status != CheckStatusFailed || check.Error != "" || check.ConsecutiveFailures != 1 {
+		t.Errorf("unexpected kms-provider-0 result %+v", check)
+	}
+	if check := byName(status, "etcd"); check.Status != CheckStatusOK || check.Detail != "version 3.5.6" || check.ConsecutiveFailures != 0 {
+		t.Errorf("unexpected etcd result %+v", check)
+	}
+
+	// the per-check subpath requires authorization and does include the reason
+	req, err := http.NewRequest("GET", "http://example.com/healthz/kms-provider-0?format=json", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %v, got %v", http.StatusInternalServerError, w.Code)
+	}
+	var checkStatus CheckStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &checkStatus); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if checkStatus.Status != CheckStatusFailed || checkStatus.Error != "rpc error: code = Unavailable" {
+		t.Errorf("unexpected kms-provider-0 subpath result %+v", checkStatus)
+	}
+
+	// a second failure increments the consecutive failure count
+	_, status = get("http://example.com/healthz?format=json")
+	if check := byName(status, "kms-provider-0"); check.ConsecutiveFailures != 2 {
+		t.Errorf("expected 2 consecutive failures, got %+v", check)
+	}
+
+	// excluding the failing check keeps its count but makes the endpoint healthy
+	w, status = get("http://example.com/healthz?format=json&exclude=kms-provider-0")
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %v, got %v", http.StatusOK, w.Code)
+	}
+	if check := byName(status, "kms-provider-0"); check.Status != CheckStatusExcluded || check.ConsecutiveFailures != 2 {
+		t.Errorf("unexpected excluded result %+v", check)
+	}
+
+	// a success resets the count
+	failing = false
+	w, status = get("http://example.com/healthz?format=json")
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %v, got %v", http.StatusOK, w.Code)
+	}
+	if check := byName(status, "kms-provider-0"); check.Status != CheckStatusOK || check.ConsecutiveFailures != 0 || check.Error != "" {
+		t.Errorf("unexpected kms-provider-0 result %+v", check)
+	}
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package server
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	testingclock "k8s.io/utils/clock/testing"
 )
 
@@ -75,3 +79,45 @@ func TestDelayedHealthCheck(t *testing.T) {
 	})
 
 }
+
+func TestProbeInFlightLimit(t *testing.T) {
+	blockCh := make(chan struct{})
+	startedCh := make(chan struct{}, 1)
+	handler := probeInFlightLimit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case startedCh <- struct{}{}:
+		default:
+		}
+		<-blockCh
+	}), 1)
+
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	}()
+	<-startedCh
+
+	// the limit is consumed by the blocked request, the next one must be rejected
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d while at the in-flight limit, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	close(blockCh)
+
+	// once the blocked request finishes, the limit frees up again
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+		return w.Code == http.StatusOK, nil
+	}); err != nil {
+		t.Errorf("expected a request to be admitted after the in-flight request finished: %v", err)
+	}
+}
+
+func TestProbeInFlightLimitDisabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	if got := probeInFlightLimit(handler, 0); fmt.Sprintf("%T", got) != fmt.Sprintf("%T", handler) {
+		t.Errorf("expected a non-positive limit to return the handler unchanged, got %T", got)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// KnownAlgorithms are the algorithm names accepted by the
+// --encryption-allowed-algorithms policy, one per provider type. KMS v1 and
+// v2 are distinguished so a policy can require the v2 API; keyring providers
+// are policed as the AES mode their keys are used with.
+var KnownAlgorithms = []string{
+	"aescbc",
+	"aesgcm",
+	"identity",
+	"kmssplit",
+	"kmsv1",
+	"kmsv2",
+	"secretbox",
+	"tpm",
+	"x25519",
+}
+
+var (
+	allowedAlgorithmsLock sync.RWMutex
+	allowedAlgorithms     sets.String
+)
+
+// SetAllowedAlgorithms installs the process-wide algorithm policy enforced
+// when an encryption configuration is loaded, both at startup and on hot
+// reload. An empty set means no restriction.
+func SetAllowedAlgorithms(algorithms []string) {
+	allowedAlgorithmsLock.Lock()
+	defer allowedAlgorithmsLock.Unlock()
+	allowedAlgorithms = sets.NewString(algorithms...)
+}
+
+var (
+	registerAlgorithmsMetric sync.Once
+
+	encryptionConfigAlgorithmsInUse = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace: "apiserver",
+			Name:      "encryption_config_algorithms_in_use",
+			Help: "Algorithms referenced by the active encryption configuration, one series with value one " +
+				"per algorithm. Operators can alert on algorithms their compliance regime forbids.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"algorithm"},
+	)
+)
+
+// checkAlgorithmPolicy returns an error for every provider in the
+// configuration whose algorithm the installed policy does not permit. A nil
+// error means the configuration may be used.
+func checkAlgorithmPolicy(config *apiserverconfig.EncryptionConfiguration) error {
+	allowedAlgorithmsLock.RLock()
+	allowed := allowedAlgorithms
+	allowedAlgorithmsLock.RUnlock()
+
+	if allowed.Len() == 0 {
+		return nil
+	}
+
+	var violations []string
+	for i, resourceConfig := range config.Resources {
+		for j, provider := range resourceConfig.Providers {
+			algorithm := providerAlgorithm(provider)
+			if allowed.Has(algorithm) {
+				continue
+			}
+			violations = append(violations, fmt.Sprintf("resources[%d].providers[%d] uses %s", i, j, algorithm))
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("encryption configuration violates the algorithm policy (allowed: %s): %s",
+			strings.Join(allowed.List(), ", "), strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// RecordActiveAlgorithms publishes the algorithm set of the configuration the
+// apiserver is actively serving with. Algorithms of a previously active
+// configuration drop out of the metric.
+func RecordActiveAlgorithms(algorithms []string) {
+	registerAlgorithmsMetric.Do(func() {
+		legacyregistry.MustRegister(encryptionConfigAlgorithmsInUse)
+	})
+
+	encryptionConfigAlgorithmsInUse.Reset()
+	for _, algorithm := range algorithms {
+		encryptionConfigAlgorithmsInUse.WithLabelValues(algorithm).Set(1)
+	}
+}
+
+// algorithmsFromConfig returns the sorted set of algorithms the configuration
+// references across all resources, in the terms of KnownAlgorithms.
+func algorithmsFromConfig(config *apiserverconfig.EncryptionConfiguration) []string {
+	algorithms := sets.NewString()
+	for _, resourceConfig := range config.Resources {
+		for _, provider := range resourceConfig.Providers {
+			algorithms.Insert(providerAlgorithm(provider))
+		}
+	}
+	return algorithms.List()
+}
+
+// providerAlgorithm names the algorithm a provider stanza uses, in the terms
+// of KnownAlgorithms.
+func providerAlgorithm(provider apiserverconfig.ProviderConfiguration) string {
+	switch {
+	case provider.AESGCM != nil:
+		return "aesgcm"
+	case provider.AESCBC != nil:
+		return "aescbc"
+	case provider.Secretbox != nil:
+		return "secretbox"
+	case provider.Identity != nil:
+		return "identity"
+	case provider.Keyring != nil:
+		if provider.Keyring.Mode == "aescbc" {
+			return "aescbc"
+		}
+		return "aesgcm"
+	case provider.KMS != nil:
+		if provider.KMS.APIVersion == kmsAPIVersionV2 || provider.KMS.APIVersion == kmsAPIVersionV3 {
+			return "kmsv2"
+		}
+		return "kmsv1"
+	case provider.KMSSplit != nil:
+		return "kmssplit"
+	case provider.TPM != nil:
+		return "tpm"
+	case provider.X25519 != nil:
+		return "x25519"
+	default:
+		return "unknown"
+	}
+}
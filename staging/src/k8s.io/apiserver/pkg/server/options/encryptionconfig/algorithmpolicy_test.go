@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestCheckAlgorithmPolicy(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   string
+		allowed  []string
+		wantErrs []string
+	}{
+		{
+			desc:    "empty policy permits everything",
+			config:  "testdata/valid-configs/secret-box-first.yaml",
+			allowed: nil,
+		},
+		{
+			desc:    "policy covering the config",
+			config:  "testdata/valid-configs/aes/aes-gcm.yaml",
+			allowed: []string{"aesgcm", "identity"},
+		},
+		{
+			desc:    "forbidden algorithms are named with their path",
+			config:  "testdata/valid-configs/secret-box-first.yaml",
+			allowed: []string{"kmsv2", "aesgcm"},
+			wantErrs: []string{
+				"(allowed: aesgcm, kmsv2)",
+				"resources[0].providers[0] uses secretbox",
+				"resources[0].providers[4] uses identity",
+			},
+		},
+		{
+			desc:    "kms v1 is distinguished from kms v2",
+			config:  "testdata/valid-configs/kms/default-timeout.yaml",
+			allowed: []string{"kmsv2"},
+			wantErrs: []string{
+				"uses kmsv1",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			SetAllowedAlgorithms(tt.allowed)
+			defer SetAllowedAlgorithms(nil)
+
+			config, _, err := loadConfig(tt.config, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkAlgorithmPolicy(config)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("checkAlgorithmPolicy(%q) returned unexpected error: %v", tt.config, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("checkAlgorithmPolicy(%q) expected error containing %q, got none", tt.config, tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("checkAlgorithmPolicy(%q) error %q does not contain %q", tt.config, err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestAlgorithmsFromConfig(t *testing.T) {
+	config, _, err := loadConfig("testdata/valid-configs/secret-box-first.yaml", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	algorithms := algorithmsFromConfig(config)
+	expected := []string{"aescbc", "aesgcm", "identity", "kmsv1", "kmsv2", "secretbox"}
+	if !reflect.DeepEqual(expected, algorithms) {
+		t.Errorf("expected algorithms %v, got %v", expected, algorithms)
+	}
+}
+
+func TestRecordActiveAlgorithms(t *testing.T) {
+	RecordActiveAlgorithms([]string{"aesgcm", "kmsv2"})
+
+	expected := `
+	# HELP apiserver_encryption_config_algorithms_in_use [ALPHA] Algorithms referenced by the active encryption configuration, one series with value one per algorithm. Operators can alert on algorithms their compliance regime forbids.
+	# TYPE apiserver_encryption_config_algorithms_in_use gauge
+	apiserver_encryption_config_algorithms_in_use{algorithm="aesgcm"} 1
+	apiserver_encryption_config_algorithms_in_use{algorithm="kmsv2"} 1
+	`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_encryption_config_algorithms_in_use"); err != nil {
+		t.Error(err)
+	}
+
+	// a reload that drops an algorithm must drop its series
+	RecordActiveAlgorithms([]string{"kmsv2"})
+
+	expected = `
+	# HELP apiserver_encryption_config_algorithms_in_use [ALPHA] Algorithms referenced by the active encryption configuration, one series with value one per algorithm. Operators can alert on algorithms their compliance regime forbids.
+	# TYPE apiserver_encryption_config_algorithms_in_use gauge
+	apiserver_encryption_config_algorithms_in_use{algorithm="kmsv2"} 1
+	`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_encryption_config_algorithms_in_use"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadEncryptionConfigEnforcesAlgorithmPolicy(t *testing.T) {
+	SetAllowedAlgorithms([]string{"kmsv2"})
+	defer SetAllowedAlgorithms(nil)
+
+	_, err := LoadEncryptionConfig("testdata/valid-configs/aes/aes-gcm.yaml", false, testContext(t).Done())
+	if err == nil {
+		t.Fatal("expected the algorithm policy to reject the configuration")
+	}
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Reason != ReloadFailureReasonValidation {
+		t.Errorf("expected a validation ConfigError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "violates the algorithm policy") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+)
+
+type failingTransformer struct{}
+
+func (failingTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	return nil, false, errors.New("decrypt failed")
+}
+
+func (failingTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return nil, errors.New("encrypt failed")
+}
+
+func TestCanaryRoundTrip(t *testing.T) {
+	block, err := aes.NewCipher([]byte("abcdefghijklmnopqrstuvwxyz123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transformer := value.NewPrefixTransformers(fmt.Errorf("no matching prefix found"), value.PrefixTransformer{
+		Transformer: aestransformer.NewGCMTransformer(block),
+		Prefix:      []byte(kmsTransformerPrefixV2 + "provider-1:"),
+	})
+
+	if err := canaryRoundTrip(context.Background(), "provider-1", transformer); err != nil {
+		t.Errorf("expected canary round-trip to succeed, got %v", err)
+	}
+
+	if err := canaryRoundTrip(context.Background(), "provider-1", failingTransformer{}); err == nil {
+		t.Error("expected canary round-trip through a failing transformer to fail")
+	}
+}
+
+func TestCanaryErrorSurfacedInHealthz(t *testing.T) {
+	probe := &kmsv2PluginProbe{
+		name:         "provider-1",
+		transformer:  failingTransformer{},
+		l:            &sync.Mutex{},
+		lastResponse: &kmsPluginHealthzResponse{},
+	}
+
+	// before the first round-trip the canary must not fail the health check
+	if err := probe.canaryError(); err != nil {
+		t.Errorf("expected no canary error before the first round-trip, got %v", err)
+	}
+
+	probe.runCanary(context.Background())
+	if err := probe.canaryError(); err == nil {
+		t.Error("expected a canary error after a failed round-trip")
+	}
+}
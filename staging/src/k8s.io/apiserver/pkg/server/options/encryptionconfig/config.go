@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
@@ -27,6 +28,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -36,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
 	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
@@ -48,20 +51,36 @@ import (
 	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
 	"k8s.io/apiserver/pkg/storage/value/encrypt/identity"
 	"k8s.io/apiserver/pkg/storage/value/encrypt/secretbox"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/tpm"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/x25519"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	aesCBCTransformerPrefixV1    = "k8s:enc:aescbc:v1:"
 	aesGCMTransformerPrefixV1    = "k8s:enc:aesgcm:v1:"
 	secretboxTransformerPrefixV1 = "k8s:enc:secretbox:v1:"
+	tpmTransformerPrefixV1       = "k8s:enc:tpm:v1:"
+	x25519TransformerPrefixV1    = "k8s:enc:x25519:v1:"
 	kmsTransformerPrefixV1       = "k8s:enc:kms:v1:"
 	kmsTransformerPrefixV2       = "k8s:enc:kms:v2:"
+	kmsSplitTransformerPrefixV1  = "k8s:enc:kmssplit:v1:"
 	kmsPluginHealthzNegativeTTL  = 3 * time.Second
 	kmsPluginHealthzPositiveTTL  = 20 * time.Second
 	kmsAPIVersionV1              = "v1"
 	kmsAPIVersionV2              = "v2"
+	kmsAPIVersionV3              = "v3"
 	kmsReloadHealthCheckName     = "kms-providers"
+
+	// kmsBatchMaxSize and kmsBatchLinger bound how v3 plugins coalesce
+	// concurrent DEK operations into batch RPCs: a batch is sent once it
+	// holds kmsBatchMaxSize operations or kmsBatchLinger has passed since
+	// its first one, so an isolated call pays at most the linger in extra
+	// latency.
+	kmsBatchMaxSize = 32
+	kmsBatchLinger  = 2 * time.Millisecond
 )
 
 type kmsPluginHealthzResponse struct {
@@ -74,15 +93,31 @@ type kmsPluginProbe struct {
 	ttl          time.Duration
 	service      envelope.Service
 	lastResponse *kmsPluginHealthzResponse
-	l            *sync.Mutex
+	// transformer is the provider's full prefix transformer chain, used for
+	// the periodic canary record round-trips.
+	transformer value.Transformer
+	// lastCanary is the result of the most recent canary round-trip.
+	lastCanary *kmsPluginHealthzResponse
+	l          *sync.Mutex
 }
 
 type kmsv2PluginProbe struct {
-	name         string
+	name string
+	// apiVersion is the KMS API version the plugin is expected to report in
+	// its Status responses. Empty means envelopekmsv2.KMSAPIVersion.
+	apiVersion   string
 	ttl          time.Duration
 	service      envelopekmsv2.Service
 	lastResponse *kmsPluginHealthzResponse
-	l            *sync.Mutex
+	// lastStatus is the most recent Status response from the plugin, kept so
+	// the plugin version and key id can be surfaced in verbose healthz output.
+	lastStatus *envelopekmsv2.StatusResponse
+	// transformer is the provider's full prefix transformer chain, used for
+	// the periodic canary record round-trips.
+	transformer value.Transformer
+	// lastCanary is the result of the most recent canary round-trip.
+	lastCanary *kmsPluginHealthzResponse
+	l          *sync.Mutex
 }
 
 type kmsHealthChecker []healthz.HealthChecker
@@ -104,16 +139,51 @@ func (k kmsHealthChecker) Check(req *http.Request) error {
 	return utilerrors.Reduce(utilerrors.NewAggregate(errs))
 }
 
+// Detail aggregates the details of the underlying checks for verbose healthz
+// output.
+func (k kmsHealthChecker) Detail() string {
+	var details []string
+
+	for i := range k {
+		if detailed, ok := k[i].(healthz.DetailedHealthChecker); ok {
+			if detail := detailed.Detail(); detail != "" {
+				details = append(details, fmt.Sprintf("%s(%s)", detailed.Name(), detail))
+			}
+		}
+	}
+
+	return strings.Join(details, ", ")
+}
+
 func (h *kmsPluginProbe) toHealthzCheck(idx int) healthz.HealthChecker {
 	return healthz.NamedCheck(fmt.Sprintf("kms-provider-%d", idx), func(r *http.Request) error {
-		return h.check()
+		if err := h.check(); err != nil {
+			return err
+		}
+		return h.canaryError()
 	})
 }
 
 func (h *kmsv2PluginProbe) toHealthzCheck(idx int) healthz.HealthChecker {
-	return healthz.NamedCheck(fmt.Sprintf("kms-provider-%d", idx), func(r *http.Request) error {
-		return h.check(r.Context())
-	})
+	return healthz.NamedDetailedCheck(fmt.Sprintf("kms-provider-%d", idx), func(r *http.Request) error {
+		if err := h.check(r.Context()); err != nil {
+			return err
+		}
+		return h.canaryError()
+	}, h.detail)
+}
+
+// detail reports the plugin version and key id from the most recent Status
+// response, so a plugin upgrade or key rotation can be confirmed from verbose
+// healthz output alone.
+func (h *kmsv2PluginProbe) detail() string {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	if h.lastStatus == nil {
+		return ""
+	}
+	return fmt.Sprintf("name=%s, version=%s, key_id=%s", h.name, h.lastStatus.Version, h.lastStatus.KeyID)
 }
 
 // EncryptionConfiguration represents the parsed and normalized encryption configuration for the apiserver.
@@ -127,23 +197,62 @@ type EncryptionConfiguration struct {
 	// EncryptionFileContentHash is the hash of the encryption config file.
 	EncryptionFileContentHash string
 
+	// WriteProviders maps each configured resource to the provider that new
+	// writes are encrypted with, i.e. the first provider in its chain.
+	WriteProviders map[schema.GroupResource]string
+
+	// Providers is the sorted set of provider names the configuration
+	// references across all resources.
+	Providers []string
+
+	// Algorithms is the sorted set of algorithms the configuration references
+	// across all resources, in the terms of KnownAlgorithms.
+	Algorithms []string
+
 	// KMSCloseGracePeriod is the duration we will wait before closing old transformers.
 	// We wait for any in-flight requests to finish by using the duration which is longer than their timeout.
 	KMSCloseGracePeriod time.Duration
 }
 
+// ConfigError wraps an error that rejected an encryption configuration with
+// the failure class the reload failure metric reports.
+type ConfigError struct {
+	// Reason is one of the ReloadFailureReason constants.
+	Reason string
+	Err    error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// ConfigContentHash returns the hash identifying the configuration file at
+// filepath, including referenced external files, without constructing
+// transformers. It is the empty string when the file cannot be read or
+// decoded. It allows attributing a rejected reload to the configuration that
+// was attempted.
+func ConfigContentHash(filepath string) string {
+	_, contentHash, _ := loadConfig(filepath, true)
+	return contentHash
+}
+
 // LoadEncryptionConfig parses and validates the encryption config specified by filepath.
 // It may launch multiple go routines whose lifecycle is controlled by stopCh.
 // If reload is true, or KMS v2 plugins are used with no KMS v1 plugins, the returned slice of health checkers will always be of length 1.
 func LoadEncryptionConfig(filepath string, reload bool, stopCh <-chan struct{}) (*EncryptionConfiguration, error) {
 	config, contentHash, err := loadConfig(filepath, reload)
 	if err != nil {
-		return nil, fmt.Errorf("error while parsing file: %w", err)
+		return nil, &ConfigError{Reason: ReloadFailureReasonValidation, Err: fmt.Errorf("error while parsing file: %w", err)}
+	}
+
+	// enforced here so hot reloads are policed the same way as the startup load
+	if err := checkAlgorithmPolicy(config); err != nil {
+		return nil, &ConfigError{Reason: ReloadFailureReasonValidation, Err: err}
 	}
 
 	transformers, kmsHealthChecks, kmsUsed, err := getTransformerOverridesAndKMSPluginHealthzCheckers(config, stopCh)
 	if err != nil {
-		return nil, fmt.Errorf("error while building transformers: %w", err)
+		return nil, &ConfigError{Reason: ReloadFailureReasonPlugin, Err: fmt.Errorf("error while building transformers: %w", err)}
 	}
 
 	if reload || (kmsUsed.v2Used && !kmsUsed.v1Used) {
@@ -159,10 +268,72 @@ func LoadEncryptionConfig(filepath string, reload bool, stopCh <-chan struct{})
 		Transformers:              transformers,
 		HealthChecks:              kmsHealthChecks,
 		EncryptionFileContentHash: contentHash,
+		WriteProviders:            writeProvidersFromConfig(config),
+		Providers:                 providersFromConfig(config),
+		Algorithms:                algorithmsFromConfig(config),
 		KMSCloseGracePeriod:       2 * kmsUsed.kmsTimeoutSum,
 	}, err
 }
 
+// providersFromConfig returns the sorted set of provider names the
+// configuration references across all resources.
+func providersFromConfig(config *apiserverconfig.EncryptionConfiguration) []string {
+	providers := sets.NewString()
+	for _, resourceConfig := range config.Resources {
+		for _, provider := range resourceConfig.Providers {
+			providers.Insert(providerName(provider))
+		}
+	}
+	return providers.List()
+}
+
+// writeProvidersFromConfig maps each resource to the human readable name of the
+// provider that new writes are encrypted with. When a resource is listed more
+// than once, the first entry wins, matching how the transformer chain is built.
+func writeProvidersFromConfig(config *apiserverconfig.EncryptionConfiguration) map[schema.GroupResource]string {
+	writeProviders := map[schema.GroupResource]string{}
+	for _, resourceConfig := range config.Resources {
+		if len(resourceConfig.Providers) == 0 {
+			continue
+		}
+		name := providerName(resourceConfig.Providers[0])
+		for _, resource := range resourceConfig.Resources {
+			gr := schema.ParseGroupResource(resource)
+			if _, ok := writeProviders[gr]; !ok {
+				writeProviders[gr] = name
+			}
+		}
+	}
+	return writeProviders
+}
+
+// providerName returns a human readable name for the given provider, suitable
+// for logs and events.
+func providerName(provider apiserverconfig.ProviderConfiguration) string {
+	switch {
+	case provider.AESGCM != nil:
+		return "aesgcm"
+	case provider.AESCBC != nil:
+		return "aescbc"
+	case provider.Secretbox != nil:
+		return "secretbox"
+	case provider.KMS != nil:
+		return fmt.Sprintf("kms:%s:%s", provider.KMS.APIVersion, provider.KMS.Name)
+	case provider.TPM != nil:
+		return "tpm"
+	case provider.X25519 != nil:
+		return "x25519"
+	case provider.Keyring != nil:
+		return "keyring"
+	case provider.KMSSplit != nil:
+		return fmt.Sprintf("kmssplit:%s", provider.KMSSplit.Name)
+	case provider.Identity != nil:
+		return "identity"
+	default:
+		return "unknown"
+	}
+}
+
 func getTransformerOverridesAndKMSPluginHealthzCheckers(config *apiserverconfig.EncryptionConfiguration, stopCh <-chan struct{}) (map[schema.GroupResource]value.Transformer, []healthz.HealthChecker, *kmsState, error) {
 	var kmsHealthChecks []healthz.HealthChecker
 	transformers, probes, kmsUsed, err := getTransformerOverridesAndKMSPluginProbes(config, stopCh)
@@ -186,14 +357,24 @@ func getTransformerOverridesAndKMSPluginProbes(config *apiserverconfig.Encryptio
 	var probes []healthChecker
 	var kmsUsed kmsState
 
+	// Transformers registered in-process are appended to the chain of every
+	// resource, after the providers from the config file.
+	custom, err := customPrefixTransformers(stopCh)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	revoked := newRevocationSet(config.Revocations)
+
 	// For each entry in the configuration
 	for _, resourceConfig := range config.Resources {
 		resourceConfig := resourceConfig
 
-		transformers, p, used, err := prefixTransformersAndProbes(resourceConfig, stopCh)
+		transformers, p, used, err := prefixTransformersAndProbes(resourceConfig, revoked, stopCh)
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		transformers = append(transformers, custom...)
 		kmsUsed.v1Used = kmsUsed.v1Used || used.v1Used
 		kmsUsed.v2Used = kmsUsed.v2Used || used.v2Used
 
@@ -233,17 +414,20 @@ func (h *kmsPluginProbe) check() error {
 	if err != nil {
 		h.lastResponse = &kmsPluginHealthzResponse{err: err, received: time.Now()}
 		h.ttl = kmsPluginHealthzNegativeTTL
+		recordKMSProviderProbe(h.name, "", err)
 		return fmt.Errorf("failed to perform encrypt section of the healthz check for KMS Provider %s, error: %w", h.name, err)
 	}
 
 	if _, err := h.service.Decrypt(p); err != nil {
 		h.lastResponse = &kmsPluginHealthzResponse{err: err, received: time.Now()}
 		h.ttl = kmsPluginHealthzNegativeTTL
+		recordKMSProviderProbe(h.name, "", err)
 		return fmt.Errorf("failed to perform decrypt section of the healthz check for KMS Provider %s, error: %w", h.name, err)
 	}
 
 	h.lastResponse = &kmsPluginHealthzResponse{err: nil, received: time.Now()}
 	h.ttl = kmsPluginHealthzPositiveTTL
+	recordKMSProviderProbe(h.name, "", nil)
 	return nil
 }
 
@@ -260,28 +444,36 @@ func (h *kmsv2PluginProbe) check(ctx context.Context) error {
 	if err != nil {
 		h.lastResponse = &kmsPluginHealthzResponse{err: err, received: time.Now()}
 		h.ttl = kmsPluginHealthzNegativeTTL
+		recordKMSProviderProbe(h.name, "", err)
 		return fmt.Errorf("failed to perform status section of the healthz check for KMS Provider %s, error: %w", h.name, err)
 	}
+	h.lastStatus = p
 
-	if err := isKMSv2ProviderHealthy(h.name, p); err != nil {
+	expectedVersion := h.apiVersion
+	if expectedVersion == "" {
+		expectedVersion = envelopekmsv2.KMSAPIVersion
+	}
+	if err := isKMSv2ProviderHealthy(h.name, p, expectedVersion); err != nil {
 		h.lastResponse = &kmsPluginHealthzResponse{err: err, received: time.Now()}
 		h.ttl = kmsPluginHealthzNegativeTTL
+		recordKMSProviderProbe(h.name, p.KeyID, err)
 		return err
 	}
 
 	h.lastResponse = &kmsPluginHealthzResponse{err: nil, received: time.Now()}
 	h.ttl = kmsPluginHealthzPositiveTTL
+	recordKMSProviderProbe(h.name, p.KeyID, nil)
 	return nil
 }
 
 // isKMSv2ProviderHealthy checks if the KMSv2-Plugin is healthy.
-func isKMSv2ProviderHealthy(name string, response *envelopekmsv2.StatusResponse) error {
+func isKMSv2ProviderHealthy(name string, response *envelopekmsv2.StatusResponse, expectedVersion string) error {
 	var errs []error
 	if response.Healthz != "ok" {
 		errs = append(errs, fmt.Errorf("got unexpected healthz status: %s", response.Healthz))
 	}
-	if response.Version != envelopekmsv2.KMSAPIVersion {
-		errs = append(errs, fmt.Errorf("expected KMSv2 API version %s, got %s", envelopekmsv2.KMSAPIVersion, response.Version))
+	if response.Version != expectedVersion {
+		errs = append(errs, fmt.Errorf("expected KMSv2 API version %s, got %s", expectedVersion, response.Version))
 	}
 	if len(response.KeyID) == 0 {
 		errs = append(errs, fmt.Errorf("expected KMSv2 KeyID to be set, got %s", response.KeyID))
@@ -323,10 +515,19 @@ func loadConfig(filepath string, reload bool) (*apiserverconfig.EncryptionConfig
 		return nil, "", fmt.Errorf("got unexpected config type: %v", gvk)
 	}
 
+	// An edit to a referenced keyring or key file must change the computed
+	// hash or the reload controller will skip it, so fold the contents of
+	// those files into the hashed bytes.
+	for _, externalFile := range externalFilePaths(config) {
+		if fileData, err := os.ReadFile(externalFile); err == nil {
+			data = append(data, fileData...)
+		}
+	}
+
 	return config, computeEncryptionConfigHash(data), validation.ValidateEncryptionConfiguration(config, reload).ToAggregate()
 }
 
-func prefixTransformersAndProbes(config apiserverconfig.ResourceConfiguration, stopCh <-chan struct{}) ([]value.PrefixTransformer, []healthChecker, *kmsState, error) {
+func prefixTransformersAndProbes(config apiserverconfig.ResourceConfiguration, revoked *revocationSet, stopCh <-chan struct{}) ([]value.PrefixTransformer, []healthChecker, *kmsState, error) {
 	var transformers []value.PrefixTransformer
 	var probes []healthChecker
 	var kmsUsed kmsState
@@ -351,7 +552,7 @@ func prefixTransformersAndProbes(config apiserverconfig.ResourceConfiguration, s
 			transformer, transformerErr = secretboxPrefixTransformer(provider.Secretbox)
 
 		case provider.KMS != nil:
-			transformer, probe, used, transformerErr = kmsPrefixTransformer(provider.KMS, stopCh)
+			transformer, probe, used, transformerErr = kmsPrefixTransformer(provider.KMS, revoked, stopCh)
 			if transformerErr == nil {
 				probes = append(probes, probe)
 				kmsUsed.v1Used = kmsUsed.v1Used || used.v1Used
@@ -361,6 +562,23 @@ func prefixTransformersAndProbes(config apiserverconfig.ResourceConfiguration, s
 				kmsUsed.kmsTimeoutSum += used.kmsTimeoutSum
 			}
 
+		case provider.TPM != nil:
+			transformer, transformerErr = tpmPrefixTransformer(provider.TPM)
+
+		case provider.X25519 != nil:
+			transformer, transformerErr = x25519PrefixTransformer(provider.X25519)
+
+		case provider.Keyring != nil:
+			transformer, transformerErr = keyringPrefixTransformer(provider.Keyring)
+
+		case provider.KMSSplit != nil:
+			transformer, probe, used, transformerErr = kmsSplitPrefixTransformer(provider.KMSSplit, stopCh)
+			if transformerErr == nil {
+				probes = append(probes, probe)
+				kmsUsed.v1Used = kmsUsed.v1Used || used.v1Used
+				kmsUsed.kmsTimeoutSum += used.kmsTimeoutSum
+			}
+
 		case provider.Identity != nil:
 			transformer = value.PrefixTransformer{
 				Transformer: identity.NewEncryptCheckTransformer(),
@@ -368,14 +586,41 @@ func prefixTransformersAndProbes(config apiserverconfig.ResourceConfiguration, s
 			}
 
 		default:
-			return nil, nil, nil, errors.New("provider does not contain any of the expected providers: KMS, AESGCM, AESCBC, Secretbox, Identity")
+			return nil, nil, nil, errors.New("provider does not contain any of the expected providers: KMS, AESGCM, AESCBC, Secretbox, Identity, TPM, X25519, Keyring, KMSSplit")
 		}
 
 		if transformerErr != nil {
 			return nil, nil, nil, transformerErr
 		}
 
+		// a revoked provider may stay configured (validation keeps it out of
+		// the write position) so records it still protects fail with the
+		// distinct revoked-key error rather than the unknown-prefix one.
+		switch {
+		case provider.KMS != nil && revoked.providerRevoked(provider.KMS.Name):
+			transformer.Transformer = &revokedProviderTransformer{name: provider.KMS.Name}
+		case provider.KMSSplit != nil && revoked.providerRevoked(provider.KMSSplit.Name):
+			transformer.Transformer = &revokedProviderTransformer{name: provider.KMSSplit.Name}
+		}
+
 		transformers = append(transformers, transformer)
+
+		// records written before a kms provider rename keep the old name in their
+		// prefix. Aliases decrypt those records through the renamed provider and,
+		// because they are never the first transformer, mark them stale so they
+		// are rewritten under the current name on their next update.
+		if provider.KMS != nil {
+			prefix := kmsTransformerPrefixV1
+			if provider.KMS.APIVersion == kmsAPIVersionV2 || provider.KMS.APIVersion == kmsAPIVersionV3 {
+				prefix = kmsTransformerPrefixV2
+			}
+			for _, alias := range provider.KMS.Aliases {
+				transformers = append(transformers, value.PrefixTransformer{
+					Transformer: transformer.Transformer,
+					Prefix:      []byte(prefix + alias + ":"),
+				})
+			}
+		}
 	}
 
 	return transformers, probes, &kmsUsed, nil
@@ -383,6 +628,19 @@ func prefixTransformersAndProbes(config apiserverconfig.ResourceConfiguration, s
 
 type blockTransformerFunc func(cipher.Block) value.Transformer
 
+// keySecret returns the base64 encoded secret of a key, reading it from the
+// referenced file when the key uses secretFile instead of an inline secret.
+func keySecret(key apiserverconfig.Key) (string, error) {
+	if key.SecretFile == "" {
+		return key.Secret, nil
+	}
+	data, err := os.ReadFile(key.SecretFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file %q for named key %s: %w", key.SecretFile, key.Name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
 func aesPrefixTransformer(config *apiserverconfig.AESConfiguration, fn blockTransformerFunc, prefix string) (value.PrefixTransformer, error) {
 	var result value.PrefixTransformer
 
@@ -394,7 +652,7 @@ func aesPrefixTransformer(config *apiserverconfig.AESConfiguration, fn blockTran
 		if key.Name == "" {
 			return result, fmt.Errorf("key with invalid name provided")
 		}
-		if key.Secret == "" {
+		if key.Secret == "" && key.SecretFile == "" {
 			return result, fmt.Errorf("key %v has no provided secret", key.Name)
 		}
 	}
@@ -403,7 +661,11 @@ func aesPrefixTransformer(config *apiserverconfig.AESConfiguration, fn blockTran
 
 	for _, keyData := range config.Keys {
 		keyData := keyData
-		key, err := base64.StdEncoding.DecodeString(keyData.Secret)
+		secret, err := keySecret(keyData)
+		if err != nil {
+			return result, err
+		}
+		key, err := base64.StdEncoding.DecodeString(secret)
 		if err != nil {
 			return result, fmt.Errorf("could not obtain secret for named key %s: %s", keyData.Name, err)
 		}
@@ -432,6 +694,199 @@ func aesPrefixTransformer(config *apiserverconfig.AESConfiguration, fn blockTran
 	return result, nil
 }
 
+// unsealKey is the function used to unseal TPM protected keys. This is to make writing test easier.
+var unsealKey = tpm.UnsealKey
+
+// dekCacheKey resolves the key that seals a persisted DEK cache: either a
+// TPM-sealed blob unsealed through the device, or a key file, at which a
+// fresh key is generated on first use so operators can point it at tmpfs.
+func dekCacheKey(config *apiserverconfig.DEKCacheConfiguration) ([]byte, error) {
+	if len(config.SealedKeyFile) != 0 {
+		sealedKey, err := os.ReadFile(config.SealedKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read sealed key file %q: %w", config.SealedKeyFile, err)
+		}
+		devicePath := config.DevicePath
+		if len(devicePath) == 0 {
+			devicePath = tpm.DefaultDevicePath
+		}
+		return unsealKey(devicePath, sealedKey, config.PCRs)
+	}
+
+	key, err := os.ReadFile(config.KeyFile)
+	if err == nil {
+		switch len(key) {
+		case 16, 24, 32:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("key file %q has invalid length %d, expected one of [16 24 32]", config.KeyFile, len(key))
+		}
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read key file %q: %w", config.KeyFile, err)
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(config.KeyFile, key, 0600); err != nil {
+		return nil, fmt.Errorf("could not write key file %q: %w", config.KeyFile, err)
+	}
+	return key, nil
+}
+
+func tpmPrefixTransformer(config *apiserverconfig.TPMConfiguration) (value.PrefixTransformer, error) {
+	var result value.PrefixTransformer
+
+	sealedKey, err := os.ReadFile(config.SealedKeyFile)
+	if err != nil {
+		return result, fmt.Errorf("could not read sealed key file %q for TPM provider %s: %w", config.SealedKeyFile, config.Name, err)
+	}
+
+	key, err := unsealKey(config.DevicePath, sealedKey, config.PCRs)
+	if err != nil {
+		return result, fmt.Errorf("could not unseal key for TPM provider %s: %w", config.Name, err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return result, fmt.Errorf("error while creating cipher for TPM provider %s: %w", config.Name, err)
+	}
+
+	var transformer value.Transformer
+	switch config.Mode {
+	case "aesgcm":
+		transformer = aestransformer.NewGCMTransformer(block)
+	case "aescbc":
+		transformer = aestransformer.NewCBCTransformer(block)
+	default:
+		return result, fmt.Errorf("unsupported mode %q for TPM provider %s", config.Mode, config.Name)
+	}
+
+	result = value.PrefixTransformer{
+		Transformer: transformer,
+		Prefix:      []byte(tpmTransformerPrefixV1 + config.Name + ":"),
+	}
+	return result, nil
+}
+
+func x25519PrefixTransformer(config *apiserverconfig.X25519Configuration) (value.PrefixTransformer, error) {
+	var result value.PrefixTransformer
+
+	recipients := make([][]byte, 0, len(config.Recipients))
+	for i, recipient := range config.Recipients {
+		key, err := base64.StdEncoding.DecodeString(recipient)
+		if err != nil {
+			return result, fmt.Errorf("could not decode recipient %d for x25519 provider %s: %w", i, config.Name, err)
+		}
+		recipients = append(recipients, key)
+	}
+
+	transformer, err := x25519.NewWriteOnlyTransformer(recipients)
+	if err != nil {
+		return result, fmt.Errorf("error while creating transformer for x25519 provider %s: %w", config.Name, err)
+	}
+
+	result = value.PrefixTransformer{
+		Transformer: transformer,
+		Prefix:      []byte(x25519TransformerPrefixV1 + config.Name + ":"),
+	}
+	return result, nil
+}
+
+// loadKeyring reads the keyring file at path, a YAML list of name/secret
+// pairs. The keys are returned newest first: the last key appended to the
+// file becomes the write key, so rotating is appending a key and reloading.
+func loadKeyring(path string) ([]apiserverconfig.Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading keyring file %q: %w", path, err)
+	}
+
+	var fileKeys []apiserverconfigv1.Key
+	if err := yaml.Unmarshal(data, &fileKeys); err != nil {
+		return nil, fmt.Errorf("error parsing keyring file %q: %w", path, err)
+	}
+	if len(fileKeys) == 0 {
+		return nil, fmt.Errorf("keyring file %q contains no keys", path)
+	}
+
+	seenNames := map[string]bool{}
+	keys := make([]apiserverconfig.Key, 0, len(fileKeys))
+	for i := len(fileKeys) - 1; i >= 0; i-- {
+		keyData := fileKeys[i]
+		if seenNames[keyData.Name] {
+			return nil, fmt.Errorf("duplicate key name %q in keyring file %q", keyData.Name, path)
+		}
+		seenNames[keyData.Name] = true
+		keys = append(keys, apiserverconfig.Key{Name: keyData.Name, Secret: keyData.Secret})
+	}
+	return keys, nil
+}
+
+func keyringPrefixTransformer(config *apiserverconfig.KeyringConfiguration) (value.PrefixTransformer, error) {
+	var result value.PrefixTransformer
+
+	keys, err := loadKeyring(config.Path)
+	if err != nil {
+		return result, err
+	}
+
+	// Records written by a keyring provider use the plain AES prefixes, so
+	// moving keys between an inline keys list and a keyring file never
+	// requires rewriting stored data. A key dropped from the keyring leaves
+	// its records unreadable: reads fail with the no-matching-key error.
+	aesConfig := &apiserverconfig.AESConfiguration{Keys: keys}
+	switch config.Mode {
+	case "aesgcm", "":
+		return aesPrefixTransformer(aesConfig, aestransformer.NewGCMTransformer, aesGCMTransformerPrefixV1)
+	case "aescbc":
+		return aesPrefixTransformer(aesConfig, aestransformer.NewCBCTransformer, aesCBCTransformerPrefixV1)
+	default:
+		return result, fmt.Errorf("unsupported mode %q for keyring provider", config.Mode)
+	}
+}
+
+// externalFilePaths returns the paths of all files referenced by the
+// encryption configuration: keyring files and key secret files.
+func externalFilePaths(config *apiserverconfig.EncryptionConfiguration) []string {
+	var paths []string
+	appendKeyFiles := func(keys []apiserverconfig.Key) {
+		for _, key := range keys {
+			if key.SecretFile != "" {
+				paths = append(paths, key.SecretFile)
+			}
+		}
+	}
+	for _, resource := range config.Resources {
+		for _, provider := range resource.Providers {
+			switch {
+			case provider.Keyring != nil:
+				paths = append(paths, provider.Keyring.Path)
+			case provider.AESGCM != nil:
+				appendKeyFiles(provider.AESGCM.Keys)
+			case provider.AESCBC != nil:
+				appendKeyFiles(provider.AESCBC.Keys)
+			case provider.Secretbox != nil:
+				appendKeyFiles(provider.Secretbox.Keys)
+			}
+		}
+	}
+	return paths
+}
+
+// ExternalFiles returns the keyring and key secret files referenced by the
+// encryption configuration file at filepath, so that callers watching the
+// configuration for changes can watch those files as well. A configuration
+// that cannot be parsed references no files.
+func ExternalFiles(filepath string) []string {
+	config, _, _ := loadConfig(filepath, true)
+	if config == nil {
+		return nil
+	}
+	return externalFilePaths(config)
+}
+
 func secretboxPrefixTransformer(config *apiserverconfig.SecretboxConfiguration) (value.PrefixTransformer, error) {
 	var result value.PrefixTransformer
 
@@ -443,7 +898,7 @@ func secretboxPrefixTransformer(config *apiserverconfig.SecretboxConfiguration)
 		if key.Name == "" {
 			return result, fmt.Errorf("key with invalid name provided")
 		}
-		if key.Secret == "" {
+		if key.Secret == "" && key.SecretFile == "" {
 			return result, fmt.Errorf("key %v has no provided secret", key.Name)
 		}
 	}
@@ -452,7 +907,11 @@ func secretboxPrefixTransformer(config *apiserverconfig.SecretboxConfiguration)
 
 	for _, keyData := range config.Keys {
 		keyData := keyData
-		key, err := base64.StdEncoding.DecodeString(keyData.Secret)
+		secret, err := keySecret(keyData)
+		if err != nil {
+			return result, err
+		}
+		key, err := base64.StdEncoding.DecodeString(secret)
 		if err != nil {
 			return result, fmt.Errorf("could not obtain secret for named key %s: %s", keyData.Name, err)
 		}
@@ -490,6 +949,9 @@ var (
 
 	// The factory to create kmsv2 service.  Exported for integration tests.
 	EnvelopeKMSv2ServiceFactory = envelopekmsv2.NewGRPCService
+
+	// The factory to create kmsv3 service.  Exported for integration tests.
+	EnvelopeKMSv3ServiceFactory = envelopekmsv2.NewGRPCServiceV3
 )
 
 type kmsState struct {
@@ -497,7 +959,7 @@ type kmsState struct {
 	kmsTimeoutSum  time.Duration
 }
 
-func kmsPrefixTransformer(config *apiserverconfig.KMSConfiguration, stopCh <-chan struct{}) (value.PrefixTransformer, healthChecker, *kmsState, error) {
+func kmsPrefixTransformer(config *apiserverconfig.KMSConfiguration, revoked *revocationSet, stopCh <-chan struct{}) (value.PrefixTransformer, healthChecker, *kmsState, error) {
 	// we ignore the cancel func because this context should only be canceled when stopCh is closed
 	ctx, _ := wait.ContextForChannel(stopCh)
 
@@ -508,6 +970,10 @@ func kmsPrefixTransformer(config *apiserverconfig.KMSConfiguration, stopCh <-cha
 		if err != nil {
 			return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure KMSv1-Plugin's probe %q, error: %w", kmsName, err)
 		}
+		envelopeService = &instrumentedEnvelopeService{name: kmsName, service: envelopeService}
+		if config.MaxInFlight != nil {
+			envelopeService = &limitedEnvelopeService{limiter: newKMSRPCLimiter(kmsName, int(*config.MaxInFlight)), service: envelopeService}
+		}
 
 		probe := &kmsPluginProbe{
 			name:         kmsName,
@@ -519,36 +985,101 @@ func kmsPrefixTransformer(config *apiserverconfig.KMSConfiguration, stopCh <-cha
 
 		transformer := envelopePrefixTransformer(config, envelopeService, kmsTransformerPrefixV1)
 
+		probe.transformer = value.NewPrefixTransformers(fmt.Errorf("no matching prefix found"), transformer)
+		startKMSCanary(ctx, stopCh, probe.runCanary)
+
+		// the canary keeps round-tripping through the ungated transformer
+		// above, so recovery of the plugin is still observed while writes are
+		// failing closed.
+		if config.FailClosedWrites {
+			transformer.Transformer = &failClosedWritesTransformer{name: kmsName, healthErr: probe.healthErr, delegate: transformer.Transformer}
+		}
+
 		return transformer, probe, &kmsState{
 			v1Used: true,
 			// for v1 we will do encrypt and decrypt for health check. Since these are serial operations, we will double the timeout.
 			kmsTimeoutSum: 2 * config.Timeout.Duration,
 		}, nil
 
-	case kmsAPIVersionV2:
+	case kmsAPIVersionV2, kmsAPIVersionV3:
 		if !utilfeature.DefaultFeatureGate.Enabled(features.KMSv2) {
 			return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure KMSv2 plugin %q, KMSv2 feature is not enabled", kmsName)
 		}
 
-		envelopeService, err := EnvelopeKMSv2ServiceFactory(ctx, config.Endpoint, config.Timeout.Duration)
+		// v3 keeps the v2 envelope format on disk and only upgrades the
+		// transport: the plugin additionally serves batch RPCs, and the
+		// coalescer below turns concurrent per-object DEK operations into
+		// shared round trips.
+		expectedAPIVersion := envelopekmsv2.KMSAPIVersion
+		var envelopeService envelopekmsv2.Service
+		var err error
+		if config.APIVersion == kmsAPIVersionV3 {
+			expectedAPIVersion = envelopekmsv2.KMSv3APIVersion
+			var batchService envelopekmsv2.BatchService
+			batchService, err = EnvelopeKMSv3ServiceFactory(ctx, config.Endpoint, config.Timeout.Duration)
+			if err == nil {
+				envelopeService = envelopekmsv2.NewBatchingService(batchService, kmsBatchMaxSize, kmsBatchLinger)
+			}
+		} else {
+			envelopeService, err = EnvelopeKMSv2ServiceFactory(ctx, config.Endpoint, config.Timeout.Duration)
+		}
 		if err != nil {
 			return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure KMSv2-Plugin's probe %q, error: %w", kmsName, err)
 		}
+		envelopeService = &tracedKMSv2Service{name: kmsName, service: envelopeService}
+		envelopeService = &instrumentedKMSv2Service{name: kmsName, service: envelopeService}
+		if revoked.hasKeyIDs() {
+			envelopeService = &revocationCheckingKMSv2Service{name: kmsName, revoked: revoked, service: envelopeService}
+		}
+		if config.MaxInFlight != nil {
+			envelopeService = &limitedKMSv2Service{limiter: newKMSRPCLimiter(kmsName, int(*config.MaxInFlight)), service: envelopeService}
+		}
 
 		probe := &kmsv2PluginProbe{
 			name:         kmsName,
+			apiVersion:   expectedAPIVersion,
 			ttl:          kmsPluginHealthzNegativeTTL,
 			service:      envelopeService,
 			l:            &sync.Mutex{},
 			lastResponse: &kmsPluginHealthzResponse{},
 		}
 
+		reusePolicy := envelopekmsv2.DEKReusePolicy{}
+		if config.DEKReuseMaxWrites != nil {
+			reusePolicy.MaxWrites = *config.DEKReuseMaxWrites
+		}
+		if config.DEKReuseDuration != nil {
+			reusePolicy.MaxAge = config.DEKReuseDuration.Duration
+		}
+
+		var persistentCache *envelopekmsv2.PersistentDEKCache
+		if config.DEKCache != nil {
+			cacheKey, err := dekCacheKey(config.DEKCache)
+			if err != nil {
+				return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure DEK cache persistence for KMS provider %q, error: %w", kmsName, err)
+			}
+			persistentCache, err = envelopekmsv2.NewPersistentDEKCache(config.DEKCache.File, cacheKey)
+			if err != nil {
+				return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure DEK cache persistence for KMS provider %q, error: %w", kmsName, err)
+			}
+		}
+
 		// using AES-GCM by default for encrypting data with KMSv2
 		transformer := value.PrefixTransformer{
-			Transformer: envelopekmsv2.NewEnvelopeTransformer(envelopeService, int(*config.CacheSize), aestransformer.NewGCMTransformer),
+			Transformer: envelopekmsv2.NewEnvelopeTransformerWithPersistentCache(envelopeService, kmsName, int(*config.CacheSize), aestransformer.NewGCMTransformer, reusePolicy, persistentCache),
 			Prefix:      []byte(kmsTransformerPrefixV2 + kmsName + ":"),
 		}
 
+		probe.transformer = value.NewPrefixTransformers(fmt.Errorf("no matching prefix found"), transformer)
+		startKMSCanary(ctx, stopCh, probe.runCanary)
+
+		// the canary keeps round-tripping through the ungated transformer
+		// above, so recovery of the plugin is still observed while writes are
+		// failing closed.
+		if config.FailClosedWrites {
+			transformer.Transformer = &failClosedWritesTransformer{name: kmsName, healthErr: probe.healthErr, delegate: transformer.Transformer}
+		}
+
 		return transformer, probe, &kmsState{
 			v2Used:        true,
 			kmsTimeoutSum: config.Timeout.Duration,
@@ -559,6 +1090,62 @@ func kmsPrefixTransformer(config *apiserverconfig.KMSConfiguration, stopCh <-cha
 	}
 }
 
+// kmsSplitPrefixTransformer wires a dual-control provider: each DEK is split
+// into two shares wrapped by two independent KMSv1 plugins, and both plugins
+// must answer for a decrypt to succeed.
+func kmsSplitPrefixTransformer(config *apiserverconfig.KMSSplitConfiguration, stopCh <-chan struct{}) (value.PrefixTransformer, healthChecker, *kmsState, error) {
+	// we ignore the cancel func because this context should only be canceled when stopCh is closed
+	ctx, _ := wait.ContextForChannel(stopCh)
+
+	kmsName := config.Name
+	primaryService, err := envelopeServiceFactory(ctx, config.Primary.Endpoint, config.Primary.Timeout.Duration)
+	if err != nil {
+		return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure primary plugin of KMSSplit provider %q, error: %w", kmsName, err)
+	}
+	secondaryService, err := envelopeServiceFactory(ctx, config.Secondary.Endpoint, config.Secondary.Timeout.Duration)
+	if err != nil {
+		return value.PrefixTransformer{}, nil, nil, fmt.Errorf("could not configure secondary plugin of KMSSplit provider %q, error: %w", kmsName, err)
+	}
+	splitService := envelope.NewSplitService(
+		&instrumentedEnvelopeService{name: kmsName + "-primary", service: primaryService},
+		&instrumentedEnvelopeService{name: kmsName + "-secondary", service: secondaryService},
+	)
+
+	// a single probe through the combined service verifies dual availability:
+	// it fails as soon as either plugin cannot wrap or unwrap its share.
+	probe := &kmsPluginProbe{
+		name:         kmsName,
+		ttl:          kmsPluginHealthzNegativeTTL,
+		service:      splitService,
+		l:            &sync.Mutex{},
+		lastResponse: &kmsPluginHealthzResponse{},
+	}
+
+	baseTransformerFunc := func(block cipher.Block) value.Transformer {
+		return unionTransformers{aestransformer.NewGCMTransformer(block), aestransformer.NewCBCTransformer(block)}
+	}
+	transformer := value.PrefixTransformer{
+		Transformer: envelope.NewEnvelopeTransformer(splitService, kmsName, int(*config.CacheSize), baseTransformerFunc),
+		Prefix:      []byte(kmsSplitTransformerPrefixV1 + kmsName + ":"),
+	}
+
+	probe.transformer = value.NewPrefixTransformers(fmt.Errorf("no matching prefix found"), transformer)
+	startKMSCanary(ctx, stopCh, probe.runCanary)
+
+	// the canary keeps round-tripping through the ungated transformer above,
+	// so recovery of the plugins is still observed while writes are failing
+	// closed.
+	if config.FailClosedWrites {
+		transformer.Transformer = &failClosedWritesTransformer{name: kmsName, healthErr: probe.healthErr, delegate: transformer.Transformer}
+	}
+
+	return transformer, probe, &kmsState{
+		v1Used: true,
+		// the health check wraps and unwraps serially through both plugins.
+		kmsTimeoutSum: 2 * (config.Primary.Timeout.Duration + config.Secondary.Timeout.Duration),
+	}, nil
+}
+
 func envelopePrefixTransformer(config *apiserverconfig.KMSConfiguration, envelopeService envelope.Service, prefix string) value.PrefixTransformer {
 	baseTransformerFunc := func(block cipher.Block) value.Transformer {
 		// v1.24: write using AES-CBC only but support reads via AES-CBC and AES-GCM (so we can move to AES-GCM)
@@ -569,7 +1156,7 @@ func envelopePrefixTransformer(config *apiserverconfig.KMSConfiguration, envelop
 	}
 
 	return value.PrefixTransformer{
-		Transformer: envelope.NewEnvelopeTransformer(envelopeService, int(*config.CacheSize), baseTransformerFunc),
+		Transformer: envelope.NewEnvelopeTransformer(envelopeService, config.Name, int(*config.CacheSize), baseTransformerFunc),
 		Prefix:      []byte(prefix + config.Name + ":"),
 	}
 }
@@ -21,6 +21,9 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -31,6 +34,7 @@ import (
 	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
 	"k8s.io/apiserver/pkg/features"
 	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
 	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope"
 	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -518,8 +522,9 @@ func TestKMSPluginHealthz(t *testing.T) {
 			config: "testdata/valid-configs/kms/multiple-providers-kmsv2.yaml",
 			want: []healthChecker{
 				&kmsv2PluginProbe{
-					name: "foo",
-					ttl:  3 * time.Second,
+					name:       "foo",
+					apiVersion: envelopekmsv2.KMSAPIVersion,
+					ttl:        3 * time.Second,
 				},
 				&kmsPluginProbe{
 					name: "bar",
@@ -533,7 +538,7 @@ func TestKMSPluginHealthz(t *testing.T) {
 			desc:    "Invalid API version",
 			config:  "testdata/invalid-configs/kms/invalid-apiversion.yaml",
 			want:    nil,
-			wantErr: `resources[0].providers[0].kms.apiVersion: Invalid value: "v3": unsupported apiVersion apiVersion for KMS provider, only v1 and v2 are supported`,
+			wantErr: `resources[0].providers[0].kms.apiVersion: Invalid value: "v4": unsupported apiVersion apiVersion for KMS provider, only v1, v2 and v3 are supported`,
 		},
 	}
 
@@ -560,10 +565,12 @@ func TestKMSPluginHealthz(t *testing.T) {
 					p.service = nil
 					p.l = nil
 					p.lastResponse = nil
+					p.transformer = nil
 				case *kmsv2PluginProbe:
 					p.service = nil
 					p.l = nil
 					p.lastResponse = nil
+					p.transformer = nil
 				default:
 					t.Fatalf("unexpected probe type %T", p)
 				}
@@ -680,6 +687,92 @@ func TestKMSv2PluginHealthzTTL(t *testing.T) {
 	}
 }
 
+func TestKeySecretFromFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key1")
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	if err := os.WriteFile(keyFile, []byte(secret+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := aesPrefixTransformer(&apiserverconfig.AESConfiguration{
+		Keys: []apiserverconfig.Key{{Name: "key1", SecretFile: keyFile}},
+	}, aestransformer.NewGCMTransformer, aesGCMTransformerPrefixV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte("authenticated_data"))
+
+	out, err := result.Transformer.TransformToStorage(ctx, []byte("firstvalue"), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, stale, err := result.Transformer.TransformFromStorage(ctx, out, dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale || !bytes.Equal([]byte("firstvalue"), from) {
+		t.Fatalf("unexpected data: %t %q", stale, from)
+	}
+
+	// a missing key file must fail transformer construction, not fall back to
+	// an empty key.
+	if _, err := aesPrefixTransformer(&apiserverconfig.AESConfiguration{
+		Keys: []apiserverconfig.Key{{Name: "key1", SecretFile: filepath.Join(t.TempDir(), "no-such-key")}},
+	}, aestransformer.NewGCMTransformer, aesGCMTransformerPrefixV1); err == nil {
+		t.Fatal("expected error for missing key file")
+	}
+}
+
+func TestKeySecretFileChangesHash(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key1")
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	if err := os.WriteFile(keyFile, []byte(secret), 0600); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	configYAML := fmt.Sprintf(`
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - aesgcm:
+        keys:
+        - name: key1
+          secretFile: %s
+`, keyFile)
+	if err := os.WriteFile(configFile, []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, firstHash, err := loadConfig(configFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rewriting the key file leaves the config file untouched but must still
+	// change the computed hash so the reload controller picks it up.
+	newSecret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	if err := os.WriteFile(keyFile, []byte(newSecret), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, secondHash, err := loadConfig(configFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstHash == secondHash {
+		t.Fatal("hash did not change after the key file changed")
+	}
+
+	if got := ExternalFiles(configFile); len(got) != 1 || got[0] != keyFile {
+		t.Errorf("ExternalFiles(%q) = %v, want [%q]", configFile, got, keyFile)
+	}
+}
+
 func TestCBCKeyRotationWithOverlappingProviders(t *testing.T) {
 	testCBCKeyRotationWithProviders(
 		t,
@@ -798,7 +891,7 @@ func TestIsKMSv2ProviderHealthyError(t *testing.T) {
 
 	for _, tt := range testCases {
 		t.Run(tt.desc, func(t *testing.T) {
-			if err := isKMSv2ProviderHealthy("testplugin", tt.statusResponse); err == nil {
+			if err := isKMSv2ProviderHealthy("testplugin", tt.statusResponse, envelopekmsv2.KMSAPIVersion); err == nil {
 				t.Fatalf("isKMSv2ProviderHealthy() should have returned an error")
 			}
 		})
@@ -827,3 +920,69 @@ func TestComputeEncryptionConfigHash(t *testing.T) {
 		t.Errorf("expected hash %q but got %q", expect, sum)
 	}
 }
+
+func TestKMSAliasTransformers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factory := envelopeServiceFactory
+	envelopeServiceFactory = newMockEnvelopeService
+	defer func() {
+		envelopeServiceFactory = factory
+	}()
+
+	cacheSize := int32(1000)
+	resourceConfig := apiserverconfig.ResourceConfiguration{
+		Resources: []string{"secrets"},
+		Providers: []apiserverconfig.ProviderConfiguration{
+			{
+				KMS: &apiserverconfig.KMSConfiguration{
+					APIVersion: "v1",
+					Name:       "new-name",
+					Aliases:    []string{"old-name"},
+					Endpoint:   "unix:///tmp/testprovider.sock",
+					Timeout:    &metav1.Duration{Duration: 3 * time.Second},
+					CacheSize:  &cacheSize,
+				},
+			},
+		},
+	}
+
+	transformers, _, _, err := prefixTransformersAndProbes(resourceConfig, newRevocationSet(nil), ctx.Done())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transformers) != 2 {
+		t.Fatalf("expected 2 prefix transformers, got %d", len(transformers))
+	}
+	if string(transformers[0].Prefix) != "k8s:enc:kms:v1:new-name:" {
+		t.Errorf("unexpected write prefix: %q", transformers[0].Prefix)
+	}
+	if string(transformers[1].Prefix) != "k8s:enc:kms:v1:old-name:" {
+		t.Errorf("unexpected alias prefix: %q", transformers[1].Prefix)
+	}
+
+	union := value.NewPrefixTransformers(nil, transformers...)
+	dataCtx := value.DefaultContext([]byte(sampleContextText))
+
+	out, err := union.TransformToStorage(ctx, []byte(sampleText), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte("k8s:enc:kms:v1:new-name:")) {
+		t.Fatalf("new writes must use the current provider name: %q", out)
+	}
+
+	// simulate a record written before the provider was renamed.
+	aliased := append([]byte("k8s:enc:kms:v1:old-name:"), out[len("k8s:enc:kms:v1:new-name:"):]...)
+	from, stale, err := union.TransformFromStorage(ctx, aliased, dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stale {
+		t.Error("reads through an alias must be marked stale so the record is rewritten under the current name")
+	}
+	if !bytes.Equal([]byte(sampleText), from) {
+		t.Errorf("unexpected data: %q", from)
+	}
+}
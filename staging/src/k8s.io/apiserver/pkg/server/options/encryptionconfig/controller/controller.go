@@ -18,15 +18,21 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
@@ -44,6 +50,18 @@ type DynamicKMSEncryptionConfigContent struct {
 	// lastLoadedEncryptionConfigHash stores last successfully read encryption config file content.
 	lastLoadedEncryptionConfigHash string
 
+	// lastLoadedWriteProviders stores the per-resource write providers of the
+	// last successfully loaded config, so reloads can report what changed.
+	lastLoadedWriteProviders map[schema.GroupResource]string
+
+	// lastLoadedProviders stores the provider names of the last successfully
+	// loaded config, so reloads can report providers added and removed.
+	lastLoadedProviders []string
+
+	// eventRecorder emits an Event on every reload outcome. It may be nil, in
+	// which case outcomes are only logged.
+	eventRecorder events.EventRecorder
+
 	// queue for processing changes in encryption config file.
 	queue workqueue.RateLimitingInterface
 
@@ -59,12 +77,18 @@ func NewDynamicKMSEncryptionConfiguration(
 	name, filePath string,
 	dynamicTransformers *encryptionconfig.DynamicTransformers,
 	configContentHash string,
+	writeProviders map[schema.GroupResource]string,
+	providers []string,
+	eventRecorder events.EventRecorder,
 	stopCh <-chan struct{},
 ) *DynamicKMSEncryptionConfigContent {
 	encryptionConfig := &DynamicKMSEncryptionConfigContent{
 		name:                           name,
 		filePath:                       filePath,
 		lastLoadedEncryptionConfigHash: configContentHash,
+		lastLoadedWriteProviders:       writeProviders,
+		lastLoadedProviders:            providers,
+		eventRecorder:                  eventRecorder,
 		dynamicTransformers:            dynamicTransformers,
 		stopCh:                         stopCh,
 		queue:                          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("%s-hot-reload", name)),
@@ -108,6 +132,14 @@ func (d *DynamicKMSEncryptionConfigContent) watchEncryptionConfigFile(stopCh <-c
 		return fmt.Errorf("error adding watch for file %s: %w", d.filePath, err)
 	}
 
+	// a keyring or key file edit changes the effective config without touching
+	// the config file itself, so those files need watches of their own.
+	for _, externalFile := range encryptionconfig.ExternalFiles(d.filePath) {
+		if err = watcher.Add(externalFile); err != nil {
+			return fmt.Errorf("error adding watch for file %s: %w", externalFile, err)
+		}
+	}
+
 	for {
 		select {
 		case event := <-watcher.Events:
@@ -131,11 +163,11 @@ func (d *DynamicKMSEncryptionConfigContent) handleWatchEvent(event fsnotify.Even
 		return nil
 	}
 
-	if err := watcher.Remove(d.filePath); err != nil {
-		klog.V(2).InfoS("Failed to remove file watch, it may have been deleted", "file", d.filePath, "err", err)
+	if err := watcher.Remove(event.Name); err != nil {
+		klog.V(2).InfoS("Failed to remove file watch, it may have been deleted", "file", event.Name, "err", err)
 	}
-	if err := watcher.Add(d.filePath); err != nil {
-		return fmt.Errorf("error adding watch for file %s: %w", d.filePath, err)
+	if err := watcher.Add(event.Name); err != nil {
+		return fmt.Errorf("error adding watch for file %s: %w", event.Name, err)
 	}
 
 	return nil
@@ -174,8 +206,23 @@ func (d *DynamicKMSEncryptionConfigContent) processNextWorkItem() bool {
 			closeTransformers()
 		}
 		if err != nil {
-			// TODO: increment failure metric
+			configHash := ""
+			if encryptionConfiguration != nil {
+				configHash = encryptionConfiguration.EncryptionFileContentHash
+			} else {
+				configHash = encryptionconfig.ConfigContentHash(d.filePath)
+			}
+			encryptionconfig.RecordReloadFailure(&encryptionconfig.ReloadFailure{
+				ConfigHash: configHash,
+				FailedAt:   time.Now(),
+				Reason:     reloadFailureReason(err),
+				Message:    err.Error(),
+			})
 			utilruntime.HandleError(fmt.Errorf("error processing encryption config file %s: %v", d.filePath, err))
+			if d.eventRecorder != nil {
+				d.eventRecorder.Eventf(d.objectReference(), nil, corev1.EventTypeWarning, "EncryptionConfigReloadFailed", "Reload",
+					"error processing encryption config file %s: %v", d.filePath, err)
+			}
 			// add dummy item back to the queue to trigger file content processing.
 			d.queue.AddRateLimited(key)
 		}
@@ -195,6 +242,7 @@ func (d *DynamicKMSEncryptionConfigContent) processNextWorkItem() bool {
 	}
 	// get healthz checks for all new KMS plugins.
 	if err = d.validateNewTransformersHealth(ctx, encryptionConfiguration.HealthChecks[0], encryptionConfiguration.KMSCloseGracePeriod); err != nil {
+		err = &encryptionconfig.ConfigError{Reason: encryptionconfig.ReloadFailureReasonHealthz, Err: err}
 		return true
 	}
 
@@ -208,13 +256,112 @@ func (d *DynamicKMSEncryptionConfigContent) processNextWorkItem() bool {
 	)
 
 	// update local copy of recent config content once update is successful.
+	summary := &encryptionconfig.ReloadSummary{
+		ConfigHash:           encryptionConfiguration.EncryptionFileContentHash,
+		ReloadedAt:           time.Now(),
+		ProvidersAdded:       providerDiff(encryptionConfiguration.Providers, d.lastLoadedProviders),
+		ProvidersRemoved:     providerDiff(d.lastLoadedProviders, encryptionConfiguration.Providers),
+		WriteProviderChanges: writeProviderChanges(d.lastLoadedWriteProviders, encryptionConfiguration.WriteProviders),
+	}
+	changedProviders := renderWriteProviderChanges(summary.WriteProviderChanges)
 	d.lastLoadedEncryptionConfigHash = encryptionConfiguration.EncryptionFileContentHash
-	klog.V(2).InfoS("Loaded new kms encryption config content", "name", d.name)
+	d.lastLoadedWriteProviders = encryptionConfiguration.WriteProviders
+	d.lastLoadedProviders = encryptionConfiguration.Providers
+	encryptionconfig.RecordReloadSummary(summary)
+	encryptionconfig.RecordActiveConfigHash(encryptionConfiguration.EncryptionFileContentHash)
+	encryptionconfig.RecordActiveAlgorithms(encryptionConfiguration.Algorithms)
+	klog.V(2).InfoS("Loaded new kms encryption config content", "name", d.name,
+		"configHash", summary.ConfigHash, "providersAdded", summary.ProvidersAdded,
+		"providersRemoved", summary.ProvidersRemoved, "writeProviderChanges", summary.WriteProviderChanges)
+	if d.eventRecorder != nil {
+		d.eventRecorder.Eventf(d.objectReference(), nil, corev1.EventTypeNormal, "EncryptionConfigReloadSuccess", "Reload",
+			"loaded encryption config with hash %q, write provider changes: %s", summary.ConfigHash, changedProviders)
+	}
 
 	updatedEffectiveConfig = true
 	return true
 }
 
+// reloadFailureReason extracts the failure class of a rejected reload
+// attempt. Errors outside the classified load and health check paths are
+// reported as internal.
+func reloadFailureReason(err error) string {
+	var configErr *encryptionconfig.ConfigError
+	if errors.As(err, &configErr) {
+		return configErr.Reason
+	}
+	return encryptionconfig.ReloadFailureReasonInternal
+}
+
+// objectReference returns the object reloads are recorded against. The
+// controller does not correspond to an API object, so events reference it by
+// its controller name, the same way dynamiccertificates events do.
+func (d *DynamicKMSEncryptionConfigContent) objectReference() *corev1.ObjectReference {
+	return &corev1.ObjectReference{Name: d.name}
+}
+
+// writeProviderChanges maps each resource whose write provider differs
+// between two configs to a "<old> -> <new>" description, with "none" standing
+// in for resources absent from one of them.
+func writeProviderChanges(old, new map[schema.GroupResource]string) map[string]string {
+	changes := map[string]string{}
+	for gr, oldProvider := range old {
+		newProvider, ok := new[gr]
+		switch {
+		case !ok:
+			changes[gr.String()] = oldProvider + " -> none"
+		case oldProvider != newProvider:
+			changes[gr.String()] = oldProvider + " -> " + newProvider
+		}
+	}
+	for gr, newProvider := range new {
+		if _, ok := old[gr]; !ok {
+			changes[gr.String()] = "none -> " + newProvider
+		}
+	}
+	return changes
+}
+
+// renderWriteProviderChanges renders the change map as a human readable,
+// deterministically ordered string for logs and events.
+func renderWriteProviderChanges(changes map[string]string) string {
+	if len(changes) == 0 {
+		return "none"
+	}
+	resources := make([]string, 0, len(changes))
+	for resource := range changes {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	rendered := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		rendered = append(rendered, fmt.Sprintf("%s: %s", resource, changes[resource]))
+	}
+	return strings.Join(rendered, ", ")
+}
+
+// changedWriteProviders renders the difference between the write providers of
+// two configs as a human readable, deterministically ordered string.
+func changedWriteProviders(old, new map[schema.GroupResource]string) string {
+	return renderWriteProviderChanges(writeProviderChanges(old, new))
+}
+
+// providerDiff returns the providers present in a but not in b, preserving
+// the sorted order of a.
+func providerDiff(a, b []string) []string {
+	other := make(map[string]bool, len(b))
+	for _, provider := range b {
+		other[provider] = true
+	}
+	var diff []string
+	for _, provider := range a {
+		if !other[provider] {
+			diff = append(diff, provider)
+		}
+	}
+	return diff
+}
+
 // loadEncryptionConfig processes the next set of content from the file.
 func (d *DynamicKMSEncryptionConfigContent) processEncryptionConfig(ctx context.Context) (
 	encryptionConfiguration *encryptionconfig.EncryptionConfiguration,
@@ -18,11 +18,18 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+	"k8s.io/client-go/tools/events"
 )
 
 func TestProcessEncryptionConfig(t *testing.T) {
@@ -46,6 +53,9 @@ func TestProcessEncryptionConfig(t *testing.T) {
 				testCase.filePath,
 				nil,
 				"",
+				nil,
+				nil,
+				nil,
 				ctx.Done(),
 			)
 
@@ -110,6 +120,9 @@ func TestWatchEncryptionConfigFile(t *testing.T) {
 				testFilePath,
 				nil,
 				"",
+				nil,
+				nil,
+				nil,
 				ctx.Done(),
 			)
 
@@ -170,3 +183,121 @@ func copyFileForTest(t *testing.T, srcFilePath string) string {
 
 	return dstFilePath
 }
+
+func TestChangedWriteProviders(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+	configmaps := schema.GroupResource{Resource: "configmaps"}
+
+	testCases := []struct {
+		name string
+		old  map[schema.GroupResource]string
+		new  map[schema.GroupResource]string
+		want string
+	}{
+		{
+			name: "no changes",
+			old:  map[schema.GroupResource]string{secrets: "aescbc"},
+			new:  map[schema.GroupResource]string{secrets: "aescbc"},
+			want: "none",
+		},
+		{
+			name: "provider changed",
+			old:  map[schema.GroupResource]string{secrets: "aescbc"},
+			new:  map[schema.GroupResource]string{secrets: "kms:v2:my-kms"},
+			want: "secrets: aescbc -> kms:v2:my-kms",
+		},
+		{
+			name: "resource added and removed",
+			old:  map[schema.GroupResource]string{secrets: "aescbc"},
+			new:  map[schema.GroupResource]string{configmaps: "aesgcm"},
+			want: "configmaps: none -> aesgcm, secrets: aescbc -> none",
+		},
+		{
+			name: "no previous config",
+			old:  nil,
+			new:  map[schema.GroupResource]string{secrets: "identity"},
+			want: "secrets: none -> identity",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := changedWriteProviders(testCase.old, testCase.new); got != testCase.want {
+				t.Errorf("changedWriteProviders() = %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestReloadFailureEmitsEvent(t *testing.T) {
+	ctx := context.Background()
+	eventRecorder := events.NewFakeRecorder(1)
+	d := NewDynamicKMSEncryptionConfiguration(
+		"test-reload-failure",
+		"testdata/empty_config.yaml",
+		nil,
+		"",
+		nil,
+		nil,
+		eventRecorder,
+		ctx.Done(),
+	)
+
+	d.processNextWorkItem()
+
+	select {
+	case event := <-eventRecorder.Events:
+		if !strings.Contains(event, "EncryptionConfigReloadFailed") {
+			t.Errorf("expected EncryptionConfigReloadFailed event, got %q", event)
+		}
+	default:
+		t.Fatal("expected an event to be emitted for the failed reload")
+	}
+}
+
+func TestProviderDiff(t *testing.T) {
+	old := []string{"aescbc", "identity", "kms:v1:old"}
+	new := []string{"identity", "kms:v1:old", "kms:v2:new"}
+
+	if added := providerDiff(new, old); len(added) != 1 || added[0] != "kms:v2:new" {
+		t.Errorf("providerDiff(new, old) = %v, want [kms:v2:new]", added)
+	}
+	if removed := providerDiff(old, new); len(removed) != 1 || removed[0] != "aescbc" {
+		t.Errorf("providerDiff(old, new) = %v, want [aescbc]", removed)
+	}
+	if diff := providerDiff(old, old); diff != nil {
+		t.Errorf("providerDiff(old, old) = %v, want nil", diff)
+	}
+}
+
+func TestReloadFailureReason(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "classified error",
+			err:  &encryptionconfig.ConfigError{Reason: encryptionconfig.ReloadFailureReasonValidation, Err: errors.New("spec must not be empty")},
+			want: encryptionconfig.ReloadFailureReasonValidation,
+		},
+		{
+			name: "wrapped classified error",
+			err:  fmt.Errorf("error processing config: %w", &encryptionconfig.ConfigError{Reason: encryptionconfig.ReloadFailureReasonHealthz, Err: errors.New("unhealthy")}),
+			want: encryptionconfig.ReloadFailureReasonHealthz,
+		},
+		{
+			name: "unclassified error",
+			err:  errors.New("boom"),
+			want: encryptionconfig.ReloadFailureReasonInternal,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := reloadFailureReason(testCase.err); got != testCase.want {
+				t.Errorf("reloadFailureReason() = %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kmstypes "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2/v2alpha1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// coverageSampleInterval is how often the coverage controller samples
+	// storage.
+	coverageSampleInterval = 1 * time.Hour
+	// coverageSampleLimit caps how many records are read per resource and
+	// sample, to bound the load a sample puts on etcd.
+	coverageSampleLimit = 1000
+)
+
+var (
+	registerKeyIDUsageMetric sync.Once
+
+	encryptionConfigKeyIDUsage = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace: "apiserver",
+			Name:      "encryption_config_key_id_usage",
+			Help: "Number of sampled storage records protected by each KMSv2 key ID. Operators " +
+				"can verify that an old key version approaches zero usage before retiring it " +
+				"in the external KMS.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"provider_name", "key_id"},
+	)
+)
+
+// recordKeyIDUsage publishes the per-KEK record counts of one coverage
+// sample, summed across resources. Key IDs absent from the sample drop out of
+// the metric.
+func recordKeyIDUsage(report *EncryptionCoverageReport) {
+	registerKeyIDUsageMetric.Do(func() {
+		legacyregistry.MustRegister(encryptionConfigKeyIDUsage)
+	})
+
+	usage := map[string]map[string]int{}
+	for _, resource := range report.Resources {
+		for _, provider := range resource.Providers {
+			if len(provider.KeyIDs) == 0 {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimPrefix(provider.Prefix, kmsTransformerPrefixV2), ":")
+			if usage[name] == nil {
+				usage[name] = map[string]int{}
+			}
+			for keyID, count := range provider.KeyIDs {
+				usage[name][keyID] += count
+			}
+		}
+	}
+
+	encryptionConfigKeyIDUsage.Reset()
+	for name, keys := range usage {
+		for keyID, count := range keys {
+			encryptionConfigKeyIDUsage.WithLabelValues(name, keyID).Set(float64(count))
+		}
+	}
+}
+
+// RawRecordLister lists up to limit raw, still-encrypted records of a resource
+// directly from storage, bypassing the transformer chain.
+type RawRecordLister func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error)
+
+// EncryptionCoverageReport records, per resource covered by the encryption
+// configuration, which provider prefixes the stored records actually carry.
+// It is the compliance evidence that an encryption rollout or storage
+// migration has reached the data, not just the configuration.
+type EncryptionCoverageReport struct {
+	// SampledAt is when the sample was taken.
+	SampledAt time.Time `json:"sampledAt"`
+	// Resources carries one entry per resource declared in the encryption
+	// configuration.
+	Resources []ResourceCoverage `json:"resources"`
+}
+
+// ResourceCoverage is the sampled encryption state of one resource.
+type ResourceCoverage struct {
+	Resource string `json:"resource"`
+	// Sampled is the number of records read for this resource, capped at the
+	// sample limit.
+	Sampled int `json:"sampled"`
+	// Providers lists the provider prefixes found among the sampled records.
+	Providers []ProviderCoverage `json:"providers,omitempty"`
+	// Plaintext is the number of sampled records without an encryption prefix.
+	Plaintext int `json:"plaintext"`
+	// Error is set when this resource could not be sampled.
+	Error string `json:"error,omitempty"`
+}
+
+// ProviderCoverage counts the sampled records stored under one provider
+// prefix.
+type ProviderCoverage struct {
+	// Prefix is the full storage prefix, k8s:enc:<provider>:<version>:<name>:.
+	Prefix string `json:"prefix"`
+	Count  int    `json:"count"`
+	// Fraction is Count divided by the number of sampled records.
+	Fraction float64 `json:"fraction"`
+	// KeyIDs counts the records protected by each KEK version. Only set for
+	// KMSv2 providers, whose records carry the key ID; records that cannot
+	// be decoded are counted under "unknown".
+	KeyIDs map[string]int `json:"keyIDs,omitempty"`
+}
+
+// CoverageController periodically samples storage and computes an
+// EncryptionCoverageReport for the resources declared in the encryption
+// configuration file. The latest report is served by the handler interface it
+// implements.
+type CoverageController struct {
+	filepath string
+	reload   bool
+	lister   RawRecordLister
+
+	lock   sync.Mutex
+	report *EncryptionCoverageReport
+}
+
+// NewCoverageController returns a controller that samples the raw records of
+// every resource declared in the encryption configuration file at filepath.
+func NewCoverageController(filepath string, reload bool, lister RawRecordLister) *CoverageController {
+	return &CoverageController{
+		filepath: filepath,
+		reload:   reload,
+		lister:   lister,
+	}
+}
+
+// Run samples storage periodically until stopCh is closed.
+func (c *CoverageController) Run(stopCh <-chan struct{}) {
+	// we ignore the cancel func because this context should only be canceled when stopCh is closed
+	ctx, _ := wait.ContextForChannel(stopCh)
+	wait.Until(func() { c.sample(ctx) }, coverageSampleInterval, stopCh)
+}
+
+// LatestReport returns the most recent coverage report, or nil when no sample
+// has completed yet.
+func (c *CoverageController) LatestReport() *EncryptionCoverageReport {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.report
+}
+
+// ServeHTTP serves the latest coverage report as JSON.
+func (c *CoverageController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.LatestReport()
+	if report == nil {
+		http.Error(w, "no encryption coverage sample has completed yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, "failed to encode encryption coverage report", http.StatusInternalServerError)
+	}
+}
+
+func (c *CoverageController) sample(ctx context.Context) {
+	config, _, err := loadConfig(c.filepath, c.reload)
+	if err != nil {
+		return
+	}
+
+	report := &EncryptionCoverageReport{SampledAt: time.Now(), Resources: []ResourceCoverage{}}
+	seen := map[schema.GroupResource]bool{}
+	for _, resourceConfig := range config.Resources {
+		for _, resource := range resourceConfig.Resources {
+			gr := schema.ParseGroupResource(resource)
+			// wildcard entries do not map to a single key prefix
+			if strings.Contains(gr.Resource, "*") {
+				continue
+			}
+			if seen[gr] {
+				continue
+			}
+			seen[gr] = true
+			report.Resources = append(report.Resources, c.sampleResource(ctx, gr))
+		}
+	}
+
+	recordKeyIDUsage(report)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report = report
+}
+
+func (c *CoverageController) sampleResource(ctx context.Context, gr schema.GroupResource) ResourceCoverage {
+	coverage := ResourceCoverage{Resource: gr.String()}
+
+	values, err := c.lister(ctx, gr, coverageSampleLimit)
+	if err != nil {
+		coverage.Error = err.Error()
+		return coverage
+	}
+
+	counts := map[string]int{}
+	keyIDs := map[string]map[string]int{}
+	for _, value := range values {
+		coverage.Sampled++
+		prefix, ok := encryptionPrefix(value)
+		if !ok {
+			coverage.Plaintext++
+			continue
+		}
+		counts[prefix]++
+		if keyID, ok := kmsv2KeyID(prefix, value); ok {
+			if keyIDs[prefix] == nil {
+				keyIDs[prefix] = map[string]int{}
+			}
+			keyIDs[prefix][keyID]++
+		}
+	}
+
+	prefixes := make([]string, 0, len(counts))
+	for prefix := range counts {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		coverage.Providers = append(coverage.Providers, ProviderCoverage{
+			Prefix:   prefix,
+			Count:    counts[prefix],
+			Fraction: float64(counts[prefix]) / float64(coverage.Sampled),
+			KeyIDs:   keyIDs[prefix],
+		})
+	}
+
+	return coverage
+}
+
+// kmsv2KeyID extracts the KEK version protecting a KMSv2 record. The second
+// return value is false for records of other providers. Records that cannot
+// be decoded are reported as "unknown" rather than dropped, so they still
+// show up in the accounting.
+func kmsv2KeyID(prefix string, value []byte) (string, bool) {
+	if !strings.HasPrefix(prefix, kmsTransformerPrefixV2) {
+		return "", false
+	}
+	object := &kmstypes.EncryptedObject{}
+	if err := proto.Unmarshal(value[len(prefix):], object); err != nil || object.KeyID == "" {
+		return "unknown", true
+	}
+	return object.KeyID, true
+}
+
+// encryptionPrefix extracts the full provider prefix,
+// k8s:enc:<provider>:<version>:<name>:, from a raw storage record. The second
+// return value is false for plaintext records.
+func encryptionPrefix(value []byte) (string, bool) {
+	if !bytes.HasPrefix(value, []byte("k8s:enc:")) {
+		return "", false
+	}
+	fields := bytes.SplitN(value, []byte(":"), 6)
+	if len(fields) < 6 {
+		return "", false
+	}
+	return string(bytes.Join(fields[:5], []byte(":"))) + ":", true
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kmstypes "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2/v2alpha1"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func kmsv2Record(t *testing.T, provider, keyID string) []byte {
+	t.Helper()
+	data, err := proto.Marshal(&kmstypes.EncryptedObject{
+		EncryptedData: []byte("ciphertext"),
+		KeyID:         keyID,
+		EncryptedDEK:  []byte("dek"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append([]byte("k8s:enc:kms:v2:"+provider+":"), data...)
+}
+
+func TestCoverageControllerSample(t *testing.T) {
+	configFile := writeProvidersDebugTestConfig(t)
+
+	records := [][]byte{
+		[]byte("k8s:enc:aesgcm:v1:key1:ciphertext"),
+		[]byte("plaintext record"),
+	}
+	lister := func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error) {
+		if resource.Resource != "secrets" {
+			t.Errorf("unexpected resource sampled: %v", resource)
+		}
+		return append([][]byte{
+			kmsv2Record(t, "provider-1", "key-1"),
+			kmsv2Record(t, "provider-1", "key-2"),
+		}, records...), nil
+	}
+
+	controller := NewCoverageController(configFile, false, lister)
+
+	// before the first sample the handler must report unavailability
+	recorder := httptest.NewRecorder()
+	controller.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/encryption/coverage", nil))
+	if recorder.Code != 503 {
+		t.Errorf("expected status 503 before the first sample, got %d", recorder.Code)
+	}
+
+	controller.sample(context.Background())
+
+	report := controller.LatestReport()
+	if report == nil {
+		t.Fatal("expected a report after sampling")
+	}
+	if len(report.Resources) != 1 {
+		t.Fatalf("expected 1 resource, got %+v", report.Resources)
+	}
+
+	secrets := report.Resources[0]
+	if secrets.Resource != "secrets" || secrets.Sampled != 4 || secrets.Plaintext != 1 {
+		t.Errorf("unexpected resource coverage %+v", secrets)
+	}
+	if len(secrets.Providers) != 2 {
+		t.Fatalf("expected 2 provider prefixes, got %+v", secrets.Providers)
+	}
+	if got := secrets.Providers[0]; got.Prefix != "k8s:enc:aesgcm:v1:key1:" || got.Count != 1 || got.Fraction != 0.25 || got.KeyIDs != nil {
+		t.Errorf("unexpected provider coverage %+v", got)
+	}
+	if got := secrets.Providers[1]; got.Prefix != "k8s:enc:kms:v2:provider-1:" || got.Count != 2 || got.Fraction != 0.5 ||
+		len(got.KeyIDs) != 2 || got.KeyIDs["key-1"] != 1 || got.KeyIDs["key-2"] != 1 {
+		t.Errorf("unexpected provider coverage %+v", got)
+	}
+
+	expectedMetrics := `
+	# HELP apiserver_encryption_config_key_id_usage [ALPHA] Number of sampled storage records protected by each KMSv2 key ID. Operators can verify that an old key version approaches zero usage before retiring it in the external KMS.
+	# TYPE apiserver_encryption_config_key_id_usage gauge
+	apiserver_encryption_config_key_id_usage{key_id="key-1",provider_name="provider-1"} 1
+	apiserver_encryption_config_key_id_usage{key_id="key-2",provider_name="provider-1"} 1
+	`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expectedMetrics), "apiserver_encryption_config_key_id_usage"); err != nil {
+		t.Error(err)
+	}
+
+	recorder = httptest.NewRecorder()
+	controller.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/encryption/coverage", nil))
+	if recorder.Code != 200 {
+		t.Fatalf("expected status 200 after sampling, got %d", recorder.Code)
+	}
+	var served EncryptionCoverageReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if len(served.Resources) != 1 || served.Resources[0].Resource != "secrets" {
+		t.Errorf("unexpected served report %+v", served)
+	}
+}
+
+func TestKMSv2KeyID(t *testing.T) {
+	record := kmsv2Record(t, "provider-1", "key-1")
+	if keyID, ok := kmsv2KeyID("k8s:enc:kms:v2:provider-1:", record); !ok || keyID != "key-1" {
+		t.Errorf("expected key-1, got %q, %v", keyID, ok)
+	}
+	if keyID, ok := kmsv2KeyID("k8s:enc:kms:v2:provider-1:", []byte("k8s:enc:kms:v2:provider-1:not a proto message")); !ok || keyID != "unknown" {
+		t.Errorf("expected unknown for an undecodable record, got %q, %v", keyID, ok)
+	}
+	if _, ok := kmsv2KeyID("k8s:enc:kms:v1:provider-1:", []byte("k8s:enc:kms:v1:provider-1:ciphertext")); ok {
+		t.Error("expected no key ID for a KMSv1 record")
+	}
+}
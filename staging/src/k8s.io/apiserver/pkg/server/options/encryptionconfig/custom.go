@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+// CustomTransformerFactory builds a prefix transformer for one load of the
+// encryption config. It is invoked once per load, including every automatic
+// reload, so registered transformers participate in hot reload; any resources
+// spawned by the factory must be bound to stopCh.
+type CustomTransformerFactory func(stopCh <-chan struct{}) (value.PrefixTransformer, error)
+
+// reservedPrefixes are the prefixes owned by the built-in providers. Custom
+// prefixes must not collide with them, nor with each other, in the sense of
+// one being a prefix of the other: the prefix transformer chain matches on
+// longest known prefix and ambiguous registrations would silently shadow one
+// another.
+var reservedPrefixes = []string{
+	aesCBCTransformerPrefixV1,
+	aesGCMTransformerPrefixV1,
+	secretboxTransformerPrefixV1,
+	tpmTransformerPrefixV1,
+	x25519TransformerPrefixV1,
+	kmsTransformerPrefixV1,
+	kmsTransformerPrefixV2,
+}
+
+var (
+	customTransformersLock sync.RWMutex
+	customTransformers     = map[string]CustomTransformerFactory{}
+)
+
+// RegisterCustomTransformer registers an additional prefix transformer that is
+// appended to the transformer chain of every resource in the encryption
+// config. This is intended for distributions embedding custom crypto
+// in-process; it must be called before the encryption config is loaded.
+//
+// Custom transformers can decrypt data they wrote previously, but the first
+// provider in the config file remains the one used for writes.
+func RegisterCustomTransformer(prefix string, factory CustomTransformerFactory) error {
+	if len(prefix) == 0 {
+		return fmt.Errorf("custom transformer prefix must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("custom transformer factory must not be nil")
+	}
+
+	customTransformersLock.Lock()
+	defer customTransformersLock.Unlock()
+
+	for _, reserved := range reservedPrefixes {
+		if strings.HasPrefix(prefix, reserved) || strings.HasPrefix(reserved, prefix) {
+			return fmt.Errorf("custom transformer prefix %q conflicts with reserved prefix %q", prefix, reserved)
+		}
+	}
+	for registered := range customTransformers {
+		if strings.HasPrefix(prefix, registered) || strings.HasPrefix(registered, prefix) {
+			return fmt.Errorf("custom transformer prefix %q conflicts with already registered prefix %q", prefix, registered)
+		}
+	}
+
+	customTransformers[prefix] = factory
+	return nil
+}
+
+// customPrefixTransformers instantiates all registered custom transformers
+// for one load of the encryption config, in a stable order.
+func customPrefixTransformers(stopCh <-chan struct{}) ([]value.PrefixTransformer, error) {
+	customTransformersLock.RLock()
+	defer customTransformersLock.RUnlock()
+
+	if len(customTransformers) == 0 {
+		return nil, nil
+	}
+
+	prefixes := make([]string, 0, len(customTransformers))
+	for prefix := range customTransformers {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	transformers := make([]value.PrefixTransformer, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		transformer, err := customTransformers[prefix](stopCh)
+		if err != nil {
+			return nil, fmt.Errorf("error while building custom transformer for prefix %q: %w", prefix, err)
+		}
+		if string(transformer.Prefix) != prefix {
+			return nil, fmt.Errorf("custom transformer for prefix %q returned mismatching prefix %q", prefix, string(transformer.Prefix))
+		}
+		transformers = append(transformers, transformer)
+	}
+	return transformers, nil
+}
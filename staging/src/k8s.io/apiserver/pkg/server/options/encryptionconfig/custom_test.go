@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/identity"
+)
+
+// withEmptyRegistry isolates the global registry for one test.
+func withEmptyRegistry(t *testing.T) {
+	t.Helper()
+	customTransformersLock.Lock()
+	saved := customTransformers
+	customTransformers = map[string]CustomTransformerFactory{}
+	customTransformersLock.Unlock()
+	t.Cleanup(func() {
+		customTransformersLock.Lock()
+		customTransformers = saved
+		customTransformersLock.Unlock()
+	})
+}
+
+func identityFactory(prefix string) CustomTransformerFactory {
+	return func(stopCh <-chan struct{}) (value.PrefixTransformer, error) {
+		return value.PrefixTransformer{
+			Transformer: identity.NewEncryptCheckTransformer(),
+			Prefix:      []byte(prefix),
+		}, nil
+	}
+}
+
+func TestRegisterCustomTransformer(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		prefix  string
+		wantErr string
+	}{
+		{
+			desc:   "valid prefix",
+			prefix: "example.com:enc:fancy:v1:",
+		},
+		{
+			desc:    "empty prefix",
+			prefix:  "",
+			wantErr: "must not be empty",
+		},
+		{
+			desc:    "reserved prefix",
+			prefix:  "k8s:enc:aescbc:v1:",
+			wantErr: "conflicts with reserved prefix",
+		},
+		{
+			desc:    "prefix of a reserved prefix",
+			prefix:  "k8s:enc:",
+			wantErr: "conflicts with reserved prefix",
+		},
+		{
+			desc:    "extension of a reserved prefix",
+			prefix:  "k8s:enc:kms:v2:custom:",
+			wantErr: "conflicts with reserved prefix",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			withEmptyRegistry(t)
+			err := RegisterCustomTransformer(tt.prefix, identityFactory(tt.prefix))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("RegisterCustomTransformer(%q) returned unexpected error: %v", tt.prefix, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RegisterCustomTransformer(%q) error = %v, want error containing %q", tt.prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterCustomTransformerDuplicate(t *testing.T) {
+	withEmptyRegistry(t)
+	if err := RegisterCustomTransformer("example.com:enc:fancy:v1:", identityFactory("example.com:enc:fancy:v1:")); err != nil {
+		t.Fatalf("first registration failed: %v", err)
+	}
+	if err := RegisterCustomTransformer("example.com:enc:fancy:v1:", identityFactory("example.com:enc:fancy:v1:")); err == nil {
+		t.Fatal("duplicate registration succeeded")
+	}
+	if err := RegisterCustomTransformer("example.com:enc:", identityFactory("example.com:enc:")); err == nil {
+		t.Fatal("registration of prefix of registered prefix succeeded")
+	}
+}
+
+func TestCustomTransformerInChain(t *testing.T) {
+	withEmptyRegistry(t)
+	const prefix = "example.com:enc:fancy:v1:"
+	if err := RegisterCustomTransformer(prefix, identityFactory(prefix)); err != nil {
+		t.Fatalf("registration failed: %v", err)
+	}
+
+	config, err := LoadEncryptionConfig("testdata/valid-configs/aes/aes-gcm.yaml", false, testContext(t).Done())
+	if err != nil {
+		t.Fatalf("LoadEncryptionConfig failed: %v", err)
+	}
+	transformer, ok := config.Transformers[schema.GroupResource{Resource: "secrets"}]
+	if !ok {
+		t.Fatal("no transformer for secrets")
+	}
+
+	ctx := context.Background()
+	dataCtx := value.DefaultContext("ns/name")
+
+	// Data written by the custom transformer is readable through the chain.
+	out, _, err := transformer.TransformFromStorage(ctx, append([]byte(prefix), []byte("custom data")...), dataCtx)
+	if err != nil {
+		t.Fatalf("TransformFromStorage of custom-prefixed data failed: %v", err)
+	}
+	if string(out) != "custom data" {
+		t.Errorf("TransformFromStorage returned %q, want %q", string(out), "custom data")
+	}
+
+	// The first provider from the config file still drives writes.
+	stored, err := transformer.TransformToStorage(ctx, []byte("new data"), dataCtx)
+	if err != nil {
+		t.Fatalf("TransformToStorage failed: %v", err)
+	}
+	if !strings.HasPrefix(string(stored), "k8s:enc:aesgcm:v1:") {
+		t.Errorf("TransformToStorage wrote prefix %q, want the configured aesgcm provider", string(stored[:20]))
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+// failClosedWritesTransformer implements the failClosedWrites provider policy:
+// while the provider's health probe reports the plugin unhealthy, writes are
+// rejected immediately with a kms_unavailable error instead of each write
+// discovering the outage on its own plugin timeout. Reads pass through
+// untouched so records whose DEKs are still cached keep being served during
+// the outage.
+type failClosedWritesTransformer struct {
+	name string
+	// healthErr reports the most recent probe or canary failure, without
+	// calling the plugin.
+	healthErr func() error
+	delegate  value.Transformer
+}
+
+var _ value.Transformer = &failClosedWritesTransformer{}
+
+// healthErr returns the most recent failure observed by the plugin health
+// probe or the canary round-trips, or nil while both were healthy. Unlike
+// check, it never calls the plugin, so it is safe on the write path.
+func (h *kmsPluginProbe) healthErr() error {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	if h.lastResponse.err != nil {
+		return h.lastResponse.err
+	}
+	if h.lastCanary != nil {
+		return h.lastCanary.err
+	}
+	return nil
+}
+
+// healthErr returns the most recent failure observed by the plugin health
+// probe or the canary round-trips, or nil while both were healthy. Unlike
+// check, it never calls the plugin, so it is safe on the write path.
+func (h *kmsv2PluginProbe) healthErr() error {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	if h.lastResponse.err != nil {
+		return h.lastResponse.err
+	}
+	if h.lastCanary != nil {
+		return h.lastCanary.err
+	}
+	return nil
+}
+
+func (t *failClosedWritesTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	return t.delegate.TransformFromStorage(ctx, data, dataCtx)
+}
+
+func (t *failClosedWritesTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	if err := t.healthErr(); err != nil {
+		return nil, value.NewKMSUnavailableError(fmt.Errorf("KMS provider %q is unhealthy, rejecting write: %w", t.name, err))
+	}
+	return t.delegate.TransformToStorage(ctx, data, dataCtx)
+}
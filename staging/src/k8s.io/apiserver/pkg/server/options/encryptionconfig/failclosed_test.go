@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/identity"
+)
+
+func TestFailClosedWritesTransformer(t *testing.T) {
+	probe := &kmsPluginProbe{
+		name:         "provider",
+		l:            &sync.Mutex{},
+		lastResponse: &kmsPluginHealthzResponse{},
+	}
+	transformer := &failClosedWritesTransformer{
+		name:      "provider",
+		healthErr: probe.healthErr,
+		delegate:  identity.NewEncryptCheckTransformer(),
+	}
+
+	// healthy: reads and writes pass through to the delegate
+	if _, err := transformer.TransformToStorage(context.Background(), []byte("data"), value.DefaultContext("key")); err != nil {
+		t.Errorf("expected write to pass through while the probe is healthy, got: %v", err)
+	}
+	if _, _, err := transformer.TransformFromStorage(context.Background(), []byte("data"), value.DefaultContext("key")); err != nil {
+		t.Errorf("expected read to pass through while the probe is healthy, got: %v", err)
+	}
+
+	// unhealthy probe: writes fail closed, reads still pass through
+	probe.lastResponse = &kmsPluginHealthzResponse{err: fmt.Errorf("plugin down"), received: time.Now()}
+	if _, err := transformer.TransformToStorage(context.Background(), []byte("data"), value.DefaultContext("key")); value.ReasonForError(err) != value.ReasonKMSUnavailable {
+		t.Errorf("expected kms unavailable error on write while the probe is unhealthy, got: %v", err)
+	}
+	if _, _, err := transformer.TransformFromStorage(context.Background(), []byte("data"), value.DefaultContext("key")); err != nil {
+		t.Errorf("expected read to pass through while the probe is unhealthy, got: %v", err)
+	}
+
+	// failed canary with a healthy probe also fails writes closed
+	probe.lastResponse = &kmsPluginHealthzResponse{received: time.Now()}
+	probe.lastCanary = &kmsPluginHealthzResponse{err: fmt.Errorf("canary round-trip failed"), received: time.Now()}
+	if _, err := transformer.TransformToStorage(context.Background(), []byte("data"), value.DefaultContext("key")); value.ReasonForError(err) != value.ReasonKMSUnavailable {
+		t.Errorf("expected kms unavailable error on write while the canary is failing, got: %v", err)
+	}
+
+	// recovery reopens writes
+	probe.lastCanary = &kmsPluginHealthzResponse{received: time.Now()}
+	if _, err := transformer.TransformToStorage(context.Background(), []byte("data"), value.DefaultContext("key")); err != nil {
+		t.Errorf("expected write to pass through after the canary recovers, got: %v", err)
+	}
+}
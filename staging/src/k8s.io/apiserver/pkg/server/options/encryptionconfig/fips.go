@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+)
+
+// fipsModuleEnabled reports whether the crypto backend of this binary is a
+// FIPS-validated module. This is a variable to make writing tests easier.
+var fipsModuleEnabled = boringCryptoEnabled
+
+// CheckFIPSCompliance parses the encryption config specified by filepath and returns an
+// aggregate error describing every provider that uses a non-FIPS-approved algorithm.
+// It also fails when the binary was not built against a FIPS-validated crypto module.
+func CheckFIPSCompliance(filepath string) error {
+	config, _, err := loadConfig(filepath, false)
+	if err != nil {
+		return fmt.Errorf("error while parsing file: %w", err)
+	}
+
+	var errs []error
+	if !fipsModuleEnabled() {
+		errs = append(errs, fmt.Errorf("this binary was not built with a FIPS-validated crypto module (boringcrypto)"))
+	}
+	errs = append(errs, fipsViolations(config)...)
+	return utilerrors.NewAggregate(errs)
+}
+
+// fipsViolations walks the encryption configuration and returns an error for every
+// provider that uses an algorithm which is not FIPS approved.
+func fipsViolations(config *apiserverconfig.EncryptionConfiguration) []error {
+	var errs []error
+	for i, resourceConfig := range config.Resources {
+		for j, provider := range resourceConfig.Providers {
+			path := fmt.Sprintf("resources[%d].providers[%d]", i, j)
+			switch {
+			case provider.Secretbox != nil:
+				errs = append(errs, fmt.Errorf("%s: secretbox uses XSalsa20 and Poly1305 which are not FIPS-approved algorithms", path))
+			case provider.Identity != nil:
+				errs = append(errs, fmt.Errorf("%s: identity stores resources in plaintext and is not allowed in FIPS mode", path))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckFIPSCompliance(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        string
+		moduleEnabled bool
+		wantErrs      []string
+	}{
+		{
+			desc:          "aes providers are approved",
+			config:        "testdata/valid-configs/aes/aes-gcm.yaml",
+			moduleEnabled: true,
+		},
+		{
+			desc:          "secretbox and identity providers are rejected",
+			config:        "testdata/valid-configs/secret-box-first.yaml",
+			moduleEnabled: true,
+			wantErrs: []string{
+				"resources[0].providers[0]: secretbox uses XSalsa20 and Poly1305 which are not FIPS-approved algorithms",
+				"resources[0].providers[4]: identity stores resources in plaintext and is not allowed in FIPS mode",
+			},
+		},
+		{
+			desc:          "non FIPS crypto module is rejected",
+			config:        "testdata/valid-configs/aes/aes-gcm.yaml",
+			moduleEnabled: false,
+			wantErrs: []string{
+				"this binary was not built with a FIPS-validated crypto module (boringcrypto)",
+			},
+		},
+		{
+			desc:          "unparseable config is surfaced",
+			config:        "testdata/invalid-configs/kms/invalid-apiversion.yaml",
+			moduleEnabled: true,
+			wantErrs:      []string{"error while parsing file"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			origFIPSModuleEnabled := fipsModuleEnabled
+			fipsModuleEnabled = func() bool { return tt.moduleEnabled }
+			defer func() {
+				fipsModuleEnabled = origFIPSModuleEnabled
+			}()
+
+			err := CheckFIPSCompliance(tt.config)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("CheckFIPSCompliance(%q) returned unexpected error: %v", tt.config, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("CheckFIPSCompliance(%q) expected error containing %q, got none", tt.config, tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("CheckFIPSCompliance(%q) error %q does not contain %q", tt.config, err.Error(), want)
+				}
+			}
+		})
+	}
+}
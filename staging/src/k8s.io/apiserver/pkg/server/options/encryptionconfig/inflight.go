@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope"
+	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+	envelopemetrics "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
+)
+
+// kmsRPCLimiter is a semaphore bounding how many Encrypt and Decrypt RPCs may
+// be in flight against one KMS plugin at a time. Calls beyond the bound wait
+// for a slot; the queue length and wait time are published per provider so an
+// overwhelmed plugin shows up as queueing rather than as unexplained latency.
+type kmsRPCLimiter struct {
+	name  string
+	slots chan struct{}
+}
+
+func newKMSRPCLimiter(name string, maxInFlight int) *kmsRPCLimiter {
+	return &kmsRPCLimiter{name: name, slots: make(chan struct{}, maxInFlight)}
+}
+
+// acquire blocks until an in-flight slot is free or the context is canceled.
+func (l *kmsRPCLimiter) acquire(ctx context.Context) error {
+	start := time.Now()
+	envelopemetrics.RecordKMSRPCQueued(l.name)
+	defer envelopemetrics.RecordKMSRPCDequeued(l.name, start)
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *kmsRPCLimiter) release() {
+	<-l.slots
+}
+
+// limitedEnvelopeService wraps a KMSv1 service with a kmsRPCLimiter. The v1
+// Service API carries no context, so waiters can only be released by a slot
+// freeing up.
+type limitedEnvelopeService struct {
+	limiter *kmsRPCLimiter
+	service envelope.Service
+}
+
+var _ envelope.Service = &limitedEnvelopeService{}
+
+func (s *limitedEnvelopeService) Decrypt(data []byte) ([]byte, error) {
+	if err := s.limiter.acquire(context.Background()); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release()
+	return s.service.Decrypt(data)
+}
+
+func (s *limitedEnvelopeService) Encrypt(data []byte) ([]byte, error) {
+	if err := s.limiter.acquire(context.Background()); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release()
+	return s.service.Encrypt(data)
+}
+
+// limitedKMSv2Service is the KMSv2 equivalent of limitedEnvelopeService.
+// Status calls bypass the limiter so health probes are not starved by queued
+// transforms.
+type limitedKMSv2Service struct {
+	limiter *kmsRPCLimiter
+	service envelopekmsv2.Service
+}
+
+var _ envelopekmsv2.Service = &limitedKMSv2Service{}
+
+func (s *limitedKMSv2Service) Decrypt(ctx context.Context, uid string, req *envelopekmsv2.DecryptRequest) ([]byte, error) {
+	if err := s.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release()
+	return s.service.Decrypt(ctx, uid, req)
+}
+
+func (s *limitedKMSv2Service) Encrypt(ctx context.Context, uid string, data []byte) (*envelopekmsv2.EncryptResponse, error) {
+	if err := s.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.limiter.release()
+	return s.service.Encrypt(ctx, uid, data)
+}
+
+func (s *limitedKMSv2Service) Status(ctx context.Context) (*envelopekmsv2.StatusResponse, error) {
+	return s.service.Status(ctx)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+)
+
+// blockingEnvelopeService reports on entered when a call reaches the plugin
+// and holds the call until proceed is closed.
+type blockingEnvelopeService struct {
+	entered chan struct{}
+	proceed chan struct{}
+}
+
+func (s *blockingEnvelopeService) Decrypt(data []byte) ([]byte, error) {
+	s.entered <- struct{}{}
+	<-s.proceed
+	return data, nil
+}
+
+func (s *blockingEnvelopeService) Encrypt(data []byte) ([]byte, error) {
+	s.entered <- struct{}{}
+	<-s.proceed
+	return data, nil
+}
+
+func TestLimitedEnvelopeServiceBoundsConcurrency(t *testing.T) {
+	entered := make(chan struct{})
+	proceed := make(chan struct{})
+	service := &limitedEnvelopeService{
+		limiter: newKMSRPCLimiter("panda", 1),
+		service: &blockingEnvelopeService{entered: entered, proceed: proceed},
+	}
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := service.Decrypt([]byte("ciphertext"))
+			results <- err
+		}()
+	}
+
+	// the first call holds the only slot inside the plugin
+	<-entered
+	select {
+	case <-entered:
+		t.Fatal("second call reached the plugin while the first held the only in-flight slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(proceed)
+	// the second call reaches the plugin once the slot frees up
+	<-entered
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestLimitedKMSv2ServiceContextCancel(t *testing.T) {
+	limiter := newKMSRPCLimiter("panda", 1)
+	// occupy the only slot so the call has to wait
+	limiter.slots <- struct{}{}
+	service := &limitedKMSv2Service{limiter: limiter}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := service.Decrypt(ctx, "uid", &envelopekmsv2.DecryptRequest{}); err != context.Canceled {
+		t.Errorf("expected context.Canceled while waiting for a slot, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+func writeTestKeyring(t *testing.T, keyNames ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	for i, name := range keyNames {
+		secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{byte(i + 1)}, 32))
+		fmt.Fprintf(&buf, "- name: %s\n  secret: %s\n", name, secret)
+	}
+	keyringFile := filepath.Join(t.TempDir(), "keyring.yaml")
+	if err := os.WriteFile(keyringFile, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return keyringFile
+}
+
+func TestLoadKeyring(t *testing.T) {
+	keyringFile := writeTestKeyring(t, "key1", "key2", "key3")
+
+	keys, err := loadKeyring(keyringFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the last key appended to the file must come back first: it is the write key.
+	wantOrder := []string{"key3", "key2", "key1"}
+	if len(keys) != len(wantOrder) {
+		t.Fatalf("expected %d keys, got %d", len(wantOrder), len(keys))
+	}
+	for i, want := range wantOrder {
+		if keys[i].Name != want {
+			t.Errorf("keys[%d].Name = %q, want %q", i, keys[i].Name, want)
+		}
+	}
+}
+
+func TestLoadKeyringErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := loadKeyring(filepath.Join(t.TempDir(), "no-such-keyring.yaml")); err == nil {
+			t.Fatal("expected error for missing keyring file")
+		}
+	})
+
+	t.Run("empty keyring", func(t *testing.T) {
+		keyringFile := filepath.Join(t.TempDir(), "keyring.yaml")
+		if err := os.WriteFile(keyringFile, []byte("[]\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadKeyring(keyringFile); err == nil {
+			t.Fatal("expected error for empty keyring file")
+		}
+	})
+
+	t.Run("duplicate key names", func(t *testing.T) {
+		keyringFile := writeTestKeyring(t, "key1", "key1")
+		if _, err := loadKeyring(keyringFile); err == nil {
+			t.Fatal("expected error for duplicate key names")
+		}
+	})
+}
+
+func TestKeyringPrefixTransformer(t *testing.T) {
+	keyringFile := writeTestKeyring(t, "key1", "key2")
+
+	result, err := keyringPrefixTransformer(&apiserverconfig.KeyringConfiguration{Path: keyringFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Prefix) != aesGCMTransformerPrefixV1 {
+		t.Errorf("prefix = %q, want %q", result.Prefix, aesGCMTransformerPrefixV1)
+	}
+
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte("authenticated_data"))
+
+	out, err := result.Transformer.TransformToStorage(ctx, []byte("firstvalue"), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// new writes must use the newest key in the keyring.
+	if !bytes.HasPrefix(out, []byte("key2:")) {
+		t.Fatalf("new write did not use the newest key: %q", out)
+	}
+	from, stale, err := result.Transformer.TransformFromStorage(ctx, out, dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale || !bytes.Equal([]byte("firstvalue"), from) {
+		t.Fatalf("unexpected data: %t %q", stale, from)
+	}
+
+	// a record written under a key that was since removed from the keyring
+	// must be refused with an error instead of silently decrypting.
+	shrunkKeyring := writeTestKeyring(t, "key3")
+	shrunk, err := keyringPrefixTransformer(&apiserverconfig.KeyringConfiguration{Path: shrunkKeyring})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := shrunk.Transformer.TransformFromStorage(ctx, out, dataCtx); err == nil {
+		t.Fatal("expected error reading record written under a removed key")
+	}
+}
+
+func TestKeyringChangesHash(t *testing.T) {
+	keyringFile := writeTestKeyring(t, "key1")
+	configFile := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	configYAML := fmt.Sprintf(`
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - keyring:
+        path: %s
+`, keyringFile)
+	if err := os.WriteFile(configFile, []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, firstHash, err := loadConfig(configFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// appending a key to the keyring leaves the config file untouched but
+	// must still change the computed hash, or the reload controller would
+	// never pick the new write key up.
+	f, err := os.OpenFile(keyringFile, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{9}, 32))
+	if _, err := fmt.Fprintf(f, "- name: key2\n  secret: %s\n", secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, secondHash, err := loadConfig(configFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstHash == secondHash {
+		t.Fatal("hash did not change after the keyring file changed")
+	}
+
+	if got := ExternalFiles(configFile); len(got) != 1 || got[0] != keyringFile {
+		t.Errorf("ExternalFiles(%q) = %v, want [%q]", configFile, got, keyringFile)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope"
+	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+	envelopemetrics "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	registerActiveHashMetric sync.Once
+
+	encryptionConfigActiveInfo = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace: "apiserver",
+			Name:      "encryption_config_active_info",
+			Help: "Information about the encryption configuration this API server is actively serving with. " +
+				"The config_hash label identifies the active configuration; a hash that differs across " +
+				"apiservers indicates a partial rollout.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"config_hash"},
+	)
+
+	activeConfigHashLock sync.RWMutex
+	activeConfigHash     string
+
+	registerReloadFailureMetric sync.Once
+
+	encryptionConfigReloadFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace: "apiserver",
+			Name:      "encryption_config_reload_failures_total",
+			Help: "Total number of rejected encryption configuration hot reloads, partitioned by the " +
+				"hash of the attempted configuration and the failure class. A nonzero count for the " +
+				"hash a rollout just pushed means that configuration was rejected.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"config_hash", "reason"},
+	)
+)
+
+// The failure classes a rejected encryption configuration reload is
+// attributed to.
+const (
+	// ReloadFailureReasonValidation covers configurations that could not be
+	// read, decoded or that failed validation.
+	ReloadFailureReasonValidation = "validation"
+	// ReloadFailureReasonPlugin covers failures constructing the transformers,
+	// including dialing KMS plugins.
+	ReloadFailureReasonPlugin = "plugin"
+	// ReloadFailureReasonHealthz covers new transformers that never became
+	// healthy within the grace period.
+	ReloadFailureReasonHealthz = "healthz"
+	// ReloadFailureReasonInternal covers everything else.
+	ReloadFailureReasonInternal = "internal"
+)
+
+// recordReloadFailureMetric counts one rejected reload attempt. The empty
+// hash, reported when the attempted file could not even be read, is published
+// as "unknown".
+func recordReloadFailureMetric(configHash, reason string) {
+	registerReloadFailureMetric.Do(func() {
+		legacyregistry.MustRegister(encryptionConfigReloadFailures)
+	})
+
+	if configHash == "" {
+		configHash = "unknown"
+	}
+	encryptionConfigReloadFailures.WithLabelValues(configHash, reason).Inc()
+}
+
+// RecordActiveConfigHash publishes the hash of the encryption configuration
+// this server is actively serving with, both as the
+// apiserver_encryption_config_active_info metric and through ActiveConfigHash
+// for the kube-apiserver identity lease annotation.
+func RecordActiveConfigHash(hash string) {
+	registerActiveHashMetric.Do(func() {
+		legacyregistry.MustRegister(encryptionConfigActiveInfo)
+	})
+
+	activeConfigHashLock.Lock()
+	defer activeConfigHashLock.Unlock()
+
+	if activeConfigHash == hash {
+		return
+	}
+	if activeConfigHash != "" {
+		encryptionConfigActiveInfo.DeleteLabelValues(activeConfigHash)
+	}
+	activeConfigHash = hash
+	encryptionConfigActiveInfo.WithLabelValues(hash).Set(1)
+}
+
+// ActiveConfigHash returns the hash of the encryption configuration the
+// server is currently serving with, or the empty string when encryption at
+// rest is not configured.
+func ActiveConfigHash() string {
+	activeConfigHashLock.RLock()
+	defer activeConfigHashLock.RUnlock()
+	return activeConfigHash
+}
+
+// instrumentedEnvelopeService wraps a KMSv1 service so every Encrypt and
+// Decrypt RPC is recorded under the configured provider name.
+type instrumentedEnvelopeService struct {
+	name    string
+	service envelope.Service
+}
+
+var _ envelope.Service = &instrumentedEnvelopeService{}
+
+func (s *instrumentedEnvelopeService) Decrypt(data []byte) ([]byte, error) {
+	start := time.Now()
+	out, err := s.service.Decrypt(data)
+	envelopemetrics.RecordKMSOperationLatency(context.Background(), s.name, "Decrypt", time.Since(start), err)
+	return out, err
+}
+
+func (s *instrumentedEnvelopeService) Encrypt(data []byte) ([]byte, error) {
+	start := time.Now()
+	out, err := s.service.Encrypt(data)
+	envelopemetrics.RecordKMSOperationLatency(context.Background(), s.name, "Encrypt", time.Since(start), err)
+	return out, err
+}
+
+// instrumentedKMSv2Service is the KMSv2 equivalent of
+// instrumentedEnvelopeService.
+type instrumentedKMSv2Service struct {
+	name    string
+	service envelopekmsv2.Service
+}
+
+var _ envelopekmsv2.Service = &instrumentedKMSv2Service{}
+
+func (s *instrumentedKMSv2Service) Decrypt(ctx context.Context, uid string, req *envelopekmsv2.DecryptRequest) ([]byte, error) {
+	start := time.Now()
+	out, err := s.service.Decrypt(ctx, uid, req)
+	envelopemetrics.RecordKMSOperationLatency(ctx, s.name, "Decrypt", time.Since(start), err)
+	return out, err
+}
+
+func (s *instrumentedKMSv2Service) Encrypt(ctx context.Context, uid string, data []byte) (*envelopekmsv2.EncryptResponse, error) {
+	start := time.Now()
+	out, err := s.service.Encrypt(ctx, uid, data)
+	envelopemetrics.RecordKMSOperationLatency(ctx, s.name, "Encrypt", time.Since(start), err)
+	return out, err
+}
+
+func (s *instrumentedKMSv2Service) Status(ctx context.Context) (*envelopekmsv2.StatusResponse, error) {
+	start := time.Now()
+	out, err := s.service.Status(ctx)
+	envelopemetrics.RecordKMSOperationLatency(ctx, s.name, "Status", time.Since(start), err)
+	return out, err
+}
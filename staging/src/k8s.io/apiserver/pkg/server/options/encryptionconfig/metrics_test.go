@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	envelopemetrics "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+type fakeEnvelopeService struct {
+	err error
+}
+
+func (f *fakeEnvelopeService) Decrypt(data []byte) ([]byte, error) {
+	return data, f.err
+}
+
+func (f *fakeEnvelopeService) Encrypt(data []byte) ([]byte, error) {
+	return data, f.err
+}
+
+func TestInstrumentedEnvelopeService(t *testing.T) {
+	envelopemetrics.RegisterMetrics()
+
+	service := &instrumentedEnvelopeService{
+		name:    "metrics-test-provider",
+		service: &fakeEnvelopeService{err: status.Error(codes.Unavailable, "not there")},
+	}
+
+	if _, err := service.Encrypt([]byte("foo")); err == nil {
+		t.Fatal("expected error from envelope service")
+	}
+	if _, err := service.Decrypt([]byte("foo")); err == nil {
+		t.Fatal("expected error from envelope service")
+	}
+
+	want := `
+		# HELP apiserver_envelope_encryption_kms_operations_errors_total [ALPHA] Total number of failed KMS operations, broken down by provider name, method name and gRPC status code.
+		# TYPE apiserver_envelope_encryption_kms_operations_errors_total counter
+		apiserver_envelope_encryption_kms_operations_errors_total{grpc_status_code="Unavailable",method_name="Decrypt",provider_name="metrics-test-provider"} 1
+		apiserver_envelope_encryption_kms_operations_errors_total{grpc_status_code="Unavailable",method_name="Encrypt",provider_name="metrics-test-provider"} 1
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_envelope_encryption_kms_operations_errors_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordActiveConfigHash(t *testing.T) {
+	RecordActiveConfigHash("sha256:aaaa")
+
+	if got := ActiveConfigHash(); got != "sha256:aaaa" {
+		t.Fatalf("expected active config hash %q, got %q", "sha256:aaaa", got)
+	}
+
+	want := `
+		# HELP apiserver_encryption_config_active_info [ALPHA] Information about the encryption configuration this API server is actively serving with. The config_hash label identifies the active configuration; a hash that differs across apiservers indicates a partial rollout.
+		# TYPE apiserver_encryption_config_active_info gauge
+		apiserver_encryption_config_active_info{config_hash="sha256:aaaa"} 1
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_encryption_config_active_info"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a reload with a new hash must replace the old series, not add to it
+	RecordActiveConfigHash("sha256:bbbb")
+
+	want = `
+		# HELP apiserver_encryption_config_active_info [ALPHA] Information about the encryption configuration this API server is actively serving with. The config_hash label identifies the active configuration; a hash that differs across apiservers indicates a partial rollout.
+		# TYPE apiserver_encryption_config_active_info gauge
+		apiserver_encryption_config_active_info{config_hash="sha256:bbbb"} 1
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_encryption_config_active_info"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordReloadFailure(t *testing.T) {
+	RecordReloadFailure(&ReloadFailure{
+		ConfigHash: "sha256:cccc",
+		FailedAt:   time.Now(),
+		Reason:     ReloadFailureReasonValidation,
+		Message:    "spec must not be empty",
+	})
+	RecordReloadFailure(&ReloadFailure{
+		FailedAt: time.Now(),
+		Reason:   ReloadFailureReasonHealthz,
+		Message:  "health check for new transformers failed",
+	})
+
+	failure := LastReloadFailure()
+	if failure == nil || failure.Reason != ReloadFailureReasonHealthz {
+		t.Fatalf("unexpected last reload failure %+v", failure)
+	}
+
+	want := `
+		# HELP apiserver_encryption_config_reload_failures_total [ALPHA] Total number of rejected encryption configuration hot reloads, partitioned by the hash of the attempted configuration and the failure class. A nonzero count for the hash a rollout just pushed means that configuration was rejected.
+		# TYPE apiserver_encryption_config_reload_failures_total counter
+		apiserver_encryption_config_reload_failures_total{config_hash="sha256:cccc",reason="validation"} 1
+		apiserver_encryption_config_reload_failures_total{config_hash="unknown",reason="healthz"} 1
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_encryption_config_reload_failures_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+)
+
+const (
+	// PrefixCheckWarn logs records with unrecognized prefixes and lets the
+	// server start.
+	PrefixCheckWarn = "warn"
+	// PrefixCheckFail refuses to serve when records with unrecognized
+	// prefixes exist.
+	PrefixCheckFail = "fail"
+
+	// prefixCheckSampleLimit caps how many records are read per resource, to
+	// bound the load the startup check puts on etcd.
+	prefixCheckSampleLimit = 1000
+)
+
+// CheckStoredPrefixes samples the raw records of every resource declared in
+// the encryption configuration at filepath and reports records whose storage
+// prefix is not produced by any provider configured for that resource. Such
+// records are unreadable: the usual cause is a provider stanza deleted from
+// the configuration before its records were migrated, and catching it at
+// startup beats users discovering it one decrypt failure at a time.
+func CheckStoredPrefixes(ctx context.Context, filepath string, reload bool, lister RawRecordLister) error {
+	config, _, err := loadConfig(filepath, reload)
+	if err != nil {
+		return fmt.Errorf("failed to load encryption configuration: %w", err)
+	}
+
+	resources, allowed, err := configuredPrefixes(config)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, gr := range resources {
+		values, err := lister(ctx, gr, prefixCheckSampleLimit)
+		if err != nil {
+			return fmt.Errorf("failed to sample stored %s records: %w", gr, err)
+		}
+
+		unknown := map[string]int{}
+		for _, value := range values {
+			prefix, ok := encryptionPrefix(value)
+			if !ok || allowed[gr][prefix] {
+				continue
+			}
+			unknown[prefix]++
+		}
+
+		prefixes := make([]string, 0, len(unknown))
+		for prefix := range unknown {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			problems = append(problems, fmt.Sprintf("%d of %d sampled %s records carry prefix %q, which no configured provider can decrypt",
+				unknown[prefix], len(values), gr, prefix))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("stored records are not covered by the encryption configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// configuredPrefixes returns the resources declared in the configuration, in
+// declaration order, and the storage prefixes the providers configured for
+// each resource can decrypt. Wildcard resource entries do not map to a single
+// key prefix and are skipped, as in the coverage sampler.
+func configuredPrefixes(config *apiserverconfig.EncryptionConfiguration) ([]schema.GroupResource, map[schema.GroupResource]map[string]bool, error) {
+	var resources []schema.GroupResource
+	allowed := map[schema.GroupResource]map[string]bool{}
+	for _, resourceConfig := range config.Resources {
+		var prefixes []string
+		for _, provider := range resourceConfig.Providers {
+			providerPrefixes, err := prefixesForProvider(provider)
+			if err != nil {
+				return nil, nil, err
+			}
+			prefixes = append(prefixes, providerPrefixes...)
+		}
+
+		for _, resource := range resourceConfig.Resources {
+			gr := schema.ParseGroupResource(resource)
+			if strings.Contains(gr.Resource, "*") {
+				continue
+			}
+			if allowed[gr] == nil {
+				resources = append(resources, gr)
+				allowed[gr] = map[string]bool{}
+			}
+			for _, prefix := range prefixes {
+				allowed[gr][prefix] = true
+			}
+		}
+	}
+	return resources, allowed, nil
+}
+
+// prefixesForProvider returns the full storage prefixes,
+// k8s:enc:<provider>:<version>:<name>:, of the records the provider can
+// decrypt. AES, Secretbox and keyring providers write one prefix per key;
+// envelope providers write one prefix per provider name. Identity writes
+// plaintext and contributes none.
+func prefixesForProvider(provider apiserverconfig.ProviderConfiguration) ([]string, error) {
+	keyPrefixes := func(typePrefix string, keys []apiserverconfig.Key) []string {
+		prefixes := make([]string, 0, len(keys))
+		for _, key := range keys {
+			prefixes = append(prefixes, typePrefix+key.Name+":")
+		}
+		return prefixes
+	}
+
+	switch {
+	case provider.AESGCM != nil:
+		return keyPrefixes(aesGCMTransformerPrefixV1, provider.AESGCM.Keys), nil
+	case provider.AESCBC != nil:
+		return keyPrefixes(aesCBCTransformerPrefixV1, provider.AESCBC.Keys), nil
+	case provider.Secretbox != nil:
+		return keyPrefixes(secretboxTransformerPrefixV1, provider.Secretbox.Keys), nil
+	case provider.Keyring != nil:
+		keys, err := loadKeyring(provider.Keyring.Path)
+		if err != nil {
+			return nil, err
+		}
+		// records written by a keyring provider use the plain AES prefixes
+		switch provider.Keyring.Mode {
+		case "aesgcm", "":
+			return keyPrefixes(aesGCMTransformerPrefixV1, keys), nil
+		case "aescbc":
+			return keyPrefixes(aesCBCTransformerPrefixV1, keys), nil
+		default:
+			return nil, fmt.Errorf("unsupported mode %q for keyring provider", provider.Keyring.Mode)
+		}
+	case provider.KMS != nil:
+		if provider.KMS.APIVersion == kmsAPIVersionV2 || provider.KMS.APIVersion == kmsAPIVersionV3 {
+			return []string{kmsTransformerPrefixV2 + provider.KMS.Name + ":"}, nil
+		}
+		return []string{kmsTransformerPrefixV1 + provider.KMS.Name + ":"}, nil
+	case provider.KMSSplit != nil:
+		return []string{kmsSplitTransformerPrefixV1 + provider.KMSSplit.Name + ":"}, nil
+	case provider.TPM != nil:
+		return []string{tpmTransformerPrefixV1 + provider.TPM.Name + ":"}, nil
+	case provider.X25519 != nil:
+		return []string{x25519TransformerPrefixV1 + provider.X25519.Name + ":"}, nil
+	case provider.Identity != nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("provider does not contain any of the expected providers")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCheckStoredPrefixesCovered(t *testing.T) {
+	configFile := writeProvidersDebugTestConfig(t)
+
+	lister := func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error) {
+		if resource.Resource != "secrets" {
+			t.Errorf("unexpected resource sampled: %v", resource)
+		}
+		return [][]byte{
+			kmsv2Record(t, "provider-1", "key-1"),
+			[]byte("k8s:enc:aesgcm:v1:key1:ciphertext"),
+			// identity is configured, so plaintext records are fine
+			[]byte("plaintext record"),
+		}, nil
+	}
+
+	if err := CheckStoredPrefixes(context.Background(), configFile, false, lister); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckStoredPrefixesUnknown(t *testing.T) {
+	configFile := writeProvidersDebugTestConfig(t)
+
+	lister := func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error) {
+		return [][]byte{
+			kmsv2Record(t, "provider-1", "key-1"),
+			// a provider stanza for these was deleted from the config
+			[]byte("k8s:enc:aescbc:v1:retired-key:ciphertext"),
+			[]byte("k8s:enc:aescbc:v1:retired-key:more ciphertext"),
+			[]byte("k8s:enc:kms:v2:deleted-provider:ciphertext"),
+		}, nil
+	}
+
+	err := CheckStoredPrefixes(context.Background(), configFile, false, lister)
+	if err == nil {
+		t.Fatal("expected an error for records with unknown prefixes")
+	}
+	for _, want := range []string{
+		`2 of 4 sampled secrets records carry prefix "k8s:enc:aescbc:v1:retired-key:"`,
+		`1 of 4 sampled secrets records carry prefix "k8s:enc:kms:v2:deleted-provider:"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestCheckStoredPrefixesListerError(t *testing.T) {
+	configFile := writeProvidersDebugTestConfig(t)
+
+	lister := func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error) {
+		return nil, errors.New("etcd is down")
+	}
+
+	err := CheckStoredPrefixes(context.Background(), configFile, false, lister)
+	if err == nil || !strings.Contains(err.Error(), "etcd is down") {
+		t.Errorf("expected the lister error to be reported, got: %v", err)
+	}
+}
+
+func TestConfiguredPrefixes(t *testing.T) {
+	configFile := writeProvidersDebugTestConfig(t)
+	config, _, err := loadConfig(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources, allowed, err := configuredPrefixes(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 || resources[0].Resource != "secrets" {
+		t.Fatalf("unexpected resources %v", resources)
+	}
+
+	prefixes := allowed[schema.GroupResource{Resource: "secrets"}]
+	if len(prefixes) != 2 || !prefixes["k8s:enc:kms:v2:provider-1:"] || !prefixes["k8s:enc:aesgcm:v1:key1:"] {
+		t.Errorf("unexpected prefixes %v", prefixes)
+	}
+}
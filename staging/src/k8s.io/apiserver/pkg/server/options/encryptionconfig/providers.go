@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	envelopemetrics "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
+)
+
+// Connection states reported for KMS providers, based on the most recent
+// health probe.
+const (
+	providerConnected = "Connected"
+	providerUnhealthy = "Unhealthy"
+	providerUnknown   = "Unknown"
+)
+
+// EncryptionProvidersDebugInfo is the response body of the
+// /debug/encryption/providers endpoint.
+type EncryptionProvidersDebugInfo struct {
+	Providers []ProviderDebugInfo `json:"providers"`
+}
+
+// ProviderDebugInfo summarizes the live state of one configured encryption
+// provider: what the health probe last saw, the latency of recent KMS
+// operations and the state of the provider's DEK cache. Local providers that
+// run in process carry only their name and health.
+type ProviderDebugInfo struct {
+	Name string `json:"name"`
+	// ConnectionState is the health probe's view of the plugin connection:
+	// Connected, Unhealthy or Unknown. Only set for KMS providers.
+	ConnectionState string `json:"connectionState,omitempty"`
+	// Health is "ok", "unknown", or the error returned by the last probe.
+	Health string `json:"health"`
+	// KeyID is the key ID the KMSv2 plugin reported on the last probe.
+	KeyID         string     `json:"keyID,omitempty"`
+	LastProbeTime *time.Time `json:"lastProbeTime,omitempty"`
+	// Operations carries recent latency percentiles per KMS method.
+	Operations []envelopemetrics.OperationStats `json:"operations,omitempty"`
+	// DEKCache is set for KMSv2 providers that cache data encryption keys.
+	DEKCache *envelopemetrics.DEKCacheStats `json:"dekCache,omitempty"`
+}
+
+// kmsProviderProbeResult is what the health probes last observed for a single
+// KMS provider.
+type kmsProviderProbeResult struct {
+	err      string
+	keyID    string
+	probedAt time.Time
+}
+
+var (
+	kmsProviderProbesLock sync.Mutex
+	kmsProviderProbes     = map[string]kmsProviderProbeResult{}
+)
+
+// recordKMSProviderProbe stores the outcome of a health probe so the debug
+// endpoint can report connection state, health and key ID per provider.
+func recordKMSProviderProbe(name, keyID string, err error) {
+	kmsProviderProbesLock.Lock()
+	defer kmsProviderProbesLock.Unlock()
+
+	result := kmsProviderProbeResult{keyID: keyID, probedAt: time.Now()}
+	if err != nil {
+		result.err = err.Error()
+	}
+	kmsProviderProbes[name] = result
+}
+
+// lastKMSProviderProbe returns the most recent probe result for the named KMS
+// provider, if it has been probed since the server started.
+func lastKMSProviderProbe(name string) (kmsProviderProbeResult, bool) {
+	kmsProviderProbesLock.Lock()
+	defer kmsProviderProbesLock.Unlock()
+
+	result, ok := kmsProviderProbes[name]
+	return result, ok
+}
+
+// GetEncryptionProvidersDebugInfo computes the live state of every provider
+// declared in the encryption configuration file at filepath. Like
+// GetEncryptionState it only parses the file; all runtime data comes from
+// observations the health probes and transformers have already recorded.
+func GetEncryptionProvidersDebugInfo(filepath string, reload bool) (*EncryptionProvidersDebugInfo, error) {
+	config, _, err := loadConfig(filepath, reload)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &EncryptionProvidersDebugInfo{Providers: []ProviderDebugInfo{}}
+	seen := map[string]bool{}
+	for _, resource := range config.Resources {
+		for _, provider := range resource.Providers {
+			name := providerName(provider)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			entry := ProviderDebugInfo{Name: name, Health: "ok"}
+			if provider.KMS != nil {
+				kmsName := provider.KMS.Name
+				entry.ConnectionState = providerUnknown
+				entry.Health = "unknown"
+				if probe, ok := lastKMSProviderProbe(kmsName); ok {
+					entry.KeyID = probe.keyID
+					probedAt := probe.probedAt
+					entry.LastProbeTime = &probedAt
+					if probe.err == "" {
+						entry.ConnectionState = providerConnected
+						entry.Health = "ok"
+					} else {
+						entry.ConnectionState = providerUnhealthy
+						entry.Health = probe.err
+					}
+				}
+				entry.Operations = envelopemetrics.ProviderOperationStats(kmsName)
+				entry.DEKCache = envelopemetrics.ProviderDEKCacheStats(kmsName)
+			}
+			info.Providers = append(info.Providers, entry)
+		}
+	}
+
+	return info, nil
+}
+
+// NewEncryptionProvidersHandler returns a handler serving per-provider live
+// statistics for the encryption configuration file at filepath as JSON. The
+// provider list is recomputed per request so it stays accurate while the
+// configuration is hot reloaded.
+func NewEncryptionProvidersHandler(filepath string, reload bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := GetEncryptionProvidersDebugInfo(filepath, reload)
+		if err != nil {
+			http.Error(w, "failed to compute encryption provider statistics", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(info); err != nil {
+			http.Error(w, "failed to encode encryption provider statistics", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeProvidersDebugTestConfig(t *testing.T) string {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	configYAML := `
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - kms:
+        apiVersion: v2
+        name: provider-1
+        endpoint: unix:///tmp/kms-provider.sock
+    - aesgcm:
+        keys:
+        - name: key1
+          secret: c2VjcmV0IGlzIHNlY3VyZSwgaXMgaXQ/
+    - identity: {}
+`
+	if err := os.WriteFile(configFile, []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return configFile
+}
+
+func resetKMSProviderProbes() {
+	kmsProviderProbesLock.Lock()
+	defer kmsProviderProbesLock.Unlock()
+	kmsProviderProbes = map[string]kmsProviderProbeResult{}
+}
+
+func TestGetEncryptionProvidersDebugInfo(t *testing.T) {
+	resetKMSProviderProbes()
+	defer resetKMSProviderProbes()
+
+	configFile := writeProvidersDebugTestConfig(t)
+
+	info, err := GetEncryptionProvidersDebugInfo(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Providers) != 3 {
+		t.Fatalf("expected 3 providers, got %+v", info.Providers)
+	}
+
+	// before the first probe the KMS provider's state is unknown
+	kms := info.Providers[0]
+	if kms.Name != "kms:v2:provider-1" {
+		t.Errorf("unexpected provider name %q", kms.Name)
+	}
+	if kms.ConnectionState != providerUnknown || kms.Health != "unknown" {
+		t.Errorf("expected unknown state before the first probe, got %+v", kms)
+	}
+
+	// local providers are always healthy and carry no connection state
+	if got := info.Providers[1]; got.Name != "aesgcm" || got.Health != "ok" || got.ConnectionState != "" {
+		t.Errorf("unexpected local provider entry %+v", got)
+	}
+
+	recordKMSProviderProbe("provider-1", "key-id-1", nil)
+	info, err = GetEncryptionProvidersDebugInfo(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms = info.Providers[0]
+	if kms.ConnectionState != providerConnected || kms.Health != "ok" || kms.KeyID != "key-id-1" {
+		t.Errorf("expected a connected provider after a successful probe, got %+v", kms)
+	}
+	if kms.LastProbeTime == nil {
+		t.Error("expected the last probe time to be set")
+	}
+
+	recordKMSProviderProbe("provider-1", "key-id-1", errors.New("connection refused"))
+	info, err = GetEncryptionProvidersDebugInfo(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms = info.Providers[0]
+	if kms.ConnectionState != providerUnhealthy || kms.Health != "connection refused" {
+		t.Errorf("expected an unhealthy provider after a failed probe, got %+v", kms)
+	}
+}
+
+func TestEncryptionProvidersHandler(t *testing.T) {
+	resetKMSProviderProbes()
+	defer resetKMSProviderProbes()
+
+	configFile := writeProvidersDebugTestConfig(t)
+	recordKMSProviderProbe("provider-1", "key-id-1", nil)
+
+	handler := NewEncryptionProvidersHandler(configFile, false)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/encryption/providers", nil))
+	if recorder.Code != 200 {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	var served EncryptionProvidersDebugInfo
+	if err := json.Unmarshal(recorder.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if len(served.Providers) != 3 {
+		t.Fatalf("expected 3 providers, got %+v", served.Providers)
+	}
+	if served.Providers[0].KeyID != "key-id-1" {
+		t.Errorf("expected the served entry to carry the probed key ID, got %+v", served.Providers[0])
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+// recordingTransformer reads succeed except for keys in failKeys, which fail
+// as an undecryptable record would.
+type recordingTransformer struct {
+	failKeys map[string]bool
+}
+
+func (t *recordingTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	if t.failKeys[string(dataCtx.AuthenticatedData())] {
+		return nil, false, errors.New("no matching key was found")
+	}
+	return data, false, nil
+}
+
+func (t *recordingTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return data, nil
+}
+
+func decryptabilityCheckFixture(t *testing.T, samples int64, records []RawRecord, listErr error, transformer value.Transformer) (*DecryptabilityCheck, *int) {
+	configFile := writeProvidersDebugTestConfig(t)
+
+	listCalls := 0
+	lister := func(ctx context.Context, resource schema.GroupResource, limit int64) ([]RawRecord, error) {
+		listCalls++
+		if resource.Resource != "secrets" {
+			t.Errorf("unexpected resource sampled: %v", resource)
+		}
+		if limit != samples {
+			t.Errorf("expected the sample size %d as list limit, got %d", samples, limit)
+		}
+		return records, listErr
+	}
+
+	check := NewDecryptabilityCheck(configFile, false, samples, lister, func(schema.GroupResource) value.Transformer {
+		return transformer
+	})
+	return check, &listCalls
+}
+
+func TestDecryptabilityCheckPasses(t *testing.T) {
+	records := []RawRecord{
+		{Key: "/registry/secrets/ns/one", Value: []byte("k8s:enc:aesgcm:v1:key1:ciphertext")},
+		{Key: "/registry/secrets/ns/two", Value: []byte("k8s:enc:aesgcm:v1:key1:more ciphertext")},
+	}
+	check, listCalls := decryptabilityCheckFixture(t, 10, records, nil, &recordingTransformer{})
+
+	if err := check.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// once passed, the check must not touch storage again
+	if err := check.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *listCalls != 1 {
+		t.Errorf("expected a single sample after the check passed, got %d", *listCalls)
+	}
+}
+
+func TestDecryptabilityCheckFailsUntilRecordsDecrypt(t *testing.T) {
+	records := []RawRecord{
+		{Key: "/registry/secrets/ns/readable", Value: []byte("k8s:enc:aesgcm:v1:key1:ciphertext")},
+		{Key: "/registry/secrets/ns/unreadable", Value: []byte("k8s:enc:aesgcm:v1:retired-key:ciphertext")},
+	}
+	transformer := &recordingTransformer{failKeys: map[string]bool{"/registry/secrets/ns/unreadable": true}}
+	check, listCalls := decryptabilityCheckFixture(t, 10, records, nil, transformer)
+
+	err := check.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if err == nil {
+		t.Fatal("expected the undecryptable record to fail the check")
+	}
+	if !strings.Contains(err.Error(), "secrets record /registry/secrets/ns/unreadable") {
+		t.Errorf("expected the failing record to be named, got: %v", err)
+	}
+
+	// the record decrypts again, e.g. after the missing key was restored via
+	// hot reload: the next probe passes
+	transformer.failKeys = nil
+	if err := check.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *listCalls != 2 {
+		t.Errorf("expected one sample per probe until the check passed, got %d", *listCalls)
+	}
+}
+
+func TestDecryptabilityCheckListerError(t *testing.T) {
+	check, _ := decryptabilityCheckFixture(t, 10, nil, errors.New("etcd is down"), &recordingTransformer{})
+
+	err := check.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if err == nil || !strings.Contains(err.Error(), "etcd is down") {
+		t.Errorf("expected the lister error to be reported, got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/storage/value"
+	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+)
+
+// revokedKeyAuditAnnotationKey is the audit annotation recording which revoked
+// key or provider a request tried to read through, so the affected records can
+// be located from the audit log.
+const revokedKeyAuditAnnotationKey = "encryption.k8s.io/revoked-key"
+
+// revocationSet indexes the revocations declared in an encryption
+// configuration for lookup on the read and write paths.
+type revocationSet struct {
+	keyIDs    sets.String
+	providers sets.String
+}
+
+func newRevocationSet(revocations []apiserverconfig.RevocationConfiguration) *revocationSet {
+	rs := &revocationSet{keyIDs: sets.NewString(), providers: sets.NewString()}
+	for _, revocation := range revocations {
+		if len(revocation.KeyID) != 0 {
+			rs.keyIDs.Insert(revocation.KeyID)
+		}
+		if len(revocation.Provider) != 0 {
+			rs.providers.Insert(revocation.Provider)
+		}
+	}
+	return rs
+}
+
+func (r *revocationSet) providerRevoked(name string) bool {
+	return len(name) != 0 && r.providers.Has(name)
+}
+
+func (r *revocationSet) hasKeyIDs() bool {
+	return r.keyIDs.Len() > 0
+}
+
+// revokedProviderTransformer takes the place of a revoked provider's
+// transformer. Every read of a record still protected by the provider fails
+// with a revoked-key error and an audit annotation, so the affected records
+// can be located through the audit log and the
+// apiserver_storage_decrypt_errors_total{reason="revoked_key"} metric instead
+// of silently serving data protected by a compromised key.
+type revokedProviderTransformer struct {
+	name string
+}
+
+var _ value.Transformer = &revokedProviderTransformer{}
+
+func (t *revokedProviderTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	audit.AddAuditAnnotation(ctx, revokedKeyAuditAnnotationKey, t.name)
+	return nil, false, value.NewRevokedKeyError(fmt.Errorf("provider %q has been revoked and may no longer decrypt data", t.name))
+}
+
+func (t *revokedProviderTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return nil, value.NewRevokedKeyError(fmt.Errorf("provider %q has been revoked and may no longer encrypt data", t.name))
+}
+
+// revocationCheckingKMSv2Service refuses to use revoked key_ids: decrypts of
+// DEKs wrapped by a revoked key fail before the plugin is called, and encrypts
+// or status reports that come back under a revoked key are turned into errors
+// so the plugin operator is forced to rotate.
+type revocationCheckingKMSv2Service struct {
+	name    string
+	revoked *revocationSet
+	service envelopekmsv2.Service
+}
+
+var _ envelopekmsv2.Service = &revocationCheckingKMSv2Service{}
+
+func (s *revocationCheckingKMSv2Service) Decrypt(ctx context.Context, uid string, req *envelopekmsv2.DecryptRequest) ([]byte, error) {
+	if s.revoked.keyIDs.Has(req.KeyID) {
+		audit.AddAuditAnnotation(ctx, revokedKeyAuditAnnotationKey, fmt.Sprintf("%s/%s", s.name, req.KeyID))
+		return nil, value.NewRevokedKeyError(fmt.Errorf("key %q of KMS provider %q has been revoked and may no longer decrypt data", req.KeyID, s.name))
+	}
+	return s.service.Decrypt(ctx, uid, req)
+}
+
+func (s *revocationCheckingKMSv2Service) Encrypt(ctx context.Context, uid string, data []byte) (*envelopekmsv2.EncryptResponse, error) {
+	resp, err := s.service.Encrypt(ctx, uid, data)
+	if err == nil && s.revoked.keyIDs.Has(resp.KeyID) {
+		return nil, value.NewRevokedKeyError(fmt.Errorf("KMS provider %q encrypted with revoked key %q, the plugin must be rotated to a new key", s.name, resp.KeyID))
+	}
+	return resp, err
+}
+
+func (s *revocationCheckingKMSv2Service) Status(ctx context.Context) (*envelopekmsv2.StatusResponse, error) {
+	resp, err := s.service.Status(ctx)
+	if err == nil && s.revoked.keyIDs.Has(resp.KeyID) {
+		return nil, value.NewRevokedKeyError(fmt.Errorf("KMS provider %q reports revoked key %q as its current key", s.name, resp.KeyID))
+	}
+	return resp, err
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"testing"
+
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+	"k8s.io/apiserver/pkg/storage/value"
+	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+)
+
+func TestRevokedProviderTransformer(t *testing.T) {
+	transformer := &revokedProviderTransformer{name: "compromised-kms"}
+
+	if _, _, err := transformer.TransformFromStorage(context.Background(), []byte("data"), value.DefaultContext("key")); value.ReasonForError(err) != value.ReasonRevokedKey {
+		t.Errorf("expected revoked key error on read, got: %v", err)
+	}
+	if _, err := transformer.TransformToStorage(context.Background(), []byte("data"), value.DefaultContext("key")); value.ReasonForError(err) != value.ReasonRevokedKey {
+		t.Errorf("expected revoked key error on write, got: %v", err)
+	}
+}
+
+func TestRevocationCheckingKMSv2Service(t *testing.T) {
+	revoked := newRevocationSet([]apiserverconfig.RevocationConfiguration{{KeyID: "revoked-key"}})
+	service := &revocationCheckingKMSv2Service{
+		name:    "provider",
+		revoked: revoked,
+		service: &testKMSv2EnvelopeService{},
+	}
+
+	if _, err := service.Decrypt(context.Background(), "uid", &envelopekmsv2.DecryptRequest{KeyID: "revoked-key"}); value.ReasonForError(err) != value.ReasonRevokedKey {
+		t.Errorf("expected revoked key error decrypting with revoked key_id, got: %v", err)
+	}
+	if _, err := service.Decrypt(context.Background(), "uid", &envelopekmsv2.DecryptRequest{KeyID: "1", Ciphertext: []byte("aGVsbG8=")}); err != nil {
+		t.Errorf("expected decrypt with current key_id to pass through, got: %v", err)
+	}
+
+	// the fake service encrypts and reports status under key_id "1"
+	if _, err := service.Encrypt(context.Background(), "uid", []byte("data")); err != nil {
+		t.Errorf("expected encrypt under a non-revoked key_id to succeed, got: %v", err)
+	}
+	if _, err := service.Status(context.Background()); err != nil {
+		t.Errorf("expected status with a non-revoked key_id to succeed, got: %v", err)
+	}
+
+	service.revoked = newRevocationSet([]apiserverconfig.RevocationConfiguration{{KeyID: "1"}})
+	if _, err := service.Encrypt(context.Background(), "uid", []byte("data")); value.ReasonForError(err) != value.ReasonRevokedKey {
+		t.Errorf("expected revoked key error encrypting under a revoked key_id, got: %v", err)
+	}
+	if _, err := service.Status(context.Background()); value.ReasonForError(err) != value.ReasonRevokedKey {
+		t.Errorf("expected revoked key error when status reports a revoked key_id, got: %v", err)
+	}
+}
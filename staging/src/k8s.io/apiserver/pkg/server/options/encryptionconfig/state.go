@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
+)
+
+// EncryptionState summarizes the configured encryption providers in a form
+// suitable for auditors: one entry per storage prefix, carrying the
+// provenance metadata declared in the encryption configuration.
+type EncryptionState struct {
+	// FileContentHash identifies the configuration, including referenced
+	// keyring and key files, the state was computed from.
+	FileContentHash string `json:"fileContentHash"`
+	// Keys lists the storage prefixes of the configured keys and providers.
+	Keys []KeyState `json:"keys"`
+	// LastReload summarizes the most recent successful hot reload, when one
+	// has happened since the server started.
+	LastReload *ReloadSummary `json:"lastReload,omitempty"`
+	// LastReloadFailure describes the most recent rejected hot reload attempt,
+	// when one has happened since the server started. It is kept after later
+	// successful reloads so a rejected configuration push stays detectable.
+	LastReloadFailure *ReloadFailure `json:"lastReloadFailure,omitempty"`
+}
+
+// ReloadFailure describes one rejected hot reload attempt.
+type ReloadFailure struct {
+	// ConfigHash identifies the configuration the attempt tried to load. It is
+	// empty when the file could not even be read.
+	ConfigHash string `json:"configHash,omitempty"`
+	// FailedAt is when the attempt was rejected.
+	FailedAt time.Time `json:"failedAt"`
+	// Reason is one of the ReloadFailureReason constants.
+	Reason string `json:"reason"`
+	// Message is the error that rejected the attempt.
+	Message string `json:"message"`
+}
+
+// ReloadSummary describes the effect of one successful transformer swap in a
+// form that can be logged and served without diffing configuration files.
+type ReloadSummary struct {
+	// ConfigHash identifies the configuration the swap loaded.
+	ConfigHash string `json:"configHash"`
+	// ReloadedAt is when the new transformers became effective.
+	ReloadedAt time.Time `json:"reloadedAt"`
+	// ProvidersAdded and ProvidersRemoved list the provider names that appear
+	// in, or disappeared from, the configuration.
+	ProvidersAdded   []string `json:"providersAdded,omitempty"`
+	ProvidersRemoved []string `json:"providersRemoved,omitempty"`
+	// WriteProviderChanges maps each resource whose write provider changed to
+	// a "<old> -> <new>" description.
+	WriteProviderChanges map[string]string `json:"writeProviderChanges,omitempty"`
+}
+
+var (
+	reloadSummaryLock sync.Mutex
+	lastReloadSummary *ReloadSummary
+	lastReloadFailure *ReloadFailure
+)
+
+// RecordReloadSummary publishes the summary of a successful transformer swap
+// so it is served by the encryption state handler.
+func RecordReloadSummary(summary *ReloadSummary) {
+	reloadSummaryLock.Lock()
+	defer reloadSummaryLock.Unlock()
+	lastReloadSummary = summary
+}
+
+// LastReloadSummary returns the most recently recorded reload summary, or nil
+// if no reload has happened.
+func LastReloadSummary() *ReloadSummary {
+	reloadSummaryLock.Lock()
+	defer reloadSummaryLock.Unlock()
+	return lastReloadSummary
+}
+
+// RecordReloadFailure counts a rejected reload attempt in the failure metric
+// and keeps it for the encryption state handler.
+func RecordReloadFailure(failure *ReloadFailure) {
+	recordReloadFailureMetric(failure.ConfigHash, failure.Reason)
+
+	reloadSummaryLock.Lock()
+	defer reloadSummaryLock.Unlock()
+	lastReloadFailure = failure
+}
+
+// LastReloadFailure returns the most recently recorded reload failure, or nil
+// if no reload attempt has been rejected.
+func LastReloadFailure() *ReloadFailure {
+	reloadSummaryLock.Lock()
+	defer reloadSummaryLock.Unlock()
+	return lastReloadFailure
+}
+
+// KeyState ties a single storage prefix back to the key management record of
+// the key that produces it.
+type KeyState struct {
+	// Prefix is the full storage prefix, k8s:enc:<provider>:<version>:<name>:.
+	Prefix string `json:"prefix"`
+	// CreatedAt, Owner and Ticket mirror the provenance metadata declared for
+	// the key or provider, when present.
+	CreatedAt string `json:"createdAt,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+	Ticket    string `json:"ticket,omitempty"`
+}
+
+// GetEncryptionState computes the encryption state of the configuration file
+// at filepath. The state is derived from the file contents alone; no
+// transformers are constructed and no KMS plugins are contacted.
+func GetEncryptionState(filepath string, reload bool) (*EncryptionState, error) {
+	config, contentHash, err := loadConfig(filepath, reload)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &EncryptionState{FileContentHash: contentHash, LastReload: LastReloadSummary(), LastReloadFailure: LastReloadFailure()}
+	seen := map[string]bool{}
+	addKey := func(prefix string, provenance *apiserverconfig.ProvenanceConfiguration) {
+		if seen[prefix] {
+			return
+		}
+		seen[prefix] = true
+		keyState := KeyState{Prefix: prefix}
+		if provenance != nil {
+			keyState.CreatedAt = provenance.CreatedAt
+			keyState.Owner = provenance.Owner
+			keyState.Ticket = provenance.Ticket
+		}
+		state.Keys = append(state.Keys, keyState)
+	}
+	addKeys := func(prefix string, keys []apiserverconfig.Key) {
+		for _, key := range keys {
+			addKey(prefix+key.Name+":", key.Provenance)
+		}
+	}
+
+	for _, resource := range config.Resources {
+		for _, provider := range resource.Providers {
+			switch {
+			case provider.AESGCM != nil:
+				addKeys(aesGCMTransformerPrefixV1, provider.AESGCM.Keys)
+			case provider.AESCBC != nil:
+				addKeys(aesCBCTransformerPrefixV1, provider.AESCBC.Keys)
+			case provider.Secretbox != nil:
+				addKeys(secretboxTransformerPrefixV1, provider.Secretbox.Keys)
+			case provider.KMS != nil:
+				prefix := kmsTransformerPrefixV1
+				if provider.KMS.APIVersion == "v2" {
+					prefix = kmsTransformerPrefixV2
+				}
+				addKey(prefix+provider.KMS.Name+":", provider.KMS.Provenance)
+				for _, alias := range provider.KMS.Aliases {
+					addKey(prefix+alias+":", provider.KMS.Provenance)
+				}
+			case provider.TPM != nil:
+				addKey(tpmTransformerPrefixV1+provider.TPM.Name+":", nil)
+			case provider.X25519 != nil:
+				addKey(x25519TransformerPrefixV1+provider.X25519.Name+":", nil)
+			case provider.Keyring != nil:
+				keys, err := loadKeyring(provider.Keyring.Path)
+				if err != nil {
+					continue
+				}
+				prefix := aesGCMTransformerPrefixV1
+				if provider.Keyring.Mode == "aescbc" {
+					prefix = aesCBCTransformerPrefixV1
+				}
+				addKeys(prefix, keys)
+			case provider.KMSSplit != nil:
+				addKey(kmsSplitTransformerPrefixV1+provider.KMSSplit.Name+":", nil)
+			}
+		}
+	}
+
+	return state, nil
+}
+
+// NewEncryptionStateHandler returns a handler serving the encryption state of
+// the configuration file at filepath as JSON. The state is recomputed per
+// request so it stays accurate while the configuration is hot reloaded.
+func NewEncryptionStateHandler(filepath string, reload bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state, err := GetEncryptionState(filepath, reload)
+		if err != nil {
+			http.Error(w, "failed to compute encryption state", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(state); err != nil {
+			http.Error(w, "failed to encode encryption state", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetEncryptionState(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	configYAML := `
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - aesgcm:
+        keys:
+        - name: key1
+          secret: c2VjcmV0IGlzIHNlY3VyZSwgaXMgaXQ/
+          provenance:
+            createdAt: "2023-01-02T15:04:05Z"
+            owner: storage-team
+            ticket: KEY-123
+    - kms:
+        name: provider-1
+        endpoint: unix:///tmp/kms-provider.sock
+        provenance:
+          owner: kms-team
+    - identity: {}
+`
+	if err := os.WriteFile(configFile, []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := GetEncryptionState(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FileContentHash == "" {
+		t.Error("expected a non-empty file content hash")
+	}
+	want := []KeyState{
+		{Prefix: "k8s:enc:aesgcm:v1:key1:", CreatedAt: "2023-01-02T15:04:05Z", Owner: "storage-team", Ticket: "KEY-123"},
+		{Prefix: "k8s:enc:kms:v1:provider-1:", Owner: "kms-team"},
+	}
+	if len(state.Keys) != len(want) {
+		t.Fatalf("expected %d keys, got %d: %+v", len(want), len(state.Keys), state.Keys)
+	}
+	for i := range want {
+		if state.Keys[i] != want[i] {
+			t.Errorf("keys[%d] = %+v, want %+v", i, state.Keys[i], want[i])
+		}
+	}
+}
+
+func TestEncryptionStateHandler(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	configYAML := `
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - aescbc:
+        keys:
+        - name: key1
+          secret: c2VjcmV0IGlzIHNlY3VyZSwgaXMgaXQ/
+`
+	if err := os.WriteFile(configFile, []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewEncryptionStateHandler(configFile, false)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug/encryption-state", nil))
+	if w.Code != 200 {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var state EncryptionState
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Keys) != 1 || state.Keys[0].Prefix != "k8s:enc:aescbc:v1:key1:" {
+		t.Errorf("unexpected state keys: %+v", state.Keys)
+	}
+
+	// a broken config must not leak details through the endpoint.
+	if err := os.WriteFile(configFile, []byte("not a config"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug/encryption-state", nil))
+	if w.Code != 500 {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+}
+
+func TestEncryptionStateIncludesLastReload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	configYAML := `
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - identity: {}
+`
+	if err := os.WriteFile(configFile, []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	RecordReloadSummary(nil)
+	defer RecordReloadSummary(nil)
+
+	state, err := GetEncryptionState(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.LastReload != nil {
+		t.Errorf("expected no reload summary before the first reload, got %+v", state.LastReload)
+	}
+
+	RecordReloadSummary(&ReloadSummary{
+		ConfigHash:           "sha256:abc",
+		ProvidersAdded:       []string{"kms:v2:new-provider"},
+		ProvidersRemoved:     []string{"aescbc"},
+		WriteProviderChanges: map[string]string{"secrets": "aescbc -> kms:v2:new-provider"},
+	})
+	state, err = GetEncryptionState(configFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.LastReload == nil || state.LastReload.ConfigHash != "sha256:abc" {
+		t.Fatalf("expected the recorded reload summary to be served, got %+v", state.LastReload)
+	}
+	if len(state.LastReload.ProvidersAdded) != 1 || state.LastReload.ProvidersAdded[0] != "kms:v2:new-provider" {
+		t.Errorf("unexpected providersAdded: %v", state.LastReload.ProvidersAdded)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionconfig
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	envelopekmsv2 "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+)
+
+type fakeKMSv2Service struct{}
+
+func (f *fakeKMSv2Service) Decrypt(ctx context.Context, uid string, req *envelopekmsv2.DecryptRequest) ([]byte, error) {
+	return req.Ciphertext, nil
+}
+
+func (f *fakeKMSv2Service) Encrypt(ctx context.Context, uid string, data []byte) (*envelopekmsv2.EncryptResponse, error) {
+	return &envelopekmsv2.EncryptResponse{Ciphertext: data, KeyID: "1"}, nil
+}
+
+func (f *fakeKMSv2Service) Status(ctx context.Context) (*envelopekmsv2.StatusResponse, error) {
+	return &envelopekmsv2.StatusResponse{Version: "v2alpha1", Healthz: "ok", KeyID: "1"}, nil
+}
+
+func TestTracedKMSv2Service(t *testing.T) {
+	fakeRecorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(fakeRecorder))
+
+	service := &tracedKMSv2Service{name: "traced-test-provider", service: &fakeKMSv2Service{}}
+
+	// Child spans are only emitted when the incoming context already carries
+	// a span, mimicking a request handled with APIServerTracing enabled.
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "write secret")
+	if _, err := service.Encrypt(ctx, "uid", []byte("plaintext")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Decrypt(ctx, "uid", &envelopekmsv2.DecryptRequest{Ciphertext: []byte("ciphertext")}); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := fakeRecorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	wantNames := []string{"KMSv2 Encrypt", "KMSv2 Decrypt", "write secret"}
+	for i, span := range spans {
+		if span.Name() != wantNames[i] {
+			t.Errorf("span %d: got name %q, want %q", i, span.Name(), wantNames[i])
+		}
+	}
+	for _, span := range spans[:2] {
+		var foundProvider bool
+		for _, attr := range span.Attributes() {
+			if attr.Key == attribute.Key("provider") && attr.Value.AsString() == "traced-test-provider" {
+				foundProvider = true
+			}
+		}
+		if !foundProvider {
+			t.Errorf("span %q is missing the provider attribute", span.Name())
+		}
+	}
+}
+
+func TestTracedKMSv2ServiceWithoutParentSpan(t *testing.T) {
+	service := &tracedKMSv2Service{name: "traced-test-provider", service: &fakeKMSv2Service{}}
+
+	// Without a span in the context the wrapper must be a transparent noop.
+	if _, err := service.Encrypt(context.Background(), "uid", []byte("plaintext")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Decrypt(context.Background(), "uid", &envelopekmsv2.DecryptRequest{Ciphertext: []byte("ciphertext")}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package options
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -35,9 +37,14 @@ import (
 	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
 	kmsconfigcontroller "k8s.io/apiserver/pkg/server/options/encryptionconfig/controller"
 	serverstorage "k8s.io/apiserver/pkg/server/storage"
+	"k8s.io/apiserver/pkg/storage/etcd3"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	storagefactory "k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	envelopeutil "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/util"
 	flowcontrolrequest "k8s.io/apiserver/pkg/util/flowcontrol/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 )
 
@@ -47,9 +54,21 @@ type EtcdOptions struct {
 	StorageConfig                           storagebackend.Config
 	EncryptionProviderConfigFilepath        string
 	EncryptionProviderConfigAutomaticReload bool
+	EncryptionProviderFIPSMode              bool
+	EncryptionProviderMlockKeyMaterial      bool
+	EncryptionProviderAuditDEKUnwrap        bool
+	EncryptionProviderReencryptOnRead       bool
+	EncryptionQuarantineRecovery            bool
+	EncryptionProviderPrefixCheck           string
+	EncryptionProviderAllowedAlgorithms     []string
+	EncryptionReadinessSampleSize           int
 
 	EtcdServersOverrides []string
 
+	// UpdateConflictRetryOverrides replaces the guaranteed update conflict
+	// retry attempt bound for specific resources, format: resource[.group]#attempts.
+	UpdateConflictRetryOverrides []string
+
 	// To enable protobuf as storage format, it is enough
 	// to set it to "application/vnd.kubernetes.protobuf".
 	DefaultStorageMediaType string
@@ -123,6 +142,75 @@ func (s *EtcdOptions) Validate() []error {
 		allErrors = append(allErrors, fmt.Errorf("--encryption-provider-config-automatic-reload must be set with --encryption-provider-config"))
 	}
 
+	if len(s.EncryptionProviderConfigFilepath) == 0 && s.EncryptionProviderFIPSMode {
+		allErrors = append(allErrors, fmt.Errorf("--encryption-fips-mode must be set with --encryption-provider-config"))
+	}
+
+	if len(s.EncryptionProviderConfigFilepath) == 0 && s.EncryptionProviderMlockKeyMaterial {
+		allErrors = append(allErrors, fmt.Errorf("--encryption-mlock-key-material must be set with --encryption-provider-config"))
+	}
+
+	if len(s.EncryptionProviderConfigFilepath) == 0 && s.EncryptionProviderAuditDEKUnwrap {
+		allErrors = append(allErrors, fmt.Errorf("--encryption-audit-dek-unwrap must be set with --encryption-provider-config"))
+	}
+
+	if len(s.EncryptionProviderConfigFilepath) == 0 && s.EncryptionProviderReencryptOnRead {
+		allErrors = append(allErrors, fmt.Errorf("--encryption-reencrypt-on-read must be set with --encryption-provider-config"))
+	}
+
+	if len(s.EncryptionProviderAllowedAlgorithms) != 0 {
+		if len(s.EncryptionProviderConfigFilepath) == 0 {
+			allErrors = append(allErrors, fmt.Errorf("--encryption-allowed-algorithms must be set with --encryption-provider-config"))
+		}
+		known := sets.NewString(encryptionconfig.KnownAlgorithms...)
+		for _, algorithm := range s.EncryptionProviderAllowedAlgorithms {
+			if !known.Has(algorithm) {
+				allErrors = append(allErrors, fmt.Errorf("--encryption-allowed-algorithms contains unknown algorithm %q, allowed values: %s", algorithm, strings.Join(encryptionconfig.KnownAlgorithms, ", ")))
+			}
+		}
+	}
+
+	if len(s.EncryptionProviderPrefixCheck) != 0 {
+		if len(s.EncryptionProviderConfigFilepath) == 0 {
+			allErrors = append(allErrors, fmt.Errorf("--encryption-prefix-check must be set with --encryption-provider-config"))
+		}
+		if s.EncryptionProviderPrefixCheck != encryptionconfig.PrefixCheckWarn && s.EncryptionProviderPrefixCheck != encryptionconfig.PrefixCheckFail {
+			allErrors = append(allErrors, fmt.Errorf("--encryption-prefix-check invalid, allowed values: %s, %s", encryptionconfig.PrefixCheckWarn, encryptionconfig.PrefixCheckFail))
+		}
+	}
+
+	if s.EncryptionReadinessSampleSize != 0 {
+		if len(s.EncryptionProviderConfigFilepath) == 0 {
+			allErrors = append(allErrors, fmt.Errorf("--encryption-readiness-sample-size must be set with --encryption-provider-config"))
+		}
+		if s.EncryptionReadinessSampleSize < 0 {
+			allErrors = append(allErrors, fmt.Errorf("--encryption-readiness-sample-size must not be negative"))
+		}
+	}
+
+	if s.StorageConfig.ConflictRetry.MaxAttempts < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--etcd-update-conflict-retry-attempts must not be negative"))
+	}
+	if s.StorageConfig.ConflictRetry.InitialBackoff < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--etcd-update-conflict-retry-backoff must not be negative"))
+	}
+	if s.StorageConfig.ConflictRetry.MaxBackoff < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--etcd-update-conflict-retry-max-backoff must not be negative"))
+	}
+	if _, err := ParseUpdateConflictRetryOverrides(s.UpdateConflictRetryOverrides, s.StorageConfig.ConflictRetry); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("--etcd-update-conflict-retry-overrides invalid: %v", err))
+	}
+
+	switch s.StorageConfig.EndpointRouting {
+	case "", storagebackend.EndpointRoutingBalanced, storagebackend.EndpointRoutingNearest:
+	default:
+		allErrors = append(allErrors, fmt.Errorf("--etcd-endpoint-routing %q invalid, allowed values: %q, %q",
+			s.StorageConfig.EndpointRouting, storagebackend.EndpointRoutingBalanced, storagebackend.EndpointRoutingNearest))
+	}
+	if s.StorageConfig.EndpointRoutingProbeInterval < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--etcd-endpoint-routing-probe-interval must not be negative"))
+	}
+
 	return allErrors
 }
 
@@ -192,6 +280,68 @@ func (s *EtcdOptions) AddFlags(fs *pflag.FlagSet) {
 		"Determines if the file set by --encryption-provider-config should be automatically reloaded if the disk contents change. "+
 			"Setting this to true disables the ability to uniquely identify distinct KMS plugins via the API server healthz endpoints.")
 
+	fs.BoolVar(&s.EncryptionProviderFIPSMode, "encryption-fips-mode", s.EncryptionProviderFIPSMode,
+		"If true, the file set by --encryption-provider-config must only use FIPS-approved algorithms and this binary "+
+			"must be built with a FIPS-validated crypto module. The server fails to start with a report of all violating providers otherwise.")
+
+	fs.BoolVar(&s.EncryptionProviderMlockKeyMaterial, "encryption-mlock-key-material", s.EncryptionProviderMlockKeyMaterial,
+		"If true, buffers holding plaintext data encryption keys are locked into resident memory while in use and zeroed "+
+			"afterwards, keeping key material out of swap and core dumps on the control-plane node. "+
+			"Requires a sufficient RLIMIT_MEMLOCK; requests fail when locking does.")
+
+	fs.BoolVar(&s.EncryptionProviderAuditDEKUnwrap, "encryption-audit-dek-unwrap", s.EncryptionProviderAuditDEKUnwrap,
+		"If true, every data encryption key unwrapped by a KMS provider is recorded as annotations on the audit event "+
+			"of the request being served, naming the provider, the key ID when known, and the storage key of the object. "+
+			"Requires an audit policy capturing the request at Metadata level or higher.")
+
+	fs.BoolVar(&s.EncryptionProviderReencryptOnRead, "encryption-reencrypt-on-read", s.EncryptionProviderReencryptOnRead,
+		"If true, records read back with a non-current storage encryption prefix are transparently rewritten with the "+
+			"current write provider, rate limited process-wide, so frequently accessed objects migrate organically "+
+			"without waiting for a full storage migration pass.")
+
+	fs.BoolVar(&s.EncryptionQuarantineRecovery, "encryption-quarantine-recovery", s.EncryptionQuarantineRecovery,
+		"If true, records that repeatedly fail decryption are skipped on LIST instead of failing the whole request, "+
+			"and the /debug/encryption/quarantine endpoint for exporting or force-deleting them is served. "+
+			"If false, the default, an undecryptable record fails any LIST covering it and quarantine is "+
+			"tracked for logging only.")
+
+	fs.StringSliceVar(&s.EncryptionProviderAllowedAlgorithms, "encryption-allowed-algorithms", s.EncryptionProviderAllowedAlgorithms,
+		"Comma-separated list of algorithms providers in the file set by --encryption-provider-config may use, "+
+			"for example 'kmsv2,aesgcm' to require envelope encryption with the KMS v2 API. Enforced when the "+
+			"configuration is loaded, at startup and on every hot reload. Empty means no restriction. "+
+			"Known algorithms: "+strings.Join(encryptionconfig.KnownAlgorithms, ", ")+".")
+
+	fs.StringVar(&s.EncryptionProviderPrefixCheck, "encryption-prefix-check", s.EncryptionProviderPrefixCheck,
+		"If set, stored records of the resources declared in --encryption-provider-config are sampled at startup and "+
+			"records carrying an encryption prefix no configured provider can decrypt, for example after a provider "+
+			"stanza was deleted before its records were migrated, are reported. 'warn' logs the finding, 'fail' "+
+			"terminates the server.")
+
+	fs.IntVar(&s.EncryptionReadinessSampleSize, "encryption-readiness-sample-size", s.EncryptionReadinessSampleSize,
+		"If set, the server reports readiness only after this many stored records per resource declared in "+
+			"--encryption-provider-config (fewer when fewer exist) have been read back and decrypted with the loaded "+
+			"providers, so a key set that cannot read the existing data is caught by the startup probe instead of by "+
+			"clients. The check runs until it passes once. Zero disables it.")
+
+	fs.IntVar(&s.StorageConfig.ListDecryptWorkers, "etcd-list-decrypt-workers", s.StorageConfig.ListDecryptWorkers,
+		"Bound on how many list items are decrypted concurrently when serving a LIST request, so large lists of "+
+			"envelope-encrypted objects do not serialize on per-item decryption. Values below two mean sequential decryption.")
+
+	fs.IntVar(&s.StorageConfig.ConflictRetry.MaxAttempts, "etcd-update-conflict-retry-attempts", s.StorageConfig.ConflictRetry.MaxAttempts,
+		"Bound on how many times an update whose etcd transaction lost to a concurrent writer is retried before the "+
+			"conflict is returned to the caller. Zero means retry without bound.")
+
+	fs.DurationVar(&s.StorageConfig.ConflictRetry.InitialBackoff, "etcd-update-conflict-retry-backoff", s.StorageConfig.ConflictRetry.InitialBackoff,
+		"Time to wait before the first retry of a conflicting update, doubling on every further retry up to "+
+			"--etcd-update-conflict-retry-max-backoff. Zero means retry immediately.")
+
+	fs.DurationVar(&s.StorageConfig.ConflictRetry.MaxBackoff, "etcd-update-conflict-retry-max-backoff", s.StorageConfig.ConflictRetry.MaxBackoff,
+		"Cap on the doubling backoff between conflicting update retries. Zero means no cap.")
+
+	fs.StringSliceVar(&s.UpdateConflictRetryOverrides, "etcd-update-conflict-retry-overrides", s.UpdateConflictRetryOverrides,
+		"Per-resource overrides of the update conflict retry attempt bound, comma separated. The individual override "+
+			"format: resource[.group]#attempts. Backoff settings are inherited from the global flags.")
+
 	fs.DurationVar(&s.StorageConfig.CompactionInterval, "etcd-compaction-interval", s.StorageConfig.CompactionInterval,
 		"The interval of compaction requests. If 0, the compaction request from apiserver is disabled.")
 
@@ -201,6 +351,15 @@ func (s *EtcdOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&s.StorageConfig.DBMetricPollInterval, "etcd-db-metric-poll-interval", s.StorageConfig.DBMetricPollInterval,
 		"The interval of requests to poll etcd and update metric. 0 disables the metric collection")
 
+	fs.StringVar(&s.StorageConfig.EndpointRouting, "etcd-endpoint-routing", s.StorageConfig.EndpointRouting,
+		"How the etcd client routes requests across the servers in --etcd-servers. 'balanced', the default, spreads "+
+			"requests across all servers. 'nearest' periodically probes every server and pins the client to the "+
+			"lowest-latency healthy one, keeping reads within the local zone of a stretched control plane, and "+
+			"falls back to all servers while that one is unhealthy.")
+
+	fs.DurationVar(&s.StorageConfig.EndpointRoutingProbeInterval, "etcd-endpoint-routing-probe-interval", s.StorageConfig.EndpointRoutingProbeInterval,
+		"The interval between latency probes of the etcd servers when --etcd-endpoint-routing=nearest.")
+
 	fs.DurationVar(&s.StorageConfig.HealthcheckTimeout, "etcd-healthcheck-timeout", s.StorageConfig.HealthcheckTimeout,
 		"The timeout to use when checking etcd health.")
 
@@ -228,6 +387,16 @@ func (s *EtcdOptions) Complete(
 	}
 
 	if len(s.EncryptionProviderConfigFilepath) != 0 {
+		envelopeutil.SetMlockKeyMaterial(s.EncryptionProviderMlockKeyMaterial)
+		envelopeutil.SetAuditDEKUnwrap(s.EncryptionProviderAuditDEKUnwrap)
+		etcd3.SetReencryptOnRead(s.EncryptionProviderReencryptOnRead)
+		encryptionconfig.SetAllowedAlgorithms(s.EncryptionProviderAllowedAlgorithms)
+		if s.EncryptionProviderFIPSMode {
+			if err := encryptionconfig.CheckFIPSCompliance(s.EncryptionProviderConfigFilepath); err != nil {
+				return fmt.Errorf("encryption provider config is not FIPS compliant: %w", err)
+			}
+		}
+
 		ctx, closeTransformers := wait.ContextForChannel(stopCh)
 
 		encryptionConfiguration, err := encryptionconfig.LoadEncryptionConfig(s.EncryptionProviderConfigFilepath, s.EncryptionProviderConfigAutomaticReload, ctx.Done())
@@ -236,6 +405,8 @@ func (s *EtcdOptions) Complete(
 			closeTransformers()
 			return err
 		}
+		encryptionconfig.RecordActiveConfigHash(encryptionConfiguration.EncryptionFileContentHash)
+		encryptionconfig.RecordActiveAlgorithms(encryptionConfiguration.Algorithms)
 
 		// enable kms hot reload controller only if the config file is set to be automatically reloaded
 		if s.EncryptionProviderConfigAutomaticReload {
@@ -256,11 +427,25 @@ func (s *EtcdOptions) Complete(
 			err = addPostStartHook(
 				"start-encryption-provider-config-automatic-reload",
 				func(hookContext server.PostStartHookContext) error {
+					// reload outcomes are emitted as Events via the loopback
+					// client; if it cannot be built they are only logged.
+					var eventRecorder events.EventRecorder
+					if client, err := kubernetes.NewForConfig(hookContext.LoopbackClientConfig); err != nil {
+						klog.ErrorS(err, "Failed to build loopback client for encryption config reload events")
+					} else {
+						eventBroadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
+						eventBroadcaster.StartRecordingToSink(ctx.Done())
+						eventRecorder = eventBroadcaster.NewRecorder(scheme.Scheme, "encryption-provider-config-automatic-reload-controller")
+					}
+
 					kmsConfigController := kmsconfigcontroller.NewDynamicKMSEncryptionConfiguration(
 						"kms-encryption-config",
 						s.EncryptionProviderConfigFilepath,
 						dynamicTransformers,
 						encryptionConfiguration.EncryptionFileContentHash,
+						encryptionConfiguration.WriteProviders,
+						encryptionConfiguration.Providers,
+						eventRecorder,
 						ctx.Done(),
 					)
 
@@ -280,6 +465,14 @@ func (s *EtcdOptions) Complete(
 		}
 	}
 
+	if len(s.UpdateConflictRetryOverrides) != 0 {
+		overrides, err := ParseUpdateConflictRetryOverrides(s.UpdateConflictRetryOverrides, s.StorageConfig.ConflictRetry)
+		if err != nil {
+			return err
+		}
+		s.StorageConfig.ConflictRetryOverrides = overrides
+	}
+
 	s.StorageConfig.StorageObjectCountTracker = storageObjectCountTracker
 
 	s.complete = true
@@ -318,6 +511,84 @@ func (s *EtcdOptions) ApplyWithStorageFactoryTo(factory serverstorage.StorageFac
 			resourceTransformers: s.resourceTransformers,
 		}
 	}
+	if len(s.EncryptionProviderConfigFilepath) != 0 {
+		c.EncryptionStateHandler = encryptionconfig.NewEncryptionStateHandler(s.EncryptionProviderConfigFilepath, s.EncryptionProviderConfigAutomaticReload)
+		c.EncryptionProvidersDebugHandler = encryptionconfig.NewEncryptionProvidersHandler(s.EncryptionProviderConfigFilepath, s.EncryptionProviderConfigAutomaticReload)
+		if s.EncryptionQuarantineRecovery {
+			etcd3.EnableQuarantineListSkip()
+			c.EncryptionQuarantineHandler = etcd3.NewQuarantineHandler()
+		}
+
+		rawLister, err := storagefactory.CreateRawLister(s.StorageConfig, c.DrainedNotify())
+		if err != nil {
+			return err
+		}
+		storageConfig := s.StorageConfig
+		coverageController := encryptionconfig.NewCoverageController(
+			s.EncryptionProviderConfigFilepath,
+			s.EncryptionProviderConfigAutomaticReload,
+			func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error) {
+				return rawLister(ctx, path.Join("/", storageConfig.Prefix, factory.ResourcePrefix(resource))+"/", limit)
+			},
+		)
+		c.EncryptionCoverageHandler = coverageController
+		if err := c.AddPostStartHook("start-encryption-coverage-controller", func(hookContext server.PostStartHookContext) error {
+			go coverageController.Run(hookContext.StopCh)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if s.EncryptionReadinessSampleSize > 0 && s.resourceTransformers != nil {
+			kvLister, err := storagefactory.CreateRawKVLister(s.StorageConfig, c.DrainedNotify())
+			if err != nil {
+				return err
+			}
+			resourceTransformers := s.resourceTransformers
+			c.AddReadyzChecks(encryptionconfig.NewDecryptabilityCheck(
+				s.EncryptionProviderConfigFilepath,
+				s.EncryptionProviderConfigAutomaticReload,
+				int64(s.EncryptionReadinessSampleSize),
+				func(ctx context.Context, resource schema.GroupResource, limit int64) ([]encryptionconfig.RawRecord, error) {
+					raw, err := kvLister(ctx, path.Join("/", storageConfig.Prefix, factory.ResourcePrefix(resource))+"/", limit)
+					if err != nil {
+						return nil, err
+					}
+					records := make([]encryptionconfig.RawRecord, 0, len(raw))
+					for _, record := range raw {
+						records = append(records, encryptionconfig.RawRecord{Key: record.Key, Value: record.Value})
+					}
+					return records, nil
+				},
+				resourceTransformers.TransformerForResource,
+			))
+		}
+
+		if len(s.EncryptionProviderPrefixCheck) != 0 {
+			filepath := s.EncryptionProviderConfigFilepath
+			reload := s.EncryptionProviderConfigAutomaticReload
+			mode := s.EncryptionProviderPrefixCheck
+			lister := func(ctx context.Context, resource schema.GroupResource, limit int64) ([][]byte, error) {
+				return rawLister(ctx, path.Join("/", storageConfig.Prefix, factory.ResourcePrefix(resource))+"/", limit)
+			}
+			if err := c.AddPostStartHook("encryption-prefix-check", func(hookContext server.PostStartHookContext) error {
+				// we ignore the cancel func because this context should only be canceled when stopCh is closed
+				ctx, _ := wait.ContextForChannel(hookContext.StopCh)
+				checkErr := encryptionconfig.CheckStoredPrefixes(ctx, filepath, reload, lister)
+				if checkErr == nil {
+					return nil
+				}
+				// a failed post start hook terminates the server
+				if mode == encryptionconfig.PrefixCheckFail {
+					return checkErr
+				}
+				klog.Warningf("encryption prefix check: %v", checkErr)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
 
 	c.RESTOptionsGetter = &StorageFactoryRestOptionsFactory{Options: *s, StorageFactory: factory}
 	return nil
@@ -409,6 +680,34 @@ func ParseWatchCacheSizes(cacheSizes []string) (map[schema.GroupResource]int, er
 	return watchCacheSizes, nil
 }
 
+// ParseUpdateConflictRetryOverrides turns a list of per-resource conflict retry
+// attempt bounds into a map of group resources to retry policies. Each override
+// keeps the backoff of the given default policy and replaces its attempt bound.
+func ParseUpdateConflictRetryOverrides(overrides []string, defaultPolicy etcd3.ConflictRetryPolicy) (map[schema.GroupResource]etcd3.ConflictRetryPolicy, error) {
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	policies := make(map[schema.GroupResource]etcd3.ConflictRetryPolicy, len(overrides))
+	for _, override := range overrides {
+		tokens := strings.Split(override, "#")
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("invalid value of conflict retry override: %s", override)
+		}
+
+		attempts, err := strconv.Atoi(tokens[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid attempts of conflict retry override: %s", override)
+		}
+		if attempts < 0 {
+			return nil, fmt.Errorf("conflict retry attempts cannot be negative: %s", override)
+		}
+		policy := defaultPolicy
+		policy.MaxAttempts = attempts
+		policies[schema.ParseGroupResource(tokens[0])] = policy
+	}
+	return policies, nil
+}
+
 // WriteWatchCacheSizes turns a map of cache size values into a list of string specifications.
 func WriteWatchCacheSizes(watchCacheSizes map[schema.GroupResource]int) ([]string, error) {
 	var cacheSizes []string
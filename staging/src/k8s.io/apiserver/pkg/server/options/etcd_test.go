@@ -29,6 +29,7 @@ import (
 	"k8s.io/apiserver/pkg/features"
 	"k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/storage/etcd3"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
@@ -137,6 +138,31 @@ func TestEtcdOptionsValidate(t *testing.T) {
 			},
 			expectErr: "--encryption-provider-config-automatic-reload must be set with --encryption-provider-config",
 		},
+		{
+			name: "test when etcd-endpoint-routing is invalid",
+			testOptions: &EtcdOptions{
+				StorageConfig: storagebackend.Config{
+					Type:   "etcd3",
+					Prefix: "/registry",
+					Transport: storagebackend.TransportConfig{
+						ServerList:    []string{"http://127.0.0.1"},
+						KeyFile:       "/var/run/kubernetes/etcd.key",
+						TrustedCAFile: "/var/run/kubernetes/etcdca.crt",
+						CertFile:      "/var/run/kubernetes/etcdce.crt",
+					},
+					CompactionInterval:    storagebackend.DefaultCompactInterval,
+					CountMetricPollPeriod: time.Minute,
+					EndpointRouting:       "fastest",
+				},
+				DefaultStorageMediaType: "application/vnd.kubernetes.protobuf",
+				DeleteCollectionWorkers: 1,
+				EnableGarbageCollection: true,
+				EnableWatchCache:        true,
+				DefaultWatchCacheSize:   100,
+				EtcdServersOverrides:    []string{"/events#http://127.0.0.1:4002"},
+			},
+			expectErr: `--etcd-endpoint-routing "fastest" invalid, allowed values: "balanced", "nearest"`,
+		},
 		{
 			name: "test when EtcdOptions is valid",
 			testOptions: &EtcdOptions{
@@ -228,6 +254,64 @@ func TestParseWatchCacheSizes(t *testing.T) {
 	}
 }
 
+func TestParseUpdateConflictRetryOverrides(t *testing.T) {
+	defaultPolicy := etcd3.ConflictRetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     100 * time.Millisecond,
+	}
+	testCases := []struct {
+		name           string
+		overrides      []string
+		expectPolicies map[schema.GroupResource]etcd3.ConflictRetryPolicy
+		expectErr      string
+	}{
+		{
+			name:      "test when override has no attempts",
+			overrides: []string{"leases.coordination.k8s.io"},
+			expectErr: "invalid value of conflict retry override",
+		},
+		{
+			name:      "test when attempts is not a number",
+			overrides: []string{"leases.coordination.k8s.io#many"},
+			expectErr: "invalid attempts of conflict retry override",
+		},
+		{
+			name:      "test when attempts is negative",
+			overrides: []string{"leases.coordination.k8s.io#-1"},
+			expectErr: "conflict retry attempts cannot be negative",
+		},
+		{
+			name:      "test when parse overrides success",
+			overrides: []string{"leases.coordination.k8s.io#10", "endpoints#0"},
+			expectPolicies: map[schema.GroupResource]etcd3.ConflictRetryPolicy{
+				{Group: "coordination.k8s.io", Resource: "leases"}: {MaxAttempts: 10, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 100 * time.Millisecond},
+				{Resource: "endpoints"}:                            {MaxAttempts: 0, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 100 * time.Millisecond},
+			},
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			result, err := ParseUpdateConflictRetryOverrides(testcase.overrides, defaultPolicy)
+			if len(testcase.expectErr) != 0 && !strings.Contains(err.Error(), testcase.expectErr) {
+				t.Errorf("got err: %v, expected err: %s", err, testcase.expectErr)
+			}
+			if len(testcase.expectErr) == 0 {
+				if err != nil {
+					t.Errorf("got err: %v, expected err nil", err)
+				} else {
+					for key, expectValue := range testcase.expectPolicies {
+						if resultValue, exist := result[key]; !exist || resultValue != expectValue {
+							t.Errorf("got conflict retry overrides: %v, expected %v", result, testcase.expectPolicies)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestKMSHealthzEndpoint(t *testing.T) {
 	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.KMSv2, true)()
 
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apiserver/pkg/server"
+)
+
+// HealthProbesOptions are for serving /healthz, /livez and /readyz on a dedicated
+// port outside the secure serving chain. Requests on this port bypass authentication
+// and API priority and fairness, so load balancer probes need no tokens and health
+// stays reachable while the secure chain is saturated. Only the health endpoints are
+// served; no API content is exposed.
+type HealthProbesOptions struct {
+	BindAddress net.IP
+	// BindPort is the port on which to serve the health endpoints. Zero disables the
+	// dedicated health probes port.
+	BindPort int
+	// BindNetwork is the type of network to bind to - defaults to "tcp", accepts "tcp",
+	// "tcp4", and "tcp6".
+	BindNetwork string
+	// MaxInFlight bounds the number of concurrent requests on the health probes port;
+	// excess requests are rejected with 429.
+	MaxInFlight int
+
+	// Listener is the health probes network listener.
+	// either Listener or BindAddress/BindPort/BindNetwork is set,
+	// if Listener is set, use it and omit BindAddress/BindPort/BindNetwork.
+	Listener net.Listener
+
+	// ListenFunc can be overridden to create a custom listener, e.g. for mocking in tests.
+	// It defaults to options.CreateListener.
+	ListenFunc func(network, addr string, config net.ListenConfig) (net.Listener, int, error)
+}
+
+// NewHealthProbesOptions returns health probes options with the dedicated port
+// disabled and a conservative concurrency bound.
+func NewHealthProbesOptions() *HealthProbesOptions {
+	return &HealthProbesOptions{
+		BindAddress: net.ParseIP("0.0.0.0"),
+		BindPort:    0,
+		MaxInFlight: 16,
+	}
+}
+
+// Validate ensures that the health probes port values are within the range of the port.
+func (s *HealthProbesOptions) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	errors := []error{}
+
+	if s.BindPort < 0 || s.BindPort > 65535 {
+		errors = append(errors, fmt.Errorf("health probes port %v must be between 0 and 65535, inclusive. 0 for turning off the health probes port", s.BindPort))
+	}
+	if s.MaxInFlight < 0 {
+		errors = append(errors, fmt.Errorf("health probes max in-flight %v must not be negative. 0 for no limit", s.MaxInFlight))
+	}
+
+	return errors
+}
+
+// AddFlags adds flags related to the health probes port to the specified FlagSet.
+func (s *HealthProbesOptions) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.IPVar(&s.BindAddress, "health-probes-bind-address", s.BindAddress, ""+
+		"The IP address on which to serve the --health-probes-port (set to 0.0.0.0 or :: for listening in all interfaces and IP families).")
+
+	fs.IntVar(&s.BindPort, "health-probes-port", s.BindPort, ""+
+		"The port on which to additionally serve /healthz, /livez and /readyz without authentication, "+
+		"so load balancer health probes need no tokens and do not consume API priority and fairness capacity. "+
+		"Set to 0 to disable.")
+
+	fs.IntVar(&s.MaxInFlight, "health-probes-max-in-flight", s.MaxInFlight, ""+
+		"The maximum number of concurrent requests served on --health-probes-port; excess requests are rejected with 429. "+
+		"Set to 0 for no limit.")
+}
+
+// ApplyTo creates the health probes listener and configures the dedicated health
+// endpoints on the server configuration.
+func (s *HealthProbesOptions) ApplyTo(c *server.Config) error {
+	if s == nil {
+		return nil
+	}
+	if s.BindPort <= 0 && s.Listener == nil {
+		return nil
+	}
+
+	if s.Listener == nil {
+		var err error
+		listen := CreateListener
+		if s.ListenFunc != nil {
+			listen = s.ListenFunc
+		}
+		addr := net.JoinHostPort(s.BindAddress.String(), fmt.Sprintf("%d", s.BindPort))
+		s.Listener, s.BindPort, err = listen(s.BindNetwork, addr, net.ListenConfig{})
+		if err != nil {
+			return fmt.Errorf("failed to create listener: %v", err)
+		}
+	}
+
+	c.HealthProbesListener = s.Listener
+	c.HealthProbesMaxInFlight = s.MaxInFlight
+
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"net"
+	"testing"
+
+	"k8s.io/apiserver/pkg/server"
+)
+
+func TestHealthProbesOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     *HealthProbesOptions
+		expectError bool
+	}{
+		{
+			name:    "nil options",
+			options: nil,
+		},
+		{
+			name:    "defaults",
+			options: NewHealthProbesOptions(),
+		},
+		{
+			name:        "negative port",
+			options:     &HealthProbesOptions{BindPort: -1},
+			expectError: true,
+		},
+		{
+			name:        "port out of range",
+			options:     &HealthProbesOptions{BindPort: 65536},
+			expectError: true,
+		},
+		{
+			name:        "negative max in-flight",
+			options:     &HealthProbesOptions{MaxInFlight: -1},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := testCase.options.Validate()
+			if testCase.expectError && len(errs) == 0 {
+				t.Error("expected validation errors but got none")
+			}
+			if !testCase.expectError && len(errs) > 0 {
+				t.Errorf("expected no validation errors but got %v", errs)
+			}
+		})
+	}
+}
+
+func TestHealthProbesOptionsApplyTo(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		config := &server.Config{}
+		if err := NewHealthProbesOptions().ApplyTo(config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.HealthProbesListener != nil {
+			t.Error("expected no listener when the port is 0")
+		}
+	})
+
+	t.Run("creates listener when enabled", func(t *testing.T) {
+		options := NewHealthProbesOptions()
+		options.BindAddress = net.ParseIP("127.0.0.1")
+		options.BindPort = 1
+
+		// use a listener instead of the hardcoded port
+		ln, port, err := CreateListener("tcp", "127.0.0.1:0", net.ListenConfig{})
+		if err != nil {
+			t.Fatalf("failed to create listener: %v", err)
+		}
+		defer ln.Close()
+		options.Listener = ln
+		options.BindPort = port
+
+		config := &server.Config{}
+		if err := options.ApplyTo(config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.HealthProbesListener != ln {
+			t.Error("expected the configured listener to be applied")
+		}
+		if config.HealthProbesMaxInFlight != options.MaxInFlight {
+			t.Errorf("expected max in-flight %d, got %d", options.MaxInFlight, config.HealthProbesMaxInFlight)
+		}
+	})
+}
@@ -19,6 +19,7 @@ package options
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -26,6 +27,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/server"
+	genericfilters "k8s.io/apiserver/pkg/server/filters"
+	"k8s.io/apiserver/pkg/server/healthz"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
 	"github.com/spf13/pflag"
@@ -63,6 +66,29 @@ type ServerRunOptions struct {
 	// If enabled, after ShutdownDelayDuration elapses, any incoming request is
 	// rejected with a 429 status code and a 'Retry-After' response.
 	ShutdownSendRetryAfter bool
+
+	// EnableReadOnlyMode wires up a runtime-togglable read-only mode. While
+	// the mode is enabled via the privileged /debug/flags/read-only endpoint,
+	// all mutating requests for non-exempt resources are rejected with a 503
+	// status code and a 'Retry-After' response, and the 'read-only-mode'
+	// readyz check reports failure.
+	EnableReadOnlyMode bool
+
+	// ReadOnlyModeExemptions lists resources (in "resource.group" form) whose
+	// mutating requests are still admitted while read-only mode is enabled.
+	ReadOnlyModeExemptions []string
+
+	// ServeRemovedAPIs lists removed group-versions to re-serve as rewrites to
+	// a replacement group-version, in "group/version=group/version" form.
+	ServeRemovedAPIs []string
+
+	// ServeRemovedAPIsNamespaces lists the namespaces the removed API rewrite
+	// applies to.
+	ServeRemovedAPIsNamespaces []string
+
+	// ServeRemovedAPIsUntil is the RFC 3339 instant the removed API rewrite
+	// stops applying.
+	ServeRemovedAPIsUntil string
 }
 
 func NewServerRunOptions() *ServerRunOptions {
@@ -78,6 +104,7 @@ func NewServerRunOptions() *ServerRunOptions {
 		MaxRequestBodyBytes:         defaults.MaxRequestBodyBytes,
 		EnablePriorityAndFairness:   true,
 		ShutdownSendRetryAfter:      false,
+		ReadOnlyModeExemptions:      genericfilters.DefaultReadOnlyModeExemptions(),
 	}
 }
 
@@ -98,6 +125,29 @@ func (s *ServerRunOptions) ApplyTo(c *server.Config) error {
 	c.PublicAddress = s.AdvertiseAddress
 	c.ShutdownSendRetryAfter = s.ShutdownSendRetryAfter
 
+	if s.EnableReadOnlyMode {
+		readOnlyMode := genericfilters.NewReadOnlyMode(s.ReadOnlyModeExemptions)
+		c.ReadOnlyMode = readOnlyMode
+		c.AddReadyzChecks(healthz.NamedCheck("read-only-mode", func(r *http.Request) error {
+			if readOnlyMode.Enabled() {
+				return fmt.Errorf("the apiserver is in read-only mode")
+			}
+			return nil
+		}))
+	}
+
+	if len(s.ServeRemovedAPIs) > 0 {
+		rules, err := genericfilters.ParseCompatibilityShimRules(s.ServeRemovedAPIs)
+		if err != nil {
+			return err
+		}
+		until, err := time.Parse(time.RFC3339, s.ServeRemovedAPIsUntil)
+		if err != nil {
+			return fmt.Errorf("invalid --serve-removed-apis-until value %q: %v", s.ServeRemovedAPIsUntil, err)
+		}
+		c.RemovedAPICompatibilityShim = genericfilters.NewCompatibilityShim(rules, s.ServeRemovedAPIsNamespaces, until)
+	}
+
 	return nil
 }
 
@@ -161,6 +211,24 @@ func (s *ServerRunOptions) Validate() []error {
 	if err := validateHSTSDirectives(s.HSTSDirectives); err != nil {
 		errors = append(errors, err)
 	}
+
+	if len(s.ServeRemovedAPIs) > 0 {
+		if _, err := genericfilters.ParseCompatibilityShimRules(s.ServeRemovedAPIs); err != nil {
+			errors = append(errors, err)
+		}
+		if len(s.ServeRemovedAPIsNamespaces) == 0 {
+			errors = append(errors, fmt.Errorf("--serve-removed-apis requires --serve-removed-apis-namespaces to limit which namespaces are rewritten"))
+		}
+		if until, err := time.Parse(time.RFC3339, s.ServeRemovedAPIsUntil); err != nil {
+			errors = append(errors, fmt.Errorf("--serve-removed-apis requires a valid RFC 3339 --serve-removed-apis-until deadline: %v", err))
+		} else if until.Before(time.Now()) {
+			errors = append(errors, fmt.Errorf("--serve-removed-apis-until %q is in the past", s.ServeRemovedAPIsUntil))
+		}
+	} else {
+		if len(s.ServeRemovedAPIsNamespaces) > 0 || len(s.ServeRemovedAPIsUntil) > 0 {
+			errors = append(errors, fmt.Errorf("--serve-removed-apis-namespaces and --serve-removed-apis-until must be set with --serve-removed-apis"))
+		}
+	}
 	return errors
 }
 
@@ -257,5 +325,33 @@ func (s *ServerRunOptions) AddUniversalFlags(fs *pflag.FlagSet) {
 		"during this window all incoming requests will be rejected with a status code 429 and a 'Retry-After' response header, "+
 		"in addition 'Connection: close' response header is set in order to tear down the TCP connection when idle.")
 
+	fs.BoolVar(&s.EnableReadOnlyMode, "enable-read-only-mode", s.EnableReadOnlyMode, ""+
+		"If true the server exposes a runtime-togglable read-only mode via the privileged "+
+		"/debug/flags/read-only endpoint. While enabled, all mutating requests for non-exempt "+
+		"resources are rejected with a status code 503 and a 'Retry-After' response header, and "+
+		"the 'read-only-mode' readyz check reports failure. Intended for freezing cluster state "+
+		"during etcd maintenance or forensic investigation.")
+
+	fs.StringSliceVar(&s.ReadOnlyModeExemptions, "read-only-mode-exemptions", s.ReadOnlyModeExemptions, ""+
+		"List of resources (in 'resource.group' form, comma separated) whose mutating requests are "+
+		"still admitted while read-only mode is enabled. Defaults to leases and events so that "+
+		"leader election, node heartbeats and event recording keep working during the freeze.")
+
+	fs.StringSliceVar(&s.ServeRemovedAPIs, "serve-removed-apis", s.ServeRemovedAPIs, ""+
+		"List of removed group-versions to re-serve as rewrites to a replacement version of "+
+		"the same API group, in 'group/version=group/version' form. Requests for a removed group-version in the "+
+		"namespaces listed in --serve-removed-apis-namespaces are rewritten to the replacement, "+
+		"answered with a warning, and annotated in the audit log. Only useful for removed "+
+		"versions that are wire-compatible with their replacement; intended as a time-boxed "+
+		"migration aid, not a way to keep using removed APIs.")
+
+	fs.StringSliceVar(&s.ServeRemovedAPIsNamespaces, "serve-removed-apis-namespaces", s.ServeRemovedAPIsNamespaces, ""+
+		"List of namespaces the --serve-removed-apis rewrite applies to. Requests for other "+
+		"namespaces, and cluster-scoped requests, are not rewritten.")
+
+	fs.StringVar(&s.ServeRemovedAPIsUntil, "serve-removed-apis-until", s.ServeRemovedAPIsUntil, ""+
+		"RFC 3339 instant after which the --serve-removed-apis rewrite stops applying, "+
+		"e.g. '2024-06-30T00:00:00Z'. Required when --serve-removed-apis is set.")
+
 	utilfeature.DefaultMutableFeatureGate.AddFlag(fs)
 }
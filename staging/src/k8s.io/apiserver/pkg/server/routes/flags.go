@@ -32,6 +32,9 @@ import (
 var (
 	lock            = &sync.RWMutex{}
 	registeredFlags = map[string]debugFlag{}
+	// indexInstalled tracks the muxes that already serve the /debug/flags
+	// index, so that Install can be called once per flag on the same mux.
+	indexInstalled = map[*mux.PathRecorderMux]bool{}
 )
 
 // DebugFlags adds handlers for flags under /debug/flags.
@@ -40,8 +43,7 @@ type DebugFlags struct {
 
 // Install registers the APIServer's flags handler.
 func (f DebugFlags) Install(c *mux.PathRecorderMux, flag string, handler func(http.ResponseWriter, *http.Request)) {
-	c.UnlistedHandle("/debug/flags", http.HandlerFunc(f.Index))
-	c.UnlistedHandlePrefix("/debug/flags/", http.HandlerFunc(f.Index))
+	f.installIndex(c)
 
 	url := path.Join("/debug/flags", flag)
 	c.UnlistedHandleFunc(url, handler)
@@ -49,6 +51,20 @@ func (f DebugFlags) Install(c *mux.PathRecorderMux, flag string, handler func(ht
 	f.addFlag(flag)
 }
 
+// installIndex registers the /debug/flags index on the given mux exactly once,
+// no matter how many flags are installed on it.
+func (f DebugFlags) installIndex(c *mux.PathRecorderMux) {
+	lock.Lock()
+	defer lock.Unlock()
+	if indexInstalled[c] {
+		return
+	}
+	indexInstalled[c] = true
+
+	c.UnlistedHandle("/debug/flags", http.HandlerFunc(f.Index))
+	c.UnlistedHandlePrefix("/debug/flags/", http.HandlerFunc(f.Index))
+}
+
 // Index responds with the `/debug/flags` request.
 // For example, "/debug/flags/v" serves the "--v" flag.
 // Index responds to a request for "/debug/flags/" with an HTML page
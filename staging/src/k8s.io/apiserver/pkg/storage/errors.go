@@ -30,6 +30,9 @@ const (
 	ErrCodeResourceVersionConflicts
 	ErrCodeInvalidObj
 	ErrCodeUnreachable
+	ErrCodeDecryptionFailed
+	ErrCodeKMSUnavailable
+	ErrCodeStaleKey
 )
 
 var errCodeToMessage = map[int]string{
@@ -38,6 +41,9 @@ var errCodeToMessage = map[int]string{
 	ErrCodeResourceVersionConflicts: "resource version conflicts",
 	ErrCodeInvalidObj:               "invalid object",
 	ErrCodeUnreachable:              "server unreachable",
+	ErrCodeDecryptionFailed:         "decryption failed",
+	ErrCodeKMSUnavailable:           "KMS provider unavailable",
+	ErrCodeStaleKey:                 "stale encryption key",
 }
 
 func NewKeyNotFoundError(key string, rv int64) *StorageError {
@@ -72,6 +78,36 @@ func NewUnreachableError(key string, rv int64) *StorageError {
 	}
 }
 
+// NewDecryptionFailedError is returned when the data stored at key could not
+// be decrypted with any of the configured keys.
+func NewDecryptionFailedError(key, msg string) *StorageError {
+	return &StorageError{
+		Code:               ErrCodeDecryptionFailed,
+		Key:                key,
+		AdditionalErrorMsg: msg,
+	}
+}
+
+// NewKMSUnavailableError is returned when the KMS provider needed to transform
+// the data stored at key could not be reached.
+func NewKMSUnavailableError(key, msg string) *StorageError {
+	return &StorageError{
+		Code:               ErrCodeKMSUnavailable,
+		Key:                key,
+		AdditionalErrorMsg: msg,
+	}
+}
+
+// NewStaleKeyError is returned when the data stored at key was written under
+// an encryption key that is no longer configured.
+func NewStaleKeyError(key, msg string) *StorageError {
+	return &StorageError{
+		Code:               ErrCodeStaleKey,
+		Key:                key,
+		AdditionalErrorMsg: msg,
+	}
+}
+
 func NewInvalidObjError(key, msg string) *StorageError {
 	return &StorageError{
 		Code:               ErrCodeInvalidObj,
@@ -117,6 +153,21 @@ func IsInvalidObj(err error) bool {
 	return isErrCode(err, ErrCodeInvalidObj)
 }
 
+// IsDecryptionFailed returns true if and only if err indicates stored data could not be decrypted.
+func IsDecryptionFailed(err error) bool {
+	return isErrCode(err, ErrCodeDecryptionFailed)
+}
+
+// IsKMSUnavailable returns true if and only if err indicates the KMS provider could not be reached.
+func IsKMSUnavailable(err error) bool {
+	return isErrCode(err, ErrCodeKMSUnavailable)
+}
+
+// IsStaleKey returns true if and only if err indicates stored data was written under a key that is no longer configured.
+func IsStaleKey(err error) bool {
+	return isErrCode(err, ErrCodeStaleKey)
+}
+
 func isErrCode(err error, code int) bool {
 	if err == nil {
 		return false
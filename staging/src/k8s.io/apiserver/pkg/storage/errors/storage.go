@@ -17,11 +17,64 @@ limitations under the License.
 package storage
 
 import (
+	"net/http"
+
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/storage"
 )
 
+const (
+	// StatusReasonDecryptionFailed is returned when the data stored for an
+	// object could not be decrypted with any of the configured keys.
+	StatusReasonDecryptionFailed metav1.StatusReason = "DecryptionFailed"
+	// StatusReasonKMSUnavailable is returned when the KMS provider needed to
+	// transform the stored data could not be reached. The request can be retried.
+	StatusReasonKMSUnavailable metav1.StatusReason = "KMSProviderUnavailable"
+	// StatusReasonStaleKey is returned when the data stored for an object was
+	// written under an encryption key that is no longer configured.
+	StatusReasonStaleKey metav1.StatusReason = "StaleEncryptionKey"
+)
+
+// kmsRetryAfterSeconds is the delay suggested to clients while a KMS provider
+// is unavailable. Plugin outages usually clear within a health probe interval,
+// so a short hint keeps client-go backoff from escalating needlessly.
+const kmsRetryAfterSeconds = 5
+
+// isTransformationError returns true if err is a storage transformation
+// failure that has a more specific client-facing reason than InternalError.
+func isTransformationError(err error) bool {
+	return storage.IsDecryptionFailed(err) || storage.IsKMSUnavailable(err) || storage.IsStaleKey(err)
+}
+
+// interpretTransformationError converts a storage transformation failure into
+// a StatusError with a distinct reason, so clients and operators can tell data
+// corruption apart from a KMS outage.
+func interpretTransformationError(err error) *errors.StatusError {
+	reason := StatusReasonDecryptionFailed
+	code := http.StatusInternalServerError
+	var details *metav1.StatusDetails
+	switch {
+	case storage.IsKMSUnavailable(err):
+		reason = StatusReasonKMSUnavailable
+		code = http.StatusServiceUnavailable
+		// the outage is transient; tell clients when to retry so client-go
+		// rate limiting and controllers back off instead of hammering the
+		// apiserver while the plugin recovers
+		details = &metav1.StatusDetails{RetryAfterSeconds: kmsRetryAfterSeconds}
+	case storage.IsStaleKey(err):
+		reason = StatusReasonStaleKey
+	}
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    int32(code),
+		Reason:  reason,
+		Details: details,
+		Message: err.Error(),
+	}}
+}
+
 // InterpretListError converts a generic error on a retrieval
 // operation into the appropriate API error.
 func InterpretListError(err error, qualifiedResource schema.GroupResource) error {
@@ -30,6 +83,8 @@ func InterpretListError(err error, qualifiedResource schema.GroupResource) error
 		return errors.NewNotFound(qualifiedResource, "")
 	case storage.IsUnreachable(err):
 		return errors.NewServerTimeout(qualifiedResource, "list", 2) // TODO: make configurable or handled at a higher level
+	case isTransformationError(err):
+		return interpretTransformationError(err)
 	case storage.IsInternalError(err):
 		return errors.NewInternalError(err)
 	default:
@@ -45,6 +100,8 @@ func InterpretGetError(err error, qualifiedResource schema.GroupResource, name s
 		return errors.NewNotFound(qualifiedResource, name)
 	case storage.IsUnreachable(err):
 		return errors.NewServerTimeout(qualifiedResource, "get", 2) // TODO: make configurable or handled at a higher level
+	case isTransformationError(err):
+		return interpretTransformationError(err)
 	case storage.IsInternalError(err):
 		return errors.NewInternalError(err)
 	default:
@@ -60,6 +117,8 @@ func InterpretCreateError(err error, qualifiedResource schema.GroupResource, nam
 		return errors.NewAlreadyExists(qualifiedResource, name)
 	case storage.IsUnreachable(err):
 		return errors.NewServerTimeout(qualifiedResource, "create", 2) // TODO: make configurable or handled at a higher level
+	case isTransformationError(err):
+		return interpretTransformationError(err)
 	case storage.IsInternalError(err):
 		return errors.NewInternalError(err)
 	default:
@@ -77,6 +136,8 @@ func InterpretUpdateError(err error, qualifiedResource schema.GroupResource, nam
 		return errors.NewServerTimeout(qualifiedResource, "update", 2) // TODO: make configurable or handled at a higher level
 	case storage.IsNotFound(err):
 		return errors.NewNotFound(qualifiedResource, name)
+	case isTransformationError(err):
+		return interpretTransformationError(err)
 	case storage.IsInternalError(err):
 		return errors.NewInternalError(err)
 	default:
@@ -94,6 +155,8 @@ func InterpretDeleteError(err error, qualifiedResource schema.GroupResource, nam
 		return errors.NewServerTimeout(qualifiedResource, "delete", 2) // TODO: make configurable or handled at a higher level
 	case storage.IsConflict(err), storage.IsExist(err), storage.IsInvalidObj(err):
 		return errors.NewConflict(qualifiedResource, name, err)
+	case isTransformationError(err):
+		return interpretTransformationError(err)
 	case storage.IsInternalError(err):
 		return errors.NewInternalError(err)
 	default:
@@ -108,6 +171,8 @@ func InterpretWatchError(err error, resource schema.GroupResource, name string)
 	case storage.IsInvalidError(err):
 		invalidError, _ := err.(storage.InvalidError)
 		return errors.NewInvalid(schema.GroupKind{Group: resource.Group, Kind: resource.Resource}, name, invalidError.Errs)
+	case isTransformationError(err):
+		return interpretTransformationError(err)
 	case storage.IsInternalError(err):
 		return errors.NewInternalError(err)
 	default:
@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/klog/v2"
+)
+
+// quarantineThreshold is the number of consecutive decryption failures after
+// which a record is quarantined: it becomes visible through the quarantine
+// admin endpoint, and if quarantine recovery is enabled, LIST skips it
+// instead of failing wholesale.
+const quarantineThreshold = 3
+
+// listSkipEnabled controls whether quarantined records are skipped on LIST.
+// It defaults to false, keeping the long-standing behavior of failing the
+// whole LIST on an undecryptable record: silently omitting objects from LISTs
+// is only acceptable when an operator explicitly asked for it.
+var listSkipEnabled atomic.Bool
+
+// EnableQuarantineListSkip opts the process into skipping quarantined records
+// on LIST instead of failing the request. Without it, quarantine only tracks
+// failing records for observability.
+func EnableQuarantineListSkip() {
+	listSkipEnabled.Store(true)
+}
+
+// QuarantinedRecord describes one record that failed decryption, as served by
+// the quarantine admin endpoint.
+type QuarantinedRecord struct {
+	// Key is the full etcd key of the record.
+	Key string `json:"key"`
+	// GroupResource is the resource the record belongs to.
+	GroupResource string `json:"groupResource"`
+	// Failures counts consecutive decryption failures of the record.
+	Failures int `json:"failures"`
+	// Quarantined is true once Failures reached the quarantine threshold. The
+	// record is then skipped on LIST if quarantine recovery is enabled.
+	Quarantined bool `json:"quarantined"`
+	// FirstFailure and LastFailure bound the window of observed failures.
+	FirstFailure time.Time `json:"firstFailure"`
+	LastFailure  time.Time `json:"lastFailure"`
+	// LastError is the most recent decryption error.
+	LastError string `json:"lastError"`
+}
+
+type quarantineEntry struct {
+	record QuarantinedRecord
+	store  *store
+}
+
+// quarantineIndex tracks records that repeatedly fail decryption across all
+// stores of the process. It is deliberately a process-wide singleton: stores
+// are constructed per resource deep inside the storage factory, while the
+// admin endpoint needs a view across all of them.
+type quarantineIndex struct {
+	// active is true while the index holds entries, so the per-item clear on
+	// the LIST and GET hot paths costs a single atomic load in the common
+	// case of an empty index.
+	active  atomic.Bool
+	lock    sync.Mutex
+	entries map[string]*quarantineEntry
+}
+
+var globalQuarantine = &quarantineIndex{entries: map[string]*quarantineEntry{}}
+
+// isQuarantineReason returns true for transformation failures caused by the
+// stored data itself. Transient provider outages never quarantine a record.
+func isQuarantineReason(err error) bool {
+	switch value.ReasonForError(err) {
+	case value.ReasonDecryptionFailed, value.ReasonStaleKey:
+		return true
+	}
+	return false
+}
+
+// record registers a decryption failure of key and reports whether the record
+// is quarantined.
+func (q *quarantineIndex) record(s *store, key string, err error) bool {
+	if !isQuarantineReason(err) {
+		return false
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	entry, ok := q.entries[key]
+	if !ok {
+		entry = &quarantineEntry{
+			record: QuarantinedRecord{
+				Key:           key,
+				GroupResource: s.groupResourceString,
+				FirstFailure:  time.Now(),
+			},
+			store: s,
+		}
+		q.entries[key] = entry
+		q.active.Store(true)
+	}
+	entry.record.Failures++
+	entry.record.LastFailure = time.Now()
+	entry.record.LastError = err.Error()
+	if entry.record.Failures == quarantineThreshold {
+		klog.Errorf("quarantining %s record %q after %d consecutive decryption failures: %v", s.groupResourceString, key, entry.record.Failures, err)
+	}
+	entry.record.Quarantined = entry.record.Failures >= quarantineThreshold
+	return entry.record.Quarantined
+}
+
+// clear drops the entry for key after a successful decryption.
+func (q *quarantineIndex) clear(key string) {
+	if !q.active.Load() {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, ok := q.entries[key]; !ok {
+		return
+	}
+	delete(q.entries, key)
+	if len(q.entries) == 0 {
+		q.active.Store(false)
+	}
+}
+
+// list returns the tracked records sorted by key.
+func (q *quarantineIndex) list() []QuarantinedRecord {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	records := make([]QuarantinedRecord, 0, len(q.entries))
+	for _, entry := range q.entries {
+		records = append(records, entry.record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+	return records
+}
+
+func (q *quarantineIndex) get(key string) (*quarantineEntry, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	entry, ok := q.entries[key]
+	return entry, ok
+}
+
+// recordDecryptionFailure registers a read-path transformation failure of key
+// and reports whether the record may be skipped on LIST, i.e. it is
+// quarantined and skipping was enabled via EnableQuarantineListSkip. Tracking
+// happens regardless of that setting so the admin endpoint stays accurate.
+func (s *store) recordDecryptionFailure(key string, err error) bool {
+	return globalQuarantine.record(s, key, err) && listSkipEnabled.Load()
+}
+
+// clearDecryptionFailure drops any quarantine bookkeeping for key after it
+// decrypted successfully again, e.g. once a missing key was restored to the
+// configuration.
+func (s *store) clearDecryptionFailure(key string) {
+	globalQuarantine.clear(key)
+}
+
+// NewQuarantineHandler returns a handler serving the process-wide quarantine
+// index of records that repeatedly failed decryption. GET lists the affected
+// keys as JSON; GET with ?key=<etcd key>&export=raw returns the stored
+// ciphertext of one record; DELETE with ?key=<etcd key> force-deletes the
+// record from etcd. Export and forced deletion are the escape hatches for
+// records that can no longer be decrypted at all.
+func NewQuarantineHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		switch {
+		case r.Method == http.MethodGet && key == "":
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(struct {
+				Records []QuarantinedRecord `json:"records"`
+			}{Records: globalQuarantine.list()}); err != nil {
+				http.Error(w, "failed to encode quarantine index", http.StatusInternalServerError)
+			}
+		case r.Method == http.MethodGet && r.URL.Query().Get("export") == "raw":
+			entry, ok := globalQuarantine.get(key)
+			if !ok {
+				http.Error(w, fmt.Sprintf("key %q is not quarantined", key), http.StatusNotFound)
+				return
+			}
+			getResp, err := entry.store.client.KV.Get(r.Context(), key)
+			if err != nil {
+				http.Error(w, "failed to read record from etcd", http.StatusInternalServerError)
+				return
+			}
+			if len(getResp.Kvs) == 0 {
+				http.Error(w, fmt.Sprintf("key %q no longer exists", key), http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(getResp.Kvs[0].Value)
+		case r.Method == http.MethodDelete && key != "":
+			entry, ok := globalQuarantine.get(key)
+			if !ok {
+				http.Error(w, fmt.Sprintf("key %q is not quarantined", key), http.StatusNotFound)
+				return
+			}
+			if _, err := entry.store.client.KV.Delete(r.Context(), key); err != nil {
+				http.Error(w, "failed to delete record from etcd", http.StatusInternalServerError)
+				return
+			}
+			globalQuarantine.clear(key)
+			klog.Warningf("force-deleted quarantined %s record %q", entry.record.GroupResource, key)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "GET lists quarantined records, GET with ?key=&export=raw exports one, DELETE with ?key= force-deletes one", http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/apis/example"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+// selectiveFailTransformer fails decryption of every record whose key ends in
+// failKeySuffix, as if that one envelope was corrupt.
+type selectiveFailTransformer struct {
+	delegate      value.Transformer
+	failKeySuffix string
+}
+
+func (t *selectiveFailTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	if strings.HasSuffix(string(dataCtx.AuthenticatedData()), t.failKeySuffix) {
+		return nil, false, value.NewDecryptionFailedError(fmt.Errorf("corrupt envelope"))
+	}
+	return t.delegate.TransformFromStorage(ctx, data, dataCtx)
+}
+
+func (t *selectiveFailTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return t.delegate.TransformToStorage(ctx, data, dataCtx)
+}
+
+func resetQuarantine(t *testing.T) {
+	t.Cleanup(func() {
+		globalQuarantine.lock.Lock()
+		defer globalQuarantine.lock.Unlock()
+		globalQuarantine.entries = map[string]*quarantineEntry{}
+		globalQuarantine.active.Store(false)
+		listSkipEnabled.Store(false)
+	})
+}
+
+func listPods(ctx context.Context, store *store) (*example.PodList, error) {
+	out := &example.PodList{}
+	err := store.GetList(ctx, "/pods", storage.ListOptions{
+		Recursive: true,
+		Predicate: storage.SelectionPredicate{Label: labels.Everything(), Field: fields.Everything()},
+	}, out)
+	return out, err
+}
+
+func TestQuarantineShieldsListAfterRepeatedFailures(t *testing.T) {
+	resetQuarantine(t)
+	EnableQuarantineListSkip()
+	ctx, store, _ := testSetup(t)
+
+	for _, name := range []string{"readable", "corrupt"} {
+		input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if err := store.Create(ctx, "/pods/"+name, input, &example.Pod{}, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	store.transformer = &selectiveFailTransformer{delegate: store.transformer, failKeySuffix: "/pods/corrupt"}
+
+	// until the failure threshold is reached, the unreadable record still
+	// fails the whole LIST
+	for i := 0; i < quarantineThreshold-1; i++ {
+		if _, err := listPods(ctx, store); !storage.IsDecryptionFailed(err) {
+			t.Fatalf("expected a decryption failure on list %d, got: %v", i, err)
+		}
+	}
+
+	// once quarantined, LIST serves the remaining records
+	out, err := listPods(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Items) != 1 || out.Items[0].Name != "readable" {
+		t.Errorf("expected only the readable pod to be listed, got %v", out.Items)
+	}
+
+	// a direct GET of the quarantined record still surfaces the error
+	if err := store.Get(ctx, "/pods/corrupt", storage.GetOptions{}, &example.Pod{}); !storage.IsDecryptionFailed(err) {
+		t.Errorf("expected a decryption failure on get, got: %v", err)
+	}
+}
+
+func TestQuarantineListSkipDisabledByDefault(t *testing.T) {
+	resetQuarantine(t)
+	ctx, store, _ := testSetup(t)
+
+	input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "corrupt"}}
+	if err := store.Create(ctx, "/pods/corrupt", input, &example.Pod{}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.transformer = &selectiveFailTransformer{delegate: store.transformer, failKeySuffix: "/pods/corrupt"}
+
+	// without the opt-in, LIST keeps failing past the quarantine threshold
+	// instead of silently omitting the record
+	for i := 0; i < quarantineThreshold+1; i++ {
+		if _, err := listPods(ctx, store); !storage.IsDecryptionFailed(err) {
+			t.Fatalf("expected a decryption failure on list %d, got: %v", i, err)
+		}
+	}
+	// the record is still tracked for the admin endpoint
+	if records := globalQuarantine.list(); len(records) != 1 || !records[0].Quarantined {
+		t.Errorf("expected one quarantined record, got %v", records)
+	}
+}
+
+func TestQuarantineClearsAfterSuccessfulDecryption(t *testing.T) {
+	resetQuarantine(t)
+	ctx, store, _ := testSetup(t)
+
+	input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "flaky"}}
+	if err := store.Create(ctx, "/pods/flaky", input, &example.Pod{}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	goodTransformer := store.transformer
+	store.transformer = &selectiveFailTransformer{delegate: goodTransformer, failKeySuffix: "/pods/flaky"}
+
+	for i := 0; i < quarantineThreshold; i++ {
+		if _, err := listPods(ctx, store); !storage.IsDecryptionFailed(err) && err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if records := globalQuarantine.list(); len(records) != 1 || !records[0].Quarantined {
+		t.Fatalf("expected one quarantined record, got %v", records)
+	}
+
+	// the key decrypts again, e.g. after its key was restored to the config
+	store.transformer = goodTransformer
+	if err := store.Get(ctx, "/pods/flaky", storage.GetOptions{}, &example.Pod{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if records := globalQuarantine.list(); len(records) != 0 {
+		t.Errorf("expected the quarantine entry to be cleared, got %v", records)
+	}
+}
+
+func TestQuarantineHandler(t *testing.T) {
+	resetQuarantine(t)
+	ctx, store, _ := testSetup(t)
+
+	input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "corrupt"}}
+	if err := store.Create(ctx, "/pods/corrupt", input, &example.Pod{}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.transformer = &selectiveFailTransformer{delegate: store.transformer, failKeySuffix: "/pods/corrupt"}
+	for i := 0; i < quarantineThreshold; i++ {
+		listPods(ctx, store)
+	}
+
+	handler := NewQuarantineHandler()
+
+	// list
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/encryption/quarantine", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	index := struct {
+		Records []QuarantinedRecord `json:"records"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &index); err != nil {
+		t.Fatalf("malformed index: %v", err)
+	}
+	if len(index.Records) != 1 || !index.Records[0].Quarantined {
+		t.Fatalf("expected one quarantined record, got %v", index.Records)
+	}
+	key := index.Records[0].Key
+
+	// raw export
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/encryption/quarantine?export=raw&key="+key, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	if w.Body.Len() == 0 {
+		t.Error("expected the raw ciphertext to be exported")
+	}
+
+	// forced deletion
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/debug/encryption/quarantine?key="+key, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	if err := store.Get(ctx, "/pods/corrupt", storage.GetOptions{}, &example.Pod{}); !storage.IsNotFound(err) {
+		t.Errorf("expected the record to be deleted, got: %v", err)
+	}
+	if records := globalQuarantine.list(); len(records) != 0 {
+		t.Errorf("expected the quarantine entry to be removed, got %v", records)
+	}
+}
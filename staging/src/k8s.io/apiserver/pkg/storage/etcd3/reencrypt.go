@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd3
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"k8s.io/apiserver/pkg/storage/etcd3/metrics"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// reencryptQPS and reencryptBurst bound how many stale records may be
+	// rewritten per second across all storage backends of the process, so a
+	// burst of reads of not-yet-migrated records cannot flood the backend
+	// with writes.
+	reencryptQPS   = 5
+	reencryptBurst = 10
+
+	reencryptTimeout = 30 * time.Second
+)
+
+var (
+	reencryptOnRead  int32
+	reencryptLimiter = flowcontrol.NewTokenBucketRateLimiter(reencryptQPS, reencryptBurst)
+)
+
+// SetReencryptOnRead switches lazy storage migration on or off for the
+// process. When enabled, records read back with a non-current storage
+// encryption prefix are transparently rewritten with the current write
+// transformer, so frequently accessed records migrate without waiting for a
+// full storage migration pass.
+func SetReencryptOnRead(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&reencryptOnRead, 1)
+	} else {
+		atomic.StoreInt32(&reencryptOnRead, 0)
+	}
+}
+
+func reencryptOnReadEnabled() bool {
+	return atomic.LoadInt32(&reencryptOnRead) == 1
+}
+
+// maybeReencrypt rewrites the record at key with the current write
+// transformer, if lazy migration is enabled and the rate limit allows it. The
+// write is conditional on the revision the stale data was read at, so any
+// concurrent update wins and the rewrite is dropped. Failures only delay
+// migration of the record, so they are logged and swallowed.
+func (s *store) maybeReencrypt(key string, data []byte, rev int64) {
+	if !reencryptOnReadEnabled() || !reencryptLimiter.TryAccept() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reencryptTimeout)
+		defer cancel()
+
+		newData, err := s.transformer.TransformToStorage(ctx, data, authenticatedDataString(key))
+		if err != nil {
+			klog.V(4).InfoS("re-encrypt on read failed to transform record", "key", key, "err", err)
+			return
+		}
+
+		startTime := time.Now()
+		txnResp, err := s.client.KV.Txn(ctx).If(
+			clientv3.Compare(clientv3.ModRevision(key), "=", rev),
+		).Then(
+			clientv3.OpPut(key, string(newData)),
+		).Commit()
+		metrics.RecordEtcdRequestLatency("update", s.groupResourceString, startTime)
+		if err != nil {
+			klog.V(4).InfoS("re-encrypt on read failed to write record", "key", key, "err", err)
+			return
+		}
+		if !txnResp.Succeeded {
+			// the record changed since it was read; the update rewrote it already
+			return
+		}
+		klog.V(4).InfoS("re-encrypted stale record on read", "key", key)
+	}()
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd3
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/example"
+	"k8s.io/apiserver/pkg/storage"
+	storagetesting "k8s.io/apiserver/pkg/storage/testing"
+)
+
+func TestReencryptOnRead(t *testing.T) {
+	ctx, store, etcdClient := testSetup(t)
+	// report every read as stale, as if the record was written under a
+	// non-current provider
+	store.transformer = storagetesting.NewPrefixTransformer([]byte(defaultTestPrefix), true)
+
+	SetReencryptOnRead(true)
+	defer SetReencryptOnRead(false)
+
+	key := "/foo"
+	input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	if err := store.Create(ctx, key, input, &example.Pod{}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	preparedKey, err := store.prepareKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rawResp, err := etcdClient.KV.Get(ctx, preparedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rawResp.Kvs) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(rawResp.Kvs))
+	}
+	createdRev := rawResp.Kvs[0].ModRevision
+
+	if err := store.Get(ctx, key, storage.GetOptions{}, &example.Pod{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the stale read should trigger an asynchronous rewrite of the record
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		rawResp, err := etcdClient.KV.Get(ctx, preparedKey)
+		if err != nil {
+			return false, err
+		}
+		if len(rawResp.Kvs) != 1 {
+			return false, nil
+		}
+		return rawResp.Kvs[0].ModRevision > createdRev, nil
+	}); err != nil {
+		t.Fatalf("record was not rewritten after a stale read: %v", err)
+	}
+
+	out := &example.Pod{}
+	if err := store.Get(ctx, key, storage.GetOptions{}, out); err != nil {
+		t.Fatalf("unexpected error reading the rewritten record: %v", err)
+	}
+	if out.Name != "foo" {
+		t.Errorf("expected pod foo, got %q", out.Name)
+	}
+}
+
+func TestReencryptOnReadDisabled(t *testing.T) {
+	ctx, store, etcdClient := testSetup(t)
+	store.transformer = storagetesting.NewPrefixTransformer([]byte(defaultTestPrefix), true)
+
+	key := "/foo"
+	input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	if err := store.Create(ctx, key, input, &example.Pod{}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	preparedKey, err := store.prepareKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rawResp, err := etcdClient.KV.Get(ctx, preparedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	createdRev := rawResp.Kvs[0].ModRevision
+
+	if err := store.Get(ctx, key, storage.GetOptions{}, &example.Pod{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	rawResp, err = etcdClient.KV.Get(ctx, preparedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rawResp.Kvs[0].ModRevision != createdRev {
+		t.Errorf("record was rewritten although re-encrypt on read is disabled")
+	}
+}
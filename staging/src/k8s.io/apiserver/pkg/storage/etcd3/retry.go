@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd3
+
+import (
+	"context"
+	"time"
+)
+
+// ConflictRetryPolicy bounds how GuaranteedUpdate retries an update whose etcd
+// transaction failed its ModRevision guard because another writer got there
+// first. The zero value retries immediately and without bound, the historic
+// behavior; a bounded policy trades availability of hot objects for a cap on
+// the work one contended key can consume.
+type ConflictRetryPolicy struct {
+	// MaxAttempts is how many times a conflicting update is retried before the
+	// conflict is returned to the caller. Zero means retry without bound.
+	MaxAttempts int
+	// InitialBackoff is slept before the first retry, doubling on every
+	// further retry up to MaxBackoff. Zero means retry immediately.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the doubling of InitialBackoff. Zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// exhausted reports whether the given 1-based retry attempt exceeds the
+// policy.
+func (p ConflictRetryPolicy) exhausted(attempt int) bool {
+	return p.MaxAttempts > 0 && attempt > p.MaxAttempts
+}
+
+// backoff returns how long to sleep before the given 1-based retry attempt.
+func (p ConflictRetryPolicy) backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+	delay := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// sleep blocks for the backoff of the given retry attempt, or until the
+// context is canceled.
+func (p ConflictRetryPolicy) sleep(ctx context.Context, attempt int) error {
+	delay := p.backoff(attempt)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd3
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConflictRetryPolicyExhausted(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    ConflictRetryPolicy
+		attempt   int
+		exhausted bool
+	}{
+		{
+			name:      "zero value never exhausts",
+			policy:    ConflictRetryPolicy{},
+			attempt:   1000,
+			exhausted: false,
+		},
+		{
+			name:      "attempt within bound",
+			policy:    ConflictRetryPolicy{MaxAttempts: 3},
+			attempt:   3,
+			exhausted: false,
+		},
+		{
+			name:      "attempt beyond bound",
+			policy:    ConflictRetryPolicy{MaxAttempts: 3},
+			attempt:   4,
+			exhausted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.exhausted(tt.attempt); got != tt.exhausted {
+				t.Errorf("exhausted(%d) = %v, want %v", tt.attempt, got, tt.exhausted)
+			}
+		})
+	}
+}
+
+func TestConflictRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ConflictRetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "zero value retries immediately",
+			policy:  ConflictRetryPolicy{},
+			attempt: 5,
+			want:    0,
+		},
+		{
+			name:    "first retry uses the initial backoff",
+			policy:  ConflictRetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond},
+			attempt: 1,
+			want:    10 * time.Millisecond,
+		},
+		{
+			name:    "backoff doubles per retry",
+			policy:  ConflictRetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond},
+			attempt: 2,
+			want:    20 * time.Millisecond,
+		},
+		{
+			name:    "backoff is capped",
+			policy:  ConflictRetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond},
+			attempt: 10,
+			want:    40 * time.Millisecond,
+		},
+		{
+			name:    "no cap keeps doubling",
+			policy:  ConflictRetryPolicy{InitialBackoff: 10 * time.Millisecond},
+			attempt: 4,
+			want:    80 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.attempt); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConflictRetryPolicySleepCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	policy := ConflictRetryPolicy{InitialBackoff: time.Hour}
+	if err := policy.sleep(ctx, 1); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
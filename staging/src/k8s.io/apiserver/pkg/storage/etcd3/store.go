@@ -24,8 +24,10 @@ import (
 	"path"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.opentelemetry.io/otel/attribute"
 
@@ -53,6 +55,12 @@ const (
 	// This limit is used only for increasing page size by kube-apiserver. If request
 	// specifies larger limit initially, it won't be changed.
 	maxLimit = 10000
+
+	// DefaultListDecryptWorkers is the default bound on how many list items
+	// are decrypted concurrently when serving a LIST request. Decryption of
+	// KMS-encrypted resources pays a per-item cache lookup or Decrypt call,
+	// which serializes large lists when done one item at a time.
+	DefaultListDecryptWorkers = 8
 )
 
 // authenticatedDataString satisfies the value.Context interface. It uses the key to
@@ -81,6 +89,8 @@ type store struct {
 	groupResourceString string
 	watcher             *watcher
 	pagingEnabled       bool
+	listDecryptWorkers  int
+	conflictRetry       ConflictRetryPolicy
 	leaseManager        *leaseManager
 }
 
@@ -93,11 +103,11 @@ type objState struct {
 }
 
 // New returns an etcd3 implementation of storage.Interface.
-func New(c *clientv3.Client, codec runtime.Codec, newFunc func() runtime.Object, prefix string, groupResource schema.GroupResource, transformer value.Transformer, pagingEnabled bool, leaseManagerConfig LeaseManagerConfig) storage.Interface {
-	return newStore(c, codec, newFunc, prefix, groupResource, transformer, pagingEnabled, leaseManagerConfig)
+func New(c *clientv3.Client, codec runtime.Codec, newFunc func() runtime.Object, prefix string, groupResource schema.GroupResource, transformer value.Transformer, pagingEnabled bool, listDecryptWorkers int, conflictRetry ConflictRetryPolicy, leaseManagerConfig LeaseManagerConfig) storage.Interface {
+	return newStore(c, codec, newFunc, prefix, groupResource, transformer, pagingEnabled, listDecryptWorkers, conflictRetry, leaseManagerConfig)
 }
 
-func newStore(c *clientv3.Client, codec runtime.Codec, newFunc func() runtime.Object, prefix string, groupResource schema.GroupResource, transformer value.Transformer, pagingEnabled bool, leaseManagerConfig LeaseManagerConfig) *store {
+func newStore(c *clientv3.Client, codec runtime.Codec, newFunc func() runtime.Object, prefix string, groupResource schema.GroupResource, transformer value.Transformer, pagingEnabled bool, listDecryptWorkers int, conflictRetry ConflictRetryPolicy, leaseManagerConfig LeaseManagerConfig) *store {
 	versioner := storage.APIObjectVersioner{}
 	// for compatibility with etcd2 impl.
 	// no-op for default prefix of '/registry'.
@@ -113,6 +123,8 @@ func newStore(c *clientv3.Client, codec runtime.Codec, newFunc func() runtime.Ob
 		versioner:           versioner,
 		transformer:         transformer,
 		pagingEnabled:       pagingEnabled,
+		listDecryptWorkers:  listDecryptWorkers,
+		conflictRetry:       conflictRetry,
 		pathPrefix:          pathPrefix,
 		groupResource:       groupResource,
 		groupResourceString: groupResource.String(),
@@ -151,9 +163,17 @@ func (s *store) Get(ctx context.Context, key string, opts storage.GetOptions, ou
 	}
 	kv := getResp.Kvs[0]
 
-	data, _, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(preparedKey))
+	data, stale, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(preparedKey))
 	if err != nil {
-		return storage.NewInternalError(err.Error())
+		// a direct GET still surfaces the error; quarantine only shields LISTs
+		s.recordDecryptionFailure(preparedKey, err)
+		return transformationError(err, preparedKey)
+	}
+	s.clearDecryptionFailure(preparedKey)
+
+	if stale && kv.Lease == 0 {
+		// leased records are skipped: a plain rewrite would detach the lease
+		s.maybeReencrypt(preparedKey, data, kv.ModRevision)
 	}
 
 	return decode(s.codec, s.versioner, data, out, kv.ModRevision)
@@ -194,9 +214,10 @@ func (s *store) Create(ctx context.Context, key string, obj, out runtime.Object,
 	newData, err := s.transformer.TransformToStorage(ctx, data, authenticatedDataString(preparedKey))
 	if err != nil {
 		span.AddEvent("TransformToStorage failed", attribute.String("err", err.Error()))
-		return storage.NewInternalError(err.Error())
+		return transformationError(err, preparedKey)
 	}
 	span.AddEvent("TransformToStorage succeeded")
+	metrics.RecordStorageSizeRatio(s.groupResourceString, len(data), len(newData))
 
 	startTime := time.Now()
 	txnResp, err := s.client.KV.Txn(ctx).If(
@@ -400,6 +421,7 @@ func (s *store) GuaranteedUpdate(
 	span.AddEvent("initial value restored")
 
 	transformContext := authenticatedDataString(preparedKey)
+	conflicts := 0
 	for {
 		if err := preconditions.Check(preparedKey, origState.obj); err != nil {
 			// If our data is already up to date, return the error
@@ -477,9 +499,10 @@ func (s *store) GuaranteedUpdate(
 		newData, err := s.transformer.TransformToStorage(ctx, data, transformContext)
 		if err != nil {
 			span.AddEvent("TransformToStorage failed", attribute.String("err", err.Error()))
-			return storage.NewInternalError(err.Error())
+			return transformationError(err, preparedKey)
 		}
 		span.AddEvent("TransformToStorage succeeded")
+		metrics.RecordStorageSizeRatio(s.groupResourceString, len(data), len(newData))
 
 		opts, err := s.ttlOpts(ctx, int64(ttl))
 		if err != nil {
@@ -504,7 +527,16 @@ func (s *store) GuaranteedUpdate(
 		span.AddEvent("Transaction committed")
 		if !txnResp.Succeeded {
 			getResp := (*clientv3.GetResponse)(txnResp.Responses[0].GetResponseRange())
+			metrics.RecordStorageUpdateConflict(s.groupResourceString)
+			conflicts++
+			if s.conflictRetry.exhausted(conflicts) {
+				klog.V(4).Infof("GuaranteedUpdate of %s gave up after %d conflicts", preparedKey, conflicts)
+				return storage.NewResourceVersionConflictsError(preparedKey, origState.rev)
+			}
 			klog.V(4).Infof("GuaranteedUpdate of %s failed because of a conflict, going to retry", preparedKey)
+			if err := s.conflictRetry.sleep(ctx, conflicts); err != nil {
+				return err
+			}
 			origState, err = s.getState(ctx, getResp, preparedKey, v, ignoreNotFound)
 			if err != nil {
 				return err
@@ -731,6 +763,24 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 			growSlice(v, 2048, len(getResp.Kvs))
 		}
 
+		// decrypt the page up front with a bounded worker pool, so large
+		// lists of envelope-encrypted objects do not serialize on per-item
+		// Decrypt calls and DEK cache lookups. Only the prefix of the page
+		// that is guaranteed to be consumed is decrypted eagerly: the bucket
+		// accepts at least limit-v.Len() more items before the loop below can
+		// break, so this transforms exactly the items the sequential loop
+		// would, never more.
+		guaranteed := len(getResp.Kvs)
+		if paging && pred.Limit > 0 {
+			if remaining := pred.Limit - int64(v.Len()); remaining < int64(guaranteed) {
+				guaranteed = int(remaining)
+				if guaranteed < 0 {
+					guaranteed = 0
+				}
+			}
+		}
+		decrypted := s.decryptListPage(ctx, getResp.Kvs[:guaranteed])
+
 		// take items from the response until the bucket is full, filtering as we go
 		for i, kv := range getResp.Kvs {
 			if paging && int64(v.Len()) >= pred.Limit {
@@ -739,10 +789,28 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 			}
 			lastKey = kv.Key
 
-			data, _, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(kv.Key))
+			var data []byte
+			var err error
+			if i < len(decrypted) {
+				data, err = decrypted[i].data, decrypted[i].err
+			} else {
+				// items past the guaranteed prefix may not be consumed at
+				// all, so they are decrypted only on demand
+				data, _, err = s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(kv.Key))
+			}
 			if err != nil {
-				return storage.NewInternalErrorf("unable to transform key %q: %v", kv.Key, err)
+				// if list-skipping was opted into, quarantined records are
+				// skipped so one unreadable object does not fail every LIST;
+				// they stay reachable through the quarantine admin endpoint
+				// until exported or deleted. By default the LIST fails.
+				if s.recordDecryptionFailure(string(kv.Key), err) {
+					klog.Warningf("skipping quarantined %s record %q on list: %v", s.groupResourceString, string(kv.Key), err)
+					getResp.Kvs[i] = nil
+					continue
+				}
+				return transformationError(err, string(kv.Key))
 			}
+			s.clearDecryptionFailure(string(kv.Key))
 
 			if err := appendListItem(v, data, uint64(kv.ModRevision), pred, s.codec, s.versioner, newItemFunc); err != nil {
 				return err
@@ -815,6 +883,59 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 // educated guess about the potential size of the total list while
 // still avoiding overly aggressive initial allocation. If sizes
 // is empty maxCapacity will be used as the size to grow.
+// decryptedListItem is the result of transforming one raw list item from its
+// storage format.
+type decryptedListItem struct {
+	data []byte
+	err  error
+}
+
+// decryptListPage transforms one page of raw list results from their storage
+// format, running at most s.listDecryptWorkers transformations concurrently.
+// Values below two fall back to sequential decryption. Results are returned
+// in input order; errors are reported per item so the caller keeps deciding
+// how to handle them.
+func (s *store) decryptListPage(ctx context.Context, kvs []*mvccpb.KeyValue) []decryptedListItem {
+	startTime := time.Now()
+	defer metrics.RecordStorageListDecryptLatency(s.groupResourceString, startTime)
+
+	decrypted := make([]decryptedListItem, len(kvs))
+	decryptOne := func(i int) {
+		kv := kvs[i]
+		data, _, err := s.transformer.TransformFromStorage(ctx, kv.Value, authenticatedDataString(kv.Key))
+		decrypted[i] = decryptedListItem{data: data, err: err}
+	}
+
+	workers := s.listDecryptWorkers
+	if workers > len(kvs) {
+		workers = len(kvs)
+	}
+	if workers < 2 {
+		for i := range kvs {
+			decryptOne(i)
+		}
+		return decrypted
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				decryptOne(i)
+			}
+		}()
+	}
+	for i := range kvs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return decrypted
+}
+
 func growSlice(v reflect.Value, maxCapacity int, sizes ...int) {
 	cap := v.Cap()
 	max := cap
@@ -873,8 +994,10 @@ func (s *store) getState(ctx context.Context, getResp *clientv3.GetResponse, key
 	} else {
 		data, stale, err := s.transformer.TransformFromStorage(ctx, getResp.Kvs[0].Value, authenticatedDataString(key))
 		if err != nil {
-			return nil, storage.NewInternalError(err.Error())
+			s.recordDecryptionFailure(key, err)
+			return nil, transformationError(err, key)
 		}
+		s.clearDecryptionFailure(key)
 		state.rev = getResp.Kvs[0].ModRevision
 		state.meta.ResourceVersion = uint64(state.rev)
 		state.data = data
@@ -1022,6 +1145,21 @@ func notFound(key string) clientv3.Cmp {
 	return clientv3.Compare(clientv3.ModRevision(key), "=", 0)
 }
 
+// transformationError converts a value transformation failure into a storage
+// error that carries the reason for the failure, so callers can tell
+// unreadable data and KMS outages apart from generic internal errors.
+func transformationError(err error, key string) error {
+	switch value.ReasonForError(err) {
+	case value.ReasonDecryptionFailed:
+		return storage.NewDecryptionFailedError(key, err.Error())
+	case value.ReasonKMSUnavailable:
+		return storage.NewKMSUnavailableError(key, err.Error())
+	case value.ReasonStaleKey:
+		return storage.NewStaleKeyError(key, err.Error())
+	}
+	return storage.NewInternalError(err.Error())
+}
+
 // getTypeName returns type name of an object for reporting purposes.
 func getTypeName(obj interface{}) string {
 	return reflect.TypeOf(obj).String()
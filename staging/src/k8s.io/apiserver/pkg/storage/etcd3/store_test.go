@@ -25,7 +25,9 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/embed"
 	"google.golang.org/grpc/grpclog"
@@ -182,6 +184,45 @@ func TestGuaranteedUpdateWithSuggestionAndConflict(t *testing.T) {
 	storagetesting.RunTestGuaranteedUpdateWithSuggestionAndConflict(ctx, t, store)
 }
 
+func TestGuaranteedUpdateConflictRetryExhaustion(t *testing.T) {
+	ctx, store, _ := testSetup(t, withConflictRetry(ConflictRetryPolicy{MaxAttempts: 2}))
+
+	key := "/testkey"
+	out := &example.Pod{}
+	if err := store.Create(ctx, key, &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}, out, 0); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	attempts := 0
+	err := store.GuaranteedUpdate(ctx, key, &example.Pod{}, false, nil,
+		storage.SimpleUpdate(func(obj runtime.Object) (runtime.Object, error) {
+			attempts++
+			// a competing writer bumps the key on every attempt, so the
+			// transaction's revision guard can never hold
+			if err := store.GuaranteedUpdate(ctx, key, &example.Pod{}, false, nil,
+				storage.SimpleUpdate(func(competing runtime.Object) (runtime.Object, error) {
+					pod := competing.(*example.Pod)
+					pod.Labels = map[string]string{"competing-write": fmt.Sprintf("%d", attempts)}
+					return pod, nil
+				}), nil); err != nil {
+				return nil, err
+			}
+			pod := obj.(*example.Pod)
+			pod.Annotations = map[string]string{"attempt": fmt.Sprintf("%d", attempts)}
+			return pod, nil
+		}), nil)
+	if err == nil {
+		t.Fatal("expected the update to give up after exhausting its conflict retries")
+	}
+	if !storage.IsConflict(err) {
+		t.Errorf("expected a conflict error, got: %v", err)
+	}
+	// the initial attempt plus MaxAttempts retries
+	if want := 3; attempts != want {
+		t.Errorf("expected %d update attempts, got %d", want, attempts)
+	}
+}
+
 func TestTransformationFailure(t *testing.T) {
 	ctx, store, _ := testSetup(t)
 	storagetesting.RunTestTransformationFailure(ctx, t, &storeWithPrefixTransformer{store})
@@ -463,14 +504,16 @@ func (r *clientRecorder) GetReadsAndReset() uint64 {
 }
 
 type setupOptions struct {
-	client        func(*testing.T) *clientv3.Client
-	codec         runtime.Codec
-	newFunc       func() runtime.Object
-	prefix        string
-	groupResource schema.GroupResource
-	transformer   value.Transformer
-	pagingEnabled bool
-	leaseConfig   LeaseManagerConfig
+	client             func(*testing.T) *clientv3.Client
+	codec              runtime.Codec
+	newFunc            func() runtime.Object
+	prefix             string
+	groupResource      schema.GroupResource
+	transformer        value.Transformer
+	pagingEnabled      bool
+	listDecryptWorkers int
+	conflictRetry      ConflictRetryPolicy
+	leaseConfig        LeaseManagerConfig
 
 	recorderEnabled bool
 }
@@ -503,6 +546,12 @@ func withLeaseConfig(leaseConfig LeaseManagerConfig) setupOption {
 	}
 }
 
+func withConflictRetry(policy ConflictRetryPolicy) setupOption {
+	return func(options *setupOptions) {
+		options.conflictRetry = policy
+	}
+}
+
 func withRecorder() setupOption {
 	return func(options *setupOptions) {
 		options.recorderEnabled = true
@@ -519,6 +568,7 @@ func withDefaults(options *setupOptions) {
 	options.groupResource = schema.GroupResource{Resource: "pods"}
 	options.transformer = newTestTransformer()
 	options.pagingEnabled = true
+	options.listDecryptWorkers = DefaultListDecryptWorkers
 	options.leaseConfig = newTestLeaseManagerConfig()
 }
 
@@ -542,6 +592,8 @@ func testSetup(t *testing.T, opts ...setupOption) (context.Context, *store, *cli
 		setupOpts.groupResource,
 		setupOpts.transformer,
 		setupOpts.pagingEnabled,
+		setupOpts.listDecryptWorkers,
+		setupOpts.conflictRetry,
 		setupOpts.leaseConfig,
 	)
 	ctx := context.Background()
@@ -622,3 +674,84 @@ func TestInvalidKeys(t *testing.T) {
 	_, countErr := store.Count(invalidKey)
 	expectInvalidKey("Count", countErr)
 }
+
+type funcTransformer struct {
+	from func(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error)
+}
+
+func (t *funcTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	return t.from(ctx, data, dataCtx)
+}
+
+func (t *funcTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return data, nil
+}
+
+func TestDecryptListPage(t *testing.T) {
+	kvs := make([]*mvccpb.KeyValue, 10)
+	for i := range kvs {
+		kvs[i] = &mvccpb.KeyValue{
+			Key:   []byte(fmt.Sprintf("/pods/pod-%d", i)),
+			Value: []byte(fmt.Sprintf("value-%d", i)),
+		}
+	}
+
+	for _, workers := range []int{0, 1, 2, 8} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			var current, peak int32
+			s := &store{
+				groupResourceString: "pods",
+				listDecryptWorkers:  workers,
+				transformer: &funcTransformer{
+					from: func(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+						cur := atomic.AddInt32(&current, 1)
+						defer atomic.AddInt32(&current, -1)
+						for {
+							observed := atomic.LoadInt32(&peak)
+							if cur <= observed || atomic.CompareAndSwapInt32(&peak, observed, cur) {
+								break
+							}
+						}
+						// give the other workers a chance to overlap
+						time.Sleep(time.Millisecond)
+						if strings.HasSuffix(string(dataCtx.AuthenticatedData()), "-3") {
+							return nil, false, fmt.Errorf("broken record")
+						}
+						return append([]byte("decrypted:"), data...), false, nil
+					},
+				},
+			}
+
+			decrypted := s.decryptListPage(context.Background(), kvs)
+			if len(decrypted) != len(kvs) {
+				t.Fatalf("expected %d results, got %d", len(kvs), len(decrypted))
+			}
+			for i, item := range decrypted {
+				if i == 3 {
+					if item.err == nil || item.err.Error() != "broken record" {
+						t.Errorf("expected the error of item 3 to be reported in place, got %v", item.err)
+					}
+					continue
+				}
+				if item.err != nil {
+					t.Errorf("unexpected error for item %d: %v", i, item.err)
+					continue
+				}
+				if expected := fmt.Sprintf("decrypted:value-%d", i); string(item.data) != expected {
+					t.Errorf("expected item %d to decrypt to %q in input order, got %q", i, expected, string(item.data))
+				}
+			}
+
+			bound := workers
+			if bound < 1 {
+				bound = 1
+			}
+			if int(peak) > bound {
+				t.Errorf("observed %d concurrent transformations, bound is %d", peak, bound)
+			}
+			if workers >= 2 && peak < 2 {
+				t.Errorf("expected overlapping transformations with %d workers, observed peak %d", workers, peak)
+			}
+		})
+	}
+}
@@ -42,6 +42,11 @@ const (
 	// We have set a buffer in order to reduce times of context switches.
 	incomingBufSize = 100
 	outgoingBufSize = 100
+	// watchDecryptWorkers bounds how many incoming events a single watch
+	// channel transforms concurrently, so that a burst of updates on an
+	// encrypted resource is not serialized behind one slow KMS decryption.
+	// Results are still delivered in the order the events arrived.
+	watchDecryptWorkers = 8
 )
 
 // fatalOnDecodeError is used during testing to panic the server if watcher encounters a decoding error
@@ -273,27 +278,57 @@ func (wc *watchChan) startWatching(watchClosedCh chan struct{}) {
 }
 
 // processEvent processes events from etcd watcher and sends results to resultChan.
+// Events are transformed on a bounded pool of goroutines so that decryption of
+// a burst of events can overlap, while results are delivered strictly in the
+// order the events arrived - a watcher therefore still observes every key's
+// history in etcd revision order.
 func (wc *watchChan) processEvent(wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	for {
-		select {
-		case e := <-wc.incomingEventChan:
-			res := wc.transform(e)
-			if res == nil {
-				continue
-			}
-			if len(wc.resultChan) == outgoingBufSize {
-				klog.V(3).InfoS("Fast watcher, slow processing. Probably caused by slow dispatching events to watchers", "outgoingEvents", outgoingBufSize, "objectType", wc.watcher.objectType, "groupResource", wc.watcher.groupResource)
-			}
-			// If user couldn't receive results fast enough, we also block incoming events from watcher.
-			// Because storing events in local will cause more memory usage.
-			// The worst case would be closing the fast watcher.
+	// pending carries one result channel per in-flight transformation, in
+	// arrival order. Its capacity bounds how many transformations run
+	// concurrently: the dispatching goroutine blocks once it is full.
+	pending := make(chan chan *watch.Event, watchDecryptWorkers)
+	go func() {
+		defer close(pending)
+		for {
 			select {
-			case wc.resultChan <- *res:
+			case e := <-wc.incomingEventChan:
+				resCh := make(chan *watch.Event, 1)
+				select {
+				case pending <- resCh:
+				case <-wc.ctx.Done():
+					return
+				}
+				go func() {
+					// resCh is buffered, so the transformation never
+					// outlives a cancelled watch
+					resCh <- wc.transform(e)
+				}()
 			case <-wc.ctx.Done():
 				return
 			}
+		}
+	}()
+
+	for resCh := range pending {
+		var res *watch.Event
+		select {
+		case res = <-resCh:
+		case <-wc.ctx.Done():
+			return
+		}
+		if res == nil {
+			continue
+		}
+		if len(wc.resultChan) == outgoingBufSize {
+			klog.V(3).InfoS("Fast watcher, slow processing. Probably caused by slow dispatching events to watchers", "outgoingEvents", outgoingBufSize, "objectType", wc.watcher.objectType, "groupResource", wc.watcher.groupResource)
+		}
+		// If user couldn't receive results fast enough, we also block incoming events from watcher.
+		// Because storing events in local will cause more memory usage.
+		// The worst case would be closing the fast watcher.
+		select {
+		case wc.resultChan <- *res:
 		case <-wc.ctx.Done():
 			return
 		}
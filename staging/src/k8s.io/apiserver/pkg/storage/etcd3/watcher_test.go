@@ -19,14 +19,19 @@ package etcd3
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"testing"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/apis/example"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/apiserver/pkg/storage/etcd3/testserver"
 	storagetesting "k8s.io/apiserver/pkg/storage/testing"
+	"k8s.io/apiserver/pkg/storage/value"
 )
 
 func TestWatch(t *testing.T) {
@@ -108,3 +113,55 @@ func TestWatchErrResultNotBlockAfterCancel(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+// jitterTransformer delays every read by a random amount, as a slow KMS
+// plugin would, so reordering by concurrent decryption becomes visible.
+type jitterTransformer struct {
+	delegate value.Transformer
+}
+
+func (t *jitterTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond)
+	return t.delegate.TransformFromStorage(ctx, data, dataCtx)
+}
+
+func (t *jitterTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return t.delegate.TransformToStorage(ctx, data, dataCtx)
+}
+
+// TestWatchDecryptionPreservesOrder verifies that the transformation worker
+// pool in processEvent does not reorder events: even when every decryption
+// takes a different amount of time, watchers observe events in etcd revision
+// order.
+func TestWatchDecryptionPreservesOrder(t *testing.T) {
+	ctx, store, _ := testSetup(t)
+	store.transformer = &jitterTransformer{delegate: store.transformer}
+
+	w, err := store.Watch(ctx, "/pods", storage.ListOptions{ResourceVersion: "0", Predicate: storage.Everything, Recursive: true})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer w.Stop()
+
+	const events = 20
+	for i := 0; i < events; i++ {
+		input := &example.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i)}}
+		if err := store.Create(ctx, fmt.Sprintf("/pods/pod-%d", i), input, &example.Pod{}, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i := 0; i < events; i++ {
+		select {
+		case event := <-w.ResultChan():
+			if event.Type != watch.Added {
+				t.Fatalf("expected an ADDED event, got %v", event.Type)
+			}
+			if name := event.Object.(*example.Pod).Name; name != fmt.Sprintf("pod-%d", i) {
+				t.Fatalf("event %d: expected pod-%d, got %s", i, i, name)
+			}
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+}
@@ -38,6 +38,15 @@ const (
 	DefaultDBMetricPollInterval = 30 * time.Second
 	DefaultHealthcheckTimeout   = 2 * time.Second
 	DefaultReadinessTimeout     = 2 * time.Second
+
+	// EndpointRoutingBalanced spreads requests across all configured servers,
+	// the client default.
+	EndpointRoutingBalanced = "balanced"
+	// EndpointRoutingNearest pins the client to the lowest-latency healthy
+	// server, falling back to all servers while it is unhealthy.
+	EndpointRoutingNearest = "nearest"
+
+	DefaultEndpointRoutingProbeInterval = 30 * time.Second
 )
 
 // TransportConfig holds all connection related info,  i.e. equal TransportConfig means equal servers we talk to.
@@ -76,6 +85,17 @@ type Config struct {
 	EncodeVersioner runtime.GroupVersioner
 	// Transformer allows the value to be transformed prior to persisting into etcd.
 	Transformer value.Transformer
+	// ListDecryptWorkers bounds how many list items are transformed from their
+	// storage format concurrently when serving a LIST request. Values below
+	// two mean sequential decryption.
+	ListDecryptWorkers int
+	// ConflictRetry bounds how often a guaranteed update that lost its etcd
+	// transaction to a concurrent writer is retried. The zero value retries
+	// immediately and without bound.
+	ConflictRetry etcd3.ConflictRetryPolicy
+	// ConflictRetryOverrides replaces ConflictRetry for specific resources,
+	// letting known-contended objects be tuned separately.
+	ConflictRetryOverrides map[schema.GroupResource]etcd3.ConflictRetryPolicy
 
 	// CompactionInterval is an interval of requesting compaction from apiserver.
 	// If the value is 0, no compaction will be issued.
@@ -84,6 +104,16 @@ type Config struct {
 	CountMetricPollPeriod time.Duration
 	// DBMetricPollInterval specifies how often should storage backend metric be updated.
 	DBMetricPollInterval time.Duration
+	// EndpointRouting selects how the client routes requests across the
+	// servers in Transport.ServerList. The empty string and
+	// EndpointRoutingBalanced keep the client default of balancing across all
+	// servers; EndpointRoutingNearest periodically probes each server and
+	// pins the client to the lowest-latency healthy one, keeping reads within
+	// the local zone of a stretched control plane.
+	EndpointRouting string
+	// EndpointRoutingProbeInterval is how often server latencies are probed
+	// when EndpointRouting is EndpointRoutingNearest.
+	EndpointRoutingProbeInterval time.Duration
 	// HealthcheckTimeout specifies the timeout used when checking health
 	HealthcheckTimeout time.Duration
 	// ReadycheckTimeout specifies the timeout used when checking readiness
@@ -116,13 +146,16 @@ func (config *Config) ForResource(resource schema.GroupResource) *ConfigForResou
 func NewDefaultConfig(prefix string, codec runtime.Codec) *Config {
 	return &Config{
 		Paging:               true,
+		ListDecryptWorkers:   etcd3.DefaultListDecryptWorkers,
 		Prefix:               prefix,
 		Codec:                codec,
 		CompactionInterval:   DefaultCompactInterval,
 		DBMetricPollInterval: DefaultDBMetricPollInterval,
 		HealthcheckTimeout:   DefaultHealthcheckTimeout,
 		ReadycheckTimeout:    DefaultReadinessTimeout,
-		LeaseManagerConfig:   etcd3.NewDefaultLeaseManagerConfig(),
-		Transport:            TransportConfig{TracerProvider: oteltrace.NewNoopTracerProvider()},
+
+		EndpointRoutingProbeInterval: DefaultEndpointRoutingProbeInterval,
+		LeaseManagerConfig:           etcd3.NewDefaultLeaseManagerConfig(),
+		Transport:                    TransportConfig{TracerProvider: oteltrace.NewNoopTracerProvider()},
 	}
 }
@@ -67,6 +67,18 @@ const (
 	dialTimeout = 20 * time.Second
 
 	dbMetricsMonitorJitter = 0.5
+
+	// routingProbeTimeout bounds a single latency probe of one endpoint when
+	// nearest-endpoint routing is enabled.
+	routingProbeTimeout = 2 * time.Second
+
+	// endpointRoutingHealthInterval is how often the pinned endpoint is
+	// health checked between full routing probes, so that a dead member
+	// does not leave the client without a working endpoint until the next
+	// probe tick.
+	endpointRoutingHealthInterval = 5 * time.Second
+
+	endpointRoutingJitter = 0.2
 )
 
 // TODO(negz): Stop using a package scoped logger. At the time of writing we're
@@ -225,6 +237,85 @@ func newETCD3Check(c storagebackend.Config, timeout time.Duration, stopCh <-chan
 	}, nil
 }
 
+func newETCD3RawLister(c storagebackend.Config, stopCh <-chan struct{}) (RawLister, error) {
+	kvLister, err := newETCD3RawKVLister(c, stopCh)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, keyPrefix string, limit int64) ([][]byte, error) {
+		records, err := kvLister(ctx, keyPrefix, limit)
+		if err != nil {
+			return nil, err
+		}
+		values := make([][]byte, 0, len(records))
+		for _, record := range records {
+			values = append(values, record.Value)
+		}
+		return values, nil
+	}, nil
+}
+
+func newETCD3RawKVLister(c storagebackend.Config, stopCh <-chan struct{}) (RawKVLister, error) {
+	// the client is constructed lazily in the background, mirroring
+	// newETCD3Check, so a slow etcd does not block server startup.
+	lock := sync.RWMutex{}
+	var client *clientv3.Client
+	clientErr := fmt.Errorf("etcd client connection not yet established")
+
+	go wait.PollUntil(time.Second, func() (bool, error) {
+		newClient, err := newETCD3Client(c.Transport)
+		lock.Lock()
+		defer lock.Unlock()
+		// Ensure that server is already not shutting down.
+		select {
+		case <-stopCh:
+			if err == nil {
+				newClient.Close()
+			}
+			return true, nil
+		default:
+		}
+		if err != nil {
+			clientErr = err
+			return false, nil
+		}
+		client = newClient
+		clientErr = nil
+		return true, nil
+	}, stopCh)
+
+	// Close the client on shutdown.
+	go func() {
+		defer utilruntime.HandleCrash()
+		<-stopCh
+
+		lock.Lock()
+		defer lock.Unlock()
+		if client != nil {
+			client.Close()
+			clientErr = fmt.Errorf("server is shutting down")
+		}
+	}()
+
+	return func(ctx context.Context, keyPrefix string, limit int64) ([]RawRecord, error) {
+		lock.RLock()
+		defer lock.RUnlock()
+
+		if clientErr != nil {
+			return nil, clientErr
+		}
+		resp, err := client.Get(ctx, keyPrefix, clientv3.WithPrefix(), clientv3.WithLimit(limit))
+		if err != nil {
+			return nil, fmt.Errorf("error listing raw values from etcd: %w", err)
+		}
+		records := make([]RawRecord, 0, len(resp.Kvs))
+		for _, kv := range resp.Kvs {
+			records = append(records, RawRecord{Key: string(kv.Key), Value: kv.Value})
+		}
+		return records, nil
+	}, nil
+}
+
 var newETCD3Client = func(c storagebackend.TransportConfig) (*clientv3.Client, error) {
 	tlsInfo := transport.TLSInfo{
 		CertFile:      c.CertFile,
@@ -382,6 +473,11 @@ func newETCD3Storage(c storagebackend.ConfigForResource, newFunc func() runtime.
 		return nil, nil, err
 	}
 
+	stopEndpointRouting, err := startEndpointRouting(client, c.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var once sync.Once
 	destroyFunc := func() {
 		// we know that storage destroy funcs are called multiple times (due to reuse in subresources).
@@ -390,6 +486,7 @@ func newETCD3Storage(c storagebackend.ConfigForResource, newFunc func() runtime.
 		once.Do(func() {
 			stopCompactor()
 			stopDBSizeMonitor()
+			stopEndpointRouting()
 			client.Close()
 		})
 	}
@@ -397,7 +494,11 @@ func newETCD3Storage(c storagebackend.ConfigForResource, newFunc func() runtime.
 	if transformer == nil {
 		transformer = identity.NewEncryptCheckTransformer()
 	}
-	return etcd3.New(client, c.Codec, newFunc, c.Prefix, c.GroupResource, transformer, c.Paging, c.LeaseManagerConfig), destroyFunc, nil
+	conflictRetry := c.ConflictRetry
+	if override, ok := c.ConflictRetryOverrides[c.GroupResource]; ok {
+		conflictRetry = override
+	}
+	return etcd3.New(client, c.Codec, newFunc, c.Prefix, c.GroupResource, transformer, c.Paging, c.ListDecryptWorkers, conflictRetry, c.LeaseManagerConfig), destroyFunc, nil
 }
 
 // startDBSizeMonitorPerEndpoint starts a loop to monitor etcd database size and update the
@@ -432,3 +533,98 @@ func startDBSizeMonitorPerEndpoint(client *clientv3.Client, interval time.Durati
 		cancel()
 	}, nil
 }
+
+// startEndpointRouting starts a loop that probes the latency of every server
+// in c.Transport.ServerList and pins the client to the fastest healthy one,
+// keeping reads within the local zone of a stretched control plane. Between
+// full probes the pinned endpoint is health checked frequently, and the
+// client immediately falls back to balancing across every endpoint when it
+// stops answering, rather than failing requests until the next probe tick.
+// The returned function stops the loop.
+func startEndpointRouting(client *clientv3.Client, c storagebackend.Config) (func(), error) {
+	switch c.EndpointRouting {
+	case storagebackend.EndpointRoutingNearest:
+	case "", storagebackend.EndpointRoutingBalanced:
+		return func() {}, nil
+	default:
+		return nil, fmt.Errorf("unknown etcd endpoint routing %q", c.EndpointRouting)
+	}
+
+	interval := c.EndpointRoutingProbeInterval
+	if interval == 0 {
+		interval = storagebackend.DefaultEndpointRoutingProbeInterval
+	}
+	allEndpoints := make([]string, len(c.Transport.ServerList))
+	copy(allEndpoints, c.Transport.ServerList)
+
+	tick := interval
+	if tick > endpointRoutingHealthInterval {
+		tick = endpointRoutingHealthInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	preferred := ""
+	var lastProbe time.Time
+	go wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if preferred != "" && time.Since(lastProbe) < interval {
+			// between full probes, only watch the pinned endpoint; losing it
+			// must not pin requests to a dead member
+			if healthyEndpoint(ctx, client, preferred) {
+				return
+			}
+			klog.V(2).Infof("Pinned etcd endpoint %s stopped answering, balancing across all of %v", preferred, allEndpoints)
+			client.SetEndpoints(allEndpoints...)
+			preferred = ""
+			return
+		}
+		lastProbe = time.Now()
+		nearest := nearestEndpoint(ctx, client, allEndpoints)
+		if nearest == preferred {
+			return
+		}
+		if nearest == "" {
+			klog.V(2).Infof("No etcd endpoint answered a routing probe, balancing across all of %v", allEndpoints)
+			client.SetEndpoints(allEndpoints...)
+		} else {
+			klog.V(2).Infof("Pinning etcd client to nearest endpoint %s", nearest)
+			client.SetEndpoints(nearest)
+		}
+		preferred = nearest
+	}, tick, endpointRoutingJitter, true)
+
+	return func() {
+		cancel()
+	}, nil
+}
+
+// healthyEndpoint reports whether the endpoint answers a status probe within
+// routingProbeTimeout.
+func healthyEndpoint(ctx context.Context, client *clientv3.Client, endpoint string) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, routingProbeTimeout)
+	defer cancel()
+	_, err := client.Maintenance.Status(probeCtx, endpoint)
+	return err == nil
+}
+
+// nearestEndpoint returns the endpoint that answers a status probe fastest,
+// or the empty string when none answers within routingProbeTimeout.
+func nearestEndpoint(ctx context.Context, client *clientv3.Client, endpoints []string) string {
+	var nearest string
+	var best time.Duration
+	for _, endpoint := range endpoints {
+		probeCtx, cancel := context.WithTimeout(ctx, routingProbeTimeout)
+		start := time.Now()
+		_, err := client.Maintenance.Status(probeCtx, endpoint)
+		latency := time.Since(start)
+		cancel()
+		if err != nil {
+			klog.V(4).Infof("Routing probe of etcd endpoint %s failed: %v", endpoint, err)
+			continue
+		}
+		if nearest == "" || latency < best {
+			nearest = endpoint
+			best = latency
+		}
+	}
+	return nearest
+}
@@ -17,10 +17,15 @@ limitations under the License.
 package factory
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/storage/etcd3/testserver"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
 )
 
 func Test_atomicLastError(t *testing.T) {
@@ -46,3 +51,65 @@ func Test_atomicLastError(t *testing.T) {
 		t.Fatalf("Expected: \"now error\" got: %s", err.Error())
 	}
 }
+
+func TestNearestEndpoint(t *testing.T) {
+	etcdConfig := testserver.NewTestConfig(t)
+	client := testserver.RunEtcd(t, etcdConfig)
+
+	healthy := client.Endpoints()[0]
+	// the unreachable endpoint must lose the probe to the healthy one
+	nearest := nearestEndpoint(context.Background(), client, []string{"http://127.0.0.1:1", healthy})
+	if nearest != healthy {
+		t.Errorf("expected nearest endpoint %s, got %q", healthy, nearest)
+	}
+
+	nearest = nearestEndpoint(context.Background(), client, []string{"http://127.0.0.1:1"})
+	if nearest != "" {
+		t.Errorf("expected no nearest endpoint among unreachable ones, got %q", nearest)
+	}
+}
+
+func TestHealthyEndpoint(t *testing.T) {
+	etcdConfig := testserver.NewTestConfig(t)
+	client := testserver.RunEtcd(t, etcdConfig)
+
+	if !healthyEndpoint(context.Background(), client, client.Endpoints()[0]) {
+		t.Error("expected the running endpoint to be healthy")
+	}
+	if healthyEndpoint(context.Background(), client, "http://127.0.0.1:1") {
+		t.Error("expected the unreachable endpoint to be unhealthy")
+	}
+}
+
+func TestStartEndpointRouting(t *testing.T) {
+	etcdConfig := testserver.NewTestConfig(t)
+	client := testserver.RunEtcd(t, etcdConfig)
+	healthy := client.Endpoints()[0]
+
+	config := storagebackend.Config{
+		Transport: storagebackend.TransportConfig{
+			ServerList: []string{"http://127.0.0.1:1", healthy},
+		},
+		EndpointRouting:              storagebackend.EndpointRoutingNearest,
+		EndpointRoutingProbeInterval: 10 * time.Millisecond,
+	}
+	stop, err := startEndpointRouting(client, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	// the routing loop pins the client to the only healthy endpoint
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		endpoints := client.Endpoints()
+		return len(endpoints) == 1 && endpoints[0] == healthy, nil
+	}); err != nil {
+		t.Errorf("client was not pinned to the healthy endpoint, has %v: %v", client.Endpoints(), err)
+	}
+}
+
+func TestStartEndpointRoutingUnknownMode(t *testing.T) {
+	if _, err := startEndpointRouting(nil, storagebackend.Config{EndpointRouting: "fastest"}); err == nil {
+		t.Error("expected an error for an unknown routing mode")
+	}
+}
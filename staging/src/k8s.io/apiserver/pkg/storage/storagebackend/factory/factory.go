@@ -17,6 +17,7 @@ limitations under the License.
 package factory
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -51,6 +52,44 @@ func CreateHealthCheck(c storagebackend.Config, stopCh <-chan struct{}) (func()
 	}
 }
 
+// RawLister lists up to limit raw values stored under keyPrefix, without
+// decoding or decrypting them.
+type RawLister func(ctx context.Context, keyPrefix string, limit int64) ([][]byte, error)
+
+// CreateRawLister creates a raw value lister based on given config.
+func CreateRawLister(c storagebackend.Config, stopCh <-chan struct{}) (RawLister, error) {
+	switch c.Type {
+	case storagebackend.StorageTypeETCD2:
+		return nil, fmt.Errorf("%s is no longer a supported storage backend", c.Type)
+	case storagebackend.StorageTypeUnset, storagebackend.StorageTypeETCD3:
+		return newETCD3RawLister(c, stopCh)
+	default:
+		return nil, fmt.Errorf("unknown storage type: %s", c.Type)
+	}
+}
+
+// RawRecord is a raw key/value pair read directly from storage.
+type RawRecord struct {
+	Key   string
+	Value []byte
+}
+
+// RawKVLister lists up to limit raw records stored under keyPrefix together
+// with their storage keys, without decoding or decrypting them.
+type RawKVLister func(ctx context.Context, keyPrefix string, limit int64) ([]RawRecord, error)
+
+// CreateRawKVLister creates a raw record lister based on given config.
+func CreateRawKVLister(c storagebackend.Config, stopCh <-chan struct{}) (RawKVLister, error) {
+	switch c.Type {
+	case storagebackend.StorageTypeETCD2:
+		return nil, fmt.Errorf("%s is no longer a supported storage backend", c.Type)
+	case storagebackend.StorageTypeUnset, storagebackend.StorageTypeETCD3:
+		return newETCD3RawKVLister(c, stopCh)
+	default:
+		return nil, fmt.Errorf("unknown storage type: %s", c.Type)
+	}
+}
+
 func CreateReadyCheck(c storagebackend.Config, stopCh <-chan struct{}) (func() error, error) {
 	switch c.Type {
 	case storagebackend.StorageTypeETCD2:
@@ -99,6 +99,60 @@ func TestGCMKeyRotation(t *testing.T) {
 	}
 }
 
+func TestCountingNonceGCM(t *testing.T) {
+	block, err := aes.NewCipher([]byte("abcdefghijklmnop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counting, err := NewCountingNonceGCMTransformer(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := NewGCMTransformer(block)
+
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte("authenticated_data"))
+
+	nonces := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		out, err := counting.TransformToStorage(ctx, []byte("firstvalue"), dataCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonce := string(out[:12])
+		if nonces[nonce] {
+			t.Fatalf("nonce %s repeated on write %d", hex.EncodeToString(out[:12]), i)
+		}
+		nonces[nonce] = true
+
+		// values written with counter nonces must decrypt through the
+		// ordinary random-nonce read path
+		from, stale, err := random.TransformFromStorage(ctx, out, dataCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stale {
+			t.Fatal("unexpected stale output")
+		}
+		if !bytes.Equal(from, []byte("firstvalue")) {
+			t.Fatalf("unexpected round trip value %q", from)
+		}
+	}
+
+	// the counting transformer decrypts values written with random nonces
+	out, err := random.TransformToStorage(ctx, []byte("firstvalue"), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, _, err := counting.TransformFromStorage(ctx, out, dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(from, []byte("firstvalue")) {
+		t.Fatalf("unexpected round trip value %q", from)
+	}
+}
+
 func TestCBCKeyRotation(t *testing.T) {
 	testErr := fmt.Errorf("test error")
 	block1, err := aes.NewCipher([]byte("abcdefghijklmnop"))
@@ -432,3 +486,44 @@ func TestRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// TestReadAllocations guards the allocation budget of the decryption path:
+// large encrypted LISTs run it once per record, so a regression here shows up
+// directly as allocation pressure in profiles.
+func TestReadAllocations(t *testing.T) {
+	block, err := aes.NewCipher(bytes.Repeat([]byte("a"), 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte("authenticated_data"))
+	v := bytes.Repeat([]byte("0123456789abcdef"), 64)
+
+	for _, tt := range []struct {
+		name        string
+		transformer value.Transformer
+		// the budget covers the plaintext buffer returned to the caller plus
+		// the fixed per-call state of the cipher mode; building the AEAD or
+		// copying the ciphertext per read costs well beyond it
+		maxAllocs float64
+	}{
+		{name: "gcm", transformer: NewGCMTransformer(block), maxAllocs: 2},
+		{name: "cbc", transformer: NewCBCTransformer(block), maxAllocs: 5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.transformer.TransformToStorage(ctx, v, dataCtx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			allocs := testing.AllocsPerRun(100, func() {
+				if _, _, err := tt.transformer.TransformFromStorage(ctx, out, dataCtx); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > tt.maxAllocs {
+				t.Errorf("expected at most %v allocations per read, got %v", tt.maxAllocs, allocs)
+			}
+		})
+	}
+}
@@ -22,13 +22,12 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
-	"encoding/base64"
 	"fmt"
 	"time"
 
 	"k8s.io/apiserver/pkg/storage/value"
 	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
-	"k8s.io/utils/lru"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/util"
 
 	"golang.org/x/crypto/cryptobyte"
 )
@@ -49,34 +48,27 @@ type Service interface {
 type envelopeTransformer struct {
 	envelopeService Service
 
-	// transformers is a thread-safe LRU cache which caches decrypted DEKs indexed by their encrypted form.
-	transformers *lru.Cache
+	// providerName is the name of the KMS provider, used for audit annotations.
+	providerName string
+
+	// cache is a thread-safe expiring LRU cache which caches decrypted DEK
+	// transformers indexed by the encrypted form of the DEK.
+	cache *util.TransformerCache
 
 	// baseTransformerFunc creates a new transformer for encrypting the data with the DEK.
 	baseTransformerFunc func(cipher.Block) value.Transformer
-
-	cacheSize    int
-	cacheEnabled bool
 }
 
 // NewEnvelopeTransformer returns a transformer which implements a KEK-DEK based envelope encryption scheme.
 // It uses envelopeService to encrypt and decrypt DEKs. Respective DEKs (in encrypted form) are prepended to
 // the data items they encrypt. A cache (of size cacheSize) is maintained to store the most recently
 // used decrypted DEKs in memory.
-func NewEnvelopeTransformer(envelopeService Service, cacheSize int, baseTransformerFunc func(cipher.Block) value.Transformer) value.Transformer {
-	var (
-		cache *lru.Cache
-	)
-
-	if cacheSize > 0 {
-		cache = lru.New(cacheSize)
-	}
+func NewEnvelopeTransformer(envelopeService Service, providerName string, cacheSize int, baseTransformerFunc func(cipher.Block) value.Transformer) value.Transformer {
 	return &envelopeTransformer{
 		envelopeService:     envelopeService,
-		transformers:        cache,
+		providerName:        providerName,
+		cache:               util.NewTransformerCache(providerName, cacheSize, util.DefaultDEKCacheTTL),
 		baseTransformerFunc: baseTransformerFunc,
-		cacheEnabled:        cacheSize > 0,
-		cacheSize:           cacheSize,
 	}
 }
 
@@ -96,9 +88,9 @@ func (t *envelopeTransformer) TransformFromStorage(ctx context.Context, data []b
 	encData := []byte(s)
 
 	// Look up the decrypted DEK from cache or Envelope.
-	transformer := t.getTransformer(encKey)
+	transformer := t.cache.Get(encKey)
 	if transformer == nil {
-		if t.cacheEnabled {
+		if t.cache.Enabled() {
 			value.RecordCacheMiss()
 		}
 		key, err := t.envelopeService.Decrypt(encKey)
@@ -108,6 +100,15 @@ func (t *envelopeTransformer) TransformFromStorage(ctx context.Context, data []b
 			// record the metric.
 			return nil, false, err
 		}
+		if err := util.LockKeyMaterial(key); err != nil {
+			return nil, false, fmt.Errorf("failed to lock DEK into memory: %w", err)
+		}
+		defer func() {
+			util.ZeroMemory(key)
+			util.UnlockKeyMaterial(key)
+		}()
+		// the v1 API does not surface a key ID for the unwrap
+		util.RecordDEKUnwrap(ctx, t.providerName, "", dataCtx.AuthenticatedData())
 
 		transformer, err = t.addTransformer(encKey, key)
 		if err != nil {
@@ -125,6 +126,13 @@ func (t *envelopeTransformer) TransformToStorage(ctx context.Context, data []byt
 	if err != nil {
 		return nil, err
 	}
+	if err := util.LockKeyMaterial(newKey); err != nil {
+		return nil, fmt.Errorf("failed to lock DEK into memory: %w", err)
+	}
+	defer func() {
+		util.ZeroMemory(newKey)
+		util.UnlockKeyMaterial(newKey)
+	}()
 
 	encKey, err := t.envelopeService.Encrypt(newKey)
 	if err != nil {
@@ -162,28 +170,10 @@ func (t *envelopeTransformer) addTransformer(encKey []byte, key []byte) (value.T
 		return nil, err
 	}
 	transformer := t.baseTransformerFunc(block)
-	// Use base64 of encKey as the key into the cache because hashicorp/golang-lru
-	// cannot hash []uint8.
-	if t.cacheEnabled {
-		t.transformers.Add(base64.StdEncoding.EncodeToString(encKey), transformer)
-		metrics.RecordDekCacheFillPercent(float64(t.transformers.Len()) / float64(t.cacheSize))
-	}
+	t.cache.Add(encKey, transformer)
 	return transformer, nil
 }
 
-// getTransformer fetches the transformer corresponding to encKey from cache, if it exists.
-func (t *envelopeTransformer) getTransformer(encKey []byte) value.Transformer {
-	if !t.cacheEnabled {
-		return nil
-	}
-
-	_transformer, found := t.transformers.Get(base64.StdEncoding.EncodeToString(encKey))
-	if found {
-		return _transformer.(value.Transformer)
-	}
-	return nil
-}
-
 // generateKey generates a random key using system randomness.
 func generateKey(length int) (key []byte, err error) {
 	defer func(start time.Time) {
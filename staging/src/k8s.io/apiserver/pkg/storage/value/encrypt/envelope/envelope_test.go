@@ -106,7 +106,7 @@ func TestEnvelopeCaching(t *testing.T) {
 	for _, tt := range testCases {
 		t.Run(tt.desc, func(t *testing.T) {
 			envelopeService := newTestEnvelopeService()
-			envelopeTransformer := NewEnvelopeTransformer(envelopeService, tt.cacheSize, aestransformer.NewCBCTransformer)
+			envelopeTransformer := NewEnvelopeTransformer(envelopeService, "panda", tt.cacheSize, aestransformer.NewCBCTransformer)
 			ctx := context.Background()
 			dataCtx := value.DefaultContext([]byte(testContextText))
 			originalText := []byte(testText)
@@ -146,7 +146,7 @@ func TestEnvelopeCaching(t *testing.T) {
 
 // Makes Envelope transformer hit cache limit, throws error if it misbehaves.
 func TestEnvelopeCacheLimit(t *testing.T) {
-	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), testEnvelopeCacheSize, aestransformer.NewCBCTransformer)
+	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), "panda", testEnvelopeCacheSize, aestransformer.NewCBCTransformer)
 	ctx := context.Background()
 	dataCtx := value.DefaultContext([]byte(testContextText))
 
@@ -179,7 +179,7 @@ func TestEnvelopeCacheLimit(t *testing.T) {
 }
 
 func BenchmarkEnvelopeCBCRead(b *testing.B) {
-	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), testEnvelopeCacheSize, aestransformer.NewCBCTransformer)
+	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), "panda", testEnvelopeCacheSize, aestransformer.NewCBCTransformer)
 	benchmarkRead(b, envelopeTransformer, 1024)
 }
 
@@ -194,7 +194,7 @@ func BenchmarkAESCBCRead(b *testing.B) {
 }
 
 func BenchmarkEnvelopeGCMRead(b *testing.B) {
-	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
 	benchmarkRead(b, envelopeTransformer, 1024)
 }
 
@@ -234,7 +234,7 @@ func benchmarkRead(b *testing.B, transformer value.Transformer, valueLength int)
 // remove after 1.13
 func TestBackwardsCompatibility(t *testing.T) {
 	envelopeService := newTestEnvelopeService()
-	envelopeTransformerInst := NewEnvelopeTransformer(envelopeService, testEnvelopeCacheSize, aestransformer.NewCBCTransformer)
+	envelopeTransformerInst := NewEnvelopeTransformer(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewCBCTransformer)
 	ctx := context.Background()
 	dataCtx := value.DefaultContext([]byte(testContextText))
 	originalText := []byte(testText)
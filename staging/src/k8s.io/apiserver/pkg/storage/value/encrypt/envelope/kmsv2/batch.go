@@ -0,0 +1,215 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmsv2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// batchingService coalesces concurrent Decrypt and Encrypt calls into batch
+// RPCs against a BatchService. Callers keep the per-object Service API; the
+// first call of a batch starts a short linger window, and every call arriving
+// before it closes, or before the batch is full, shares its round trip. The
+// concurrent DEK operations issued by the LIST decryption workers and by
+// storage migrations fill batches naturally, while an isolated call pays at
+// most the linger in extra latency.
+type batchingService struct {
+	service      BatchService
+	maxBatchSize int
+	linger       time.Duration
+
+	mu           sync.Mutex
+	decrypts     []*pendingDecrypt
+	decryptTimer *time.Timer
+	encrypts     []*pendingEncrypt
+	encryptTimer *time.Timer
+}
+
+type decryptResult struct {
+	plaintext []byte
+	err       error
+}
+
+type pendingDecrypt struct {
+	req    *DecryptRequest
+	result chan decryptResult
+}
+
+type encryptResult struct {
+	response *EncryptResponse
+	err      error
+}
+
+type pendingEncrypt struct {
+	plaintext []byte
+	result    chan encryptResult
+}
+
+// NewBatchingService wraps a BatchService so that concurrent Decrypt and
+// Encrypt calls are coalesced into batch RPCs of up to maxBatchSize
+// operations, waiting at most linger for a batch to fill. A maxBatchSize
+// below two or a non-positive linger disables coalescing.
+func NewBatchingService(service BatchService, maxBatchSize int, linger time.Duration) Service {
+	if maxBatchSize <= 1 || linger <= 0 {
+		return service
+	}
+	return &batchingService{
+		service:      service,
+		maxBatchSize: maxBatchSize,
+		linger:       linger,
+	}
+}
+
+// Decrypt a given data string to obtain the original byte data.
+func (s *batchingService) Decrypt(ctx context.Context, uid string, req *DecryptRequest) ([]byte, error) {
+	p := &pendingDecrypt{req: req, result: make(chan decryptResult, 1)}
+
+	s.mu.Lock()
+	s.decrypts = append(s.decrypts, p)
+	switch {
+	case len(s.decrypts) >= s.maxBatchSize:
+		if s.decryptTimer != nil {
+			s.decryptTimer.Stop()
+			s.decryptTimer = nil
+		}
+		batch := s.decrypts
+		s.decrypts = nil
+		s.mu.Unlock()
+		go s.flushDecrypts(batch)
+	case len(s.decrypts) == 1:
+		s.decryptTimer = time.AfterFunc(s.linger, func() {
+			s.mu.Lock()
+			s.decryptTimer = nil
+			batch := s.decrypts
+			s.decrypts = nil
+			s.mu.Unlock()
+			s.flushDecrypts(batch)
+		})
+		s.mu.Unlock()
+	default:
+		s.mu.Unlock()
+	}
+
+	select {
+	case r := <-p.result:
+		return r.plaintext, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Encrypt bytes to a string ciphertext.
+func (s *batchingService) Encrypt(ctx context.Context, uid string, data []byte) (*EncryptResponse, error) {
+	p := &pendingEncrypt{plaintext: data, result: make(chan encryptResult, 1)}
+
+	s.mu.Lock()
+	s.encrypts = append(s.encrypts, p)
+	switch {
+	case len(s.encrypts) >= s.maxBatchSize:
+		if s.encryptTimer != nil {
+			s.encryptTimer.Stop()
+			s.encryptTimer = nil
+		}
+		batch := s.encrypts
+		s.encrypts = nil
+		s.mu.Unlock()
+		go s.flushEncrypts(batch)
+	case len(s.encrypts) == 1:
+		s.encryptTimer = time.AfterFunc(s.linger, func() {
+			s.mu.Lock()
+			s.encryptTimer = nil
+			batch := s.encrypts
+			s.encrypts = nil
+			s.mu.Unlock()
+			s.flushEncrypts(batch)
+		})
+		s.mu.Unlock()
+	default:
+		s.mu.Unlock()
+	}
+
+	select {
+	case r := <-p.result:
+		return r.response, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Status returns the status of the KMS.
+func (s *batchingService) Status(ctx context.Context) (*StatusResponse, error) {
+	return s.service.Status(ctx)
+}
+
+// flushDecrypts sends one batch of decrypt operations to the plugin and fans
+// the results back out to the callers. The batch is shared by several
+// callers, so it is not tied to any one caller's context; the client's
+// per-call timeout still bounds the RPC.
+func (s *batchingService) flushDecrypts(batch []*pendingDecrypt) {
+	if len(batch) == 0 {
+		return
+	}
+	uid := string(uuid.NewUUID())
+	if len(batch) == 1 {
+		// a lone call is not worth the batch envelope
+		plaintext, err := s.service.Decrypt(context.Background(), uid, batch[0].req)
+		batch[0].result <- decryptResult{plaintext: plaintext, err: err}
+		return
+	}
+	reqs := make([]*DecryptRequest, 0, len(batch))
+	for _, p := range batch {
+		reqs = append(reqs, p.req)
+	}
+	plaintexts, err := s.service.BatchDecrypt(context.Background(), uid, reqs)
+	for i, p := range batch {
+		if err != nil {
+			p.result <- decryptResult{err: err}
+			continue
+		}
+		p.result <- decryptResult{plaintext: plaintexts[i]}
+	}
+}
+
+// flushEncrypts is the encrypt counterpart of flushDecrypts.
+func (s *batchingService) flushEncrypts(batch []*pendingEncrypt) {
+	if len(batch) == 0 {
+		return
+	}
+	uid := string(uuid.NewUUID())
+	if len(batch) == 1 {
+		// a lone call is not worth the batch envelope
+		response, err := s.service.Encrypt(context.Background(), uid, batch[0].plaintext)
+		batch[0].result <- encryptResult{response: response, err: err}
+		return
+	}
+	plaintexts := make([][]byte, 0, len(batch))
+	for _, p := range batch {
+		plaintexts = append(plaintexts, p.plaintext)
+	}
+	responses, err := s.service.BatchEncrypt(context.Background(), uid, plaintexts)
+	for i, p := range batch {
+		if err != nil {
+			p.result <- encryptResult{err: err}
+			continue
+		}
+		p.result <- encryptResult{response: responses[i]}
+	}
+}
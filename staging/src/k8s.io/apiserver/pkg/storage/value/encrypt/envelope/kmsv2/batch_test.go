@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmsv2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingBatchService echoes plaintexts and counts how many RPCs it served.
+type countingBatchService struct {
+	singleCalls int32
+	batchCalls  int32
+	// batchSizes records the size of every batch RPC.
+	mu         sync.Mutex
+	batchSizes []int
+	err        error
+}
+
+func (s *countingBatchService) Decrypt(ctx context.Context, uid string, req *DecryptRequest) ([]byte, error) {
+	atomic.AddInt32(&s.singleCalls, 1)
+	return req.Ciphertext, s.err
+}
+
+func (s *countingBatchService) Encrypt(ctx context.Context, uid string, data []byte) (*EncryptResponse, error) {
+	atomic.AddInt32(&s.singleCalls, 1)
+	return &EncryptResponse{Ciphertext: data, KeyID: "1"}, s.err
+}
+
+func (s *countingBatchService) Status(ctx context.Context) (*StatusResponse, error) {
+	return &StatusResponse{Version: KMSv3APIVersion, Healthz: "ok", KeyID: "1"}, nil
+}
+
+func (s *countingBatchService) BatchDecrypt(ctx context.Context, uid string, reqs []*DecryptRequest) ([][]byte, error) {
+	atomic.AddInt32(&s.batchCalls, 1)
+	s.mu.Lock()
+	s.batchSizes = append(s.batchSizes, len(reqs))
+	s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	plaintexts := make([][]byte, 0, len(reqs))
+	for _, req := range reqs {
+		plaintexts = append(plaintexts, req.Ciphertext)
+	}
+	return plaintexts, nil
+}
+
+func (s *countingBatchService) BatchEncrypt(ctx context.Context, uid string, plaintexts [][]byte) ([]*EncryptResponse, error) {
+	atomic.AddInt32(&s.batchCalls, 1)
+	s.mu.Lock()
+	s.batchSizes = append(s.batchSizes, len(plaintexts))
+	s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	responses := make([]*EncryptResponse, 0, len(plaintexts))
+	for _, plaintext := range plaintexts {
+		responses = append(responses, &EncryptResponse{Ciphertext: plaintext, KeyID: "1"})
+	}
+	return responses, nil
+}
+
+func TestBatchingServiceCoalescesDecrypts(t *testing.T) {
+	const callers = 16
+	plugin := &countingBatchService{}
+	// the linger is far longer than the test is willing to wait, so the batch
+	// must flush when the last caller fills it
+	service := NewBatchingService(plugin, callers, time.Hour)
+
+	results := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		i := i
+		go func() {
+			want := []byte(fmt.Sprintf("dek-%d", i))
+			got, err := service.Decrypt(context.Background(), "uid", &DecryptRequest{Ciphertext: want})
+			if err == nil && !bytes.Equal(got, want) {
+				err = fmt.Errorf("caller %d got plaintext %q, want %q", i, got, want)
+			}
+			results <- err
+		}()
+	}
+	for i := 0; i < callers; i++ {
+		if err := <-results; err != nil {
+			t.Error(err)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&plugin.batchCalls); calls != 1 {
+		t.Errorf("expected all %d concurrent decrypts to share one round trip, got %d batch RPCs", callers, calls)
+	}
+	if calls := atomic.LoadInt32(&plugin.singleCalls); calls != 0 {
+		t.Errorf("expected no per-object RPCs, got %d", calls)
+	}
+	if len(plugin.batchSizes) != 1 || plugin.batchSizes[0] != callers {
+		t.Errorf("expected one batch of %d operations, got %v", callers, plugin.batchSizes)
+	}
+}
+
+func TestBatchingServiceFullBatchDoesNotLinger(t *testing.T) {
+	plugin := &countingBatchService{}
+	// far longer than the test is willing to wait, so results must come from
+	// the full-batch flush
+	service := NewBatchingService(plugin, 2, time.Hour)
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := service.Decrypt(context.Background(), "uid", &DecryptRequest{Ciphertext: []byte("dek")})
+			results <- err
+		}()
+	}
+	timeout := time.After(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		case <-timeout:
+			t.Fatal("full batch was not flushed before the linger expired")
+		}
+	}
+}
+
+func TestBatchingServiceEncrypt(t *testing.T) {
+	plugin := &countingBatchService{}
+	service := NewBatchingService(plugin, 8, 5*time.Millisecond)
+
+	want := []byte("new-dek")
+	resp, err := service.Encrypt(context.Background(), "uid", want)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(resp.Ciphertext, want) {
+		t.Errorf("got ciphertext %q, want %q", resp.Ciphertext, want)
+	}
+}
+
+func TestBatchingServiceErrorFansOut(t *testing.T) {
+	wantErr := errors.New("key disabled")
+	plugin := &countingBatchService{err: wantErr}
+	service := NewBatchingService(plugin, 2, time.Hour)
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := service.Decrypt(context.Background(), "uid", &DecryptRequest{Ciphertext: []byte("dek")})
+			results <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-results; !errors.Is(err, wantErr) {
+			t.Errorf("expected the batch error for every caller, got %v", err)
+		}
+	}
+}
+
+func TestBatchingServiceContextCancel(t *testing.T) {
+	plugin := &countingBatchService{}
+	service := NewBatchingService(plugin, 32, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := service.Decrypt(ctx, "uid", &DecryptRequest{Ciphertext: []byte("dek")}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled while waiting for the batch, got %v", err)
+	}
+}
+
+func TestBatchingServiceDisabled(t *testing.T) {
+	plugin := &countingBatchService{}
+	if service := NewBatchingService(plugin, 1, time.Millisecond); service != BatchService(plugin) {
+		t.Error("expected a maxBatchSize below two to disable coalescing")
+	}
+	if service := NewBatchingService(plugin, 32, 0); service != BatchService(plugin) {
+		t.Error("expected a non-positive linger to disable coalescing")
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmsv2
+
+import (
+	"context"
+	"crypto/aes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+)
+
+// dekCacheAuthenticatedData binds persisted DEK cache files to their purpose,
+// so a cache file cannot be replayed as any other AES-GCM encrypted value.
+const dekCacheAuthenticatedData = "kubernetes.io/kms/dek-cache"
+
+// PersistentDEKCache persists wrapped-to-plain DEK pairs to a file so that an
+// apiserver restarted during a KMS outage can still unwrap DEKs it had
+// already seen. The file is encrypted with a cache key that never comes from
+// the KMS; the caller derives it from a key file or a TPM-sealed blob.
+type PersistentDEKCache struct {
+	path        string
+	transformer value.Transformer
+
+	mu sync.Mutex
+	// deks maps the base64 encoded encrypted DEK to the plaintext DEK.
+	deks map[string][]byte
+}
+
+// NewPersistentDEKCache opens or creates the encrypted DEK cache at path,
+// sealed with cacheKey. A cache that cannot be decrypted, for example because
+// the key rotated, is discarded rather than treated as fatal: it is only ever
+// an availability optimization.
+func NewPersistentDEKCache(path string, cacheKey []byte) (*PersistentDEKCache, error) {
+	block, err := aes.NewCipher(cacheKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct cipher for DEK cache %q: %w", path, err)
+	}
+	c := &PersistentDEKCache{
+		path:        path,
+		transformer: aestransformer.NewGCMTransformer(block),
+		deks:        map[string][]byte{},
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *PersistentDEKCache) load() error {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read DEK cache %q: %w", c.path, err)
+	}
+	plaintext, _, err := c.transformer.TransformFromStorage(context.Background(), data, value.DefaultContext([]byte(dekCacheAuthenticatedData)))
+	if err != nil {
+		// the cache key changed or the file is corrupt; start over
+		return nil
+	}
+	return json.Unmarshal(plaintext, &c.deks)
+}
+
+// Get returns the plaintext DEK persisted for the given encrypted DEK, or nil.
+func (c *PersistentDEKCache) Get(encryptedDEK []byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok := c.deks[base64.StdEncoding.EncodeToString(encryptedDEK)]
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), key...)
+}
+
+// Store persists the plaintext DEK for the given encrypted DEK. The cache
+// file is rewritten atomically so that a crash mid-write leaves the previous
+// cache intact.
+func (c *PersistentDEKCache) Store(ctx context.Context, encryptedDEK []byte, key []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cacheKey := base64.StdEncoding.EncodeToString(encryptedDEK)
+	if _, ok := c.deks[cacheKey]; ok {
+		return nil
+	}
+	c.deks[cacheKey] = append([]byte(nil), key...)
+
+	plaintext, err := json.Marshal(c.deks)
+	if err != nil {
+		return err
+	}
+	data, err := c.transformer.TransformToStorage(ctx, plaintext, value.DefaultContext([]byte(dekCacheAuthenticatedData)))
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
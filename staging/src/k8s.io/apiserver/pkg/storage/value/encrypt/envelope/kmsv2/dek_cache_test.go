@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmsv2
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+)
+
+func TestPersistentDEKCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dek-cache")
+	cacheKey := []byte("0123456789abcdef0123456789abcdef")
+	ctx := context.Background()
+
+	cache, err := NewPersistentDEKCache(path, cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Get([]byte("encrypted-dek")); got != nil {
+		t.Fatalf("expected miss from empty cache, got %v", got)
+	}
+	if err := cache.Store(ctx, []byte("encrypted-dek"), []byte("plain-dek")); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new instance over the same file, as after an apiserver restart,
+	// serves the persisted entry
+	reopened, err := NewPersistentDEKCache(path, cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Get([]byte("encrypted-dek")); !bytes.Equal(got, []byte("plain-dek")) {
+		t.Fatalf("expected persisted DEK to survive reopen, got %v", got)
+	}
+
+	// a rotated cache key discards the cache instead of failing
+	otherKey := []byte("fedcba9876543210fedcba9876543210")
+	discarded, err := NewPersistentDEKCache(path, otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := discarded.Get([]byte("encrypted-dek")); got != nil {
+		t.Fatalf("expected undecryptable cache to be discarded, got %v", got)
+	}
+}
+
+func TestTransformFromStorageWithPersistedDEKs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dek-cache")
+	cacheKey := []byte("0123456789abcdef0123456789abcdef")
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte(testContextText))
+	originalText := []byte(testText)
+
+	envelopeService := newTestEnvelopeService()
+	cache, err := NewPersistentDEKCache(path, cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the in-memory DEK cache is disabled so every read has to unwrap the DEK
+	transformer := NewEnvelopeTransformerWithPersistentCache(envelopeService, "panda", 0, aestransformer.NewGCMTransformer, DEKReusePolicy{}, cache)
+
+	transformedData, err := transformer.TransformToStorage(ctx, originalText, dataCtx)
+	if err != nil {
+		t.Fatalf("envelopeTransformer: error while transforming data to storage: %s", err)
+	}
+
+	// simulate an apiserver restart during a KMS outage: fresh transformer
+	// state, same persisted cache, unreachable plugin
+	envelopeService.SetDisabledStatus(true)
+	restarted, err := NewPersistentDEKCache(path, cacheKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transformer = NewEnvelopeTransformerWithPersistentCache(envelopeService, "panda", 0, aestransformer.NewGCMTransformer, DEKReusePolicy{}, restarted)
+
+	untransformedData, _, err := transformer.TransformFromStorage(ctx, transformedData, dataCtx)
+	if err != nil {
+		t.Fatalf("could not decrypt from persisted DEK cache during KMS outage: %v", err)
+	}
+	if !bytes.Equal(untransformedData, originalText) {
+		t.Fatalf("envelopeTransformer transformed data incorrectly. Expected: %v, got %v", originalText, untransformedData)
+	}
+
+	// without the persisted cache the same read fails
+	transformer = NewEnvelopeTransformer(envelopeService, "panda", 0, aestransformer.NewGCMTransformer)
+	if _, _, err := transformer.TransformFromStorage(ctx, transformedData, dataCtx); err == nil {
+		t.Fatal("expected read without persisted DEK cache to fail during KMS outage")
+	}
+}
@@ -22,31 +22,45 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
-	"encoding/base64"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/otel/attribute"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
 	kmstypes "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2/v2alpha1"
 	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/util"
+	"k8s.io/component-base/tracing"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/lru"
 )
 
 const (
 	// KMSAPIVersion is the version of the KMS API.
 	KMSAPIVersion = "v2alpha1"
+	// KMSv3APIVersion is the version of the KMS API served by plugins that
+	// also implement the batch RPCs.
+	KMSv3APIVersion = "v3alpha1"
+	// keyIDAuditAnnotationKey is the audit annotation recording the KMS key ID
+	// that protected an object version written to storage.
+	keyIDAuditAnnotationKey = "encryption.k8s.io/key-id"
 	// annotationsMaxSize is the maximum size of the annotations.
 	annotationsMaxSize = 32 * 1024 // 32 kB
 	// keyIDMaxSize is the maximum size of the keyID.
 	keyIDMaxSize = 1 * 1024 // 1 kB
 	// encryptedDEKMaxSize is the maximum size of the encrypted DEK.
 	encryptedDEKMaxSize = 1 * 1024 // 1 kB
+	// dekReuseMaxWritesHardCap bounds DEK reuse when only a duration is
+	// configured. Counter nonces would tolerate far more writes, but a cap
+	// keeps the number of objects exposed by any one leaked DEK bounded.
+	dekReuseMaxWritesHardCap = 1 << 20
 )
 
 // Service allows encrypting and decrypting data using an external Key Management Service.
@@ -59,17 +73,74 @@ type Service interface {
 	Status(ctx context.Context) (*StatusResponse, error)
 }
 
+// BatchService is implemented by Services whose plugin speaks the v3 KMS API,
+// which can wrap or unwrap several DEKs in one round trip.
+type BatchService interface {
+	Service
+	// BatchDecrypt obtains the original bytes of every request in one round
+	// trip. The plugin either answers all requests, in request order, or
+	// fails the whole batch.
+	BatchDecrypt(ctx context.Context, uid string, reqs []*DecryptRequest) ([][]byte, error)
+	// BatchEncrypt encrypts every plaintext in one round trip. The plugin
+	// either answers all requests, in request order, or fails the whole
+	// batch.
+	BatchEncrypt(ctx context.Context, uid string, plaintexts [][]byte) ([]*EncryptResponse, error)
+}
+
+// DEKReusePolicy bounds how often and for how long one wrapped DEK may be
+// reused across writes. The zero value disables reuse and keeps the historical
+// behavior of one KMS Encrypt round trip per write.
+type DEKReusePolicy struct {
+	// MaxWrites is the number of objects one DEK may encrypt before a fresh
+	// one is requested from the KMS. Zero or one disables write-based reuse.
+	MaxWrites int32
+	// MaxAge bounds how long one DEK may be reused regardless of how few
+	// writes it has served. Zero means writes alone bound reuse.
+	MaxAge time.Duration
+}
+
+func (p DEKReusePolicy) enabled() bool {
+	return p.MaxWrites > 1 || (p.MaxWrites == 0 && p.MaxAge > 0)
+}
+
+// reusableDEK is a DEK checked out for reuse across writes, together with the
+// wrapped form and key metadata that every object it encrypts must record.
+type reusableDEK struct {
+	transformer  value.Transformer
+	encryptedDEK []byte
+	keyID        string
+	annotations  map[string][]byte
+	// expiry is the wall-clock time after which the DEK must not encrypt
+	// further objects; zero means no age bound.
+	expiry time.Time
+	// remainingWrites counts down to zero as objects are encrypted.
+	remainingWrites int64
+}
+
 type envelopeTransformer struct {
 	envelopeService Service
 
-	// transformers is a thread-safe LRU cache which caches decrypted DEKs indexed by their encrypted form.
-	transformers *lru.Cache
+	// providerName is the name of the KMS provider, used for tracing events.
+	providerName string
+
+	// cache is a thread-safe expiring LRU cache which caches decrypted DEK
+	// transformers indexed by the encrypted form of the DEK.
+	cache *util.TransformerCache
 
 	// baseTransformerFunc creates a new transformer for encrypting the data with the DEK.
 	baseTransformerFunc func(cipher.Block) value.Transformer
 
-	cacheSize    int
-	cacheEnabled bool
+	// reusePolicy bounds reuse of the current DEK across writes. When it is
+	// enabled, currentDEK (guarded by currentDEKLock) holds the DEK most
+	// recently wrapped by the KMS until the policy retires it.
+	reusePolicy    DEKReusePolicy
+	currentDEKLock sync.Mutex
+	currentDEK     *reusableDEK
+
+	// persistentCache, when non-nil, persists unwrapped DEKs to disk so that
+	// a restart during a KMS outage can keep serving reads of previously
+	// accessed objects.
+	persistentCache *PersistentDEKCache
 }
 
 // EncryptResponse is the response from the Envelope service when encrypting data.
@@ -97,21 +168,31 @@ type StatusResponse struct {
 // It uses envelopeService to encrypt and decrypt DEKs. Respective DEKs (in encrypted form) are prepended to
 // the data items they encrypt. A cache (of size cacheSize) is maintained to store the most recently
 // used decrypted DEKs in memory.
-func NewEnvelopeTransformer(envelopeService Service, cacheSize int, baseTransformerFunc func(cipher.Block) value.Transformer) value.Transformer {
-	var cache *lru.Cache
+func NewEnvelopeTransformer(envelopeService Service, providerName string, cacheSize int, baseTransformerFunc func(cipher.Block) value.Transformer) value.Transformer {
+	return NewEnvelopeTransformerWithReusePolicy(envelopeService, providerName, cacheSize, baseTransformerFunc, DEKReusePolicy{})
+}
 
-	if cacheSize > 0 {
-		// TODO(aramase): Switch to using expiring cache: kubernetes/kubernetes/staging/src/k8s.io/apimachinery/pkg/util/cache/expiring.go.
-		// It handles scans a lot better, doesn't have to be right sized, and don't have a global lock on reads.
-		cache = lru.New(cacheSize)
-	}
+// NewEnvelopeTransformerWithReusePolicy is NewEnvelopeTransformer with a DEK
+// reuse policy. While the current DEK is inside its reuse window, writes skip
+// the KMS Encrypt round trip and encrypt with collision-safe counter nonces,
+// so reuse stays well clear of the random-nonce birthday bound.
+func NewEnvelopeTransformerWithReusePolicy(envelopeService Service, providerName string, cacheSize int, baseTransformerFunc func(cipher.Block) value.Transformer, reusePolicy DEKReusePolicy) value.Transformer {
+	return NewEnvelopeTransformerWithPersistentCache(envelopeService, providerName, cacheSize, baseTransformerFunc, reusePolicy, nil)
+}
 
+// NewEnvelopeTransformerWithPersistentCache is NewEnvelopeTransformerWithReusePolicy
+// with an optional on-disk DEK cache. Every DEK that is generated or unwrapped
+// is persisted through it, and a DEK whose unwrap fails is looked up in it, so
+// reads of previously accessed objects survive an apiserver restart during a
+// KMS outage. Pass nil to keep DEKs in memory only.
+func NewEnvelopeTransformerWithPersistentCache(envelopeService Service, providerName string, cacheSize int, baseTransformerFunc func(cipher.Block) value.Transformer, reusePolicy DEKReusePolicy, persistentCache *PersistentDEKCache) value.Transformer {
 	return &envelopeTransformer{
 		envelopeService:     envelopeService,
-		transformers:        cache,
+		providerName:        providerName,
+		cache:               util.NewTransformerCache(providerName, cacheSize, util.DefaultDEKCacheTTL),
 		baseTransformerFunc: baseTransformerFunc,
-		cacheEnabled:        cacheSize > 0,
-		cacheSize:           cacheSize,
+		reusePolicy:         reusePolicy,
+		persistentCache:     persistentCache,
 	}
 }
 
@@ -126,9 +207,15 @@ func (t *envelopeTransformer) TransformFromStorage(ctx context.Context, data []b
 	}
 
 	// Look up the decrypted DEK from cache or Envelope.
-	transformer := t.getTransformer(encryptedObject.EncryptedDEK)
+	transformer := t.cache.Get(encryptedObject.EncryptedDEK)
+	if t.cache.Enabled() {
+		tracing.SpanFromContext(ctx).AddEvent("DEK cache lookup",
+			attribute.String("provider", t.providerName),
+			attribute.Bool("cache_hit", transformer != nil),
+		)
+	}
 	if transformer == nil {
-		if t.cacheEnabled {
+		if t.cache.Enabled() {
 			value.RecordCacheMiss()
 		}
 		uid := string(uuid.NewUUID())
@@ -139,8 +226,23 @@ func (t *envelopeTransformer) TransformFromStorage(ctx context.Context, data []b
 			Annotations: encryptedObject.Annotations,
 		})
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to decrypt DEK, error: %w", err)
+			if persisted := t.persistedDEK(encryptedObject.EncryptedDEK); persisted != nil {
+				klog.V(2).InfoS("Serving read from persisted DEK cache after failed unwrap", "provider", t.providerName, "uid", uid, "err", err)
+				key = persisted
+			} else {
+				return nil, false, fmt.Errorf("failed to decrypt DEK, error: %w", err)
+			}
+		} else {
+			t.persistDEK(ctx, encryptedObject.EncryptedDEK, key)
+		}
+		if err := util.LockKeyMaterial(key); err != nil {
+			return nil, false, fmt.Errorf("failed to lock DEK into memory: %w", err)
 		}
+		defer func() {
+			util.ZeroMemory(key)
+			util.UnlockKeyMaterial(key)
+		}()
+		util.RecordDEKUnwrap(ctx, t.providerName, encryptedObject.KeyID, dataCtx.AuthenticatedData())
 
 		transformer, err = t.addTransformer(encryptedObject.EncryptedDEK, key)
 		if err != nil {
@@ -154,10 +256,36 @@ func (t *envelopeTransformer) TransformFromStorage(ctx context.Context, data []b
 // TransformToStorage encrypts data to be written to disk using envelope encryption.
 func (t *envelopeTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
 	metrics.RecordArrival(metrics.ToStorageLabel, time.Now())
+
+	if dek := t.checkoutCurrentDEK(); dek != nil {
+		result, err := dek.transformer.TransformToStorage(ctx, data, dataCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		encObject := &kmstypes.EncryptedObject{
+			KeyID:         dek.keyID,
+			EncryptedDEK:  dek.encryptedDEK,
+			EncryptedData: result,
+			Annotations:   dek.annotations,
+		}
+
+		audit.AddAuditAnnotation(ctx, keyIDAuditAnnotationKey, dek.keyID)
+
+		return t.doEncode(encObject)
+	}
+
 	newKey, err := generateKey(32)
 	if err != nil {
 		return nil, err
 	}
+	if err := util.LockKeyMaterial(newKey); err != nil {
+		return nil, fmt.Errorf("failed to lock DEK into memory: %w", err)
+	}
+	defer func() {
+		util.ZeroMemory(newKey)
+		util.UnlockKeyMaterial(newKey)
+	}()
 
 	uid := string(uuid.NewUUID())
 	klog.V(6).InfoS("Encrypting content using envelope service", "uid", uid, "key", string(dataCtx.AuthenticatedData()))
@@ -165,8 +293,15 @@ func (t *envelopeTransformer) TransformToStorage(ctx context.Context, data []byt
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt DEK, error: %w", err)
 	}
-
-	transformer, err := t.addTransformer(resp.Ciphertext, newKey)
+	t.persistDEK(ctx, resp.Ciphertext, newKey)
+
+	var transformer value.Transformer
+	if t.reusePolicy.enabled() {
+		// the install consumes the first write from the new DEK's budget
+		transformer, err = t.installReusableDEK(newKey, resp)
+	} else {
+		transformer, err = t.addTransformer(resp.Ciphertext, newKey)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -183,37 +318,99 @@ func (t *envelopeTransformer) TransformToStorage(ctx context.Context, data []byt
 		Annotations:   resp.Annotations,
 	}
 
+	audit.AddAuditAnnotation(ctx, keyIDAuditAnnotationKey, resp.KeyID)
+
 	// Serialize the EncryptedObject to a byte array.
 	return t.doEncode(encObject)
 }
 
-// addTransformer inserts a new transformer to the Envelope cache of DEKs for future reads.
-func (t *envelopeTransformer) addTransformer(encKey []byte, key []byte) (value.Transformer, error) {
+// checkoutCurrentDEK consumes one write from the current DEK's reuse budget
+// and returns the DEK, or nil when reuse is disabled or the policy has
+// retired the DEK and a fresh one must be wrapped by the KMS.
+func (t *envelopeTransformer) checkoutCurrentDEK() *reusableDEK {
+	if !t.reusePolicy.enabled() {
+		return nil
+	}
+
+	t.currentDEKLock.Lock()
+	defer t.currentDEKLock.Unlock()
+	dek := t.currentDEK
+	if dek == nil {
+		return nil
+	}
+	if dek.remainingWrites <= 0 || (!dek.expiry.IsZero() && time.Now().After(dek.expiry)) {
+		t.currentDEK = nil
+		return nil
+	}
+	dek.remainingWrites--
+	return dek
+}
+
+// installReusableDEK makes the freshly wrapped DEK the current DEK for
+// subsequent writes and returns its transformer, which uses counter nonces so
+// that no number of writes inside the reuse window can repeat a nonce. The
+// transformer is also added to the read cache.
+func (t *envelopeTransformer) installReusableDEK(key []byte, resp *EncryptResponse) (value.Transformer, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
-	transformer := t.baseTransformerFunc(block)
-	// Use base64 of encKey as the key into the cache because hashicorp/golang-lru
-	// cannot hash []uint8.
-	if t.cacheEnabled {
-		t.transformers.Add(base64.StdEncoding.EncodeToString(encKey), transformer)
-		metrics.RecordDekCacheFillPercent(float64(t.transformers.Len()) / float64(t.cacheSize))
+	transformer, err := aestransformer.NewCountingNonceGCMTransformer(block)
+	if err != nil {
+		return nil, err
+	}
+	t.cache.Add(resp.Ciphertext, transformer)
+
+	dek := &reusableDEK{
+		transformer:     transformer,
+		encryptedDEK:    resp.Ciphertext,
+		keyID:           resp.KeyID,
+		annotations:     resp.Annotations,
+		remainingWrites: dekReuseMaxWritesHardCap - 1,
+	}
+	if t.reusePolicy.MaxWrites > 1 {
+		dek.remainingWrites = int64(t.reusePolicy.MaxWrites) - 1
+	}
+	if t.reusePolicy.MaxAge > 0 {
+		dek.expiry = time.Now().Add(t.reusePolicy.MaxAge)
 	}
+
+	t.currentDEKLock.Lock()
+	t.currentDEK = dek
+	t.currentDEKLock.Unlock()
 	return transformer, nil
 }
 
-// getTransformer fetches the transformer corresponding to encKey from cache, if it exists.
-func (t *envelopeTransformer) getTransformer(encKey []byte) value.Transformer {
-	if !t.cacheEnabled {
+// persistDEK stores an unwrapped DEK in the persistent cache, if one is
+// configured. Persistence is best effort; a failure must not fail the request
+// it piggybacks on.
+func (t *envelopeTransformer) persistDEK(ctx context.Context, encryptedDEK []byte, key []byte) {
+	if t.persistentCache == nil {
+		return
+	}
+	if err := t.persistentCache.Store(ctx, encryptedDEK, key); err != nil {
+		klog.ErrorS(err, "Failed to persist DEK to cache", "provider", t.providerName)
+	}
+}
+
+// persistedDEK fetches an unwrapped DEK from the persistent cache, if one is
+// configured.
+func (t *envelopeTransformer) persistedDEK(encryptedDEK []byte) []byte {
+	if t.persistentCache == nil {
 		return nil
 	}
+	return t.persistentCache.Get(encryptedDEK)
+}
 
-	_transformer, found := t.transformers.Get(base64.StdEncoding.EncodeToString(encKey))
-	if found {
-		return _transformer.(value.Transformer)
+// addTransformer inserts a new transformer to the Envelope cache of DEKs for future reads.
+func (t *envelopeTransformer) addTransformer(encKey []byte, key []byte) (value.Transformer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	transformer := t.baseTransformerFunc(block)
+	t.cache.Add(encKey, transformer)
+	return transformer, nil
 }
 
 // doEncode encodes the EncryptedObject to a byte array.
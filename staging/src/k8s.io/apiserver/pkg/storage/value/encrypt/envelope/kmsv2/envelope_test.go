@@ -26,10 +26,14 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/storage/value"
 	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
 	kmstypes "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2/v2alpha1"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/util"
 )
 
 const (
@@ -132,7 +136,7 @@ func TestEnvelopeCaching(t *testing.T) {
 	for _, tt := range testCases {
 		t.Run(tt.desc, func(t *testing.T) {
 			envelopeService := newTestEnvelopeService()
-			envelopeTransformer := NewEnvelopeTransformer(envelopeService, tt.cacheSize, aestransformer.NewGCMTransformer)
+			envelopeTransformer := NewEnvelopeTransformer(envelopeService, "panda", tt.cacheSize, aestransformer.NewGCMTransformer)
 			ctx := context.Background()
 			dataCtx := value.DefaultContext([]byte(testContextText))
 			originalText := []byte(testText)
@@ -172,7 +176,7 @@ func TestEnvelopeCaching(t *testing.T) {
 
 // Makes Envelope transformer hit cache limit, throws error if it misbehaves.
 func TestEnvelopeCacheLimit(t *testing.T) {
-	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+	envelopeTransformer := NewEnvelopeTransformer(newTestEnvelopeService(), "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
 	ctx := context.Background()
 	dataCtx := value.DefaultContext([]byte(testContextText))
 
@@ -204,6 +208,121 @@ func TestEnvelopeCacheLimit(t *testing.T) {
 	}
 }
 
+// countingEnvelopeService counts Encrypt round trips to the mock service.
+type countingEnvelopeService struct {
+	*testEnvelopeService
+	encryptCalls int
+}
+
+func (c *countingEnvelopeService) Encrypt(ctx context.Context, uid string, data []byte) (*EncryptResponse, error) {
+	c.encryptCalls++
+	return c.testEnvelopeService.Encrypt(ctx, uid, data)
+}
+
+func TestEnvelopeDEKReuse(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		reusePolicy          DEKReusePolicy
+		writes               int
+		expectedEncryptCalls int
+	}{
+		{
+			desc:                 "zero policy wraps a fresh DEK per write",
+			reusePolicy:          DEKReusePolicy{},
+			writes:               4,
+			expectedEncryptCalls: 4,
+		},
+		{
+			desc:                 "maxWrites of one wraps a fresh DEK per write",
+			reusePolicy:          DEKReusePolicy{MaxWrites: 1},
+			writes:               4,
+			expectedEncryptCalls: 4,
+		},
+		{
+			desc:                 "maxWrites of three wraps every third write",
+			reusePolicy:          DEKReusePolicy{MaxWrites: 3},
+			writes:               7,
+			expectedEncryptCalls: 3,
+		},
+		{
+			desc:                 "duration alone allows reuse up to the hard cap",
+			reusePolicy:          DEKReusePolicy{MaxAge: time.Hour},
+			writes:               7,
+			expectedEncryptCalls: 1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			envelopeService := &countingEnvelopeService{testEnvelopeService: newTestEnvelopeService()}
+			envelopeTransformer := NewEnvelopeTransformerWithReusePolicy(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer, tt.reusePolicy)
+			ctx := context.Background()
+			dataCtx := value.DefaultContext([]byte(testContextText))
+			originalText := []byte(testText)
+
+			transformedDatas := make([][]byte, tt.writes)
+			for i := 0; i < tt.writes; i++ {
+				transformedData, err := envelopeTransformer.TransformToStorage(ctx, originalText, dataCtx)
+				if err != nil {
+					t.Fatalf("envelopeTransformer: error while transforming data to storage: %s", err)
+				}
+				transformedDatas[i] = transformedData
+			}
+			if envelopeService.encryptCalls != tt.expectedEncryptCalls {
+				t.Fatalf("expected %d Encrypt calls for %d writes, got %d", tt.expectedEncryptCalls, tt.writes, envelopeService.encryptCalls)
+			}
+
+			for i, transformedData := range transformedDatas {
+				untransformedData, _, err := envelopeTransformer.TransformFromStorage(ctx, transformedData, dataCtx)
+				if err != nil {
+					t.Fatalf("could not decrypt write %d: %v", i, err)
+				}
+				if !bytes.Equal(untransformedData, originalText) {
+					t.Fatalf("envelopeTransformer transformed data incorrectly. Expected: %v, got %v", originalText, untransformedData)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvelopeDEKReuseExpiry(t *testing.T) {
+	envelopeService := &countingEnvelopeService{testEnvelopeService: newTestEnvelopeService()}
+	transformer := NewEnvelopeTransformerWithReusePolicy(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer, DEKReusePolicy{MaxWrites: 100, MaxAge: time.Hour})
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte(testContextText))
+	originalText := []byte(testText)
+
+	for i := 0; i < 3; i++ {
+		if _, err := transformer.TransformToStorage(ctx, originalText, dataCtx); err != nil {
+			t.Fatalf("envelopeTransformer: error while transforming data to storage: %s", err)
+		}
+	}
+	if envelopeService.encryptCalls != 1 {
+		t.Fatalf("expected 1 Encrypt call before expiry, got %d", envelopeService.encryptCalls)
+	}
+
+	// age out the current DEK
+	envelopeTransformer := transformer.(*envelopeTransformer)
+	envelopeTransformer.currentDEKLock.Lock()
+	envelopeTransformer.currentDEK.expiry = time.Now().Add(-time.Second)
+	envelopeTransformer.currentDEKLock.Unlock()
+
+	transformedData, err := transformer.TransformToStorage(ctx, originalText, dataCtx)
+	if err != nil {
+		t.Fatalf("envelopeTransformer: error while transforming data to storage: %s", err)
+	}
+	if envelopeService.encryptCalls != 2 {
+		t.Fatalf("expected an expired DEK to force a second Encrypt call, got %d", envelopeService.encryptCalls)
+	}
+	untransformedData, _, err := transformer.TransformFromStorage(ctx, transformedData, dataCtx)
+	if err != nil {
+		t.Fatalf("could not decrypt data written after expiry: %v", err)
+	}
+	if !bytes.Equal(untransformedData, originalText) {
+		t.Fatalf("envelopeTransformer transformed data incorrectly. Expected: %v, got %v", originalText, untransformedData)
+	}
+}
+
 func TestTransformToStorageError(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -237,7 +356,7 @@ func TestTransformToStorageError(t *testing.T) {
 			t.Parallel()
 			envelopeService := newTestEnvelopeService()
 			envelopeService.SetAnnotations(tt.annotations)
-			envelopeTransformer := NewEnvelopeTransformer(envelopeService, 0, aestransformer.NewGCMTransformer)
+			envelopeTransformer := NewEnvelopeTransformer(envelopeService, "panda", 0, aestransformer.NewGCMTransformer)
 			ctx := context.Background()
 			dataCtx := value.DefaultContext([]byte(testContextText))
 
@@ -510,3 +629,67 @@ func TestValidateEncryptedDEK(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformToStorageKeyIDAuditAnnotation(t *testing.T) {
+	envelopeService := newTestEnvelopeService()
+	envelopeTransformer := NewEnvelopeTransformer(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+
+	ctx := audit.WithAuditContext(context.Background())
+	ac := audit.AuditContextFrom(ctx)
+	ac.Event = &auditinternal.Event{Level: auditinternal.LevelMetadata}
+
+	dataCtx := value.DefaultContext([]byte(testContextText))
+	if _, err := envelopeTransformer.TransformToStorage(ctx, []byte(testText), dataCtx); err != nil {
+		t.Fatal(err)
+	}
+	if got := ac.Event.Annotations["encryption.k8s.io/key-id"]; got != envelopeService.keyVersion {
+		t.Errorf("expected key ID audit annotation %q, got %q", envelopeService.keyVersion, got)
+	}
+}
+
+func TestTransformFromStorageDEKUnwrapAuditAnnotations(t *testing.T) {
+	util.SetAuditDEKUnwrap(true)
+	defer util.SetAuditDEKUnwrap(false)
+
+	envelopeService := newTestEnvelopeService()
+	writeTransformer := NewEnvelopeTransformer(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+
+	dataCtx := value.DefaultContext([]byte(testContextText))
+	data, err := writeTransformer.TransformToStorage(context.Background(), []byte(testText), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a fresh transformer has an empty DEK cache, so the read must unwrap
+	readTransformer := NewEnvelopeTransformer(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+	ctx := audit.WithAuditContext(context.Background())
+	ac := audit.AuditContextFrom(ctx)
+	ac.Event = &auditinternal.Event{Level: auditinternal.LevelMetadata}
+
+	if _, _, err := readTransformer.TransformFromStorage(ctx, data, dataCtx); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"encryption.k8s.io/decrypt-provider": "panda",
+		"encryption.k8s.io/decrypt-key-id":   envelopeService.keyVersion,
+		"encryption.k8s.io/decrypt-resource": testContextText,
+	} {
+		if got := ac.Event.Annotations[key]; got != want {
+			t.Errorf("expected audit annotation %q to be %q, got %q", key, want, got)
+		}
+	}
+
+	// unwraps are not recorded while the option is disabled
+	util.SetAuditDEKUnwrap(false)
+	readTransformer = NewEnvelopeTransformer(envelopeService, "panda", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+	ctx = audit.WithAuditContext(context.Background())
+	ac = audit.AuditContextFrom(ctx)
+	ac.Event = &auditinternal.Event{Level: auditinternal.LevelMetadata}
+
+	if _, _, err := readTransformer.TransformFromStorage(ctx, data, dataCtx); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := ac.Event.Annotations["encryption.k8s.io/decrypt-provider"]; ok {
+		t.Errorf("expected no decrypt audit annotations while disabled, got provider %q", got)
+	}
+}
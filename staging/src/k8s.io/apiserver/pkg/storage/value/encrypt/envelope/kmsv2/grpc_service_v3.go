@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kmsv2 transforms values for storage at rest using a Envelope provider
+package kmsv2
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/util"
+	"k8s.io/klog/v2"
+	kmsapi "k8s.io/kms/apis/v3alpha1"
+)
+
+// The gRPC implementation for BatchService, speaking the v3 KMS API.
+type gRPCServiceV3 struct {
+	kmsClient   kmsapi.KeyManagementServiceClient
+	connection  *grpc.ClientConn
+	callTimeout time.Duration
+}
+
+var _ BatchService = &gRPCServiceV3{}
+
+// NewGRPCServiceV3 returns a BatchService which uses gRPC to communicate with
+// a remote KMS provider speaking the v3 KMS API.
+func NewGRPCServiceV3(ctx context.Context, endpoint string, callTimeout time.Duration) (BatchService, error) {
+	klog.V(4).Infof("Configure KMS provider with endpoint: %s", endpoint)
+
+	addr, err := util.ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &gRPCServiceV3{callTimeout: callTimeout}
+	s.connection, err = grpc.Dial(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+		grpc.WithContextDialer(
+			func(context.Context, string) (net.Conn, error) {
+				// Ignoring addr and timeout arguments:
+				// addr - comes from the closure
+				c, err := net.DialUnix(unixProtocol, nil, &net.UnixAddr{Name: addr})
+				if err != nil {
+					klog.Errorf("failed to create connection to unix socket: %s, error: %v", addr, err)
+				} else {
+					klog.V(4).Infof("Successfully dialed Unix socket %v", addr)
+				}
+				return c, err
+			}))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection to %s, error: %v", endpoint, err)
+	}
+
+	s.kmsClient = kmsapi.NewKeyManagementServiceClient(s.connection)
+
+	go func() {
+		defer utilruntime.HandleCrash()
+
+		<-ctx.Done()
+		_ = s.connection.Close()
+	}()
+
+	return s, nil
+}
+
+// Decrypt a given data string to obtain the original byte data.
+func (g *gRPCServiceV3) Decrypt(ctx context.Context, uid string, req *DecryptRequest) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.callTimeout)
+	defer cancel()
+
+	request := &kmsapi.DecryptRequest{
+		Ciphertext:  req.Ciphertext,
+		Uid:         uid,
+		KeyId:       req.KeyID,
+		Annotations: req.Annotations,
+	}
+	response, err := g.kmsClient.Decrypt(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
+
+// Encrypt bytes to a string ciphertext.
+func (g *gRPCServiceV3) Encrypt(ctx context.Context, uid string, plaintext []byte) (*EncryptResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.callTimeout)
+	defer cancel()
+
+	request := &kmsapi.EncryptRequest{
+		Plaintext: plaintext,
+		Uid:       uid,
+	}
+	response, err := g.kmsClient.Encrypt(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptResponse{
+		Ciphertext:  response.Ciphertext,
+		KeyID:       response.KeyId,
+		Annotations: response.Annotations,
+	}, nil
+}
+
+// BatchDecrypt obtains the original bytes of every request in one round trip.
+func (g *gRPCServiceV3) BatchDecrypt(ctx context.Context, uid string, reqs []*DecryptRequest) ([][]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.callTimeout)
+	defer cancel()
+
+	request := &kmsapi.BatchDecryptRequest{Requests: make([]*kmsapi.DecryptRequest, 0, len(reqs))}
+	for _, req := range reqs {
+		request.Requests = append(request.Requests, &kmsapi.DecryptRequest{
+			Ciphertext:  req.Ciphertext,
+			Uid:         uid,
+			KeyId:       req.KeyID,
+			Annotations: req.Annotations,
+		})
+	}
+	response, err := g.kmsClient.BatchDecrypt(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Responses) != len(reqs) {
+		return nil, fmt.Errorf("expected %d responses to a batch decrypt of %d requests, got %d", len(reqs), len(reqs), len(response.Responses))
+	}
+	plaintexts := make([][]byte, 0, len(response.Responses))
+	for _, resp := range response.Responses {
+		plaintexts = append(plaintexts, resp.Plaintext)
+	}
+	return plaintexts, nil
+}
+
+// BatchEncrypt encrypts every plaintext in one round trip.
+func (g *gRPCServiceV3) BatchEncrypt(ctx context.Context, uid string, plaintexts [][]byte) ([]*EncryptResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.callTimeout)
+	defer cancel()
+
+	request := &kmsapi.BatchEncryptRequest{Requests: make([]*kmsapi.EncryptRequest, 0, len(plaintexts))}
+	for _, plaintext := range plaintexts {
+		request.Requests = append(request.Requests, &kmsapi.EncryptRequest{
+			Plaintext: plaintext,
+			Uid:       uid,
+		})
+	}
+	response, err := g.kmsClient.BatchEncrypt(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Responses) != len(plaintexts) {
+		return nil, fmt.Errorf("expected %d responses to a batch encrypt of %d requests, got %d", len(plaintexts), len(plaintexts), len(response.Responses))
+	}
+	responses := make([]*EncryptResponse, 0, len(response.Responses))
+	for _, resp := range response.Responses {
+		responses = append(responses, &EncryptResponse{
+			Ciphertext:  resp.Ciphertext,
+			KeyID:       resp.KeyId,
+			Annotations: resp.Annotations,
+		})
+	}
+	return responses, nil
+}
+
+// Status returns the status of the KMSv3 provider.
+func (g *gRPCServiceV3) Status(ctx context.Context) (*StatusResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.callTimeout)
+	defer cancel()
+
+	request := &kmsapi.StatusRequest{}
+	response, err := g.kmsClient.Status(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusResponse{Version: response.Version, Healthz: response.Healthz, KeyID: response.KeyId}, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kmsv2 transforms values for storage at rest using a Envelope v2 provider
+package kmsv2
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	mock "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/testing/v3alpha1"
+)
+
+func destroyServiceV3(service BatchService) {
+	if service != nil {
+		s := service.(*gRPCServiceV3)
+		s.connection.Close()
+	}
+}
+
+// TestGRPCServiceV3RoundTrip tests the single-operation RPCs of the v3 client
+// against a mock plugin.
+func TestGRPCServiceV3RoundTrip(t *testing.T) {
+	t.Parallel()
+	s := newEndpoint()
+
+	f, err := mock.NewBase64Plugin(s.path)
+	if err != nil {
+		t.Fatalf("failed to construct test KMS provider server, error: %v", err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatalf("Failed to start kms-plugin, err: %v", err)
+	}
+	defer f.CleanUp()
+	if err := mock.WaitForBase64PluginToBeUp(f); err != nil {
+		t.Fatalf("Failed to start kms-plugin, err: %v", err)
+	}
+
+	ctx := testContext(t)
+	service, err := NewGRPCServiceV3(ctx, s.endpoint, 1*time.Second)
+	if err != nil {
+		t.Fatalf("failed to create envelope service, error: %v", err)
+	}
+	defer destroyServiceV3(service)
+
+	data := []byte("test data")
+	uid := string(uuid.NewUUID())
+	resp, err := service.Encrypt(ctx, uid, data)
+	if err != nil {
+		t.Fatalf("failed when execute encrypt, error: %v", err)
+	}
+	plaintext, err := service.Decrypt(ctx, uid, &DecryptRequest{Ciphertext: resp.Ciphertext, KeyID: resp.KeyID, Annotations: resp.Annotations})
+	if err != nil {
+		t.Fatalf("failed when execute decrypt, error: %v", err)
+	}
+	if !bytes.Equal(plaintext, data) {
+		t.Errorf("got plaintext %q, want %q", plaintext, data)
+	}
+
+	status, err := service.Status(ctx)
+	if err != nil {
+		t.Fatalf("failed when execute status, error: %v", err)
+	}
+	if status.Version != KMSv3APIVersion {
+		t.Errorf("got version %q, want %q", status.Version, KMSv3APIVersion)
+	}
+}
+
+// TestGRPCServiceV3Batch tests that a whole batch of DEKs round-trips through
+// one BatchEncrypt and one BatchDecrypt RPC.
+func TestGRPCServiceV3Batch(t *testing.T) {
+	t.Parallel()
+	s := newEndpoint()
+
+	f, err := mock.NewBase64Plugin(s.path)
+	if err != nil {
+		t.Fatalf("failed to construct test KMS provider server, error: %v", err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatalf("Failed to start kms-plugin, err: %v", err)
+	}
+	defer f.CleanUp()
+	if err := mock.WaitForBase64PluginToBeUp(f); err != nil {
+		t.Fatalf("Failed to start kms-plugin, err: %v", err)
+	}
+
+	ctx := testContext(t)
+	service, err := NewGRPCServiceV3(ctx, s.endpoint, 1*time.Second)
+	if err != nil {
+		t.Fatalf("failed to create envelope service, error: %v", err)
+	}
+	defer destroyServiceV3(service)
+
+	plaintexts := make([][]byte, 8)
+	for i := range plaintexts {
+		plaintexts[i] = []byte(fmt.Sprintf("dek-%d", i))
+	}
+	uid := string(uuid.NewUUID())
+	responses, err := service.BatchEncrypt(ctx, uid, plaintexts)
+	if err != nil {
+		t.Fatalf("failed when execute batch encrypt, error: %v", err)
+	}
+	if len(responses) != len(plaintexts) {
+		t.Fatalf("got %d responses for %d plaintexts", len(responses), len(plaintexts))
+	}
+
+	reqs := make([]*DecryptRequest, 0, len(responses))
+	for _, resp := range responses {
+		reqs = append(reqs, &DecryptRequest{Ciphertext: resp.Ciphertext, KeyID: resp.KeyID, Annotations: resp.Annotations})
+	}
+	decrypted, err := service.BatchDecrypt(ctx, uid, reqs)
+	if err != nil {
+		t.Fatalf("failed when execute batch decrypt, error: %v", err)
+	}
+	for i := range plaintexts {
+		if !bytes.Equal(decrypted[i], plaintexts[i]) {
+			t.Errorf("request %d: got plaintext %q, want %q", i, decrypted[i], plaintexts[i])
+		}
+	}
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package metrics
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"google.golang.org/grpc/status"
+
 	"k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
 )
@@ -67,17 +70,207 @@ var (
 		},
 		[]string{"transformation_type"},
 	)
+
+	dekCacheEntries = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "dek_cache_entries",
+			Help:           "Number of DEKs currently cached, broken down by provider name.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"provider_name"},
+	)
+
+	dekCacheRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "dek_cache_requests_total",
+			Help:           "Total number of DEK cache lookups, broken down by provider name and whether the DEK was found.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"provider_name", "result"},
+	)
+
+	dekCacheEvictions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "dek_cache_evictions_total",
+			Help:           "Total number of DEKs evicted because the cache was full, broken down by provider name.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"provider_name"},
+	)
+
+	kmsOperationsLatencies = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "kms_operations_latency_seconds",
+			Help:           "KMS operation duration in seconds, broken down by provider name and method name.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        metrics.ExponentialBuckets(0.0001, 2, 20),
+		},
+		[]string{"provider_name", "method_name"},
+	)
+
+	kmsOperationsErrors = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "kms_operations_errors_total",
+			Help:           "Total number of failed KMS operations, broken down by provider name, method name and gRPC status code.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"provider_name", "method_name", "grpc_status_code"},
+	)
+
+	kmsRPCQueueLength = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "kms_rpc_queue_length",
+			Help:           "Number of Encrypt and Decrypt calls currently waiting for an in-flight slot of the KMS plugin, broken down by provider name.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"provider_name"},
+	)
+
+	kmsRPCWaitLatencies = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "kms_rpc_wait_duration_seconds",
+			Help:           "Time Encrypt and Decrypt calls spent waiting for an in-flight slot of the KMS plugin, broken down by provider name.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        metrics.ExponentialBuckets(0.0001, 2, 20),
+		},
+		[]string{"provider_name"},
+	)
+
+	kmsLastSuccessAgeDesc = metrics.NewDesc(
+		metrics.BuildFQName(namespace, subsystem, "kms_operations_time_since_last_success_seconds"),
+		"Seconds since the last successful KMS operation, broken down by provider name and method name. Alerts on this catch a silently wedged plugin before the next healthz probe fails.",
+		[]string{"provider_name", "method_name"}, nil,
+		metrics.ALPHA, "",
+	)
 )
 
+// now is overridden in tests to make the age gauge deterministic.
+var now = time.Now
+
+// kmsLastSuccessCollector computes the time-since-last-success gauge at
+// scrape time, so the metric keeps growing while a plugin is wedged even
+// though no new samples are recorded.
+type kmsLastSuccessCollector struct {
+	metrics.BaseStableCollector
+}
+
+var _ metrics.StableCollector = &kmsLastSuccessCollector{}
+
+// DescribeWithStability implements the metrics.StableCollector interface.
+func (c *kmsLastSuccessCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- kmsLastSuccessAgeDesc
+}
+
+// CollectWithStability implements the metrics.StableCollector interface.
+func (c *kmsLastSuccessCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	operationRecordsLock.Lock()
+	defer operationRecordsLock.Unlock()
+	for key, record := range operationRecords {
+		if record.lastSuccess.IsZero() {
+			continue
+		}
+		ch <- metrics.NewLazyConstMetric(kmsLastSuccessAgeDesc, metrics.GaugeValue, now().Sub(record.lastSuccess).Seconds(), key[0], key[1])
+	}
+}
+
 var registerMetricsFunc sync.Once
 
 func RegisterMetrics() {
 	registerMetricsFunc.Do(func() {
 		legacyregistry.MustRegister(dekCacheFillPercent)
 		legacyregistry.MustRegister(dekCacheInterArrivals)
+		legacyregistry.MustRegister(dekCacheEntries)
+		legacyregistry.MustRegister(dekCacheRequests)
+		legacyregistry.MustRegister(dekCacheEvictions)
+		legacyregistry.MustRegister(kmsOperationsLatencies)
+		legacyregistry.MustRegister(kmsOperationsErrors)
+		legacyregistry.MustRegister(kmsRPCQueueLength)
+		legacyregistry.MustRegister(kmsRPCWaitLatencies)
+		legacyregistry.CustomMustRegister(&kmsLastSuccessCollector{})
 	})
 }
 
+// RecordDekCacheRequest records a single DEK cache lookup for the named
+// provider and whether the DEK was found.
+func RecordDekCacheRequest(providerName string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	dekCacheRequests.WithLabelValues(providerName, result).Inc()
+
+	dekCacheStatsLock.Lock()
+	defer dekCacheStatsLock.Unlock()
+	stats := dekCacheStatsFor(providerName)
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+}
+
+// RecordDekCacheEviction records a DEK being evicted from the named
+// provider's cache because the cache was full.
+func RecordDekCacheEviction(providerName string) {
+	dekCacheEvictions.WithLabelValues(providerName).Inc()
+
+	dekCacheStatsLock.Lock()
+	defer dekCacheStatsLock.Unlock()
+	dekCacheStatsFor(providerName).Evictions++
+}
+
+// RecordDekCacheEntries records the number of DEKs currently cached for the
+// named provider.
+func RecordDekCacheEntries(providerName string, entries int) {
+	dekCacheEntries.WithLabelValues(providerName).Set(float64(entries))
+
+	dekCacheStatsLock.Lock()
+	defer dekCacheStatsLock.Unlock()
+	dekCacheStatsFor(providerName).Entries = entries
+}
+
+// RecordKMSOperationLatency records the latency of a single Encrypt, Decrypt
+// or Status call to the named KMS provider. Failed operations are also
+// counted by their gRPC status code, so dashboards can distinguish a slow
+// plugin from a slow etcd. When the context carries a sampled trace span, the
+// latency sample is annotated with the trace ID as an exemplar, so a spike in
+// the histogram links directly to a trace of the offending RPC.
+func RecordKMSOperationLatency(ctx context.Context, providerName, methodName string, duration time.Duration, err error) {
+	kmsOperationsLatencies.WithContext(ctx).WithLabelValues(providerName, methodName).Observe(duration.Seconds())
+	if err != nil {
+		kmsOperationsErrors.WithLabelValues(providerName, methodName, status.Code(err).String()).Inc()
+	}
+	recordOperation(providerName, methodName, duration, err)
+}
+
+// RecordKMSRPCQueued notes that a call to the named provider started waiting
+// for an in-flight slot.
+func RecordKMSRPCQueued(providerName string) {
+	kmsRPCQueueLength.WithLabelValues(providerName).Inc()
+}
+
+// RecordKMSRPCDequeued notes that a call to the named provider stopped
+// waiting, whether it acquired a slot or gave up, and records how long it
+// waited.
+func RecordKMSRPCDequeued(providerName string, start time.Time) {
+	kmsRPCQueueLength.WithLabelValues(providerName).Dec()
+	kmsRPCWaitLatencies.WithLabelValues(providerName).Observe(time.Since(start).Seconds())
+}
+
 func RecordArrival(transformationType string, start time.Time) {
 	switch transformationType {
 	case FromStorageLabel:
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestTimeSinceLastSuccessfulKMSOperation(t *testing.T) {
+	RegisterMetrics()
+
+	fakeNow := time.Now()
+	now = func() time.Time { return fakeNow }
+	defer func() {
+		now = time.Now
+		operationRecordsLock.Lock()
+		defer operationRecordsLock.Unlock()
+		operationRecords = map[[2]string]*operationRecord{}
+	}()
+
+	RecordKMSOperationLatency(context.Background(), "age-test-provider", "Encrypt", time.Millisecond, nil)
+	RecordKMSOperationLatency(context.Background(), "age-test-provider", "Decrypt", time.Millisecond, nil)
+
+	// a failed operation must not move the last success timestamp
+	fakeNow = fakeNow.Add(30 * time.Second)
+	RecordKMSOperationLatency(context.Background(), "age-test-provider", "Decrypt", time.Millisecond, status.Error(codes.Unavailable, "not there"))
+
+	want := `
+		# HELP apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds [ALPHA] Seconds since the last successful KMS operation, broken down by provider name and method name. Alerts on this catch a silently wedged plugin before the next healthz probe fails.
+		# TYPE apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds gauge
+		apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds{method_name="Decrypt",provider_name="age-test-provider"} 30
+		apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds{method_name="Encrypt",provider_name="age-test-provider"} 30
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a later success resets the age
+	RecordKMSOperationLatency(context.Background(), "age-test-provider", "Decrypt", time.Millisecond, nil)
+
+	want = `
+		# HELP apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds [ALPHA] Seconds since the last successful KMS operation, broken down by provider name and method name. Alerts on this catch a silently wedged plugin before the next healthz probe fails.
+		# TYPE apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds gauge
+		apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds{method_name="Decrypt",provider_name="age-test-provider"} 0
+		apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds{method_name="Encrypt",provider_name="age-test-provider"} 30
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_envelope_encryption_kms_operations_time_since_last_success_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples bounds the per-provider, per-method ring of recent
+// latencies that percentiles are computed from.
+const maxLatencySamples = 128
+
+// OperationStats summarizes the recent Encrypt, Decrypt or Status calls to a
+// single KMS provider in a form the encryption debug endpoint can serve.
+// Percentiles are computed over a bounded window of the most recent calls.
+type OperationStats struct {
+	Method string `json:"method"`
+	// Samples is the number of recent latencies the percentiles are computed
+	// from, at most maxLatencySamples.
+	Samples           int        `json:"samples"`
+	LatencyP50Seconds float64    `json:"latencyP50Seconds"`
+	LatencyP90Seconds float64    `json:"latencyP90Seconds"`
+	LatencyP99Seconds float64    `json:"latencyP99Seconds"`
+	LastSuccess       *time.Time `json:"lastSuccess,omitempty"`
+	LastError         string     `json:"lastError,omitempty"`
+	LastErrorTime     *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// DEKCacheStats summarizes a single provider's DEK cache.
+type DEKCacheStats struct {
+	Entries   int    `json:"entries"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// operationRecord accumulates the raw observations behind OperationStats. The
+// latencies slice is used as a ring once it reaches maxLatencySamples, with
+// writes counted so the oldest sample is overwritten first.
+type operationRecord struct {
+	latencies     []float64
+	writes        int
+	lastSuccess   time.Time
+	lastError     string
+	lastErrorTime time.Time
+}
+
+var (
+	operationRecordsLock sync.Mutex
+	operationRecords     = map[[2]string]*operationRecord{}
+
+	dekCacheStatsLock sync.Mutex
+	dekCacheStats     = map[string]*DEKCacheStats{}
+)
+
+func recordOperation(providerName, methodName string, duration time.Duration, err error) {
+	operationRecordsLock.Lock()
+	defer operationRecordsLock.Unlock()
+
+	key := [2]string{providerName, methodName}
+	record := operationRecords[key]
+	if record == nil {
+		record = &operationRecord{}
+		operationRecords[key] = record
+	}
+	if len(record.latencies) < maxLatencySamples {
+		record.latencies = append(record.latencies, duration.Seconds())
+	} else {
+		record.latencies[record.writes%maxLatencySamples] = duration.Seconds()
+	}
+	record.writes++
+
+	if err != nil {
+		record.lastError = err.Error()
+		record.lastErrorTime = now()
+		return
+	}
+	record.lastSuccess = now()
+}
+
+// ProviderOperationStats returns per-method statistics for the named
+// provider, sorted by method name. Providers that have not served any
+// operation yet yield an empty slice.
+func ProviderOperationStats(providerName string) []OperationStats {
+	operationRecordsLock.Lock()
+	defer operationRecordsLock.Unlock()
+
+	var out []OperationStats
+	for key, record := range operationRecords {
+		if key[0] != providerName {
+			continue
+		}
+		sorted := append([]float64(nil), record.latencies...)
+		sort.Float64s(sorted)
+		stats := OperationStats{
+			Method:            key[1],
+			Samples:           len(sorted),
+			LatencyP50Seconds: percentile(sorted, 50),
+			LatencyP90Seconds: percentile(sorted, 90),
+			LatencyP99Seconds: percentile(sorted, 99),
+			LastError:         record.lastError,
+		}
+		if !record.lastSuccess.IsZero() {
+			t := record.lastSuccess
+			stats.LastSuccess = &t
+		}
+		if !record.lastErrorTime.IsZero() {
+			t := record.lastErrorTime
+			stats.LastErrorTime = &t
+		}
+		out = append(out, stats)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Method < out[j].Method })
+	return out
+}
+
+// ProviderDEKCacheStats returns a copy of the named provider's DEK cache
+// statistics, or nil if the provider has no DEK cache.
+func ProviderDEKCacheStats(providerName string) *DEKCacheStats {
+	dekCacheStatsLock.Lock()
+	defer dekCacheStatsLock.Unlock()
+
+	stats, ok := dekCacheStats[providerName]
+	if !ok {
+		return nil
+	}
+	out := *stats
+	return &out
+}
+
+// dekCacheStatsFor returns the mutable stats entry for the named provider,
+// creating it on first use. Callers must hold dekCacheStatsLock.
+func dekCacheStatsFor(providerName string) *DEKCacheStats {
+	stats := dekCacheStats[providerName]
+	if stats == nil {
+		stats = &DEKCacheStats{}
+		dekCacheStats[providerName] = stats
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of an already sorted sample, using
+// the nearest-rank method.
+func percentile(sorted []float64, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p + 99) / 100
+	if idx > 0 {
+		idx--
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// multiServiceHeader identifies envelopes that carry one wrapped copy of the
+// DEK per recipient. Ciphertexts without the header are passed to the first
+// recipient unchanged, so existing single-KEK data stays readable.
+var multiServiceHeader = []byte("k8s:multikek:v1:")
+
+// Recipient is one KEK that wraps the DEK in a multi-recipient envelope. The
+// name ties wrapped copies to their service across restarts and config
+// reloads and must therefore be stable.
+type Recipient struct {
+	Name    string
+	Service Service
+}
+
+type multiService struct {
+	recipients []Recipient
+}
+
+var _ Service = &multiService{}
+
+// NewMultiService returns a Service that wraps every DEK with each of the
+// given recipients, storing the copies together in one envelope. Decryption
+// succeeds as long as any one recipient can unwrap its copy, which keeps data
+// recoverable when one KMS backend is lost.
+func NewMultiService(recipients []Recipient) (Service, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+	seen := map[string]bool{}
+	for _, r := range recipients {
+		if len(r.Name) == 0 {
+			return nil, fmt.Errorf("recipient name must not be empty")
+		}
+		if len(r.Name) > 255 {
+			return nil, fmt.Errorf("recipient name %q is longer than 255 bytes", r.Name)
+		}
+		if r.Service == nil {
+			return nil, fmt.Errorf("recipient %q has no service", r.Name)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("duplicate recipient name %q", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return &multiService{recipients: recipients}, nil
+}
+
+// Encrypt wraps data under every recipient. All recipients must succeed: an
+// envelope silently missing one copy would defeat the disaster recovery
+// guarantee the caller asked for.
+func (m *multiService) Encrypt(data []byte) ([]byte, error) {
+	b := cryptobyte.NewBuilder(multiServiceHeader)
+	for _, r := range m.recipients {
+		ciphertext, err := r.Service.Encrypt(data)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %q failed to wrap key: %w", r.Name, err)
+		}
+		b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes([]byte(r.Name))
+		})
+		b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes(ciphertext)
+		})
+	}
+	return b.Bytes()
+}
+
+// Decrypt unwraps the first copy whose recipient is still configured and
+// healthy. Copies from recipients that are no longer configured are skipped.
+func (m *multiService) Decrypt(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, multiServiceHeader) {
+		// Data written before multi-recipient wrapping was enabled.
+		return m.recipients[0].Service.Decrypt(data)
+	}
+
+	copies := map[string][]byte{}
+	s := cryptobyte.String(data[len(multiServiceHeader):])
+	for !s.Empty() {
+		var name, ciphertext cryptobyte.String
+		if !s.ReadUint8LengthPrefixed(&name) || !s.ReadUint24LengthPrefixed(&ciphertext) {
+			return nil, fmt.Errorf("invalid multi-recipient envelope")
+		}
+		copies[string(name)] = ciphertext
+	}
+
+	var errs []error
+	for _, r := range m.recipients {
+		ciphertext, ok := copies[r.Name]
+		if !ok {
+			continue
+		}
+		plaintext, err := r.Service.Decrypt(ciphertext)
+		if err != nil {
+			klog.V(2).InfoS("Recipient failed to unwrap key, trying next", "recipient", r.Name, "err", err)
+			errs = append(errs, fmt.Errorf("recipient %q: %w", r.Name, err))
+			continue
+		}
+		return plaintext, nil
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("envelope carries no copy for any configured recipient")
+	}
+	return nil, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// prefixService is a trivial Service wrapping data with a recognizable prefix.
+type prefixService struct {
+	prefix string
+	broken bool
+}
+
+func (p *prefixService) Encrypt(data []byte) ([]byte, error) {
+	if p.broken {
+		return nil, fmt.Errorf("%s is unavailable", p.prefix)
+	}
+	return append([]byte(p.prefix+":"), data...), nil
+}
+
+func (p *prefixService) Decrypt(data []byte) ([]byte, error) {
+	if p.broken {
+		return nil, fmt.Errorf("%s is unavailable", p.prefix)
+	}
+	if !bytes.HasPrefix(data, []byte(p.prefix+":")) {
+		return nil, fmt.Errorf("not a %s ciphertext", p.prefix)
+	}
+	return data[len(p.prefix)+1:], nil
+}
+
+func TestNewMultiService(t *testing.T) {
+	valid := Recipient{Name: "a", Service: &prefixService{prefix: "a"}}
+	testCases := []struct {
+		desc       string
+		recipients []Recipient
+		wantErr    bool
+	}{
+		{desc: "single recipient", recipients: []Recipient{valid}},
+		{desc: "no recipients", recipients: nil, wantErr: true},
+		{desc: "empty name", recipients: []Recipient{{Name: "", Service: &prefixService{}}}, wantErr: true},
+		{desc: "nil service", recipients: []Recipient{{Name: "a"}}, wantErr: true},
+		{desc: "duplicate names", recipients: []Recipient{valid, valid}, wantErr: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := NewMultiService(tt.recipients)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("NewMultiService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultiServiceRoundTrip(t *testing.T) {
+	east := &prefixService{prefix: "east"}
+	west := &prefixService{prefix: "west"}
+	service, err := NewMultiService([]Recipient{
+		{Name: "kms-east", Service: east},
+		{Name: "kms-west", Service: west},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dek := []byte("the data encryption key")
+	envelope, err := service.Encrypt(dek)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !bytes.HasPrefix(envelope, multiServiceHeader) {
+		t.Fatal("envelope is missing the multi-recipient header")
+	}
+
+	decrypted, err := service.Decrypt(envelope)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, dek) {
+		t.Error("decrypted DEK does not match original")
+	}
+
+	// Losing the first KMS must not lose the data.
+	east.broken = true
+	decrypted, err = service.Decrypt(envelope)
+	if err != nil {
+		t.Fatalf("Decrypt with first recipient broken failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, dek) {
+		t.Error("decrypted DEK does not match original after failover")
+	}
+
+	// Losing both is an error naming the recipients.
+	west.broken = true
+	if _, err := service.Decrypt(envelope); err == nil {
+		t.Error("Decrypt succeeded with every recipient broken")
+	}
+}
+
+func TestMultiServiceEncryptRequiresAllRecipients(t *testing.T) {
+	service, err := NewMultiService([]Recipient{
+		{Name: "kms-east", Service: &prefixService{prefix: "east"}},
+		{Name: "kms-west", Service: &prefixService{prefix: "west", broken: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Encrypt([]byte("dek")); err == nil {
+		t.Fatal("Encrypt succeeded with a broken recipient")
+	}
+}
+
+func TestMultiServiceLegacyPassthrough(t *testing.T) {
+	east := &prefixService{prefix: "east"}
+	service, err := NewMultiService([]Recipient{{Name: "kms-east", Service: east}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := east.Encrypt([]byte("old dek"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := service.Decrypt(legacy)
+	if err != nil {
+		t.Fatalf("Decrypt of legacy ciphertext failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, []byte("old dek")) {
+		t.Errorf("legacy decrypt returned %q", decrypted)
+	}
+}
+
+func TestMultiServiceRecipientRemoved(t *testing.T) {
+	east := &prefixService{prefix: "east"}
+	west := &prefixService{prefix: "west"}
+	both, err := NewMultiService([]Recipient{
+		{Name: "kms-east", Service: east},
+		{Name: "kms-west", Service: west},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := both.Encrypt([]byte("dek"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A config now listing only the second recipient can still decrypt.
+	westOnly, err := NewMultiService([]Recipient{{Name: "kms-west", Service: west}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := westOnly.Decrypt(envelope)
+	if err != nil {
+		t.Fatalf("Decrypt after recipient removal failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, []byte("dek")) {
+		t.Error("decrypted DEK does not match original")
+	}
+
+	// A config with no matching recipients reports it clearly.
+	other, err := NewMultiService([]Recipient{{Name: "kms-north", Service: &prefixService{prefix: "north"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Decrypt(envelope); err == nil {
+		t.Error("Decrypt succeeded without any matching recipient")
+	}
+}
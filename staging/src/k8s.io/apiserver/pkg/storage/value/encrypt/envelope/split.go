@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// splitService is a Service that splits each DEK into two shares with a
+// one-time pad and wraps the shares with two independent KMS providers.
+// Either share on its own is indistinguishable from random data, so both
+// providers must be available (dual control) to recover the DEK and a single
+// compromised KMS account cannot read any stored data.
+type splitService struct {
+	primary   Service
+	secondary Service
+}
+
+// NewSplitService returns a Service wrapping DEK shares with the two given
+// services. Both are required for Decrypt; the pair is order-sensitive, so the
+// same service must stay primary for previously written data to decrypt.
+func NewSplitService(primary, secondary Service) Service {
+	return &splitService{primary: primary, secondary: secondary}
+}
+
+// Encrypt splits data with a fresh one-time pad and wraps one share per
+// provider, encoding both wrapped shares with 16 bit length prefixes.
+func (s *splitService) Encrypt(data []byte) ([]byte, error) {
+	pad := make([]byte, len(data))
+	if _, err := rand.Read(pad); err != nil {
+		return nil, err
+	}
+	share := make([]byte, len(data))
+	for i := range data {
+		share[i] = data[i] ^ pad[i]
+	}
+
+	wrappedShare, err := s.primary.Encrypt(share)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap DEK share with primary provider: %w", err)
+	}
+	wrappedPad, err := s.secondary.Encrypt(pad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap DEK share with secondary provider: %w", err)
+	}
+
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(wrappedShare)
+	})
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(wrappedPad)
+	})
+	return b.Bytes()
+}
+
+// Decrypt unwraps both shares and recombines them into the original DEK.
+func (s *splitService) Decrypt(data []byte) ([]byte, error) {
+	var wrappedShare, wrappedPad cryptobyte.String
+	str := cryptobyte.String(data)
+	if ok := str.ReadUint16LengthPrefixed(&wrappedShare); !ok {
+		return nil, fmt.Errorf("invalid data encountered by split envelope service: failed to read primary share")
+	}
+	if ok := str.ReadUint16LengthPrefixed(&wrappedPad); !ok {
+		return nil, fmt.Errorf("invalid data encountered by split envelope service: failed to read secondary share")
+	}
+	if !str.Empty() {
+		return nil, fmt.Errorf("invalid data encountered by split envelope service: trailing data after shares")
+	}
+
+	share, err := s.primary.Decrypt(wrappedShare)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap DEK share with primary provider: %w", err)
+	}
+	pad, err := s.secondary.Decrypt(wrappedPad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap DEK share with secondary provider: %w", err)
+	}
+	if len(share) != len(pad) {
+		return nil, fmt.Errorf("invalid data encountered by split envelope service: share lengths do not match")
+	}
+
+	key := make([]byte, len(share))
+	for i := range share {
+		key[i] = share[i] ^ pad[i]
+	}
+	return key, nil
+}
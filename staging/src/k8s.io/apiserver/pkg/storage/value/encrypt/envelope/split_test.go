@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+)
+
+func TestSplitServiceRoundTrip(t *testing.T) {
+	primary := newTestEnvelopeService()
+	secondary := newTestEnvelopeService()
+	service := NewSplitService(primary, secondary)
+
+	key := []byte(testText)
+	wrapped, err := service.Encrypt(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(wrapped, key) {
+		t.Errorf("wrapped DEK must not contain the plaintext key")
+	}
+
+	unwrapped, err := service.Decrypt(wrapped)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(unwrapped, key) {
+		t.Errorf("expected %q, got %q", key, unwrapped)
+	}
+}
+
+func TestSplitServiceRequiresBothProviders(t *testing.T) {
+	primary := newTestEnvelopeService()
+	secondary := newTestEnvelopeService()
+	service := NewSplitService(primary, secondary)
+
+	wrapped, err := service.Encrypt([]byte(testText))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	primary.SetDisabledStatus(true)
+	if _, err := service.Decrypt(wrapped); err == nil || !strings.Contains(err.Error(), "primary") {
+		t.Errorf("expected decryption to fail naming the primary provider, got %v", err)
+	}
+	primary.SetDisabledStatus(false)
+
+	secondary.SetDisabledStatus(true)
+	if _, err := service.Decrypt(wrapped); err == nil || !strings.Contains(err.Error(), "secondary") {
+		t.Errorf("expected decryption to fail naming the secondary provider, got %v", err)
+	}
+	secondary.SetDisabledStatus(false)
+
+	if _, err := service.Decrypt(wrapped); err != nil {
+		t.Errorf("expected decryption to succeed with both providers available, got %v", err)
+	}
+}
+
+func TestSplitServiceInvalidData(t *testing.T) {
+	service := NewSplitService(newTestEnvelopeService(), newTestEnvelopeService())
+
+	for _, data := range [][]byte{nil, {0x00}, {0x00, 0x05, 0x01}} {
+		if _, err := service.Decrypt(data); err == nil {
+			t.Errorf("expected an error decrypting malformed data %v, got none", data)
+		}
+	}
+}
+
+func TestSplitServiceTransformerRoundTrip(t *testing.T) {
+	service := NewSplitService(newTestEnvelopeService(), newTestEnvelopeService())
+	transformer := NewEnvelopeTransformer(service, "split", testEnvelopeCacheSize, aestransformer.NewGCMTransformer)
+
+	ctx := context.Background()
+	dataCtx := value.DefaultContext([]byte(testContextText))
+	encrypted, err := transformer.TransformToStorage(ctx, []byte(testText), dataCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decrypted, _, err := transformer.TransformFromStorage(ctx, encrypted, dataCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(decrypted) != testText {
+		t.Errorf("expected %q, got %q", testText, decrypted)
+	}
+}
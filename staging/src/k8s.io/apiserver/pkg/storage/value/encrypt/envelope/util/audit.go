@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sync/atomic"
+
+	"k8s.io/apiserver/pkg/audit"
+)
+
+const (
+	// decryptProviderAuditAnnotationKey is the audit annotation recording the
+	// KMS provider that unwrapped a DEK while serving the request.
+	decryptProviderAuditAnnotationKey = "encryption.k8s.io/decrypt-provider"
+	// decryptKeyIDAuditAnnotationKey is the audit annotation recording the KMS
+	// key ID the unwrapped DEK was protected with, when the provider reports one.
+	decryptKeyIDAuditAnnotationKey = "encryption.k8s.io/decrypt-key-id"
+	// decryptResourceAuditAnnotationKey is the audit annotation recording the
+	// storage key of the object whose DEK was unwrapped.
+	decryptResourceAuditAnnotationKey = "encryption.k8s.io/decrypt-resource"
+)
+
+// auditDEKUnwrap is accessed atomically because envelope transformers decrypt
+// DEKs concurrently with flag handling at startup.
+var auditDEKUnwrap int32
+
+// SetAuditDEKUnwrap globally enables or disables recording every DEK unwrap
+// into the audit stream. It is set once at startup from the
+// --encryption-audit-dek-unwrap flag.
+func SetAuditDEKUnwrap(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&auditDEKUnwrap, 1)
+	} else {
+		atomic.StoreInt32(&auditDEKUnwrap, 0)
+	}
+}
+
+func auditDEKUnwrapEnabled() bool {
+	return atomic.LoadInt32(&auditDEKUnwrap) == 1
+}
+
+// RecordDEKUnwrap annotates the audit event of the request in ctx with the
+// provider, key ID (when known) and storage key of a DEK unwrap, so that every
+// use of the KEK is attributable in the audit log. The caller identity is not
+// recorded here because it is already part of the surrounding audit event;
+// unwraps outside a request context (for example storage migration started by
+// a controller loop) carry no event and are only visible through the
+// kms_operations_latency_seconds metric.
+func RecordDEKUnwrap(ctx context.Context, providerName, keyID string, resourceKey []byte) {
+	if !auditDEKUnwrapEnabled() {
+		return
+	}
+	annotations := []string{
+		decryptProviderAuditAnnotationKey, providerName,
+		decryptResourceAuditAnnotationKey, string(resourceKey),
+	}
+	if len(keyID) > 0 {
+		annotations = append(annotations, decryptKeyIDAuditAnnotationKey, keyID)
+	}
+	audit.AddAuditAnnotations(ctx, annotations...)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/metrics"
+	"k8s.io/utils/lru"
+)
+
+// DefaultDEKCacheTTL bounds how long a cached DEK transformer may be reused
+// before the encrypted DEK has to be unwrapped through the KMS again. Without
+// a bound, a transformer cached before a KEK was revoked would keep decrypting
+// for as long as it stayed warm in the cache.
+const DefaultDEKCacheTTL = time.Hour
+
+// TransformerCache is a thread-safe LRU cache of transformers indexed by the
+// encrypted form of the DEK they were built from, shared by the envelope
+// transformer implementations. Entries hold only the expanded cipher state,
+// never the plaintext DEK, and expire after a TTL so that a revoked KEK stops
+// serving reads once the cached entries age out.
+type TransformerCache struct {
+	// providerName is the name of the KMS provider, used as the metrics label
+	// for the DEK cache.
+	providerName string
+	cache        *lru.Cache
+	size         int
+	ttl          time.Duration
+}
+
+type cacheEntry struct {
+	transformer value.Transformer
+	expires     time.Time
+}
+
+// NewTransformerCache returns a transformer cache of the given size for the
+// given provider. A size of zero or less disables caching, in which case all
+// methods are no-ops that report misses.
+func NewTransformerCache(providerName string, size int, ttl time.Duration) *TransformerCache {
+	c := &TransformerCache{
+		providerName: providerName,
+		size:         size,
+		ttl:          ttl,
+	}
+	if size > 0 {
+		c.cache = lru.New(size)
+	}
+	return c
+}
+
+// Enabled reports whether the cache stores transformers.
+func (c *TransformerCache) Enabled() bool {
+	return c.cache != nil
+}
+
+// Get fetches the transformer corresponding to encKey from the cache, if a
+// live entry exists. Expired entries are removed and reported as misses.
+func (c *TransformerCache) Get(encKey []byte) value.Transformer {
+	if !c.Enabled() {
+		return nil
+	}
+	cacheKey := string(encKey)
+	record, found := c.cache.Get(cacheKey)
+	if !found {
+		metrics.RecordDekCacheRequest(c.providerName, false)
+		return nil
+	}
+	entry := record.(cacheEntry)
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.cache.Remove(cacheKey)
+		metrics.RecordDekCacheEntries(c.providerName, c.cache.Len())
+		metrics.RecordDekCacheRequest(c.providerName, false)
+		return nil
+	}
+	metrics.RecordDekCacheRequest(c.providerName, true)
+	return entry.transformer
+}
+
+// Add inserts the transformer built from the DEK whose encrypted form is
+// encKey for future reads.
+func (c *TransformerCache) Add(encKey []byte, transformer value.Transformer) {
+	if !c.Enabled() {
+		return
+	}
+	// Use the raw bytes of encKey, converted to a string, as the key into the
+	// cache because hashicorp/golang-lru cannot hash []uint8. The conversion
+	// allocates once, where base64 encoding allocated and copied a third more.
+	cacheKey := string(encKey)
+	if _, found := c.cache.Get(cacheKey); !found && c.cache.Len() == c.size {
+		metrics.RecordDekCacheEviction(c.providerName)
+	}
+	c.cache.Add(cacheKey, cacheEntry{transformer: transformer, expires: time.Now().Add(c.ttl)})
+	metrics.RecordDekCacheFillPercent(float64(c.cache.Len()) / float64(c.size))
+	metrics.RecordDekCacheEntries(c.providerName, c.cache.Len())
+}
+
+// Clear drops every cached transformer, forcing all DEKs to be unwrapped
+// through the KMS again.
+func (c *TransformerCache) Clear() {
+	if !c.Enabled() {
+		return
+	}
+	c.cache.Clear()
+	metrics.RecordDekCacheEntries(c.providerName, 0)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+type fakeTransformer struct{}
+
+func (fakeTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	return data, false, nil
+}
+
+func (fakeTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return data, nil
+}
+
+func TestTransformerCacheDisabled(t *testing.T) {
+	cache := NewTransformerCache("panda", 0, DefaultDEKCacheTTL)
+	if cache.Enabled() {
+		t.Error("expected a zero sized cache to be disabled")
+	}
+	cache.Add([]byte("encrypted-dek"), fakeTransformer{})
+	if got := cache.Get([]byte("encrypted-dek")); got != nil {
+		t.Errorf("expected a disabled cache to miss, got %v", got)
+	}
+}
+
+func TestTransformerCacheRoundTrip(t *testing.T) {
+	cache := NewTransformerCache("panda", 10, DefaultDEKCacheTTL)
+	transformer := fakeTransformer{}
+	cache.Add([]byte("encrypted-dek"), transformer)
+	if got := cache.Get([]byte("encrypted-dek")); got != transformer {
+		t.Errorf("expected the cached transformer, got %v", got)
+	}
+	if got := cache.Get([]byte("other-dek")); got != nil {
+		t.Errorf("expected a miss for an unknown DEK, got %v", got)
+	}
+}
+
+func TestTransformerCacheExpiry(t *testing.T) {
+	cache := NewTransformerCache("panda", 10, time.Millisecond)
+	cache.Add([]byte("encrypted-dek"), fakeTransformer{})
+	time.Sleep(5 * time.Millisecond)
+	if got := cache.Get([]byte("encrypted-dek")); got != nil {
+		t.Errorf("expected the entry to have expired, got %v", got)
+	}
+}
+
+func TestTransformerCacheClear(t *testing.T) {
+	cache := NewTransformerCache("panda", 10, DefaultDEKCacheTTL)
+	cache.Add([]byte("encrypted-dek"), fakeTransformer{})
+	cache.Clear()
+	if got := cache.Get([]byte("encrypted-dek")); got != nil {
+		t.Errorf("expected the cache to be empty after Clear, got %v", got)
+	}
+}
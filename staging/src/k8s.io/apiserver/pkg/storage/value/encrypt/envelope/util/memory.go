@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync/atomic"
+)
+
+// ZeroMemory overwrites b with zeros so plaintext key material does not
+// linger in freed heap memory, where it could surface in a core dump.
+func ZeroMemory(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+var mlockKeyMaterial int32
+
+// SetMlockKeyMaterial toggles best-effort locking of DEK buffers into
+// resident memory while they are in use, keeping plaintext key material out
+// of swap. Locking is a no-op on platforms without mlock. The expanded key
+// schedules held by cached transformers are not covered; only the raw DEK
+// buffers are, for the short window they exist before being zeroed.
+func SetMlockKeyMaterial(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&mlockKeyMaterial, 1)
+	} else {
+		atomic.StoreInt32(&mlockKeyMaterial, 0)
+	}
+}
+
+func mlockKeyMaterialEnabled() bool {
+	return atomic.LoadInt32(&mlockKeyMaterial) == 1
+}
+
+// LockKeyMaterial locks b into resident memory when enabled via
+// SetMlockKeyMaterial. A failure is returned so the caller can log it; the
+// buffer stays usable either way.
+func LockKeyMaterial(b []byte) error {
+	if !mlockKeyMaterialEnabled() || len(b) == 0 {
+		return nil
+	}
+	return lockMemory(b)
+}
+
+// UnlockKeyMaterial undoes LockKeyMaterial. Callers must zero the buffer
+// first so the pages hold no key material once they may be swapped again.
+func UnlockKeyMaterial(b []byte) {
+	if !mlockKeyMaterialEnabled() || len(b) == 0 {
+		return
+	}
+	unlockMemory(b)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestZeroMemory(t *testing.T) {
+	key := []byte("sensitive key material")
+	ZeroMemory(key)
+	if !bytes.Equal(key, make([]byte, len(key))) {
+		t.Errorf("expected buffer to be zeroed, got %q", key)
+	}
+
+	// must not panic on empty or nil buffers
+	ZeroMemory(nil)
+	ZeroMemory([]byte{})
+}
+
+func TestLockKeyMaterialDisabled(t *testing.T) {
+	key := []byte("sensitive key material")
+	if err := LockKeyMaterial(key); err != nil {
+		t.Errorf("expected locking to be a no-op while disabled, got %v", err)
+	}
+	UnlockKeyMaterial(key)
+}
+
+func TestLockKeyMaterialRoundTrip(t *testing.T) {
+	SetMlockKeyMaterial(true)
+	defer SetMlockKeyMaterial(false)
+
+	key := make([]byte, 32)
+	if err := LockKeyMaterial(key); err != nil {
+		// the environment may not grant RLIMIT_MEMLOCK; the failure mode is
+		// what callers see, not a bug in the helper
+		t.Skipf("mlock not permitted in this environment: %v", err)
+	}
+	ZeroMemory(key)
+	UnlockKeyMaterial(key)
+}
@@ -52,7 +52,7 @@ func (t *secretboxTransformer) TransformFromStorage(ctx context.Context, data []
 	out := make([]byte, 0, len(data)-secretbox.Overhead)
 	result, ok := secretbox.Open(out, data, &nonce, &t.key)
 	if !ok {
-		return nil, false, fmt.Errorf("output array was not large enough for encryption")
+		return nil, false, value.NewDecryptionFailedError(fmt.Errorf("output array was not large enough for encryption"))
 	}
 	return result, false, nil
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tpm unseals local key encryption keys (KEKs) with the node's TPM.
+// Unlike the aescbc and aesgcm providers, the raw KEK is never stored on
+// disk: the encryption configuration references a sealed key blob that only
+// the TPM can decrypt, under a policy bound to the configured PCRs.
+package tpm
+
+import (
+	"fmt"
+)
+
+// DefaultDevicePath is the in-kernel TPM 2.0 resource manager, which allows
+// the device to be shared with other TPM consumers on the node.
+const DefaultDevicePath = "/dev/tpmrm0"
+
+// Device abstracts the subset of TPM 2.0 functionality needed to unseal KEKs.
+// Implementations must be safe for use from a single goroutine at a time.
+type Device interface {
+	// Unseal decrypts a key blob previously sealed to this TPM under a
+	// policy covering the given PCR indexes.
+	Unseal(sealedKey []byte, pcrs []int32) ([]byte, error)
+	// Close releases the device.
+	Close() error
+}
+
+// DeviceFactory opens the TPM device at the given path.
+type DeviceFactory func(devicePath string) (Device, error)
+
+// deviceFactory is the factory used by UnsealKey to open TPM devices.
+// Distributions that link in hardware TPM support replace it via
+// SetDeviceFactory.
+var deviceFactory DeviceFactory = func(devicePath string) (Device, error) {
+	return nil, fmt.Errorf("cannot open TPM device %q: no TPM device implementation is linked into this binary", devicePath)
+}
+
+// SetDeviceFactory replaces the factory used to open TPM devices. It must be
+// called before any encryption configuration referencing a tpm provider is
+// loaded, typically from an init function of the package providing the
+// device implementation.
+func SetDeviceFactory(factory DeviceFactory) {
+	deviceFactory = factory
+}
+
+// UnsealKey opens the TPM device at devicePath, unseals the given key blob
+// under the policy of the given PCRs and validates that the result is a key
+// of AES length.
+func UnsealKey(devicePath string, sealedKey []byte, pcrs []int32) ([]byte, error) {
+	if len(sealedKey) == 0 {
+		return nil, fmt.Errorf("sealed key is empty")
+	}
+
+	device, err := deviceFactory(devicePath)
+	if err != nil {
+		return nil, err
+	}
+	defer device.Close()
+
+	key, err := device.Unseal(sealedKey, pcrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal key with TPM device %q: %w", devicePath, err)
+	}
+
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsealed key has invalid length %d, expected one of [16 24 32]", len(key))
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpm
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// fakeDevice unseals by stripping a fixed prefix from the sealed blob.
+type fakeDevice struct {
+	closed bool
+}
+
+func (d *fakeDevice) Unseal(sealedKey []byte, pcrs []int32) ([]byte, error) {
+	if !bytes.HasPrefix(sealedKey, []byte("sealed:")) {
+		return nil, fmt.Errorf("invalid sealed blob")
+	}
+	return bytes.TrimPrefix(sealedKey, []byte("sealed:")), nil
+}
+
+func (d *fakeDevice) Close() error {
+	d.closed = true
+	return nil
+}
+
+func withFakeDevice(t *testing.T, device *fakeDevice) {
+	t.Helper()
+	original := deviceFactory
+	deviceFactory = func(devicePath string) (Device, error) {
+		return device, nil
+	}
+	t.Cleanup(func() {
+		deviceFactory = original
+	})
+}
+
+func TestUnsealKey(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+	device := &fakeDevice{}
+	withFakeDevice(t, device)
+
+	unsealed, err := UnsealKey(DefaultDevicePath, append([]byte("sealed:"), key...), []int32{7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(unsealed, key) {
+		t.Errorf("expected key %q, got %q", key, unsealed)
+	}
+	if !device.closed {
+		t.Errorf("expected the device to be closed")
+	}
+}
+
+func TestUnsealKeyInvalidLength(t *testing.T) {
+	withFakeDevice(t, &fakeDevice{})
+
+	if _, err := UnsealKey(DefaultDevicePath, []byte("sealed:short"), []int32{7}); err == nil {
+		t.Errorf("expected an error for a key of invalid length")
+	}
+}
+
+func TestUnsealKeyEmptyBlob(t *testing.T) {
+	withFakeDevice(t, &fakeDevice{})
+
+	if _, err := UnsealKey(DefaultDevicePath, nil, []int32{7}); err == nil {
+		t.Errorf("expected an error for an empty sealed key")
+	}
+}
+
+func TestUnsealKeyNoDevice(t *testing.T) {
+	if _, err := UnsealKey(DefaultDevicePath, []byte("sealed:key"), nil); err == nil {
+		t.Errorf("expected an error when no TPM device implementation is available")
+	}
+}
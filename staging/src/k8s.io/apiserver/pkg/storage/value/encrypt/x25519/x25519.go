@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package x25519 transforms values for storage at rest by encrypting them to
+// one or more X25519 recipient public keys, age-style. The apiserver only
+// holds public keys, so it can write but never read the data back: decryption
+// requires a recipient's private key, held offline for break-glass tooling.
+package x25519
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/curve25519"
+
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+// kdfInfo domain-separates the derived wrapping key from other X25519 uses.
+const kdfInfo = "kubernetes x25519 recipient wrap v1"
+
+// recipientIDSize is the length of the public key fingerprint stored with
+// each wrapped DEK copy so decryption can pick the right copy.
+const recipientIDSize = 8
+
+// writeOnlyTransformer encrypts to recipient public keys and cannot decrypt.
+type writeOnlyTransformer struct {
+	recipients [][]byte
+}
+
+// NewWriteOnlyTransformer returns a transformer that encrypts each value with
+// a fresh DEK and wraps the DEK to every recipient public key. Reads always
+// fail: they require a recipient private key, see NewRecoveryTransformer.
+func NewWriteOnlyTransformer(recipientPublicKeys [][]byte) (value.Transformer, error) {
+	if len(recipientPublicKeys) == 0 {
+		return nil, fmt.Errorf("at least one recipient public key is required")
+	}
+	for i, key := range recipientPublicKeys {
+		if len(key) != curve25519.PointSize {
+			return nil, fmt.Errorf("recipient public key %d must be %d bytes, got %d", i, curve25519.PointSize, len(key))
+		}
+	}
+	return &writeOnlyTransformer{recipients: recipientPublicKeys}, nil
+}
+
+func (t *writeOnlyTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	return nil, false, fmt.Errorf("data is encrypted to offline X25519 recipients and cannot be decrypted by the apiserver")
+}
+
+func (t *writeOnlyTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	payload, err := seal(dek, data)
+	if err != nil {
+		return nil, err
+	}
+
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint8(uint8(len(t.recipients)))
+	for _, publicKey := range t.recipients {
+		wrapped, err := wrapDEK(publicKey, dek)
+		if err != nil {
+			return nil, err
+		}
+		b.AddBytes(recipientID(publicKey))
+		b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes(wrapped)
+		})
+	}
+	b.AddBytes(payload)
+	return b.Bytes()
+}
+
+// recoveryTransformer decrypts values written by a writeOnlyTransformer using
+// one recipient's private key. It is intended for offline break-glass tooling
+// and refuses to encrypt so the private key never ends up on a live server by
+// accident.
+type recoveryTransformer struct {
+	privateKey []byte
+	id         []byte
+}
+
+// NewRecoveryTransformer returns a transformer that decrypts values encrypted
+// to the recipient owning privateKey.
+func NewRecoveryTransformer(privateKey []byte) (value.Transformer, error) {
+	if len(privateKey) != curve25519.ScalarSize {
+		return nil, fmt.Errorf("private key must be %d bytes, got %d", curve25519.ScalarSize, len(privateKey))
+	}
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	return &recoveryTransformer{privateKey: privateKey, id: recipientID(publicKey)}, nil
+}
+
+func (t *recoveryTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	s := cryptobyte.String(data)
+	var count uint8
+	if !s.ReadUint8(&count) || count == 0 {
+		return nil, false, fmt.Errorf("invalid x25519 encrypted value")
+	}
+
+	var wrapped []byte
+	for i := 0; i < int(count); i++ {
+		var id []byte
+		var copyBytes cryptobyte.String
+		if !s.ReadBytes(&id, recipientIDSize) || !s.ReadUint16LengthPrefixed(&copyBytes) {
+			return nil, false, fmt.Errorf("invalid x25519 encrypted value")
+		}
+		if hmac.Equal(id, t.id) {
+			wrapped = copyBytes
+		}
+	}
+	if wrapped == nil {
+		return nil, false, fmt.Errorf("value carries no copy for this recipient")
+	}
+
+	dek, err := unwrapDEK(t.privateKey, wrapped)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not unwrap DEK: %w", err)
+	}
+	plaintext, err := open(dek, s)
+	if err != nil {
+		return nil, false, err
+	}
+	return plaintext, false, nil
+}
+
+func (t *recoveryTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return nil, fmt.Errorf("the x25519 recovery transformer is read only")
+}
+
+// wrapDEK seals dek to a single recipient: ephemeral Diffie-Hellman against
+// the recipient key, HKDF to a wrapping key, AES-GCM over the DEK. The blob
+// is the ephemeral public key followed by the sealed DEK, length prefixed.
+func wrapDEK(recipientPublicKey, dek []byte) ([]byte, error) {
+	ephemeralPrivate := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeralPrivate); err != nil {
+		return nil, err
+	}
+	ephemeralPublic, err := curve25519.X25519(ephemeralPrivate, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := curve25519.X25519(ephemeralPrivate, recipientPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(deriveWrappingKey(sharedSecret), dek)
+	if err != nil {
+		return nil, err
+	}
+
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(ephemeralPublic)
+	})
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(sealed)
+	})
+	return b.Bytes()
+}
+
+func unwrapDEK(privateKey, wrapped []byte) ([]byte, error) {
+	var ephemeralPublic, sealed cryptobyte.String
+	s := cryptobyte.String(wrapped)
+	if !s.ReadUint16LengthPrefixed(&ephemeralPublic) ||
+		!s.ReadUint16LengthPrefixed(&sealed) ||
+		!s.Empty() {
+		return nil, fmt.Errorf("invalid wrapped DEK")
+	}
+	sharedSecret, err := curve25519.X25519(privateKey, ephemeralPublic)
+	if err != nil {
+		return nil, err
+	}
+	return open(deriveWrappingKey(sharedSecret), sealed)
+}
+
+// recipientID is a short fingerprint of the public key, stored next to each
+// wrapped copy so recovery tooling can select the copy for its key.
+func recipientID(publicKey []byte) []byte {
+	sum := sha256.Sum256(publicKey)
+	return sum[:recipientIDSize]
+}
+
+// deriveWrappingKey expands the Diffie-Hellman shared secret into an AES-256
+// wrapping key via single-block HKDF-SHA256.
+func deriveWrappingKey(sharedSecret []byte) []byte {
+	prk := hmac.New(sha256.New, nil)
+	prk.Write(sharedSecret)
+	pseudorandomKey := prk.Sum(nil)
+
+	expand := hmac.New(sha256.New, pseudorandomKey)
+	expand.Write([]byte(kdfInfo))
+	expand.Write([]byte{1})
+	return expand.Sum(nil)
+}
+
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid x25519 encrypted value")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
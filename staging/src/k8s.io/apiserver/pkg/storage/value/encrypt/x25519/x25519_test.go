@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x25519
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"golang.org/x/crypto/curve25519"
+
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+func generateKeyPair(t *testing.T) (privateKey, publicKey []byte) {
+	t.Helper()
+	privateKey = make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(privateKey); err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey, publicKey
+}
+
+func TestWriteOnlyTransformerValidation(t *testing.T) {
+	if _, err := NewWriteOnlyTransformer(nil); err == nil {
+		t.Error("NewWriteOnlyTransformer accepted zero recipients")
+	}
+	if _, err := NewWriteOnlyTransformer([][]byte{[]byte("short")}); err == nil {
+		t.Error("NewWriteOnlyTransformer accepted a truncated public key")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dataCtx := value.DefaultContext("authenticated_data")
+	alicePrivate, alicePublic := generateKeyPair(t)
+	bobPrivate, bobPublic := generateKeyPair(t)
+
+	writer, err := NewWriteOnlyTransformer([][]byte{alicePublic, bobPublic})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("a secret to keep")
+	encrypted, err := writer.TransformToStorage(ctx, plaintext, dataCtx)
+	if err != nil {
+		t.Fatalf("TransformToStorage failed: %v", err)
+	}
+	if bytes.Contains(encrypted, plaintext) {
+		t.Fatal("encrypted value contains the plaintext")
+	}
+
+	// The apiserver side must not be able to read the value back.
+	if _, _, err := writer.TransformFromStorage(ctx, encrypted, dataCtx); err == nil {
+		t.Error("write-only transformer decrypted a value")
+	}
+
+	// Either offline private key recovers the value.
+	for name, privateKey := range map[string][]byte{"alice": alicePrivate, "bob": bobPrivate} {
+		recovery, err := NewRecoveryTransformer(privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, _, err := recovery.TransformFromStorage(ctx, encrypted, dataCtx)
+		if err != nil {
+			t.Fatalf("recovery with %s's key failed: %v", name, err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("recovery with %s's key returned %q, want %q", name, decrypted, plaintext)
+		}
+	}
+}
+
+func TestRecoveryWithWrongKey(t *testing.T) {
+	ctx := context.Background()
+	dataCtx := value.DefaultContext("")
+	_, recipientPublic := generateKeyPair(t)
+	strangerPrivate, _ := generateKeyPair(t)
+
+	writer, err := NewWriteOnlyTransformer([][]byte{recipientPublic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := writer.TransformToStorage(ctx, []byte("secret"), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recovery, err := NewRecoveryTransformer(strangerPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := recovery.TransformFromStorage(ctx, encrypted, dataCtx); err == nil {
+		t.Error("recovery succeeded with a non-recipient key")
+	}
+}
+
+func TestTamperedValueRejected(t *testing.T) {
+	ctx := context.Background()
+	dataCtx := value.DefaultContext("")
+	recipientPrivate, recipientPublic := generateKeyPair(t)
+
+	writer, err := NewWriteOnlyTransformer([][]byte{recipientPublic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := writer.TransformToStorage(ctx, []byte("secret"), dataCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted[len(encrypted)-1] ^= 0xff
+
+	recovery, err := NewRecoveryTransformer(recipientPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := recovery.TransformFromStorage(ctx, encrypted, dataCtx); err == nil {
+		t.Error("recovery succeeded on a tampered value")
+	}
+}
+
+func TestRecoveryTransformerIsReadOnly(t *testing.T) {
+	recipientPrivate, _ := generateKeyPair(t)
+	recovery, err := NewRecoveryTransformer(recipientPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recovery.TransformToStorage(context.Background(), []byte("secret"), value.DefaultContext("")); err == nil {
+		t.Error("recovery transformer encrypted a value")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TransformReason classifies why a storage transformation failed, so callers
+// can distinguish unreadable data from a transient provider outage.
+type TransformReason string
+
+const (
+	// ReasonDecryptionFailed means the stored data could not be decrypted with
+	// any of the configured keys. The data is corrupt, was tampered with, or
+	// was written under a different authenticated data context.
+	ReasonDecryptionFailed TransformReason = "decryption_failed"
+	// ReasonKMSUnavailable means the KMS provider could not be reached or did
+	// not answer in time. The data itself may be fine; the read can be retried.
+	ReasonKMSUnavailable TransformReason = "kms_unavailable"
+	// ReasonStaleKey means the stored data carries a prefix that matches none
+	// of the configured providers or keys, typically because the key it was
+	// written under has been removed from the configuration.
+	ReasonStaleKey TransformReason = "stale_key"
+	// ReasonRevokedKey means the stored data is protected by a key that has
+	// been declared revoked in the configuration, e.g. after a compromise.
+	// The data is deliberately not served.
+	ReasonRevokedKey TransformReason = "revoked_key"
+)
+
+var reasonMessage = map[TransformReason]string{
+	ReasonDecryptionFailed: "decryption failed",
+	ReasonKMSUnavailable:   "KMS provider unavailable",
+	ReasonStaleKey:         "stale key",
+	ReasonRevokedKey:       "revoked key",
+}
+
+// TransformError carries the reason a transformation failed alongside the
+// underlying error.
+type TransformError struct {
+	Reason TransformReason
+	Err    error
+}
+
+func (e *TransformError) Error() string {
+	return fmt.Sprintf("%s: %v", reasonMessage[e.Reason], e.Err)
+}
+
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// NewDecryptionFailedError marks err as a failure to decrypt stored data.
+func NewDecryptionFailedError(err error) error {
+	return &TransformError{Reason: ReasonDecryptionFailed, Err: err}
+}
+
+// NewKMSUnavailableError marks err as a failure caused by the KMS provider
+// being unreachable or unhealthy rather than by the stored data itself.
+func NewKMSUnavailableError(err error) error {
+	return &TransformError{Reason: ReasonKMSUnavailable, Err: err}
+}
+
+// NewStaleKeyError marks err as stored data written under a key that is no
+// longer configured.
+func NewStaleKeyError(err error) error {
+	return &TransformError{Reason: ReasonStaleKey, Err: err}
+}
+
+// NewRevokedKeyError marks err as stored data protected by a key that has
+// been declared revoked in the configuration.
+func NewRevokedKeyError(err error) error {
+	return &TransformError{Reason: ReasonRevokedKey, Err: err}
+}
+
+// ReasonForError returns the reason a transformation failed, or the empty
+// string when the failure is unclassified. KMS provider errors are left
+// unwrapped by the envelope transformers so their gRPC status survives; they
+// are classified here from that status instead.
+func ReasonForError(err error) TransformReason {
+	if err == nil {
+		return ""
+	}
+	var transformError *TransformError
+	if errors.As(err, &transformError) {
+		return transformError.Reason
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return ReasonKMSUnavailable
+	}
+	return ""
+}
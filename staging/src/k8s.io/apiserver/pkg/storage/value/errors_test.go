@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReasonForError(t *testing.T) {
+	testCases := []struct {
+		desc string
+		err  error
+		want TransformReason
+	}{
+		{
+			desc: "nil error",
+			err:  nil,
+			want: "",
+		},
+		{
+			desc: "plain error",
+			err:  fmt.Errorf("some error"),
+			want: "",
+		},
+		{
+			desc: "decryption failed",
+			err:  NewDecryptionFailedError(fmt.Errorf("cipher: message authentication failed")),
+			want: ReasonDecryptionFailed,
+		},
+		{
+			desc: "stale key",
+			err:  NewStaleKeyError(fmt.Errorf("no matching prefix")),
+			want: ReasonStaleKey,
+		},
+		{
+			desc: "wrapped transform error",
+			err:  fmt.Errorf("outer: %w", NewDecryptionFailedError(fmt.Errorf("inner"))),
+			want: ReasonDecryptionFailed,
+		},
+		{
+			desc: "grpc unavailable",
+			err:  status.Error(codes.Unavailable, "connection refused"),
+			want: ReasonKMSUnavailable,
+		},
+		{
+			desc: "grpc deadline exceeded",
+			err:  status.Error(codes.DeadlineExceeded, "timed out"),
+			want: ReasonKMSUnavailable,
+		},
+		{
+			desc: "grpc internal",
+			err:  status.Error(codes.Internal, "plugin error"),
+			want: "",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := ReasonForError(tt.err); got != tt.want {
+				t.Errorf("ReasonForError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
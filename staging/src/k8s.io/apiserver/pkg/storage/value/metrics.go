@@ -20,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"k8s.io/component-base/metrics"
@@ -51,7 +52,7 @@ var (
 			Buckets:        metrics.ExponentialBuckets(5e-6, 2, 25),
 			StabilityLevel: metrics.ALPHA,
 		},
-		[]string{"transformation_type"},
+		[]string{"transformation_type", "transformer_prefix"},
 	)
 
 	transformerOperationsTotal = metrics.NewCounterVec(
@@ -65,6 +66,39 @@ var (
 		[]string{"transformation_type", "transformer_prefix", "status"},
 	)
 
+	transformerErrorsTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "transformation_errors_total",
+			Help:           "Total number of failed transformations, partitioned by the reason of the failure.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"transformation_type", "transformer_prefix", "reason"},
+	)
+
+	decryptErrorsTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "decrypt_errors_total",
+			Help:           "Total number of failed decrypt operations, partitioned by the class of the failure: key_disabled, permission_denied, timeout, unavailable, corrupt_envelope, unknown_prefix, revoked_key or other.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"transformer_prefix", "reason"},
+	)
+
+	plaintextReadsTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "plaintext_reads_total",
+			Help:           "Total number of unencrypted (identity-prefixed) records read from storage for resources configured to be written with an encryption provider, partitioned by the configured write provider prefix.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"transformer_prefix"},
+	)
+
 	envelopeTransformationCacheMissTotal = metrics.NewCounter(
 		&metrics.CounterOpts{
 			Namespace:      namespace,
@@ -103,6 +137,9 @@ func RegisterMetrics() {
 	registerMetrics.Do(func() {
 		legacyregistry.MustRegister(transformerLatencies)
 		legacyregistry.MustRegister(transformerOperationsTotal)
+		legacyregistry.MustRegister(transformerErrorsTotal)
+		legacyregistry.MustRegister(decryptErrorsTotal)
+		legacyregistry.MustRegister(plaintextReadsTotal)
 		legacyregistry.MustRegister(envelopeTransformationCacheMissTotal)
 		legacyregistry.MustRegister(dataKeyGenerationLatencies)
 		legacyregistry.MustRegister(dataKeyGenerationFailuresTotal)
@@ -114,10 +151,50 @@ func RegisterMetrics() {
 func RecordTransformation(transformationType, transformerPrefix string, start time.Time, err error) {
 	transformerOperationsTotal.WithLabelValues(transformationType, transformerPrefix, status.Code(err).String()).Inc()
 
+	if reason := ReasonForError(err); reason != "" {
+		transformerErrorsTotal.WithLabelValues(transformationType, transformerPrefix, string(reason)).Inc()
+	}
+
+	if err != nil && transformationType == "from_storage" {
+		decryptErrorsTotal.WithLabelValues(transformerPrefix, decryptErrorClass(err)).Inc()
+	}
+
 	switch {
 	case err == nil:
-		transformerLatencies.WithLabelValues(transformationType).Observe(sinceInSeconds(start))
+		transformerLatencies.WithLabelValues(transformationType, transformerPrefix).Observe(sinceInSeconds(start))
+	}
+}
+
+// decryptErrorClass buckets a decrypt failure so dashboards can tell a KMS
+// outage apart from unreadable data. gRPC statuses come straight from the KMS
+// plugin; TransformError reasons are assigned by the transformers themselves.
+func decryptErrorClass(err error) string {
+	switch ReasonForError(err) {
+	case ReasonDecryptionFailed:
+		return "corrupt_envelope"
+	case ReasonStaleKey:
+		return "unknown_prefix"
+	case ReasonRevokedKey:
+		return "revoked_key"
 	}
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		return "key_disabled"
+	case codes.PermissionDenied:
+		return "permission_denied"
+	case codes.DeadlineExceeded:
+		return "timeout"
+	case codes.Unavailable:
+		return "unavailable"
+	}
+	return "other"
+}
+
+// RecordPlaintextRead records a read of an unencrypted record for a resource whose
+// configured write provider is a real encryption provider, i.e. a record an
+// encryption migration has not reached yet.
+func RecordPlaintextRead(transformerPrefix string) {
+	plaintextReadsTotal.WithLabelValues(transformerPrefix).Inc()
 }
 
 // RecordCacheMiss records a miss on Key Encryption Key(KEK) - call to KMS was required to decrypt KEK.
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -112,3 +113,60 @@ func TestTotals(t *testing.T) {
 		})
 	}
 }
+
+func TestDecryptErrorsTotal(t *testing.T) {
+	RegisterMetrics()
+	decryptErrorsTotal.Reset()
+	defer decryptErrorsTotal.Reset()
+
+	now := time.Now()
+	RecordTransformation("from_storage", "k8s:enc:kms:v2:", now, status.Error(codes.FailedPrecondition, "key disabled"))
+	RecordTransformation("from_storage", "k8s:enc:kms:v2:", now, status.Error(codes.PermissionDenied, "denied"))
+	RecordTransformation("from_storage", "k8s:enc:kms:v2:", now, status.Error(codes.DeadlineExceeded, "deadline"))
+	RecordTransformation("from_storage", "k8s:enc:aesgcm:v1:", now, NewDecryptionFailedError(errors.New("cipher: message authentication failed")))
+	RecordTransformation("from_storage", "unknown", now, NewStaleKeyError(errors.New("no matching prefix found")))
+	RecordTransformation("from_storage", "k8s:enc:kms:v2:", now, errors.New("something else"))
+	// encrypt failures and successes are not classified
+	RecordTransformation("to_storage", "k8s:enc:kms:v2:", now, status.Error(codes.PermissionDenied, "denied"))
+	RecordTransformation("from_storage", "k8s:enc:kms:v2:", now, nil)
+
+	want := `
+		# HELP apiserver_storage_decrypt_errors_total [ALPHA] Total number of failed decrypt operations, partitioned by the class of the failure: key_disabled, permission_denied, timeout, unavailable, corrupt_envelope, unknown_prefix, revoked_key or other.
+		# TYPE apiserver_storage_decrypt_errors_total counter
+		apiserver_storage_decrypt_errors_total{reason="corrupt_envelope",transformer_prefix="k8s:enc:aesgcm:v1:"} 1
+		apiserver_storage_decrypt_errors_total{reason="key_disabled",transformer_prefix="k8s:enc:kms:v2:"} 1
+		apiserver_storage_decrypt_errors_total{reason="other",transformer_prefix="k8s:enc:kms:v2:"} 1
+		apiserver_storage_decrypt_errors_total{reason="permission_denied",transformer_prefix="k8s:enc:kms:v2:"} 1
+		apiserver_storage_decrypt_errors_total{reason="timeout",transformer_prefix="k8s:enc:kms:v2:"} 1
+		apiserver_storage_decrypt_errors_total{reason="unknown_prefix",transformer_prefix="unknown"} 1
+		`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(want),
+		"apiserver_storage_decrypt_errors_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLatencyPerPrefix(t *testing.T) {
+	okTransformer := PrefixTransformer{Prefix: []byte("k8s:enc:kms:v1:"), Transformer: &testTransformer{from: []byte("value"), to: []byte("value")}}
+	prefix := NewPrefixTransformers(nil, okTransformer)
+
+	RegisterMetrics()
+	transformerLatencies.Reset()
+	defer transformerLatencies.Reset()
+
+	prefix.TransformToStorage(context.Background(), []byte("value"), nil)
+	prefix.TransformFromStorage(context.Background(), []byte("k8s:enc:kms:v1:value"), nil)
+
+	for _, transformationType := range []string{"from_storage", "to_storage"} {
+		hist, err := testutil.GetHistogramVecFromGatherer(legacyregistry.DefaultGatherer, "apiserver_storage_transformation_duration_seconds", map[string]string{
+			"transformation_type": transformationType,
+			"transformer_prefix":  "k8s:enc:kms:v1:",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hist.GetAggregatedSampleCount(); got != 1 {
+			t.Errorf("expected one %s observation for prefix, got %d", transformationType, got)
+		}
+	}
+}
@@ -23,9 +23,20 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/audit"
+	endpointsrequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
+	"k8s.io/component-base/tracing"
 )
 
+// encryptionProviderAuditAnnotationKey is the audit annotation recording the
+// provider prefix that encrypted an object version on its way to storage, so
+// a given object version can be traced back to the key that protected it.
+const encryptionProviderAuditAnnotationKey = "encryption.k8s.io/provider-prefix"
+
 func init() {
 	RegisterMetrics()
 }
@@ -88,6 +99,9 @@ func NewPrefixTransformers(err error, transformers ...PrefixTransformer) Transfo
 // the first transformer.
 func (t *prefixTransformers) TransformFromStorage(ctx context.Context, data []byte, dataCtx Context) ([]byte, bool, error) {
 	start := time.Now()
+	defer func() {
+		trackTransformLatency(ctx, "from_storage", time.Since(start))
+	}()
 	var errs []error
 	for i, transformer := range t.transformers {
 		if bytes.HasPrefix(data, transformer.Prefix) {
@@ -101,6 +115,13 @@ func (t *prefixTransformers) TransformFromStorage(ctx context.Context, data []by
 			}
 			if len(transformer.Prefix) == 0 {
 				RecordTransformation("from_storage", "identity", start, err)
+				// The write provider is a real encryption provider, so this record
+				// should have been encrypted: surface the incomplete migration to
+				// operators and to the requesting client.
+				if writePrefix := t.transformers[0].Prefix; len(writePrefix) != 0 {
+					RecordPlaintextRead(string(writePrefix))
+					warning.AddWarning(ctx, "", fmt.Sprintf("unencrypted record read from storage; it will be encrypted with provider prefix %q on its next write", string(writePrefix)))
+				}
 			} else {
 				RecordTransformation("from_storage", string(transformer.Prefix), start, err)
 			}
@@ -146,21 +167,44 @@ func (t *prefixTransformers) TransformFromStorage(ctx context.Context, data []by
 	if err := errors.Reduce(errors.NewAggregate(errs)); err != nil {
 		return nil, false, err
 	}
-	RecordTransformation("from_storage", "unknown", start, t.err)
-	return nil, false, t.err
+	// data with a prefix that matches no configured transformer was written
+	// under a key or provider that has since been removed from the config.
+	staleKeyErr := NewStaleKeyError(t.err)
+	RecordTransformation("from_storage", "unknown", start, staleKeyErr)
+	return nil, false, staleKeyErr
 }
 
 // TransformToStorage uses the first transformer and adds its prefix to the data.
 func (t *prefixTransformers) TransformToStorage(ctx context.Context, data []byte, dataCtx Context) ([]byte, error) {
 	start := time.Now()
+	defer func() {
+		trackTransformLatency(ctx, "to_storage", time.Since(start))
+	}()
 	transformer := t.transformers[0]
 	result, err := transformer.Transformer.TransformToStorage(ctx, data, dataCtx)
 	RecordTransformation("to_storage", string(transformer.Prefix), start, err)
 	if err != nil {
 		return nil, err
 	}
+	if len(transformer.Prefix) == 0 {
+		audit.AddAuditAnnotation(ctx, encryptionProviderAuditAnnotationKey, "identity")
+	} else {
+		audit.AddAuditAnnotation(ctx, encryptionProviderAuditAnnotationKey, string(transformer.Prefix))
+	}
 	prefixedData := make([]byte, len(transformer.Prefix), len(result)+len(transformer.Prefix))
 	copy(prefixedData, transformer.Prefix)
 	prefixedData = append(prefixedData, result...)
 	return prefixedData, nil
 }
+
+// trackTransformLatency attributes the time spent in the transformer chain to
+// the request, both in its latency trackers and as a trace event, so slow
+// reads and writes can be blamed on decryption rather than on etcd or
+// serialization.
+func trackTransformLatency(ctx context.Context, transformationType string, d time.Duration) {
+	endpointsrequest.TrackStorageTransformLatency(ctx, d)
+	tracing.SpanFromContext(ctx).AddEvent("storage value transformed",
+		attribute.String("transformation_type", transformationType),
+		attribute.Int64("duration_ms", d.Milliseconds()),
+	)
+}
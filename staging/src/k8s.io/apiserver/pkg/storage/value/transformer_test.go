@@ -19,10 +19,14 @@ package value
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/component-base/metrics/testutil"
 )
@@ -70,7 +74,8 @@ func TestPrefixFrom(t *testing.T) {
 	}
 	for i, test := range testCases {
 		got, stale, err := p.TransformFromStorage(context.Background(), test.input, nil)
-		if err != test.err || stale != test.stale || !bytes.Equal(got, test.expect) {
+		// the unmatched prefix error is wrapped to carry the stale key reason.
+		if !errors.Is(err, test.err) || stale != test.stale || !bytes.Equal(got, test.expect) {
 			t.Errorf("%d: unexpected out: %q %t %#v", i, string(got), stale, err)
 			continue
 		}
@@ -193,6 +198,72 @@ func TestPrefixFromMetrics(t *testing.T) {
 	}
 }
 
+type recordingWarningRecorder struct {
+	warnings []string
+}
+
+func (r *recordingWarningRecorder) AddWarning(agent, text string) {
+	r.warnings = append(r.warnings, text)
+}
+
+func TestPrefixFromPlaintextReads(t *testing.T) {
+	testErr := fmt.Errorf("test error")
+	identityTransformer := PrefixTransformer{Prefix: []byte{}, Transformer: &testTransformer{from: []byte("value1")}}
+	otherTransformer := PrefixTransformer{Prefix: []byte("other:"), Transformer: &testTransformer{from: []byte("value1")}}
+
+	testCases := []struct {
+		desc        string
+		input       []byte
+		prefix      Transformer
+		want        string
+		wantWarning bool
+	}{
+		{
+			desc:        "plaintext read with encrypting write provider",
+			input:       []byte("value"),
+			prefix:      NewPrefixTransformers(testErr, otherTransformer, identityTransformer),
+			wantWarning: true,
+			want: `
+	# HELP apiserver_storage_plaintext_reads_total [ALPHA] Total number of unencrypted (identity-prefixed) records read from storage for resources configured to be written with an encryption provider, partitioned by the configured write provider prefix.
+  # TYPE apiserver_storage_plaintext_reads_total counter
+  apiserver_storage_plaintext_reads_total{transformer_prefix="other:"} 1
+  `,
+		},
+		{
+			desc:   "plaintext read with identity write provider",
+			input:  []byte("value"),
+			prefix: NewPrefixTransformers(testErr, identityTransformer, otherTransformer),
+			want:   ``,
+		},
+		{
+			desc:   "encrypted read with encrypting write provider",
+			input:  []byte("other:value"),
+			prefix: NewPrefixTransformers(testErr, otherTransformer, identityTransformer),
+			want:   ``,
+		},
+	}
+
+	RegisterMetrics()
+	plaintextReadsTotal.Reset()
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			recorder := &recordingWarningRecorder{}
+			ctx := warning.WithWarningRecorder(context.Background(), recorder)
+			if _, _, err := tc.prefix.TransformFromStorage(ctx, tc.input, nil); err != nil {
+				t.Fatal(err)
+			}
+			defer plaintextReadsTotal.Reset()
+			if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(tc.want), "apiserver_storage_plaintext_reads_total"); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(recorder.warnings) > 0; got != tc.wantWarning {
+				t.Errorf("got warnings %v, wanted a warning: %t", recorder.warnings, tc.wantWarning)
+			}
+		})
+	}
+}
+
 func TestPrefixToMetrics(t *testing.T) {
 	testErr := fmt.Errorf("test error")
 	transformerErr := fmt.Errorf("test error")
@@ -250,3 +321,19 @@ func TestPrefixToMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformToStorageAuditAnnotation(t *testing.T) {
+	prefix := "k8s:enc:kms:v1:panda:"
+	transformer := NewPrefixTransformers(nil, PrefixTransformer{Prefix: []byte(prefix), Transformer: &testTransformer{to: []byte("value")}})
+
+	ctx := audit.WithAuditContext(context.Background())
+	ac := audit.AuditContextFrom(ctx)
+	ac.Event = &auditinternal.Event{Level: auditinternal.LevelMetadata}
+
+	if _, err := transformer.TransformToStorage(ctx, []byte("value"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := ac.Event.Annotations["encryption.k8s.io/provider-prefix"]; got != prefix {
+		t.Errorf("expected provider prefix audit annotation %q, got %q", prefix, got)
+	}
+}
@@ -26,6 +26,9 @@ import (
 // with apply.
 type PodReadinessGateApplyConfiguration struct {
 	ConditionType *v1.PodConditionType `json:"conditionType,omitempty"`
+	DefaultStatus *v1.ConditionStatus  `json:"defaultStatus,omitempty"`
+	ExpirySeconds *int64               `json:"expirySeconds,omitempty"`
+	ExpiryStatus  *v1.ConditionStatus  `json:"expiryStatus,omitempty"`
 }
 
 // PodReadinessGateApplyConfiguration constructs an declarative configuration of the PodReadinessGate type for use with
@@ -41,3 +44,27 @@ func (b *PodReadinessGateApplyConfiguration) WithConditionType(value v1.PodCondi
 	b.ConditionType = &value
 	return b
 }
+
+// WithDefaultStatus sets the DefaultStatus field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DefaultStatus field is set to the value of the last call.
+func (b *PodReadinessGateApplyConfiguration) WithDefaultStatus(value v1.ConditionStatus) *PodReadinessGateApplyConfiguration {
+	b.DefaultStatus = &value
+	return b
+}
+
+// WithExpirySeconds sets the ExpirySeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ExpirySeconds field is set to the value of the last call.
+func (b *PodReadinessGateApplyConfiguration) WithExpirySeconds(value int64) *PodReadinessGateApplyConfiguration {
+	b.ExpirySeconds = &value
+	return b
+}
+
+// WithExpiryStatus sets the ExpiryStatus field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ExpiryStatus field is set to the value of the last call.
+func (b *PodReadinessGateApplyConfiguration) WithExpiryStatus(value v1.ConditionStatus) *PodReadinessGateApplyConfiguration {
+	b.ExpiryStatus = &value
+	return b
+}
@@ -5893,6 +5893,15 @@ var schemaYAML = typed.YAMLObject(`types:
       type:
         scalar: string
       default: ""
+    - name: defaultStatus
+      type:
+        scalar: string
+    - name: expirySeconds
+      type:
+        scalar: numeric
+    - name: expiryStatus
+      type:
+        scalar: string
 - name: io.k8s.api.core.v1.PodResourceClaim
   map:
     fields:
@@ -11631,6 +11640,9 @@ var schemaYAML = typed.YAMLObject(`types:
 - name: io.k8s.api.resource.v1alpha1.ResourceClaimSpec
   map:
     fields:
+    - name: adminAccess
+      type:
+        scalar: boolean
     - name: allocationMode
       type:
         scalar: string
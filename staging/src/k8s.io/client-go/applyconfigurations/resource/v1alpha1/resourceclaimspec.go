@@ -28,6 +28,7 @@ type ResourceClaimSpecApplyConfiguration struct {
 	ResourceClassName *string                                             `json:"resourceClassName,omitempty"`
 	ParametersRef     *ResourceClaimParametersReferenceApplyConfiguration `json:"parametersRef,omitempty"`
 	AllocationMode    *resourcev1alpha1.AllocationMode                    `json:"allocationMode,omitempty"`
+	AdminAccess       *bool                                               `json:"adminAccess,omitempty"`
 }
 
 // ResourceClaimSpecApplyConfiguration constructs an declarative configuration of the ResourceClaimSpec type for use with
@@ -59,3 +60,11 @@ func (b *ResourceClaimSpecApplyConfiguration) WithAllocationMode(value resourcev
 	b.AllocationMode = &value
 	return b
 }
+
+// WithAdminAccess sets the AdminAccess field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AdminAccess field is set to the value of the last call.
+func (b *ResourceClaimSpecApplyConfiguration) WithAdminAccess(value bool) *ResourceClaimSpecApplyConfiguration {
+	b.AdminAccess = &value
+	return b
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package testing
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"sort"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +37,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/watch"
 	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
 )
 
 // ObjectTracker keeps track of objects. It is intended to be used to
@@ -54,6 +59,13 @@ type ObjectTracker interface {
 	// Update updates an existing object in the tracker in the specified namespace.
 	Update(gvr schema.GroupVersionResource, obj runtime.Object, ns string) error
 
+	// Apply applies applyConfiguration to the object specified by gvr and the
+	// configuration's name, following server-side apply semantics: the object
+	// is created if it does not exist, fields applied earlier but absent from
+	// applyConfiguration are removed, and field ownership is recorded in the
+	// object's managedFields.
+	Apply(gvr schema.GroupVersionResource, applyConfiguration runtime.Object, ns string) error
+
 	// List retrieves all objects of a given kind in the given
 	// namespace. Only non-List kinds are accepted.
 	List(gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, ns string) (runtime.Object, error)
@@ -143,6 +155,19 @@ func ObjectReaction(tracker ObjectTracker) ReactionFunc {
 			return true, nil, nil
 
 		case PatchActionImpl:
+			if action.GetPatchType() == types.ApplyPatchType {
+				applyConfiguration := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				if err := json.Unmarshal(action.GetPatch(), &applyConfiguration.Object); err != nil {
+					return true, nil, err
+				}
+				applyConfiguration.SetName(action.GetName())
+				if err := tracker.Apply(gvr, applyConfiguration, ns); err != nil {
+					return true, nil, err
+				}
+				obj, err := tracker.Get(gvr, ns, action.GetName())
+				return true, obj, err
+			}
+
 			obj, err := tracker.Get(gvr, ns, action.GetName())
 			if err != nil {
 				return true, nil, err
@@ -181,7 +206,7 @@ func ObjectReaction(tracker ObjectTracker) ReactionFunc {
 				if err := json.Unmarshal(modified, obj); err != nil {
 					return true, nil, err
 				}
-			case types.StrategicMergePatchType, types.ApplyPatchType:
+			case types.StrategicMergePatchType:
 				mergedByte, err := strategicpatch.StrategicMergePatch(old, action.GetPatch(), obj)
 				if err != nil {
 					return true, nil, err
@@ -360,6 +385,177 @@ func (t *tracker) Update(gvr schema.GroupVersionResource, obj runtime.Object, ns
 	return t.add(gvr, obj, ns, true)
 }
 
+// FieldManager is the field manager all apply operations through the fake
+// clientset are attributed to. The fake actions do not carry the caller's
+// field manager name, so every apply shares this one and conflicts between
+// appliers cannot occur; conflicts with field owners recorded directly on
+// tracked objects are forced.
+const FieldManager = "fake-client-side-apply"
+
+func (t *tracker) Apply(gvr schema.GroupVersionResource, applyConfiguration runtime.Object, ns string) error {
+	applyConfigurationMeta, err := meta.Accessor(applyConfiguration)
+	if err != nil {
+		return err
+	}
+
+	var live runtime.Object
+	obj, err := t.Get(gvr, ns, applyConfigurationMeta.GetName())
+	switch {
+	case err == nil:
+		live = obj
+	case errors.IsNotFound(err):
+		// apply creates the object if it does not exist yet.
+	default:
+		return err
+	}
+
+	// managedFields are bookkeeping, not part of the merged content.
+	liveMap := map[string]interface{}{}
+	var managedFields []metav1.ManagedFieldsEntry
+	if live != nil {
+		liveMeta, err := meta.Accessor(live)
+		if err != nil {
+			return err
+		}
+		managedFields = liveMeta.GetManagedFields()
+		liveMeta.SetManagedFields(nil)
+		if liveMap, err = runtime.DefaultUnstructuredConverter.ToUnstructured(live); err != nil {
+			return err
+		}
+	}
+	configMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(applyConfiguration)
+	if err != nil {
+		return err
+	}
+	if metadata, ok := configMap["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "managedFields")
+	}
+
+	// the tracker has no openapi schema to build real types from, so merge with
+	// deduced types: maps merge recursively and lists are replaced wholesale.
+	liveTyped, err := typed.DeducedParseableType.FromUnstructured(liveMap)
+	if err != nil {
+		return err
+	}
+	configTyped, err := typed.DeducedParseableType.FromUnstructured(configMap)
+	if err != nil {
+		return err
+	}
+	managers, err := decodeManagedFields(managedFields)
+	if err != nil {
+		return err
+	}
+
+	updater := merge.Updater{Converter: deducedConverter{}}
+	version := fieldpath.APIVersion(gvr.GroupVersion().String())
+	mergedTyped, managers, err := updater.Apply(liveTyped, configTyped, version, managers, FieldManager, true)
+	if err != nil {
+		return err
+	}
+	if mergedTyped == nil {
+		// no changes, but field ownership may still have moved.
+		mergedTyped = liveTyped
+	}
+	mergedMap, ok := mergedTyped.AsValue().Unstructured().(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("merged apply result is not an object")
+	}
+
+	var result runtime.Object
+	if live != nil {
+		result = reflect.New(reflect.TypeOf(live).Elem()).Interface().(runtime.Object)
+	} else {
+		gvk := applyConfiguration.GetObjectKind().GroupVersionKind()
+		if result, err = t.scheme.New(gvk); err != nil {
+			if !runtime.IsNotRegisteredError(err) {
+				return err
+			}
+			// unstructured trackers do not register their kinds in the scheme.
+			result = &unstructured.Unstructured{}
+		}
+	}
+	if resultUnstructured, ok := result.(*unstructured.Unstructured); ok {
+		resultUnstructured.Object = mergedMap
+	} else if err := runtime.DefaultUnstructuredConverter.FromUnstructured(mergedMap, result); err != nil {
+		return err
+	}
+
+	entries, err := encodeManagedFields(managers)
+	if err != nil {
+		return err
+	}
+	resultMeta, err := meta.Accessor(result)
+	if err != nil {
+		return err
+	}
+	resultMeta.SetManagedFields(entries)
+
+	return t.add(gvr, result, ns, live != nil)
+}
+
+// deducedConverter satisfies merge.Converter for deduced types, which carry no
+// version specific structure: objects are used as is for every version.
+type deducedConverter struct{}
+
+func (deducedConverter) Convert(object *typed.TypedValue, version fieldpath.APIVersion) (*typed.TypedValue, error) {
+	return object, nil
+}
+
+func (deducedConverter) IsMissingVersionError(error) bool { return false }
+
+// decodeManagedFields converts managedFields entries stored on an object into
+// the structured-merge-diff bookkeeping format.
+func decodeManagedFields(entries []metav1.ManagedFieldsEntry) (fieldpath.ManagedFields, error) {
+	managers := fieldpath.ManagedFields{}
+	for _, entry := range entries {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("error decoding managed fields for manager %q: %w", entry.Manager, err)
+		}
+		managers[entry.Manager] = fieldpath.NewVersionedSet(set, fieldpath.APIVersion(entry.APIVersion), entry.Operation == metav1.ManagedFieldsOperationApply)
+	}
+	return managers, nil
+}
+
+// encodeManagedFields converts structured-merge-diff bookkeeping back into the
+// managedFields entries stored on an object.
+func encodeManagedFields(managers fieldpath.ManagedFields) ([]metav1.ManagedFieldsEntry, error) {
+	if len(managers) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(managers))
+	for name := range managers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := metav1.Now()
+	entries := make([]metav1.ManagedFieldsEntry, 0, len(managers))
+	for _, name := range names {
+		versionedSet := managers[name]
+		fieldsJSON, err := versionedSet.Set().ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("error encoding managed fields for manager %q: %w", name, err)
+		}
+		operation := metav1.ManagedFieldsOperationUpdate
+		if versionedSet.Applied() {
+			operation = metav1.ManagedFieldsOperationApply
+		}
+		entries = append(entries, metav1.ManagedFieldsEntry{
+			Manager:    name,
+			Operation:  operation,
+			APIVersion: string(versionedSet.APIVersion()),
+			Time:       &now,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: fieldsJSON},
+		})
+	}
+	return entries, nil
+}
+
 func (t *tracker) getWatches(gvr schema.GroupVersionResource, ns string) []*watch.RaceFreeFakeWatcher {
 	watches := []*watch.RaceFreeFakeWatcher{}
 	if t.watchers[gvr] != nil {
@@ -27,6 +27,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -275,6 +276,46 @@ func TestPatchWithMissingObject(t *testing.T) {
 	assert.EqualError(t, err, `nodes "node-1" not found`)
 }
 
+func TestApplyPatch(t *testing.T) {
+	configMapsResource := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
+
+	scheme := runtime.NewScheme()
+	codecs := serializer.NewCodecFactory(scheme)
+	o := NewObjectTracker(scheme, codecs.UniversalDecoder())
+	reaction := ObjectReaction(o)
+
+	// an apply against a missing object creates it.
+	apply := []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm-1"}, "data": {"a": "1", "b": "2"}}`)
+	handled, obj, err := reaction(NewRootPatchAction(configMapsResource, "cm-1", types.ApplyPatchType, apply))
+	assert.True(t, handled)
+	assert.NoError(t, err)
+
+	cm := obj.(*unstructured.Unstructured)
+	data, _, err := unstructured.NestedStringMap(cm.Object, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, data)
+
+	// field ownership is recorded in managedFields.
+	accessor, err := meta.Accessor(obj)
+	assert.NoError(t, err)
+	managedFields := accessor.GetManagedFields()
+	if assert.Len(t, managedFields, 1) {
+		assert.Equal(t, FieldManager, managedFields[0].Manager)
+		assert.Equal(t, metav1.ManagedFieldsOperationApply, managedFields[0].Operation)
+	}
+
+	// fields applied earlier but omitted from a later apply are removed.
+	apply = []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm-1"}, "data": {"a": "3"}}`)
+	handled, obj, err = reaction(NewRootPatchAction(configMapsResource, "cm-1", types.ApplyPatchType, apply))
+	assert.True(t, handled)
+	assert.NoError(t, err)
+
+	cm = obj.(*unstructured.Unstructured)
+	data, _, err = unstructured.NestedStringMap(cm.Object, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "3"}, data)
+}
+
 func TestGetWithExactMatch(t *testing.T) {
 	scheme := runtime.NewScheme()
 	codecs := serializer.NewCodecFactory(scheme)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Histogram is our internal representation for our wrapping struct around prometheus
@@ -203,12 +204,35 @@ type HistogramVecWithContext struct {
 	ctx context.Context
 }
 
-// WithLabelValues is the wrapper of HistogramVec.WithLabelValues.
+// WithLabelValues is the wrapper of HistogramVec.WithLabelValues. Samples
+// observed through the returned ObserverMetric carry the trace ID of the
+// context's span as an exemplar when that span is sampled.
 func (vc *HistogramVecWithContext) WithLabelValues(lvs ...string) ObserverMetric {
-	return vc.HistogramVec.WithLabelValues(lvs...)
+	return exemplarObserver{vc.HistogramVec.WithLabelValues(lvs...), vc.ctx}
 }
 
-// With is the wrapper of HistogramVec.With.
+// With is the wrapper of HistogramVec.With. Samples observed through the
+// returned ObserverMetric carry the trace ID of the context's span as an
+// exemplar when that span is sampled.
 func (vc *HistogramVecWithContext) With(labels map[string]string) ObserverMetric {
-	return vc.HistogramVec.With(labels)
+	return exemplarObserver{vc.HistogramVec.With(labels), vc.ctx}
+}
+
+// exemplarObserver attaches the trace ID of a sampled span as an exemplar to
+// every observed sample, linking a latency spike on a dashboard to a trace of
+// the request that caused it. Without a sampled span in the context it
+// observes plainly.
+type exemplarObserver struct {
+	ObserverMetric
+	ctx context.Context
+}
+
+func (o exemplarObserver) Observe(v float64) {
+	if spanContext := trace.SpanContextFromContext(o.ctx); spanContext.IsSampled() {
+		if observer, ok := o.ObserverMetric.(prometheus.ExemplarObserver); ok {
+			observer.ObserveWithExemplar(v, prometheus.Labels{"trace_id": spanContext.TraceID().String()})
+			return
+		}
+	}
+	o.ObserverMetric.Observe(v)
 }
@@ -17,11 +17,13 @@ limitations under the License.
 package metrics
 
 import (
+	"context"
 	"testing"
 
 	"github.com/blang/semver/v4"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 
 	apimachineryversion "k8s.io/apimachinery/pkg/version"
 )
@@ -311,3 +313,49 @@ func TestHistogramWithLabelValueAllowList(t *testing.T) {
 		})
 	}
 }
+
+func TestHistogramVecWithContextExemplar(t *testing.T) {
+	registry := newKubeRegistry(apimachineryversion.Info{
+		Major:      "1",
+		Minor:      "15",
+		GitVersion: "v1.15.0-alpha-1.12345",
+	})
+	c := NewHistogramVec(&HistogramOpts{
+		Namespace: "namespace",
+		Name:      "metric_exemplar_test_name",
+		Subsystem: "subsystem",
+		Help:      "histogram help message",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"label_a"})
+	registry.MustRegister(c)
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	sampledCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	c.WithContext(sampledCtx).WithLabelValues("sampled").Observe(1.0)
+	c.WithContext(context.Background()).WithLabelValues("unsampled").Observe(1.0)
+
+	ms, err := registry.Gather()
+	assert.Nil(t, err, "Gather failed %v", err)
+	assert.Equalf(t, 1, len(ms), "Got %v metrics, Want: 1 metric", len(ms))
+
+	for _, m := range ms[0].GetMetric() {
+		var exemplar string
+		for _, bucket := range m.GetHistogram().GetBucket() {
+			for _, pair := range bucket.GetExemplar().GetLabel() {
+				if pair.GetName() == "trace_id" {
+					exemplar = pair.GetValue()
+				}
+			}
+		}
+		switch m.GetLabel()[0].GetValue() {
+		case "sampled":
+			assert.Equalf(t, traceID.String(), exemplar, "Got exemplar trace ID %q, want %q", exemplar, traceID.String())
+		case "unsampled":
+			assert.Emptyf(t, exemplar, "Got unexpected exemplar trace ID %q for an unsampled context", exemplar)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterproperty contains helpers for the About API, the standard
+// identity surface for multi-cluster tooling. The ClusterProperty resource is
+// a cluster-scoped name/value pair; the well-known "cluster.id" and
+// "clusterset" properties identify a cluster and its clusterset membership to
+// ServiceExport-style consumers.
+package clusterproperty
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the API group of the ClusterProperty resource.
+	GroupName = "cluster.clusterset.k8s.io"
+	// Version is the API version of the ClusterProperty resource.
+	Version = "v1alpha1"
+
+	// ClusterIDPropertyName is the well-known property carrying the unique
+	// identifier of the cluster. Its value must remain stable for the
+	// lifetime of the cluster's clusterset membership.
+	ClusterIDPropertyName = "cluster.id"
+	// ClusterSetPropertyName is the well-known property carrying the name of
+	// the clusterset this cluster is a member of.
+	ClusterSetPropertyName = "clusterset"
+)
+
+// ClusterPropertyResource is the group/version/resource of the ClusterProperty
+// resource, for use with dynamic clients.
+var ClusterPropertyResource = schema.GroupVersionResource{
+	Group:    GroupName,
+	Version:  Version,
+	Resource: "clusterproperties",
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterproperty
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxClusterPropertyValueLength bounds the value of properties that are not
+// well known. Well-known property values have stricter, format-specific
+// validation.
+const maxClusterPropertyValueLength = 1024
+
+// ValidateClusterPropertyName validates the name of a ClusterProperty.
+// Property names are DNS-1123 subdomains so that they can be safely embedded
+// in DNS records and label values by multi-cluster tooling.
+func ValidateClusterPropertyName(name string) []string {
+	return validation.IsDNS1123Subdomain(name)
+}
+
+// ValidateClusterPropertyValue validates the value of a ClusterProperty with
+// the given name. The well-known "cluster.id" and "clusterset" values must be
+// DNS-1123 subdomains because consumers embed them in clusterset DNS names;
+// other values are only bounded in length.
+func ValidateClusterPropertyValue(name, value string) []string {
+	switch name {
+	case ClusterIDPropertyName, ClusterSetPropertyName:
+		return validation.IsDNS1123Subdomain(value)
+	default:
+		var errs []string
+		if len(value) == 0 {
+			errs = append(errs, "must not be empty")
+		}
+		if len(value) > maxClusterPropertyValueLength {
+			errs = append(errs, fmt.Sprintf("must be no more than %d characters", maxClusterPropertyValueLength))
+		}
+		return errs
+	}
+}
+
+// ValidateServiceExportName validates that a Service name is exportable to a
+// clusterset. Clusterset DNS names (<service>.<ns>.svc.clusterset.local)
+// require RFC-1035 labels, which is stricter than what Services themselves
+// allow, so ServiceExport-style consumers should reject names that fail here.
+func ValidateServiceExportName(name string) []string {
+	return validation.IsDNS1035Label(name)
+}
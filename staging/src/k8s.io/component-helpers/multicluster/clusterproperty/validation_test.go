@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterproperty
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateClusterPropertyName(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectValid bool
+	}{
+		{ClusterIDPropertyName, true},
+		{ClusterSetPropertyName, true},
+		{"vendor.example.com/region", false},
+		{"vendor.example.com", true},
+		{"", false},
+		{"UpperCase", false},
+		{strings.Repeat("a", 254), false},
+	}
+	for _, test := range tests {
+		errs := ValidateClusterPropertyName(test.name)
+		if valid := len(errs) == 0; valid != test.expectValid {
+			t.Errorf("ValidateClusterPropertyName(%q) = %v, expected valid=%t", test.name, errs, test.expectValid)
+		}
+	}
+}
+
+func TestValidateClusterPropertyValue(t *testing.T) {
+	tests := []struct {
+		propertyName string
+		value        string
+		expectValid  bool
+	}{
+		{ClusterIDPropertyName, "cluster-1", true},
+		{ClusterIDPropertyName, "7e86b1b2-39f4-4a54-9a60-6c4c1d1e1a2f", true},
+		{ClusterIDPropertyName, "", false},
+		{ClusterIDPropertyName, "Not_A_Subdomain", false},
+		{ClusterSetPropertyName, "production-clusterset", true},
+		{ClusterSetPropertyName, strings.Repeat("a", 254), false},
+		{"vendor.example.com", "anything goes here", true},
+		{"vendor.example.com", "", false},
+		{"vendor.example.com", strings.Repeat("a", maxClusterPropertyValueLength+1), false},
+	}
+	for _, test := range tests {
+		errs := ValidateClusterPropertyValue(test.propertyName, test.value)
+		if valid := len(errs) == 0; valid != test.expectValid {
+			t.Errorf("ValidateClusterPropertyValue(%q, %q) = %v, expected valid=%t", test.propertyName, test.value, errs, test.expectValid)
+		}
+	}
+}
+
+func TestValidateServiceExportName(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectValid bool
+	}{
+		{"my-service", true},
+		{"1numeric-start", false},
+		{"", false},
+		{strings.Repeat("a", 64), false},
+	}
+	for _, test := range tests {
+		errs := ValidateServiceExportName(test.name)
+		if valid := len(errs) == 0; valid != test.expectValid {
+			t.Errorf("ValidateServiceExportName(%q) = %v, expected valid=%t", test.name, errs, test.expectValid)
+		}
+	}
+}
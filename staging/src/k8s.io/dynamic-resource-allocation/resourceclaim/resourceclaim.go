@@ -75,7 +75,10 @@ func IsReservedForPod(pod *v1.Pod, claim *resourcev1alpha1.ResourceClaim) bool {
 }
 
 // CanBeReserved checks whether the claim could be reserved for another object.
+// Claims with admin access can always be reserved for additional consumers,
+// regardless of whether the allocated resource is shareable.
 func CanBeReserved(claim *resourcev1alpha1.ResourceClaim) bool {
-	return claim.Status.Allocation.Shareable ||
+	return claim.Spec.AdminAccess ||
+		claim.Status.Allocation.Shareable ||
 		len(claim.Status.ReservedFor) == 0
 }
@@ -137,6 +137,10 @@ type KubeProxyConfiguration struct {
 	// metricsBindAddress is the IP address and port for the metrics server to serve on,
 	// defaulting to 127.0.0.1:10249 (set to 0.0.0.0 for all interfaces)
 	MetricsBindAddress string `json:"metricsBindAddress"`
+	// programmingBindAddress is the IP address and port for the service
+	// programming API to serve on, exposing the joined service, endpoint slice
+	// and node topology view to external dataplanes. Empty disables the API.
+	ProgrammingBindAddress string `json:"programmingBindAddress,omitempty"`
 	// bindAddressHardFail, if true, kube-proxy will treat failure to bind to a port as fatal and exit
 	BindAddressHardFail bool `json:"bindAddressHardFail"`
 	// enableProfiling enables profiling via web interface on /debug/pprof handler.
@@ -162,6 +162,30 @@ type KubeSchedulerProfile struct {
 	// +listType=map
 	// +listMapKey=name
 	PluginConfig []PluginConfig `json:"pluginConfig,omitempty"`
+
+	// PluginExecutionBudgets is an optional list of per-plugin execution time
+	// budgets. A plugin whose Filter or Score calls repeatedly exceed its
+	// budget can have its Score calls temporarily skipped, protecting
+	// scheduling throughput from a single slow plugin. Filter calls are never
+	// skipped, because they enforce hard constraints.
+	// +listType=map
+	// +listMapKey=name
+	PluginExecutionBudgets []PluginExecutionBudget `json:"pluginExecutionBudgets,omitempty"`
+}
+
+// PluginExecutionBudget is an execution time budget for a single plugin.
+type PluginExecutionBudget struct {
+	// Name is the name of the plugin being budgeted.
+	Name string `json:"name"`
+	// Budget is the maximum duration a single Filter or Score call of the
+	// plugin is expected to take.
+	Budget metav1.Duration `json:"budget"`
+	// MaxConsecutiveOverruns is the number of consecutive calls exceeding
+	// Budget after which the plugin is temporarily skipped. Zero means the
+	// budget is record-only: overruns are surfaced in metrics but the plugin
+	// always runs.
+	// +optional
+	MaxConsecutiveOverruns int32 `json:"maxConsecutiveOverruns,omitempty"`
 }
 
 // Plugins include multiple extension points. When specified, the list of plugins for
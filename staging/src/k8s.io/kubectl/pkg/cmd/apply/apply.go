@@ -70,6 +70,10 @@ type ApplyFlags struct {
 	Overwrite      bool
 	OpenAPIPatch   bool
 
+	Render           bool
+	RenderOnly       bool
+	RenderConfigMaps []string
+
 	// DEPRECATED: Use PruneAllowlist instead
 	PruneWhitelist []string // TODO: Remove this in kubectl 1.28 or later
 	PruneAllowlist []string
@@ -99,6 +103,8 @@ type ApplyOptions struct {
 	All                     bool
 	Overwrite               bool
 	OpenAPIPatch            bool
+	Renderer                *manifestRenderer
+	RenderOnly              bool
 
 	ValidationDirective string
 	Validator           validation.Schema
@@ -236,6 +242,9 @@ func (flags *ApplyFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringArrayVar(&flags.PruneWhitelist, "prune-whitelist", flags.PruneWhitelist, "Overwrite the default whitelist with <group/version/kind> for --prune") // TODO: Remove this in kubectl 1.28 or later
 	cmd.Flags().MarkDeprecated("prune-whitelist", "Use --prune-allowlist instead.")
 	cmd.Flags().BoolVar(&flags.OpenAPIPatch, "openapi-patch", flags.OpenAPIPatch, "If true, use openapi to calculate diff when the openapi presents and the resource can be found in the openapi spec. Otherwise, fall back to use baked-in types.")
+	cmd.Flags().BoolVar(&flags.Render, "render", flags.Render, "If true, substitute $(cluster.<value>) and $(configmap.<name>.<key>) expressions in the manifests before applying them. Can be combined with --dry-run=server to validate the rendered result.")
+	cmd.Flags().BoolVar(&flags.RenderOnly, "render-only", flags.RenderOnly, "If true, print the rendered manifests without applying them. Implies --render.")
+	cmd.Flags().StringArrayVar(&flags.RenderConfigMaps, "render-configmap", flags.RenderConfigMaps, "Config map ([namespace/]name) whose data keys are made available to --render as $(configmap.<name>.<key>). Can be specified multiple times.")
 }
 
 // ToOptions converts from CLI inputs to runtime inputs
@@ -312,6 +321,25 @@ func (flags *ApplyFlags) ToOptions(cmd *cobra.Command, baseName string, args []s
 		}
 	}
 
+	if len(flags.RenderConfigMaps) > 0 && !flags.Render && !flags.RenderOnly {
+		return nil, cmdutil.UsageErrorf(cmd, "--render-configmap requires --render or --render-only")
+	}
+	var renderer *manifestRenderer
+	if flags.Render || flags.RenderOnly {
+		discoveryClient, err := flags.Factory.ToDiscoveryClient()
+		if err != nil {
+			return nil, err
+		}
+		clientSet, err := flags.Factory.KubernetesClientSet()
+		if err != nil {
+			return nil, err
+		}
+		renderer, err = newManifestRenderer(discoveryClient, clientSet, namespace, flags.RenderConfigMaps)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	o := &ApplyOptions{
 		// 	Store baseName for use in printing warnings / messages involving the base command name.
 		// 	This is useful for downstream command that wrap this one.
@@ -332,6 +360,8 @@ func (flags *ApplyFlags) ToOptions(cmd *cobra.Command, baseName string, args []s
 		All:             flags.All,
 		Overwrite:       flags.Overwrite,
 		OpenAPIPatch:    flags.OpenAPIPatch,
+		Renderer:        renderer,
+		RenderOnly:      flags.RenderOnly,
 
 		Recorder:            recorder,
 		Namespace:           namespace,
@@ -457,6 +487,22 @@ func (o *ApplyOptions) Run() error {
 	if len(infos) == 0 && len(errs) == 0 {
 		return fmt.Errorf("no objects passed to apply")
 	}
+	if o.Renderer != nil {
+		for _, info := range infos {
+			if err := o.Renderer.RenderInfo(info); err != nil {
+				return err
+			}
+		}
+		if o.RenderOnly {
+			printer := &printers.YAMLPrinter{}
+			for _, info := range infos {
+				if err := printer.PrintObj(info.Object, o.Out); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
 	// Iterate through all objects, applying each one.
 	for _, info := range infos {
 		if err := o.applyOneObject(info); err != nil {
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+)
+
+// renderExpression matches the restricted expression syntax understood by
+// --render: $(cluster.<value>) and $(configmap.<name>.<key>). The mandatory
+// prefixes keep the syntax from colliding with container environment variable
+// references like $(VAR), which pass through untouched.
+var renderExpression = regexp.MustCompile(`\$\((cluster|configmap)\.[^)]*\)`)
+
+// manifestRenderer substitutes the restricted expression set in manifests
+// before they are applied. All inputs are resolved up front, so rendering
+// itself cannot fail halfway through a multi-document apply because the
+// cluster became unreachable.
+type manifestRenderer struct {
+	// values maps expression bodies, e.g. "cluster.serverVersion" or
+	// "configmap.app-config.replicas", to their resolved values.
+	values map[string]string
+}
+
+// newManifestRenderer fetches the cluster values from discovery and the data
+// of the declared ConfigMap inputs ([namespace/]name, defaulting to the
+// effective namespace of the apply).
+func newManifestRenderer(discoveryClient discovery.DiscoveryInterface, client kubernetes.Interface, namespace string, configMaps []string) (*manifestRenderer, error) {
+	serverVersion, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch server version for rendering: %w", err)
+	}
+	values := map[string]string{
+		"cluster.serverVersion":      serverVersion.GitVersion,
+		"cluster.serverMajorVersion": serverVersion.Major,
+		"cluster.serverMinorVersion": serverVersion.Minor,
+		"cluster.namespace":          namespace,
+	}
+
+	for _, input := range configMaps {
+		cmNamespace, cmName := namespace, input
+		if parts := strings.SplitN(input, "/", 2); len(parts) == 2 {
+			cmNamespace, cmName = parts[0], parts[1]
+		}
+		configMap, err := client.CoreV1().ConfigMaps(cmNamespace).Get(context.TODO(), cmName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch render input config map %q: %w", input, err)
+		}
+		for key, value := range configMap.Data {
+			values[fmt.Sprintf("configmap.%s.%s", cmName, key)] = value
+		}
+	}
+
+	return &manifestRenderer{values: values}, nil
+}
+
+// RenderInfo substitutes all expressions in the string fields of the object
+// held by info, in place.
+func (r *manifestRenderer) RenderInfo(info *resource.Info) error {
+	obj, ok := info.Object.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("cannot render non-unstructured object %s", info.String())
+	}
+	if err := r.render(obj.Object); err != nil {
+		return fmt.Errorf("error rendering %s: %w", info.String(), err)
+	}
+	return nil
+}
+
+// render walks the object tree and substitutes expressions in every string
+// value it finds. Map keys are left alone.
+func (r *manifestRenderer) render(obj map[string]interface{}) error {
+	_, err := r.renderAny(obj)
+	return err
+}
+
+func (r *manifestRenderer) renderAny(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case string:
+		return r.renderString(value)
+	case map[string]interface{}:
+		for key, element := range value {
+			rendered, err := r.renderAny(element)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = rendered
+		}
+		return value, nil
+	case []interface{}:
+		for i, element := range value {
+			rendered, err := r.renderAny(element)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = rendered
+		}
+		return value, nil
+	default:
+		return v, nil
+	}
+}
+
+// renderString substitutes the expressions in s. An expression using one of
+// the restricted prefixes that does not resolve to a known value is an error
+// rather than a silent pass-through, so a typo cannot reach the server.
+func (r *manifestRenderer) renderString(s string) (string, error) {
+	var unknown []string
+	out := renderExpression.ReplaceAllStringFunc(s, func(match string) string {
+		name := strings.TrimSuffix(strings.TrimPrefix(match, "$("), ")")
+		if value, ok := r.values[name]; ok {
+			return value
+		}
+		unknown = append(unknown, match)
+		return match
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown render expression %s; cluster values are limited to the documented set and config map inputs must be declared with --render-configmap", strings.Join(unknown, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRenderSubstitution(t *testing.T) {
+	renderer := &manifestRenderer{values: map[string]string{
+		"cluster.namespace":          "test",
+		"configmap.app-config.image": "registry.example/app:v2",
+	}}
+
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "app-$(cluster.namespace)",
+			"namespace": "$(cluster.namespace)",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"containers": []interface{}{
+				map[string]interface{}{
+					"image": "$(configmap.app-config.image)",
+					"env": []interface{}{
+						map[string]interface{}{
+							"name":  "POD_NAME",
+							"value": "$(POD_NAME)",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := renderer.render(obj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "app-test",
+			"namespace": "test",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"containers": []interface{}{
+				map[string]interface{}{
+					"image": "registry.example/app:v2",
+					"env": []interface{}{
+						map[string]interface{}{
+							"name": "POD_NAME",
+							// container environment references do not use the
+							// restricted prefixes and must pass through untouched
+							"value": "$(POD_NAME)",
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("unexpected rendered object:\ngot:  %#v\nwant: %#v", obj, expected)
+	}
+}
+
+func TestRenderUnknownExpression(t *testing.T) {
+	renderer := &manifestRenderer{values: map[string]string{
+		"cluster.namespace": "test",
+	}}
+
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "$(configmap.missing.image)",
+		},
+	}
+	err := renderer.render(obj)
+	if err == nil {
+		t.Fatal("expected an error for an undeclared expression, got none")
+	}
+	if !strings.Contains(err.Error(), "$(configmap.missing.image)") {
+		t.Errorf("expected the error to name the unknown expression, got %v", err)
+	}
+}
+
+func TestNewManifestRenderer(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "test"},
+			Data:       map[string]string{"replicas": "3"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared-config", Namespace: "infra"},
+			Data:       map[string]string{"registry": "registry.example"},
+		},
+	)
+
+	renderer, err := newManifestRenderer(client.Discovery(), client, "test", []string{"app-config", "infra/shared-config"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for expression, want := range map[string]string{
+		"cluster.namespace":                "test",
+		"configmap.app-config.replicas":    "3",
+		"configmap.shared-config.registry": "registry.example",
+	} {
+		if got := renderer.values[expression]; got != want {
+			t.Errorf("expected %q to resolve to %q, got %q", expression, want, got)
+		}
+	}
+
+	if _, err := newManifestRenderer(client.Discovery(), client, "test", []string{"missing"}); err == nil {
+		t.Error("expected an error for a missing config map input, got none")
+	}
+}
@@ -17,11 +17,14 @@ limitations under the License.
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -46,6 +49,9 @@ type ReconcileOptions struct {
 	DryRun                 bool
 	RemoveExtraPermissions bool
 	RemoveExtraSubjects    bool
+	Prune                  bool
+	PruneSelector          string
+	ShowDiff               bool
 
 	Visitor         resource.Visitor
 	RBACClient      rbacv1client.RbacV1Interface
@@ -68,11 +74,24 @@ var (
 		Existing bindings are updated to include the subjects in the input objects,
 		and remove extra subjects if --remove-extra-subjects is specified.
 
+		Aggregated cluster roles are reconciled by their aggregation rule label selectors;
+		their rules are owned by the aggregation controller and left alone.
+
+		If --prune is specified, roles and bindings that match --prune-selector but are
+		absent from the input objects are deleted, so a label-selected set of RBAC objects
+		can be kept in sync with its manifests.
+
+		If --show-diff is specified, the rule and subject changes are printed before they
+		are applied.
+
 		This is preferred to 'apply' for RBAC resources so that semantically-aware merging of rules and subjects is done.`)
 
 	reconcileExample = templates.Examples(`
 		# Reconcile RBAC resources from a file
-		kubectl auth reconcile -f my-rbac-rules.yaml`)
+		kubectl auth reconcile -f my-rbac-rules.yaml
+
+		# Reconcile RBAC resources, pruning label-selected objects missing from the file
+		kubectl auth reconcile -f my-rbac-rules.yaml --prune --prune-selector=rbac.example.com/managed=true`)
 )
 
 // NewReconcileOptions returns a new ReconcileOptions instance
@@ -106,6 +125,9 @@ func NewCmdReconcile(f cmdutil.Factory, streams genericclioptions.IOStreams) *co
 	cmdutil.AddFilenameOptionFlags(cmd, o.FilenameOptions, "identifying the resource to reconcile.")
 	cmd.Flags().BoolVar(&o.RemoveExtraPermissions, "remove-extra-permissions", o.RemoveExtraPermissions, "If true, removes extra permissions added to roles")
 	cmd.Flags().BoolVar(&o.RemoveExtraSubjects, "remove-extra-subjects", o.RemoveExtraSubjects, "If true, removes extra subjects added to rolebindings")
+	cmd.Flags().BoolVar(&o.Prune, "prune", o.Prune, "If true, deletes roles and bindings that match --prune-selector but are not present in the input. Requires --prune-selector.")
+	cmd.Flags().StringVar(&o.PruneSelector, "prune-selector", o.PruneSelector, "Label selector limiting which objects may be pruned, e.g. rbac.example.com/managed=true.")
+	cmd.Flags().BoolVar(&o.ShowDiff, "show-diff", o.ShowDiff, "If true, prints the rule and subject changes before applying them")
 	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
@@ -191,18 +213,23 @@ func (o *ReconcileOptions) Validate() error {
 	if o.ErrOut == nil {
 		return errors.New("ReconcileOptions.Err must be set")
 	}
+	if o.Prune && len(o.PruneSelector) == 0 {
+		return errors.New("--prune requires --prune-selector to limit which objects may be pruned")
+	}
 	return nil
 }
 
 // RunReconcile performs the execution
 func (o *ReconcileOptions) RunReconcile() error {
-	return o.Visitor.Visit(func(info *resource.Info, err error) error {
+	visited := sets.NewString()
+	err := o.Visitor.Visit(func(info *resource.Info, err error) error {
 		if err != nil {
 			return err
 		}
 
 		switch t := info.Object.(type) {
 		case *rbacv1.Role:
+			visited.Insert(pruneKey("role", t.Namespace, t.Name))
 			reconcileOptions := reconciliation.ReconcileRoleOptions{
 				Confirm:                !o.DryRun,
 				RemoveExtraPermissions: o.RemoveExtraPermissions,
@@ -212,13 +239,19 @@ func (o *ReconcileOptions) RunReconcile() error {
 					Client:          o.RBACClient,
 				},
 			}
+			if o.ShowDiff {
+				if err := o.printRoleDiff("role", reconcileOptions); err != nil {
+					return err
+				}
+			}
 			result, err := reconcileOptions.Run()
 			if err != nil {
 				return err
 			}
-			o.printResults(result.Role.GetObject(), nil, nil, result.MissingRules, result.ExtraRules, result.Operation, result.Protected)
+			o.printResults(result.Role.GetObject(), nil, nil, result.MissingRules, result.ExtraRules, nil, nil, result.Operation, result.Protected)
 
 		case *rbacv1.ClusterRole:
+			visited.Insert(pruneKey("clusterrole", "", t.Name))
 			reconcileOptions := reconciliation.ReconcileRoleOptions{
 				Confirm:                !o.DryRun,
 				RemoveExtraPermissions: o.RemoveExtraPermissions,
@@ -227,13 +260,19 @@ func (o *ReconcileOptions) RunReconcile() error {
 					Client: o.RBACClient.ClusterRoles(),
 				},
 			}
+			if o.ShowDiff {
+				if err := o.printRoleDiff("clusterrole", reconcileOptions); err != nil {
+					return err
+				}
+			}
 			result, err := reconcileOptions.Run()
 			if err != nil {
 				return err
 			}
-			o.printResults(result.Role.GetObject(), nil, nil, result.MissingRules, result.ExtraRules, result.Operation, result.Protected)
+			o.printResults(result.Role.GetObject(), nil, nil, result.MissingRules, result.ExtraRules, result.MissingAggregationRuleSelectors, result.ExtraAggregationRuleSelectors, result.Operation, result.Protected)
 
 		case *rbacv1.RoleBinding:
+			visited.Insert(pruneKey("rolebinding", t.Namespace, t.Name))
 			reconcileOptions := reconciliation.ReconcileRoleBindingOptions{
 				Confirm:             !o.DryRun,
 				RemoveExtraSubjects: o.RemoveExtraSubjects,
@@ -243,13 +282,19 @@ func (o *ReconcileOptions) RunReconcile() error {
 					NamespaceClient: o.NamespaceClient.Namespaces(),
 				},
 			}
+			if o.ShowDiff {
+				if err := o.printRoleBindingDiff("rolebinding", reconcileOptions); err != nil {
+					return err
+				}
+			}
 			result, err := reconcileOptions.Run()
 			if err != nil {
 				return err
 			}
-			o.printResults(result.RoleBinding.GetObject(), result.MissingSubjects, result.ExtraSubjects, nil, nil, result.Operation, result.Protected)
+			o.printResults(result.RoleBinding.GetObject(), result.MissingSubjects, result.ExtraSubjects, nil, nil, nil, nil, result.Operation, result.Protected)
 
 		case *rbacv1.ClusterRoleBinding:
+			visited.Insert(pruneKey("clusterrolebinding", "", t.Name))
 			reconcileOptions := reconciliation.ReconcileRoleBindingOptions{
 				Confirm:             !o.DryRun,
 				RemoveExtraSubjects: o.RemoveExtraSubjects,
@@ -258,11 +303,16 @@ func (o *ReconcileOptions) RunReconcile() error {
 					Client: o.RBACClient.ClusterRoleBindings(),
 				},
 			}
+			if o.ShowDiff {
+				if err := o.printRoleBindingDiff("clusterrolebinding", reconcileOptions); err != nil {
+					return err
+				}
+			}
 			result, err := reconcileOptions.Run()
 			if err != nil {
 				return err
 			}
-			o.printResults(result.RoleBinding.GetObject(), result.MissingSubjects, result.ExtraSubjects, nil, nil, result.Operation, result.Protected)
+			o.printResults(result.RoleBinding.GetObject(), result.MissingSubjects, result.ExtraSubjects, nil, nil, nil, nil, result.Operation, result.Protected)
 
 		case *rbacv1beta1.Role,
 			*rbacv1beta1.RoleBinding,
@@ -281,11 +331,169 @@ func (o *ReconcileOptions) RunReconcile() error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if o.Prune {
+		return o.pruneExtraObjects(visited)
+	}
+	return nil
+}
+
+// pruneKey identifies a reconciled object for pruning purposes.
+func pruneKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// pruneExtraObjects deletes roles and bindings that match the prune selector
+// but were not present in the input. Objects that opted out of reconciliation
+// are left alone.
+func (o *ReconcileOptions) pruneExtraObjects(visited sets.String) error {
+	ctx := context.TODO()
+	listOptions := metav1.ListOptions{LabelSelector: o.PruneSelector}
+
+	clusterRoles, err := o.RBACClient.ClusterRoles().List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range clusterRoles.Items {
+		if visited.Has(pruneKey("clusterrole", "", item.Name)) || item.Annotations[rbacv1.AutoUpdateAnnotationKey] == "false" {
+			continue
+		}
+		if !o.DryRun {
+			if err := o.RBACClient.ClusterRoles().Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
+		o.printPruned("clusterrole.rbac.authorization.k8s.io", "", item.Name)
+	}
+
+	clusterRoleBindings, err := o.RBACClient.ClusterRoleBindings().List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range clusterRoleBindings.Items {
+		if visited.Has(pruneKey("clusterrolebinding", "", item.Name)) || item.Annotations[rbacv1.AutoUpdateAnnotationKey] == "false" {
+			continue
+		}
+		if !o.DryRun {
+			if err := o.RBACClient.ClusterRoleBindings().Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
+		o.printPruned("clusterrolebinding.rbac.authorization.k8s.io", "", item.Name)
+	}
+
+	roles, err := o.RBACClient.Roles(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range roles.Items {
+		if visited.Has(pruneKey("role", item.Namespace, item.Name)) || item.Annotations[rbacv1.AutoUpdateAnnotationKey] == "false" {
+			continue
+		}
+		if !o.DryRun {
+			if err := o.RBACClient.Roles(item.Namespace).Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
+		o.printPruned("role.rbac.authorization.k8s.io", item.Namespace, item.Name)
+	}
+
+	roleBindings, err := o.RBACClient.RoleBindings(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range roleBindings.Items {
+		if visited.Has(pruneKey("rolebinding", item.Namespace, item.Name)) || item.Annotations[rbacv1.AutoUpdateAnnotationKey] == "false" {
+			continue
+		}
+		if !o.DryRun {
+			if err := o.RBACClient.RoleBindings(item.Namespace).Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
+		o.printPruned("rolebinding.rbac.authorization.k8s.io", item.Namespace, item.Name)
+	}
+
+	return nil
+}
+
+func (o *ReconcileOptions) printPruned(kind, namespace, name string) {
+	suffix := ""
+	if o.DryRun {
+		suffix = " (dry run)"
+	}
+	if len(namespace) > 0 {
+		name = namespace + "/" + name
+	}
+	fmt.Fprintf(o.Out, "%s/%s pruned%s\n", kind, name, suffix)
+}
+
+// printRoleDiff runs the reconcile as a dry run and prints the changes it
+// would make.
+func (o *ReconcileOptions) printRoleDiff(kind string, opts reconciliation.ReconcileRoleOptions) error {
+	opts.Confirm = false
+	result, err := opts.Run()
+	if err != nil {
+		return err
+	}
+	if result.Operation == reconciliation.ReconcileNone {
+		return nil
+	}
+	o.printDiffHeader(kind, result.Role.GetNamespace(), result.Role.GetName(), result.Operation)
+	for _, r := range result.MissingRules {
+		fmt.Fprintf(o.Out, "+ rule %+v\n", r)
+	}
+	for _, s := range result.MissingAggregationRuleSelectors {
+		fmt.Fprintf(o.Out, "+ aggregation selector %+v\n", s)
+	}
+	if o.RemoveExtraPermissions {
+		for _, r := range result.ExtraRules {
+			fmt.Fprintf(o.Out, "- rule %+v\n", r)
+		}
+		for _, s := range result.ExtraAggregationRuleSelectors {
+			fmt.Fprintf(o.Out, "- aggregation selector %+v\n", s)
+		}
+	}
+	return nil
+}
+
+// printRoleBindingDiff runs the reconcile as a dry run and prints the changes
+// it would make.
+func (o *ReconcileOptions) printRoleBindingDiff(kind string, opts reconciliation.ReconcileRoleBindingOptions) error {
+	opts.Confirm = false
+	result, err := opts.Run()
+	if err != nil {
+		return err
+	}
+	if result.Operation == reconciliation.ReconcileNone {
+		return nil
+	}
+	o.printDiffHeader(kind, result.RoleBinding.GetNamespace(), result.RoleBinding.GetName(), result.Operation)
+	for _, s := range result.MissingSubjects {
+		fmt.Fprintf(o.Out, "+ subject %+v\n", s)
+	}
+	if o.RemoveExtraSubjects {
+		for _, s := range result.ExtraSubjects {
+			fmt.Fprintf(o.Out, "- subject %+v\n", s)
+		}
+	}
+	return nil
+}
+
+func (o *ReconcileOptions) printDiffHeader(kind, namespace, name string, operation reconciliation.ReconcileOperation) {
+	if len(namespace) > 0 {
+		name = namespace + "/" + name
+	}
+	fmt.Fprintf(o.Out, "--- %s/%s (%s)\n", kind, name, operation)
 }
 
 func (o *ReconcileOptions) printResults(object runtime.Object,
 	missingSubjects, extraSubjects []rbacv1.Subject,
 	missingRules, extraRules []rbacv1.PolicyRule,
+	missingAggregationSelectors, extraAggregationSelectors []metav1.LabelSelector,
 	operation reconciliation.ReconcileOperation,
 	protected bool) {
 
@@ -334,6 +542,20 @@ func (o *ReconcileOptions) printResults(object runtime.Object,
 			}
 		}
 	}
+	if len(missingAggregationSelectors) > 0 {
+		fmt.Fprintf(o.ErrOut, "\tmissing aggregation selectors added:\n")
+		for _, s := range missingAggregationSelectors {
+			fmt.Fprintf(o.ErrOut, "\t\t%+v\n", s)
+		}
+	}
+	if o.RemoveExtraPermissions {
+		if len(extraAggregationSelectors) > 0 {
+			fmt.Fprintf(o.ErrOut, "\textra aggregation selectors removed:\n")
+			for _, s := range extraAggregationSelectors {
+				fmt.Fprintf(o.ErrOut, "\t\t%+v\n", s)
+			}
+		}
+	}
 }
 
 func getClientSideDryRun(cmd *cobra.Command) (bool, error) {
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+)
+
+func reconcileTestOptions(t *testing.T, client *fakeclient.Clientset, infos ...*resource.Info) (*ReconcileOptions, *strings.Builder) {
+	t.Helper()
+	out := &strings.Builder{}
+	visitors := resource.VisitorList{}
+	for _, info := range infos {
+		visitors = append(visitors, info)
+	}
+	return &ReconcileOptions{
+		Visitor:         visitors,
+		RBACClient:      client.RbacV1(),
+		NamespaceClient: client.CoreV1(),
+		PrintObject:     func(obj runtime.Object, w io.Writer) error { return nil },
+		IOStreams:       genericclioptions.IOStreams{Out: out, ErrOut: &strings.Builder{}},
+	}, out
+}
+
+func managedClusterRole(name string, labels map[string]string, annotations map[string]string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+	}
+}
+
+func TestReconcilePrune(t *testing.T) {
+	managed := map[string]string{"rbac.example.com/managed": "true"}
+	keep := managedClusterRole("keep", managed, nil)
+	client := fakeclient.NewSimpleClientset(
+		keep,
+		managedClusterRole("stale", managed, nil),
+		managedClusterRole("unmanaged", nil, nil),
+		managedClusterRole("protected", managed, map[string]string{rbacv1.AutoUpdateAnnotationKey: "false"}),
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "stale-binding", Namespace: "ns1", Labels: managed}},
+	)
+
+	o, out := reconcileTestOptions(t, client, &resource.Info{Object: keep})
+	o.Prune = true
+	o.PruneSelector = "rbac.example.com/managed=true"
+	if err := o.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := o.RunReconcile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, pruned := range []string{
+		"clusterrole.rbac.authorization.k8s.io/stale pruned",
+		"rolebinding.rbac.authorization.k8s.io/ns1/stale-binding pruned",
+	} {
+		if !strings.Contains(out.String(), pruned) {
+			t.Errorf("expected output to contain %q, got:\n%s", pruned, out.String())
+		}
+	}
+
+	remaining, err := client.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, item := range remaining.Items {
+		names = append(names, item.Name)
+	}
+	want := "keep protected unmanaged"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("expected remaining cluster roles %q, got %q", want, got)
+	}
+}
+
+func TestReconcilePruneRequiresSelector(t *testing.T) {
+	o, _ := reconcileTestOptions(t, fakeclient.NewSimpleClientset())
+	o.Prune = true
+	if err := o.Validate(); err == nil || !strings.Contains(err.Error(), "--prune-selector") {
+		t.Errorf("expected a validation error mentioning --prune-selector, got %v", err)
+	}
+}
+
+func TestReconcileShowDiff(t *testing.T) {
+	existing := managedClusterRole("viewer", nil, nil)
+	client := fakeclient.NewSimpleClientset(existing)
+
+	expected := existing.DeepCopy()
+	expected.Rules = append(expected.Rules, rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"services"}})
+
+	o, out := reconcileTestOptions(t, client, &resource.Info{Object: expected})
+	o.ShowDiff = true
+	if err := o.RunReconcile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "--- clusterrole/viewer (update)") {
+		t.Errorf("expected a diff header for clusterrole/viewer, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "+ rule") || !strings.Contains(out.String(), "services") {
+		t.Errorf("expected the diff to contain the added rule, got:\n%s", out.String())
+	}
+}
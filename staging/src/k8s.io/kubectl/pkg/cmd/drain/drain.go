@@ -19,6 +19,9 @@ package drain
 import (
 	"errors"
 	"fmt"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -26,7 +29,6 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
@@ -45,8 +47,15 @@ type DrainCmdOptions struct {
 
 	Namespace string
 
+	// Parallelism is the maximum number of nodes drained concurrently.
+	Parallelism int
+	// ReportFile, when set, is where the JSON report of evicted and failed
+	// pods per node is written ("-" for standard output).
+	ReportFile string
+
 	drainer   *drain.Helper
 	nodeInfos []*resource.Info
+	report    *drainReport
 
 	genericclioptions.IOStreams
 	warningPrinter *printers.WarningPrinter
@@ -146,8 +155,10 @@ var (
 
 func NewDrainCmdOptions(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *DrainCmdOptions {
 	o := &DrainCmdOptions{
-		PrintFlags: genericclioptions.NewPrintFlags("drained").WithTypeSetter(scheme.Scheme),
-		IOStreams:  ioStreams,
+		PrintFlags:  genericclioptions.NewPrintFlags("drained").WithTypeSetter(scheme.Scheme),
+		IOStreams:   ioStreams,
+		Parallelism: 1,
+		report:      newDrainReport(),
 		drainer: &drain.Helper{
 			GracePeriodSeconds: -1,
 			Out:                ioStreams.Out,
@@ -161,6 +172,7 @@ func NewDrainCmdOptions(f cmdutil.Factory, ioStreams genericclioptions.IOStreams
 
 // onPodDeletedOrEvicted is called by drain.Helper, when the pod has been deleted or evicted
 func (o *DrainCmdOptions) onPodDeletedOrEvicted(pod *corev1.Pod, usingEviction bool) {
+	o.report.recordEvictedPod(pod.Spec.NodeName, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
 	var verbStr string
 	if usingEviction {
 		verbStr = "evicted"
@@ -201,6 +213,8 @@ func NewCmdDrain(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobr
 	cmd.Flags().StringVarP(&o.drainer.PodSelector, "pod-selector", "", o.drainer.PodSelector, "Label selector to filter pods on the node")
 	cmd.Flags().BoolVar(&o.drainer.DisableEviction, "disable-eviction", o.drainer.DisableEviction, "Force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
 	cmd.Flags().IntVar(&o.drainer.SkipWaitForDeleteTimeoutSeconds, "skip-wait-for-delete-timeout", o.drainer.SkipWaitForDeleteTimeoutSeconds, "If pod DeletionTimestamp older than N seconds, skip waiting for the pod.  Seconds must be greater than 0 to skip.")
+	cmd.Flags().IntVar(&o.Parallelism, "parallel", o.Parallelism, "Maximum number of nodes to drain concurrently. Evictions rejected because a pod disruption budget has no disruptions left pause all nodes together before retrying.")
+	cmd.Flags().StringVar(&o.ReportFile, "report-file", o.ReportFile, "If set, write a JSON report of the evicted and failed pods per node to this file ('-' for standard output).")
 
 	cmdutil.AddChunkSizeFlag(cmd, &o.drainer.ChunkSize)
 	cmdutil.AddDryRunFlag(cmd)
@@ -219,6 +233,12 @@ func (o *DrainCmdOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args [
 	if len(args) > 0 && len(o.drainer.Selector) > 0 {
 		return cmdutil.UsageErrorf(cmd, "error: cannot specify both a node name and a --selector option")
 	}
+	if o.Parallelism < 1 {
+		return cmdutil.UsageErrorf(cmd, "error: --parallel must be at least 1")
+	}
+	if o.Parallelism > 1 {
+		o.drainer.Pacer = drain.NewEvictionPacer(5 * time.Second)
+	}
 
 	o.drainer.DryRunStrategy, err = cmdutil.GetDryRunStrategy(cmd)
 	if err != nil {
@@ -299,30 +319,63 @@ func (o *DrainCmdOptions) RunDrain() error {
 		return err
 	}
 
-	drainedNodes := sets.NewString()
-	var fatal []error
+	var (
+		lock           sync.Mutex
+		fatal          []error
+		remainingNodes []string
+		processed      int
+		drained        int
+	)
 
-	remainingNodes := []string{}
-	for _, info := range o.nodeInfos {
-		if err := o.deleteOrEvictPodsSimple(info); err == nil {
-			drainedNodes.Insert(info.Name)
+	drainNode := func(info *resource.Info) {
+		err := o.deleteOrEvictPodsSimple(info)
+
+		lock.Lock()
+		defer lock.Unlock()
+		processed++
+		o.report.recordResult(info.Name, err)
+		if err == nil {
+			drained++
 
 			printObj, err := o.ToPrinter("drained")
 			if err != nil {
-				return err
+				fatal = append(fatal, err)
+				return
 			}
 
 			printObj(info.Object, o.Out)
 		} else {
 			fmt.Fprintf(o.ErrOut, "error: unable to drain node %q due to error:%s, continuing command...\n", info.Name, err)
 
-			if !drainedNodes.Has(info.Name) {
-				fatal = append(fatal, err)
-				remainingNodes = append(remainingNodes, info.Name)
-			}
+			fatal = append(fatal, err)
+			remainingNodes = append(remainingNodes, info.Name)
+		}
+		if o.Parallelism > 1 {
+			fmt.Fprintf(o.ErrOut, "progress: %d/%d nodes processed, %d drained, %d failed\n", processed, len(o.nodeInfos), drained, processed-drained)
+		}
+	}
 
-			continue
+	if o.Parallelism <= 1 {
+		for _, info := range o.nodeInfos {
+			drainNode(info)
 		}
+	} else {
+		infoCh := make(chan *resource.Info)
+		var wg sync.WaitGroup
+		for i := 0; i < o.Parallelism; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for info := range infoCh {
+					drainNode(info)
+				}
+			}()
+		}
+		for _, info := range o.nodeInfos {
+			infoCh <- info
+		}
+		close(infoCh)
+		wg.Wait()
 	}
 
 	if len(remainingNodes) > 0 {
@@ -332,9 +385,29 @@ func (o *DrainCmdOptions) RunDrain() error {
 		}
 	}
 
+	if err := o.writeReport(); err != nil {
+		fatal = append(fatal, err)
+	}
+
 	return utilerrors.NewAggregate(fatal)
 }
 
+// writeReport writes the JSON drain report when --report-file was given.
+func (o *DrainCmdOptions) writeReport() error {
+	if len(o.ReportFile) == 0 {
+		return nil
+	}
+	if o.ReportFile == "-" {
+		return o.report.write(o.Out)
+	}
+	f, err := os.Create(o.ReportFile)
+	if err != nil {
+		return fmt.Errorf("unable to write drain report: %v", err)
+	}
+	defer f.Close()
+	return o.report.write(f)
+}
+
 func (o *DrainCmdOptions) deleteOrEvictPodsSimple(nodeInfo *resource.Info) error {
 	list, errs := o.drainer.GetPodsForDeletion(nodeInfo.Name)
 	if errs != nil {
@@ -357,6 +430,7 @@ func (o *DrainCmdOptions) deleteOrEvictPodsSimple(nodeInfo *resource.Info) error
 			if len(pods) != 0 {
 				fmt.Fprintf(o.ErrOut, "There are pending pods in node %q when an error occurred: %v\n", nodeInfo.Name, err)
 				for _, pendingPod := range pods {
+					o.report.recordFailedPod(nodeInfo.Name, fmt.Sprintf("%s/%s", pendingPod.Namespace, pendingPod.Name))
 					fmt.Fprintf(o.ErrOut, "%s/%s\n", "pod", pendingPod.Name)
 				}
 			}
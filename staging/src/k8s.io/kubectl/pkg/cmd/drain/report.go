@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+)
+
+// drainNodeReport is the per-node entry of the machine-readable report
+// written for --report-file.
+type drainNodeReport struct {
+	Name        string   `json:"name"`
+	Drained     bool     `json:"drained"`
+	EvictedPods []string `json:"evictedPods,omitempty"`
+	FailedPods  []string `json:"failedPods,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// drainReport accumulates per-node drain outcomes. It is safe for use from
+// the goroutines draining nodes in parallel.
+type drainReport struct {
+	lock  sync.Mutex
+	nodes map[string]*drainNodeReport
+}
+
+func newDrainReport() *drainReport {
+	return &drainReport{nodes: map[string]*drainNodeReport{}}
+}
+
+// node returns the entry for nodeName, creating it if needed. Callers must
+// hold r.lock.
+func (r *drainReport) node(nodeName string) *drainNodeReport {
+	entry, ok := r.nodes[nodeName]
+	if !ok {
+		entry = &drainNodeReport{Name: nodeName}
+		r.nodes[nodeName] = entry
+	}
+	return entry
+}
+
+func (r *drainReport) recordEvictedPod(nodeName, podKey string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry := r.node(nodeName)
+	entry.EvictedPods = append(entry.EvictedPods, podKey)
+}
+
+func (r *drainReport) recordFailedPod(nodeName, podKey string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry := r.node(nodeName)
+	entry.FailedPods = append(entry.FailedPods, podKey)
+}
+
+func (r *drainReport) recordResult(nodeName string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry := r.node(nodeName)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Drained = true
+	}
+}
+
+// write emits the report as JSON, with nodes sorted by name for stable output.
+func (r *drainReport) write(out io.Writer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	nodes := make([]*drainNodeReport, 0, len(r.nodes))
+	for _, entry := range r.nodes {
+		nodes = append(nodes, entry)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Nodes []*drainNodeReport `json:"nodes"`
+	}{Nodes: nodes})
+}
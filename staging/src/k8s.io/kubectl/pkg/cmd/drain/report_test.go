@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDrainReport(t *testing.T) {
+	report := newDrainReport()
+	report.recordEvictedPod("node2", "default/pod-b")
+	report.recordEvictedPod("node1", "default/pod-a")
+	report.recordFailedPod("node2", "default/pod-c")
+	report.recordResult("node1", nil)
+	report.recordResult("node2", errors.New("cannot evict pod"))
+
+	out := &bytes.Buffer{}
+	if err := report.write(out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := struct {
+		Nodes []drainNodeReport `json:"nodes"`
+	}{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error decoding report: %v", err)
+	}
+
+	expected := []drainNodeReport{
+		{Name: "node1", Drained: true, EvictedPods: []string{"default/pod-a"}},
+		{Name: "node2", EvictedPods: []string{"default/pod-b"}, FailedPods: []string{"default/pod-c"}, Error: "cannot evict pod"},
+	}
+	if !reflect.DeepEqual(got.Nodes, expected) {
+		t.Errorf("unexpected report:\ngot:  %#v\nwant: %#v", got.Nodes, expected)
+	}
+}
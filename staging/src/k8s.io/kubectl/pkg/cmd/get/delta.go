@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// deltaEvent is one line of --output-delta output: the watch event type, the
+// identity of the object, and an RFC 6902 patch describing how the object
+// changed relative to its previously seen state.
+type deltaEvent struct {
+	Type       string           `json:"type"`
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Namespace  string           `json:"namespace,omitempty"`
+	Name       string           `json:"name"`
+	Patch      []patchOperation `json:"patch"`
+}
+
+// patchOperation is a single RFC 6902 operation. Value is a pointer so that
+// remove operations omit the member entirely while add and replace always
+// carry it, even when the value is a JSON null.
+type patchOperation struct {
+	Op    string       `json:"op"`
+	Path  string       `json:"path"`
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// deltaTracker remembers the last seen state of each watched object so each
+// watch event can be emitted as a patch relative to that state. The first
+// event for an object is a patch relative to the empty object, and a DELETED
+// event is a patch removing the remaining fields.
+type deltaTracker struct {
+	previous map[string]map[string]interface{}
+}
+
+func newDeltaTracker() *deltaTracker {
+	return &deltaTracker{previous: map[string]map[string]interface{}{}}
+}
+
+// Observe records the state of obj without emitting an event, so a later
+// event for the same object diffs against it.
+func (t *deltaTracker) Observe(obj runtime.Object) error {
+	state, err := objectState(obj)
+	if err != nil {
+		return err
+	}
+	t.previous[deltaKey(state)] = state
+	return nil
+}
+
+// WriteEvent emits one JSON line describing how obj changed since it was last
+// seen.
+func (t *deltaTracker) WriteEvent(eventType string, obj runtime.Object, w io.Writer) error {
+	state, err := objectState(obj)
+	if err != nil {
+		return err
+	}
+	key := deltaKey(state)
+
+	var ops []patchOperation
+	if eventType == string(watch.Deleted) {
+		before := t.previous[key]
+		if before == nil {
+			before = state
+		}
+		ops = jsonPatchDiff("", before, map[string]interface{}{})
+		delete(t.previous, key)
+	} else {
+		ops = jsonPatchDiff("", t.previous[key], state)
+		t.previous[key] = state
+	}
+	if ops == nil {
+		ops = []patchOperation{}
+	}
+
+	u := &unstructured.Unstructured{Object: state}
+	event := deltaEvent{
+		Type:       eventType,
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+		Patch:      ops,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// objectState returns the JSON-compatible map form of obj, copied so later
+// mutations of the object do not change the recorded state.
+func objectState(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return runtime.DeepCopyJSON(u.UnstructuredContent()), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func deltaKey(state map[string]interface{}) string {
+	u := &unstructured.Unstructured{Object: state}
+	return strings.Join([]string{u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName()}, "/")
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonPatchDiff computes the RFC 6902 operations turning before into after.
+// Nested objects are diffed recursively; lists and scalars are replaced
+// wholesale. Operations are emitted in sorted key order so the output is
+// deterministic.
+func jsonPatchDiff(path string, before, after map[string]interface{}) []patchOperation {
+	keys := sets.NewString()
+	for key := range before {
+		keys.Insert(key)
+	}
+	for key := range after {
+		keys.Insert(key)
+	}
+
+	var ops []patchOperation
+	for _, key := range keys.List() {
+		childPath := path + "/" + jsonPointerEscaper.Replace(key)
+		beforeValue, inBefore := before[key]
+		afterValue, inAfter := after[key]
+		switch {
+		case !inAfter:
+			ops = append(ops, patchOperation{Op: "remove", Path: childPath})
+		case !inBefore:
+			ops = append(ops, patchOperation{Op: "add", Path: childPath, Value: &afterValue})
+		case reflect.DeepEqual(beforeValue, afterValue):
+			// unchanged
+		default:
+			beforeMap, beforeIsMap := beforeValue.(map[string]interface{})
+			afterMap, afterIsMap := afterValue.(map[string]interface{})
+			if beforeIsMap && afterIsMap {
+				ops = append(ops, jsonPatchDiff(childPath, beforeMap, afterMap)...)
+			} else {
+				ops = append(ops, patchOperation{Op: "replace", Path: childPath, Value: &afterValue})
+			}
+		}
+	}
+	return ops
+}
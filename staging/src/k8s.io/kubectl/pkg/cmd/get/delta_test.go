@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func val(v interface{}) *interface{} {
+	return &v
+}
+
+func TestJSONPatchDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   map[string]interface{}
+		after    map[string]interface{}
+		expected []patchOperation
+	}{
+		{
+			name:   "add against empty",
+			before: map[string]interface{}{},
+			after:  map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+			expected: []patchOperation{
+				{Op: "add", Path: "/spec", Value: val(map[string]interface{}{"replicas": int64(1)})},
+			},
+		},
+		{
+			name:     "no change",
+			before:   map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+			after:    map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+			expected: nil,
+		},
+		{
+			name:   "nested replace",
+			before: map[string]interface{}{"status": map[string]interface{}{"phase": "Pending", "hostIP": "10.0.0.1"}},
+			after:  map[string]interface{}{"status": map[string]interface{}{"phase": "Running", "hostIP": "10.0.0.1"}},
+			expected: []patchOperation{
+				{Op: "replace", Path: "/status/phase", Value: val("Running")},
+			},
+		},
+		{
+			name:   "remove",
+			before: map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"a": "b"}}},
+			after:  map[string]interface{}{"metadata": map[string]interface{}{}},
+			expected: []patchOperation{
+				{Op: "remove", Path: "/metadata/labels"},
+			},
+		},
+		{
+			name:   "lists are replaced wholesale",
+			before: map[string]interface{}{"spec": map[string]interface{}{"finalizers": []interface{}{"a"}}},
+			after:  map[string]interface{}{"spec": map[string]interface{}{"finalizers": []interface{}{"a", "b"}}},
+			expected: []patchOperation{
+				{Op: "replace", Path: "/spec/finalizers", Value: val([]interface{}{"a", "b"})},
+			},
+		},
+		{
+			name:   "explicit nulls keep their value member",
+			before: map[string]interface{}{"spec": map[string]interface{}{"x": "a"}},
+			after:  map[string]interface{}{"spec": map[string]interface{}{"x": nil}},
+			expected: []patchOperation{
+				{Op: "replace", Path: "/spec/x", Value: val(nil)},
+			},
+		},
+		{
+			name:   "keys are escaped",
+			before: map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{}}},
+			after:  map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{"example.com/a~b": "c"}}},
+			expected: []patchOperation{
+				{Op: "add", Path: "/metadata/annotations/example.com~1a~0b", Value: val("c")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := jsonPatchDiff("", tt.before, tt.after)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPatchOperationMarshal(t *testing.T) {
+	added, err := json.Marshal(patchOperation{Op: "add", Path: "/a", Value: val(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"op":"add","path":"/a","value":null}`; string(added) != want {
+		t.Errorf("expected %s, got %s", want, added)
+	}
+	removed, err := json.Marshal(patchOperation{Op: "remove", Path: "/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"op":"remove","path":"/a"}`; string(removed) != want {
+		t.Errorf("expected %s, got %s", want, removed)
+	}
+}
+
+func deltaTestPod(phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"namespace": "test", "name": "foo"},
+		"status":     map[string]interface{}{"phase": phase},
+	}}
+}
+
+func TestDeltaTrackerWriteEvent(t *testing.T) {
+	tracker := newDeltaTracker()
+	out := &strings.Builder{}
+
+	if err := tracker.WriteEvent(string(watch.Added), deltaTestPod("Pending"), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tracker.WriteEvent(string(watch.Modified), deltaTestPod("Running"), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tracker.WriteEvent(string(watch.Deleted), deltaTestPod("Running"), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines of output, got %d:\n%s", len(lines), out.String())
+	}
+
+	expected := []string{
+		`{"type":"ADDED","apiVersion":"v1","kind":"Pod","namespace":"test","name":"foo","patch":[{"op":"add","path":"/apiVersion","value":"v1"},{"op":"add","path":"/kind","value":"Pod"},{"op":"add","path":"/metadata","value":{"name":"foo","namespace":"test"}},{"op":"add","path":"/status","value":{"phase":"Pending"}}]}`,
+		`{"type":"MODIFIED","apiVersion":"v1","kind":"Pod","namespace":"test","name":"foo","patch":[{"op":"replace","path":"/status/phase","value":"Running"}]}`,
+		`{"type":"DELETED","apiVersion":"v1","kind":"Pod","namespace":"test","name":"foo","patch":[{"op":"remove","path":"/apiVersion"},{"op":"remove","path":"/kind"},{"op":"remove","path":"/metadata"},{"op":"remove","path":"/status"}]}`,
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("line %d:\nexpected %s\ngot      %s", i, expected[i], line)
+		}
+	}
+}
+
+func TestDeltaTrackerObserve(t *testing.T) {
+	tracker := newDeltaTracker()
+	out := &strings.Builder{}
+
+	if err := tracker.Observe(deltaTestPod("Pending")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tracker.WriteEvent(string(watch.Modified), deltaTestPod("Running"), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), `"patch":[{"op":"replace","path":"/status/phase","value":"Running"}]`) {
+		t.Errorf("expected the event to diff against the observed state, got:\n%s", out.String())
+	}
+}
@@ -69,6 +69,7 @@ type GetOptions struct {
 	ChunkSize int64
 
 	OutputWatchEvents bool
+	OutputDelta       bool
 
 	LabelSelector     string
 	FieldSelector     string
@@ -181,6 +182,7 @@ func NewCmdGet(parent string, f cmdutil.Factory, streams genericclioptions.IOStr
 	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After listing/getting the requested object, watch for changes.")
 	cmd.Flags().BoolVar(&o.WatchOnly, "watch-only", o.WatchOnly, "Watch for changes to the requested object(s), without listing/getting first.")
 	cmd.Flags().BoolVar(&o.OutputWatchEvents, "output-watch-events", o.OutputWatchEvents, "Output watch event objects when --watch or --watch-only is used. Existing objects are output as initial ADDED events.")
+	cmd.Flags().BoolVar(&o.OutputDelta, "output-delta", o.OutputDelta, "Output one JSON line per watch event containing an RFC 6902 patch relative to the previously seen state of each object when --watch or --watch-only is used.")
 	cmd.Flags().BoolVar(&o.IgnoreNotFound, "ignore-not-found", o.IgnoreNotFound, "If the requested object does not exist the command will return exit code 0.")
 	cmd.Flags().StringVar(&o.FieldSelector, "field-selector", o.FieldSelector, "Selector (field query) to filter on, supports '=', '==', and '!='.(e.g. --field-selector key1=value1,key2=value2). The server only supports a limited number of field queries per type.")
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
@@ -324,6 +326,12 @@ func (o *GetOptions) Validate() error {
 	if o.OutputWatchEvents && !(o.Watch || o.WatchOnly) {
 		return fmt.Errorf("--output-watch-events option can only be used with --watch or --watch-only")
 	}
+	if o.OutputDelta && !(o.Watch || o.WatchOnly) {
+		return fmt.Errorf("--output-delta option can only be used with --watch or --watch-only")
+	}
+	if o.OutputDelta && o.OutputWatchEvents {
+		return fmt.Errorf("--output-delta and --output-watch-events are mutually exclusive")
+	}
 	if len(o.Subresource) > 0 && !slice.ContainsString(supportedSubresources, o.Subresource, nil) {
 		return fmt.Errorf("invalid subresource value: %q. Must be one of %v", o.Subresource, supportedSubresources)
 	}
@@ -685,6 +693,11 @@ func (o *GetOptions) watch(f cmdutil.Factory, cmd *cobra.Command, args []string)
 
 	writer := printers.GetNewTabWriter(o.Out)
 
+	var deltas *deltaTracker
+	if o.OutputDelta {
+		deltas = newDeltaTracker()
+	}
+
 	// print the current object
 	var objsToPrint []runtime.Object
 	if isList {
@@ -693,6 +706,14 @@ func (o *GetOptions) watch(f cmdutil.Factory, cmd *cobra.Command, args []string)
 		objsToPrint = append(objsToPrint, obj)
 	}
 	for _, objToPrint := range objsToPrint {
+		if o.OutputDelta {
+			if *outputObjects {
+				if err := deltas.WriteEvent(string(watch.Added), objToPrint, o.Out); err != nil {
+					return fmt.Errorf("unable to output the provided object: %v", err)
+				}
+			}
+			continue
+		}
 		if o.OutputWatchEvents {
 			objToPrint = &metav1.WatchEvent{Type: string(watch.Added), Object: runtime.RawExtension{Object: objToPrint}}
 		}
@@ -720,6 +741,19 @@ func (o *GetOptions) watch(f cmdutil.Factory, cmd *cobra.Command, args []string)
 	intr := interrupt.New(nil, cancel)
 	intr.Run(func() error {
 		_, err := watchtools.UntilWithoutRetry(ctx, w, func(e watch.Event) (bool, error) {
+			if o.OutputDelta {
+				if *outputObjects {
+					if err := deltas.WriteEvent(string(e.Type), e.Object, o.Out); err != nil {
+						return false, err
+					}
+				} else if err := deltas.Observe(e.Object); err != nil {
+					// record the state of the suppressed synthetic ADDED event
+					// so the next event diffs against it
+					return false, err
+				}
+				*outputObjects = true
+				return false, nil
+			}
 			objToPrint := e.Object
 			if o.OutputWatchEvents {
 				objToPrint = &metav1.WatchEvent{Type: string(e.Type), Object: runtime.RawExtension{Object: objToPrint}}
@@ -87,6 +87,10 @@ type Helper struct {
 
 	// OnPodDeletedOrEvicted is called when a pod is evicted/deleted; for printing progress output
 	OnPodDeletedOrEvicted func(pod *corev1.Pod, usingEviction bool)
+
+	// Pacer, when set, coordinates eviction retries triggered by exhausted
+	// PodDisruptionBudgets across node drains sharing this helper.
+	Pacer *EvictionPacer
 }
 
 type waitForDeleteParams struct {
@@ -310,6 +314,9 @@ func (d *Helper) evictPods(pods []corev1.Pod, evictionGroupVersion schema.GroupV
 					refreshPod = false
 				}
 
+				if d.Pacer != nil {
+					d.Pacer.Wait()
+				}
 				err := d.EvictPod(activePod, evictionGroupVersion)
 				if err == nil {
 					break
@@ -318,7 +325,11 @@ func (d *Helper) evictPods(pods []corev1.Pod, evictionGroupVersion schema.GroupV
 					return
 				} else if apierrors.IsTooManyRequests(err) {
 					fmt.Fprintf(d.ErrOut, "error when evicting pods/%q -n %q (will retry after 5s): %v\n", activePod.Name, activePod.Namespace, err)
-					time.Sleep(5 * time.Second)
+					if d.Pacer != nil {
+						d.Pacer.Backoff()
+					} else {
+						time.Sleep(5 * time.Second)
+					}
 				} else if !activePod.ObjectMeta.DeletionTimestamp.IsZero() && apierrors.IsForbidden(err) && apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
 					// an eviction request in a deleting namespace will throw a forbidden error,
 					// if the pod is already marked deleted, we can ignore this error, an eviction
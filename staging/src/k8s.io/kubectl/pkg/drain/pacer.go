@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"sync"
+	"time"
+)
+
+// EvictionPacer coordinates eviction retries across node drains that share a
+// Helper. When one eviction is rejected because a PodDisruptionBudget has no
+// disruptions left, all evictions going through the pacer hold off together
+// until the backoff window passes, instead of each per-pod retry loop hitting
+// the API server independently.
+type EvictionPacer struct {
+	interval time.Duration
+
+	lock  sync.Mutex
+	until time.Time
+}
+
+// NewEvictionPacer returns a pacer that pauses evictions for interval after
+// each disruption budget rejection.
+func NewEvictionPacer(interval time.Duration) *EvictionPacer {
+	return &EvictionPacer{interval: interval}
+}
+
+// Backoff opens a new backoff window, extending any window already open.
+func (p *EvictionPacer) Backoff() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if until := time.Now().Add(p.interval); until.After(p.until) {
+		p.until = until
+	}
+}
+
+// Wait blocks until the current backoff window, if any, has passed.
+func (p *EvictionPacer) Wait() {
+	for {
+		p.lock.Lock()
+		remaining := time.Until(p.until)
+		p.lock.Unlock()
+		if remaining <= 0 {
+			return
+		}
+		time.Sleep(remaining)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictionPacerWaitWithoutBackoff(t *testing.T) {
+	pacer := NewEvictionPacer(time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		pacer.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Wait should return immediately when no backoff window is open")
+	}
+}
+
+func TestEvictionPacerBackoff(t *testing.T) {
+	pacer := NewEvictionPacer(50 * time.Millisecond)
+	pacer.Backoff()
+
+	start := time.Now()
+	pacer.Wait()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Wait returned after %v, expected it to hold for the full backoff window", elapsed)
+	}
+
+	// the window is shared: a second waiter entering after it passed does not block
+	pacer.Wait()
+}
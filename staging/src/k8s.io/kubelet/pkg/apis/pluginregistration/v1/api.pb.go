@@ -81,6 +81,11 @@ type PluginInfo struct {
 	// to choose any preferred version from this list, or returns an error
 	// if none of the listed versions is supported.
 	SupportedVersions []string `protobuf:"bytes,4,rep,name=supported_versions,json=supportedVersions" json:"supported_versions,omitempty"`
+	// Optional list of capability identifiers the plugin supports beyond the
+	// base registration protocol, e.g. HealthPing or GracefulDeregistration.
+	// The Kubelet component ignores capabilities it does not recognize and only
+	// exercises protocol extensions the plugin advertised.
+	Capabilities []string `protobuf:"bytes,5,rep,name=capabilities" json:"capabilities,omitempty"`
 }
 
 func (m *PluginInfo) Reset()                    { *m = PluginInfo{} }
@@ -115,6 +120,13 @@ func (m *PluginInfo) GetSupportedVersions() []string {
 	return nil
 }
 
+func (m *PluginInfo) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 // RegistrationStatus is the message sent from Kubelet pluginwatcher to the plugin for notification on registration status
 type RegistrationStatus struct {
 	// True if plugin gets registered successfully at Kubelet
@@ -317,6 +329,21 @@ func (m *PluginInfo) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -420,6 +447,12 @@ func (m *PluginInfo) Size() (n int) {
 			n += 1 + l + sovApi(uint64(l))
 		}
 	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			l = len(s)
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -470,6 +503,7 @@ func (this *PluginInfo) String() string {
 		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
 		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
 		`SupportedVersions:` + fmt.Sprintf("%v", this.SupportedVersions) + `,`,
+		`Capabilities:` + fmt.Sprintf("%v", this.Capabilities) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -656,6 +690,35 @@ func (m *PluginInfo) Unmarshal(dAtA []byte) error {
 			}
 			m.SupportedVersions = append(m.SupportedVersions, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Capabilities = append(m.Capabilities, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
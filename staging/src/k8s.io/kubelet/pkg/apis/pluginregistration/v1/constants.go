@@ -23,4 +23,12 @@ const (
 	DevicePlugin = "DevicePlugin"
 	// DRAPlugin identifier for registered Dynamic Resourc Allocation plugins
 	DRAPlugin = "DRAPlugin"
+
+	// CapabilityHealthPing indicates the plugin keeps serving GetInfo on its
+	// registration socket after registration, so the Kubelet may periodically
+	// ping it and deregister the plugin when the socket goes stale.
+	CapabilityHealthPing = "HealthPing"
+	// CapabilityGracefulDeregistration indicates the plugin wants to be
+	// notified via NotifyRegistrationStatus before the Kubelet deregisters it.
+	CapabilityGracefulDeregistration = "GracefulDeregistration"
 )
@@ -42,6 +42,13 @@ type NodeMetrics struct {
 
 	// The memory usage is the memory working set.
 	Usage corev1.ResourceList
+
+	// Percentiles of the usage distribution over the reporting window, such
+	// as p50 and p95. Only populated when the client requested percentile
+	// aggregation and the backing implementation supports it; the window the
+	// aggregates were computed over is reported in the window field.
+	// +optional
+	Percentiles []UsagePercentile
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -98,4 +105,19 @@ type ContainerMetrics struct {
 	Name string
 	// The memory usage is the memory working set.
 	Usage corev1.ResourceList
+
+	// Percentiles of the container's usage distribution over the reporting
+	// window. Only populated when the client requested percentile aggregation
+	// and the backing implementation supports it.
+	// +optional
+	Percentiles []UsagePercentile
+}
+
+// UsagePercentile is the usage of a resource at a single percentile of the
+// distribution observed over the reporting window.
+type UsagePercentile struct {
+	// Percentile identifies the quantile, e.g. "p50" or "p95".
+	Percentile string
+	// The usage at this percentile.
+	Usage corev1.ResourceList
 }
@@ -87,12 +87,23 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*UsagePercentile)(nil), (*metrics.UsagePercentile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_UsagePercentile_To_metrics_UsagePercentile(a.(*UsagePercentile), b.(*metrics.UsagePercentile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metrics.UsagePercentile)(nil), (*UsagePercentile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metrics_UsagePercentile_To_v1alpha1_UsagePercentile(a.(*metrics.UsagePercentile), b.(*UsagePercentile), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
 func autoConvert_v1alpha1_ContainerMetrics_To_metrics_ContainerMetrics(in *ContainerMetrics, out *metrics.ContainerMetrics, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Usage = *(*v1.ResourceList)(unsafe.Pointer(&in.Usage))
+	out.Percentiles = *(*[]metrics.UsagePercentile)(unsafe.Pointer(&in.Percentiles))
 	return nil
 }
 
@@ -104,6 +115,7 @@ func Convert_v1alpha1_ContainerMetrics_To_metrics_ContainerMetrics(in *Container
 func autoConvert_metrics_ContainerMetrics_To_v1alpha1_ContainerMetrics(in *metrics.ContainerMetrics, out *ContainerMetrics, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Usage = *(*v1.ResourceList)(unsafe.Pointer(&in.Usage))
+	out.Percentiles = *(*[]UsagePercentile)(unsafe.Pointer(&in.Percentiles))
 	return nil
 }
 
@@ -117,6 +129,7 @@ func autoConvert_v1alpha1_NodeMetrics_To_metrics_NodeMetrics(in *NodeMetrics, ou
 	out.Timestamp = in.Timestamp
 	out.Window = in.Window
 	out.Usage = *(*v1.ResourceList)(unsafe.Pointer(&in.Usage))
+	out.Percentiles = *(*[]metrics.UsagePercentile)(unsafe.Pointer(&in.Percentiles))
 	return nil
 }
 
@@ -130,6 +143,7 @@ func autoConvert_metrics_NodeMetrics_To_v1alpha1_NodeMetrics(in *metrics.NodeMet
 	out.Timestamp = in.Timestamp
 	out.Window = in.Window
 	out.Usage = *(*v1.ResourceList)(unsafe.Pointer(&in.Usage))
+	out.Percentiles = *(*[]UsagePercentile)(unsafe.Pointer(&in.Percentiles))
 	return nil
 }
 
@@ -207,3 +221,25 @@ func autoConvert_metrics_PodMetricsList_To_v1alpha1_PodMetricsList(in *metrics.P
 func Convert_metrics_PodMetricsList_To_v1alpha1_PodMetricsList(in *metrics.PodMetricsList, out *PodMetricsList, s conversion.Scope) error {
 	return autoConvert_metrics_PodMetricsList_To_v1alpha1_PodMetricsList(in, out, s)
 }
+
+func autoConvert_v1alpha1_UsagePercentile_To_metrics_UsagePercentile(in *UsagePercentile, out *metrics.UsagePercentile, s conversion.Scope) error {
+	out.Percentile = in.Percentile
+	out.Usage = *(*v1.ResourceList)(unsafe.Pointer(&in.Usage))
+	return nil
+}
+
+// Convert_v1alpha1_UsagePercentile_To_metrics_UsagePercentile is an autogenerated conversion function.
+func Convert_v1alpha1_UsagePercentile_To_metrics_UsagePercentile(in *UsagePercentile, out *metrics.UsagePercentile, s conversion.Scope) error {
+	return autoConvert_v1alpha1_UsagePercentile_To_metrics_UsagePercentile(in, out, s)
+}
+
+func autoConvert_metrics_UsagePercentile_To_v1alpha1_UsagePercentile(in *metrics.UsagePercentile, out *UsagePercentile, s conversion.Scope) error {
+	out.Percentile = in.Percentile
+	out.Usage = *(*v1.ResourceList)(unsafe.Pointer(&in.Usage))
+	return nil
+}
+
+// Convert_metrics_UsagePercentile_To_v1alpha1_UsagePercentile is an autogenerated conversion function.
+func Convert_metrics_UsagePercentile_To_v1alpha1_UsagePercentile(in *metrics.UsagePercentile, out *UsagePercentile, s conversion.Scope) error {
+	return autoConvert_metrics_UsagePercentile_To_v1alpha1_UsagePercentile(in, out, s)
+}
@@ -36,6 +36,13 @@ func (in *ContainerMetrics) DeepCopyInto(out *ContainerMetrics) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.Percentiles != nil {
+		in, out := &in.Percentiles, &out.Percentiles
+		*out = make([]UsagePercentile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -63,6 +70,13 @@ func (in *NodeMetrics) DeepCopyInto(out *NodeMetrics) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.Percentiles != nil {
+		in, out := &in.Percentiles, &out.Percentiles
+		*out = make([]UsagePercentile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -184,3 +198,26 @@ func (in *PodMetricsList) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsagePercentile) DeepCopyInto(out *UsagePercentile) {
+	*out = *in
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsagePercentile.
+func (in *UsagePercentile) DeepCopy() *UsagePercentile {
+	if in == nil {
+		return nil
+	}
+	out := new(UsagePercentile)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -42,6 +42,13 @@ type NodeMetrics struct {
 
 	// The memory usage is the memory working set.
 	Usage v1.ResourceList `json:"usage" protobuf:"bytes,4,rep,name=usage,casttype=k8s.io/api/core/v1.ResourceList,castkey=k8s.io/api/core/v1.ResourceName,castvalue=k8s.io/apimachinery/pkg/api/resource.Quantity"`
+
+	// Percentiles of the usage distribution over the reporting window, such
+	// as p50 and p95. Only populated when the client requested percentile
+	// aggregation and the backing implementation supports it; the window the
+	// aggregates were computed over is reported in the window field.
+	// +optional
+	Percentiles []UsagePercentile `json:"percentiles,omitempty" protobuf:"bytes,5,rep,name=percentiles"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -98,4 +105,19 @@ type ContainerMetrics struct {
 	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
 	// The memory usage is the memory working set.
 	Usage v1.ResourceList `json:"usage" protobuf:"bytes,2,rep,name=usage,casttype=k8s.io/api/core/v1.ResourceList,castkey=k8s.io/api/core/v1.ResourceName,castvalue=k8s.io/apimachinery/pkg/api/resource.Quantity"`
+
+	// Percentiles of the container's usage distribution over the reporting
+	// window. Only populated when the client requested percentile aggregation
+	// and the backing implementation supports it.
+	// +optional
+	Percentiles []UsagePercentile `json:"percentiles,omitempty" protobuf:"bytes,3,rep,name=percentiles"`
+}
+
+// UsagePercentile is the usage of a resource at a single percentile of the
+// distribution observed over the reporting window.
+type UsagePercentile struct {
+	// Percentile identifies the quantile, e.g. "p50" or "p95".
+	Percentile string `json:"percentile" protobuf:"bytes,1,opt,name=percentile"`
+	// The usage at this percentile.
+	Usage v1.ResourceList `json:"usage" protobuf:"bytes,2,rep,name=usage,casttype=k8s.io/api/core/v1.ResourceList,castkey=k8s.io/api/core/v1.ResourceName,castvalue=k8s.io/apimachinery/pkg/api/resource.Quantity"`
 }
@@ -0,0 +1,611 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package criproxy provides a CRI proxy that sits between the kubelet and the
+// container runtime during e2e_node tests. Tests register injectors that add
+// latency, return errors, or mutate responses for specific RPCs, which allows
+// exercising kubelet resilience against runtime failures (image pull
+// timeouts, sandbox creation failures, ...) against a real runtime.
+package criproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/kubelet/util"
+)
+
+const (
+	// defaultDialTimeout is how long to wait for the connection to the real
+	// runtime to come up.
+	defaultDialTimeout = 30 * time.Second
+	// maxMsgSize matches the limit the kubelet uses when talking to the runtime.
+	maxMsgSize = 1024 * 1024 * 16
+)
+
+// RPC names that can be passed to AddInjector, one per CRI method.
+const (
+	Version                  = "Version"
+	RunPodSandbox            = "RunPodSandbox"
+	StopPodSandbox           = "StopPodSandbox"
+	RemovePodSandbox         = "RemovePodSandbox"
+	PodSandboxStatus         = "PodSandboxStatus"
+	ListPodSandbox           = "ListPodSandbox"
+	CreateContainer          = "CreateContainer"
+	StartContainer           = "StartContainer"
+	StopContainer            = "StopContainer"
+	RemoveContainer          = "RemoveContainer"
+	ListContainers           = "ListContainers"
+	ContainerStatus          = "ContainerStatus"
+	UpdateContainerResources = "UpdateContainerResources"
+	ReopenContainerLog       = "ReopenContainerLog"
+	ExecSync                 = "ExecSync"
+	Exec                     = "Exec"
+	Attach                   = "Attach"
+	PortForward              = "PortForward"
+	ContainerStats           = "ContainerStats"
+	ListContainerStats       = "ListContainerStats"
+	PodSandboxStats          = "PodSandboxStats"
+	ListPodSandboxStats      = "ListPodSandboxStats"
+	UpdateRuntimeConfig      = "UpdateRuntimeConfig"
+	Status                   = "Status"
+	CheckpointContainer      = "CheckpointContainer"
+	ListMetricDescriptors    = "ListMetricDescriptors"
+	ListPodSandboxMetrics    = "ListPodSandboxMetrics"
+	GetContainerEvents       = "GetContainerEvents"
+	ListImages               = "ListImages"
+	ImageStatus              = "ImageStatus"
+	PullImage                = "PullImage"
+	RemoveImage              = "RemoveImage"
+	ImageFsInfo              = "ImageFsInfo"
+)
+
+// Injector perturbs a single RPC before it is forwarded to the runtime.
+// Returning an error fails the RPC with that error; sleeping adds latency.
+type Injector func(apiName string) error
+
+// ResponseMutator rewrites the response of a successfully forwarded RPC
+// before it is returned to the kubelet, to simulate partial responses.
+type ResponseMutator func(apiName string, resp interface{})
+
+// Proxy is a CRI gRPC server that forwards every RPC to a real runtime after
+// running the registered injectors.
+type Proxy struct {
+	runtime runtimeapi.RuntimeServiceClient
+	image   runtimeapi.ImageServiceClient
+	conn    *grpc.ClientConn
+	server  *grpc.Server
+
+	lock             sync.Mutex
+	injectors        []Injector
+	responseMutators []ResponseMutator
+}
+
+var _ runtimeapi.RuntimeServiceServer = &Proxy{}
+var _ runtimeapi.ImageServiceServer = &Proxy{}
+
+// NewProxy connects to the runtime at remoteEndpoint and returns a proxy that
+// is ready to be started with Start.
+func NewProxy(remoteEndpoint string) (*Proxy, error) {
+	addr, dialer, err := util.GetAddressAndDialer(remoteEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to runtime %q: %w", remoteEndpoint, err)
+	}
+
+	p := &Proxy{
+		runtime: runtimeapi.NewRuntimeServiceClient(conn),
+		image:   runtimeapi.NewImageServiceClient(conn),
+		conn:    conn,
+		server:  grpc.NewServer(grpc.MaxRecvMsgSize(maxMsgSize)),
+	}
+	runtimeapi.RegisterRuntimeServiceServer(p.server, p)
+	runtimeapi.RegisterImageServiceServer(p.server, p)
+	return p, nil
+}
+
+// Start serves the proxy on localEndpoint; the kubelet is then pointed at
+// that endpoint instead of the real runtime.
+func (p *Proxy) Start(localEndpoint string) error {
+	l, err := util.CreateListener(localEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", localEndpoint, err)
+	}
+	go func() {
+		if err := p.server.Serve(l); err != nil {
+			klog.ErrorS(err, "CRI proxy server stopped serving")
+		}
+	}()
+	return nil
+}
+
+// Stop stops the proxy server and closes the connection to the runtime.
+func (p *Proxy) Stop() {
+	p.server.Stop()
+	p.conn.Close()
+}
+
+// AddInjector registers an injector that runs before every forwarded RPC.
+func (p *Proxy) AddInjector(injector Injector) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.injectors = append(p.injectors, injector)
+}
+
+// AddResponseMutator registers a mutator that runs on every successful
+// response before it is returned to the kubelet.
+func (p *Proxy) AddResponseMutator(mutator ResponseMutator) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.responseMutators = append(p.responseMutators, mutator)
+}
+
+// ResetInjectors removes all registered injectors and response mutators,
+// turning the proxy back into a transparent pass-through.
+func (p *Proxy) ResetInjectors() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.injectors = nil
+	p.responseMutators = nil
+}
+
+func (p *Proxy) runInjectors(apiName string) error {
+	p.lock.Lock()
+	injectors := make([]Injector, len(p.injectors))
+	copy(injectors, p.injectors)
+	p.lock.Unlock()
+
+	for _, injector := range injectors {
+		if err := injector(apiName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Proxy) runResponseMutators(apiName string, resp interface{}) {
+	p.lock.Lock()
+	mutators := make([]ResponseMutator, len(p.responseMutators))
+	copy(mutators, p.responseMutators)
+	p.lock.Unlock()
+
+	for _, mutator := range mutators {
+		mutator(apiName, resp)
+	}
+}
+
+// GetContainerEvents forwards the event stream from the runtime. Injectors
+// run once when the stream is established.
+func (p *Proxy) GetContainerEvents(req *runtimeapi.GetEventsRequest, srv runtimeapi.RuntimeService_GetContainerEventsServer) error {
+	if err := p.runInjectors(GetContainerEvents); err != nil {
+		return err
+	}
+	stream, err := p.runtime.GetContainerEvents(srv.Context(), req)
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := srv.Send(event); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *Proxy) Version(ctx context.Context, req *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	if err := p.runInjectors(Version); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.Version(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(Version, resp)
+	return resp, nil
+}
+
+func (p *Proxy) RunPodSandbox(ctx context.Context, req *runtimeapi.RunPodSandboxRequest) (*runtimeapi.RunPodSandboxResponse, error) {
+	if err := p.runInjectors(RunPodSandbox); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.RunPodSandbox(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(RunPodSandbox, resp)
+	return resp, nil
+}
+
+func (p *Proxy) StopPodSandbox(ctx context.Context, req *runtimeapi.StopPodSandboxRequest) (*runtimeapi.StopPodSandboxResponse, error) {
+	if err := p.runInjectors(StopPodSandbox); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.StopPodSandbox(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(StopPodSandbox, resp)
+	return resp, nil
+}
+
+func (p *Proxy) RemovePodSandbox(ctx context.Context, req *runtimeapi.RemovePodSandboxRequest) (*runtimeapi.RemovePodSandboxResponse, error) {
+	if err := p.runInjectors(RemovePodSandbox); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.RemovePodSandbox(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(RemovePodSandbox, resp)
+	return resp, nil
+}
+
+func (p *Proxy) PodSandboxStatus(ctx context.Context, req *runtimeapi.PodSandboxStatusRequest) (*runtimeapi.PodSandboxStatusResponse, error) {
+	if err := p.runInjectors(PodSandboxStatus); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.PodSandboxStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(PodSandboxStatus, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListPodSandbox(ctx context.Context, req *runtimeapi.ListPodSandboxRequest) (*runtimeapi.ListPodSandboxResponse, error) {
+	if err := p.runInjectors(ListPodSandbox); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ListPodSandbox(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListPodSandbox, resp)
+	return resp, nil
+}
+
+func (p *Proxy) CreateContainer(ctx context.Context, req *runtimeapi.CreateContainerRequest) (*runtimeapi.CreateContainerResponse, error) {
+	if err := p.runInjectors(CreateContainer); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.CreateContainer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(CreateContainer, resp)
+	return resp, nil
+}
+
+func (p *Proxy) StartContainer(ctx context.Context, req *runtimeapi.StartContainerRequest) (*runtimeapi.StartContainerResponse, error) {
+	if err := p.runInjectors(StartContainer); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.StartContainer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(StartContainer, resp)
+	return resp, nil
+}
+
+func (p *Proxy) StopContainer(ctx context.Context, req *runtimeapi.StopContainerRequest) (*runtimeapi.StopContainerResponse, error) {
+	if err := p.runInjectors(StopContainer); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.StopContainer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(StopContainer, resp)
+	return resp, nil
+}
+
+func (p *Proxy) RemoveContainer(ctx context.Context, req *runtimeapi.RemoveContainerRequest) (*runtimeapi.RemoveContainerResponse, error) {
+	if err := p.runInjectors(RemoveContainer); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.RemoveContainer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(RemoveContainer, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListContainers(ctx context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	if err := p.runInjectors(ListContainers); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ListContainers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListContainers, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ContainerStatus(ctx context.Context, req *runtimeapi.ContainerStatusRequest) (*runtimeapi.ContainerStatusResponse, error) {
+	if err := p.runInjectors(ContainerStatus); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ContainerStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ContainerStatus, resp)
+	return resp, nil
+}
+
+func (p *Proxy) UpdateContainerResources(ctx context.Context, req *runtimeapi.UpdateContainerResourcesRequest) (*runtimeapi.UpdateContainerResourcesResponse, error) {
+	if err := p.runInjectors(UpdateContainerResources); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.UpdateContainerResources(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(UpdateContainerResources, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ReopenContainerLog(ctx context.Context, req *runtimeapi.ReopenContainerLogRequest) (*runtimeapi.ReopenContainerLogResponse, error) {
+	if err := p.runInjectors(ReopenContainerLog); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ReopenContainerLog(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ReopenContainerLog, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ExecSync(ctx context.Context, req *runtimeapi.ExecSyncRequest) (*runtimeapi.ExecSyncResponse, error) {
+	if err := p.runInjectors(ExecSync); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ExecSync(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ExecSync, resp)
+	return resp, nil
+}
+
+func (p *Proxy) Exec(ctx context.Context, req *runtimeapi.ExecRequest) (*runtimeapi.ExecResponse, error) {
+	if err := p.runInjectors(Exec); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.Exec(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(Exec, resp)
+	return resp, nil
+}
+
+func (p *Proxy) Attach(ctx context.Context, req *runtimeapi.AttachRequest) (*runtimeapi.AttachResponse, error) {
+	if err := p.runInjectors(Attach); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.Attach(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(Attach, resp)
+	return resp, nil
+}
+
+func (p *Proxy) PortForward(ctx context.Context, req *runtimeapi.PortForwardRequest) (*runtimeapi.PortForwardResponse, error) {
+	if err := p.runInjectors(PortForward); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.PortForward(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(PortForward, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ContainerStats(ctx context.Context, req *runtimeapi.ContainerStatsRequest) (*runtimeapi.ContainerStatsResponse, error) {
+	if err := p.runInjectors(ContainerStats); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ContainerStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ContainerStats, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListContainerStats(ctx context.Context, req *runtimeapi.ListContainerStatsRequest) (*runtimeapi.ListContainerStatsResponse, error) {
+	if err := p.runInjectors(ListContainerStats); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ListContainerStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListContainerStats, resp)
+	return resp, nil
+}
+
+func (p *Proxy) PodSandboxStats(ctx context.Context, req *runtimeapi.PodSandboxStatsRequest) (*runtimeapi.PodSandboxStatsResponse, error) {
+	if err := p.runInjectors(PodSandboxStats); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.PodSandboxStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(PodSandboxStats, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListPodSandboxStats(ctx context.Context, req *runtimeapi.ListPodSandboxStatsRequest) (*runtimeapi.ListPodSandboxStatsResponse, error) {
+	if err := p.runInjectors(ListPodSandboxStats); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ListPodSandboxStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListPodSandboxStats, resp)
+	return resp, nil
+}
+
+func (p *Proxy) UpdateRuntimeConfig(ctx context.Context, req *runtimeapi.UpdateRuntimeConfigRequest) (*runtimeapi.UpdateRuntimeConfigResponse, error) {
+	if err := p.runInjectors(UpdateRuntimeConfig); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.UpdateRuntimeConfig(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(UpdateRuntimeConfig, resp)
+	return resp, nil
+}
+
+func (p *Proxy) Status(ctx context.Context, req *runtimeapi.StatusRequest) (*runtimeapi.StatusResponse, error) {
+	if err := p.runInjectors(Status); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.Status(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(Status, resp)
+	return resp, nil
+}
+
+func (p *Proxy) CheckpointContainer(ctx context.Context, req *runtimeapi.CheckpointContainerRequest) (*runtimeapi.CheckpointContainerResponse, error) {
+	if err := p.runInjectors(CheckpointContainer); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.CheckpointContainer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(CheckpointContainer, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListMetricDescriptors(ctx context.Context, req *runtimeapi.ListMetricDescriptorsRequest) (*runtimeapi.ListMetricDescriptorsResponse, error) {
+	if err := p.runInjectors(ListMetricDescriptors); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ListMetricDescriptors(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListMetricDescriptors, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListPodSandboxMetrics(ctx context.Context, req *runtimeapi.ListPodSandboxMetricsRequest) (*runtimeapi.ListPodSandboxMetricsResponse, error) {
+	if err := p.runInjectors(ListPodSandboxMetrics); err != nil {
+		return nil, err
+	}
+	resp, err := p.runtime.ListPodSandboxMetrics(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListPodSandboxMetrics, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ListImages(ctx context.Context, req *runtimeapi.ListImagesRequest) (*runtimeapi.ListImagesResponse, error) {
+	if err := p.runInjectors(ListImages); err != nil {
+		return nil, err
+	}
+	resp, err := p.image.ListImages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ListImages, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ImageStatus(ctx context.Context, req *runtimeapi.ImageStatusRequest) (*runtimeapi.ImageStatusResponse, error) {
+	if err := p.runInjectors(ImageStatus); err != nil {
+		return nil, err
+	}
+	resp, err := p.image.ImageStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ImageStatus, resp)
+	return resp, nil
+}
+
+func (p *Proxy) PullImage(ctx context.Context, req *runtimeapi.PullImageRequest) (*runtimeapi.PullImageResponse, error) {
+	if err := p.runInjectors(PullImage); err != nil {
+		return nil, err
+	}
+	resp, err := p.image.PullImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(PullImage, resp)
+	return resp, nil
+}
+
+func (p *Proxy) RemoveImage(ctx context.Context, req *runtimeapi.RemoveImageRequest) (*runtimeapi.RemoveImageResponse, error) {
+	if err := p.runInjectors(RemoveImage); err != nil {
+		return nil, err
+	}
+	resp, err := p.image.RemoveImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(RemoveImage, resp)
+	return resp, nil
+}
+
+func (p *Proxy) ImageFsInfo(ctx context.Context, req *runtimeapi.ImageFsInfoRequest) (*runtimeapi.ImageFsInfoResponse, error) {
+	if err := p.runInjectors(ImageFsInfo); err != nil {
+		return nil, err
+	}
+	resp, err := p.image.ImageFsInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.runResponseMutators(ImageFsInfo, resp)
+	return resp, nil
+}
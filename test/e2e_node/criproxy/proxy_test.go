@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package criproxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cri/remote"
+	fakeremote "k8s.io/kubernetes/pkg/kubelet/cri/remote/fake"
+)
+
+const defaultConnectionTimeout = 15 * time.Second
+
+// startProxy wires a proxy in front of a fake runtime and returns a kubelet
+// style runtime client talking through the proxy.
+func startProxy(t *testing.T) (*Proxy, internalapi.RuntimeService) {
+	t.Helper()
+
+	runtimeEndpoint, err := fakeremote.GenerateEndpoint()
+	if err != nil {
+		t.Fatalf("failed to generate runtime endpoint: %v", err)
+	}
+	fakeRuntime := fakeremote.NewFakeRemoteRuntime()
+	if err := fakeRuntime.Start(runtimeEndpoint); err != nil {
+		t.Fatalf("failed to start fake runtime: %v", err)
+	}
+	t.Cleanup(fakeRuntime.Stop)
+
+	proxy, err := NewProxy(runtimeEndpoint)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	proxyEndpoint, err := fakeremote.GenerateEndpoint()
+	if err != nil {
+		t.Fatalf("failed to generate proxy endpoint: %v", err)
+	}
+	if err := proxy.Start(proxyEndpoint); err != nil {
+		t.Fatalf("failed to start proxy: %v", err)
+	}
+	t.Cleanup(proxy.Stop)
+
+	runtimeService, err := remote.NewRemoteRuntimeService(proxyEndpoint, defaultConnectionTimeout, oteltrace.NewNoopTracerProvider())
+	if err != nil {
+		t.Fatalf("failed to create runtime client: %v", err)
+	}
+	return proxy, runtimeService
+}
+
+func TestProxyPassThrough(t *testing.T) {
+	_, runtimeService := startProxy(t)
+
+	version, err := runtimeService.Version(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Version through proxy failed: %v", err)
+	}
+	if version.RuntimeName == "" {
+		t.Error("Version returned an empty runtime name")
+	}
+}
+
+func TestProxyErrorInjection(t *testing.T) {
+	proxy, runtimeService := startProxy(t)
+
+	proxy.AddInjector(func(apiName string) error {
+		if apiName == Version {
+			return fmt.Errorf("injected %s failure", apiName)
+		}
+		return nil
+	})
+
+	if _, err := runtimeService.Version(context.Background(), ""); err == nil {
+		t.Fatal("Version succeeded despite injected error")
+	}
+	// Other RPCs stay unaffected.
+	if _, err := runtimeService.Status(context.Background(), false); err != nil {
+		t.Fatalf("Status through proxy failed: %v", err)
+	}
+
+	proxy.ResetInjectors()
+	if _, err := runtimeService.Version(context.Background(), ""); err != nil {
+		t.Fatalf("Version after ResetInjectors failed: %v", err)
+	}
+}
+
+func TestProxyLatencyInjection(t *testing.T) {
+	proxy, runtimeService := startProxy(t)
+
+	const delay = 300 * time.Millisecond
+	proxy.AddInjector(func(apiName string) error {
+		if apiName == Version {
+			time.Sleep(delay)
+		}
+		return nil
+	})
+
+	start := time.Now()
+	if _, err := runtimeService.Version(context.Background(), ""); err != nil {
+		t.Fatalf("Version through proxy failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("Version returned after %v, want at least %v", elapsed, delay)
+	}
+}
+
+func TestProxyResponseMutation(t *testing.T) {
+	proxy, runtimeService := startProxy(t)
+
+	proxy.AddResponseMutator(func(apiName string, resp interface{}) {
+		if versionResp, ok := resp.(*runtimeapi.VersionResponse); ok {
+			versionResp.RuntimeName = "mutated"
+		}
+	})
+
+	version, err := runtimeService.Version(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Version through proxy failed: %v", err)
+	}
+	if version.RuntimeName != "mutated" {
+		t.Errorf("RuntimeName = %q, want %q", version.RuntimeName, "mutated")
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transformation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/client-go/kubernetes"
+	kubeapiservertesting "k8s.io/kubernetes/cmd/kube-apiserver/app/testing"
+	"k8s.io/kubernetes/test/integration/framework"
+)
+
+const (
+	// the intermediate configuration of a safe HA rollout: still writing
+	// plaintext, but already able to read records encrypted by members that
+	// have moved ahead
+	writePlainReadAESCBCConfigYAML = `
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - identity: {}
+    - aescbc:
+        keys:
+        - name: key1
+          secret: c2VjcmV0IGlzIHNlY3VyZQ==
+`
+
+	// the final configuration of a safe HA rollout: writing encrypted, still
+	// able to read plaintext records written by members that lag behind
+	writeAESCBCReadPlainConfigYAML = `
+kind: EncryptionConfiguration
+apiVersion: apiserver.config.k8s.io/v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - aescbc:
+        keys:
+        - name: key1
+          secret: c2VjcmV0IGlzIHNlY3VyZQ==
+    - identity: {}
+`
+)
+
+// haTransformTest drives several kube-apiservers that share a single etcd but
+// each hold their own EncryptionConfiguration, the situation an HA control
+// plane is in while an encryption rollout is staggered across its members.
+type haTransformTest struct {
+	logger kubeapiservertesting.Logger
+	// the members share this one config so they share one etcd prefix
+	storageConfig *storagebackend.Config
+	members       []*transformTest
+}
+
+// newHATransformTest starts one kube-apiserver per entry of
+// memberConfigYAMLs, all sharing a single etcd, each with its own encryption
+// config dir holding its entry. The test namespace is created once and is
+// visible to every member through the shared storage.
+func newHATransformTest(l kubeapiservertesting.Logger, memberConfigYAMLs []string) (*haTransformTest, error) {
+	ha := &haTransformTest{
+		logger:        l,
+		storageConfig: framework.SharedEtcd(),
+	}
+
+	for i, configYAML := range memberConfigYAMLs {
+		if err := ha.startMember(configYAML); err != nil {
+			ha.cleanUp()
+			return nil, fmt.Errorf("error while starting member %d: %v", i, err)
+		}
+	}
+
+	ns, err := ha.members[0].createNamespace(testNamespace)
+	if err != nil {
+		ha.cleanUp()
+		return nil, err
+	}
+	for _, member := range ha.members {
+		member.ns = ns
+	}
+
+	return ha, nil
+}
+
+// startMember starts an additional kube-apiserver against the shared etcd
+// with its own encryption config dir holding configYAML.
+func (ha *haTransformTest) startMember(configYAML string) error {
+	member := &transformTest{
+		logger:            ha.logger,
+		transformerConfig: configYAML,
+		storageConfig:     ha.storageConfig,
+	}
+	// the member is tracked before it is fully started so that cleanUp
+	// covers partially constructed members
+	ha.members = append(ha.members, member)
+
+	var err error
+	if member.configDir, member.configParentDir, err = member.createEncryptionConfig(false); err != nil {
+		return fmt.Errorf("error while creating encryption config: %v", err)
+	}
+
+	if member.kubeAPIServer, err = kubeapiservertesting.StartTestServer(ha.logger, nil, member.getEncryptionOptions(false), ha.storageConfig); err != nil {
+		return fmt.Errorf("failed to start KubeAPI server: %v", err)
+	}
+
+	if member.restClient, err = kubernetes.NewForConfig(member.kubeAPIServer.ClientConfig); err != nil {
+		return fmt.Errorf("error while creating rest client: %v", err)
+	}
+
+	return nil
+}
+
+// restartMember tears one member down and starts it again with a new
+// encryption configuration, the way a rolling config change restarts one
+// member of an HA control plane at a time. The other members keep serving
+// throughout.
+func (ha *haTransformTest) restartMember(i int, configYAML string) error {
+	member := ha.members[i]
+	member.kubeAPIServer.TearDownFn()
+
+	member.transformerConfig = configYAML
+	if err := os.WriteFile(filepath.Join(member.configDir, encryptionConfigFileName), []byte(configYAML), 0644); err != nil {
+		return fmt.Errorf("error while rewriting encryption config of member %d: %v", i, err)
+	}
+
+	var err error
+	if member.kubeAPIServer, err = kubeapiservertesting.StartTestServer(ha.logger, nil, member.getEncryptionOptions(false), ha.storageConfig); err != nil {
+		return fmt.Errorf("failed to restart member %d: %v", i, err)
+	}
+
+	if member.restClient, err = kubernetes.NewForConfig(member.kubeAPIServer.ClientConfig); err != nil {
+		return fmt.Errorf("error while creating rest client of member %d: %v", i, err)
+	}
+
+	return nil
+}
+
+func (ha *haTransformTest) cleanUp() {
+	for _, member := range ha.members {
+		os.RemoveAll(member.configDir)
+		os.RemoveAll(member.configParentDir)
+		if member.restClient != nil && member.ns != nil {
+			// the namespace is shared; deleting it more than once is harmless
+			member.restClient.CoreV1().Namespaces().Delete(context.TODO(), member.ns.Name, *metav1.NewDeleteOptions(0))
+		}
+		if member.kubeAPIServer.TearDownFn != nil {
+			member.kubeAPIServer.TearDownFn()
+		}
+	}
+}
+
+// secretMustBeReadableBy asserts that the named secret written by one member
+// round-trips through another member's API, i.e. that member can decrypt what
+// its peer stored.
+func (ha *haTransformTest) secretMustBeReadableBy(i int, name string) {
+	member := ha.members[i]
+	s, err := member.restClient.CoreV1().Secrets(testNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		ha.logger.Fatalf("member %d failed to read secret %s: %v", i, name, err)
+		return
+	}
+	if secretVal != string(s.Data[secretKey]) {
+		ha.logger.Errorf("member %d: expected %s from KubeAPI, but got %s", i, secretVal, string(s.Data[secretKey]))
+	}
+}
+
+// rawSecretMustHavePrefix asserts on the envelope prefix of the stored record
+// so tests can tell encrypted writes from plaintext ones.
+func (ha *haTransformTest) rawSecretMustHavePrefix(name, expectedPrefix string) {
+	member := ha.members[0]
+	response, err := member.readRawRecordFromETCD(member.getETCDPathForResource(ha.storageConfig.Prefix, "", "secrets", name, testNamespace))
+	if err != nil {
+		ha.logger.Fatalf("failed to read %s from etcd: %v", name, err)
+		return
+	}
+	if !bytes.HasPrefix(response.Kvs[0].Value, []byte(expectedPrefix)) {
+		ha.logger.Errorf("expected secret %s to be prefixed with %s, but got %s", name, expectedPrefix, response.Kvs[0].Value)
+	}
+}
+
+// rawSecretMustBePlaintext asserts that the stored record carries no envelope
+// prefix, i.e. it was written by a member that is still on identity.
+func (ha *haTransformTest) rawSecretMustBePlaintext(name string) {
+	member := ha.members[0]
+	response, err := member.readRawRecordFromETCD(member.getETCDPathForResource(ha.storageConfig.Prefix, "", "secrets", name, testNamespace))
+	if err != nil {
+		ha.logger.Fatalf("failed to read %s from etcd: %v", name, err)
+		return
+	}
+	if bytes.HasPrefix(response.Kvs[0].Value, []byte("k8s:enc:")) {
+		ha.logger.Errorf("expected secret %s to be stored as plaintext, but got %s", name, response.Kvs[0].Value)
+	}
+}
+
+// TestHAMembersWithDivergedEncryptionConfigs covers the HA rollout bug this
+// harness exists to catch: one member encrypts its writes while another was
+// never given the new configuration, so records written through the first
+// member cannot be served by the second.
+func TestHAMembersWithDivergedEncryptionConfigs(t *testing.T) {
+	ha, err := newHATransformTest(t, []string{aesCBCConfigYAML, identityConfigYAML})
+	if err != nil {
+		t.Fatalf("failed to start HA test: %v", err)
+	}
+	defer ha.cleanUp()
+
+	if _, err := ha.members[0].createSecret(testSecret, testNamespace); err != nil {
+		t.Fatalf("failed to create test secret, error: %v", err)
+	}
+	ha.rawSecretMustHavePrefix(testSecret, aesCBCPrefix)
+	ha.secretMustBeReadableBy(0, testSecret)
+
+	// the identity-only member has no key for the record its peer stored
+	if _, err := ha.members[1].restClient.CoreV1().Secrets(testNamespace).Get(context.TODO(), testSecret, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the member without the encryption config to fail reading the encrypted secret, but the read succeeded")
+	}
+}
+
+// TestHAStaggeredEncryptionRollout walks a two-member control plane through
+// the documented safe rollout sequence - every member learns to read the new
+// key before any member writes with it - and asserts that at each step every
+// member can serve every record, no matter which member wrote it.
+func TestHAStaggeredEncryptionRollout(t *testing.T) {
+	ha, err := newHATransformTest(t, []string{identityConfigYAML, identityConfigYAML})
+	if err != nil {
+		t.Fatalf("failed to start HA test: %v", err)
+	}
+	defer ha.cleanUp()
+
+	// both members still write plaintext
+	if _, err := ha.members[0].createSecret(testSecret+"-1", testNamespace); err != nil {
+		t.Fatalf("failed to create test secret, error: %v", err)
+	}
+	ha.rawSecretMustBePlaintext(testSecret + "-1")
+	ha.secretMustBeReadableBy(1, testSecret+"-1")
+
+	// step one: every member learns to read the new key before anyone writes with it
+	if err := ha.restartMember(1, writePlainReadAESCBCConfigYAML); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ha.members[1].createSecret(testSecret+"-2", testNamespace); err != nil {
+		t.Fatalf("failed to create test secret, error: %v", err)
+	}
+	ha.secretMustBeReadableBy(0, testSecret+"-1")
+	ha.secretMustBeReadableBy(0, testSecret+"-2")
+	ha.secretMustBeReadableBy(1, testSecret+"-1")
+
+	// step two: the first member starts writing encrypted; its peer already
+	// holds the read key and the member itself still reads plaintext records
+	if err := ha.restartMember(0, writeAESCBCReadPlainConfigYAML); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ha.members[0].createSecret(testSecret+"-3", testNamespace); err != nil {
+		t.Fatalf("failed to create test secret, error: %v", err)
+	}
+	ha.rawSecretMustHavePrefix(testSecret+"-3", aesCBCPrefix)
+	ha.secretMustBeReadableBy(0, testSecret+"-2")
+	ha.secretMustBeReadableBy(1, testSecret+"-3")
+
+	// step three: the last member flips to encrypted writes as well
+	if err := ha.restartMember(1, writeAESCBCReadPlainConfigYAML); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ha.members[1].createSecret(testSecret+"-4", testNamespace); err != nil {
+		t.Fatalf("failed to create test secret, error: %v", err)
+	}
+	ha.rawSecretMustHavePrefix(testSecret+"-4", aesCBCPrefix)
+	for i := range ha.members {
+		for _, name := range []string{testSecret + "-1", testSecret + "-2", testSecret + "-3", testSecret + "-4"} {
+			ha.secretMustBeReadableBy(i, name)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/klog/v2"
+)
+
+// EtcdProxy is a fault-injecting TCP proxy for the test etcd. Tests point the
+// component under test at URL() instead of the real etcd and then degrade the
+// connection: add latency, drop established connections, or blackhole new
+// ones. This allows exercising storage-layer behavior (watch cache,
+// transformers, APF) under realistic etcd degradation without touching the
+// etcd process itself.
+type EtcdProxy struct {
+	target   string
+	listener net.Listener
+
+	// latencyNanos is the artificial delay added to every chunk of proxied
+	// data, stored atomically so it can be changed while traffic flows.
+	latencyNanos int64
+	// blackhole, when nonzero, makes the proxy close new connections
+	// immediately, simulating a partitioned etcd.
+	blackhole int32
+
+	lock  sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// NewEtcdProxy starts a proxy in front of the etcd reachable at targetURL
+// (e.g. the result of GetEtcdURL). Call Stop when the test is done.
+func NewEtcdProxy(targetURL string) (*EtcdProxy, error) {
+	target := strings.TrimPrefix(targetURL, "http://")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not bind etcd proxy: %v", err)
+	}
+	p := &EtcdProxy{
+		target:   target,
+		listener: listener,
+		conns:    map[net.Conn]struct{}{},
+	}
+	go p.acceptLoop()
+	return p, nil
+}
+
+// URL returns the client URL tests should use instead of the real etcd URL.
+func (p *EtcdProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// SetLatency sets the artificial delay added to all proxied traffic in each
+// direction. Zero removes the delay.
+func (p *EtcdProxy) SetLatency(d time.Duration) {
+	atomic.StoreInt64(&p.latencyNanos, d.Nanoseconds())
+}
+
+// SetBlackhole toggles dropping of new connections. Established connections
+// are unaffected; combine with DropConnections for a full partition.
+func (p *EtcdProxy) SetBlackhole(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&p.blackhole, 1)
+	} else {
+		atomic.StoreInt32(&p.blackhole, 0)
+	}
+}
+
+// DropConnections closes every established connection through the proxy.
+// Clients observe the same connection resets they would see during an etcd
+// leader change.
+func (p *EtcdProxy) DropConnections() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+}
+
+// Stop closes the proxy listener and all connections.
+func (p *EtcdProxy) Stop() {
+	p.listener.Close()
+	p.DropConnections()
+}
+
+func (p *EtcdProxy) acceptLoop() {
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		if atomic.LoadInt32(&p.blackhole) != 0 {
+			client.Close()
+			continue
+		}
+		backend, err := net.Dial("tcp", p.target)
+		if err != nil {
+			klog.Warningf("etcd proxy could not reach %s: %v", p.target, err)
+			client.Close()
+			continue
+		}
+		p.track(client)
+		p.track(backend)
+		go p.pipe(client, backend)
+		go p.pipe(backend, client)
+	}
+}
+
+func (p *EtcdProxy) track(conn net.Conn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.conns[conn] = struct{}{}
+}
+
+func (p *EtcdProxy) forget(conn net.Conn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.conns, conn)
+}
+
+// pipe copies src to dst, applying the configured latency to every chunk.
+func (p *EtcdProxy) pipe(dst, src net.Conn) {
+	defer func() {
+		dst.Close()
+		src.Close()
+		p.forget(dst)
+		p.forget(src)
+	}()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if latency := atomic.LoadInt64(&p.latencyNanos); latency > 0 {
+				time.Sleep(time.Duration(latency))
+			}
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				klog.V(4).Infof("etcd proxy connection closed: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// CompactEtcd forces a compaction of the etcd keyspace at its current
+// revision and returns the compacted revision. Watch cache and transformer
+// tests use this to provoke "required revision has been compacted" paths.
+func CompactEtcd(ctx context.Context, client *clientv3.Client) (int64, error) {
+	resp, err := client.Get(ctx, "compaction-probe")
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch current revision: %v", err)
+	}
+	rev := resp.Header.Revision
+	if _, err := client.Compact(ctx, rev, clientv3.WithCompactPhysical()); err != nil {
+		return 0, fmt.Errorf("could not compact to revision %d: %v", rev, err)
+	}
+	return rev, nil
+}
+
+// ForceEtcdLeaderChange moves etcd leadership to another member of the
+// cluster. The single-member etcd started by this framework cannot change
+// leader; tests running against it should use EtcdProxy.DropConnections to
+// approximate the client-visible effects of leader churn instead.
+func ForceEtcdLeaderChange(ctx context.Context, client *clientv3.Client) error {
+	members, err := client.MemberList(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list etcd members: %v", err)
+	}
+	if len(members.Members) < 2 {
+		return fmt.Errorf("cannot force a leader change on a cluster with %d member(s)", len(members.Members))
+	}
+
+	status, err := client.Status(ctx, client.Endpoints()[0])
+	if err != nil {
+		return fmt.Errorf("could not determine etcd leader: %v", err)
+	}
+	for _, member := range members.Members {
+		if member.ID != status.Leader {
+			if _, err := client.MoveLeader(ctx, member.ID); err != nil {
+				return fmt.Errorf("could not move etcd leadership to member %d: %v", member.ID, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no transfer target found among %d members", len(members.Members))
+}
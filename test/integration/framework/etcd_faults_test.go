@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// startEchoBackend stands in for etcd; the proxy only deals in TCP streams.
+func startEchoBackend(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				scanner := bufio.NewScanner(c)
+				for scanner.Scan() {
+					fmt.Fprintf(c, "%s\n", scanner.Text())
+				}
+			}(conn)
+		}
+	}()
+	return "http://" + l.Addr().String()
+}
+
+func dialProxy(t *testing.T, p *EtcdProxy) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", p.URL()[len("http://"):])
+	if err != nil {
+		t.Fatalf("could not dial proxy: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func roundTrip(conn net.Conn, msg string) (string, error) {
+	if _, err := fmt.Fprintf(conn, "%s\n", msg); err != nil {
+		return "", err
+	}
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	return line, err
+}
+
+func TestEtcdProxyPassThrough(t *testing.T) {
+	proxy, err := NewEtcdProxy(startEchoBackend(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	conn := dialProxy(t, proxy)
+	reply, err := roundTrip(conn, "ping")
+	if err != nil {
+		t.Fatalf("round trip through proxy failed: %v", err)
+	}
+	if reply != "ping\n" {
+		t.Errorf("reply = %q, want %q", reply, "ping\n")
+	}
+}
+
+func TestEtcdProxyLatency(t *testing.T) {
+	proxy, err := NewEtcdProxy(startEchoBackend(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	const latency = 200 * time.Millisecond
+	proxy.SetLatency(latency)
+
+	conn := dialProxy(t, proxy)
+	start := time.Now()
+	if _, err := roundTrip(conn, "ping"); err != nil {
+		t.Fatalf("round trip through proxy failed: %v", err)
+	}
+	// The delay applies in both directions.
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("round trip took %v, want at least %v", elapsed, latency)
+	}
+}
+
+func TestEtcdProxyDropConnections(t *testing.T) {
+	proxy, err := NewEtcdProxy(startEchoBackend(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	conn := dialProxy(t, proxy)
+	if _, err := roundTrip(conn, "ping"); err != nil {
+		t.Fatalf("round trip through proxy failed: %v", err)
+	}
+
+	proxy.DropConnections()
+	if _, err := roundTrip(conn, "ping"); err == nil {
+		t.Error("round trip succeeded on a dropped connection")
+	}
+
+	// New connections work again.
+	if _, err := roundTrip(dialProxy(t, proxy), "ping"); err != nil {
+		t.Fatalf("round trip after reconnect failed: %v", err)
+	}
+}
+
+func TestEtcdProxyBlackhole(t *testing.T) {
+	proxy, err := NewEtcdProxy(startEchoBackend(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	proxy.SetBlackhole(true)
+	if _, err := roundTrip(dialProxy(t, proxy), "ping"); err == nil {
+		t.Error("round trip succeeded while blackholed")
+	}
+
+	proxy.SetBlackhole(false)
+	if _, err := roundTrip(dialProxy(t, proxy), "ping"); err != nil {
+		t.Fatalf("round trip after clearing blackhole failed: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loadgen provides reusable load shapes extracted from
+// clusterloader-style scalability jobs: churny pods, services with large
+// endpoint sets, and watch storms. Component owners can import these from
+// integration tests to put targeted pressure on the code path they own
+// without standing up a full scalability run.
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Profile is one load shape. Run applies load until ctx is canceled and then
+// returns; context cancellation is the normal way to stop a profile and is
+// not reported as an error.
+type Profile interface {
+	// Name identifies the profile in logs and errors.
+	Name() string
+	// Run applies the load until ctx is canceled.
+	Run(ctx context.Context, client clientset.Interface) error
+}
+
+// RunConcurrently runs all profiles until ctx is canceled or one of them
+// fails, and returns the aggregated errors.
+func RunConcurrently(ctx context.Context, client clientset.Interface, profiles ...Profile) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lock sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, profile := range profiles {
+		wg.Add(1)
+		go func(p Profile) {
+			defer wg.Done()
+			klog.V(2).Infof("starting load profile %q", p.Name())
+			if err := p.Run(ctx, client); err != nil {
+				lock.Lock()
+				errs = append(errs, fmt.Errorf("profile %q: %w", p.Name(), err))
+				lock.Unlock()
+				// One failed profile invalidates the run; stop the others.
+				cancel()
+			}
+		}(profile)
+	}
+	wg.Wait()
+	return utilerrors.NewAggregate(errs)
+}
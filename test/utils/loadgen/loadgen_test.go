@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodChurnKeepsSteadyState(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	profile := &PodChurn{Namespace: "churn", Pods: 3, Interval: time.Millisecond}
+	done := make(chan error, 1)
+	go func() { done <- profile.Run(ctx, client) }()
+
+	// Wait for the profile to cycle well past its steady-state size.
+	if err := wait.PollImmediate(5*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		pods, err := client.CoreV1().Pods("churn").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range pods.Items {
+			index, err := strconv.Atoi(strings.TrimPrefix(pod.Name, "churn-"))
+			if err == nil && index >= 10 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}); err != nil {
+		t.Fatalf("pod churn never progressed: %v", err)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	pods, err := client.CoreV1().Pods("churn").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The population may briefly be one above target between create and delete.
+	if got := len(pods.Items); got < 3 || got > 4 {
+		t.Errorf("steady-state pod count = %d, want 3 or 4", got)
+	}
+}
+
+func TestHugeServiceEndpoints(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	profile := &HugeService{Namespace: "load", ServiceName: "huge", Endpoints: 500}
+	done := make(chan error, 1)
+	go func() { done <- profile.Run(ctx, client) }()
+
+	if err := wait.PollImmediate(5*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		_, err := client.CoreV1().Endpoints("load").Get(context.Background(), "huge", metav1.GetOptions{})
+		return err == nil, nil
+	}); err != nil {
+		t.Fatalf("endpoints were never created: %v", err)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	endpoints, err := client.CoreV1().Endpoints("load").Get(context.Background(), "huge", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(endpoints.Subsets[0].Addresses); got != 500 {
+		t.Errorf("endpoint address count = %d, want 500", got)
+	}
+}
+
+func TestWatchStormStopsOnCancel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	profile := &WatchStorm{Namespace: "load", Watchers: 5}
+	done := make(chan error, 1)
+	go func() { done <- profile.Run(ctx, client) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("watch storm did not stop after cancellation")
+	}
+}
+
+// failingProfile fails immediately so the run aborts.
+type failingProfile struct{}
+
+func (f *failingProfile) Name() string { return "failing" }
+func (f *failingProfile) Run(ctx context.Context, client clientset.Interface) error {
+	return errors.New("boom")
+}
+
+func TestRunConcurrentlyStopsOnFailure(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The watch storm would run forever; the failing profile must stop it.
+	err := RunConcurrently(ctx, client, &failingProfile{}, &WatchStorm{Watchers: 2})
+	if err == nil {
+		t.Fatal("RunConcurrently returned nil, want the profile error")
+	}
+	if !strings.Contains(err.Error(), `profile "failing"`) {
+		t.Errorf("error %q does not name the failing profile", err)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// PodChurn keeps a fixed-size population of pods while continuously deleting
+// the oldest and creating replacements, the dominant load shape of batch-heavy
+// clusters. It stresses admission, scheduling, and the watch path feeding
+// controllers.
+type PodChurn struct {
+	// Namespace the pods are created in. It must already exist.
+	Namespace string
+	// Pods is the steady-state number of pods kept alive.
+	Pods int
+	// Interval is the pause between churn steps; zero churns as fast as the
+	// apiserver allows.
+	Interval time.Duration
+}
+
+func (p *PodChurn) Name() string {
+	return fmt.Sprintf("pod-churn-%d", p.Pods)
+}
+
+func (p *PodChurn) Run(ctx context.Context, client clientset.Interface) error {
+	for i := 0; ; i++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		pod := churnPod(fmt.Sprintf("churn-%d", i))
+		if _, err := client.CoreV1().Pods(p.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("creating pod %s: %w", pod.Name, err)
+		}
+		if i >= p.Pods {
+			victim := fmt.Sprintf("churn-%d", i-p.Pods)
+			if err := client.CoreV1().Pods(p.Namespace).Delete(ctx, victim, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("deleting pod %s: %w", victim, err)
+			}
+		}
+		if p.Interval > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(p.Interval):
+			}
+		}
+	}
+}
+
+func churnPod(name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"loadgen": "pod-churn"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:  "pause",
+				Image: "registry.k8s.io/pause:3.9",
+			}},
+		},
+	}
+}
+
+// HugeService maintains a service with a large, slowly rotating endpoint set,
+// the shape that historically melted kube-proxy and the endpoints controller.
+// Addresses are written directly to the Endpoints object so the profile does
+// not need thousands of running pods behind it.
+type HugeService struct {
+	// Namespace the service is created in. It must already exist.
+	Namespace string
+	// ServiceName names the service and its endpoints.
+	ServiceName string
+	// Endpoints is the number of addresses behind the service.
+	Endpoints int
+	// RotatePeriod is how often a slice of the addresses is replaced to
+	// generate endpoint churn. Zero leaves the endpoint set static.
+	RotatePeriod time.Duration
+}
+
+func (h *HugeService) Name() string {
+	return fmt.Sprintf("huge-service-%d", h.Endpoints)
+}
+
+func (h *HugeService) Run(ctx context.Context, client clientset.Interface) error {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: h.ServiceName},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(80)}},
+		},
+	}
+	if _, err := client.CoreV1().Services(h.Namespace).Create(ctx, service, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating service %s: %w", h.ServiceName, err)
+	}
+
+	generation := 0
+	endpoints := h.endpoints(generation)
+	if _, err := client.CoreV1().Endpoints(h.Namespace).Create(ctx, endpoints, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating endpoints %s: %w", h.ServiceName, err)
+	}
+	if h.RotatePeriod == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(h.RotatePeriod):
+		}
+		generation++
+		if _, err := client.CoreV1().Endpoints(h.Namespace).Update(ctx, h.endpoints(generation), metav1.UpdateOptions{}); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("rotating endpoints %s: %w", h.ServiceName, err)
+		}
+	}
+}
+
+// endpoints builds the endpoint set for a generation. A tenth of the
+// addresses change between generations, matching the rolling-update churn
+// seen behind real large services.
+func (h *HugeService) endpoints(generation int) *v1.Endpoints {
+	rotated := h.Endpoints / 10
+	addresses := make([]v1.EndpointAddress, 0, h.Endpoints)
+	for i := 0; i < h.Endpoints; i++ {
+		octet := i
+		if i < rotated {
+			octet = h.Endpoints + generation*rotated + i
+		}
+		addresses = append(addresses, v1.EndpointAddress{
+			IP: fmt.Sprintf("10.%d.%d.%d", (octet>>16)&0xff, (octet>>8)&0xff, octet&0xff),
+		})
+	}
+	return &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: h.ServiceName},
+		Subsets: []v1.EndpointSubset{{
+			Addresses: addresses,
+			Ports:     []v1.EndpointPort{{Port: 80}},
+		}},
+	}
+}
+
+// WatchStorm opens many concurrent pod watches and consumes their events,
+// the load shape of a large fleet of kubelets and controllers reconnecting
+// after an apiserver restart. It stresses the watch cache and serialization.
+type WatchStorm struct {
+	// Namespace to watch. Empty watches all namespaces.
+	Namespace string
+	// Watchers is the number of concurrent watches held open.
+	Watchers int
+	// LabelSelector optionally restricts the watches, exercising selector
+	// fan-out in the watch cache.
+	LabelSelector string
+}
+
+func (w *WatchStorm) Name() string {
+	return fmt.Sprintf("watch-storm-%d", w.Watchers)
+}
+
+func (w *WatchStorm) Run(ctx context.Context, client clientset.Interface) error {
+	var wg sync.WaitGroup
+	errCh := make(chan error, w.Watchers)
+	for i := 0; i < w.Watchers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.watch(ctx, client); err != nil {
+				errCh <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+// watch holds one watch open until ctx is canceled, re-establishing it when
+// the server closes it, as client-go reflectors do.
+func (w *WatchStorm) watch(ctx context.Context, client clientset.Interface) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		watcher, err := client.CoreV1().Pods(w.Namespace).Watch(ctx, metav1.ListOptions{LabelSelector: w.LabelSelector})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("starting watch: %w", err)
+		}
+	consume:
+		for {
+			select {
+			case <-ctx.Done():
+				watcher.Stop()
+				return nil
+			case _, ok := <-watcher.ResultChan():
+				if !ok {
+					// Server closed the watch; reconnect.
+					break consume
+				}
+			}
+		}
+		watcher.Stop()
+	}
+}